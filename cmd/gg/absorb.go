@@ -0,0 +1,483 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const absorbSynopsis = "fold working copy changes into the commits they modify"
+
+func absorb(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg absorb [-n] [--rewrite [--force-protected]] [--base REV] [--allow-rewrite-published] [--no-verify] [-I PATTERN] [-X PATTERN] [FILE [...]]", absorbSynopsis+`
+
+	Finds the commit in the current stack that last touched the lines
+	around each change to the given files, or to all tracked files if no
+	files are given, and commits the change as a fix to that commit, as in
+	`+"`gg fixup`"+`. The fixes are folded into their commits by the next
+	`+"`gg rebase`"+`, `+"`gg evolve`"+`, or `+"`gg histedit`"+`, or right away
+	with `+"`--rewrite`"+`. Changes that don't belong to a single commit in
+	the stack are left in the working copy.
+
+	The stack is the commits on the working copy's first-parent history
+	that are not reachable from `+"`--base`"+`, which defaults to the
+	current branch's upstream. Commits on remote branches are not part of
+	the stack unless `+"`--allow-rewrite-published`"+` is given. The stack
+	stops at the first merge commit and holds at most `+strconv.Itoa(absorbMaxStack)+` commits.
+
+	`+"`-n`"+` shows which commits the changes would be folded into without
+	changing anything.`+protectedBranchHelp+commitGuardHelp+patternHelp)
+	pats := new(patternSet)
+	pats.addFlags(f)
+	dryRun := f.Bool("n", false, "show where changes would go without committing them")
+	f.Alias("n", "dry-run")
+	rewrite := f.Bool("rewrite", false, "fold the fixes into their commits right away")
+	base := f.String("base", "", "oldest `rev`ision to exclude from the stack")
+	allowPublished := f.Bool("allow-rewrite-published", false, allowRewritePublishedUsage)
+	forceProtected := f.Bool("force-protected", false, forceProtectedUsage)
+	noVerify := f.Bool("no-verify", false, noVerifyUsage)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if *dryRun && *rewrite {
+		return usagef("can't pass both -n and --rewrite")
+	}
+	if strings.HasPrefix(*base, "-") {
+		return usagef("--base revision cannot start with '-'")
+	}
+	pats.args = f.Args()
+	pathspecs, err := pats.pathspecs(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	head, err := cc.git.Head(ctx)
+	if err != nil {
+		return err
+	}
+	if *base == "" {
+		if dst, err := defaultRebaseDest(ctx, cc.git); err == nil {
+			if _, err := cc.git.ParseRev(ctx, dst); err == nil {
+				*base = dst
+			}
+		}
+	} else if _, err := cc.git.ParseRev(ctx, *base); err != nil {
+		return fmt.Errorf("base: %w", err)
+	}
+	stack, err := readAbsorbStack(ctx, cc.git, *base, *allowPublished)
+	if err != nil {
+		return err
+	}
+	if len(stack) == 0 {
+		return errors.New("no commits to absorb changes into")
+	}
+	files, err := readAbsorbDiff(ctx, cc.git, pathspecs)
+	if err != nil {
+		return err
+	}
+	plan, err := planAbsorb(ctx, cc.git, stack, files)
+	if err != nil {
+		return err
+	}
+	if len(plan.groups) == 0 {
+		fmt.Fprintf(cc.stderr, "gg: absorb: no changes can be absorbed (%d left in working copy)\n", plan.skipped)
+		return nil
+	}
+	for _, g := range plan.groups {
+		verb := "absorbed"
+		if *dryRun {
+			verb = "would absorb"
+		}
+		fmt.Fprintf(cc.stdout, "%s %s into %s %s\n", verb, countChanges(len(g.hunks)), shortHash(g.target.Hash.String()), g.target.Summary())
+	}
+	if plan.skipped > 0 {
+		fmt.Fprintf(cc.stdout, "%s left in working copy\n", countChanges(plan.skipped))
+	}
+	if *dryRun {
+		return nil
+	}
+	if !*noVerify {
+		if err := checkCommitChanges(ctx, cc, plan.pathspecs()); err != nil {
+			return err
+		}
+	}
+	if *rewrite && !*forceProtected {
+		if err := checkRewriteProtected(ctx, cc, "absorb"); err != nil {
+			return err
+		}
+	}
+	if err := commitAbsorbPlan(ctx, cc, head.Commit, plan); err != nil {
+		return err
+	}
+	if !*rewrite {
+		return nil
+	}
+	if err := recordOperation(ctx, cc.git, "absorb", args); err != nil {
+		return err
+	}
+	if err := autoSnapshot(ctx, cc, "absorb"); err != nil {
+		return err
+	}
+	oldest := plan.groups[0].target
+	rebaseArgs := []string{"-c", "sequence.editor=true", "rebase", "-i", "--autosquash", "--autostash", "--no-fork-point"}
+	if len(oldest.Parents) == 0 {
+		rebaseArgs = append(rebaseArgs, "--root")
+	} else {
+		rebaseArgs = append(rebaseArgs, "--", oldest.Parents[0].String())
+	}
+	return cc.interactiveGit(ctx, rebaseArgs...)
+}
+
+// absorbMaxStack is the largest number of commits that gg absorb
+// considers, so that blaming stays fast on long branches.
+const absorbMaxStack = 50
+
+// readAbsorbStack returns the commits that gg absorb can fold changes
+// into, newest first: the first-parent history of HEAD up to but not
+// including base or the first merge commit.
+func readAbsorbStack(ctx context.Context, g *git.Git, base string, allowPublished bool) ([]git.Hash, error) {
+	args := []string{"rev-list", "--first-parent", "--parents", "--max-count=" + strconv.Itoa(absorbMaxStack), git.Head.String()}
+	if base != "" {
+		args = append(args, "^"+base)
+	}
+	if !allowPublished {
+		args = append(args, "--not", "--remotes")
+	}
+	args = append(args, "--")
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var stack []git.Hash
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			// Merge commit.
+			break
+		}
+		h, err := git.ParseHash(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("read stack: %w", err)
+		}
+		stack = append(stack, h)
+	}
+	return stack, nil
+}
+
+// readAbsorbDiff returns the changes to tracked files between HEAD and
+// the working copy without context lines, so that each hunk is only the
+// lines that changed. Files that were added, deleted, or changed in ways
+// that have no lines to blame are left out.
+func readAbsorbDiff(ctx context.Context, g *git.Git, pathspecs []git.Pathspec) ([]*fileDiff, error) {
+	args := []string{"diff", "--no-color", "--no-ext-diff", "--no-renames", "--unified=0", git.Head.String(), "--"}
+	for _, spec := range pathspecs {
+		args = append(args, spec.String())
+	}
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	files, err := parseDiff(out)
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+fileLoop:
+	for _, fd := range files {
+		for _, line := range fd.header {
+			if strings.HasPrefix(line, "new file mode ") ||
+				strings.HasPrefix(line, "deleted file mode ") ||
+				strings.HasPrefix(line, "old mode ") ||
+				strings.HasPrefix(line, "Binary files ") {
+				continue fileLoop
+			}
+		}
+		if len(fd.hunks) > 0 {
+			files[n] = fd
+			n++
+		}
+	}
+	return files[:n], nil
+}
+
+// An absorbPlan maps hunks of the working copy's changes to the commits
+// they will be folded into.
+type absorbPlan struct {
+	// groups is in stack order, oldest commit first.
+	groups  []*absorbGroup
+	skipped int
+}
+
+// An absorbGroup is the hunks that will be folded into a single commit.
+type absorbGroup struct {
+	target *git.CommitInfo
+	hunks  []absorbHunk
+}
+
+type absorbHunk struct {
+	file *fileDiff
+	hunk *hunk
+
+	oldStart, oldCount int
+	newCount           int
+}
+
+// planAbsorb assigns each hunk in files to the commit in stack that last
+// changed the lines it replaces or, for pure additions, the lines
+// around it. Hunks whose lines come from more than one commit or from
+// commits outside the stack are counted as skipped.
+func planAbsorb(ctx context.Context, g *git.Git, stack []git.Hash, files []*fileDiff) (*absorbPlan, error) {
+	stackIndex := make(map[git.Hash]int, len(stack))
+	for i, h := range stack {
+		stackIndex[h] = i
+	}
+	byTarget := make(map[git.Hash]*absorbGroup)
+	plan := new(absorbPlan)
+	for _, fd := range files {
+		for _, h := range fd.hunks {
+			oldStart, oldCount, _, newCount, ok := parseHunkHeader(h.header)
+			if !ok {
+				return nil, fmt.Errorf("parse diff: malformed hunk header %q", h.header)
+			}
+			first, last := oldStart, oldStart+oldCount-1
+			if oldCount == 0 {
+				// Pure addition after line oldStart: use its neighbors.
+				first, last = oldStart, oldStart+1
+				if first < 1 {
+					first = 1
+				}
+			}
+			owners, err := blameLines(ctx, g, fd.name, first, last)
+			if err != nil {
+				return nil, err
+			}
+			target, ok := singleOwner(owners)
+			if _, inStack := stackIndex[target]; !ok || !inStack {
+				plan.skipped++
+				continue
+			}
+			grp := byTarget[target]
+			if grp == nil {
+				info, err := g.CommitInfo(ctx, target.String())
+				if err != nil {
+					return nil, err
+				}
+				grp = &absorbGroup{target: info}
+				byTarget[target] = grp
+			}
+			grp.hunks = append(grp.hunks, absorbHunk{
+				file:     fd,
+				hunk:     h,
+				oldStart: oldStart,
+				oldCount: oldCount,
+				newCount: newCount,
+			})
+		}
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		if grp := byTarget[stack[i]]; grp != nil {
+			plan.groups = append(plan.groups, grp)
+		}
+	}
+	return plan, nil
+}
+
+// blameLines returns the commits that last changed each of the lines
+// first through last of the named file at HEAD. Lines past the end of
+// the file are ignored.
+func blameLines(ctx context.Context, g *git.Git, name git.TopPath, first, last int) ([]git.Hash, error) {
+	out, err := g.Output(ctx, "blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", first, last), git.Head.String(), "--", name.String())
+	if err != nil {
+		if last > first {
+			// The range may extend past the end of the file.
+			return blameLines(ctx, g, name, first, last-1)
+		}
+		return nil, nil
+	}
+	var owners []git.Hash
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		h, err := git.ParseHash(fields[0])
+		if err != nil {
+			continue
+		}
+		owners = append(owners, h)
+	}
+	return owners, nil
+}
+
+// singleOwner returns the commit in owners if they are all the same.
+func singleOwner(owners []git.Hash) (git.Hash, bool) {
+	if len(owners) == 0 {
+		return git.Hash{}, false
+	}
+	for _, h := range owners[1:] {
+		if h != owners[0] {
+			return git.Hash{}, false
+		}
+	}
+	return owners[0], true
+}
+
+// pathspecs returns pathspecs for the files with changes in the plan.
+func (plan *absorbPlan) pathspecs() []git.Pathspec {
+	var specs []git.Pathspec
+	seen := make(map[git.TopPath]bool)
+	for _, grp := range plan.groups {
+		for _, h := range grp.hunks {
+			if !seen[h.file.name] {
+				seen[h.file.name] = true
+				specs = append(specs, h.file.name.Pathspec())
+			}
+		}
+	}
+	return specs
+}
+
+// commitAbsorbPlan commits a fixup! commit for each group in the plan on
+// top of head and moves HEAD to the last of them. The working copy is
+// left alone, and the index entries of the absorbed files are updated
+// to match the new HEAD.
+func commitAbsorbPlan(ctx context.Context, cc *cmdContext, head git.Hash, plan *absorbPlan) error {
+	gitDir, err := cc.git.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(cc.abs(gitDir), "gg-absorb-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	gitOpts := cc.gitOptions
+	gitOpts.Dir = cc.dir
+	gitOpts.Env = append(append([]string(nil), gitOpts.Env...), "GIT_INDEX_FILE="+filepath.Join(tmpDir, "index"))
+	tmpGit, err := git.New(gitOpts)
+	if err != nil {
+		return err
+	}
+	if err := tmpGit.Run(ctx, "read-tree", head.String()); err != nil {
+		return err
+	}
+
+	// applied tracks the hunks committed so far in each file, so that
+	// later patches can be adjusted for the lines they added or removed.
+	applied := make(map[git.TopPath][]absorbHunk)
+	patchPath := filepath.Join(tmpDir, "absorb.patch")
+	parent := head.String()
+	for _, grp := range plan.groups {
+		patch := absorbPatch(grp.hunks, applied)
+		if err := os.WriteFile(patchPath, []byte(patch), 0o666); err != nil {
+			return err
+		}
+		if err := tmpGit.Run(ctx, "apply", "--cached", "--unidiff-zero", "--whitespace=nowarn", patchPath); err != nil {
+			return fmt.Errorf("absorb into %s: %w", shortHash(grp.target.Hash.String()), err)
+		}
+		tree, err := tmpGit.Output(ctx, "write-tree")
+		if err != nil {
+			return err
+		}
+		newHash, err := tmpGit.Output(ctx, "commit-tree", strings.TrimSpace(tree), "-p", parent, "-m", "fixup! "+grp.target.Summary())
+		if err != nil {
+			return err
+		}
+		parent = strings.TrimSpace(newHash)
+		for _, h := range grp.hunks {
+			applied[h.file.name] = append(applied[h.file.name], h)
+		}
+	}
+	if err := cc.git.Run(ctx, "update-ref", "-m", "gg absorb", git.Head.String(), parent, head.String()); err != nil {
+		return err
+	}
+	resetArgs := []string{"reset", "--quiet", "--"}
+	for _, spec := range plan.pathspecs() {
+		resetArgs = append(resetArgs, spec.String())
+	}
+	if err := cc.git.Run(ctx, resetArgs...); err != nil {
+		return fmt.Errorf("update index: %w", err)
+	}
+	return nil
+}
+
+// absorbPatch returns a patch with the given hunks that applies to a
+// tree that already has the applied hunks. The line numbers in the
+// hunk headers are shifted by the lines that earlier hunks added or
+// removed.
+func absorbPatch(hunks []absorbHunk, applied map[git.TopPath][]absorbHunk) string {
+	sb := new(strings.Builder)
+	for i := 0; i < len(hunks); {
+		fd := hunks[i].file
+		for _, line := range fd.header {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+		// Hunks from the same file are adjacent and in file order,
+		// since they were added to the group in diff order.
+		selfDelta := 0
+		for ; i < len(hunks) && hunks[i].file == fd; i++ {
+			h := hunks[i]
+			oldStart := h.oldStart
+			for _, prev := range applied[fd.name] {
+				if prev.oldStart < h.oldStart {
+					oldStart += prev.newCount - prev.oldCount
+				}
+			}
+			// With no context lines, Git numbers an empty range by the line
+			// before it.
+			newStart := oldStart + selfDelta
+			if h.oldCount == 0 {
+				newStart++
+			}
+			if h.newCount == 0 {
+				newStart--
+			}
+			fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldStart, h.oldCount, newStart, h.newCount)
+			for _, line := range h.hunk.lines {
+				sb.WriteString(line)
+				sb.WriteByte('\n')
+			}
+			selfDelta += h.newCount - h.oldCount
+		}
+	}
+	return sb.String()
+}
+
+func countChanges(n int) string {
+	if n == 1 {
+		return "1 change"
+	}
+	return strconv.Itoa(n) + " changes"
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+// setupAbsorbTest creates a repository with commits that add base.txt,
+// foo.txt, and bar.txt, then changes foo.txt and bar.txt in the working
+// copy.
+func setupAbsorbTest(ctx context.Context, env *testEnv) error {
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		return err
+	}
+	if err := env.root.Apply(filesystem.Write("base.txt", "base\n")); err != nil {
+		return err
+	}
+	if err := env.addFiles(ctx, "base.txt"); err != nil {
+		return err
+	}
+	if err := env.git.Commit(ctx, "Add base.txt", git.CommitOptions{}); err != nil {
+		return err
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "a\nb\nc\n")); err != nil {
+		return err
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		return err
+	}
+	if err := env.git.Commit(ctx, "Add foo.txt", git.CommitOptions{}); err != nil {
+		return err
+	}
+	if err := env.root.Apply(filesystem.Write("bar.txt", "x\ny\n")); err != nil {
+		return err
+	}
+	if err := env.addFiles(ctx, "bar.txt"); err != nil {
+		return err
+	}
+	if err := env.git.Commit(ctx, "Add bar.txt", git.CommitOptions{}); err != nil {
+		return err
+	}
+	return env.root.Apply(
+		filesystem.Write("foo.txt", "a\nB\nc\n"),
+		filesystem.Write("bar.txt", "x\nY\n"),
+	)
+}
+
+func TestAbsorb(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupAbsorbTest(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "absorb"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.git.Output(ctx, "log", "--format=%s", "-4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"fixup! Add bar.txt",
+		"fixup! Add foo.txt",
+		"Add bar.txt",
+		"Add foo.txt",
+	}
+	if diff := cmp.Diff(want, strings.Split(strings.TrimSuffix(out, "\n"), "\n")); diff != "" {
+		t.Errorf("log (-want +got):\n%s", diff)
+	}
+	if data, err := catBlob(ctx, env.git, "HEAD~", "foo.txt"); err != nil {
+		t.Error(err)
+	} else if got, want := string(data), "a\nB\nc\n"; got != want {
+		t.Errorf("foo.txt in HEAD~ = %q; want %q", got, want)
+	}
+	if data, err := catBlob(ctx, env.git, "HEAD~", "bar.txt"); err != nil {
+		t.Error(err)
+	} else if got, want := string(data), "x\ny\n"; got != want {
+		t.Errorf("bar.txt in HEAD~ = %q; want %q", got, want)
+	}
+	if status, err := env.git.Output(ctx, "status", "--porcelain"); err != nil {
+		t.Error(err)
+	} else if status != "" {
+		t.Errorf("status after absorb = %q; want clean", status)
+	}
+}
+
+func TestAbsorb_Rewrite(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupAbsorbTest(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	// A change to a commit outside the stack stays in the working copy.
+	if err := env.root.Apply(filesystem.Write("base.txt", "left behind\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "absorb", "--rewrite", "--base", "HEAD~2"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.git.Output(ctx, "log", "--format=%s", "-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out, "Add bar.txt\nAdd foo.txt\n"; got != want {
+		t.Errorf("log = %q; want %q", got, want)
+	}
+	if data, err := catBlob(ctx, env.git, "HEAD~", "foo.txt"); err != nil {
+		t.Error(err)
+	} else if got, want := string(data), "a\nB\nc\n"; got != want {
+		t.Errorf("foo.txt in HEAD~ = %q; want %q", got, want)
+	}
+	if data, err := catBlob(ctx, env.git, "HEAD", "bar.txt"); err != nil {
+		t.Error(err)
+	} else if got, want := string(data), "x\nY\n"; got != want {
+		t.Errorf("bar.txt in HEAD = %q; want %q", got, want)
+	}
+	if status, err := env.git.Output(ctx, "status", "--porcelain"); err != nil {
+		t.Error(err)
+	} else if got, want := status, " M base.txt\n"; got != want {
+		t.Errorf("status after absorb = %q; want %q", got, want)
+	}
+}
+
+func TestAbsorbPatch(t *testing.T) {
+	fd := &fileDiff{
+		name:   "foo.txt",
+		header: []string{"diff --git a/foo.txt b/foo.txt", "--- a/foo.txt", "+++ b/foo.txt"},
+	}
+	first := absorbHunk{file: fd, hunk: &hunk{lines: []string{"+new"}}, oldStart: 1, oldCount: 0, newCount: 1}
+	second := absorbHunk{file: fd, hunk: &hunk{lines: []string{"-old"}}, oldStart: 5, oldCount: 1, newCount: 0}
+	applied := map[git.TopPath][]absorbHunk{"foo.txt": {first}}
+	got := absorbPatch([]absorbHunk{second}, applied)
+	want := "diff --git a/foo.txt b/foo.txt\n--- a/foo.txt\n+++ b/foo.txt\n" +
+		"@@ -6,1 +5,0 @@\n-old\n"
+	if got != want {
+		t.Errorf("absorbPatch(...) = %q; want %q", got, want)
+	}
+}
@@ -16,8 +16,11 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
@@ -26,35 +29,49 @@ import (
 const addSynopsis = "add the specified files on the next commit"
 
 func add(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg add FILE [...]", addSynopsis+`
+	f := flag.NewFlagSet(true, "gg add [--force-large] [-I PATTERN] [-X PATTERN] FILE [...]", addSynopsis+`
 
 	Mark files to be tracked under version control and added at the next
 	commit. If `+"`add`"+` is run on a file X and X is ignored, it will be
 	tracked. However, adding a directory with ignored files will not track
-	the ignored files.
+	the ignored files. When adding directories or patterns, `+"`add`"+`
+	prints how many files were added and how many were skipped because
+	they are ignored.
 
-	`+"`add`"+` also marks merge conflicts as resolved like `+"`git add`.")
+	`+"`add`"+` refuses to add files larger than the `+"`gg.largeFileSize`"+`
+	setting (10M by default, with Git's k, m, and g suffixes) unless
+	`+"`--force-large`"+` is given. Setting it to 0 turns off the check.
+
+	`+"`add`"+` also marks merge conflicts as resolved like `+"`git add`."+patternHelp)
+	pats := new(patternSet)
+	pats.addFlags(f)
+	forceLarge := f.Bool("force-large", false, "add files even if they are larger than gg.largeFileSize")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
-	if f.NArg() == 0 {
+	if f.NArg() == 0 && len(pats.includes) == 0 {
 		return usagef("must pass one or more files to add")
 	}
 
-	// Group arguments into files and directories.
-	files := make([]string, 0, f.NArg())
-	dirs := make([]string, 0, f.NArg())
-	for _, a := range args {
+	// Group arguments into files and directories. Patterns are grouped
+	// with directories, since they should not match ignored files.
+	files := &patternSet{excludes: pats.excludes}
+	dirs := &patternSet{includes: pats.includes, excludes: pats.excludes}
+	for _, a := range f.Args() {
+		if hasPatternKind(a) {
+			dirs.args = append(dirs.args, a)
+			continue
+		}
 		if !filepath.IsAbs(a) {
 			a = filepath.Join(cc.dir, a)
 		}
 		if isdir(a) {
-			dirs = append(dirs, a)
+			dirs.args = append(dirs.args, a)
 		} else {
-			files = append(files, a)
+			files.args = append(files.args, a)
 		}
 	}
 	// Files can be explicit adds of ignored files.
@@ -67,6 +84,29 @@ func add(ctx context.Context, cc *cmdContext, args []string) error {
 	if err != nil {
 		return err
 	}
+	// Expand the untracked directories into the files that will be added
+	// so they can be checked and counted.
+	var dirFiles, ignoredFiles []git.TopPath
+	if dirs.hasIncludes() {
+		dirSpecs, err := dirs.pathspecs(ctx, cc.git)
+		if err != nil {
+			return err
+		}
+		dirFiles, err = listUntrackedFiles(ctx, cc.git, dirSpecs, false)
+		if err != nil {
+			return err
+		}
+		ignoredFiles, err = listUntrackedFiles(ctx, cc.git, dirSpecs, true)
+		if err != nil {
+			return err
+		}
+	}
+	newFiles := make([]git.TopPath, 0, len(untrackedFiles)+len(dirFiles))
+	newFiles = append(newFiles, untrackedFiles...)
+	newFiles = append(newFiles, dirFiles...)
+	if err := checkLargeFiles(ctx, cc, newFiles, *forceLarge); err != nil {
+		return err
+	}
 	// Untracked files coming from file arguments should be marked with
 	// intent to add.
 	if len(untrackedFiles) > 0 {
@@ -85,12 +125,18 @@ func add(ctx context.Context, cc *cmdContext, args []string) error {
 	// Untracked files coming from directory arguments should be intent to
 	// add, but no -f. A totally untracked directory will come in as a
 	// single entry, which would mean -f would apply to the whole tree.
+	// For the same reason, exclusions must be passed along.
 	if len(untrackedDirs) > 0 {
-		pathspecs := make([]git.Pathspec, 0, len(untrackedFiles))
+		exclusions, err := (&patternSet{excludes: pats.excludes}).pathspecs(ctx, cc.git)
+		if err != nil {
+			return err
+		}
+		pathspecs := make([]git.Pathspec, 0, len(untrackedDirs)+len(exclusions))
 		for _, d := range untrackedDirs {
 			pathspecs = append(pathspecs, d.Pathspec())
 		}
-		err := cc.git.Add(ctx, pathspecs, git.AddOptions{
+		pathspecs = append(pathspecs, exclusions...)
+		err = cc.git.Add(ctx, pathspecs, git.AddOptions{
 			IntentToAdd: true,
 		})
 		if err != nil {
@@ -110,9 +156,159 @@ func add(ctx context.Context, cc *cmdContext, args []string) error {
 			return err
 		}
 	}
+	if dirs.hasIncludes() {
+		summary := "added " + countFiles(len(newFiles))
+		if len(ignoredFiles) > 0 {
+			summary += ", skipped " + countIgnored(ignoredFiles) + " ignored by .gitignore (name them explicitly to add them)"
+		}
+		if _, err := fmt.Fprintln(cc.stdout, summary); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// defaultLargeFileSize is the default for the gg.largeFileSize setting.
+const defaultLargeFileSize = 10 << 20
+
+// checkLargeFiles returns an error if any of the files is larger than
+// the gg.largeFileSize setting, unless force is true. It prints a warning
+// for each such file either way.
+func checkLargeFiles(ctx context.Context, cc *cmdContext, files []git.TopPath, force bool) error {
+	if len(files) == 0 {
+		return nil
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	limit, err := parseFileSize(cfg.Value("gg.largeFileSize"))
+	if err != nil {
+		return fmt.Errorf("gg.largeFileSize: %w", err)
+	}
+	if limit == 0 {
+		return nil
+	}
+	root, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	large := 0
+	for _, name := range files {
+		info, err := os.Lstat(filepath.Join(root, filepath.FromSlash(name.String())))
+		if err != nil || !info.Mode().IsRegular() || info.Size() <= limit {
+			continue
+		}
+		large++
+		fmt.Fprintf(cc.stderr, "gg: warning: %s is %s\n", name, formatFileSize(info.Size()))
+	}
+	if large == 0 || force {
+		return nil
+	}
+	return fmt.Errorf("refusing to add %s larger than %s; pass --force-large to add anyway", countFiles(large), formatFileSize(limit))
+}
+
+// parseFileSize parses a size in bytes with an optional k, m, or g
+// suffix, like Git's integer configuration values. An empty string
+// yields the default.
+func parseFileSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return defaultLargeFileSize, nil
+	}
+	var shift uint
+	switch s[len(s)-1] {
+	case 'k', 'K':
+		shift = 10
+	case 'm', 'M':
+		shift = 20
+	case 'g', 'G':
+		shift = 30
+	}
+	if shift > 0 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 || n > (1<<62)>>shift {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n << shift, nil
+}
+
+// formatFileSize formats a size in bytes for humans, like "1.5 MiB".
+func formatFileSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return strconv.FormatFloat(float64(n)/(1<<30), 'f', 1, 64) + " GiB"
+	case n >= 1<<20:
+		return strconv.FormatFloat(float64(n)/(1<<20), 'f', 1, 64) + " MiB"
+	case n >= 1<<10:
+		return strconv.FormatFloat(float64(n)/(1<<10), 'f', 1, 64) + " KiB"
+	case n == 1:
+		return "1 byte"
+	default:
+		return strconv.FormatInt(n, 10) + " bytes"
+	}
+}
+
+func countFiles(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return strconv.Itoa(n) + " files"
+}
+
+// countIgnored describes the entries returned by listUntrackedFiles for
+// ignored files, like "2 files and 1 directory".
+func countIgnored(entries []git.TopPath) string {
+	dirs := 0
+	for _, ent := range entries {
+		if strings.HasSuffix(ent.String(), "/") {
+			dirs++
+		}
+	}
+	files := len(entries) - dirs
+	switch {
+	case dirs == 0:
+		return countFiles(files)
+	case dirs == 1 && files == 0:
+		return "1 directory"
+	case files == 0:
+		return strconv.Itoa(dirs) + " directories"
+	case dirs == 1:
+		return countFiles(files) + " and 1 directory"
+	default:
+		return countFiles(files) + " and " + strconv.Itoa(dirs) + " directories"
+	}
+}
+
+// listUntrackedFiles returns the untracked files that match pathspecs,
+// excluding ignored files. If ignored is true, it returns only the
+// ignored files instead, with an ignored directory returned as a single
+// entry ending in a slash rather than as every file inside it.
+func listUntrackedFiles(ctx context.Context, g *git.Git, pathspecs []git.Pathspec, ignored bool) ([]git.TopPath, error) {
+	args := []string{"ls-files", "-z", "--others", "--exclude-standard", "--full-name"}
+	if ignored {
+		// Ignored directories like node_modules can hold many files.
+		args = append(args, "--ignored", "--directory")
+	}
+	args = append(args, "--")
+	for _, spec := range pathspecs {
+		args = append(args, spec.String())
+	}
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	var files []git.TopPath
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			files = append(files, git.TopPath(name))
+		}
+	}
+	return files, nil
+}
+
 func isdir(name string) bool {
 	info, err := os.Stat(name)
 	return err == nil && info.IsDir()
@@ -120,13 +316,13 @@ func isdir(name string) bool {
 
 // findAddFiles finds the files described by the arguments and groups
 // them based on how they should be handled by add.
-func findAddFiles(ctx context.Context, g *git.Git, args []string, includeIgnored bool) (untracked, unmerged []git.TopPath, _ error) {
-	if len(args) == 0 {
+func findAddFiles(ctx context.Context, g *git.Git, pats *patternSet, includeIgnored bool) (untracked, unmerged []git.TopPath, _ error) {
+	if !pats.hasIncludes() {
 		return nil, nil, nil
 	}
-	statusArgs := make([]git.Pathspec, len(args))
-	for i := range args {
-		statusArgs[i] = git.LiteralPath(args[i])
+	statusArgs, err := pats.pathspecs(ctx, g)
+	if err != nil {
+		return nil, nil, err
 	}
 	st, err := g.Status(ctx, git.StatusOptions{
 		Pathspecs:      statusArgs,
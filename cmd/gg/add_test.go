@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
@@ -348,3 +349,119 @@ func TestAdd_IgnoredFileInDirectory(t *testing.T) {
 		t.Error("File foo/baz.txt not in git status")
 	}
 }
+
+func TestAdd_LargeFile(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "config", "gg.largeFileSize", "1k"); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("small.txt", dummyContent),
+		filesystem.Write("big.bin", strings.Repeat("x", 2048)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "add", "small.txt", "big.bin"); err == nil {
+		t.Error("gg add of large file succeeded")
+	} else if isUsage(err) {
+		t.Errorf("gg add of large file error: %v; want failure, not usage", err)
+	}
+	if got, want := env.stderr.String(), "gg: warning: big.bin is 2.0 KiB"; !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+	st, err := env.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ent := range st {
+		if !ent.Code.IsUntracked() {
+			t.Errorf("after failed add, %s status = '%v'; want untracked", ent.Name, ent.Code)
+		}
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "add", "--force-large", "small.txt", "big.bin"); err != nil {
+		t.Fatal(err)
+	}
+	st, err = env.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ent := range st {
+		if ent.Code[0] != 'A' && ent.Code[1] != 'A' {
+			t.Errorf("after add --force-large, %s status = '%v'; want to contain 'A'", ent.Name, ent.Code)
+		}
+	}
+}
+
+func TestAdd_Summary(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write(".gitignore", "*.log\nbuild/\n"),
+		filesystem.Write("foo/a.txt", dummyContent),
+		filesystem.Write("foo/b.txt", dummyContent),
+		filesystem.Write("foo/debug.log", dummyContent),
+		filesystem.Write("foo/build/x.o", dummyContent),
+		filesystem.Write("foo/build/y.o", dummyContent),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "add", "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "added 2 files, skipped 1 file and 1 directory ignored by .gitignore (name them explicitly to add them)\n"
+	if string(out) != want {
+		t.Errorf("gg add foo output = %q; want %q", out, want)
+	}
+}
+
+func TestParseFileSize(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    int64
+		wantErr bool
+	}{
+		{s: "", want: defaultLargeFileSize},
+		{s: "0", want: 0},
+		{s: "512", want: 512},
+		{s: "2k", want: 2048},
+		{s: "10M", want: 10 << 20},
+		{s: "1g", want: 1 << 30},
+		{s: "-1", wantErr: true},
+		{s: "big", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := parseFileSize(test.s)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("parseFileSize(%q) = _, %v; want %d, <nil>", test.s, err, test.want)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("parseFileSize(%q) = %d, <nil>; want error", test.s, got)
+		} else if got != test.want {
+			t.Errorf("parseFileSize(%q) = %d, <nil>; want %d, <nil>", test.s, got, test.want)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"gg-scm.io/pkg/git"
@@ -27,28 +28,87 @@ import (
 const addRemoveSynopsis = "add all new files, delete all missing files"
 
 func addRemove(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg addremove [FILE [...]]", addRemoveSynopsis)
+	f := flag.NewFlagSet(true, "gg addremove [--relative | --root-relative] [-I PATTERN] [-X PATTERN] [FILE [...]]", addRemoveSynopsis+patternHelp+pathStyleHelp)
+	pats := new(patternSet)
+	pats.addFlags(f)
+	pathStyle := new(pathStyleFlags)
+	pathStyle.addFlags(f)
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
-	var pathspecs []git.Pathspec
-	var doNotIgnore []git.Pathspec
-	if len(args) == 0 {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	pf, err := pathStyle.formatter(ctx, cc, cfg)
+	if err != nil {
+		return err
+	}
+	tree := &patternSet{includes: pats.includes, excludes: pats.excludes}
+	files := &patternSet{excludes: pats.excludes}
+	if f.NArg() == 0 && len(pats.includes) == 0 {
 		root, err := cc.git.WorkTree(ctx)
 		if err != nil {
 			return err
 		}
-		pathspecs = []git.Pathspec{git.LiteralPath(root)}
+		tree.args = []string{root}
 	} else {
-		for _, a := range args {
-			if info, err := os.Stat(cc.abs(a)); err == nil && !info.IsDir() {
-				doNotIgnore = append(doNotIgnore, git.LiteralPath(a))
+		for _, a := range f.Args() {
+			if info, err := os.Stat(cc.abs(a)); err == nil && !info.IsDir() && !hasPatternKind(a) {
+				files.args = append(files.args, a)
 			} else {
-				pathspecs = append(pathspecs, git.LiteralPath(a))
+				tree.args = append(tree.args, a)
+			}
+		}
+	}
+	var pathspecs []git.Pathspec
+	if tree.hasIncludes() {
+		var err error
+		pathspecs, err = tree.pathspecs(ctx, cc.git)
+		if err != nil {
+			return err
+		}
+	}
+	var doNotIgnore []git.Pathspec
+	if files.hasIncludes() {
+		var err error
+		doNotIgnore, err = files.pathspecs(ctx, cc.git)
+		if err != nil {
+			return err
+		}
+	}
+	// On file systems that ignore case or Unicode normalization, Git may
+	// report a tracked file a second time under a different name. Case
+	// differences are renames, so stage them as such. Otherwise, the
+	// untracked name is an artifact of the file system and is skipped.
+	st, err := cc.git.Status(ctx, git.StatusOptions{
+		Pathspecs: append(append([]git.Pathspec(nil), pathspecs...), doNotIgnore...),
+	})
+	if err != nil {
+		return err
+	}
+	aliases, err := findPathAliases(ctx, cc.git, st)
+	if err != nil {
+		return err
+	}
+	for _, a := range aliases {
+		if a.isCaseRename() {
+			trackedSpec := git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true}, a.tracked.String())
+			if err := cc.git.Run(ctx, "rm", "--cached", "-r", "-q", "--", trackedSpec.String()); err != nil {
+				return err
 			}
+			continue
+		}
+		fmt.Fprintf(cc.stderr, "gg: ignoring %s: same file as %s (file system normalizes names)\n", pf.format(a.untracked), pf.format(a.tracked))
+		skip := git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true, Exclude: true}, a.untracked.String())
+		if len(pathspecs) > 0 {
+			pathspecs = append(pathspecs, skip)
+		}
+		if len(doNotIgnore) > 0 {
+			doNotIgnore = append(doNotIgnore, skip)
 		}
 	}
 	err1 := cc.git.Add(ctx, pathspecs, git.AddOptions{
@@ -0,0 +1,96 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const amendSynopsis = "amend the working copy's parent with outstanding changes"
+
+func amend(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg amend [-e | -m MSG] [--date DATE] [--allow-rewrite-published] [--force-protected] [--no-verify] [-I PATTERN] [-X PATTERN] [FILE [...]]", amendSynopsis+`
+
+	Folds changes to the given files, or all changes reported by
+	`+"`gg status`"+` if no files are given, into the commit that the working
+	copy is on, as in Mercurial. It is like `+"`gg commit --amend`"+`, but
+	keeps the commit's message unless `+"`-e`"+` or `+"`-m`"+` is given.
+
+	The amended commit keeps its author date. `+"`--date now`"+` sets it to
+	the current time, and any other date that git-commit(1) accepts
+	sets it to that date.
+
+	`+"`gg amend`"+` refuses to rewrite a commit that is already on a remote
+	branch, since the amended commit would have to be force-pushed. Pass
+	`+"`--allow-rewrite-published`"+` to amend it anyway.`+protectedBranchHelp+commitGuardHelp+hookOutputHelp+dateSkewHelp+patternHelp)
+	pats := new(patternSet)
+	pats.addFlags(f)
+	edit := f.Bool("e", false, "edit the commit message")
+	f.Alias("e", "edit")
+	msg := f.String("m", "", "use text as the commit `message`")
+	date := f.String("date", "", "set the author `date`, like \"now\"")
+	allowPublished := f.Bool("allow-rewrite-published", false, allowRewritePublishedUsage)
+	forceProtected := f.Bool("force-protected", false, forceProtectedUsage)
+	runHooks := f.Bool("hooks", true, "whether to run Git hooks")
+	noVerify := f.Bool("no-verify", false, noVerifyUsage)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if *edit && *msg != "" {
+		return usagef("can't pass both --edit and -m")
+	}
+	if f.IsSet("date") && (*date == "" || strings.HasPrefix(*date, "-")) {
+		return usagef("invalid --date %q", *date)
+	}
+	pats.args = f.Args()
+	pathspecs, err := pats.pathspecs(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	warnIdentityMismatch(ctx, cc)
+	if !*noVerify {
+		if err := checkCommitChanges(ctx, cc, pathspecs); err != nil {
+			return err
+		}
+	}
+	if !*forceProtected {
+		if err := checkRewriteProtected(ctx, cc, "amend"); err != nil {
+			return err
+		}
+	}
+	if !*allowPublished {
+		if err := checkRewritePublished(ctx, cc.git, "amend", "--max-count=1", git.Head.String()); err != nil {
+			return err
+		}
+	}
+	err = doAmend(ctx, cc, *msg, pathspecs, *runHooks, amendOptions{
+		keepMessage: !*edit,
+		date:        *date,
+	})
+	if err != nil {
+		return err
+	}
+	warnDateSkew(ctx, cc, *date == "")
+	return nil
+}
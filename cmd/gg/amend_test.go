@@ -0,0 +1,114 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestAmend(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "old\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Commit(ctx, "Original message", git.CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	oldDate := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+	if err := env.git.Run(ctx, "commit", "--amend", "--no-edit", "--quiet", "--date="+oldDate.Format(time.RFC3339)); err != nil {
+		t.Fatal(err)
+	}
+	r1, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.root.Apply(filesystem.Write("foo.txt", "new\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "amend"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Hash == r1.Commit {
+		t.Fatal("amend did not create a new commit")
+	}
+	if len(info.Parents) != 0 {
+		t.Errorf("amended commit has parents %v; want none", info.Parents)
+	}
+	if data, err := catBlob(ctx, env.git, info.Hash.String(), "foo.txt"); err != nil {
+		t.Error(err)
+	} else if got, want := string(data), "new\n"; got != want {
+		t.Errorf("foo.txt = %q; want %q", got, want)
+	}
+	if got, want := info.Message, "Original message\n"; got != want {
+		t.Errorf("commit message = %q; want %q", got, want)
+	}
+	if !info.AuthorTime.Equal(oldDate) {
+		t.Errorf("author date = %v; want %v", info.AuthorTime, oldDate)
+	}
+}
+
+func TestAmend_DateNow(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	oldDate := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+	if err := env.git.Run(ctx, "commit", "--amend", "--no-edit", "--quiet", "--date="+oldDate.Format(time.RFC3339)); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now().Add(-time.Minute)
+	if _, err := env.gg(ctx, env.root.String(), "amend", "--date", "now", "-m", "New message"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.AuthorTime.Before(before) {
+		t.Errorf("author date = %v; want after %v", info.AuthorTime, before)
+	}
+	if got, want := info.Message, "New message\n"; got != want {
+		t.Errorf("commit message = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const applySynopsis = "apply a patch to the working copy"
+
+func apply(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg apply [--reverse] [--check] [--3way] [-p NUM] [FILE | -]", applySynopsis+`
+
+	Applies the unified diff in FILE, or standard input if FILE is "-" or
+	not given, to the working copy. Patches copied from a code review tool
+	or a terminal are accepted: Windows line endings, text around the
+	diff, and a missing final newline are ignored. New files in the patch
+	are tracked and deleted files are removed, as if by `+"`gg add`"+` and
+	`+"`gg remove`"+`.
+
+	If any part of the patch does not apply, nothing is changed and the
+	hunks that failed are listed. With `+"`--3way`"+`, gg instead falls back to
+	a three-way merge using the file versions named in the patch's index
+	lines, leaving conflict markers in the files that could not be merged.
+	Resolve the conflicts, then mark each file resolved with `+"`gg add`"+`.`)
+	reverse := f.Bool("reverse", false, "undo the changes in the patch")
+	f.Alias("reverse", "R")
+	check := f.Bool("check", false, "report whether the patch applies without changing any files")
+	threeWay := f.Bool("3way", false, "fall back to a three-way merge if the patch does not apply cleanly")
+	strip := f.Int("p", 1, "remove `num` leading components from file names in the patch")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 1 {
+		return usagef("can only apply one patch at a time")
+	}
+	if *strip < 0 {
+		return usagef("-p must not be negative")
+	}
+	if *check && *threeWay {
+		return usagef("cannot pass both --check and --3way")
+	}
+
+	var data []byte
+	var err error
+	if f.NArg() == 0 || f.Arg(0) == "-" {
+		data, err = io.ReadAll(cc.stdin)
+	} else {
+		data, err = os.ReadFile(cc.abs(f.Arg(0)))
+	}
+	if err != nil {
+		return err
+	}
+	patch := normalizePatch(data)
+	if patch == "" {
+		return errors.New("no diff found in patch")
+	}
+	gitDir, err := cc.git.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(cc.abs(gitDir), "gg-apply-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	patchPath := filepath.Join(tmpDir, "patch")
+	if err := os.WriteFile(patchPath, []byte(patch), 0o666); err != nil {
+		return err
+	}
+
+	// Paths in a patch are relative to the top of the working tree, but
+	// git apply treats them as relative to its working directory.
+	top, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	cc = cc.withDir(top)
+	applyArgs := []string{"apply", "-p" + strconv.Itoa(*strip)}
+	if *reverse {
+		applyArgs = append(applyArgs, "--reverse")
+	}
+	changes, err := patchChanges(ctx, cc.git, applyArgs, patchPath)
+	if err != nil {
+		return err
+	}
+	stderr, checkErr := runApply(ctx, cc, append(applyArgs, "--check", patchPath))
+	if checkErr != nil && !*threeWay {
+		return applyFailure(stderr, checkErr)
+	}
+	if *check {
+		for _, c := range changes {
+			if _, err := fmt.Fprintf(cc.stdout, "%c %s\n", c.letter, c.name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if checkErr != nil {
+		return applyThreeWay(ctx, cc, applyArgs, patchPath, changes)
+	}
+	if stderr, err := runApply(ctx, cc, append(applyArgs, patchPath)); err != nil {
+		return applyFailure(stderr, err)
+	}
+	return trackPatchChanges(ctx, cc, changes)
+}
+
+// normalizePatch cleans up a patch that was copied from a web page or
+// terminal so that git apply accepts it. It returns the empty string if
+// there is no diff in data.
+func normalizePatch(data []byte) string {
+	s := strings.ReplaceAll(string(data), "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") ||
+			strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return ""
+	}
+	lines = lines[start:]
+	// Drop trailing text that isn't part of a hunk, like a signature or
+	// blank lines added by the clipboard.
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return strings.Join(lines[:end], "\n") + "\n"
+}
+
+// A patchChange is a file that a patch modifies, creates, or deletes.
+type patchChange struct {
+	name   git.TopPath
+	letter byte // 'M', 'A', or 'R', as in gg status
+}
+
+// patchChanges returns the files that applying the patch would change.
+func patchChanges(ctx context.Context, g *git.Git, applyArgs []string, patchPath string) ([]patchChange, error) {
+	// git apply --numstat --summary doesn't read the working copy, so it
+	// works even if the patch doesn't apply.
+	args := append(append([]string(nil), applyArgs...), "--numstat", "--summary", "-z", patchPath)
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("read patch: %w", err)
+	}
+	// The numstat entries are NUL-terminated, but the summary that
+	// follows them is newline-terminated.
+	numstat, summary := out, ""
+	if i := strings.LastIndexByte(out, '\x00'); i >= 0 {
+		numstat, summary = out[:i], out[i+1:]
+	}
+	var changes []patchChange
+	index := make(map[git.TopPath]int)
+	add := func(name string, letter byte) {
+		if i, ok := index[git.TopPath(name)]; ok {
+			changes[i].letter = letter
+			return
+		}
+		index[git.TopPath(name)] = len(changes)
+		changes = append(changes, patchChange{name: git.TopPath(name), letter: letter})
+	}
+	fields := strings.Split(numstat, "\x00")
+	for i := 0; i < len(fields); i++ {
+		// "added\tdeleted\tpath", or "added\tdeleted\t" followed by the
+		// old and new paths of a rename.
+		parts := strings.SplitN(fields[i], "\t", 3)
+		switch {
+		case len(parts) != 3:
+			continue
+		case parts[2] != "":
+			add(parts[2], 'M')
+		case i+2 < len(fields):
+			add(fields[i+1], 'R')
+			add(fields[i+2], 'A')
+			i += 2
+		}
+	}
+	for _, line := range strings.Split(summary, "\n") {
+		// " create mode 100644 path"
+		parts := strings.SplitN(strings.TrimPrefix(line, " "), " ", 4)
+		if len(parts) != 4 || parts[1] != "mode" || parts[0] != "create" && parts[0] != "delete" {
+			continue
+		}
+		name := parts[3]
+		if strings.HasPrefix(name, `"`) {
+			if unquoted, err := strconv.Unquote(name); err == nil {
+				name = unquoted
+			}
+		}
+		if parts[0] == "create" {
+			add(name, 'A')
+		} else {
+			add(name, 'R')
+		}
+	}
+	return changes, nil
+}
+
+// runApply runs git apply and returns its error output.
+func runApply(ctx context.Context, cc *cmdContext, args []string) (string, error) {
+	stderr := new(bytes.Buffer)
+	err := cc.git.Runner().RunGit(ctx, &git.Invocation{
+		Args:   args,
+		Dir:    cc.dir,
+		Stdout: io.Discard,
+		Stderr: stderr,
+	})
+	return stderr.String(), err
+}
+
+var (
+	applyHunkFailedPattern = regexp.MustCompile(`^error: patch failed: (.+):([0-9]+)$`)
+	applyErrorPattern      = regexp.MustCompile(`^error: (.+?): (.+)$`)
+)
+
+// applyFailure converts git apply's error output into a list of the
+// hunks that did not apply.
+func applyFailure(stderr string, err error) error {
+	msg := new(strings.Builder)
+	msg.WriteString("patch does not apply")
+	for _, line := range strings.Split(stderr, "\n") {
+		if m := applyHunkFailedPattern.FindStringSubmatch(line); m != nil {
+			fmt.Fprintf(msg, "\n\t%s: hunk at line %s does not match", m[1], m[2])
+		} else if m := applyErrorPattern.FindStringSubmatch(line); m != nil && m[2] != "patch does not apply" {
+			fmt.Fprintf(msg, "\n\t%s: %s", m[1], m[2])
+		}
+	}
+	if msg.Len() == len("patch does not apply") {
+		return fmt.Errorf("patch does not apply: %w", err)
+	}
+	msg.WriteString("\n(pass --3way to merge the changes instead)")
+	return errors.New(msg.String())
+}
+
+// applyThreeWay applies the patch with git apply --3way and reports any
+// conflicted files.
+func applyThreeWay(ctx context.Context, cc *cmdContext, applyArgs []string, patchPath string, changes []patchChange) error {
+	// git apply --3way updates the index, so the modified files must
+	// match it first.
+	var existing []git.Pathspec
+	for _, c := range changes {
+		if c.letter != 'A' {
+			existing = append(existing, c.name.Pathspec())
+		}
+	}
+	if len(existing) > 0 {
+		if err := cc.git.Add(ctx, existing, git.AddOptions{}); err != nil {
+			return err
+		}
+	}
+	stderr, applyErr := runApply(ctx, cc, append(applyArgs, "--3way", patchPath))
+	st, err := cc.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		return err
+	}
+	var conflicts []git.TopPath
+	for _, ent := range st {
+		if ent.Code.IsUnmerged() {
+			conflicts = append(conflicts, ent.Name)
+		}
+	}
+	if len(conflicts) == 0 {
+		if applyErr != nil {
+			return applyFailure(stderr, applyErr)
+		}
+		return nil
+	}
+	msg := new(strings.Builder)
+	msg.WriteString("patch applied with conflicts in:")
+	for _, name := range conflicts {
+		fmt.Fprintf(msg, "\n\t%s", name)
+	}
+	msg.WriteString("\nresolve the conflicts, then mark each file resolved with gg add")
+	return errors.New(msg.String())
+}
+
+// trackPatchChanges tracks the files created by a patch and removes the
+// files it deleted.
+func trackPatchChanges(ctx context.Context, cc *cmdContext, changes []patchChange) error {
+	var added, removed []git.Pathspec
+	for _, c := range changes {
+		switch c.letter {
+		case 'A':
+			added = append(added, c.name.Pathspec())
+		case 'R':
+			removed = append(removed, c.name.Pathspec())
+		}
+	}
+	if len(added) > 0 {
+		// Reset first in case the file was removed from the index, like
+		// when a patch that deleted the file is applied in reverse.
+		resetArgs := []string{"reset", "--quiet", "--"}
+		for _, spec := range added {
+			resetArgs = append(resetArgs, spec.String())
+		}
+		if err := cc.git.Run(ctx, resetArgs...); err != nil {
+			return err
+		}
+		if err := cc.git.Add(ctx, added, git.AddOptions{IntentToAdd: true}); err != nil {
+			return err
+		}
+	}
+	if len(removed) > 0 {
+		err := cc.git.Remove(ctx, removed, git.RemoveOptions{
+			KeepWorkingCopy: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
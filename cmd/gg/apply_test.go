@@ -0,0 +1,218 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("modified.txt", "one\ntwo\nthree\n"),
+		filesystem.Write("deleted.txt", dummyContent),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "modified.txt", "deleted.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	// A patch as it might be pasted from a web page: with CRLF line
+	// endings and text around it.
+	patch := "Here's the fix:\r\n" +
+		"\r\n" +
+		"diff --git a/modified.txt b/modified.txt\r\n" +
+		"--- a/modified.txt\r\n" +
+		"+++ b/modified.txt\r\n" +
+		"@@ -1,3 +1,3 @@\r\n" +
+		" one\r\n" +
+		"-two\r\n" +
+		"+TWO\r\n" +
+		" three\r\n" +
+		"diff --git a/deleted.txt b/deleted.txt\r\n" +
+		"deleted file mode 100644\r\n" +
+		"--- a/deleted.txt\r\n" +
+		"+++ /dev/null\r\n" +
+		"@@ -1 +0,0 @@\r\n" +
+		"-" + strings.TrimSuffix(dummyContent, "\n") + "\r\n" +
+		"diff --git a/added.txt b/added.txt\r\n" +
+		"new file mode 100644\r\n" +
+		"--- /dev/null\r\n" +
+		"+++ b/added.txt\r\n" +
+		"@@ -0,0 +1 @@\r\n" +
+		"+new\r\n" +
+		"\r\n"
+	if err := env.topDir.Apply(filesystem.Write("fix.patch", patch)); err != nil {
+		t.Fatal(err)
+	}
+	patchPath := env.topDir.FromSlash("fix.patch")
+
+	out, err := env.gg(ctx, env.root.String(), "apply", "--check", patchPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "M modified.txt\nR deleted.txt\nA added.txt\n"; got != want {
+		t.Errorf("gg apply --check output = %q; want %q", got, want)
+	}
+	if got, err := env.root.ReadFile("modified.txt"); err != nil {
+		t.Fatal(err)
+	} else if got != "one\ntwo\nthree\n" {
+		t.Fatalf("gg apply --check changed modified.txt to %q", got)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "apply", patchPath); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := env.root.ReadFile("modified.txt"); err != nil {
+		t.Error(err)
+	} else if want := "one\nTWO\nthree\n"; got != want {
+		t.Errorf("modified.txt = %q; want %q", got, want)
+	}
+	st, err := env.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make(map[git.TopPath]git.StatusCode)
+	for _, ent := range st {
+		codes[ent.Name] = ent.Code
+	}
+	if code := codes["added.txt"]; !code.IsAdded() {
+		t.Errorf("added.txt status = '%v'; want added", code)
+	}
+	if code := codes["deleted.txt"]; !code.IsRemoved() {
+		t.Errorf("deleted.txt status = '%v'; want removed", code)
+	}
+
+	// Applying in reverse restores the original files.
+	if _, err := env.gg(ctx, env.root.String(), "apply", "--reverse", patchPath); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := env.root.ReadFile("modified.txt"); err != nil {
+		t.Error(err)
+	} else if want := "one\ntwo\nthree\n"; got != want {
+		t.Errorf("after --reverse, modified.txt = %q; want %q", got, want)
+	}
+	if exists, err := env.root.Exists("added.txt"); err != nil {
+		t.Error(err)
+	} else if exists {
+		t.Error("after --reverse, added.txt exists")
+	}
+}
+
+func TestApply_Conflict(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	const original = "one\ntwo\nthree\n"
+	if err := env.root.Apply(filesystem.Write("foo.txt", original)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	// Create a patch with index lines, then make a conflicting change.
+	if err := env.root.Apply(filesystem.Write("foo.txt", "one\nTWO\nthree\n")); err != nil {
+		t.Fatal(err)
+	}
+	patch, err := env.git.Output(ctx, "diff", "--full-index", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.topDir.Apply(filesystem.Write("fix.patch", patch)); err != nil {
+		t.Fatal(err)
+	}
+	patchPath := env.topDir.FromSlash("fix.patch")
+	if err := env.root.Apply(filesystem.Write("foo.txt", "one\n2\nthree\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, env.root.String(), "apply", patchPath)
+	if err == nil {
+		t.Fatal("gg apply succeeded with a conflicting change")
+	}
+	if !strings.Contains(err.Error(), "foo.txt: hunk at line 1 does not match") {
+		t.Errorf("error = %v; want to mention the failed hunk", err)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Fatal(err)
+	} else if want := "one\n2\nthree\n"; got != want {
+		t.Fatalf("after failed apply, foo.txt = %q; want %q", got, want)
+	}
+
+	_, err = env.gg(ctx, env.root.String(), "apply", "--3way", patchPath)
+	if err == nil {
+		t.Fatal("gg apply --3way succeeded with a conflicting change")
+	}
+	if !strings.Contains(err.Error(), "conflicts in:\n\tfoo.txt") {
+		t.Errorf("error = %v; want to list foo.txt as conflicted", err)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Error(err)
+	} else if !strings.Contains(got, "<<<<<<<") {
+		t.Errorf("after gg apply --3way, foo.txt = %q; want conflict markers", got)
+	}
+}
+
+func TestNormalizePatch(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: "no diff here\n", want: ""},
+		{
+			in:   "--- a/foo\n+++ b/foo\n@@ -1 +1 @@\n-a\n+b",
+			want: "--- a/foo\n+++ b/foo\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+		{
+			in:   "Subject: fix\r\n\r\ndiff --git a/foo b/foo\r\n--- a/foo\r\n+++ b/foo\r\n@@ -1 +1 @@\r\n-a\r\n+b\r\n\r\n\r\n",
+			want: "diff --git a/foo b/foo\n--- a/foo\n+++ b/foo\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+	}
+	for _, test := range tests {
+		if got := normalizePatch([]byte(test.in)); got != test.want {
+			t.Errorf("normalizePatch(%q) = %q; want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/tool/internal/flag"
+)
+
+const attrsSynopsis = "show the effective attributes of files"
+
+func attrs(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg attrs [--json] FILE [...]", attrsSynopsis+`
+
+	Print the attributes that apply to each file from .gitattributes and
+	other attribute sources. Attributes control line ending conversion
+	(text, eol), diff and merge drivers (diff, merge), content filters
+	like Git LFS (filter), and archiving (export-ignore). See
+	gitattributes(5) for details.
+
+	An attribute's value is "set", "unset", or a string.`)
+	jsonOutput := f.Bool("json", false, "print attributes as JSON")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() == 0 {
+		return usagef("must pass one or more files")
+	}
+	results, err := checkAttrs(ctx, cc, f.Args())
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		out, err := json.MarshalIndent(results, "", "\t")
+		if err != nil {
+			return err
+		}
+		out = append(out, '\n')
+		_, err = cc.stdout.Write(out)
+		return err
+	}
+	for i, r := range results {
+		if i > 0 {
+			if _, err := fmt.Fprintln(cc.stdout); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(cc.stdout, "%s:\n", r.Path); err != nil {
+			return err
+		}
+		if len(r.Attributes) == 0 {
+			if _, err := fmt.Fprintln(cc.stdout, "  (no attributes)"); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, a := range r.Attributes {
+			if _, err := fmt.Fprintf(cc.stdout, "  %s: %s\n", a.Name, a.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pathAttrs is the set of attributes for a single path.
+type pathAttrs struct {
+	Path       string      `json:"path"`
+	Attributes []attribute `json:"attributes"`
+}
+
+// attribute is a single Git attribute.
+// Value is "set", "unset", or the attribute's value.
+type attribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// checkAttrs runs git check-attr for the given paths, returning one
+// result per path in the same order.
+func checkAttrs(ctx context.Context, cc *cmdContext, paths []string) ([]pathAttrs, error) {
+	checkArgs := []string{"check-attr", "--all", "-z", "--"}
+	checkArgs = append(checkArgs, paths...)
+	out, err := cc.git.Output(ctx, checkArgs...)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]pathAttrs, len(paths))
+	index := make(map[string]int, len(paths))
+	for i, p := range paths {
+		results[i] = pathAttrs{Path: p, Attributes: []attribute{}}
+		if _, dup := index[p]; !dup {
+			index[p] = i
+		}
+	}
+	// Output is a sequence of NUL-terminated (path, attribute, value) triples.
+	fields := strings.Split(out, "\x00")
+	if len(fields) > 0 && fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields)%3 != 0 {
+		return nil, errors.New("check attributes: unexpected output from git check-attr")
+	}
+	for i := 0; i < len(fields); i += 3 {
+		p, name, value := fields[i], fields[i+1], fields[i+2]
+		j, ok := index[p]
+		if !ok {
+			return nil, fmt.Errorf("check attributes: git check-attr reported unknown path %q", p)
+		}
+		results[j].Attributes = append(results[j].Attributes, attribute{Name: name, Value: value})
+	}
+	return results, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAttrs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write(".gitattributes", "*.go diff=golang eol=lf\n"+
+			"*.png binary\n"+
+			"testdata/** export-ignore\n"),
+		filesystem.Write("main.go", "package main\n"),
+		filesystem.Write("testdata/foo.go", "package foo\n"),
+		filesystem.Write("README", "Hello\n"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		out, err := env.gg(ctx, env.root.String(), "attrs", "main.go", "README")
+		if err != nil {
+			t.Fatal(err)
+		}
+		const want = "main.go:\n" +
+			"  diff: golang\n" +
+			"  eol: lf\n" +
+			"\n" +
+			"README:\n" +
+			"  (no attributes)\n"
+		if diff := cmp.Diff(want, string(out)); diff != "" {
+			t.Errorf("output (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("JSON", func(t *testing.T) {
+		out, err := env.gg(ctx, env.root.String(), "attrs", "--json", "testdata/foo.go", "image.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []pathAttrs
+		if err := json.Unmarshal(out, &got); err != nil {
+			t.Fatal(err)
+		}
+		want := []pathAttrs{
+			{
+				Path: "testdata/foo.go",
+				Attributes: []attribute{
+					{Name: "diff", Value: "golang"},
+					{Name: "eol", Value: "lf"},
+					{Name: "export-ignore", Value: "set"},
+				},
+			},
+			{
+				Path: "image.png",
+				Attributes: []attribute{
+					{Name: "binary", Value: "set"},
+					{Name: "diff", Value: "unset"},
+					{Name: "merge", Value: "unset"},
+					{Name: "text", Value: "unset"},
+				},
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("attributes (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("NoArgs", func(t *testing.T) {
+		if _, err := env.gg(ctx, env.root.String(), "attrs"); err == nil {
+			t.Error("gg attrs did not return error")
+		} else if !isUsage(err) {
+			t.Errorf("Error = %v; want usage", err)
+		}
+	})
+}
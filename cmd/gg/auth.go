@@ -0,0 +1,403 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"gg-scm.io/tool/internal/flag"
+	"golang.org/x/exp/slices"
+)
+
+const authSynopsis = "manage GitHub accounts"
+
+// defaultGitHubAccount is the name of the account whose token is saved in
+// gitHubTokenFilename. It is used unless a repository picks another.
+const defaultGitHubAccount = "default"
+
+// gitHubAccountsDir is the directory inside the gg config directory that
+// holds the tokens of named GitHub accounts, one file per account.
+const gitHubAccountsDir = "github_accounts"
+
+func auth(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg auth [list]\n"+
+		"gg auth use ACCOUNT\n"+
+		"gg auth remove ACCOUNT\n"+
+		"gg auth encrypt | decrypt | lock", authSynopsis+`
+
+	gg can keep tokens for more than one GitHub account, like "work" and
+	"personal". Log into another account with
+	`+"`gg github-login --account ACCOUNT`"+`. The account that
+	`+"`gg github-login`"+` logs into without `+"`--account`"+` is named
+	"default".
+
+	`+"`gg auth use`"+` picks the account for the repository by setting its
+	`+"`gg.github.account`"+` configuration setting. `+"`gg requestpull`"+`,
+	`+"`gg pr comments`"+`, and `+"`gg release`"+` use that account's token and
+	report which account they are acting as. `+"`gg auth`"+` lists the
+	accounts and marks the one the repository uses.
+
+	`+"`gg auth encrypt`"+` encrypts the saved tokens with a passphrase, for
+	systems without a keychain where the token files shouldn't be stored
+	as plain text. gg asks for the passphrase the first time it needs a
+	token in a login session and keeps the unlocked key in
+	`+"`$XDG_RUNTIME_DIR`"+` until the session ends or `+"`gg auth lock`"+` is
+	run. Without `+"`$XDG_RUNTIME_DIR`"+`, gg asks every time. Tokens saved
+	later by `+"`gg github-login`"+` are encrypted too.
+	`+"`gg auth decrypt`"+` stores the tokens as plain text again.`)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	sub := f.Arg(0)
+	switch sub {
+	case "", "list":
+		if f.NArg() > 1 {
+			return usagef("list takes no arguments")
+		}
+		return listGitHubAccounts(ctx, cc)
+	case "encrypt", "decrypt", "lock":
+		if f.NArg() > 1 {
+			return usagef("%s takes no arguments", sub)
+		}
+		switch sub {
+		case "encrypt":
+			return encryptGitHubAccounts(cc)
+		case "decrypt":
+			return decryptGitHubAccounts(cc)
+		default:
+			return cc.xdgDirs.removeRuntime(tokenKeyCacheFile)
+		}
+	case "use", "remove":
+		if f.NArg() != 2 {
+			return usagef("%s takes a single ACCOUNT", sub)
+		}
+	default:
+		return usagef("unknown subcommand %q", sub)
+	}
+	name := f.Arg(1)
+	if !identityNameRegexp.MatchString(name) {
+		return usagef("invalid account name %q (must be lowercase letters, digits, '-', or '_')", name)
+	}
+	if _, err := statGitHubAccount(cc, name); os.IsNotExist(err) {
+		return fmt.Errorf("no GitHub account %q (log into it with 'gg github-login --account %s')", name, name)
+	} else if err != nil {
+		return err
+	}
+	switch sub {
+	case "use":
+		if name == defaultGitHubAccount {
+			local, err := listConfig(ctx, cc.git, "--local")
+			if err != nil {
+				return err
+			}
+			for _, ent := range local {
+				if ent.key == "gg.github.account" {
+					return cc.git.Run(ctx, "config", "--local", "--unset-all", "--", "gg.github.account")
+				}
+			}
+			return nil
+		}
+		return cc.git.Run(ctx, "config", "--local", "--", "gg.github.account", name)
+	default:
+		return os.Remove(filepath.Join(cc.xdgDirs.configHome, configDirname, filepath.FromSlash(gitHubAccountFile(name))))
+	}
+}
+
+// A gitHubAccount is a saved GitHub token.
+type gitHubAccount struct {
+	name  string
+	token string
+	login string // GitHub user name, empty if not known
+
+	// encrypted is the content of the token file if the token is
+	// encrypted, nil otherwise.
+	encrypted *encryptedToken
+}
+
+// gitHubAccountFile returns the slash-separated path of the account's
+// token file relative to the gg config directory.
+func gitHubAccountFile(name string) string {
+	if name == defaultGitHubAccount {
+		return gitHubTokenFilename
+	}
+	return gitHubAccountsDir + "/" + name
+}
+
+// readGitHubAccount reads the named account's token file, asking for the
+// passphrase if the token is encrypted and not yet unlocked.
+func readGitHubAccount(cc *cmdContext, name string) (*gitHubAccount, error) {
+	acct, err := statGitHubAccount(cc, name)
+	if err != nil {
+		return nil, err
+	}
+	if acct.encrypted != nil {
+		token, _, err := unlockToken(cc, acct.encrypted)
+		if err != nil {
+			return nil, err
+		}
+		acct.token = string(token)
+	}
+	return acct, nil
+}
+
+// statGitHubAccount reads the named account's token file without
+// decrypting it. The token field is empty if the token is encrypted.
+// Otherwise, the first line of the file is the token, and the optional
+// second line is the login.
+func statGitHubAccount(cc *cmdContext, name string) (*gitHubAccount, error) {
+	data, err := cc.xdgDirs.readConfig(gitHubAccountFile(name))
+	if err != nil {
+		return nil, err
+	}
+	if isEncryptedToken(data) {
+		tok, err := parseEncryptedToken(data)
+		if err != nil {
+			return nil, fmt.Errorf("GitHub account %s: %w", name, err)
+		}
+		return &gitHubAccount{name: name, login: tok.login, encrypted: tok}, nil
+	}
+	return parseGitHubAccount(name, data), nil
+}
+
+func parseGitHubAccount(name string, data []byte) *gitHubAccount {
+	token, login, _ := bytes.Cut(bytes.TrimSpace(data), []byte("\n"))
+	return &gitHubAccount{
+		name:  name,
+		token: string(bytes.TrimSpace(token)),
+		login: string(bytes.TrimSpace(login)),
+	}
+}
+
+// writeGitHubAccount saves the account's token file. The token is
+// encrypted if other saved tokens are.
+func writeGitHubAccount(cc *cmdContext, acct *gitHubAccount) error {
+	key, err := gitHubTokenStoreKey(cc)
+	if err != nil {
+		return err
+	}
+	return saveGitHubAccount(cc, acct, key)
+}
+
+// saveGitHubAccount saves the account's token file, encrypting the token
+// with key if it is not nil.
+func saveGitHubAccount(cc *cmdContext, acct *gitHubAccount, key *tokenKey) error {
+	if key == nil {
+		data := acct.token + "\n"
+		if acct.login != "" {
+			data += acct.login + "\n"
+		}
+		return cc.xdgDirs.writeSecret(gitHubAccountFile(acct.name), []byte(data))
+	}
+	sealed, err := key.seal([]byte(acct.token))
+	if err != nil {
+		return err
+	}
+	tok := &encryptedToken{salt: key.salt, login: acct.login, sealed: sealed}
+	return cc.xdgDirs.writeSecret(gitHubAccountFile(acct.name), tok.marshal())
+}
+
+// gitHubTokenStoreKey returns the key that the saved tokens are
+// encrypted with or nil if they are stored as plain text.
+func gitHubTokenStoreKey(cc *cmdContext) (*tokenKey, error) {
+	names, err := gitHubAccountNames(cc)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		acct, err := statGitHubAccount(cc, name)
+		if err != nil {
+			return nil, err
+		}
+		if acct.encrypted != nil {
+			_, key, err := unlockToken(cc, acct.encrypted)
+			return key, err
+		}
+	}
+	return nil, nil
+}
+
+// encryptGitHubAccounts encrypts the saved tokens that are stored as
+// plain text. It uses the key of any already encrypted tokens and
+// otherwise asks for a new passphrase.
+func encryptGitHubAccounts(cc *cmdContext) error {
+	names, err := gitHubAccountNames(cc)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return errors.New("no GitHub accounts (log into one with 'gg github-login')")
+	}
+	var plain []*gitHubAccount
+	var key *tokenKey
+	for _, name := range names {
+		acct, err := statGitHubAccount(cc, name)
+		if err != nil {
+			return err
+		}
+		if acct.encrypted == nil {
+			plain = append(plain, acct)
+		} else if key == nil {
+			if _, key, err = unlockToken(cc, acct.encrypted); err != nil {
+				return err
+			}
+		}
+	}
+	if len(plain) == 0 {
+		_, err := fmt.Fprintln(cc.stderr, "gg: GitHub tokens are already encrypted")
+		return err
+	}
+	if key == nil {
+		passphrase, err := readNewPassphrase(cc)
+		if err != nil {
+			return err
+		}
+		if key, err = newTokenKey(passphrase); err != nil {
+			return err
+		}
+		cacheTokenKey(cc.xdgDirs, key)
+	}
+	for _, acct := range plain {
+		if err := saveGitHubAccount(cc, acct, key); err != nil {
+			return fmt.Errorf("encrypt GitHub account %s: %w", acct.name, err)
+		}
+	}
+	return nil
+}
+
+// decryptGitHubAccounts stores the saved tokens as plain text and
+// forgets the unlocked key.
+func decryptGitHubAccounts(cc *cmdContext) error {
+	names, err := gitHubAccountNames(cc)
+	if err != nil {
+		return err
+	}
+	var key *tokenKey
+	for _, name := range names {
+		acct, err := statGitHubAccount(cc, name)
+		if err != nil {
+			return err
+		}
+		if acct.encrypted == nil {
+			continue
+		}
+		var token []byte
+		if key != nil && bytes.Equal(key.salt, acct.encrypted.salt) {
+			token, err = key.open(acct.encrypted.sealed)
+		} else {
+			token, key, err = unlockToken(cc, acct.encrypted)
+		}
+		if err != nil {
+			return err
+		}
+		acct.token = string(token)
+		if err := saveGitHubAccount(cc, acct, nil); err != nil {
+			return fmt.Errorf("decrypt GitHub account %s: %w", name, err)
+		}
+	}
+	return cc.xdgDirs.removeRuntime(tokenKeyCacheFile)
+}
+
+// String returns the account name and login for messages.
+func (acct *gitHubAccount) String() string {
+	switch {
+	case acct.login == "":
+		return "account " + acct.name
+	case acct.name == defaultGitHubAccount:
+		return acct.login
+	default:
+		return acct.login + " (account " + acct.name + ")"
+	}
+}
+
+// selectedGitHubAccount returns the name of the GitHub account to use in
+// the current repository.
+func selectedGitHubAccount(ctx context.Context, cc *cmdContext) string {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return defaultGitHubAccount
+	}
+	if name := cfg.Value("gg.github.account"); name != "" {
+		return name
+	}
+	return defaultGitHubAccount
+}
+
+// gitHubAccountNames returns the names of the saved GitHub accounts in
+// alphabetical order.
+func gitHubAccountNames(cc *cmdContext) ([]string, error) {
+	var names []string
+	if _, err := cc.xdgDirs.readConfig(gitHubTokenFilename); err == nil {
+		names = append(names, defaultGitHubAccount)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, dir := range cc.xdgDirs.configPaths() {
+		entries, err := os.ReadDir(filepath.Join(dir, configDirname, gitHubAccountsDir))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, ent := range entries {
+			if name := ent.Name(); identityNameRegexp.MatchString(name) && name != defaultGitHubAccount && !ent.IsDir() {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return slices.Compact(names), nil
+}
+
+func listGitHubAccounts(ctx context.Context, cc *cmdContext) error {
+	names, err := gitHubAccountNames(cc)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		_, err := fmt.Fprintln(cc.stdout, "no GitHub accounts (log into one with 'gg github-login')")
+		return err
+	}
+	current := selectedGitHubAccount(ctx, cc)
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		acct, err := statGitHubAccount(cc, name)
+		if err != nil {
+			return err
+		}
+		marker := ' '
+		if name == current {
+			marker = '*'
+		}
+		if acct.encrypted != nil {
+			fmt.Fprintf(tw, "%c %s\t%s\t(encrypted)\n", marker, name, acct.login)
+		} else {
+			fmt.Fprintf(tw, "%c %s\t%s\n", marker, name, acct.login)
+		}
+	}
+	return tw.Flush()
+}
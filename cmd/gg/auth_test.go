@@ -0,0 +1,99 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestParseGitHubAccount(t *testing.T) {
+	tests := []struct {
+		data      string
+		wantToken string
+		wantLogin string
+	}{
+		{data: "xyzzy\n", wantToken: "xyzzy"},
+		{data: "xyzzy\noctocat\n", wantToken: "xyzzy", wantLogin: "octocat"},
+		{data: "  xyzzy \r\n octocat", wantToken: "xyzzy", wantLogin: "octocat"},
+	}
+	for _, test := range tests {
+		acct := parseGitHubAccount("work", []byte(test.data))
+		if acct.token != test.wantToken || acct.login != test.wantLogin {
+			t.Errorf("parseGitHubAccount(%q) = {token: %q, login: %q}; want {token: %q, login: %q}",
+				test.data, acct.token, acct.login, test.wantToken, test.wantLogin)
+		}
+	}
+}
+
+func TestAuth(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.writeGitHubAuth([]byte("xyzzy\noctocat\n")); err != nil {
+		t.Fatal(err)
+	}
+	err = env.topDir.Apply(filesystem.Write("xdgconfig/gg/github_accounts/work", "plugh\nocto-work\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "* default  octocat\n  work     octo-work\n"; got != want {
+		t.Errorf("gg auth =\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "auth", "use", "work"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := env.git.Output(ctx, "config", "--local", "gg.github.account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(got); got != "work" {
+		t.Errorf("gg.github.account = %q; want \"work\"", got)
+	}
+	out, err = env.gg(ctx, env.root.String(), "auth", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "  default  octocat\n* work     octo-work\n"; got != want {
+		t.Errorf("gg auth list =\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "auth", "use", "personal"); err == nil {
+		t.Error("gg auth use personal succeeded for a missing account")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "auth", "use", "default"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.git.Output(ctx, "config", "--local", "gg.github.account"); err == nil {
+		t.Error("gg.github.account still set after gg auth use default")
+	}
+}
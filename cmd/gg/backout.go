@@ -15,7 +15,13 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
@@ -23,14 +29,34 @@ import (
 
 const backoutSynopsis = "reverse effect of an earlier commit"
 
+// backsOutTrailer is the trailer key in a backout commit's message that
+// names the commit it reverses.
+const backsOutTrailer = "Backs-out"
+
 func backout(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg backout [options] [-r] REV", backoutSynopsis+`
+	f := flag.NewFlagSet(true, "gg backout [options] [--merge-into BRANCH] [-r] REV", backoutSynopsis+`
 
 	Prepare a new commit with the effect of `+"`REV`"+` undone in the current
 	working copy. If no conflicts were encountered, it will be committed
-	immediately (unless `+"`-n`"+` is passed).`)
+	immediately (unless `+"`-n`"+` is passed).
+
+	The commit message starts out with the original commit's summary,
+	hash, and author, a place to explain why the commit is being backed
+	out, and a `+"`"+backsOutTrailer+": HASH`"+` trailer so that tools can find
+	the commit that was reversed. With `+"`--no-edit`"+`, the message is
+	committed as is, without the explanation. If there are conflicts, the
+	message is saved for the `+"`gg commit`"+` that finishes the backout.
+
+	With `+"`--merge-into`"+`, the backout commit is then merged into BRANCH,
+	which is useful for undoing a change on a release branch and on the
+	main branch at once. The messages of the backout and the merge refer
+	to each other. After the merge, the working copy returns to the
+	original branch. If the merge has conflicts, the working copy is left
+	on BRANCH to resolve them with `+"`gg commit`"+`.`)
 	edit := f.Bool("e", true, "invoke editor on commit message")
 	f.Alias("e", "edit")
+	noEdit := f.Bool("no-edit", false, "commit the standard message without invoking an editor")
+	mergeInto := f.String("merge-into", "", "merge the backout into `branch` after committing it")
 	noCommit := f.Bool("n", false, "do not commit")
 	f.Alias("n", "no-commit")
 	rev := f.String("r", "", "`rev`ision")
@@ -40,6 +66,12 @@ func backout(ctx context.Context, cc *cmdContext, args []string) error {
 	} else if err != nil {
 		return usagef("%v", err)
 	}
+	if *noEdit {
+		if f.IsSet("e") && *edit {
+			return usagef("cannot pass both --edit and --no-edit")
+		}
+		*edit = false
+	}
 	var r *git.Rev
 	switch {
 	case f.NArg() == 0 && *rev != "":
@@ -57,14 +89,128 @@ func backout(ctx context.Context, cc *cmdContext, args []string) error {
 	default:
 		return usagef("must pass a single revision")
 	}
-	switch {
-	case *noCommit:
+	if *mergeInto != "" {
+		if *noCommit {
+			return usagef("cannot pass both --merge-into and --no-commit")
+		}
+		return backoutAndMerge(ctx, cc, r, *mergeInto, *edit)
+	}
+	if *noCommit {
 		return cc.git.Run(ctx, "revert", "--no-commit", r.Commit.String())
-	case *edit:
-		// TODO(someday): Use our editor by running --no-commit and then
-		// immediately running commit.
-		return cc.interactiveGit(ctx, "revert", "--edit", r.Commit.String())
-	default:
-		return cc.git.Run(ctx, "revert", "--no-edit", r.Commit.String())
 	}
+	return commitBackout(ctx, cc, r, "", *edit, "resolve the conflicts and run gg commit")
+}
+
+// commitBackout reverts r in the working copy and commits the result with
+// a message made by backoutMessage. extra is added to the message after the
+// description of r. If the revert has conflicts, the message is saved for
+// the commit that resolves them and the returned error ends with
+// conflictHint.
+func commitBackout(ctx context.Context, cc *cmdContext, r *git.Rev, extra string, edit bool, conflictHint string) error {
+	reverted, err := cc.git.CommitInfo(ctx, r.Commit.String())
+	if err != nil {
+		return err
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	commentChar, err := cfg.CommentChar()
+	if err != nil {
+		return err
+	}
+	if err := cc.git.Run(ctx, "revert", "--no-commit", r.Commit.String()); err != nil {
+		// Replace Git's message with ours so that gg commit uses it.
+		if gitDir, dirErr := cc.git.GitDir(ctx); dirErr == nil {
+			msg := backoutMessage(r.Commit, reverted, extra, commentChar)
+			os.WriteFile(filepath.Join(gitDir, "MERGE_MSG"), []byte(msg), 0o666)
+		}
+		return fmt.Errorf("%w\n%s", err, conflictHint)
+	}
+	msg := backoutMessage(r.Commit, reverted, extra, "")
+	if edit {
+		status, err := cc.git.DiffStatus(ctx, git.DiffStatusOptions{Commit1: git.Head.String()})
+		if err != nil {
+			return err
+		}
+		msgBuf := bytes.NewBufferString(backoutMessage(r.Commit, reverted, extra, commentChar))
+		if err := commitMessageTemplate(ctx, cc.git, status, msgBuf, commentChar); err != nil {
+			return err
+		}
+		editorOut, err := cc.editor.open(ctx, commitMsgFilename, msgBuf.Bytes())
+		if err != nil {
+			return err
+		}
+		msg = cleanupMessage(string(editorOut), commentChar)
+		if msg == "" {
+			return errors.New("empty commit message; backout not committed")
+		}
+	}
+	return cc.git.Commit(ctx, msg, git.CommitOptions{})
+}
+
+// backoutMessage returns the message for a commit that reverses the
+// given commit. If commentChar is not empty, the message includes a
+// comment that prompts for the reason for the backout.
+func backoutMessage(hash git.Hash, reverted *git.CommitInfo, extra string, commentChar string) string {
+	sb := new(strings.Builder)
+	fmt.Fprintf(sb, "Revert \"%s\"\n\n", reverted.Summary())
+	if commentChar != "" {
+		fmt.Fprintf(sb, "%s Explain why the commit is being backed out here, followed by a blank line.\n", commentChar)
+	}
+	fmt.Fprintf(sb, "This reverts commit %v\nby %s.\n", hash, reverted.Author)
+	sb.WriteString(extra)
+	fmt.Fprintf(sb, "\n%s: %v\n", backsOutTrailer, hash)
+	return sb.String()
+}
+
+// backoutAndMerge commits a backout of r on the current branch and then
+// merges it into the given branch.
+func backoutAndMerge(ctx context.Context, cc *cmdContext, r *git.Rev, branch string, edit bool) error {
+	if strings.HasPrefix(branch, "-") {
+		return usagef("invalid branch %q", branch)
+	}
+	target, err := cc.git.ParseRev(ctx, git.BranchRef(branch).String())
+	if err != nil {
+		return fmt.Errorf("merge into %s: %w", branch, err)
+	}
+	head, err := cc.git.Head(ctx)
+	if err != nil {
+		return err
+	}
+	if head.Ref == target.Ref {
+		return fmt.Errorf("%s is already checked out", branch)
+	}
+	if !head.Ref.IsBranch() {
+		return errors.New("cannot merge a backout made on a detached HEAD; check out a branch first")
+	}
+	reverted, err := cc.git.CommitInfo(ctx, r.Commit.String())
+	if err != nil {
+		return err
+	}
+	summary := reverted.Summary()
+
+	extra := fmt.Sprintf("The backout is also merged into %s.\n", branch)
+	hint := fmt.Sprintf("resolve the conflicts and commit, then merge the backout into %s", branch)
+	if err := commitBackout(ctx, cc, r, extra, edit, hint); err != nil {
+		return err
+	}
+	backoutCommit, err := cc.git.Head(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := cc.git.CheckoutBranch(ctx, branch, git.CheckoutOptions{}); err != nil {
+		return fmt.Errorf("backout committed as %v, but could not merge it: %w", backoutCommit.Commit.Short(), err)
+	}
+	mergeMsg := fmt.Sprintf("Merge backout of \"%s\" from %s into %s\n\nThis merges commit %v, which reverts commit %v.\n",
+		summary, head.Ref.Branch(), branch, backoutCommit.Commit, r.Commit)
+	if err := cc.git.Run(ctx, "merge", "--quiet", "--no-ff", "--no-edit", "-m", mergeMsg, backoutCommit.Commit.String()); err != nil {
+		return fmt.Errorf("merge backout into %s: %w\nresolve the conflicts and run gg commit, then gg update %s", branch, err, head.Ref.Branch())
+	}
+	if err := cc.git.CheckoutBranch(ctx, head.Ref.Branch(), git.CheckoutOptions{}); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cc.stdout, "backed out %v as %v and merged it into %s\n", r.Commit.Short(), backoutCommit.Commit.Short(), branch)
+	return err
 }
@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
@@ -127,3 +128,139 @@ func TestBackout_NoCommit(t *testing.T) {
 		t.Errorf("After backout, HEAD = %s; want %s", prettyCommit(got, names), prettyCommit(want, names))
 	}
 }
+
+func TestBackout_MergeInto(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Hello, World!\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Hello, World!\nI had a thought...\n")); err != nil {
+		t.Fatal(err)
+	}
+	c2, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "release", git.BranchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("bar.txt", "Another file\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	c3, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.CheckoutBranch(ctx, "release", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "backout", "--edit=0", "--merge-into=main", c2.String()); err != nil {
+		t.Fatal(err)
+	}
+	if ref, err := env.git.HeadRef(ctx); err != nil {
+		t.Fatal(err)
+	} else if ref != git.BranchRef("release") {
+		t.Errorf("after backout, HEAD = %s; want refs/heads/release", ref)
+	}
+	backoutCommit, err := env.git.CommitInfo(ctx, "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backoutCommit.Parents) != 1 || backoutCommit.Parents[0] != c2 {
+		t.Errorf("backout parents = %v; want [%v]", backoutCommit.Parents, c2)
+	}
+	if !strings.Contains(backoutCommit.Message, "merged into main") {
+		t.Errorf("backout message = %q; want to mention main", backoutCommit.Message)
+	}
+	backoutHash, err := env.git.ParseRev(ctx, "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mergeCommit, err := env.git.CommitInfo(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mergeCommit.Parents) != 2 || mergeCommit.Parents[0] != c3 || mergeCommit.Parents[1] != backoutHash.Commit {
+		t.Errorf("main parents = %v; want [%v %v]", mergeCommit.Parents, c3, backoutHash.Commit)
+	}
+	if !strings.Contains(mergeCommit.Message, backoutHash.Commit.String()) {
+		t.Errorf("merge message = %q; want to mention %v", mergeCommit.Message, backoutHash.Commit)
+	}
+	if got, err := env.git.Output(ctx, "show", "main:foo.txt"); err != nil {
+		t.Error(err)
+	} else if want := "Hello, World!\n"; got != want {
+		t.Errorf("foo.txt on main = %q; want %q", got, want)
+	}
+}
+
+func TestBackout_Message(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Hello, World!\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Hello, World!\nI had a thought...\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Commit(ctx, "Add a thought", git.CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	reverted, err := env.git.ParseRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "backout", "--no-edit", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Revert \"Add a thought\"\n",
+		"This reverts commit " + reverted.Commit.String() + "\nby User <foo@example.com>.\n",
+		"\n\nBacks-out: " + reverted.Commit.String() + "\n",
+	} {
+		if !strings.Contains(info.Message, want) {
+			t.Errorf("backout message = %q; want to contain %q", info.Message, want)
+		}
+	}
+	if strings.Contains(info.Message, "#") {
+		t.Errorf("backout message = %q; want no comments", info.Message)
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+	"golang.org/x/exp/slices"
+)
+
+const backupsSynopsis = "list or restore files saved before changes were discarded"
+
+// backupsPath is the slash-separated path, relative to the Git directory,
+// of the directory that holds backups. Each backup is a directory named
+// by its ID that contains a backupInfoFile and the saved files under
+// backupFilesDir, laid out as in the working copy.
+const backupsPath = "gg/backups"
+
+const (
+	backupInfoFile = "info.json"
+	backupFilesDir = "files"
+)
+
+// defaultBackupDays is the number of days that backups are kept if
+// gg.backupDays is not set.
+const defaultBackupDays = 30
+
+func backups(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg backups [list]\n"+
+		"gg backups restore [-f] ID [FILE [...]]", backupsSynopsis+`
+
+	Before `+"`gg revert --all`"+` or `+"`gg update --clean`"+` discards
+	uncommitted changes, it saves the files that had changes in a
+	backup, much like the .orig files that Mercurial leaves behind.
+
+	`+"`gg backups`"+` lists the backups, newest first, with the command
+	that made them and the number of files saved.
+
+	`+"`gg backups restore`"+` copies the files in the backup with the given
+	ID, or only the named files, back into the working copy. It refuses
+	to overwrite files with uncommitted changes unless `+"`-f`"+` is given.
+
+	Backups are deleted after the number of days in `+"`gg.backupDays`"+`
+	(default 30). Setting it to 0 keeps them forever.`)
+	force := f.Bool("f", false, "overwrite files with uncommitted changes")
+	f.Alias("f", "force")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	switch sub := f.Arg(0); sub {
+	case "", "list":
+		if f.NArg() > 1 {
+			return usagef("list takes no arguments")
+		}
+		if *force {
+			return usagef("-f requires restore")
+		}
+		return listBackups(ctx, cc)
+	case "restore":
+		if f.NArg() < 2 {
+			return usagef("restore takes a backup ID")
+		}
+		return restoreBackup(ctx, cc, f.Arg(1), f.Args()[2:], *force)
+	default:
+		return usagef("unknown subcommand %q", sub)
+	}
+}
+
+// backupInfo is the content of a backup's backupInfoFile.
+type backupInfo struct {
+	Operation string    `json:"operation"`
+	Time      time.Time `json:"time"`
+	Files     []string  `json:"files"` // slash-separated paths from the top of the working copy
+}
+
+// A backup is a directory of files saved by saveBackup.
+type backup struct {
+	id  string
+	dir string
+	backupInfo
+}
+
+// backupsDir returns the path of the directory that holds the backups.
+func backupsDir(ctx context.Context, g *git.Git) (string, error) {
+	gitDir, err := g.GitDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, filepath.FromSlash(backupsPath)), nil
+}
+
+// saveBackup copies the named files from the working copy into a new
+// backup and deletes backups older than gg.backupDays. Files that don't
+// exist are skipped. It returns the ID of the new backup and the number
+// of files saved, or the empty string if there was nothing to save.
+func saveBackup(ctx context.Context, cc *cmdContext, op string, files []git.TopPath) (string, int, error) {
+	top, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("back up files: %w", err)
+	}
+	var saved []string
+	for _, name := range files {
+		if _, err := os.Lstat(filepath.Join(top, filepath.FromSlash(name.String()))); err == nil {
+			saved = append(saved, name.String())
+		}
+	}
+	if len(saved) == 0 {
+		return "", 0, nil
+	}
+	root, err := backupsDir(ctx, cc.git)
+	if err != nil {
+		return "", 0, fmt.Errorf("back up files: %w", err)
+	}
+	now := time.Now()
+	if err := pruneBackups(ctx, cc, root, now); err != nil {
+		fmt.Fprintf(cc.stderr, "gg: %v\n", err)
+	}
+	if err := os.MkdirAll(root, 0o777); err != nil {
+		return "", 0, fmt.Errorf("back up files: %w", err)
+	}
+	id := now.UTC().Format("20060102T150405Z")
+	dir := filepath.Join(root, id)
+	for i := 2; ; i++ {
+		err := os.Mkdir(dir, 0o777)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return "", 0, fmt.Errorf("back up files: %w", err)
+		}
+		id = now.UTC().Format("20060102T150405Z") + "-" + strconv.Itoa(i)
+		dir = filepath.Join(root, id)
+	}
+	for _, name := range saved {
+		src := filepath.Join(top, filepath.FromSlash(name))
+		dst := filepath.Join(dir, backupFilesDir, filepath.FromSlash(name))
+		if err := copyWorkingFile(dst, src); err != nil {
+			os.RemoveAll(dir)
+			return "", 0, fmt.Errorf("back up files: %w", err)
+		}
+	}
+	data, err := json.MarshalIndent(&backupInfo{
+		Operation: op,
+		Time:      now.UTC(),
+		Files:     saved,
+	}, "", "\t")
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", 0, fmt.Errorf("back up files: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, backupInfoFile), append(data, '\n'), 0o666); err != nil {
+		os.RemoveAll(dir)
+		return "", 0, fmt.Errorf("back up files: %w", err)
+	}
+	return id, len(saved), nil
+}
+
+// backupBeforeDiscard saves the files with uncommitted changes to
+// tracked files in a backup and tells the user how to get them back.
+func backupBeforeDiscard(ctx context.Context, cc *cmdContext, op string, pathspecs []git.Pathspec) error {
+	st, err := cc.git.Status(ctx, git.StatusOptions{
+		DisableRenames: true,
+		Pathspecs:      pathspecs,
+	})
+	if err != nil {
+		return fmt.Errorf("back up files: %w", err)
+	}
+	var names []git.TopPath
+	for _, ent := range st {
+		if !ent.Code.IsUntracked() {
+			names = append(names, ent.Name)
+		}
+	}
+	id, n, err := saveBackup(ctx, cc, op, names)
+	if err != nil || id == "" {
+		return err
+	}
+	fmt.Fprintf(cc.stderr, "gg: saved %s to backup %s (recover with 'gg backups restore %s')\n", countFiles(n), id, id)
+	return nil
+}
+
+// copyWorkingFile copies the file or symbolic link at src to dst,
+// creating dst's parent directories as needed. The executable bit of
+// regular files is preserved.
+func copyWorkingFile(dst, src string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o777); err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return os.Symlink(target, dst)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	perm := os.FileMode(0o666)
+	if info.Mode()&0o111 != 0 {
+		perm = 0o777
+	}
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// readBackups returns the backups in dir, newest first.
+func readBackups(dir string) ([]*backup, error) {
+	ents, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read backups: %w", err)
+	}
+	var list []*backup
+	for _, ent := range ents {
+		if !ent.IsDir() {
+			continue
+		}
+		b := &backup{id: ent.Name(), dir: filepath.Join(dir, ent.Name())}
+		data, err := os.ReadFile(filepath.Join(b.dir, backupInfoFile))
+		if err != nil {
+			// Incomplete or not a backup.
+			continue
+		}
+		if err := json.Unmarshal(data, &b.backupInfo); err != nil {
+			return nil, fmt.Errorf("read backup %s: %w", b.id, err)
+		}
+		list = append(list, b)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Time.After(list[j].Time)
+	})
+	return list, nil
+}
+
+// pruneBackups deletes the backups in dir that are older than
+// gg.backupDays.
+func pruneBackups(ctx context.Context, cc *cmdContext, dir string, now time.Time) error {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	days := defaultBackupDays
+	if setting := cfg.Value("gg.backupDays"); setting != "" {
+		days, err = strconv.Atoi(setting)
+		if err != nil || days < 0 {
+			return fmt.Errorf("gg.backupDays: %q is not a number of days", setting)
+		}
+	}
+	if days == 0 {
+		return nil
+	}
+	list, err := readBackups(dir)
+	if err != nil {
+		return err
+	}
+	for _, b := range list {
+		if now.Sub(b.Time) > time.Duration(days)*24*time.Hour {
+			if err := os.RemoveAll(b.dir); err != nil {
+				return fmt.Errorf("prune backups: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func listBackups(ctx context.Context, cc *cmdContext) error {
+	dir, err := backupsDir(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	list, err := readBackups(dir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, b := range list {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", b.id, relativeTime(now, b.Time), b.Operation, countFiles(len(b.Files)))
+	}
+	return tw.Flush()
+}
+
+func restoreBackup(ctx context.Context, cc *cmdContext, id string, args []string, force bool) error {
+	dir, err := backupsDir(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	list, err := readBackups(dir)
+	if err != nil {
+		return err
+	}
+	var b *backup
+	for _, candidate := range list {
+		if candidate.id == id {
+			b = candidate
+			break
+		}
+	}
+	if b == nil {
+		return fmt.Errorf("no backup %q (run 'gg backups' to list them)", id)
+	}
+	top, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	files := b.Files
+	if len(args) > 0 {
+		files = nil
+		for _, arg := range args {
+			name, err := worktreeRelativePath(cc, top, arg)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(b.Files, name.String()) {
+				return fmt.Errorf("%s is not in backup %s", arg, id)
+			}
+			files = append(files, name.String())
+		}
+	}
+	if !force {
+		pathspecs := make([]git.Pathspec, 0, len(files))
+		for _, name := range files {
+			pathspecs = append(pathspecs, git.TopPath(name).Pathspec())
+		}
+		st, err := cc.git.Status(ctx, git.StatusOptions{
+			DisableRenames: true,
+			Pathspecs:      pathspecs,
+		})
+		if err != nil {
+			return err
+		}
+		if len(st) > 0 {
+			var names []string
+			for _, ent := range st {
+				names = append(names, ent.Name.String())
+			}
+			return fmt.Errorf("uncommitted changes would be overwritten in: %s (pass -f to restore anyway)", strings.Join(names, ", "))
+		}
+	}
+	for _, name := range files {
+		src := filepath.Join(b.dir, backupFilesDir, filepath.FromSlash(name))
+		dst := filepath.Join(top, filepath.FromSlash(name))
+		if err := copyWorkingFile(dst, src); err != nil {
+			return fmt.Errorf("restore %s: %w", name, err)
+		}
+	}
+	_, err = fmt.Fprintf(cc.stderr, "gg: restored %s from backup %s\n", countFiles(len(files)), id)
+	return err
+}
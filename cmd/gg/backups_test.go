@@ -0,0 +1,139 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestBackups_RevertAll(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "original\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "precious\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "revert", "--all"); err != nil {
+		t.Fatal(err)
+	}
+	if got := env.stderr.String(); !strings.Contains(got, "gg backups restore") {
+		t.Errorf("gg revert --all stderr = %q; want to mention gg backups restore", got)
+	}
+	if exists, err := env.root.Exists("foo.txt.orig"); err != nil {
+		t.Error(err)
+	} else if exists {
+		t.Error("foo.txt.orig was created")
+	}
+	out, err := env.gg(ctx, env.root.String(), "backups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 || !strings.Contains(string(out), "revert --all") || !strings.Contains(string(out), "1 file") {
+		t.Fatalf("gg backups = %q; want one backup of 1 file from revert --all", out)
+	}
+	id := fields[0]
+
+	if _, err := env.gg(ctx, env.root.String(), "backups", "restore", id); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Error(err)
+	} else if got != "precious\n" {
+		t.Errorf("foo.txt after restore = %q; want %q", got, "precious\n")
+	}
+	// Now that foo.txt has changes again, restoring requires -f.
+	if _, err := env.gg(ctx, env.root.String(), "backups", "restore", id); err == nil {
+		t.Error("restore over uncommitted changes succeeded without -f")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "backups", "restore", "-f", id, "foo.txt"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBackups_UpdateClean(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "original\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "precious\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "update", "--clean"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Fatal(err)
+	} else if got != "original\n" {
+		t.Fatalf("foo.txt after update --clean = %q; want %q", got, "original\n")
+	}
+	dir, err := env.git.GitDir(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := readBackups(filepath.Join(dir, "gg", "backups"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Operation != "update --clean" {
+		t.Fatalf("backups = %+v; want one from update --clean", list)
+	}
+	got, err := os.ReadFile(filepath.Join(list[0].dir, "files", "foo.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "precious\n" {
+		t.Errorf("backed up foo.txt = %q; want %q", got, "precious\n")
+	}
+}
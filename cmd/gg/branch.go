@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
-	"strings"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/pkg/git/object"
@@ -32,7 +31,7 @@ import (
 const branchSynopsis = "list or manage branches"
 
 func branch(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg branch [-d] [-f] [-r REV] [NAME [...]]", branchSynopsis+`
+	f := flag.NewFlagSet(true, "gg branch [-d] [-f [--force-protected]] [--from-template] [-r REV] [NAME [...]]", branchSynopsis+`
 
 	Branches are references to commits to help track lines of
 	development. Branches are unversioned and can be moved, renamed, and
@@ -42,22 +41,53 @@ func branch(ctx context.Context, cc *cmdContext, args []string) error {
 	When a commit is made, the active branch will advance to the new
 	commit. A plain `+"`gg update`"+` will also advance an active branch, if
 	possible. If the revision specifies a branch with an upstream, then
-	any new branch will use the named branch's upstream.`)
+	any new branch will use the named branch's upstream.
+
+	Branch names must follow the rules in git-check-ref-format(1). A new
+	branch can't have the same name as an existing branch apart from
+	case, since the two would conflict on case-insensitive file systems
+	like those on macOS and Windows.
+
+	New branch names can be checked against a regular expression set in
+	`+"`gg.branch.pattern`"+`, which must match the whole name. `+"`${VAR}`"+` in the
+	pattern is replaced with the environment variable, so
+	`+"`user/${USER}/.+`"+` requires branches to start with your user name.
+	`+"`gg.branch.patternDescription`"+` is shown alongside the pattern when a name
+	does not match. If `+"`gg.branch.enforcePattern`"+` is false, a name that does
+	not match produces a warning instead of an error.
+
+	With `+"`--from-template`"+`, each NAME is substituted for `+"`${NAME}`"+` in the
+	`+"`gg.branch.template`"+` setting, like `+"`user/${USER}/${NAME}`"+`, to form the
+	branch name.
+
+	When listing branches, `+"`--json`"+` prints a JSON array with each
+	branch's name, whether it is checked out, and its tip commit (null
+	for a branch with no commits yet). A detached HEAD is listed first
+	without a name.`+protectedBranchHelp)
 	delete := f.Bool("d", false, "delete the given branches")
+	fromTemplate := f.Bool("from-template", false, "form branch names by substituting each NAME into gg.branch.template")
 	f.Alias("d", "delete")
 	force := f.Bool("f", false, "force")
 	f.Alias("f", "force")
+	forceProtected := f.Bool("force-protected", false, forceProtectedUsage)
 	rev := f.String("r", "", "`rev`ision to place branches on")
 	pattern := f.Regexp("p", "`regexp` of branches to list (can be specified multiple times)")
 	f.Alias("p", "pattern")
 	ord := branchSortOrder{key: branchSortDate, dir: descending}
 	f.Var(&ord, "sort", "sort `order` when listing: 'name' or 'date'. May be prefixed by '-' for descending.")
+	jsonOutput := f.Bool("json", false, "print a JSON array instead of a list of branches")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
+	if *forceProtected && !*force {
+		return usagef("--force-protected requires -f")
+	}
+	if *jsonOutput && (*delete || f.NArg() > 0) {
+		return usagef("--json can only be used when listing branches")
+	}
 	switch {
 	case *delete:
 		if f.NArg() == 0 {
@@ -66,6 +96,9 @@ func branch(ctx context.Context, cc *cmdContext, args []string) error {
 		if *rev != "" {
 			return usagef("can't pass -r for delete")
 		}
+		if *fromTemplate {
+			return usagef("can't pass --from-template for delete")
+		}
 		return deleteBranches(ctx, cc.git, f.Args(), *force)
 	case f.NArg() == 0:
 		// List
@@ -75,17 +108,49 @@ func branch(ctx context.Context, cc *cmdContext, args []string) error {
 		if *rev != "" {
 			return usagef("can't pass -r without branch names")
 		}
-		return listBranches(ctx, cc, *pattern, ord)
+		if *fromTemplate {
+			return usagef("can't pass --from-template without branch names")
+		}
+		return listBranches(ctx, cc, *pattern, ord, *jsonOutput)
 	default:
 		// Create or update
-		for _, b := range f.Args() {
-			if strings.HasPrefix(b, "-") {
-				return fmt.Errorf("invalid branch name %q", b)
+		cfg, err := cc.git.ReadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		policy, err := readBranchNamePolicy(cfg, cc.env)
+		if err != nil {
+			return err
+		}
+		protected, err := readProtectedBranches(cfg)
+		if err != nil {
+			return err
+		}
+		names := f.Args()
+		if *fromTemplate {
+			names = make([]string, 0, f.NArg())
+			for _, arg := range f.Args() {
+				b, err := policy.fromTemplate(arg)
+				if err != nil {
+					return err
+				}
+				names = append(names, b)
 			}
 		}
+		for _, b := range names {
+			if err := checkBranchName(ctx, cc.git, b); err != nil {
+				return err
+			}
+		}
+		if err := checkNewBranchNames(ctx, cc, policy, names); err != nil {
+			return err
+		}
 		target := git.Head.String()
 		if *rev != "" {
-			target = *rev
+			target, err = resolveBranchCase(ctx, cc.git, cfg, *rev)
+			if err != nil {
+				return err
+			}
 		}
 		r, err := cc.git.ParseRev(ctx, target)
 		if err != nil {
@@ -93,10 +158,6 @@ func branch(ctx context.Context, cc *cmdContext, args []string) error {
 		}
 		var upstream string
 		if b := r.Ref.Branch(); b != "" {
-			cfg, err := cc.git.ReadConfig(ctx)
-			if err != nil {
-				return err
-			}
 			upstream = branchUpstream(cfg, b)
 		}
 		var upstreamArgs []string
@@ -105,7 +166,14 @@ func branch(ctx context.Context, cc *cmdContext, args []string) error {
 			// instead of relying on the default tracking branch pattern.
 			upstreamArgs = append(upstreamArgs, "branch", "--quiet", "--set-upstream-to="+upstream, "--", "XXX")
 		}
-		for i, b := range f.Args() {
+		if *force && !*forceProtected {
+			for _, b := range names {
+				if err := checkMoveProtectedBranch(ctx, cc.git, protected, b, r.Commit); err != nil {
+					return err
+				}
+			}
+		}
+		for i, b := range names {
 			exists := false
 			if len(upstreamArgs) > 0 && *force {
 				// This check for existence is only necessary during -force,
@@ -134,7 +202,34 @@ func branch(ctx context.Context, cc *cmdContext, args []string) error {
 	return nil
 }
 
-func listBranches(ctx context.Context, cc *cmdContext, pattern *regexp.Regexp, ord branchSortOrder) error {
+// checkNewBranchNames verifies that the names of any branches that
+// don't exist yet match gg.branch.pattern and don't differ only in case
+// from an existing branch. Existing branches can be moved regardless of
+// their names.
+func checkNewBranchNames(ctx context.Context, cc *cmdContext, policy *branchNamePolicy, names []string) error {
+	for _, b := range names {
+		if _, err := cc.git.ParseRev(ctx, git.BranchRef(b).String()); err == nil {
+			continue
+		}
+		if other, err := branchCaseConflict(ctx, cc.git, b); err != nil {
+			return err
+		} else if other != "" {
+			return fmt.Errorf("branch %q differs only in case from existing branch %q, so they can't coexist on case-insensitive file systems like those on macOS and Windows", b, other)
+		}
+		if policy.pattern == nil {
+			continue
+		}
+		if err := policy.check(b); err != nil {
+			if policy.enforce {
+				return err
+			}
+			fmt.Fprintf(cc.stderr, "gg: warning: %v\n", err)
+		}
+	}
+	return nil
+}
+
+func listBranches(ctx context.Context, cc *cmdContext, pattern *regexp.Regexp, ord branchSortOrder, jsonOutput bool) error {
 	// Get color settings. Most errors can be ignored without impacting
 	// the command output.
 	var (
@@ -203,16 +298,58 @@ func listBranches(ctx context.Context, cc *cmdContext, pattern *regexp.Regexp, o
 	default:
 		panic("unknown sort order")
 	}
+	if jsonOutput {
+		return writeBranchesJSON(ctx, cc, headRef, pattern, branches, refs, commits)
+	}
 
 	if colorize {
 		if err := terminal.ResetTextStyle(cc.stdout); err != nil {
 			return err
 		}
 	}
-	for i, b := range branches {
-		if i > 0 {
+	printed := false
+	if headRef == "" && pattern == nil {
+		// HEAD is detached: show it first, since no branch is current.
+		head, err := cc.git.Head(ctx)
+		var commit *object.Commit
+		if err == nil {
+			commit, err = cc.git.CommitInfo(ctx, head.Commit.String())
+		}
+		if err == nil {
+			_, err := fmt.Fprintf(cc.stdout, "%s* %-30s %s %s\n    %s\n", currentColor, "(detached HEAD)", head.Commit.Short(), commit.Author.Name(), commit.Summary())
+			if err != nil {
+				return err
+			}
+			if colorize {
+				if err := terminal.ResetTextStyle(cc.stdout); err != nil {
+					return err
+				}
+			}
+			printed = true
+		}
+	}
+	if _, hasCommits := refs[headRef]; headRef.IsBranch() && !hasCommits &&
+		(pattern == nil || pattern.MatchString(headRef.Branch())) {
+		// HEAD is on an unborn branch, which git branch omits. Show it
+		// so that the current branch is never missing from the list.
+		if _, err := cc.git.Head(ctx); err != nil {
+			_, err := fmt.Fprintf(cc.stdout, "%s* %-30s (no commits yet)\n", currentColor, headRef.Branch())
+			if err != nil {
+				return err
+			}
+			if colorize {
+				if err := terminal.ResetTextStyle(cc.stdout); err != nil {
+					return err
+				}
+			}
+			printed = true
+		}
+	}
+	for _, b := range branches {
+		if printed {
 			fmt.Fprintln(cc.stdout)
 		}
+		printed = true
 		color, marker := localColor, ' '
 		if headRef == b {
 			color, marker = currentColor, '*'
@@ -231,6 +368,41 @@ func listBranches(ctx context.Context, cc *cmdContext, pattern *regexp.Regexp, o
 	return nil
 }
 
+// A branchJSON is an element of the output of gg branch --json.
+type branchJSON struct {
+	Name    string      `json:"name,omitempty"` // empty for a detached HEAD
+	Current bool        `json:"current,omitempty"`
+	Commit  *commitJSON `json:"commit"` // nil if the branch has no commits yet
+}
+
+// writeBranchesJSON prints the branches listed by listBranches as JSON.
+func writeBranchesJSON(ctx context.Context, cc *cmdContext, headRef git.Ref, pattern *regexp.Regexp, branches []git.Ref, refs map[git.Ref]git.Hash, commits map[git.Hash]*object.Commit) error {
+	list := make([]*branchJSON, 0, len(branches)+1)
+	_, headHasCommits := refs[headRef]
+	switch {
+	case headRef == "" && pattern == nil:
+		if head, err := cc.git.Head(ctx); err == nil {
+			commit, err := cc.git.CommitInfo(ctx, head.Commit.String())
+			if err != nil {
+				return err
+			}
+			list = append(list, &branchJSON{Current: true, Commit: newCommitJSON(commit, false)})
+		}
+	case headRef.IsBranch() && !headHasCommits && (pattern == nil || pattern.MatchString(headRef.Branch())):
+		if _, err := cc.git.Head(ctx); err != nil {
+			list = append(list, &branchJSON{Name: headRef.Branch(), Current: true})
+		}
+	}
+	for _, b := range branches {
+		list = append(list, &branchJSON{
+			Name:    b.Branch(),
+			Current: b == headRef,
+			Commit:  newCommitJSON(commits[refs[b]], false),
+		})
+	}
+	return writeJSON(cc.stdout, list)
+}
+
 func refsCommitInfo(ctx context.Context, g *git.Git, refs map[git.Ref]git.Hash) (map[git.Hash]*object.Commit, error) {
 	if len(refs) == 0 {
 		return nil, nil
@@ -16,6 +16,8 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
@@ -230,7 +232,175 @@ func TestBranch_ListNewRepo(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	if len(out) > 0 {
-		t.Errorf("stdout = %q; want \"\"", out)
+	if got, want := string(out), "* main                           (no commits yet)\n"; got != want {
+		t.Errorf("stdout = %q; want %q", got, want)
+	}
+}
+
+func TestBranch_Pattern(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.writeConfig([]byte("[gg \"branch\"]\n" +
+		"pattern = user/${USER}/.+\n" +
+		"patternDescription = branches must start with user/NAME/\n" +
+		"template = user/${USER}/${NAME}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.environ = []string{"USER=alice"}
+
+	_, err = env.gg(ctx, env.root.String(), "branch", "foo")
+	if err == nil {
+		t.Fatal("gg branch foo succeeded with a name that does not match gg.branch.pattern")
+	}
+	for _, want := range []string{"gg.branch.pattern", "branches must start with user/NAME/", `"user/alice/foo"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("gg branch foo error = %q; want to contain %q", err, want)
+		}
+	}
+	if _, err := env.git.ParseRev(ctx, "refs/heads/foo"); err == nil {
+		t.Error("branch foo was created")
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "branch", "user/alice/bar"); err != nil {
+		t.Error(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "branch", "--from-template", "baz"); err != nil {
+		t.Error(err)
+	}
+	if r, err := env.git.Head(ctx); err != nil {
+		t.Error(err)
+	} else if r.Ref != "refs/heads/user/alice/baz" {
+		t.Errorf("HEAD refname = %q; want refs/heads/user/alice/baz", r.Ref)
+	}
+	// Existing branches can be moved regardless of their names.
+	if _, err := env.gg(ctx, env.root.String(), "branch", "-f", "main"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBranch_PatternWarning(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.writeConfig([]byte("[gg \"branch\"]\n" +
+		"pattern = [A-Z]+-[0-9]+-.+\n" +
+		"enforcePattern = false\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "branch", "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.git.ParseRev(ctx, "refs/heads/foo"); err != nil {
+		t.Error("branch foo was not created:", err)
+	}
+	if got, want := env.stderr.String(), `warning: branch name "foo" does not match gg.branch.pattern`; !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+}
+
+func TestBranch_DetachedHead(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "checkout", "--quiet", "--detach", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	detached, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "branch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "* (detached HEAD)") || !strings.Contains(string(out), detached.Short()) {
+		t.Errorf("gg branch output = %q; want to start with detached HEAD at %s", out, detached.Short())
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "branch", "keep"); err != nil {
+		t.Fatal(err)
+	}
+	curr, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if curr.Commit != detached || curr.Ref != git.BranchRef("keep") {
+		t.Errorf("HEAD = %v (%s); want %v (refs/heads/keep)", curr.Commit, curr.Ref, detached)
+	}
+}
+
+func TestBranch_JSON(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "feature", git.BranchOptions{StartPoint: "HEAD~"}); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := env.git.ParseRev(ctx, "HEAD~")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "branch", "--json", "--sort=name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*branchJSON
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	if len(got) != 2 {
+		t.Fatalf("gg branch --json returned %d branches; want 2. Output:\n%s", len(got), out)
+	}
+	if got[0].Name != "feature" || got[0].Current || got[0].Commit == nil || got[0].Commit.Commit != parent.Commit.String() {
+		t.Errorf("branch[0] = %+v; want feature at %v", got[0], parent.Commit)
+	}
+	if got[1].Name != "main" || !got[1].Current || got[1].Commit == nil || got[1].Commit.Commit != head.Commit.String() {
+		t.Errorf("branch[1] = %+v; want current main at %v", got[1], head.Commit)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "branch", "--json", "foo"); err == nil {
+		t.Error("gg branch --json foo did not return an error")
+	} else if !isUsage(err) {
+		t.Errorf("gg branch --json foo returned non-usage error: %v", err)
 	}
 }
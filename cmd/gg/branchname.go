@@ -0,0 +1,325 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// branchNamePolicy is the set of rules for naming new branches,
+// read from the gg.branch.* configuration variables.
+type branchNamePolicy struct {
+	// template is the value of gg.branch.template, a branch name in which
+	// ${NAME} is replaced with the name given on the command line and
+	// other ${VAR}s are replaced with environment variables.
+	template string
+
+	// pattern is the compiled value of gg.branch.pattern, or nil if the
+	// setting is not present. patternText is the setting after
+	// environment variable expansion.
+	pattern     *regexp.Regexp
+	patternText string
+
+	// description is the value of gg.branch.patternDescription,
+	// a human-readable explanation of the pattern.
+	description string
+
+	// enforce is false if new branch names that don't match pattern
+	// should only produce a warning.
+	enforce bool
+
+	environ []string
+}
+
+func readBranchNamePolicy(cfg *git.Config, environ []string) (*branchNamePolicy, error) {
+	policy := &branchNamePolicy{
+		template:    cfg.Value("gg.branch.template"),
+		description: cfg.Value("gg.branch.patternDescription"),
+		enforce:     true,
+		environ:     environ,
+	}
+	if cfg.Value("gg.branch.enforcePattern") != "" {
+		var err error
+		policy.enforce, err = cfg.Bool("gg.branch.enforcePattern")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pat := cfg.Value("gg.branch.pattern"); pat != "" {
+		var err error
+		policy.patternText, err = policy.expand(pat, "", regexp.QuoteMeta)
+		if err != nil {
+			return nil, fmt.Errorf("gg.branch.pattern: %w", err)
+		}
+		policy.pattern, err = regexp.Compile(`^(?:` + policy.patternText + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("gg.branch.pattern: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+// expand replaces ${VAR} and $VAR in s with the value of the
+// environment variable, passed through quote. ${NAME} is replaced with
+// name if name is not empty.
+func (policy *branchNamePolicy) expand(s string, name string, quote func(string) string) (string, error) {
+	var missing []string
+	result := os.Expand(s, func(v string) string {
+		if v == "NAME" && name != "" {
+			return quote(name)
+		}
+		value := getenv(policy.environ, v)
+		if value == "" {
+			missing = append(missing, v)
+		}
+		return quote(value)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("$%s is not set", missing[0])
+	}
+	return result, nil
+}
+
+// fromTemplate returns the branch name produced by substituting name
+// into gg.branch.template.
+func (policy *branchNamePolicy) fromTemplate(name string) (string, error) {
+	if policy.template == "" {
+		return "", errors.New("gg.branch.template is not set")
+	}
+	result, err := policy.expand(policy.template, name, func(v string) string { return v })
+	if err != nil {
+		return "", fmt.Errorf("gg.branch.template: %w", err)
+	}
+	return result, nil
+}
+
+// check returns an error if name does not match gg.branch.pattern.
+func (policy *branchNamePolicy) check(name string) error {
+	if policy.pattern == nil || policy.pattern.MatchString(name) {
+		return nil
+	}
+	msg := new(strings.Builder)
+	fmt.Fprintf(msg, "branch name %q does not match gg.branch.pattern %q", name, policy.patternText)
+	if policy.description != "" {
+		fmt.Fprintf(msg, " (%s)", policy.description)
+	}
+	if suggestion, err := policy.fromTemplate(name); err == nil && policy.pattern.MatchString(suggestion) {
+		fmt.Fprintf(msg, "; did you mean %q? Pass --from-template to use it", suggestion)
+	}
+	return errors.New(msg.String())
+}
+
+// checkBranchName verifies that name is a valid branch name, as defined
+// by git-check-ref-format(1). The error explains which rule the name
+// breaks and suggests a valid name if one can be formed.
+func checkBranchName(ctx context.Context, g *git.Git, name string) error {
+	problem := branchNameProblem(name)
+	if problem == "" {
+		// Git has the final word, since its rules can change.
+		if g.Run(ctx, "check-ref-format", "--branch", name) == nil {
+			return nil
+		}
+		problem = "not allowed by git-check-ref-format(1)"
+	}
+	msg := fmt.Sprintf("invalid branch name %q: %s", name, problem)
+	if suggestion := sanitizeBranchName(name); suggestion != "" && branchNameProblem(suggestion) == "" &&
+		g.Run(ctx, "check-ref-format", "--branch", suggestion) == nil {
+		msg += fmt.Sprintf(" (try %q)", suggestion)
+	}
+	return errors.New(msg)
+}
+
+// branchNameProblem returns a description of the first rule from
+// git-check-ref-format(1) that name breaks or the empty string if it
+// follows all of them.
+func branchNameProblem(name string) string {
+	switch {
+	case name == "":
+		return "name is empty"
+	case name == "@":
+		return `can't be "@"`
+	case name == "HEAD":
+		return `can't be "HEAD"`
+	case strings.HasPrefix(name, "-"):
+		return `can't start with "-"`
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/"):
+		return `can't start or end with "/"`
+	case strings.Contains(name, "//"):
+		return `can't contain "//"`
+	case strings.HasSuffix(name, "."):
+		return `can't end with "."`
+	case strings.Contains(name, ".."):
+		return `can't contain ".."`
+	case strings.Contains(name, "@{"):
+		return `can't contain "@{"`
+	}
+	for _, c := range name {
+		switch {
+		case c == ' ':
+			return "can't contain spaces"
+		case c < ' ' || c == 0x7f:
+			return "can't contain control characters"
+		case strings.ContainsRune(`~^:?*[\`, c):
+			return fmt.Sprintf("can't contain %q", c)
+		}
+	}
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			return `components can't start with "."`
+		}
+		if strings.HasSuffix(part, ".lock") {
+			return `components can't end with ".lock"`
+		}
+	}
+	return ""
+}
+
+// sanitizeBranchName returns name with the characters and sequences that
+// branch names can't contain replaced or removed. It returns the empty
+// string if nothing is left.
+func sanitizeBranchName(name string) string {
+	name = strings.Map(func(c rune) rune {
+		switch {
+		case c <= ' ' || c == 0x7f:
+			return '-'
+		case strings.ContainsRune(`~^:?*[\`, c):
+			return '-'
+		default:
+			return c
+		}
+	}, name)
+	name = strings.ReplaceAll(name, "@{", "@-")
+	for strings.Contains(name, "..") {
+		name = strings.ReplaceAll(name, "..", ".")
+	}
+	var parts []string
+	for _, part := range strings.Split(name, "/") {
+		for strings.HasSuffix(part, ".lock") {
+			part = strings.TrimSuffix(part, ".lock")
+		}
+		part = strings.TrimLeft(part, ".")
+		part = strings.TrimRight(part, ".")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	name = strings.TrimLeft(strings.Join(parts, "/"), "-")
+	if name == "@" || name == "HEAD" {
+		return ""
+	}
+	return name
+}
+
+// branchCaseConflict returns the name of an existing branch that differs
+// from name only in case or the empty string if there is none. Such
+// branches can't both be checked out on case-insensitive file systems,
+// like the defaults on macOS and Windows, since Git may store them as
+// files with the same name.
+func branchCaseConflict(ctx context.Context, g *git.Git, name string) (string, error) {
+	branches, err := branchNames(ctx, g)
+	if err != nil {
+		return "", err
+	}
+	for _, b := range branches {
+		if b != name && strings.EqualFold(b, name) {
+			return b, nil
+		}
+	}
+	return "", nil
+}
+
+// checkNewRemoteBranch verifies that dst is a valid branch name and
+// doesn't differ only in case from a branch in remoteRefs, unless dst
+// already exists on the remote or isn't a branch.
+func checkNewRemoteBranch(ctx context.Context, g *git.Git, dst git.Ref, remoteRefs map[git.Ref]git.Hash) error {
+	if _, exists := remoteRefs[dst]; exists || !dst.IsBranch() {
+		return nil
+	}
+	if err := checkBranchName(ctx, g, dst.Branch()); err != nil {
+		return err
+	}
+	for other := range remoteRefs {
+		if other.IsBranch() && strings.EqualFold(other.Branch(), dst.Branch()) {
+			return fmt.Errorf("branch %q differs only in case from %q on the remote, so they can't coexist in clones on case-insensitive file systems like those on macOS and Windows", dst.Branch(), other.Branch())
+		}
+	}
+	return nil
+}
+
+// resolveBranchCase returns the name of the branch that rev refers to
+// if rev is not a valid revision, but it matches exactly one branch name
+// when compared without regard to case. Otherwise, it returns rev
+// unchanged. It only matches when core.ignoreCase is set, as Git does
+// for repositories on case-insensitive file systems, so that names can
+// be typed with the same leniency as file names.
+func resolveBranchCase(ctx context.Context, g *git.Git, cfg *git.Config, rev string) (string, error) {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return rev, nil
+	}
+	if ignoreCase, _ := cfg.Bool("core.ignoreCase"); !ignoreCase {
+		return rev, nil
+	}
+	if _, err := g.ParseRev(ctx, rev); err == nil {
+		return rev, nil
+	}
+	name := strings.TrimPrefix(rev, "refs/heads/")
+	branches, err := branchNames(ctx, g)
+	if err != nil {
+		return "", err
+	}
+	var match string
+	for _, b := range branches {
+		if strings.EqualFold(b, name) {
+			if match != "" {
+				// Ambiguous: let the caller report that rev is unknown.
+				return rev, nil
+			}
+			match = b
+		}
+	}
+	if match == "" {
+		return rev, nil
+	}
+	if name != rev {
+		return git.BranchRef(match).String(), nil
+	}
+	return match, nil
+}
+
+// branchNames returns the names of the local branches.
+func branchNames(ctx context.Context, g *git.Git) ([]string, error) {
+	iter := g.IterateRefs(ctx, git.IterateRefsOptions{
+		LimitToBranches: true,
+	})
+	var names []string
+	for iter.Next() {
+		if b := iter.Ref().Branch(); b != "" {
+			names = append(names, b)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
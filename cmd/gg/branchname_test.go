@@ -0,0 +1,168 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBranchNameProblem(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "main", valid: true},
+		{name: "user/alice/fix-1.2", valid: true},
+		{name: "Feature", valid: true},
+		{name: "", valid: false},
+		{name: "@", valid: false},
+		{name: "-x", valid: false},
+		{name: "foo bar", valid: false},
+		{name: "foo..bar", valid: false},
+		{name: "foo/", valid: false},
+		{name: "/foo", valid: false},
+		{name: "foo//bar", valid: false},
+		{name: "foo.", valid: false},
+		{name: "foo@{1}", valid: false},
+		{name: "foo~1", valid: false},
+		{name: "foo^", valid: false},
+		{name: "a:b", valid: false},
+		{name: "what?", valid: false},
+		{name: "a*", valid: false},
+		{name: "[x]", valid: false},
+		{name: `a\b`, valid: false},
+		{name: "tab\there", valid: false},
+		{name: ".hidden", valid: false},
+		{name: "foo/.bar", valid: false},
+		{name: "foo.lock", valid: false},
+		{name: "foo.lock/bar", valid: false},
+	}
+	for _, test := range tests {
+		problem := branchNameProblem(test.name)
+		if got := problem == ""; got != test.valid {
+			t.Errorf("branchNameProblem(%q) = %q; want valid = %t", test.name, problem, test.valid)
+		}
+	}
+}
+
+func TestSanitizeBranchName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "main", want: "main"},
+		{name: "fix the bug", want: "fix-the-bug"},
+		{name: "foo..bar", want: "foo.bar"},
+		{name: "foo/", want: "foo"},
+		{name: "foo//bar", want: "foo/bar"},
+		{name: ".hidden/x.lock", want: "hidden/x"},
+		{name: "what?", want: "what-"},
+		{name: "-x", want: "x"},
+		{name: "foo@{1}", want: "foo@-1}"},
+		{name: "...", want: ""},
+	}
+	for _, test := range tests {
+		got := sanitizeBranchName(test.name)
+		if got != test.want {
+			t.Errorf("sanitizeBranchName(%q) = %q; want %q", test.name, got, test.want)
+		}
+		if got != "" && branchNameProblem(got) != "" {
+			t.Errorf("sanitizeBranchName(%q) = %q, which is invalid: %s", test.name, got, branchNameProblem(got))
+		}
+	}
+}
+
+func TestBranch_InvalidName(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, env.root.String(), "branch", "fix the bug")
+	if err == nil {
+		t.Fatal("gg branch \"fix the bug\" succeeded")
+	}
+	if got, want := err.Error(), `(try "fix-the-bug")`; !strings.Contains(got, want) {
+		t.Errorf("error = %q; want it to contain %q", got, want)
+	}
+	if _, err := env.git.ParseRev(ctx, "refs/heads/fix the bug"); err == nil {
+		t.Error("branch was created")
+	}
+}
+
+func TestBranch_CaseConflict(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "branch", "Feature"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, env.root.String(), "branch", "feature")
+	if err == nil {
+		t.Fatal("gg branch feature succeeded")
+	}
+	if got, want := err.Error(), `"Feature"`; !strings.Contains(got, want) {
+		t.Errorf("error = %q; want it to mention %s", got, want)
+	}
+	if _, err := env.git.ParseRev(ctx, "refs/heads/feature"); err == nil {
+		t.Error("branch was created")
+	}
+}
+
+func TestUpdate_IgnoreCase(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "branch", "Feature/Foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "update", "feature/foo"); err == nil {
+		t.Error("gg update feature/foo succeeded without core.ignoreCase")
+	}
+	if err := env.git.Run(ctx, "config", "core.ignoreCase", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "update", "feature/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := env.git.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if r.Ref != "refs/heads/Feature/Foo" {
+		t.Errorf("HEAD refname = %q; want refs/heads/Feature/Foo", r.Ref)
+	}
+}
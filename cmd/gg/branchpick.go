@@ -0,0 +1,335 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gg-scm.io/pkg/git"
+)
+
+// A recentBranch is a local branch and the last time HEAD pointed to it.
+// LastUsed is the zero time for a branch that HEAD has not pointed to
+// since the reflog began.
+type recentBranch struct {
+	Name     string
+	Tip      git.Hash
+	LastUsed time.Time
+}
+
+// recentBranches returns the repository's local branches, most recently
+// checked out first. The current branch is always first. Branches that
+// do not appear in the HEAD reflog come last, sorted by name.
+func recentBranches(ctx context.Context, g *git.Git) ([]*recentBranch, error) {
+	headRef, err := g.HeadRef(ctx)
+	if err != nil {
+		return nil, err
+	}
+	branches := make(map[string]*recentBranch)
+	iter := g.IterateRefs(ctx, git.IterateRefsOptions{
+		LimitToBranches: true,
+	})
+	for iter.Next() {
+		if name := iter.Ref().Branch(); name != "" {
+			branches[name] = &recentBranch{Name: name, Tip: iter.ObjectSHA1()}
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	gitDir, err := g.GitDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var uses []branchUse
+	if _, err := os.Stat(filepath.Join(gitDir, "logs", "HEAD")); err == nil {
+		out, err := g.Output(ctx, "log", "--walk-reflogs", "--date=unix", "--format=%gd%x00%gs", git.Head.String(), "--")
+		if err != nil {
+			return nil, err
+		}
+		uses = parseHeadReflog(out)
+	}
+	for _, u := range uses {
+		if b := branches[u.branch]; b != nil && b.LastUsed.IsZero() {
+			b.LastUsed = u.time
+		}
+	}
+
+	list := make([]*recentBranch, 0, len(branches))
+	for _, b := range branches {
+		list = append(list, b)
+	}
+	current := headRef.Branch()
+	sort.Slice(list, func(i, j int) bool {
+		bi, bj := list[i], list[j]
+		switch {
+		case bi.Name == current || bj.Name == current:
+			return bi.Name == current
+		case !bi.LastUsed.Equal(bj.LastUsed):
+			return bi.LastUsed.After(bj.LastUsed)
+		default:
+			return bi.Name < bj.Name
+		}
+	})
+	return list, nil
+}
+
+// A branchUse is a time at which HEAD pointed to a branch.
+type branchUse struct {
+	branch string
+	time   time.Time
+}
+
+// parseHeadReflog returns the branch checkouts recorded in the output of
+// git log --walk-reflogs --date=unix --format=%gd%x00%gs, newest first.
+// A checkout that moves from one branch to another means that HEAD
+// pointed to both branches at that time.
+func parseHeadReflog(out string) []branchUse {
+	var uses []branchUse
+	for _, line := range strings.Split(out, "\n") {
+		selector, subject, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		// The selector is like "HEAD@{1700000000}".
+		_, stamp, ok := strings.Cut(selector, "@{")
+		if !ok {
+			continue
+		}
+		sec, err := strconv.ParseInt(strings.TrimSuffix(stamp, "}"), 10, 64)
+		if err != nil {
+			continue
+		}
+		moves, ok := strings.CutPrefix(subject, "checkout: moving from ")
+		if !ok {
+			continue
+		}
+		from, to, ok := strings.Cut(moves, " to ")
+		if !ok {
+			continue
+		}
+		t := time.Unix(sec, 0)
+		uses = append(uses, branchUse{to, t}, branchUse{from, t})
+	}
+	return uses
+}
+
+// branchPickerHelp is the paragraph of update's help text that describes
+// --pick.
+const branchPickerHelp = `
+
+	With ` + "`--pick`" + `, gg lists the local branches with the most recently
+	checked out first and updates to the one chosen. Typing filters the
+	list to branches that contain the typed characters in order. Use the
+	arrow keys to move, enter to choose, and esc to cancel. If the
+	` + "`update-pick`" + ` setting is on (see ` + "`gg config`" + `), running gg update with
+	no revision in a terminal does the same.`
+
+// branchPickerModel is the state of the branch picker's screen,
+// independent of the terminal.
+type branchPickerModel struct {
+	branches []*recentBranch
+	now      time.Time
+	query    string
+	matches  []*recentBranch
+	cursor   int // index into matches
+	top      int // index of the first match on screen
+}
+
+func newBranchPickerModel(branches []*recentBranch, now time.Time) *branchPickerModel {
+	m := &branchPickerModel{branches: branches, now: now}
+	m.filter()
+	return m
+}
+
+// filter recomputes m.matches after the query changes.
+func (m *branchPickerModel) filter() {
+	m.matches = m.matches[:0]
+	for _, b := range m.branches {
+		if fuzzyMatch(b.Name, m.query) {
+			m.matches = append(m.matches, b)
+		}
+	}
+	m.cursor = 0
+	m.top = 0
+}
+
+// fuzzyMatch reports whether the characters of query appear in s in
+// order, ignoring case.
+func fuzzyMatch(s, query string) bool {
+	rest := []rune(query)
+	for _, c := range s {
+		if len(rest) == 0 {
+			break
+		}
+		if unicode.ToLower(c) == unicode.ToLower(rest[0]) {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
+
+// handleKey updates the model for a key press. It returns the chosen
+// branch and true once the user has made a choice, or nil and true if
+// the user canceled.
+func (m *branchPickerModel) handleKey(key, text string) (_ *recentBranch, done bool) {
+	switch key {
+	case keyEscape, keyInterrupt, keyEOF:
+		return nil, true
+	case keyEnter:
+		if len(m.matches) == 0 {
+			return nil, false
+		}
+		return m.matches[m.cursor], true
+	case keyDown:
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+	case keyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case keyBackspace:
+		if m.query != "" {
+			runes := []rune(m.query)
+			m.query = string(runes[:len(runes)-1])
+			m.filter()
+		}
+	default:
+		if text != "" && !strings.ContainsAny(text, "\n\t") {
+			m.query += text
+			m.filter()
+		}
+	}
+	return nil, false
+}
+
+// render returns the lines to display on a screen of the given size,
+// including terminal escape sequences for styles.
+func (m *branchPickerModel) render(width, height int) []string {
+	if height < 3 {
+		return nil
+	}
+	lines := []string{fitWidth("update to: "+m.query, width)}
+	pageSize := height - 2
+	if m.cursor < m.top {
+		m.top = m.cursor
+	} else if m.cursor >= m.top+pageSize {
+		m.top = m.cursor - pageSize + 1
+	}
+	for i := m.top; i < len(m.matches) && i < m.top+pageSize; i++ {
+		b := m.matches[i]
+		line := b.Name
+		if !b.LastUsed.IsZero() {
+			line += "  (" + relativeTime(m.now, b.LastUsed) + ")"
+		}
+		if i == m.cursor {
+			lines = append(lines, highlightLine(line, width))
+		} else {
+			lines = append(lines, fitWidth(line, width))
+		}
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	status := fmt.Sprintf("%d of %d branches  type to filter  enter update  esc cancel", len(m.matches), len(m.branches))
+	return append(lines, highlightLine(status, width))
+}
+
+// relativeTime formats the time t relative to now, like "3 hours ago".
+func relativeTime(now, t time.Time) string {
+	d := now.Sub(t)
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return "1 " + unit + " ago"
+		}
+		return strconv.Itoa(n) + " " + unit + "s ago"
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		return plural(int(d/(24*time.Hour)), "day")
+	case d < 365*24*time.Hour:
+		return plural(int(d/(30*24*time.Hour)), "month")
+	default:
+		return plural(int(d/(365*24*time.Hour)), "year")
+	}
+}
+
+// pickBranch shows the branch picker and returns the chosen branch name.
+func pickBranch(ctx context.Context, cc *cmdContext) (string, error) {
+	screen, err := newTUIScreen(cc)
+	if err != nil {
+		return "", fmt.Errorf("--pick %w", err)
+	}
+	branches, err := recentBranches(ctx, cc.git)
+	if err != nil {
+		return "", err
+	}
+	if len(branches) == 0 {
+		return "", errors.New("no branches to pick from")
+	}
+	m := newBranchPickerModel(branches, time.Now())
+	if err := screen.start(); err != nil {
+		return "", err
+	}
+	choice, err := runBranchPicker(screen, m)
+	if stopErr := screen.stop(); err == nil {
+		err = stopErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if choice == nil {
+		return "", errors.New("update canceled")
+	}
+	return choice.Name, nil
+}
+
+func runBranchPicker(screen *tuiScreen, m *branchPickerModel) (*recentBranch, error) {
+	for {
+		width, height, err := screen.size()
+		if err != nil {
+			return nil, err
+		}
+		if err := screen.draw(m.render(width, height)); err != nil {
+			return nil, err
+		}
+		key, text, err := screen.readKey()
+		if err != nil {
+			return nil, err
+		}
+		if choice, done := m.handleKey(key, text); done {
+			return choice, nil
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseHeadReflog(t *testing.T) {
+	out := "HEAD@{1700000300}\x00checkout: moving from feature to main\n" +
+		"HEAD@{1700000200}\x00commit: Add feature\n" +
+		"HEAD@{1700000100}\x00checkout: moving from main to feature\n" +
+		"HEAD@{1700000000}\x00commit (initial): First\n"
+	got := parseHeadReflog(out)
+	want := []branchUse{
+		{"main", time.Unix(1700000300, 0)},
+		{"feature", time.Unix(1700000300, 0)},
+		{"feature", time.Unix(1700000100, 0)},
+		{"main", time.Unix(1700000100, 0)},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(branchUse{})); diff != "" {
+		t.Errorf("parseHeadReflog(...) (-want +got):\n%s", diff)
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		s, query string
+		want     bool
+	}{
+		{"feature/login", "", true},
+		{"feature/login", "flog", true},
+		{"feature/login", "FLOG", true},
+		{"feature/login", "golf", false},
+		{"main", "mainline", false},
+	}
+	for _, test := range tests {
+		if got := fuzzyMatch(test.s, test.query); got != test.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %t; want %t", test.s, test.query, got, test.want)
+		}
+	}
+}
+
+func TestBranchPickerModel(t *testing.T) {
+	now := time.Date(2026, time.January, 2, 12, 0, 0, 0, time.UTC)
+	branches := []*recentBranch{
+		{Name: "main", LastUsed: now},
+		{Name: "feature/login", LastUsed: now.Add(-3 * time.Hour)},
+		{Name: "fix-typo", LastUsed: now.Add(-48 * time.Hour)},
+		{Name: "old"},
+	}
+	m := newBranchPickerModel(branches, now)
+	if len(m.matches) != len(branches) {
+		t.Fatalf("%d matches before typing; want %d", len(m.matches), len(branches))
+	}
+	for _, key := range []string{"f", "i"} {
+		if choice, done := m.handleKey(key, key); done {
+			t.Fatalf("typing %q finished with %v", key, choice)
+		}
+	}
+	if got, want := matchNames(m), []string{"feature/login", "fix-typo"}; !cmp.Equal(got, want) {
+		t.Errorf("matches for %q = %q; want %q", m.query, got, want)
+	}
+	m.handleKey(keyDown, "")
+	lines := m.render(40, 6)
+	if len(lines) != 6 || !strings.Contains(lines[2], "fix-typo") || !strings.Contains(lines[2], "2 days ago") {
+		t.Errorf("render(40, 6) = %q; want fix-typo selected on third line", lines)
+	}
+	if choice, done := m.handleKey(keyEnter, "\n"); !done || choice == nil || choice.Name != "fix-typo" {
+		t.Errorf("enter chose %v, %t; want fix-typo", choice, done)
+	}
+
+	m.handleKey(keyBackspace, "")
+	m.handleKey(keyBackspace, "")
+	if got := matchNames(m); len(got) != len(branches) {
+		t.Errorf("matches after clearing query = %q; want all branches", got)
+	}
+	if choice, done := m.handleKey(keyEscape, ""); !done || choice != nil {
+		t.Errorf("esc chose %v, %t; want canceled", choice, done)
+	}
+}
+
+func matchNames(m *branchPickerModel) []string {
+	var names []string
+	for _, b := range m.matches {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+func TestRecentBranches(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"never", "second", "first"} {
+		if err := env.git.Run(ctx, "branch", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Checkouts within the same second are ordered by the reflog,
+	// which lists the newest first.
+	for _, name := range []string{"first", "second", "main"} {
+		if err := env.git.Run(ctx, "checkout", "--quiet", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := recentBranches(ctx, env.git)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, b := range got {
+		names = append(names, b.Name)
+	}
+	// Only check that main is first and never is last: the checkouts may
+	// have happened within the same second.
+	if len(names) != 4 || names[0] != "main" || names[3] != "never" {
+		t.Errorf("recentBranches(...) = %q; want main first and never last", names)
+	}
+	if !got[3].LastUsed.IsZero() {
+		t.Errorf("never.LastUsed = %v; want zero", got[3].LastUsed)
+	}
+}
+
+func TestUpdate_PickNeedsTerminal(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.gg(ctx, env.root.String(), "update", "--pick")
+	if err == nil || !strings.Contains(err.Error(), "terminal") {
+		t.Errorf("gg update --pick without a terminal = %v; want an error about the terminal", err)
+	}
+	_, err = env.gg(ctx, env.root.String(), "update", "--pick", "main")
+	if err == nil || !isUsage(err) {
+		t.Errorf("gg update --pick main = %v; want usage error", err)
+	}
+}
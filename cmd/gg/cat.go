@@ -21,16 +21,22 @@ import (
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
+	"gg-scm.io/tool/internal/terminal"
 )
 
 const catSynopsis = "output the current or given revision of files"
 
 func cat(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg cat [-r REV] FILE [...]", catSynopsis+`
+	f := flag.NewFlagSet(true, "gg cat [-r REV] [--textconv | --no-textconv] FILE [...]", catSynopsis+`
 
 	Print the specified files as they were at the given revision. If no
-	revision is given, HEAD is used.`)
+	revision is given, HEAD is used.`+textconvHelp+`
+	Files are only converted when printing to a terminal, so that
+	redirecting the output copies the files unchanged, unless
+	`+"`--textconv`"+` is given.`+autoFetchHelp+fileRefHelp)
 	r := f.String("r", git.Head.String(), "print the `rev`ision")
+	forceTextconv := f.Bool("textconv", false, "convert files with their textconv command even when not printing to a terminal")
+	noTextconv := f.Bool("no-textconv", false, "print files' contents without converting them")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -40,19 +46,56 @@ func cat(ctx context.Context, cc *cmdContext, args []string) error {
 	if f.NArg() == 0 {
 		return usagef("must pass one or more files to cat")
 	}
+	if *forceTextconv && *noTextconv {
+		return usagef("can't pass both --textconv and --no-textconv")
+	}
+	if err := fetchMissingRevs(ctx, cc, *r); err != nil {
+		return err
+	}
 	rev, err := cc.git.ParseRev(ctx, *r)
 	if err != nil {
 		return err
 	}
+	var cfg *git.Config
+	if !*noTextconv && (*forceTextconv || terminal.IsTerminal(cc.stdout)) {
+		cfg, err = cc.git.ReadConfig(ctx)
+		if err != nil {
+			return err
+		}
+	}
 	for _, arg := range f.Args() {
-		if err := catFile(ctx, cc, rev, arg); err != nil {
+		ref, err := parseFileRef(ctx, cc, arg)
+		if err != nil {
+			return err
+		}
+		if ref != nil {
+			err = catFileRef(ctx, cc, cfg, ref)
+		} else {
+			err = catFile(ctx, cc, cfg, rev, arg)
+		}
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func catFile(ctx context.Context, cc *cmdContext, rev *git.Rev, path string) error {
+func catFileRef(ctx context.Context, cc *cmdContext, cfg *git.Config, ref *fileRef) error {
+	r, err := ref.open(ctx, cc.git)
+	if err != nil {
+		return fmt.Errorf("%v: %w", ref, err)
+	}
+	err = catContent(ctx, cc, cfg, ref.path, r)
+	closeErr := r.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// catFile prints the file at path in rev. If cfg is not nil, the file is
+// converted with its textconv command, if any.
+func catFile(ctx context.Context, cc *cmdContext, cfg *git.Config, rev *git.Rev, path string) error {
 	// Find path relative to top of repository.
 	paths, err := cc.git.ListTree(ctx, rev.Commit.String(), git.ListTreeOptions{
 		NameOnly:  true,
@@ -79,7 +122,7 @@ func catFile(ctx context.Context, cc *cmdContext, rev *git.Rev, path string) err
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(cc.stdout, r)
+	err = catContent(ctx, cc, cfg, topPath, r)
 	closeErr := r.Close()
 	if err != nil {
 		return err
@@ -89,3 +132,19 @@ func catFile(ctx context.Context, cc *cmdContext, rev *git.Rev, path string) err
 	}
 	return nil
 }
+
+// catContent copies the content of the file at path from r to
+// cc.stdout, converting it with its textconv command if cfg is not nil.
+func catContent(ctx context.Context, cc *cmdContext, cfg *git.Config, path git.TopPath, r io.Reader) error {
+	if cfg != nil {
+		line, err := textconvCommand(ctx, cc.git, cfg, path)
+		if err != nil {
+			return err
+		}
+		if line != "" {
+			return runTextconv(ctx, cc, line, r, cc.stdout)
+		}
+	}
+	_, err := io.Copy(cc.stdout, r)
+	return err
+}
@@ -0,0 +1,335 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+	"golang.org/x/exp/slices"
+)
+
+const changelogSynopsis = "write a changelog section from commit history"
+
+func changelog(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg changelog [-r RANGE] [--format markdown|keepachangelog] [--title TITLE]", changelogSynopsis+`
+
+	Prints a changelog section for the commits in RANGE, which defaults
+	to the commits since the most recent tag. Merge commits are skipped.
+
+	Commits are grouped by their `+"`Changelog`"+` trailer if they have
+	one, or by their Conventional Commits type otherwise (for example,
+	"feat: add a flag" or "fix(log)!: stop crashing"). The name of the
+	trailer can be changed with the `+"`gg.changelog.trailer`"+` setting.
+	A trailer value of "skip" leaves the commit out of the changelog.
+
+	The `+"`markdown`"+` format has a section for each commit type and lists
+	every commit. The `+"`keepachangelog`"+` format uses the sections from
+	https://keepachangelog.com/ (Added, Changed, Deprecated, Removed,
+	Fixed, and Security) and leaves out documentation, test, build, and
+	chore commits. A trailer must name one of those sections to be placed
+	in it; other trailer values are listed under Changed.
+
+	`+"`gg release --notes-from-log`"+` uses the markdown format for its
+	release notes.`)
+	rangeFlag := f.String("r", "", "`range` of commits to include")
+	format := f.String("format", changelogMarkdown, "output `format`: markdown or keepachangelog")
+	title := f.String("title", "", "heading for the section, like a version number")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() != 0 {
+		return usagef("no arguments expected")
+	}
+	if *format != changelogMarkdown && *format != changelogKeepAChangelog {
+		return usagef("unknown format %q (must be markdown or keepachangelog)", *format)
+	}
+	if strings.HasPrefix(*rangeFlag, "-") {
+		return usagef("invalid range %q", *rangeFlag)
+	}
+	revs := []string{*rangeFlag}
+	if *rangeFlag == "" {
+		revs = []string{git.Head.String()}
+		if prevTag, err := previousTag(ctx, cc.git, git.Head.String()); err != nil {
+			return err
+		} else if prevTag != "" {
+			revs = append(revs, "^"+prevTag)
+		}
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	entries, err := readChangelogEntries(ctx, cc.git, changelogTrailer(cfg), revs)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(cc.stdout, formatChangelog(entries, *format, *title, time.Now()))
+	return err
+}
+
+// Changelog formats.
+const (
+	changelogMarkdown       = "markdown"
+	changelogKeepAChangelog = "keepachangelog"
+)
+
+// A changelogEntry is a commit as it appears in a changelog.
+type changelogEntry struct {
+	hash        string // abbreviated
+	description string
+	scope       string
+	kind        string // Conventional Commits type, like "feat"
+	label       string // value of the changelog trailer
+	breaking    bool
+}
+
+// changelogTrailer returns the name of the trailer that overrides a
+// commit's changelog section.
+func changelogTrailer(cfg *git.Config) string {
+	if name := cfg.Value("gg.changelog.trailer"); name != "" {
+		return name
+	}
+	return "Changelog"
+}
+
+// previousTag returns the most recent tag reachable from rev, or the
+// empty string if there is none.
+func previousTag(ctx context.Context, g *git.Git, rev string) (string, error) {
+	out, err := g.Output(ctx, "tag", "--merged", rev, "--sort=-creatordate", "--format=%(refname:strip=2)")
+	if err != nil {
+		return "", err
+	}
+	tag, _, _ := strings.Cut(out, "\n")
+	return tag, nil
+}
+
+// readChangelogEntries classifies the non-merge commits selected by revs
+// (as passed to git log), newest first. Commits labeled "skip" are
+// omitted.
+func readChangelogEntries(ctx context.Context, g *git.Git, trailer string, revs []string) ([]*changelogEntry, error) {
+	args := []string{"log", "--no-merges", "--format=%h%x00%B%x00"}
+	args = append(args, revs...)
+	out, err := g.Output(ctx, append(args, "--")...)
+	if err != nil {
+		return nil, fmt.Errorf("read commits: %w", err)
+	}
+	var entries []*changelogEntry
+	fields := strings.Split(out, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		e := classifyCommit(strings.TrimSpace(fields[i+1]), trailer)
+		if e == nil {
+			continue
+		}
+		e.hash = strings.TrimSpace(fields[i])
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// classifyCommit returns the changelog entry for a commit message, or
+// nil if the commit is labeled "skip".
+func classifyCommit(msg string, trailer string) *changelogEntry {
+	summary, body, _ := strings.Cut(msg, "\n")
+	e := &changelogEntry{description: strings.TrimSpace(summary)}
+	if kind, scope, desc, breaking, ok := parseConventionalSummary(e.description); ok {
+		e.kind = kind
+		e.scope = scope
+		e.description = desc
+		e.breaking = breaking
+	}
+	trailers := messageTrailers(body)
+	for _, t := range trailers {
+		switch {
+		case strings.EqualFold(t[0], trailer):
+			e.label = t[1]
+		case t[0] == "BREAKING CHANGE" || t[0] == "BREAKING-CHANGE":
+			e.breaking = true
+		}
+	}
+	if strings.EqualFold(e.label, "skip") {
+		return nil
+	}
+	return e
+}
+
+// parseConventionalSummary splits a Conventional Commits summary like
+// "feat(parser)!: add arrays" into its parts.
+func parseConventionalSummary(s string) (kind, scope, desc string, breaking, ok bool) {
+	prefix, desc, found := strings.Cut(s, ": ")
+	if !found || prefix == "" {
+		return "", "", "", false, false
+	}
+	if strings.HasSuffix(prefix, "!") {
+		breaking = true
+		prefix = prefix[:len(prefix)-1]
+	}
+	kind = prefix
+	if i := strings.IndexByte(prefix, '('); i >= 0 {
+		if !strings.HasSuffix(prefix, ")") {
+			return "", "", "", false, false
+		}
+		kind, scope = prefix[:i], prefix[i+1:len(prefix)-1]
+	}
+	if kind == "" {
+		return "", "", "", false, false
+	}
+	for _, c := range kind {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return "", "", "", false, false
+		}
+	}
+	return strings.ToLower(kind), scope, strings.TrimSpace(desc), breaking, true
+}
+
+// messageTrailers returns the "Key: value" pairs in the last paragraph
+// of a commit message body.
+func messageTrailers(body string) [][2]string {
+	body = strings.TrimSpace(body)
+	if i := strings.LastIndex(body, "\n\n"); i >= 0 {
+		body = body[i+2:]
+	}
+	var trailers [][2]string
+	for _, line := range strings.Split(body, "\n") {
+		k, v, ok := strings.Cut(line, ": ")
+		if !ok || k == "" || strings.ContainsAny(k, " \t") && k != "BREAKING CHANGE" {
+			return nil
+		}
+		trailers = append(trailers, [2]string{k, strings.TrimSpace(v)})
+	}
+	return trailers
+}
+
+// markdownSections is the order of the sections in the markdown format,
+// keyed by Conventional Commits type.
+var markdownSections = []struct {
+	title string
+	kinds []string
+}{
+	{"Features", []string{"feat", "feature"}},
+	{"Bug Fixes", []string{"fix"}},
+	{"Performance", []string{"perf"}},
+	{"Reverts", []string{"revert"}},
+	{"Documentation", []string{"docs"}},
+}
+
+// keepAChangelogSections is the order of the sections in the
+// keepachangelog format.
+var keepAChangelogSections = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// section returns the heading that the entry is listed under, or the
+// empty string if it is left out of the format.
+func (e *changelogEntry) section(format string) string {
+	if format == changelogKeepAChangelog {
+		if e.label != "" {
+			for _, s := range keepAChangelogSections {
+				if strings.EqualFold(e.label, s) {
+					return s
+				}
+			}
+			return "Changed"
+		}
+		switch e.kind {
+		case "feat", "feature":
+			return "Added"
+		case "fix":
+			return "Fixed"
+		case "revert":
+			return "Removed"
+		case "docs", "test", "tests", "build", "ci", "chore", "style":
+			return ""
+		default:
+			return "Changed"
+		}
+	}
+	if e.label != "" {
+		return strings.ToUpper(e.label[:1]) + e.label[1:]
+	}
+	if e.breaking {
+		return "Breaking Changes"
+	}
+	for _, s := range markdownSections {
+		for _, k := range s.kinds {
+			if e.kind == k {
+				return s.title
+			}
+		}
+	}
+	return "Other Changes"
+}
+
+// formatChangelog returns a changelog section listing entries. The
+// section is headed by title, if not empty.
+func formatChangelog(entries []*changelogEntry, format, title string, now time.Time) string {
+	order := []string{"Breaking Changes"}
+	for _, s := range markdownSections {
+		order = append(order, s.title)
+	}
+	if format == changelogKeepAChangelog {
+		order = keepAChangelogSections
+	}
+	var custom []string
+	bySection := make(map[string][]*changelogEntry)
+	for _, e := range entries {
+		s := e.section(format)
+		if s == "" {
+			continue
+		}
+		if !slices.Contains(order, s) && !slices.Contains(custom, s) && s != "Other Changes" {
+			custom = append(custom, s)
+		}
+		bySection[s] = append(bySection[s], e)
+	}
+	// Custom trailer labels go before the catch-all section.
+	order = append(append(slices.Clip(order), custom...), "Other Changes")
+
+	sb := new(strings.Builder)
+	switch {
+	case format == changelogKeepAChangelog && title == "":
+		sb.WriteString("## [Unreleased][]\n\n")
+	case format == changelogKeepAChangelog:
+		fmt.Fprintf(sb, "## [%s][] - %s\n\n", title, now.Format("2006-01-02"))
+	case title != "":
+		fmt.Fprintf(sb, "## %s\n\n", title)
+	}
+	for _, s := range order {
+		list := bySection[s]
+		if len(list) == 0 {
+			continue
+		}
+		fmt.Fprintf(sb, "### %s\n\n", s)
+		for _, e := range list {
+			sb.WriteString("- ")
+			if e.breaking && format == changelogKeepAChangelog {
+				sb.WriteString("**Breaking:** ")
+			}
+			if e.scope != "" {
+				fmt.Fprintf(sb, "%s: ", e.scope)
+			}
+			fmt.Fprintf(sb, "%s (%s)\n", e.description, e.hash)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestClassifyCommit(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want *changelogEntry
+	}{
+		{
+			msg:  "Fix the thing",
+			want: &changelogEntry{description: "Fix the thing"},
+		},
+		{
+			msg:  "feat: add a flag",
+			want: &changelogEntry{kind: "feat", description: "add a flag"},
+		},
+		{
+			msg:  "fix(log)!: stop crashing",
+			want: &changelogEntry{kind: "fix", scope: "log", description: "stop crashing", breaking: true},
+		},
+		{
+			msg:  "refactor: move code\n\nBREAKING CHANGE: the API moved",
+			want: &changelogEntry{kind: "refactor", description: "move code", breaking: true},
+		},
+		{
+			msg:  "Improve speed\n\nMuch faster now.\n\nChangelog: changed\nSigned-off-by: A <a@example.com>",
+			want: &changelogEntry{description: "Improve speed", label: "changed"},
+		},
+		{
+			msg:  "Update README: typo",
+			want: &changelogEntry{description: "Update README: typo"},
+		},
+		{
+			msg:  "chore: bump deps\n\nChangelog: skip",
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		got := classifyCommit(test.msg, "Changelog")
+		if got == nil || test.want == nil {
+			if got != test.want {
+				t.Errorf("classifyCommit(%q) = %+v; want %+v", test.msg, got, test.want)
+			}
+			continue
+		}
+		if *got != *test.want {
+			t.Errorf("classifyCommit(%q) = %+v; want %+v", test.msg, *got, *test.want)
+		}
+	}
+}
+
+func TestFormatChangelog(t *testing.T) {
+	entries := []*changelogEntry{
+		{hash: "aaaaaaa", kind: "feat", description: "add a flag"},
+		{hash: "bbbbbbb", description: "Tidy up"},
+		{hash: "ccccccc", kind: "fix", scope: "log", description: "stop crashing", breaking: true},
+		{hash: "ddddddd", kind: "docs", description: "explain flags"},
+		{hash: "eeeeeee", kind: "fix", description: "handle empty input"},
+		{hash: "fffffff", description: "Patch a hole", label: "security"},
+	}
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	got := formatChangelog(entries, changelogMarkdown, "v1.2.0", now)
+	want := "## v1.2.0\n\n" +
+		"### Breaking Changes\n\n" +
+		"- log: stop crashing (ccccccc)\n\n" +
+		"### Features\n\n" +
+		"- add a flag (aaaaaaa)\n\n" +
+		"### Bug Fixes\n\n" +
+		"- handle empty input (eeeeeee)\n\n" +
+		"### Documentation\n\n" +
+		"- explain flags (ddddddd)\n\n" +
+		"### Security\n\n" +
+		"- Patch a hole (fffffff)\n\n" +
+		"### Other Changes\n\n" +
+		"- Tidy up (bbbbbbb)\n\n"
+	if got != want {
+		t.Errorf("markdown changelog:\n%s\nwant:\n%s", got, want)
+	}
+
+	got = formatChangelog(entries, changelogKeepAChangelog, "1.2.0", now)
+	want = "## [1.2.0][] - 2026-03-04\n\n" +
+		"### Added\n\n" +
+		"- add a flag (aaaaaaa)\n\n" +
+		"### Changed\n\n" +
+		"- Tidy up (bbbbbbb)\n\n" +
+		"### Fixed\n\n" +
+		"- **Breaking:** log: stop crashing (ccccccc)\n" +
+		"- handle empty input (eeeeeee)\n\n" +
+		"### Security\n\n" +
+		"- Patch a hole (fffffff)\n\n"
+	if got != want {
+		t.Errorf("keepachangelog changelog:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repo"); err != nil {
+		t.Fatal(err)
+	}
+	repoGit := env.git.WithDir(env.root.FromSlash("repo"))
+	if err := repoGit.Run(ctx, "tag", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"feat: add foo", "fix: repair foo"} {
+		if err := env.root.Apply(filesystem.Write("repo/foo.txt", msg)); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, "repo/foo.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if err := repoGit.Commit(ctx, msg, git.CommitOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hashes, err := repoGit.Output(ctx, "log", "--format=%h", "-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixHash, featHash string
+	if _, err := fmt.Sscan(hashes, &fixHash, &featHash); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.FromSlash("repo"), "changelog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "### Features\n\n" +
+		"- add foo (" + featHash + ")\n\n" +
+		"### Bug Fixes\n\n" +
+		"- repair foo (" + fixHash + ")\n\n"
+	if got := string(out); got != want {
+		t.Errorf("gg changelog output:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := env.gg(ctx, env.root.FromSlash("repo"), "changelog", "--format=html"); err == nil {
+		t.Error("gg changelog --format=html succeeded")
+	} else if !isUsage(err) {
+		t.Errorf("gg changelog --format=html = %v; want usage error", err)
+	}
+}
@@ -26,7 +26,10 @@ import (
 const cloneSynopsis = "make a copy of an existing repository"
 
 func clone(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg clone [-b BRANCH] SOURCE [DEST]", cloneSynopsis)
+	f := flag.NewFlagSet(true, "gg clone [-b BRANCH] SOURCE [DEST]", cloneSynopsis+`
+
+	SOURCE can be a path alias defined by a `+"`gg.paths.NAME`"+` variable in
+	the user's Git configuration (see `+"`gg paths`"+`).`)
 	branch := f.String("b", git.Head.String(), "`branch` to check out")
 	f.Alias("b", "branch")
 	gerrit := f.Bool("gerrit", false, "install Gerrit hook")
@@ -44,6 +47,9 @@ func clone(ctx context.Context, cc *cmdContext, args []string) error {
 		return usagef("can't pass more than one destination")
 	}
 	src, dst := f.Arg(0), f.Arg(1)
+	if cfg, err := cc.git.ReadConfig(ctx); err == nil {
+		src = resolvePathAlias(cfg, src)
+	}
 	if dst == "" {
 		dst = defaultCloneDest(src)
 	}
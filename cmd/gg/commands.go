@@ -0,0 +1,254 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/tool/internal/flag"
+)
+
+// A commandInfo describes a gg command for the top-level help and for
+// `gg help --json`. The command itself is run by dispatch.
+type commandInfo struct {
+	name     string
+	aliases  []string
+	synopsis string
+	advanced bool
+
+	// readOnly reports whether an invocation is allowed in read-only
+	// mode. See readOnlyRule.
+	readOnly readOnlyRule
+	// conflicts is true if the command can create or clear conflicts,
+	// for example by leaving a rebase, merge, cherry-pick, or git am
+	// stopped, so the conflict manifest must be refreshed after it runs.
+	conflicts bool
+}
+
+var commands = []commandInfo{
+	{name: "add", synopsis: addSynopsis, conflicts: true},
+	{name: "addremove", synopsis: addRemoveSynopsis},
+	{name: "branch", synopsis: branchSynopsis, readOnly: readOnlyWithout(0, nil, []string{"p", "pattern", "sort", "r"})},
+	{name: "cat", synopsis: catSynopsis, readOnly: alwaysReadOnly},
+	{name: "clone", synopsis: cloneSynopsis},
+	{name: "commit", aliases: []string{"ci"}, synopsis: commitSynopsis, conflicts: true},
+	{name: "diff", synopsis: diffSynopsis, readOnly: alwaysReadOnly},
+	{name: "identify", aliases: []string{"id"}, synopsis: identifySynopsis, readOnly: alwaysReadOnly},
+	{name: "init", synopsis: initSynopsis},
+	{name: "log", aliases: []string{"history"}, synopsis: logSynopsis, readOnly: alwaysReadOnly},
+	{name: "merge", synopsis: mergeSynopsis, conflicts: true},
+	{name: "pull", synopsis: pullSynopsis, conflicts: true},
+	{name: "push", synopsis: pushSynopsis},
+	{name: "remove", aliases: []string{"rm"}, synopsis: removeSynopsis},
+	{name: "requestpull", aliases: []string{"pr"}, synopsis: requestPullSynopsis},
+	{name: "revert", synopsis: revertSynopsis, conflicts: true},
+	{name: "status", aliases: []string{"st", "check"}, synopsis: statusSynopsis, readOnly: alwaysReadOnly},
+	{name: "update", aliases: []string{"up", "checkout", "co"}, synopsis: updateSynopsis, conflicts: true},
+
+	{name: "absorb", synopsis: absorbSynopsis, advanced: true, conflicts: true},
+	{name: "amend", synopsis: amendSynopsis, advanced: true},
+	{name: "apply", synopsis: applySynopsis, advanced: true},
+	{name: "attrs", synopsis: attrsSynopsis, advanced: true, readOnly: alwaysReadOnly},
+	{name: "auth", synopsis: authSynopsis, advanced: true, readOnly: readOnlySubcommands("", "list")},
+	{name: "backout", synopsis: backoutSynopsis, advanced: true, conflicts: true},
+	{name: "backups", synopsis: backupsSynopsis, advanced: true, readOnly: readOnlySubcommands("", "list")},
+	{name: "changelog", synopsis: changelogSynopsis, advanced: true, readOnly: alwaysReadOnly},
+	{name: "config", synopsis: configSynopsis, advanced: true, readOnly: readOnlyWithout(1, []string{"unset"}, nil)},
+	{name: "debug", synopsis: debugSynopsis, advanced: true, readOnly: readOnlySubcommands("obj", "refs")},
+	{name: "evolve", synopsis: evolveSynopsis, advanced: true, conflicts: true},
+	{name: "filelog", synopsis: filelogSynopsis, advanced: true, readOnly: alwaysReadOnly},
+	{name: "fixup", synopsis: fixupSynopsis, advanced: true},
+	{name: "fold", aliases: []string{"squash"}, synopsis: foldSynopsis, advanced: true},
+	{name: "gerrithook", synopsis: gerrithookSynopsis, advanced: true},
+	{name: "git", synopsis: gitSynopsis, advanced: true, conflicts: true},
+	{name: "github-login", synopsis: gitHubLoginSynopsis, advanced: true},
+	{name: "graft", synopsis: graftSynopsis, advanced: true, conflicts: true},
+	{name: "histedit", synopsis: histeditSynopsis, advanced: true, conflicts: true},
+	{name: "identity", synopsis: identitySynopsis, advanced: true, readOnly: readOnlySubcommands("", "list")},
+	{name: "import", synopsis: importSynopsis, advanced: true, conflicts: true},
+	{name: "incoming", synopsis: incomingSynopsis, advanced: true, readOnly: readOnlyWithout(0, []string{"upstream"}, []string{"r"})},
+	{name: "journal", synopsis: journalSynopsis, advanced: true, readOnly: readOnlySubcommands("export")},
+	{name: "lint-history", synopsis: lintHistorySynopsis, advanced: true, readOnly: readOnlyWithout(0, []string{"exec"}, []string{"r", "base"})},
+	{name: "mail", synopsis: mailSynopsis, advanced: true},
+	{name: "note", synopsis: noteSynopsis, advanced: true, readOnly: readOnlySubcommands("", "show")},
+	{name: "old", synopsis: oldSynopsis, advanced: true, readOnly: readOnlySubcommands("", "list")},
+	{name: "outgoing", synopsis: outgoingSynopsis, advanced: true, readOnly: readOnlyWithout(0, []string{"upstream"}, []string{"r"})},
+	{name: "paths", synopsis: pathsSynopsis, advanced: true, readOnly: alwaysReadOnly},
+	{name: "rebase", synopsis: rebaseSynopsis, advanced: true, conflicts: true},
+	{name: "recent", synopsis: recentSynopsis, advanced: true, readOnly: alwaysReadOnly},
+	{name: "release", synopsis: releaseSynopsis, advanced: true},
+	{name: "remote", synopsis: remoteSynopsis, advanced: true, readOnly: readOnlySubcommands("")},
+	{name: "repos", synopsis: reposSynopsis, advanced: true, readOnly: readOnlySubcommands("", "list", "path", "status", "shell")},
+	{name: "resolve", synopsis: resolveSynopsis, advanced: true, conflicts: true},
+	{name: "resolve-rev", synopsis: resolveRevSynopsis, advanced: true, readOnly: alwaysReadOnly},
+	{name: "restore-from", synopsis: restoreFromSynopsis, advanced: true},
+	{name: "shelve", synopsis: shelveSynopsis, advanced: true, readOnly: readOnlyWith([]string{"l", "list"}, []string{"n", "name", "m", "I", "include", "X", "exclude"})},
+	{name: "snapshot", synopsis: snapshotSynopsis, advanced: true, readOnly: readOnlySubcommands("list", "diff")},
+	{name: "stage", synopsis: stageSynopsis, advanced: true, conflicts: true},
+	{name: "state", synopsis: stateSynopsis, advanced: true, readOnly: alwaysReadOnly},
+	{name: "stats-repo", synopsis: statsRepoSynopsis, advanced: true, readOnly: alwaysReadOnly},
+	{name: "trust", synopsis: trustSynopsis, advanced: true},
+	{name: "unstage", synopsis: unstageSynopsis, advanced: true},
+	{name: "unshelve", synopsis: unshelveSynopsis, advanced: true, conflicts: true},
+	{name: "untrack-changes", synopsis: untrackChangesSynopsis, advanced: true},
+	{name: "upstream", synopsis: upstreamSynopsis, advanced: true, readOnly: readOnlyWithout(0, nil, []string{"b"})},
+	{name: "view", synopsis: viewSynopsis, advanced: true, conflicts: true},
+}
+
+// findCommand returns the command with the given name or alias.
+func findCommand(name string) *commandInfo {
+	for i := range commands {
+		c := &commands[i]
+		if c.name == name {
+			return c
+		}
+		for _, a := range c.aliases {
+			if a == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// commandNames returns the names and aliases of all commands, for
+// suggesting a command when given an unknown one.
+func commandNames() []string {
+	names := []string{"help", "version"}
+	for _, c := range commands {
+		names = append(names, c.name)
+		names = append(names, c.aliases...)
+	}
+	return names
+}
+
+// commandList formats the basic or advanced commands for the top-level
+// help, one per line.
+func commandList(advanced bool) string {
+	sb := new(strings.Builder)
+	for _, c := range commands {
+		if c.advanced != advanced {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		if len(c.name) > 12 {
+			fmt.Fprintf(sb, "  %s\n%16s%s", c.name, "", c.synopsis)
+		} else {
+			fmt.Fprintf(sb, "  %-14s%s", c.name, c.synopsis)
+		}
+	}
+	return sb.String()
+}
+
+// cliMetadata is the JSON output of `gg help --json`.
+type cliMetadata struct {
+	Usage    string              `json:"usage"`
+	Flags    []flag.FlagMetadata `json:"flags"`
+	Commands []*commandMetadata  `json:"commands"`
+}
+
+// commandMetadata is the JSON description of a single command.
+type commandMetadata struct {
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Synopsis string   `json:"synopsis"`
+	Category string   `json:"category"`
+	*flag.Metadata
+}
+
+// metadataCollector is passed as a command's stdout to capture its
+// flag set's metadata when it is asked for help.
+type metadataCollector struct {
+	md *flag.Metadata
+}
+
+func (c *metadataCollector) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (c *metadataCollector) CollectMetadata(md *flag.Metadata) {
+	c.md = md
+}
+
+// describeCommand returns the metadata for a command by running it
+// with --help.
+func describeCommand(ctx context.Context, cc *cmdContext, globalFlags *flag.FlagSet, c *commandInfo) (*commandMetadata, error) {
+	collector := new(metadataCollector)
+	cc2 := new(cmdContext)
+	*cc2 = *cc
+	cc2.stdout = collector
+	if err := dispatch(ctx, cc2, globalFlags, c.name, []string{"--help"}); err != nil {
+		return nil, fmt.Errorf("describe %s: %w", c.name, err)
+	}
+	if collector.md == nil {
+		return nil, fmt.Errorf("describe %s: no flags reported", c.name)
+	}
+	category := "basic"
+	if c.advanced {
+		category = "advanced"
+	}
+	return &commandMetadata{
+		Name:     c.name,
+		Aliases:  c.aliases,
+		Synopsis: c.synopsis,
+		Category: category,
+		Metadata: collector.md,
+	}, nil
+}
+
+// writeHelpJSON prints the metadata for all commands, or for the named
+// command if name is not empty.
+func writeHelpJSON(ctx context.Context, cc *cmdContext, globalFlags *flag.FlagSet, name string) error {
+	var v interface{}
+	if name != "" {
+		c := findCommand(name)
+		if c == nil {
+			return usagef("unknown command %s", name)
+		}
+		md, err := describeCommand(ctx, cc, globalFlags, c)
+		if err != nil {
+			return err
+		}
+		v = md
+	} else {
+		global := globalFlags.Metadata()
+		cli := &cliMetadata{
+			Usage: global.Usage,
+			Flags: global.Flags,
+		}
+		for i := range commands {
+			md, err := describeCommand(ctx, cc, globalFlags, &commands[i])
+			if err != nil {
+				return err
+			}
+			cli.Commands = append(cli.Commands, md)
+		}
+		v = cli
+	}
+	out, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	out = append(out, '\n')
+	_, err = cc.stdout.Write(out)
+	return err
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/flag"
+)
+
+func TestHelpJSON(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "help", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cli struct {
+		Usage    string
+		Flags    []flag.FlagMetadata
+		Commands []struct {
+			Name     string
+			Aliases  []string
+			Synopsis string
+			Category string
+			Usage    string
+			Flags    []flag.FlagMetadata
+		}
+	}
+	if err := json.Unmarshal(out, &cli); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	if !strings.HasPrefix(cli.Usage, "gg ") {
+		t.Errorf("usage = %q; want to start with \"gg \"", cli.Usage)
+	}
+	if len(cli.Commands) != len(commands) {
+		t.Errorf("got %d commands; want %d", len(cli.Commands), len(commands))
+	}
+	for _, c := range cli.Commands {
+		if c.Synopsis == "" || !strings.HasPrefix(c.Usage, "gg "+c.Name) {
+			t.Errorf("command %s: synopsis = %q, usage = %q", c.Name, c.Synopsis, c.Usage)
+		}
+		if c.Category != "basic" && c.Category != "advanced" {
+			t.Errorf("command %s: category = %q", c.Name, c.Category)
+		}
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "help", "--json", "ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var commit struct {
+		Name    string
+		Aliases []string
+		Flags   []flag.FlagMetadata
+	}
+	if err := json.Unmarshal(out, &commit); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	if commit.Name != "commit" || len(commit.Aliases) != 1 || commit.Aliases[0] != "ci" {
+		t.Errorf("gg help --json ci: name = %q, aliases = %q; want \"commit\", [\"ci\"]", commit.Name, commit.Aliases)
+	}
+	foundAmend := false
+	for _, f := range commit.Flags {
+		if f.Name == "amend" {
+			foundAmend = true
+			if f.Type != "bool" {
+				t.Errorf("commit -amend type = %q; want \"bool\"", f.Type)
+			}
+		}
+	}
+	if !foundAmend {
+		t.Errorf("gg help --json ci flags = %+v; want to include amend", commit.Flags)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "help", "--json", "bogus"); err == nil {
+		t.Error("gg help --json bogus did not return an error")
+	} else if !isUsage(err) {
+		t.Errorf("gg help --json bogus error = %v; want usage", err)
+	}
+}
+
+func TestCommandList(t *testing.T) {
+	got := commandList(true)
+	want := "  absorb        " + absorbSynopsis + "\n"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("commandList(true) = %q; want to start with %q", got, want)
+	}
+	want = "\n  untrack-changes\n                " + untrackChangesSynopsis + "\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("commandList(true) = %q; want to contain %q", got, want)
+	}
+}
+
+func TestUnknownCommandSuggestion(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.gg(ctx, env.root.String(), "comit")
+	if err == nil {
+		t.Fatal("gg comit succeeded")
+	}
+	if got, want := err.Error(), "did you mean 'commit'?"; !strings.Contains(got, want) {
+		t.Errorf("gg comit error = %q; want to contain %q", got, want)
+	}
+	_, err = env.gg(ctx, env.root.String(), "commit", "--ammend")
+	if err == nil {
+		t.Fatal("gg commit --ammend succeeded")
+	}
+	if got, want := err.Error(), "did you mean '-amend'?"; !strings.Contains(got, want) {
+		t.Errorf("gg commit --ammend error = %q; want to contain %q", got, want)
+	}
+}
@@ -32,7 +32,7 @@ import (
 const commitSynopsis = "commit the specified files or all outstanding changes"
 
 func commit(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg commit [--amend] [-m MSG] [FILE [...]]", commitSynopsis+`
+	f := flag.NewFlagSet(true, "gg commit [--amend [--allow-rewrite-published] [--force-protected] | --split-by-dir [-n] | --tui] [--branch NAME] [--no-verify] [-m MSG] [-I PATTERN] [-X PATTERN] [FILE [...]]", commitSynopsis+`
 
 aliases: ci
 
@@ -42,10 +42,37 @@ aliases: ci
 
 	Unlike Git, gg does not require you to stage your changes into the
 	index. This approximates the behavior of `+"`git commit -a`"+`, but
-	this command will only change the index if the commit succeeds.`)
+	this command will only change the index if the commit succeeds.
+
+	With `+"`--split-by-dir`"+`, one commit is created for each top-level
+	directory with changes, or for each FILE argument if any are given.
+	Files at the top of the repository are grouped under the name ".".
+	An occurrence of "{dir}" in the message is replaced with the name of
+	the directory or the FILE argument. If no message is given, an editor
+	is opened for each commit. Use `+"`-n`"+` to preview the commits
+	without creating them.
+
+	`+"`--amend`"+` refuses to rewrite a commit that is already on a remote
+	branch, since the amended commit would have to be force-pushed. Pass
+	`+"`--allow-rewrite-published`"+` to amend it anyway.
+
+	With `+"`--branch`"+`, the new commit is placed on a new branch with the
+	given name, which becomes the current branch. The previous branch, if
+	any, is left where it was. A commit made while HEAD is detached is not
+	on any branch unless `+"`--branch`"+` is given, and gg warns about it.`+protectedBranchHelp+commitTUIHelp+commitGuardHelp+commitMessageCommandHelp+hookOutputHelp+dateSkewHelp+patternHelp)
+	pats := new(patternSet)
+	pats.addFlags(f)
 	amend := f.Bool("amend", false, "amend the parent of the working directory")
+	allowPublished := f.Bool("allow-rewrite-published", false, allowRewritePublishedUsage)
+	forceProtected := f.Bool("force-protected", false, forceProtectedUsage)
 	runHooks := f.Bool("hooks", true, "whether to run Git hooks")
+	noVerify := f.Bool("no-verify", false, noVerifyUsage)
 	msg := f.String("m", "", "use text as commit `message`")
+	splitByDir := f.Bool("split-by-dir", false, "create one commit per top-level directory or FILE argument")
+	dryRun := f.Bool("n", false, "with --split-by-dir, show the commits that would be created")
+	f.Alias("n", "dry-run")
+	tui := f.Bool("tui", false, "select the changes to commit in a full-screen terminal interface")
+	newBranch := f.String("branch", "", "create a branch with the given `name` for the new commit")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -53,21 +80,119 @@ aliases: ci
 		return usagef("%v", err)
 	}
 
+	if *splitByDir && *amend {
+		return usagef("cannot pass both --split-by-dir and --amend")
+	}
+	if *dryRun && !*splitByDir {
+		return usagef("--dry-run requires --split-by-dir")
+	}
+	if *allowPublished && !*amend {
+		return usagef("--allow-rewrite-published requires --amend")
+	}
+	if *forceProtected && !*amend {
+		return usagef("--force-protected requires --amend")
+	}
+	if *tui && (*amend || *splitByDir) {
+		return usagef("cannot pass --tui with --amend or --split-by-dir")
+	}
+	if *newBranch != "" && (*amend || *splitByDir) {
+		return usagef("cannot pass --branch with --amend or --split-by-dir")
+	}
 	// Get status on files. First level of assurance is to stop empty commits.
 	// This status info may get used for interactive commit message template.
-	var pathspecs []git.Pathspec
-	for _, arg := range f.Args() {
-		pathspecs = append(pathspecs, git.LiteralPath(arg))
+	pats.args = f.Args()
+	if !*dryRun {
+		warnIdentityMismatch(ctx, cc)
+	}
+	if !*dryRun && !*noVerify {
+		pathspecs, err := pats.pathspecs(ctx, cc.git)
+		if err != nil {
+			return err
+		}
+		if err := checkCommitChanges(ctx, cc, pathspecs); err != nil {
+			return err
+		}
+	}
+	if *splitByDir {
+		if err := splitCommit(ctx, cc, *msg, pats, *dryRun, *runHooks); err != nil {
+			return err
+		}
+		if !*dryRun {
+			warnDateSkew(ctx, cc, false)
+		}
+		return nil
+	}
+	pathspecs, err := pats.pathspecs(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	oldRef, err := cc.git.HeadRef(ctx)
+	if err != nil {
+		return err
+	}
+	oldHead, _ := cc.git.Head(ctx)
+	if *newBranch != "" {
+		if err := checkCommitBranchName(ctx, cc, *newBranch); err != nil {
+			return err
+		}
 	}
 	if *amend {
-		return doAmend(ctx, cc, *msg, pathspecs, *runHooks)
+		if !*forceProtected {
+			if err := checkRewriteProtected(ctx, cc, "amend"); err != nil {
+				return err
+			}
+		}
+		if !*allowPublished {
+			if err := checkRewritePublished(ctx, cc.git, "amend", "--max-count=1", git.Head.String()); err != nil {
+				return err
+			}
+		}
+		err = doAmend(ctx, cc, *msg, pathspecs, *runHooks, amendOptions{})
+	} else if *tui {
+		err = commitTUI(ctx, cc, *msg, pathspecs, *runHooks)
+	} else {
+		err = doCommit(ctx, cc, *msg, "", pathspecs, *runHooks)
+	}
+	if err != nil {
+		return err
+	}
+	if *newBranch != "" {
+		if err := moveCommitToBranch(ctx, cc.git, *newBranch, oldRef, oldHead); err != nil {
+			return err
+		}
+	} else if oldRef == "" {
+		warnDetachedCommit(ctx, cc)
+	}
+	warnDateSkew(ctx, cc, *amend)
+	return nil
+}
+
+// checkCommitBranchName verifies that commit --branch can create
+// a branch with the given name.
+func checkCommitBranchName(ctx context.Context, cc *cmdContext, name string) error {
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	if _, err := cc.git.ParseRev(ctx, git.BranchRef(name).String()); err == nil {
+		return fmt.Errorf("branch %q already exists", name)
 	}
-	return doCommit(ctx, cc, *msg, pathspecs, *runHooks)
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	policy, err := readBranchNamePolicy(cfg, cc.env)
+	if err != nil {
+		return err
+	}
+	return checkNewBranchNames(ctx, cc, policy, []string{name})
 }
 
 const commitMsgFilename = "COMMIT_MSG"
 
-func doCommit(ctx context.Context, cc *cmdContext, msg string, pathspecs []git.Pathspec, runHooks bool) error {
+// doCommit commits the changes to the files matched by pathspecs.
+// If msg is empty, then the user is prompted for a message in an editor
+// that starts with draft.
+func doCommit(ctx context.Context, cc *cmdContext, msg, draft string, pathspecs []git.Pathspec, runHooks bool) error {
 	// Get status on files. First level of assurance is to stop empty commits.
 	// This status info may get used for interactive commit message template.
 	status, err := cc.git.Status(ctx, git.StatusOptions{
@@ -106,6 +231,10 @@ func doCommit(ctx context.Context, cc *cmdContext, msg string, pathspecs []git.P
 		}
 		msgBuf := new(bytes.Buffer)
 		msgBuf.Write(maybeMergeMessage(ctx, cc.git))
+		msgBuf.WriteString(draft)
+		if msgBuf.Len() == 0 {
+			writeSuggestedMessage(msgBuf, suggestCommitMessage(ctx, cc, cfg, pathspecs), commentChar)
+		}
 		err = commitMessageTemplate(ctx, cc.git, diffStatus, msgBuf, commentChar)
 		if err != nil {
 			return err
@@ -120,13 +249,41 @@ func doCommit(ctx context.Context, cc *cmdContext, msg string, pathspecs []git.P
 	}
 
 	// Commit as appropriate.
-	opts := git.CommitOptions{
-		SkipHooks: !runHooks,
+	return runCommit(ctx, cc, msg, pathspecs, runHooks, false, "")
+}
+
+// runCommit runs git commit with the given message, committing the
+// changes to the files matched by pathspecs or all changes if pathspecs
+// is empty. A non-empty date sets the author date. Hook output is shown
+// as it is written.
+func runCommit(ctx context.Context, cc *cmdContext, msg string, pathspecs []git.Pathspec, runHooks, amend bool, date string) error {
+	args := []string{"commit", "--quiet", "--file=-", "--cleanup=verbatim"}
+	if amend {
+		args = append(args, "--amend")
 	}
-	if len(pathspecs) > 0 {
-		return cc.git.CommitFiles(ctx, msg, pathspecs, opts)
+	if date != "" {
+		args = append(args, "--date="+date)
 	}
-	return cc.git.CommitAll(ctx, msg, opts)
+	if !runHooks {
+		args = append(args, "--no-verify")
+	}
+	if len(pathspecs) == 0 {
+		args = append(args, "--all")
+	} else {
+		args = append(args, "--only", "--")
+		for _, p := range pathspecs {
+			args = append(args, p.String())
+		}
+	}
+	err := runGitWithHooks(ctx, cc, &git.Invocation{
+		Dir:   cc.dir,
+		Args:  args,
+		Stdin: strings.NewReader(msg),
+	})
+	if err != nil {
+		return fmt.Errorf("git commit: %w", err)
+	}
+	return nil
 }
 
 func maybeMergeMessage(ctx context.Context, g *git.Git) []byte {
@@ -141,7 +298,18 @@ func maybeMergeMessage(ctx context.Context, g *git.Git) []byte {
 	return mergeMsg
 }
 
-func doAmend(ctx context.Context, cc *cmdContext, msg string, pathspecs []git.Pathspec, runHooks bool) error {
+// amendOptions are the options for doAmend that gg amend adds to those
+// of gg commit --amend.
+type amendOptions struct {
+	// keepMessage reuses the commit's message instead of opening an
+	// editor when no message is given.
+	keepMessage bool
+	// date is the new author date, in any format that git commit --date
+	// accepts. If empty, the commit keeps its author date.
+	date string
+}
+
+func doAmend(ctx context.Context, cc *cmdContext, msg string, pathspecs []git.Pathspec, runHooks bool, opts amendOptions) error {
 
 	// Get status on files (may get used for interactive commit message template).
 	status, err := cc.git.Status(ctx, git.StatusOptions{
@@ -178,7 +346,9 @@ func doAmend(ctx context.Context, cc *cmdContext, msg string, pathspecs []git.Pa
 	}
 
 	// Get message from user.
-	if msg == "" {
+	if msg == "" && opts.keepMessage {
+		msg = commitInfo.Message
+	} else if msg == "" {
 		// Open message in editor.
 		cfg, err := cc.git.ReadConfig(ctx)
 		if err != nil {
@@ -204,14 +374,7 @@ func doAmend(ctx context.Context, cc *cmdContext, msg string, pathspecs []git.Pa
 	}
 
 	// Amend as appropriate.
-	opts := git.AmendOptions{
-		Message:   msg,
-		SkipHooks: !runHooks,
-	}
-	if len(pathspecs) > 0 {
-		return cc.git.AmendFiles(ctx, pathspecs, opts)
-	}
-	return cc.git.AmendAll(ctx, opts)
+	return runCommit(ctx, cc, msg, pathspecs, runHooks, true, opts.date)
 }
 
 func amendedDiffStatus(ctx context.Context, g *git.Git, baseRev string, pathspecs []git.Pathspec) ([]git.DiffStatusEntry, error) {
@@ -304,24 +467,35 @@ func commitMessageTemplate(ctx context.Context, g *git.Git, status []git.DiffSta
 		return status[i].Name < status[j].Name
 	})
 	for _, ent := range status {
-		switch ent.Code {
-		case git.DiffStatusAdded:
-			fmt.Fprintf(buf, "%s added %s\n", commentChar, ent.Name)
-		case git.DiffStatusCopied:
-			fmt.Fprintf(buf, "%s copied %s\n", commentChar, ent.Name)
-		case git.DiffStatusDeleted:
-			fmt.Fprintf(buf, "%s removed %s\n", commentChar, ent.Name)
-		case git.DiffStatusModified:
-			fmt.Fprintf(buf, "%s modified %s\n", commentChar, ent.Name)
-		case git.DiffStatusRenamed:
-			fmt.Fprintf(buf, "%s renamed %s\n", commentChar, ent.Name)
-		case git.DiffStatusChangedMode:
-			fmt.Fprintf(buf, "%s chmod %s\n", commentChar, ent.Name)
+		if verb := diffStatusVerb(ent.Code); verb != "" {
+			fmt.Fprintf(buf, "%s %s %s\n", commentChar, verb, ent.Name)
 		}
 	}
 	return nil
 }
 
+// diffStatusVerb returns the word used to describe a file's change in
+// a commit message template or an empty string if the change should not
+// be shown.
+func diffStatusVerb(code git.DiffStatusCode) string {
+	switch code {
+	case git.DiffStatusAdded:
+		return "added"
+	case git.DiffStatusCopied:
+		return "copied"
+	case git.DiffStatusDeleted:
+		return "removed"
+	case git.DiffStatusModified:
+		return "modified"
+	case git.DiffStatusRenamed:
+		return "renamed"
+	case git.DiffStatusChangedMode:
+		return "chmod"
+	default:
+		return ""
+	}
+}
+
 func cleanupMessage(s string, commentPrefix string) string {
 	lines := strings.SplitAfter(s, "\n")
 
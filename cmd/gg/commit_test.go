@@ -20,9 +20,11 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/escape"
 	"gg-scm.io/tool/internal/filesystem"
 	"github.com/google/go-cmp/cmp"
 )
@@ -1205,3 +1207,265 @@ func objectExists(ctx context.Context, g *git.Git, rev string, path git.TopPath)
 	}
 	return nil
 }
+
+func TestCommit_AmendPublished(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository A and clone it to repository B, so that HEAD
+	// in repository B is on origin/main.
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "repoA", "repoB"); err != nil {
+		t.Fatal(err)
+	}
+	repoBPath := env.root.FromSlash("repoB")
+	gitB := env.git.WithDir(repoBPath)
+	published, err := gitB.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoB/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.trackFiles(ctx, "repoB/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, repoBPath, "commit", "--amend", "-m", "amended")
+	if err == nil {
+		t.Fatal("gg commit --amend of published commit succeeded")
+	}
+	if isUsage(err) {
+		t.Errorf("gg commit --amend error: %v; want failure, not usage", err)
+	}
+	if got, want := err.Error(), "is already on origin/main"; !strings.Contains(got, want) {
+		t.Errorf("gg commit --amend error = %q; want to contain %q", got, want)
+	}
+	if head, err := gitB.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if head.Commit != published.Commit {
+		t.Errorf("HEAD = %v; want unchanged %v", head.Commit, published.Commit)
+	}
+
+	if _, err := env.gg(ctx, repoBPath, "commit", "--amend", "--allow-rewrite-published", "-m", "amended"); err != nil {
+		t.Fatal(err)
+	}
+	if head, err := gitB.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if head.Commit == published.Commit {
+		t.Error("HEAD did not change after commit --amend --allow-rewrite-published")
+	}
+
+	// The amended commit has not been pushed, so amending it again is fine.
+	if _, err := env.gg(ctx, repoBPath, "commit", "--amend", "-m", "amended again"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCommit_TUI(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "changed\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "commit", "--tui", "--amend"); err == nil {
+		t.Error("gg commit --tui --amend succeeded")
+	} else if !isUsage(err) {
+		t.Errorf("gg commit --tui --amend error = %v; want usage error", err)
+	}
+	_, err = env.gg(ctx, env.root.String(), "commit", "--tui")
+	if err == nil {
+		t.Fatal("gg commit --tui succeeded without a terminal")
+	}
+	if !strings.Contains(err.Error(), "terminal") {
+		t.Errorf("error = %v; want to mention terminal", err)
+	}
+}
+
+func TestCommit_DetachedHead(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "checkout", "--quiet", "--detach", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "detached"); err != nil {
+		t.Fatal(err)
+	}
+	detached, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "commit " + detached.Commit.Short() + " is not on any branch"
+	if got := env.stderr.String(); !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Changed\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "kept", "--branch", "topic"); err != nil {
+		t.Fatal(err)
+	}
+	curr, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := git.BranchRef("topic"); curr.Ref != want {
+		t.Errorf("HEAD ref = %s; want %s", curr.Ref, want)
+	}
+	if parent, err := env.git.ParseRev(ctx, "topic~"); err != nil {
+		t.Error(err)
+	} else if parent.Commit != detached.Commit {
+		t.Errorf("topic~ = %v; want %v", parent.Commit, detached.Commit)
+	}
+}
+
+func TestCommit_Branch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	oldHead, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "feature", "--branch", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	curr, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := git.BranchRef("feature"); curr.Ref != want {
+		t.Errorf("HEAD ref = %s; want %s", curr.Ref, want)
+	}
+	if curr.Commit == oldHead.Commit {
+		t.Error("commit --branch did not create a commit")
+	}
+	if main, err := env.git.ParseRev(ctx, "main"); err != nil {
+		t.Error(err)
+	} else if main.Commit != oldHead.Commit {
+		t.Errorf("main = %v; want %v (unchanged)", main.Commit, oldHead.Commit)
+	}
+
+	// Creating a branch that already exists is an error.
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Changed\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "again", "--branch", "main"); err == nil {
+		t.Error("commit --branch main succeeded even though main exists")
+	}
+}
+
+func TestCommit_MessageCommand(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "new content\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.trackFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	// The editor leaves the suggested message as-is.
+	config := fmt.Sprintf("[core]\neditor = true\n[gg \"commit\"]\nmessageCommand = %s\n",
+		escape.GitConfig(`grep -q '^+new content' && printf '\nAdd foo.txt\n\n# dropped like other comments\n'`))
+	if err := env.writeConfig([]byte(config)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Add foo.txt\n"; info.Message != want {
+		t.Errorf("commit message = %q; want %q", info.Message, want)
+	}
+}
+
+func TestCommit_MessageCommandFails(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "new content\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.trackFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	const wantMessage = "Written in the editor\n"
+	editorCmd, err := env.editorCmd([]byte(wantMessage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf("[core]\neditor = %s\n[gg \"commit\"]\nmessageCommand = false\n",
+		escape.GitConfig(editorCmd))
+	if err := env.writeConfig([]byte(config)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Message != wantMessage {
+		t.Errorf("commit message = %q; want %q", info.Message, wantMessage)
+	}
+	if !strings.Contains(env.stderr.String(), "gg.commit.messageCommand") {
+		t.Errorf("stderr = %q; want a warning about gg.commit.messageCommand", env.stderr.String())
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+const commitGuardHelp = `
+
+	Before committing, gg checks the files being committed for leftover
+	conflict markers (lines starting with <<<<<<<, |||||||, or >>>>>>>).
+	Set ` + "`gg.commit.forbidPaths`" + ` to a space-separated list of glob
+	patterns like "*.orig *.swp .env" to also refuse files with matching
+	names, and ` + "`gg.commit.forbidContent`" + ` to a regular expression to
+	refuse files with a matching line, like a private key header. Patterns
+	without a slash match the file's base name. Pass ` + "`--no-verify`" + ` to
+	commit anyway.`
+
+// noVerifyUsage is the usage text for the --no-verify flag of commands
+// that commit.
+const noVerifyUsage = "skip the check for conflict markers and forbidden files"
+
+// A commitGuard checks files that are about to be committed.
+type commitGuard struct {
+	forbidPaths   []string
+	forbidContent *regexp.Regexp // may be nil
+}
+
+// A commitProblem is a reason to refuse to commit a file.
+type commitProblem struct {
+	name   git.TopPath
+	line   int // 0 if the problem is with the file's name
+	reason string
+}
+
+func (p commitProblem) String() string {
+	if p.line == 0 {
+		return fmt.Sprintf("%s: %s", p.name, p.reason)
+	}
+	return fmt.Sprintf("%s:%d: %s", p.name, p.line, p.reason)
+}
+
+func readCommitGuard(cfg *git.Config) (*commitGuard, error) {
+	guard := &commitGuard{
+		forbidPaths: strings.Fields(cfg.Value("gg.commit.forbidPaths")),
+	}
+	for _, pat := range guard.forbidPaths {
+		if _, err := path.Match(pat, ""); err != nil {
+			return nil, fmt.Errorf("gg.commit.forbidPaths: %q: %w", pat, err)
+		}
+	}
+	if expr := cfg.Value("gg.commit.forbidContent"); expr != "" {
+		var err error
+		guard.forbidContent, err = regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("gg.commit.forbidContent: %w", err)
+		}
+	}
+	return guard, nil
+}
+
+// conflictMarkers are the prefixes of the lines that Git writes around
+// conflicting hunks, besides the ambiguous "=======".
+var conflictMarkers = []string{"<<<<<<<", "|||||||", ">>>>>>>"}
+
+// checkCommitChanges checks the working copy files with changes that
+// match pathspecs (or all changes if pathspecs is empty).
+func checkCommitChanges(ctx context.Context, cc *cmdContext, pathspecs []git.Pathspec) error {
+	status, err := cc.git.Status(ctx, git.StatusOptions{
+		Pathspecs: pathspecs,
+	})
+	if err != nil {
+		return err
+	}
+	var names []git.TopPath
+	for _, ent := range status {
+		if ent.Code.IsAdded() || ent.Code.IsModified() || ent.Code.IsRenamed() || ent.Code.IsCopied() {
+			names = append(names, ent.Name)
+		}
+	}
+	return checkCommitFiles(ctx, cc, names)
+}
+
+// checkCommitFiles returns an error listing the problems with the
+// working copy versions of the given files, which are about to be
+// committed. Files that do not exist in the working copy are skipped.
+func checkCommitFiles(ctx context.Context, cc *cmdContext, names []git.TopPath) error {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	guard, err := readCommitGuard(cfg)
+	if err != nil {
+		return err
+	}
+	workTree, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	var problems []commitProblem
+	for _, name := range names {
+		problems = append(problems, guard.checkName(name)...)
+		f, err := os.Open(name.FromSlash(workTree))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			f.Close()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		p, err := guard.checkContent(name, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("check %s: %w", name, err)
+		}
+		problems = append(problems, p...)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sb := new(strings.Builder)
+	sb.WriteString("refusing to commit:")
+	for _, p := range problems {
+		sb.WriteString("\n\t")
+		sb.WriteString(p.String())
+	}
+	sb.WriteString("\n(pass --no-verify to commit anyway)")
+	return errors.New(sb.String())
+}
+
+// checkName returns a problem if the file's name matches one of the
+// forbidden path patterns.
+func (guard *commitGuard) checkName(name git.TopPath) []commitProblem {
+	for _, pat := range guard.forbidPaths {
+		subject := string(name)
+		if !strings.Contains(pat, "/") {
+			subject = path.Base(subject)
+		}
+		if ok, _ := path.Match(pat, subject); ok {
+			return []commitProblem{{name: name, reason: "matches forbidden path " + pat}}
+		}
+	}
+	return nil
+}
+
+// checkContent reads the file line by line and returns the conflict
+// markers and forbidden content found in it. Binary files, which
+// contain a NUL byte, are skipped.
+func (guard *commitGuard) checkContent(name git.TopPath, r io.Reader) ([]commitProblem, error) {
+	var problems []commitProblem
+	br := bufio.NewReader(r)
+	for lineno := 1; ; lineno++ {
+		line, err := br.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			// Very long lines are checked only at their start.
+			for errors.Is(err, bufio.ErrBufferFull) {
+				_, err = br.ReadSlice('\n')
+			}
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if bytes.IndexByte(line, 0) != -1 {
+			return nil, nil
+		}
+		for _, marker := range conflictMarkers {
+			if bytes.HasPrefix(line, []byte(marker)) {
+				problems = append(problems, commitProblem{name: name, line: lineno, reason: "conflict marker"})
+				break
+			}
+		}
+		if guard.forbidContent != nil && guard.forbidContent.Match(bytes.TrimSuffix(line, []byte("\n"))) {
+			problems = append(problems, commitProblem{name: name, line: lineno, reason: "matches gg.commit.forbidContent"})
+		}
+		if err == io.EOF {
+			return problems, nil
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/sigterm"
+)
+
+const commitMessageCommandHelp = `
+
+	If ` + "`gg.commit.messageCommand`" + ` is set to a shell command, gg runs it
+	before opening the editor with the diff of the changes being committed
+	on its standard input. Its output is placed in the editor as a
+	suggested message that you can keep, edit, or delete. If the command
+	fails, gg prints a warning and opens the editor without a suggestion.`
+
+// suggestCommitMessage runs the gg.commit.messageCommand command, if any,
+// with the diff of the changes to the files matched by pathspecs on its
+// stdin and returns its output. Failures are printed as warnings and
+// result in an empty suggestion, since they shouldn't prevent committing.
+func suggestCommitMessage(ctx context.Context, cc *cmdContext, cfg *git.Config, pathspecs []git.Pathspec) string {
+	line := cfg.Value("gg.commit.messageCommand")
+	if line == "" {
+		return ""
+	}
+	msg, err := runCommitMessageCommand(ctx, cc, line, pathspecs)
+	if err != nil {
+		fmt.Fprintf(cc.stderr, "gg: gg.commit.messageCommand: %v\n", err)
+		return ""
+	}
+	return msg
+}
+
+func runCommitMessageCommand(ctx context.Context, cc *cmdContext, line string, pathspecs []git.Pathspec) (string, error) {
+	diffArgs := []string{"diff", "--no-ext-diff", "--no-textconv", "--no-color"}
+	if _, err := cc.git.ParseRev(ctx, git.Head.String()); err == nil {
+		diffArgs = append(diffArgs, git.Head.String())
+	} else {
+		// Unborn branch: only the index has anything to compare.
+		diffArgs = append(diffArgs, "--cached")
+	}
+	diffArgs = append(diffArgs, "--")
+	for _, p := range pathspecs {
+		diffArgs = append(diffArgs, p.String())
+	}
+	diff, err := cc.git.Output(ctx, diffArgs...)
+	if err != nil {
+		return "", err
+	}
+
+	cmd, err := bashCommand(cc.git.Exe(), line)
+	if err != nil {
+		return "", err
+	}
+	cmd.Dir = cc.dir
+	cmd.Env = cc.env
+	if len(cmd.Env) == 0 {
+		cmd.Env = []string{} // force empty
+	}
+	stdout := new(bytes.Buffer)
+	cmd.Stdin = strings.NewReader(diff)
+	cmd.Stdout = stdout
+	cmd.Stderr = cc.stderr
+	if err := sigterm.Run(ctx, cmd); err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// writeSuggestedMessage writes msg to buf, preceded by a comment line
+// marking it as a suggestion. It writes nothing if msg is blank.
+func writeSuggestedMessage(buf *bytes.Buffer, msg string, commentChar string) {
+	msg = strings.TrimLeft(cleanupMessage(msg, commentChar), "\n")
+	if msg == "" {
+		return
+	}
+	buf.WriteString(commentChar)
+	buf.WriteString(" Suggested by gg.commit.messageCommand. Edit or delete it as needed.\n")
+	buf.WriteString(msg)
+}
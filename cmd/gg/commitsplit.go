@@ -0,0 +1,208 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// splitDirPlaceholder is replaced with a group's name in split commit messages.
+const splitDirPlaceholder = "{dir}"
+
+// A commitGroup is a set of changed files to be committed together by
+// commit --split-by-dir.
+type commitGroup struct {
+	name    string
+	changes []git.StatusEntry
+}
+
+// pathspecs returns pathspecs that match exactly the files in the group.
+func (grp *commitGroup) pathspecs() []git.Pathspec {
+	specs := make([]git.Pathspec, 0, len(grp.changes))
+	for _, ent := range grp.changes {
+		specs = append(specs, ent.Name.Pathspec())
+		if ent.From != "" {
+			specs = append(specs, ent.From.Pathspec())
+		}
+	}
+	return specs
+}
+
+// splitCommit creates one commit for each group of changes in the working
+// copy. If pats has no positional arguments or --include patterns, then
+// changes are grouped by top-level directory. Otherwise, each argument
+// forms its own group.
+func splitCommit(ctx context.Context, cc *cmdContext, msg string, pats *patternSet, dryRun, runHooks bool) error {
+	groups, err := splitCommitGroups(ctx, cc.git, pats)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return errors.New("nothing changed")
+	}
+	if dryRun {
+		for i, grp := range groups {
+			if i > 0 {
+				if _, err := fmt.Fprintln(cc.stdout); err != nil {
+					return err
+				}
+			}
+			if err := printCommitGroup(cc, grp, msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i, grp := range groups {
+		grpMsg := strings.ReplaceAll(msg, splitDirPlaceholder, grp.name)
+		draft := grp.name + ": \n"
+		if err := doCommit(ctx, cc, grpMsg, draft, grp.pathspecs(), runHooks); err != nil {
+			if i > 0 {
+				return fmt.Errorf("commit %s: %w (%d of %d commits created)", grp.name, err, i, len(groups))
+			}
+			return fmt.Errorf("commit %s: %w", grp.name, err)
+		}
+	}
+	return nil
+}
+
+// splitCommitGroups computes the groups of changes that splitCommit
+// would commit. Files matched by more than one argument are placed in
+// the first group that matches them.
+func splitCommitGroups(ctx context.Context, g *git.Git, pats *patternSet) ([]*commitGroup, error) {
+	if !pats.hasIncludes() {
+		exclusions, err := pats.pathspecs(ctx, g)
+		if err != nil {
+			return nil, err
+		}
+		st, err := splitCommitStatus(ctx, g, exclusions)
+		if err != nil {
+			return nil, err
+		}
+		var groups []*commitGroup
+		groupMap := make(map[string]*commitGroup)
+		for _, ent := range st {
+			name := "."
+			if i := strings.IndexByte(string(ent.Name), '/'); i != -1 {
+				name = string(ent.Name[:i])
+			}
+			grp := groupMap[name]
+			if grp == nil {
+				grp = &commitGroup{name: name}
+				groupMap[name] = grp
+				groups = append(groups, grp)
+			}
+			grp.changes = append(grp.changes, ent)
+		}
+		sort.Slice(groups, func(i, j int) bool {
+			return groups[i].name < groups[j].name
+		})
+		return groups, nil
+	}
+
+	var members []*patternSet
+	var names []string
+	for _, arg := range pats.args {
+		members = append(members, &patternSet{args: []string{arg}, excludes: pats.excludes})
+		names = append(names, arg)
+	}
+	for _, inc := range pats.includes {
+		members = append(members, &patternSet{includes: []string{inc}, excludes: pats.excludes})
+		names = append(names, inc)
+	}
+	var groups []*commitGroup
+	seen := make(map[git.TopPath]struct{})
+	for i, member := range members {
+		pathspecs, err := member.pathspecs(ctx, g)
+		if err != nil {
+			return nil, err
+		}
+		st, err := splitCommitStatus(ctx, g, pathspecs)
+		if err != nil {
+			return nil, err
+		}
+		grp := &commitGroup{name: names[i]}
+		for _, ent := range st {
+			if _, dup := seen[ent.Name]; dup {
+				continue
+			}
+			seen[ent.Name] = struct{}{}
+			grp.changes = append(grp.changes, ent)
+		}
+		if len(grp.changes) == 0 {
+			return nil, fmt.Errorf("%s: nothing changed", names[i])
+		}
+		groups = append(groups, grp)
+	}
+	return groups, nil
+}
+
+// splitCommitStatus returns the changes to tracked files matched by
+// pathspecs. It returns an error under the same conditions as a commit
+// would, so that no commits are created if any of them would fail.
+func splitCommitStatus(ctx context.Context, g *git.Git, pathspecs []git.Pathspec) ([]git.StatusEntry, error) {
+	st, err := g.Status(ctx, git.StatusOptions{
+		Pathspecs: pathspecs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := verifyNoMissingOrUnmerged(st); err != nil {
+		return nil, err
+	}
+	changes := st[:0]
+	for _, ent := range st {
+		if !ent.Code.IsUntracked() {
+			changes = append(changes, ent)
+		}
+	}
+	return changes, nil
+}
+
+// printCommitGroup prints a preview of the commit that splitCommit would
+// create for grp.
+func printCommitGroup(cc *cmdContext, grp *commitGroup, msg string) error {
+	header := "[" + grp.name + "]"
+	if msg != "" {
+		summary, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+		header += " " + strings.ReplaceAll(summary, splitDirPlaceholder, grp.name)
+	}
+	if _, err := fmt.Fprintln(cc.stdout, header); err != nil {
+		return err
+	}
+	changes := append([]git.StatusEntry(nil), grp.changes...)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	for _, ent := range changes {
+		verb := diffStatusVerb(statusIntoHeadDiffStatus(ent).Code)
+		if verb == "" {
+			// Missing files are removed, as in a commit without arguments.
+			verb = "removed"
+		}
+		if _, err := fmt.Fprintf(cc.stdout, "\t%s %s\n", verb, ent.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+// splitCommitTestRepo creates a repository with a commit and changes to
+// files in several directories. It returns the hash of the commit.
+func splitCommitTestRepo(ctx context.Context, env *testEnv) (git.Hash, error) {
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		return git.Hash{}, err
+	}
+	err := env.root.Apply(
+		filesystem.Write("README", dummyContent),
+		filesystem.Write("api/api.go", dummyContent),
+		filesystem.Write("docs/guide.md", dummyContent),
+		filesystem.Write("docs/old.md", dummyContent),
+	)
+	if err != nil {
+		return git.Hash{}, err
+	}
+	if err := env.addFiles(ctx, "README", "api/api.go", "docs/guide.md", "docs/old.md"); err != nil {
+		return git.Hash{}, err
+	}
+	r1, err := env.newCommit(ctx, ".")
+	if err != nil {
+		return git.Hash{}, err
+	}
+	err = env.root.Apply(
+		filesystem.Write("README", "Changed README\n"),
+		filesystem.Write("api/api.go", "Changed API\n"),
+		filesystem.Write("api/new.go", dummyContent),
+		filesystem.Write("docs/guide.md", "Changed guide\n"),
+		filesystem.Remove("docs/old.md"),
+		filesystem.Write("untracked.txt", dummyContent),
+	)
+	if err != nil {
+		return git.Hash{}, err
+	}
+	if err := env.trackFiles(ctx, "api/new.go"); err != nil {
+		return git.Hash{}, err
+	}
+	return r1, nil
+}
+
+// commitChanges returns the message and sorted list of changed files
+// for each commit after base up to HEAD, oldest first.
+func commitChanges(ctx context.Context, g *git.Git, base git.Hash) (msgs []string, files [][]git.TopPath, _ error) {
+	for rev := "HEAD"; ; rev += "~" {
+		r, err := g.ParseRev(ctx, rev)
+		if err != nil {
+			return nil, nil, err
+		}
+		if r.Commit == base {
+			break
+		}
+		info, err := g.CommitInfo(ctx, rev)
+		if err != nil {
+			return nil, nil, err
+		}
+		diff, err := g.DiffStatus(ctx, git.DiffStatusOptions{
+			Commit1: rev + "~",
+			Commit2: rev,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		var names []git.TopPath
+		for _, ent := range diff {
+			names = append(names, ent.Name)
+		}
+		sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+		msgs = append([]string{info.Message}, msgs...)
+		files = append([][]git.TopPath{names}, files...)
+	}
+	return msgs, files, nil
+}
+
+func TestCommit_SplitByDir(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1, err := splitCommitTestRepo(ctx, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "commit", "--split-by-dir", "-m", "{dir}: update"); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, files, err := commitChanges(ctx, env.git, r1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMsgs := []string{".: update\n", "api: update\n", "docs: update\n"}
+	if diff := cmp.Diff(wantMsgs, msgs); diff != "" {
+		t.Errorf("commit messages (-want +got):\n%s", diff)
+	}
+	wantFiles := [][]git.TopPath{
+		{"README"},
+		{"api/api.go", "api/new.go"},
+		{"docs/guide.md", "docs/old.md"},
+	}
+	if diff := cmp.Diff(wantFiles, files); diff != "" {
+		t.Errorf("committed files (-want +got):\n%s", diff)
+	}
+	if err := objectExists(ctx, env.git, "HEAD", "untracked.txt"); err == nil {
+		t.Error("untracked.txt was committed")
+	}
+}
+
+func TestCommit_SplitByDirArgs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1, err := splitCommitTestRepo(ctx, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// api/new.go matches both arguments, but is only committed in the first.
+	_, err = env.gg(ctx, env.root.String(), "commit", "--split-by-dir", "-m", "update {dir}",
+		"api/new.go", "api", "glob:docs/*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, files, err := commitChanges(ctx, env.git, r1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMsgs := []string{"update api/new.go\n", "update api\n", "update glob:docs/*.md\n"}
+	if diff := cmp.Diff(wantMsgs, msgs); diff != "" {
+		t.Errorf("commit messages (-want +got):\n%s", diff)
+	}
+	wantFiles := [][]git.TopPath{
+		{"api/new.go"},
+		{"api/api.go"},
+		{"docs/guide.md", "docs/old.md"},
+	}
+	if diff := cmp.Diff(wantFiles, files); diff != "" {
+		t.Errorf("committed files (-want +got):\n%s", diff)
+	}
+	// README was not named, so it should still be modified.
+	if data, err := catBlob(ctx, env.git, "HEAD", "README"); err != nil {
+		t.Error(err)
+	} else if string(data) != dummyContent {
+		t.Errorf("README in HEAD = %q; want %q", data, dummyContent)
+	}
+}
+
+func TestCommit_SplitByDirDryRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1, err := splitCommitTestRepo(ctx, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "commit", "--split-by-dir", "-n", "-m", "{dir}: update", "-X", "docs/old.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "[.] .: update\n" +
+		"\tmodified README\n" +
+		"\n" +
+		"[api] api: update\n" +
+		"\tmodified api/api.go\n" +
+		"\tadded api/new.go\n" +
+		"\n" +
+		"[docs] docs: update\n" +
+		"\tmodified docs/guide.md\n"
+	if diff := cmp.Diff(want, string(out)); diff != "" {
+		t.Errorf("output (-want +got):\n%s", diff)
+	}
+	if head, err := env.git.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if head.Commit != r1 {
+		t.Errorf("HEAD = %v; want %v (dry run should not commit)", head.Commit, r1)
+	}
+}
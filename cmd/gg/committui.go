@@ -0,0 +1,415 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// commitTUIHelp is the paragraph of commit's help text that describes
+// --tui.
+const commitTUIHelp = `
+
+	With ` + "`--tui`" + `, the changes are shown in a full-screen terminal
+	interface where files and hunks can be reviewed and selected before
+	the commit message is written. Changes that are not selected are left
+	in the working copy. The keys are:
+
+	  j, k          select the next or previous file or hunk
+	  enter         show or hide the hunks of a file
+	  space         include or exclude the selected file or hunk
+	  a, u          include or exclude all changes
+	  c             write the commit message; ctrl-d commits and
+	                esc goes back to the changes
+	  q             quit without committing`
+
+// commitTUIAction is the result of a key press in commit --tui.
+type commitTUIAction int
+
+const (
+	commitTUIContinue commitTUIAction = iota
+	commitTUIQuit
+	commitTUICommit
+)
+
+// A commitTUIRow is a line in the list of changes: a file, a hunk
+// header, or a line of a hunk.
+type commitTUIRow struct {
+	file *fileDiff
+	hunk *hunk // nil for the file's row
+	line int   // index into hunk.lines, or -1 for the hunk header
+}
+
+// selectable reports whether the cursor can be placed on the row.
+func (row commitTUIRow) selectable() bool {
+	return row.hunk == nil || row.line < 0
+}
+
+// commitTUIModel is the state of commit --tui's screen, independent of
+// the terminal.
+type commitTUIModel struct {
+	files    []*fileDiff
+	expanded map[*fileDiff]bool
+	rows     []commitTUIRow
+	cursor   int // index of the selected row
+	top      int // index of the first row on screen
+
+	editing bool // whether the message editor is shown
+	message string
+	status  string // message for the status line
+}
+
+func newCommitTUIModel(files []*fileDiff, msg string) *commitTUIModel {
+	m := &commitTUIModel{
+		files:    files,
+		expanded: make(map[*fileDiff]bool),
+		message:  msg,
+	}
+	for _, fd := range files {
+		fd.selectAll(true)
+	}
+	m.layoutRows()
+	return m
+}
+
+// layoutRows recomputes m.rows after a file is expanded or collapsed,
+// keeping the cursor on the same file or hunk.
+func (m *commitTUIModel) layoutRows() {
+	var curr commitTUIRow
+	if m.cursor < len(m.rows) {
+		curr = m.rows[m.cursor]
+	}
+	m.rows = m.rows[:0]
+	m.cursor = 0
+	for _, fd := range m.files {
+		if fd == curr.file && curr.hunk == nil {
+			m.cursor = len(m.rows)
+		}
+		m.rows = append(m.rows, commitTUIRow{file: fd, line: -1})
+		if !m.expanded[fd] {
+			continue
+		}
+		for _, h := range fd.hunks {
+			if h == curr.hunk {
+				m.cursor = len(m.rows)
+			}
+			m.rows = append(m.rows, commitTUIRow{file: fd, hunk: h, line: -1})
+			for i := range h.lines {
+				m.rows = append(m.rows, commitTUIRow{file: fd, hunk: h, line: i})
+			}
+		}
+	}
+	if curr.hunk != nil && !m.expanded[curr.file] {
+		// The hunk's file was collapsed: select the file.
+		for i, row := range m.rows {
+			if row.file == curr.file {
+				m.cursor = i
+				break
+			}
+		}
+	}
+}
+
+// move places the cursor delta files or hunks after (or before, if
+// negative) the current one, stopping at the first or last.
+func (m *commitTUIModel) move(delta int) {
+	step := 1
+	if delta < 0 {
+		step, delta = -1, -delta
+	}
+	for i := m.cursor + step; delta > 0 && i >= 0 && i < len(m.rows); i += step {
+		if m.rows[i].selectable() {
+			m.cursor = i
+			delta--
+		}
+	}
+}
+
+// handleKey updates the model for a key press. pageSize is the number
+// of rows that fit on the screen.
+func (m *commitTUIModel) handleKey(key, text string, pageSize int) commitTUIAction {
+	m.status = ""
+	if m.editing {
+		return m.handleEditorKey(key, text)
+	}
+	switch key {
+	case "q", keyInterrupt:
+		return commitTUIQuit
+	case "j", keyDown:
+		m.move(1)
+	case "k", keyUp:
+		m.move(-1)
+	case keyPageDown:
+		m.move(pageSize)
+	case keyPageUp:
+		m.move(-pageSize)
+	case "g", keyHome:
+		m.cursor = 0
+	case "G", keyEnd:
+		m.cursor = 0
+		m.move(len(m.rows))
+	case keyEnter:
+		if len(m.rows) == 0 {
+			break
+		}
+		fd := m.rows[m.cursor].file
+		m.expanded[fd] = !m.expanded[fd]
+		m.layoutRows()
+	case " ":
+		if len(m.rows) == 0 {
+			break
+		}
+		row := m.rows[m.cursor]
+		if row.hunk != nil {
+			row.hunk.selected = !row.hunk.selected
+		} else {
+			_, all := row.file.selection()
+			row.file.selectAll(!all)
+		}
+	case "a", "u":
+		for _, fd := range m.files {
+			fd.selectAll(key == "a")
+		}
+	case "c":
+		if len(selectedFiles(m.files)) == 0 {
+			m.status = "no changes selected"
+			break
+		}
+		m.editing = true
+	}
+	return commitTUIContinue
+}
+
+func (m *commitTUIModel) handleEditorKey(key, text string) commitTUIAction {
+	switch key {
+	case keyInterrupt:
+		return commitTUIQuit
+	case keyEscape:
+		m.editing = false
+	case keyEOF:
+		if strings.TrimSpace(m.message) == "" {
+			m.status = "commit message is empty"
+			break
+		}
+		return commitTUICommit
+	case keyEnter:
+		m.message += "\n"
+	case keyBackspace:
+		if m.message != "" {
+			runes := []rune(m.message)
+			m.message = string(runes[:len(runes)-1])
+		}
+	default:
+		m.message += text
+	}
+	return commitTUIContinue
+}
+
+// render returns the lines to display on a screen of the given size,
+// including terminal escape sequences for styles.
+func (m *commitTUIModel) render(width, height int) []string {
+	if height < 2 {
+		return nil
+	}
+	var lines []string
+	if m.editing {
+		lines = m.renderEditor(width, height-1)
+	} else {
+		lines = m.renderChanges(width, height-1)
+	}
+	status := m.status
+	if status == "" {
+		if m.editing {
+			status = "ctrl-d commit  esc back to changes  ctrl-c quit"
+		} else {
+			status = fmt.Sprintf("%d of %d files  space select  enter hunks  a all  u none  c commit  q quit",
+				len(selectedFiles(m.files)), len(m.files))
+		}
+	}
+	return append(lines, highlightLine(status, width))
+}
+
+func (m *commitTUIModel) renderChanges(width, height int) []string {
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if m.cursor >= m.top+height {
+		m.top = m.cursor - height + 1
+	}
+	lines := make([]string, 0, height+1)
+	for i := m.top; i < m.top+height; i++ {
+		if i >= len(m.rows) {
+			lines = append(lines, "")
+			continue
+		}
+		line := formatCommitTUIRow(m.rows[i], m.expanded[m.rows[i].file])
+		switch {
+		case i == m.cursor:
+			line = highlightLine(line, width)
+		case m.rows[i].hunk != nil && m.rows[i].line >= 0:
+			line = "        " + colorDiffLine(fitWidth(m.rows[i].hunk.lines[m.rows[i].line], width-8))
+		default:
+			line = fitWidth(line, width)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func formatCommitTUIRow(row commitTUIRow, expanded bool) string {
+	switch {
+	case row.hunk == nil:
+		some, all := row.file.selection()
+		mark := "[ ]"
+		if all {
+			mark = "[x]"
+		} else if some {
+			mark = "[~]"
+		}
+		fold := "+"
+		if expanded || len(row.file.hunks) == 0 {
+			fold = " "
+		}
+		return fmt.Sprintf("%s %s%s %s", mark, fold, row.file.name, countHunks(len(row.file.hunks)))
+	case row.line < 0:
+		mark := "[ ]"
+		if row.hunk.selected {
+			mark = "[x]"
+		}
+		return "    " + mark + " " + row.hunk.header
+	default:
+		return "        " + row.hunk.lines[row.line]
+	}
+}
+
+func countHunks(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return "(1 hunk)"
+	default:
+		return fmt.Sprintf("(%d hunks)", n)
+	}
+}
+
+func (m *commitTUIModel) renderEditor(width, height int) []string {
+	lines := make([]string, 0, height+1)
+	lines = append(lines, highlightLine("Commit message", width))
+	msgLines := strings.Split(m.message, "\n")
+	// Keep the end of the message, where the cursor is, on screen.
+	if avail := height - 1; len(msgLines) > avail {
+		msgLines = msgLines[len(msgLines)-avail:]
+	}
+	for i, line := range msgLines {
+		if i == len(msgLines)-1 {
+			lines = append(lines, fitWidth(line, width-1)+"\x1b[7m \x1b[m")
+		} else {
+			lines = append(lines, fitWidth(line, width))
+		}
+	}
+	if len(lines) < height {
+		lines = append(lines, "")
+	}
+	for _, fd := range m.files {
+		if len(lines) >= height {
+			break
+		}
+		some, all := fd.selection()
+		switch {
+		case all:
+			lines = append(lines, fitWidth("# "+string(fd.name), width))
+		case some:
+			lines = append(lines, fitWidth("# "+string(fd.name)+" (partial)", width))
+		}
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// commitTUI runs commit --tui.
+func commitTUI(ctx context.Context, cc *cmdContext, msg string, pathspecs []git.Pathspec, runHooks bool) error {
+	screen, err := newTUIScreen(cc)
+	if err != nil {
+		return fmt.Errorf("--tui %w", err)
+	}
+	if merging, err := cc.git.IsMerging(ctx); err != nil {
+		return err
+	} else if merging {
+		return errors.New("cannot select changes during a merge; run gg commit without --tui")
+	}
+	status, err := cc.git.Status(ctx, git.StatusOptions{
+		Pathspecs: pathspecs,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := verifyNoMissingOrUnmerged(status); err != nil {
+		return err
+	}
+	files, err := readWorkingDiff(ctx, cc.git, pathspecs)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New("nothing changed")
+	}
+
+	m := newCommitTUIModel(files, msg)
+	if err := screen.start(); err != nil {
+		return err
+	}
+	action, err := runCommitTUI(screen, m)
+	if stopErr := screen.stop(); err == nil {
+		err = stopErr
+	}
+	if err != nil {
+		return err
+	}
+	if action != commitTUICommit {
+		return errors.New("commit canceled")
+	}
+	return commitSelected(ctx, cc, files, cleanupMessage(m.message, ""), git.CommitOptions{
+		SkipHooks: !runHooks,
+	})
+}
+
+func runCommitTUI(screen *tuiScreen, m *commitTUIModel) (commitTUIAction, error) {
+	for {
+		width, height, err := screen.size()
+		if err != nil {
+			return commitTUIQuit, err
+		}
+		if err := screen.draw(m.render(width, height)); err != nil {
+			return commitTUIQuit, err
+		}
+		key, text, err := screen.readKey()
+		if err != nil {
+			return commitTUIQuit, err
+		}
+		if action := m.handleKey(key, text, height-1); action != commitTUIContinue {
+			return action, nil
+		}
+	}
+}
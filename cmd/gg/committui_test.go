@@ -0,0 +1,92 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "testing"
+
+func TestCommitTUIModel(t *testing.T) {
+	files, err := parseDiff(testDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newCommitTUIModel(files, "")
+	if got := len(selectedFiles(files)); got != 3 {
+		t.Errorf("initially %d files selected; want 3", got)
+	}
+	if len(m.rows) != 3 {
+		t.Fatalf("initially %d rows; want 3 (one per file)", len(m.rows))
+	}
+
+	press := func(keys ...string) commitTUIAction {
+		t.Helper()
+		action := commitTUIContinue
+		for _, k := range keys {
+			action = m.handleKey(k, k, 10)
+		}
+		return action
+	}
+
+	// Deselect everything, then expand foo.txt and select its second hunk.
+	press("u", keyEnter)
+	if len(m.rows) != 3+2+len(files[0].hunks[0].lines)+len(files[0].hunks[1].lines) {
+		t.Errorf("after expanding foo.txt, %d rows", len(m.rows))
+	}
+	press("j", "j", " ")
+	if files[0].hunks[0].selected || !files[0].hunks[1].selected {
+		t.Errorf("after selecting second hunk, selection = %t, %t; want false, true",
+			files[0].hunks[0].selected, files[0].hunks[1].selected)
+	}
+	if some, all := files[0].selection(); !some || all {
+		t.Errorf("foo.txt selection() = %t, %t; want true, false", some, all)
+	}
+
+	// Collapsing the file keeps the cursor on it.
+	press(keyEnter)
+	if len(m.rows) != 3 || m.rows[m.cursor].file != files[0] {
+		t.Errorf("after collapsing, rows = %d, cursor on %q; want 3 rows, cursor on foo.txt", len(m.rows), m.rows[m.cursor].file.name)
+	}
+
+	// Write the message and commit.
+	if action := press("c", "H", "i", keyEnter, keyEnter, "x", keyBackspace); action != commitTUIContinue {
+		t.Fatalf("action while editing = %v; want continue", action)
+	}
+	if m.message != "Hi\n\n" {
+		t.Errorf("message = %q; want \"Hi\n\n\"", m.message)
+	}
+	if action := press(keyEOF); action != commitTUICommit {
+		t.Errorf("action after ctrl-d = %v; want commit", action)
+	}
+}
+
+func TestCommitTUIModel_NothingSelected(t *testing.T) {
+	files, err := parseDiff(testDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newCommitTUIModel(files, "")
+	m.handleKey("u", "u", 10)
+	m.handleKey("c", "c", 10)
+	if m.editing {
+		t.Error("editing message with no changes selected")
+	}
+	if m.status == "" {
+		t.Error("no status message when nothing selected")
+	}
+	if action := m.handleKey("q", "q", 10); action != commitTUIQuit {
+		t.Errorf("action after q = %v; want quit", action)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"gg-scm.io/tool/internal/flag"
+)
+
+const configSynopsis = "query or change settings"
+
+// A configSetting is a named shorthand for a Git configuration variable
+// that gg knows how to validate.
+type configSetting struct {
+	name    string
+	gitName string
+	help    string
+	def     string   // value used when the variable is not set
+	values  []string // permitted values, nil for booleans
+}
+
+var configSettings = []*configSetting{
+	{
+		name:    "rerere",
+		gitName: "rerere.enabled",
+		help:    "record conflict resolutions and reuse them in later merges and rebases",
+		def:     "false",
+	},
+	{
+		name:    "conflict-style",
+		gitName: "merge.conflictStyle",
+		help:    "style of conflict markers written to files",
+		def:     "merge",
+		values:  conflictStyles,
+	},
+	{
+		name:    "color",
+		gitName: "color.ui",
+		help:    "when to use colors in output",
+		def:     "auto",
+		values:  []string{"auto", "always", "never"},
+	},
+	{
+		name:    "relative-paths",
+		gitName: "gg.relativePaths",
+		help:    "print file paths relative to the current directory",
+		def:     "false",
+	},
+	{
+		name:    "pull-update",
+		gitName: "gg.pullUpdate",
+		help:    "update to the new head after pulling, as if by pull -u",
+		def:     "false",
+	},
+	{
+		name:    "update-pick",
+		gitName: "gg.updatePick",
+		help:    "choose a branch from a list when update is run with no revision in a terminal",
+		def:     "false",
+	},
+	{
+		name:    "default-command",
+		gitName: "gg.defaultCommand",
+		help:    "what to show when gg is run without a command inside a repository",
+		def:     "overview",
+		values:  []string{"overview", "help"},
+	},
+	{
+		name:    "silence-warnings",
+		gitName: "gg.silenceWarnings",
+		help:    "hide the daily warnings about deprecated flags and settings and upcoming changes",
+		def:     "false",
+	},
+	{
+		name:    "read-only",
+		gitName: "gg.readOnly",
+		help:    "refuse to run commands that change the repository, as if by --read-only",
+		def:     "false",
+	},
+}
+
+func findConfigSetting(name string) *configSetting {
+	for _, setting := range configSettings {
+		if setting.name == name {
+			return setting
+		}
+	}
+	return nil
+}
+
+// normalize validates a value for the setting and returns its canonical form.
+func (setting *configSetting) normalize(value string) (string, error) {
+	if setting.values == nil {
+		switch strings.ToLower(value) {
+		case "on", "true", "yes", "1":
+			return "true", nil
+		case "off", "false", "no", "0":
+			return "false", nil
+		default:
+			return "", fmt.Errorf("%s: %q is not a boolean (use on or off)", setting.name, value)
+		}
+	}
+	for _, v := range setting.values {
+		if value == v {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("%s: unknown value %q (must be one of %s)", setting.name, value, strings.Join(setting.values, ", "))
+}
+
+func config(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg config [--global] [--unset] [NAME [VALUE]]", configSynopsis+`
+
+	With no arguments, list gg's settings and their current values.
+	With a NAME, print the setting's value. With a NAME and VALUE, change
+	the setting for the current repository.
+
+	NAME is either one of the settings listed below or a Git configuration
+	variable like `+"`user.email`"+`. Boolean settings accept on or off.
+
+	  rerere          record conflict resolutions and reuse them in later
+	                  merges and rebases (rerere.enabled)
+	  conflict-style  style of conflict markers written to files: merge,
+	                  diff3, or zdiff3 (merge.conflictStyle)
+	  color           when to use colors in output: auto, always, or
+	                  never (color.ui)
+	  relative-paths  print file paths in `+"`diff`"+`, `+"`status`"+`, and
+	                  `+"`untrack-changes --list`"+` relative to the current
+	                  directory instead of the top of the repository
+	                  (gg.relativePaths)
+	  pull-update     update to the new head after pulling, as if by
+	                  `+"`pull -u`"+` (gg.pullUpdate)
+	  update-pick     choose a branch from a list when `+"`update`"+` is run
+	                  with no revision in a terminal, as if by
+	                  `+"`update --pick`"+` (gg.updatePick)
+	  default-command what to show when gg is run without a command
+	                  inside a repository: overview or help
+	                  (gg.defaultCommand)
+	  silence-warnings
+	                  hide the warnings, shown at most once a day, about
+	                  deprecated flags and settings and upcoming changes
+	                  in behavior (gg.silenceWarnings)
+
+	Settings are stored in Git's configuration. See git-config(1).
+
+	Any setting can be overridden for a single invocation with an
+	environment variable named after it in upper case with a `+"`GG_`"+`
+	prefix, like `+"`GG_COLOR=never`"+` or `+"`GG_PULL_UPDATE=on`"+`. Other
+	top-level gg.* variables that gg reads can be set the same way with
+	the underscores removed, so `+"`GG_LARGE_FILE_SIZE`"+` sets
+	gg.largeFileSize. Any other `+"`GG_`"+` variable is ignored, and a
+	variable with an invalid value is skipped with a warning. From highest
+	to lowest precedence, gg uses flags given on the command line, `+"`GG_`"+`
+	environment variables, the repository's configuration, and then the
+	user's configuration.`)
+	global := f.Bool("global", false, "change the user's settings instead of the repository's")
+	unset := f.Bool("unset", false, "remove the setting")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 2 {
+		return usagef("too many arguments")
+	}
+	if *unset && f.NArg() != 1 {
+		return usagef("--unset takes a single NAME")
+	}
+	if f.NArg() == 0 {
+		if *global {
+			return usagef("--global requires a NAME and VALUE")
+		}
+		return listConfigSettings(ctx, cc)
+	}
+
+	name := f.Arg(0)
+	gitName := name
+	setting := findConfigSetting(name)
+	if setting != nil {
+		gitName = setting.gitName
+	} else if !strings.Contains(name, ".") {
+		return usagef("unknown setting %q", name)
+	}
+	configArgs := []string{"config"}
+	if *global {
+		configArgs = append(configArgs, "--global")
+	}
+	switch {
+	case *unset:
+		return cc.git.Run(ctx, append(configArgs, "--unset", "--", gitName)...)
+	case f.NArg() == 2:
+		value := f.Arg(1)
+		if setting != nil {
+			var err error
+			value, err = setting.normalize(value)
+			if err != nil {
+				return err
+			}
+		}
+		return cc.git.Run(ctx, append(configArgs, "--", gitName, value)...)
+	default:
+		if *global {
+			return usagef("--global requires a VALUE")
+		}
+		cfg, err := cc.git.ReadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		value := cfg.Value(gitName)
+		if value == "" {
+			if setting == nil {
+				return fmt.Errorf("%s is not set", name)
+			}
+			value = setting.def
+		}
+		_, err = fmt.Fprintln(cc.stdout, value)
+		return err
+	}
+}
+
+func listConfigSettings(ctx context.Context, cc *cmdContext) error {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, setting := range configSettings {
+		value := cfg.Value(setting.gitName)
+		if value == "" {
+			value = setting.def
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", setting.name, value, setting.help)
+	}
+	return tw.Flush()
+}
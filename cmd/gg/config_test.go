@@ -0,0 +1,135 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(name string) string {
+		t.Helper()
+		out, err := env.gg(ctx, env.root.String(), "config", name)
+		if err != nil {
+			t.Fatalf("gg config %s: %v", name, err)
+		}
+		return strings.TrimSuffix(string(out), "\n")
+	}
+	if got := get("rerere"); got != "false" {
+		t.Errorf("default rerere = %q; want \"false\"", got)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "config", "rerere", "on"); err != nil {
+		t.Fatal(err)
+	}
+	if got := get("rerere"); got != "true" {
+		t.Errorf("after setting on, rerere = %q; want \"true\"", got)
+	}
+	if got := get("rerere.enabled"); got != "true" {
+		t.Errorf("after setting on, rerere.enabled = %q; want \"true\"", got)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "config", "conflict-style", "zdiff3"); err != nil {
+		t.Fatal(err)
+	}
+	if got := get("conflict-style"); got != "zdiff3" {
+		t.Errorf("conflict-style = %q; want \"zdiff3\"", got)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"rerere", "true", "conflict-style", "zdiff3"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("gg config output = %q; want to contain %q", out, want)
+		}
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "config", "--unset", "conflict-style"); err != nil {
+		t.Fatal(err)
+	}
+	if got := get("conflict-style"); got != "merge" {
+		t.Errorf("after unset, conflict-style = %q; want \"merge\"", got)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "config", "conflict-style", "bogus"); err == nil {
+		t.Error("gg config conflict-style bogus did not return an error")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "config", "rerere", "maybe"); err == nil {
+		t.Error("gg config rerere maybe did not return an error")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "config", "bogus"); err == nil {
+		t.Error("gg config bogus did not return an error")
+	} else if !isUsage(err) {
+		t.Errorf("gg config bogus error = %v; want usage", err)
+	}
+}
+
+func TestConfig_Env(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "config", "conflict-style", "zdiff3"); err != nil {
+		t.Fatal(err)
+	}
+
+	env.environ = []string{
+		"GG_CONFLICT_STYLE=diff3",
+		"GG_LARGE_FILE_SIZE=5m",
+	}
+	for _, test := range []struct {
+		name string
+		want string
+	}{
+		{name: "conflict-style", want: "diff3"},
+		{name: "merge.conflictStyle", want: "diff3"},
+		{name: "gg.largeFileSize", want: "5m"},
+	} {
+		out, err := env.gg(ctx, env.root.String(), "config", test.name)
+		if err != nil {
+			t.Errorf("gg config %s: %v", test.name, err)
+			continue
+		}
+		if got := strings.TrimSuffix(string(out), "\n"); got != test.want {
+			t.Errorf("gg config %s = %q; want %q", test.name, got, test.want)
+		}
+	}
+
+	env.environ = []string{"GG_COLOR=sometimes"}
+	if _, err := env.gg(ctx, env.root.String(), "config", "color"); err == nil {
+		t.Error("gg with GG_COLOR=sometimes did not return an error")
+	} else if !strings.Contains(err.Error(), "GG_COLOR") {
+		t.Errorf("gg with GG_COLOR=sometimes error = %v; want to mention GG_COLOR", err)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+// conflictStyles is the set of values accepted by --conflict-style.
+// See merge.conflictStyle in git-config(1).
+var conflictStyles = []string{"merge", "diff3", "zdiff3"}
+
+// addConflictStyleFlag registers the --conflict-style flag on f.
+func addConflictStyleFlag(f *flag.FlagSet) *string {
+	return f.String("conflict-style", "", "conflict marker `style` for this operation: "+strings.Join(conflictStyles, ", "))
+}
+
+// withConflictStyle returns a copy of cc that writes conflict markers in
+// the given style. An empty style uses the repository's configuration.
+func withConflictStyle(cc *cmdContext, style string) (*cmdContext, error) {
+	if style == "" {
+		return cc, nil
+	}
+	for _, s := range conflictStyles {
+		if style == s {
+			return cc.withGitConfig("merge.conflictStyle", style)
+		}
+	}
+	return nil, usagef("unknown conflict style %q (must be one of %s)", style, strings.Join(conflictStyles, ", "))
+}
+
+// rerereEnabled reports whether Git records and reuses conflict
+// resolutions in the repository. See git-rerere(1).
+func rerereEnabled(ctx context.Context, g *git.Git, cfg *git.Config) (bool, error) {
+	if cfg.Value("rerere.enabled") != "" {
+		return cfg.Bool("rerere.enabled")
+	}
+	// Without the setting, rerere is enabled if its cache directory exists.
+	gitDir, err := g.CommonDir(ctx)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(filepath.Join(gitDir, "rr-cache"))
+	return err == nil && info.IsDir(), nil
+}
+
+// reusedResolutions returns the conflicted files that Git resolved
+// using a recorded resolution.
+func reusedResolutions(ctx context.Context, g *git.Git) ([]git.TopPath, error) {
+	cfg, err := g.ReadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if enabled, err := rerereEnabled(ctx, g, cfg); err != nil || !enabled {
+		return nil, err
+	}
+	st, err := g.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out, err := g.Output(ctx, "rerere", "remaining")
+	if err != nil {
+		return nil, err
+	}
+	remaining := make(map[string]struct{})
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" {
+			remaining[line] = struct{}{}
+		}
+	}
+	var reused []git.TopPath
+	for _, ent := range st {
+		if !ent.Code.IsUnmerged() {
+			continue
+		}
+		if _, unresolved := remaining[string(ent.Name)]; !unresolved {
+			reused = append(reused, ent.Name)
+		}
+	}
+	return reused, nil
+}
+
+// reportReusedResolutions prints a notice for each conflicted file that
+// Git resolved using a recorded resolution. Errors are ignored, since
+// this is only advisory.
+func reportReusedResolutions(ctx context.Context, cc *cmdContext) {
+	reused, err := reusedResolutions(ctx, cc.git)
+	if err != nil {
+		return
+	}
+	for _, name := range reused {
+		fmt.Fprintf(cc.stderr, "gg: resolution reused for %s; review it, then mark it resolved with `gg add`\n", name)
+	}
+}
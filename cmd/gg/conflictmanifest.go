@@ -0,0 +1,190 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gg-scm.io/pkg/git"
+)
+
+// conflictManifestPath is the path of the conflict manifest relative to
+// the Git directory of the working copy.
+const conflictManifestPath = "gg/conflicts.json"
+
+// A conflictManifest describes the operation in progress and its
+// conflicted files for editor plugins. gg rewrites it after each command
+// that can create or clear conflicts while an operation is in progress
+// and removes it afterward.
+type conflictManifest struct {
+	Operation string `json:"operation"`
+	Step      int    `json:"step,omitempty"`
+	Total     int    `json:"total,omitempty"`
+	Branch    string `json:"branch,omitempty"`
+	Onto      string `json:"onto,omitempty"`
+	Current   string `json:"current,omitempty"`
+	Summary   string `json:"summary,omitempty"`
+	Command   string `json:"command,omitempty"`
+
+	// Files lists the files that still have conflicts.
+	Files []*conflictManifestFile `json:"files"`
+	// Resolved lists the files that had conflicts earlier in the
+	// operation and have since been marked as resolved.
+	Resolved []string `json:"resolved"`
+}
+
+// A conflictManifestFile is a conflicted file and the blob hashes of its
+// versions. A version is omitted if it doesn't exist: for example, the
+// file was added on both sides, so it has no base, or it was deleted on
+// one side.
+type conflictManifestFile struct {
+	Path  string `json:"path"`
+	Base  string `json:"base,omitempty"`
+	Local string `json:"local,omitempty"`
+	Other string `json:"other,omitempty"`
+}
+
+// buildConflictManifest returns the manifest for the operation in
+// progress or nil if there is none. prev is the manifest written
+// earlier, if any, and is used to list resolved files.
+func buildConflictManifest(ctx context.Context, g *git.Git, prev *conflictManifest) (*conflictManifest, error) {
+	op, err := readOperationState(ctx, g)
+	if err != nil || op == nil {
+		return nil, err
+	}
+	m := &conflictManifest{
+		Operation: op.kind,
+		Step:      op.step,
+		Total:     op.total,
+		Branch:    op.branch,
+		Onto:      op.onto,
+		Current:   op.current,
+		Summary:   op.summary,
+		Command:   op.command,
+		Files:     []*conflictManifestFile{},
+		Resolved:  []string{},
+	}
+	conflicted := make(map[string]bool)
+	if len(op.conflicts) > 0 {
+		all := git.JoinPathspecMagic(git.PathspecMagic{Top: true}, "")
+		files, err := unmergedFiles(ctx, g, []git.Pathspec{all})
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			mf := &conflictManifestFile{
+				Path:  f.name.String(),
+				Base:  manifestHash(f.base),
+				Local: manifestHash(f.local),
+				Other: manifestHash(f.other),
+			}
+			m.Files = append(m.Files, mf)
+			conflicted[mf.Path] = true
+		}
+	}
+	if prev != nil && prev.Operation == m.Operation && prev.Current == m.Current {
+		seen := make(map[string]bool)
+		for _, f := range prev.Files {
+			if !conflicted[f.Path] && !seen[f.Path] {
+				m.Resolved = append(m.Resolved, f.Path)
+				seen[f.Path] = true
+			}
+		}
+		for _, path := range prev.Resolved {
+			if !conflicted[path] && !seen[path] {
+				m.Resolved = append(m.Resolved, path)
+				seen[path] = true
+			}
+		}
+		sort.Strings(m.Resolved)
+	}
+	return m, nil
+}
+
+// printConflictManifest updates the conflict manifest and writes it to
+// stdout.
+func printConflictManifest(ctx context.Context, cc *cmdContext) error {
+	m, err := refreshConflictManifest(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return errors.New("no operation in progress")
+	}
+	enc := json.NewEncoder(cc.stdout)
+	enc.SetIndent("", "\t")
+	return enc.Encode(m)
+}
+
+func manifestHash(h git.Hash) string {
+	if h == (git.Hash{}) {
+		return ""
+	}
+	return h.String()
+}
+
+// refreshConflictManifest writes the conflict manifest for the operation
+// in progress or removes it if there is none. It returns the manifest
+// written, if any.
+func refreshConflictManifest(ctx context.Context, g *git.Git) (*conflictManifest, error) {
+	gitDir, err := g.GitDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(gitDir, filepath.FromSlash(conflictManifestPath))
+	var prev *conflictManifest
+	if data, err := os.ReadFile(path); err == nil {
+		prev = new(conflictManifest)
+		if json.Unmarshal(data, prev) != nil {
+			prev = nil
+		}
+	}
+	m, err := buildConflictManifest(ctx, g, prev)
+	if err != nil {
+		return nil, fmt.Errorf("update conflict manifest: %w", err)
+	}
+	if m == nil {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("update conflict manifest: %w", err)
+		}
+		return nil, nil
+	}
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return nil, fmt.Errorf("update conflict manifest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return nil, fmt.Errorf("update conflict manifest: %w", err)
+	}
+	// Write to a temporary file and rename it so that editors never see
+	// a partially written manifest.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o666); err != nil {
+		return nil, fmt.Errorf("update conflict manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("update conflict manifest: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gg-scm.io/pkg/git/object"
+)
+
+// defaultMaxDateSkew is the default for the gg.maxDateSkew setting.
+const defaultMaxDateSkew = time.Hour
+
+const dateSkewHelp = `
+
+	gg warns if the new commit's author or commit date is more than an
+	hour away from the current time, or if its commit date is more than
+	an hour before its parent's. This usually means the system clock is
+	wrong, which later confuses tools that order commits by date. Set
+	` + "`gg.maxDateSkew`" + ` to a duration like "10m" or "1d" to change the
+	threshold, or to "0" to turn off the warning.`
+
+// warnDateSkew prints a warning if the dates of the commit at HEAD are
+// far from the current time or from its parents' dates. amend indicates
+// that the commit was amended and thus kept its original author date.
+func warnDateSkew(ctx context.Context, cc *cmdContext, amend bool) {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return
+	}
+	maxSkew, err := parseDateSkew(cfg.Value("gg.maxDateSkew"))
+	if err != nil {
+		fmt.Fprintf(cc.stderr, "gg: warning: gg.maxDateSkew: %v\n", err)
+		return
+	}
+	if maxSkew == 0 {
+		return
+	}
+	c, err := cc.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		return
+	}
+	var parentTime time.Time
+	for _, p := range c.Parents {
+		pc, err := cc.git.CommitInfo(ctx, p.String())
+		if err != nil {
+			return
+		}
+		if pc.CommitTime.After(parentTime) {
+			parentTime = pc.CommitTime
+		}
+	}
+	for _, w := range dateSkewWarnings(c, parentTime, time.Now(), maxSkew, amend) {
+		fmt.Fprintf(cc.stderr, "gg: warning: %s\n", w)
+	}
+}
+
+// dateSkewWarnings returns the problems with c's dates, given the latest
+// commit date of its parents (zero if it has none) and the current time.
+func dateSkewWarnings(c *object.Commit, parentTime, now time.Time, maxSkew time.Duration, amend bool) []string {
+	var warnings []string
+	check := func(what string, t time.Time, checkPast bool) {
+		if d := t.Sub(now); d > maxSkew {
+			warnings = append(warnings, fmt.Sprintf("%s date %s is %s in the future; check the system clock and GIT_%s_DATE",
+				what, t.Format(dateSkewLayout), formatSkew(d), strings.ToUpper(what)))
+		} else if d := now.Sub(t); checkPast && d > maxSkew {
+			warnings = append(warnings, fmt.Sprintf("%s date %s is %s in the past; check the system clock and GIT_%s_DATE",
+				what, t.Format(dateSkewLayout), formatSkew(d), strings.ToUpper(what)))
+		}
+	}
+	// An amended commit keeps its author date, so only a future date is suspect.
+	check("author", c.AuthorTime, !amend)
+	check("committer", c.CommitTime, true)
+	if !parentTime.IsZero() {
+		if d := parentTime.Sub(c.CommitTime); d > maxSkew {
+			warnings = append(warnings, fmt.Sprintf("commit date %s is %s before its parent's (%s); "+
+				"the clock on this machine or the one that made the parent is wrong",
+				c.CommitTime.Format(dateSkewLayout), formatSkew(d), parentTime.Format(dateSkewLayout)))
+		}
+	}
+	return warnings
+}
+
+const dateSkewLayout = "2006-01-02 15:04:05 -0700"
+
+// parseDateSkew parses the gg.maxDateSkew setting. It accepts Go
+// durations plus a "d" suffix for days. An empty string yields the
+// default and "0" disables the check.
+func parseDateSkew(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "":
+		return defaultMaxDateSkew, nil
+	case "0", "false", "off":
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// formatSkew formats a duration in the largest whole unit that fits,
+// like "3 days" or "45 minutes".
+func formatSkew(d time.Duration) string {
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return "1 " + unit
+		}
+		return strconv.FormatInt(n, 10) + " " + unit + "s"
+	}
+	switch {
+	case d >= 48*time.Hour:
+		return plural(int64(d/(24*time.Hour)), "day")
+	case d >= 2*time.Hour:
+		return plural(int64(d/time.Hour), "hour")
+	case d >= 2*time.Minute:
+		return plural(int64(d/time.Minute), "minute")
+	default:
+		return plural(int64(d/time.Second), "second")
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gg-scm.io/pkg/git/object"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestDateSkewWarnings(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		authorTime time.Time
+		commitTime time.Time
+		parentTime time.Time
+		amend      bool
+		want       []string
+	}{
+		{
+			name:       "Normal",
+			authorTime: now,
+			commitTime: now,
+			parentTime: now.Add(-24 * time.Hour),
+		},
+		{
+			name:       "SmallSkew",
+			authorTime: now.Add(30 * time.Minute),
+			commitTime: now.Add(-30 * time.Minute),
+			parentTime: now.Add(20 * time.Minute),
+		},
+		{
+			name:       "AuthorFuture",
+			authorTime: now.Add(72 * time.Hour),
+			commitTime: now,
+			want:       []string{"author date 2026-03-04 12:00:00 +0000 is 3 days in the future"},
+		},
+		{
+			name:       "CommitterPast",
+			authorTime: now,
+			commitTime: now.Add(-5 * time.Hour),
+			want:       []string{"committer date 2026-03-01 07:00:00 +0000 is 5 hours in the past"},
+		},
+		{
+			name:       "AmendKeepsAuthorDate",
+			authorTime: now.Add(-30 * 24 * time.Hour),
+			commitTime: now,
+			amend:      true,
+		},
+		{
+			name:       "AuthorPast",
+			authorTime: now.Add(-30 * 24 * time.Hour),
+			commitTime: now,
+			want:       []string{"author date 2026-01-30 12:00:00 +0000 is 30 days in the past"},
+		},
+		{
+			name:       "ParentFuture",
+			authorTime: now,
+			commitTime: now,
+			parentTime: now.Add(90 * time.Minute),
+			want:       []string{"commit date 2026-03-01 12:00:00 +0000 is 90 minutes before its parent's"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &object.Commit{
+				AuthorTime: test.authorTime,
+				CommitTime: test.commitTime,
+			}
+			got := dateSkewWarnings(c, test.parentTime, now, time.Hour, test.amend)
+			if len(got) != len(test.want) {
+				t.Fatalf("dateSkewWarnings(...) = %q; want %d warnings starting with %q", got, len(test.want), test.want)
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], test.want[i]) {
+					t.Errorf("warning[%d] = %q; want prefix %q", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseDateSkew(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{s: "", want: defaultMaxDateSkew},
+		{s: "0", want: 0},
+		{s: "off", want: 0},
+		{s: "10m", want: 10 * time.Minute},
+		{s: "2d", want: 48 * time.Hour},
+		{s: "-1h", wantErr: true},
+		{s: "soon", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := parseDateSkew(test.s)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("parseDateSkew(%q) = _, %v; want %v, <nil>", test.s, err, test.want)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("parseDateSkew(%q) = %v, <nil>; want error", test.s, got)
+		} else if got != test.want {
+			t.Errorf("parseDateSkew(%q) = %v, <nil>; want %v, <nil>", test.s, got, test.want)
+		}
+	}
+}
+
+func TestCommit_DateSkewWarning(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	// Amending keeps the author date, so a future author date carries over.
+	future := time.Now().Add(72 * time.Hour).Format(time.RFC3339)
+	if err := env.git.Run(ctx, "commit", "--quiet", "--amend", "--no-edit", "--date="+future); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.trackFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "--amend", "-m", "amended"); err != nil {
+		t.Fatal(err)
+	}
+	const want = "gg: warning: author date "
+	if got := env.stderr.String(); !strings.Contains(got, want) || !strings.Contains(got, "in the future") {
+		t.Errorf("stderr = %q; want author date warning", got)
+	}
+
+	// Disabling the warning.
+	env.stderr.Reset()
+	if err := env.git.Run(ctx, "config", "gg.maxDateSkew", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Changed!\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "--amend", "-m", "amended again"); err != nil {
+		t.Fatal(err)
+	}
+	if got := env.stderr.String(); strings.Contains(got, "date") {
+		t.Errorf("with gg.maxDateSkew=0, stderr = %q; want no date warning", got)
+	}
+}
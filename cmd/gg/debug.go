@@ -0,0 +1,374 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/pkg/git/githash"
+	"gg-scm.io/pkg/git/object"
+	"gg-scm.io/tool/internal/flag"
+	"gg-scm.io/tool/internal/gitrepo"
+	"gg-scm.io/tool/internal/repocache"
+)
+
+const debugSynopsis = "show gg internals for troubleshooting"
+
+func debug(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg debug obj [--raw] OBJECT\n"+
+		"gg debug refs\n"+
+		"gg debug cache [--sync]", debugSynopsis+`
+
+	These commands are meant for troubleshooting gg itself and for
+	including in bug reports. Their output may change between releases.
+
+	`+"`gg debug obj`"+` prints the type and size of a Git object, like
+	`+"`HEAD`"+` or `+"`HEAD:README.md`"+`, followed by its content. Trees are
+	printed one entry per line. The object is read from gg's repository
+	cache if it is there and from Git otherwise; the "source" line says
+	which. `+"`--raw`"+` prints only the object's content.
+
+	`+"`gg debug refs`"+` lists every ref in the repository with the object it
+	points to, including the refs gg keeps for its own bookkeeping, which
+	are marked "gg". Annotated tags are followed by a line for the object
+	they point to, ending in `+"`^{}`"+`.
+
+	`+"`gg debug cache`"+` shows where gg's repository cache is, what it
+	contains, and whether it holds every branch and tag. `+"`--sync`"+` copies
+	any missing objects into the cache first.`)
+	raw := f.Bool("raw", false, "with obj, print only the object content")
+	sync := f.Bool("sync", false, "with cache, update the cache first")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	sub := f.Arg(0)
+	if *raw && sub != "obj" {
+		return usagef("--raw can only be used with obj")
+	}
+	if *sync && sub != "cache" {
+		return usagef("--sync can only be used with cache")
+	}
+	switch sub {
+	case "obj":
+		if f.NArg() != 2 {
+			return usagef("obj takes a single OBJECT")
+		}
+		return debugObject(ctx, cc, f.Arg(1), *raw)
+	case "refs":
+		if f.NArg() > 1 {
+			return usagef("refs takes no arguments")
+		}
+		return debugRefs(ctx, cc)
+	case "cache":
+		if f.NArg() > 1 {
+			return usagef("cache takes no arguments")
+		}
+		return debugCache(ctx, cc, *sync)
+	case "":
+		return usagef("must pass a subcommand (obj, refs, or cache)")
+	default:
+		return usagef("unknown subcommand %q", sub)
+	}
+}
+
+func debugObject(ctx context.Context, cc *cmdContext, name string, raw bool) error {
+	if strings.HasPrefix(name, "-") {
+		return usagef("object name must not start with '-'")
+	}
+	out, err := cc.git.Output(ctx, "rev-parse", "--verify", "--quiet", name)
+	if err != nil {
+		return fmt.Errorf("%s: no such object", name)
+	}
+	id, err := git.ParseHash(strings.TrimSuffix(out, "\n"))
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+
+	commonDir, err := cc.git.CommonDir(ctx)
+	if err != nil {
+		return err
+	}
+	r := &debugObjectReader{git: gitObjectReader{cc.git}}
+	if _, err := os.Stat(filepath.Join(commonDir, repoCacheFileName)); err == nil {
+		cache, err := openRepoCache(ctx, commonDir, false)
+		if err != nil {
+			return err
+		}
+		defer cache.Close()
+		r.cache = cache
+	}
+	prefix, rc, err := r.OpenObject(ctx, id)
+	if err != nil {
+		return err
+	}
+	content, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return err
+	}
+	if raw {
+		_, err := cc.stdout.Write(content)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 1, ' ', 0)
+	fmt.Fprintf(tw, "object\t%v\n", id)
+	fmt.Fprintf(tw, "type\t%v\n", prefix.Type)
+	fmt.Fprintf(tw, "size\t%d\n", prefix.Size)
+	fmt.Fprintf(tw, "source\t%s\n", r.source)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(cc.stdout); err != nil {
+		return err
+	}
+	if prefix.Type != object.TypeTree {
+		_, err := cc.stdout.Write(content)
+		return err
+	}
+	tree, err := object.ParseTree(content)
+	if err != nil {
+		return fmt.Errorf("%v: %v", id, err)
+	}
+	for _, ent := range tree {
+		_, err := fmt.Fprintf(cc.stdout, "%06o %v %v\t%s\n", uint32(ent.Mode), treeEntryType(ent.Mode), ent.ObjectID, ent.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// treeEntryType returns the type of object that a tree entry with the
+// given mode refers to.
+func treeEntryType(mode object.Mode) object.Type {
+	switch mode {
+	case object.ModeDir:
+		return object.TypeTree
+	case object.ModeGitlink:
+		return object.TypeCommit
+	default:
+		return object.TypeBlob
+	}
+}
+
+// debugObjectReader is a [gitrepo.ObjectReader] that reads objects from
+// the repository cache if present, falling back to Git. It records where
+// the last object came from.
+type debugObjectReader struct {
+	cache *repocache.Cache // may be nil
+	git   gitrepo.ObjectReader
+
+	source string
+}
+
+func (r *debugObjectReader) OpenObject(ctx context.Context, id githash.SHA1) (object.Prefix, io.ReadCloser, error) {
+	if r.cache != nil {
+		prefix, rc, err := r.cache.OpenObject(ctx, id)
+		if err == nil {
+			r.source = "cache"
+			return prefix, rc, nil
+		}
+		if !errors.Is(err, repocache.ErrObjectNotFound) {
+			return object.Prefix{}, nil, err
+		}
+	}
+	r.source = "git"
+	return r.git.OpenObject(ctx, id)
+}
+
+func (r *debugObjectReader) Stat(ctx context.Context, id githash.SHA1) (object.Prefix, error) {
+	if r.cache != nil {
+		prefix, err := r.cache.Stat(ctx, id)
+		if err == nil {
+			r.source = "cache"
+			return prefix, nil
+		}
+		if !errors.Is(err, repocache.ErrObjectNotFound) {
+			return object.Prefix{}, err
+		}
+	}
+	r.source = "git"
+	return r.git.Stat(ctx, id)
+}
+
+// gitObjectReader is a [gitrepo.ObjectReader] that reads objects with
+// git cat-file. It reads each object into memory.
+type gitObjectReader struct {
+	git *git.Git
+}
+
+func (r gitObjectReader) OpenObject(ctx context.Context, id githash.SHA1) (object.Prefix, io.ReadCloser, error) {
+	prefix, err := r.Stat(ctx, id)
+	if err != nil {
+		return object.Prefix{}, nil, err
+	}
+	content, err := r.git.Output(ctx, "cat-file", string(prefix.Type), id.String())
+	if err != nil {
+		return object.Prefix{}, nil, fmt.Errorf("read git object %v: %w", id, err)
+	}
+	if int64(len(content)) != prefix.Size {
+		return object.Prefix{}, nil, fmt.Errorf("read git object %v: got %d bytes (expected %d)", id, len(content), prefix.Size)
+	}
+	h := sha1.New()
+	h.Write(object.AppendPrefix(nil, prefix.Type, prefix.Size))
+	io.WriteString(h, content)
+	var got githash.SHA1
+	h.Sum(got[:0])
+	if got != id {
+		return object.Prefix{}, nil, fmt.Errorf("read git object %v: corrupted content (hash = %v)", id, got)
+	}
+	return prefix, io.NopCloser(strings.NewReader(content)), nil
+}
+
+func (r gitObjectReader) Stat(ctx context.Context, id githash.SHA1) (object.Prefix, error) {
+	tp, err := r.git.Output(ctx, "cat-file", "-t", id.String())
+	if err != nil {
+		return object.Prefix{}, fmt.Errorf("read git object %v: %w", id, err)
+	}
+	size, err := r.git.Output(ctx, "cat-file", "-s", id.String())
+	if err != nil {
+		return object.Prefix{}, fmt.Errorf("read git object %v: %w", id, err)
+	}
+	prefix := object.Prefix{Type: object.Type(strings.TrimSuffix(tp, "\n"))}
+	prefix.Size, err = strconv.ParseInt(strings.TrimSuffix(size, "\n"), 10, 64)
+	if err != nil || !prefix.Type.IsValid() {
+		return object.Prefix{}, fmt.Errorf("read git object %v: unexpected cat-file output %q %q", id, tp, size)
+	}
+	return prefix, nil
+}
+
+// isGGRef reports whether ref is one that gg creates for its own
+// bookkeeping rather than one the user made.
+func isGGRef(ref git.Ref) bool {
+	return strings.HasPrefix(ref.String(), "refs/gg/") ||
+		strings.HasPrefix(ref.String(), "refs/gg-old/") ||
+		strings.HasPrefix(ref.String(), "refs/ggpull/")
+}
+
+func debugRefs(ctx context.Context, cc *cmdContext) error {
+	iter := cc.git.IterateRefs(ctx, git.IterateRefsOptions{
+		IncludeHead:     true,
+		DereferenceTags: true,
+	})
+	defer iter.Close()
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for iter.Next() {
+		name := iter.Ref().String()
+		if iter.IsDereference() {
+			name += "^{}"
+		}
+		marker := ""
+		if isGGRef(iter.Ref()) {
+			marker = "gg"
+		}
+		fmt.Fprintf(tw, "%v\t%s\t%s\n", iter.ObjectSHA1(), name, marker)
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+func debugCache(ctx context.Context, cc *cmdContext, sync bool) error {
+	commonDir, err := cc.git.CommonDir(ctx)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(commonDir, repoCacheFileName)
+	if !sync {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			_, err := fmt.Fprintf(cc.stdout, "no cache at %s (run 'gg debug cache --sync' to create it)\n", path)
+			return err
+		}
+	}
+	cache, err := openRepoCache(ctx, commonDir, sync)
+	if err != nil {
+		return err
+	}
+	defer cache.Close()
+	stats, err := cache.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "path\t%s\n", path)
+	fmt.Fprintf(tw, "file size\t%s\n", formatFileSize(info.Size()))
+	fmt.Fprintf(tw, "modified\t%s\n", info.ModTime().Format(time.RFC3339))
+	for _, tp := range []object.Type{object.TypeCommit, object.TypeTree, object.TypeBlob, object.TypeTag} {
+		ts := stats.Objects[tp]
+		fmt.Fprintf(tw, "%vs\t%d (%s, %s stored)\n", tp, ts.Count, formatFileSize(ts.Size), formatFileSize(ts.CompressedSize))
+	}
+	fmt.Fprintf(tw, "indexed commits\t%d\n", stats.IndexedCommits)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// The cache is fresh if it holds every object that a branch or tag
+	// points to. That is what CopyFrom fetches.
+	iter := cc.git.IterateRefs(ctx, git.IterateRefsOptions{
+		IncludeHead: true,
+	})
+	defer iter.Close()
+	var missing []string
+	for iter.Next() {
+		ref := iter.Ref()
+		if ref != git.Head && !ref.IsBranch() && !ref.IsTag() {
+			continue
+		}
+		_, err := cache.Stat(ctx, iter.ObjectSHA1())
+		if errors.Is(err, repocache.ErrObjectNotFound) {
+			missing = append(missing, ref.String())
+		} else if err != nil {
+			return err
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		_, err := fmt.Fprintln(cc.stdout, "fresh: all branches and tags are cached")
+		return err
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "stale: %d refs point to objects that are not cached (run 'gg debug cache --sync'):\n", len(missing))
+	for _, ref := range missing {
+		fmt.Fprintf(buf, "\t%s\n", ref)
+	}
+	_, err = cc.stdout.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestDebugObj(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "debug", "obj", "--raw", "HEAD:foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != dummyContent {
+		t.Errorf("gg debug obj --raw HEAD:foo.txt = %q; want %q", out, dummyContent)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "debug", "obj", "HEAD:foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "type   blob\n") || !strings.Contains(string(out), "source git\n") || !strings.HasSuffix(string(out), "\n\n"+dummyContent) {
+		t.Errorf("gg debug obj HEAD:foo.txt output:\n%s\nwant a blob header read from git followed by the content", out)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "debug", "cache", "--sync"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, env.root.String(), "debug", "obj", "HEAD^{tree}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "type   tree\n") || !strings.Contains(string(out), "source cache\n") || !strings.Contains(string(out), " blob ") || !strings.HasSuffix(string(out), "\tfoo.txt\n") {
+		t.Errorf("gg debug obj HEAD^{tree} output:\n%s\nwant a tree read from the cache with a foo.txt entry", out)
+	}
+}
+
+func TestDebugRefs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "update-ref", "refs/gg/push-exclude", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "debug", "refs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sawHead, sawMain, sawGG bool
+	for _, line := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			t.Errorf("malformed line %q", line)
+			continue
+		}
+		switch fields[1] {
+		case "HEAD":
+			sawHead = true
+		case "refs/heads/main":
+			sawMain = true
+			if len(fields) != 2 {
+				t.Errorf("line %q marks a user branch", line)
+			}
+		case "refs/gg/push-exclude":
+			sawGG = len(fields) == 3 && fields[2] == "gg"
+		}
+	}
+	if !sawHead || !sawMain || !sawGG {
+		t.Errorf("gg debug refs output:\n%s\nwant HEAD, refs/heads/main, and refs/gg/push-exclude marked gg", out)
+	}
+}
+
+func TestDebugCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "debug", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "no cache at ") {
+		t.Errorf("gg debug cache before sync output:\n%s\nwant a note that there is no cache", out)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "debug", "cache", "--sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "\nfresh: ") || !strings.Contains(string(out), "indexed commits") {
+		t.Errorf("gg debug cache --sync output:\n%s\nwant a fresh cache", out)
+	}
+
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, env.root.String(), "debug", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "\nstale: ") || !strings.Contains(string(out), "\trefs/heads/main\n") {
+		t.Errorf("gg debug cache after commit output:\n%s\nwant main listed as stale", out)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// A deprecation is a warning about a deprecated flag or setting or about
+// an upcoming change in a command's behavior. Declare each one as a
+// package-level variable next to the code that uses it and call
+// warnDeprecated when the user does something the change affects.
+type deprecation struct {
+	// id is a stable name for the warning, used to remember when it was
+	// last shown. It must not change once released.
+	id string
+	// message explains what is changing and what to do instead.
+	message string
+}
+
+// warningsStateFile is the slash-separated path of the file inside the gg
+// state directory that records when each deprecation warning was last
+// shown. It is a JSON object that maps deprecation IDs to dates.
+const warningsStateFile = "warnings.json"
+
+// warnDeprecated prints the deprecation's message to stderr unless it has
+// already been printed today or the gg.silenceWarnings setting is on.
+// Failing to read or record the state never fails the command.
+func warnDeprecated(ctx context.Context, cc *cmdContext, d *deprecation) {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err == nil {
+		if silence, _ := cfg.Bool("gg.silenceWarnings"); silence {
+			return
+		}
+	}
+	cc.xdgDirs.warnOncePerDay(cc.stderr, time.Now(), d)
+}
+
+// warnOncePerDay prints the deprecation's message to w unless the state
+// directory records that it was printed on the same day as now.
+func (x *xdgDirs) warnOncePerDay(w io.Writer, now time.Time, d *deprecation) {
+	today := now.Local().Format("2006-01-02")
+	shown, err := x.readWarningsState()
+	if err == nil && shown[d.id] == today {
+		return
+	}
+	fmt.Fprintf(w, "gg: warning: %s\n", d.message)
+	fmt.Fprintln(w, "gg: (shown once a day; run 'gg config --global silence-warnings on' to hide)")
+	if err != nil {
+		// Don't clobber a state file that we couldn't understand.
+		return
+	}
+	shown[d.id] = today
+	x.writeWarningsState(shown)
+}
+
+func (x *xdgDirs) readWarningsState() (map[string]string, error) {
+	if x.stateHome == "" {
+		return nil, fmt.Errorf("read warnings state: no $XDG_STATE_HOME variable set")
+	}
+	shown := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(x.stateHome, configDirname, filepath.FromSlash(warningsStateFile)))
+	if os.IsNotExist(err) {
+		return shown, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read warnings state: %w", err)
+	}
+	if err := json.Unmarshal(data, &shown); err != nil {
+		return nil, fmt.Errorf("read warnings state: %w", err)
+	}
+	return shown, nil
+}
+
+func (x *xdgDirs) writeWarningsState(shown map[string]string) error {
+	data, err := json.Marshal(shown)
+	if err != nil {
+		return fmt.Errorf("write warnings state: %w", err)
+	}
+	if err := x.writeState(warningsStateFile, append(data, '\n')); err != nil {
+		return fmt.Errorf("write warnings state: %w", err)
+	}
+	return nil
+}
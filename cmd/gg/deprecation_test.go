@@ -0,0 +1,64 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWarnOncePerDay(t *testing.T) {
+	t.Parallel()
+	x := &xdgDirs{stateHome: t.TempDir()}
+	foo := &deprecation{id: "foo", message: "--foo is deprecated; use --bar"}
+	baz := &deprecation{id: "baz", message: "quux will change soon"}
+	day1 := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		now  time.Time
+		d    *deprecation
+		want bool
+	}{
+		{now: day1, d: foo, want: true},
+		{now: day1.Add(2 * time.Hour), d: foo, want: false},
+		{now: day1.Add(2 * time.Hour), d: baz, want: true},
+		{now: day1.AddDate(0, 0, 1), d: foo, want: true},
+		{now: day1.AddDate(0, 0, 1), d: baz, want: true},
+		{now: day1.AddDate(0, 0, 1).Add(time.Hour), d: foo, want: false},
+	}
+	for _, test := range tests {
+		out := new(strings.Builder)
+		x.warnOncePerDay(out, test.now, test.d)
+		if got := strings.Contains(out.String(), test.d.message); got != test.want {
+			t.Errorf("warnOncePerDay(%v, %q) output = %q; want warning = %t", test.now, test.d.id, out, test.want)
+		}
+	}
+}
+
+func TestWarnOncePerDayWithoutState(t *testing.T) {
+	t.Parallel()
+	x := new(xdgDirs)
+	d := &deprecation{id: "foo", message: "--foo is deprecated; use --bar"}
+	now := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.Local)
+	for i := 0; i < 2; i++ {
+		out := new(strings.Builder)
+		x.warnOncePerDay(out, now, d)
+		if !strings.Contains(out.String(), d.message) {
+			t.Errorf("warnOncePerDay output #%d = %q; want warning", i+1, out)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// detachedLeftBehindMax is the number of commits that
+// warnLeftBehind lists before summarizing the rest.
+const detachedLeftBehindMax = 5
+
+// isDetached reports whether HEAD points to a commit
+// rather than a branch.
+func isDetached(ctx context.Context, g *git.Git) (bool, error) {
+	ref, err := g.HeadRef(ctx)
+	if err != nil {
+		return false, err
+	}
+	return ref == "", nil
+}
+
+// warnDetachedCommit warns that the commit just made on a detached HEAD
+// is not on any branch.
+func warnDetachedCommit(ctx context.Context, cc *cmdContext) {
+	head, err := cc.git.Head(ctx)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(cc.stderr, "gg: warning: HEAD is detached, so commit %s is not on any branch\n"+
+		"gg: run 'gg branch NAME' to create a branch for it before updating away\n", head.Commit.Short())
+}
+
+// moveCommitToBranch points a new branch at HEAD and checks it out.
+// oldRef and oldHead are the branch and commit that HEAD pointed to
+// before the commit was made. If oldRef is a branch, it is moved back
+// to oldHead (or deleted if oldHead is nil), so that the new commit is
+// only on the new branch.
+func moveCommitToBranch(ctx context.Context, g *git.Git, name string, oldRef git.Ref, oldHead *git.Rev) error {
+	head, err := g.Head(ctx)
+	if err != nil {
+		return err
+	}
+	muts := map[git.Ref]git.RefMutation{
+		git.BranchRef(name): git.SetRef(head.Commit.String()),
+	}
+	if oldRef.IsBranch() {
+		if oldHead != nil {
+			muts[oldRef] = git.SetRefIfMatches(head.Commit.String(), oldHead.Commit.String())
+		} else {
+			muts[oldRef] = git.DeleteRefIfMatches(head.Commit.String())
+		}
+	}
+	if err := g.MutateRefs(ctx, muts); err != nil {
+		return fmt.Errorf("create branch %s: %w", name, err)
+	}
+	if err := g.Run(ctx, "symbolic-ref", "HEAD", git.BranchRef(name).String()); err != nil {
+		return fmt.Errorf("switch to branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// leftBehind returns the commits that will no longer be reachable from
+// any branch, tag, or remote-tracking branch once a detached HEAD is
+// moved to target, described by their short hash and summary. It returns
+// nil if HEAD is not detached.
+func leftBehind(ctx context.Context, g *git.Git, target string) ([]string, error) {
+	if detached, err := isDetached(ctx, g); err != nil || !detached {
+		return nil, err
+	}
+	out, err := g.Output(ctx, "log", "--format=%h %s", git.Head.String(),
+		"--not", "--branches", "--tags", "--remotes", target, "--")
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(out, "\n"), "\n"), nil
+}
+
+// warnLeftBehind warns about the commits returned by leftBehind.
+// The commits can still be found in the HEAD reflog.
+func warnLeftBehind(cc *cmdContext, commits []string) {
+	if len(commits) == 0 {
+		return
+	}
+	msg := new(strings.Builder)
+	fmt.Fprintf(msg, "gg: warning: left behind %s not on any branch:\n", countCommits(len(commits)))
+	for i, c := range commits {
+		if i == detachedLeftBehindMax {
+			fmt.Fprintf(msg, "gg:   ... and %d more\n", len(commits)-i)
+			break
+		}
+		fmt.Fprintf(msg, "gg:   %s\n", c)
+	}
+	hash, _, _ := strings.Cut(commits[0], " ")
+	fmt.Fprintf(msg, "gg: they are recorded in the HEAD reflog; run 'gg branch -r %s NAME' to keep them\n", hash)
+	io.WriteString(cc.stderr, msg.String())
+}
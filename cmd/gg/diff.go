@@ -15,17 +15,46 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 
+	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
+	"gg-scm.io/tool/internal/terminal"
 )
 
 const diffSynopsis = "diff repository (or selected files)"
 
 func diff(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg diff [--stat] [-c REV | -r REV1 [-r REV2]] [FILE [...]]", diffSynopsis)
+	f := flag.NewFlagSet(true, "gg diff [--stat [--expand-renames]] [--no-textconv] [--relative | --root-relative] [--staged [-r REV] | -c REV | -r REV1 [-r REV2]] [-I PATTERN] [-X PATTERN] [FILE [...]]", diffSynopsis+`
+
+	With `+"`--relative`"+`, paths are printed relative to the current
+	directory and changes outside of the current directory are not shown.
+	With the relative-paths setting on (see `+"`gg config`"+`), paths are
+	printed relative to the current directory, using `+"`..`"+` for changes
+	outside of it. `+"`--root-relative`"+` overrides the setting.
+
+	With `+"`--stat`"+`, a directory whose files were all renamed into
+	another directory without changes is summarized as a single
+	"directory renamed" line. `+"`--expand-renames`"+` lists the renamed
+	files individually instead.
+
+	`+"`--staged`"+` shows the changes in Git's index instead of the working
+	copy. See `+"`gg stage`"+`.
+
+	Given a BRANCH:PATH argument (described below), diff compares that
+	file with the file at the same path in the working copy. Given two
+	file arguments, at least one of them of the form BRANCH:PATH, diff
+	compares the first file with the second. Revision flags cannot be
+	used with BRANCH:PATH arguments.`+textconvHelp+autoFetchHelp+fileRefHelp+patternHelp)
+	pats := &patternSet{rawArgs: true}
+	pats.addFlags(f)
+	pathStyle := new(pathStyleFlags)
+	pathStyle.addFlags(f)
 	ignoreSpaceChange := f.Bool("b", false, "ignore changes in amount of whitespace")
 	f.Alias("b", "ignore-space-change")
 	ignoreBlankLines := f.Bool("B", false, "ignore changes whose lines are all blank")
@@ -35,6 +64,8 @@ func diff(ctx context.Context, cc *cmdContext, args []string) error {
 	var rev revFlag
 	f.Var(&rev, "r", "`rev`ision")
 	stat := f.Bool("stat", false, "output diffstat-style summary of changes")
+	expandRenames := f.Bool("expand-renames", false, "with --stat, list the files in renamed directories individually")
+	staged := f.Bool("staged", false, "show the changes in the index instead of the working copy")
 	ignoreAllSpace := f.Bool("w", false, "ignore whitespace when comparing lines")
 	f.Alias("w", "ignore-all-space")
 	ignoreSpaceAtEOL := f.Bool("Z", false, "ignore changes in whitespace at EOL")
@@ -42,18 +73,86 @@ func diff(ctx context.Context, cc *cmdContext, args []string) error {
 	renames := f.String("M", "50%", "report new files with the set `percent`age of similarity to a removed file as renamed")
 	copies := f.String("C", "50%", "report new files with the set `percent`age of similarity as copied")
 	copiesUnmodified := f.Bool("copies-unmodified", true, "whether to check unmodified files when detecting copies (can be expensive)")
+	noTextconv := f.Bool("no-textconv", false, "compare files' contents without converting them with their textconv commands")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	relative, err := pathStyle.isRelative(cfg)
+	if err != nil {
+		return err
+	}
+	textconvCfg := cfg
+	if *noTextconv {
+		textconvCfg = nil
+	}
+	fileRefs, err := diffFileRefs(ctx, cc, textconvCfg, f.Args())
+	if err != nil {
+		return err
+	}
+	if fileRefs != nil && (rev.r1 != "" || *change != "" || *staged || len(pats.includes) > 0 || len(pats.excludes) > 0) {
+		return usagef("cannot use revision or pattern flags with BRANCH:PATH arguments")
+	}
+	pats.args = f.Args()
+	var pathspecs []git.Pathspec
+	if fileRefs == nil {
+		pathspecs, err = pats.pathspecs(ctx, cc.git)
+		if err != nil {
+			return err
+		}
+	}
+	if *staged && (rev.r2 != "" || *change != "") {
+		return usagef("--staged can only be used with a single -r")
+	}
+	if err := fetchMissingRevs(ctx, cc, rev.r1, rev.r2, *change); err != nil {
+		return err
+	}
 	var diffArgs []string
 	diffArgs = append(diffArgs, "diff")
+	if *staged {
+		diffArgs = append(diffArgs, "--cached")
+	}
+	// Only the --relative flag leaves out the changes outside of the
+	// current directory. The relative-paths setting rewrites the paths
+	// that Git prints instead.
+	var pf *pathFormatter
+	switch {
+	case pathStyle.relative:
+		diffArgs = append(diffArgs, "--relative")
+	case relative:
+		pf, err = pathStyle.formatter(ctx, cc, cfg)
+		if err != nil {
+			return err
+		}
+		diffArgs = append(diffArgs, "--no-relative")
+	case pathStyle.rootRelative:
+		diffArgs = append(diffArgs, "--no-relative")
+	}
+	statIndex := -1
 	if *stat {
-		diffArgs = append(diffArgs, "--stat")
+		if pf != nil {
+			diffArgs = append(diffArgs, diffRelativeStatArgs...)
+		}
+		statIndex = len(diffArgs)
+		// --summary lists mode changes, which --stat shows as files
+		// with no changed lines.
+		diffArgs = append(diffArgs, "--stat", "--summary")
 	} else {
 		diffArgs = append(diffArgs, fmt.Sprintf("-U%d", *ncontext))
+		if cfg.Value("diff.submodule") == "" {
+			// Name the old and new commits of submodules instead of
+			// showing their hashes as file content.
+			diffArgs = append(diffArgs, "--submodule=log")
+		}
+	}
+	if *noTextconv {
+		diffArgs = append(diffArgs, "--no-textconv")
 	}
 	if *ignoreSpaceChange {
 		diffArgs = append(diffArgs, "--ignore-space-change")
@@ -76,14 +175,21 @@ func diff(ctx context.Context, cc *cmdContext, args []string) error {
 	if *copiesUnmodified {
 		diffArgs = append(diffArgs, "--find-copies-harder")
 	}
+	if fileRefs != nil {
+		diffArgs = append(diffArgs, fileRefs...)
+		return cc.interactiveGit(ctx, diffArgs...)
+	}
+	newRev := "" // the index and working copy
 	switch {
 	case rev.r1 != "" && *change == "":
 		diffArgs = append(diffArgs, rev.r1)
 		if rev.r2 != "" {
 			diffArgs = append(diffArgs, rev.r2)
+			newRev = rev.r2
 		}
 	case rev.r1 == "" && *change != "":
 		diffArgs = append(diffArgs, *change+"^", *change)
+		newRev = *change
 	case rev.r1 != "" && *change != "":
 		return usagef("can't pass both -r and -c")
 	default:
@@ -102,8 +208,179 @@ func diff(ctx context.Context, cc *cmdContext, args []string) error {
 		}
 	}
 	diffArgs = append(diffArgs, "--")
-	diffArgs = append(diffArgs, f.Args()...)
-	return cc.interactiveGit(ctx, diffArgs...)
+	for _, p := range pathspecs {
+		diffArgs = append(diffArgs, p.String())
+	}
+	if *stat && *renames != "" && !*expandRenames {
+		movePF, err := pathStyle.formatter(ctx, cc, cfg)
+		if err != nil {
+			return err
+		}
+		excludes, err := diffStatDirMoves(ctx, cc, movePF, diffArgs, statIndex, newRev)
+		if err != nil {
+			return err
+		}
+		diffArgs = append(diffArgs, excludes...)
+	}
+	if pf == nil || pf.dir == "" {
+		return cc.interactiveGit(ctx, diffArgs...)
+	}
+	colorize, err := cfg.ColorBool("color.diff", terminal.IsTerminal(cc.stdout))
+	if err != nil {
+		return err
+	}
+	if colorize {
+		diffArgs = append([]string{"diff", "--color=always"}, diffArgs[1:]...)
+	}
+	if !*stat {
+		rw := &diffPathRewriter{pf: pf}
+		return cc.filteredGit(ctx, rw.line, diffArgs...)
+	}
+	out, err := cc.git.Output(ctx, diffArgs...)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(cc.stdout, rewriteDiffStat(out, pf))
+	return err
+}
+
+// diffFileRefs returns the Git object names to compare if args contain
+// a BRANCH:PATH argument, or nil otherwise. See fileRef. If cfg is not
+// nil, files are converted with their textconv commands, since Git
+// can't find the attributes of the objects by name.
+func diffFileRefs(ctx context.Context, cc *cmdContext, cfg *git.Config, args []string) ([]string, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, nil
+	}
+	refs := make([]*fileRef, len(args))
+	found := false
+	for i, arg := range args {
+		var err error
+		refs[i], err = parseFileRef(ctx, cc, arg)
+		if err != nil {
+			return nil, err
+		}
+		found = found || refs[i] != nil
+	}
+	if !found {
+		return nil, nil
+	}
+	var names []string
+	for i, ref := range refs {
+		name, err := diffFileObject(ctx, cc, cfg, ref, cc.abs(args[i]))
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if len(names) == 1 {
+		// Compare with the same file in the working copy.
+		top, err := cc.git.WorkTree(ctx)
+		if err != nil {
+			return nil, err
+		}
+		name, err := diffFileObject(ctx, cc, cfg, &fileRef{path: refs[0].path, worktree: top}, "")
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// diffFileObject returns a Git object name for the content of the file
+// that ref names, or of the working copy file at filename if ref is nil.
+// If cfg is not nil and the file has a textconv command, the object
+// holds the converted content.
+func diffFileObject(ctx context.Context, cc *cmdContext, cfg *git.Config, ref *fileRef, filename string) (string, error) {
+	if ref == nil && cfg != nil {
+		if top, err := cc.git.WorkTree(ctx); err == nil {
+			if p, err := worktreeRelativePath(cc, top, filename); err == nil {
+				ref = &fileRef{path: p, worktree: top}
+			}
+		}
+	}
+	if ref == nil {
+		return hashWorkingFile(ctx, cc.git, filename)
+	}
+	if cfg == nil {
+		return ref.objectName(ctx, cc.git)
+	}
+	line, err := textconvCommand(ctx, cc.git, cfg, ref.path)
+	if err != nil {
+		return "", err
+	}
+	if line == "" {
+		return ref.objectName(ctx, cc.git)
+	}
+	r, err := ref.open(ctx, cc.git)
+	if err != nil {
+		return "", err
+	}
+	converted := new(bytes.Buffer)
+	err = runTextconv(ctx, cc, line, r, converted)
+	r.Close()
+	if err != nil {
+		return "", err
+	}
+	out := new(bytes.Buffer)
+	err = cc.git.Runner().RunGit(ctx, &git.Invocation{
+		Dir:    cc.dir,
+		Args:   []string{"hash-object", "-w", "--stdin"},
+		Stdin:  converted,
+		Stdout: out,
+	})
+	if err != nil {
+		return "", fmt.Errorf("hash textconv output: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// diffStatDirMoves prints a line for each directory that was moved
+// without changes in the diff that diffArgs shows, where
+// diffArgs[statIndex:statIndex+2] is "--stat", "--summary". It returns
+// pathspecs that leave the moved files out of git diff --stat's output.
+func diffStatDirMoves(ctx context.Context, cc *cmdContext, pf *pathFormatter, diffArgs []string, statIndex int, newRev string) ([]string, error) {
+	nameStatusArgs := append([]string(nil), diffArgs[:statIndex]...)
+	nameStatusArgs = append(nameStatusArgs, "--name-status", "-z", "--no-relative")
+	nameStatusArgs = append(nameStatusArgs, diffArgs[statIndex+2:]...)
+	out, err := cc.git.Output(ctx, nameStatusArgs...)
+	if err != nil {
+		return nil, err
+	}
+	var renames []fileRename
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	for i := 0; i < len(fields); i++ {
+		switch {
+		case strings.HasPrefix(fields[i], "R") || strings.HasPrefix(fields[i], "C"):
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("parse diff: malformed %s entry", fields[i])
+			}
+			// Only exact renames are summarized, so that the changes to
+			// files that were edited as well as moved are still shown.
+			if fields[i] == "R100" {
+				renames = append(renames, fileRename{from: git.TopPath(fields[i+1]), to: git.TopPath(fields[i+2])})
+			}
+			i += 2
+		default:
+			i++
+		}
+	}
+	moves, err := findDirMoves(renames, trackedFilesRemain(ctx, cc.git, newRev))
+	if err != nil {
+		return nil, err
+	}
+	var excludes []string
+	for _, m := range moves {
+		_, err := fmt.Fprintf(cc.stdout, " directory renamed %s/ → %s/ (%d files)\n", pf.format(m.from), pf.format(m.to), len(m.renames))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range m.renames {
+			excludes = append(excludes, ":(top,exclude,literal)"+string(r.from), ":(top,exclude,literal)"+string(r.to))
+		}
+	}
+	return excludes, nil
 }
 
 type revFlag struct {
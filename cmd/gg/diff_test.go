@@ -64,6 +64,74 @@ func TestDiff(t *testing.T) {
 	}
 }
 
+func TestDiff_Relative(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("top.txt", "top\n"),
+		filesystem.Write("sub/inner.txt", "inner\n"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "top.txt", "sub/inner.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("top.txt", "top changed\n"),
+		filesystem.Write("sub/inner.txt", "inner changed\n"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := env.root.FromSlash("sub")
+	out, err := env.gg(ctx, dir, "diff", "--relative")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out, []byte("a/inner.txt")) || bytes.Contains(out, []byte("sub/inner.txt")) {
+		t.Errorf("gg diff --relative does not name a/inner.txt. Output:\n%s", out)
+	}
+	if bytes.Contains(out, []byte("top.txt")) {
+		t.Errorf("gg diff --relative includes a file outside the current directory. Output:\n%s", out)
+	}
+
+	if err := env.writeConfig([]byte("[gg]\nrelativePaths = true\n")); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, dir, "diff", "--root-relative")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out, []byte("a/sub/inner.txt")) || !bytes.Contains(out, []byte("a/top.txt")) {
+		t.Errorf("gg diff --root-relative does not name a/sub/inner.txt and a/top.txt. Output:\n%s", out)
+	}
+	out, err = env.gg(ctx, dir, "diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out, []byte("a/inner.txt")) || !bytes.Contains(out, []byte("a/../top.txt")) {
+		t.Errorf("gg diff with relativePaths does not name a/inner.txt and a/../top.txt. Output:\n%s", out)
+	}
+
+	if _, err := env.gg(ctx, dir, "diff", "--relative", "--root-relative"); err == nil {
+		t.Error("gg diff --relative --root-relative did not return an error")
+	} else if !isUsage(err) {
+		t.Errorf("gg diff --relative --root-relative error = %v; want usage", err)
+	}
+}
+
 func TestDiff_NoChange(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -147,3 +215,52 @@ func TestDiff_BeforeFirstCommit(t *testing.T) {
 		t.Errorf("diff does not contain %q. Output:\n%s", line, out)
 	}
 }
+
+func TestDiff_StatDirectoryRenamed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("old/foo.txt", "foo\n"),
+		filesystem.Write("old/bar.txt", "bar\n"),
+		filesystem.Write("baz.txt", "baz\n"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "old/foo.txt", "old/bar.txt", "baz.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "mv", "old", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("baz.txt", "baz\nquux\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "diff", "--stat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const dirLine = " directory renamed old/ → new/ (2 files)\n"
+	if !bytes.HasPrefix(out, []byte(dirLine)) || bytes.Contains(out, []byte("foo.txt")) || !bytes.Contains(out, []byte("baz.txt")) {
+		t.Errorf("gg diff --stat output:\n%s\nwant %q followed by baz.txt only", out, dirLine)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "diff", "--stat", "--expand-renames")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(out, []byte("directory renamed")) || !bytes.Contains(out, []byte("foo.txt")) {
+		t.Errorf("gg diff --stat --expand-renames output:\n%s\nwant individual files", out)
+	}
+}
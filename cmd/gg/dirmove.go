@@ -0,0 +1,121 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// minDirMoveFiles is the number of renamed files that it takes for
+// findDirMoves to report a directory as moved.
+const minDirMoveFiles = 2
+
+// A fileRename is a file that rename detection paired with a new name.
+type fileRename struct {
+	from, to git.TopPath
+}
+
+// A dirMove is a directory whose files were all renamed into another
+// directory.
+type dirMove struct {
+	from, to git.TopPath // directory names without a trailing slash
+	renames  []fileRename
+}
+
+// findDirMoves finds the directories that were moved in a set of
+// renames. A directory counts as moved if at least minDirMoveFiles files
+// were renamed from it to the same new directory and remains reports
+// that no files are left in it. The moves are returned in order of
+// their old names.
+func findDirMoves(renames []fileRename, remains func(dir git.TopPath) (bool, error)) ([]*dirMove, error) {
+	type dirPair struct {
+		from, to git.TopPath
+	}
+	groups := make(map[dirPair]*dirMove)
+	for _, r := range renames {
+		from, to, ok := renameDirs(r.from, r.to)
+		if !ok {
+			continue
+		}
+		m := groups[dirPair{from, to}]
+		if m == nil {
+			m = &dirMove{from: from, to: to}
+			groups[dirPair{from, to}] = m
+		}
+		m.renames = append(m.renames, r)
+	}
+	var moves []*dirMove
+	for _, m := range groups {
+		if len(m.renames) < minDirMoveFiles {
+			continue
+		}
+		left, err := remains(m.from)
+		if err != nil {
+			return nil, err
+		}
+		if !left {
+			moves = append(moves, m)
+		}
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		return moves[i].from < moves[j].from
+	})
+	return moves, nil
+}
+
+// renameDirs returns the directories that differ between the old and
+// new names of a renamed file, after removing the path components that
+// the names end with in common. ok is false if the file was renamed
+// within a directory or to or from the top of the working tree.
+func renameDirs(from, to git.TopPath) (fromDir, toDir git.TopPath, ok bool) {
+	fromParts := strings.Split(string(from), "/")
+	toParts := strings.Split(string(to), "/")
+	n := 0
+	for n < len(fromParts) && n < len(toParts) &&
+		fromParts[len(fromParts)-1-n] == toParts[len(toParts)-1-n] {
+		n++
+	}
+	if n == 0 || n == len(fromParts) || n == len(toParts) {
+		return "", "", false
+	}
+	fromDir = git.TopPath(strings.Join(fromParts[:len(fromParts)-n], "/"))
+	toDir = git.TopPath(strings.Join(toParts[:len(toParts)-n], "/"))
+	return fromDir, toDir, true
+}
+
+// trackedFilesRemain returns a function for findDirMoves that reports
+// whether a directory has any files in rev, or in the index if rev is
+// empty.
+func trackedFilesRemain(ctx context.Context, g *git.Git, rev string) func(dir git.TopPath) (bool, error) {
+	return func(dir git.TopPath) (bool, error) {
+		var out string
+		var err error
+		if rev == "" {
+			out, err = g.Output(ctx, "ls-files", "-z", "--", git.TopPath(dir+"/").Pathspec().String())
+		} else {
+			out, err = g.Output(ctx, "ls-tree", "--full-tree", "--name-only", "-z", rev, "--", string(dir)+"/")
+		}
+		if err != nil {
+			return false, err
+		}
+		return out != "", nil
+	}
+}
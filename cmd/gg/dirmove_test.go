@@ -0,0 +1,72 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenameDirs(t *testing.T) {
+	tests := []struct {
+		from, to       git.TopPath
+		fromDir, toDir git.TopPath
+		ok             bool
+	}{
+		{from: "a/x.go", to: "b/x.go", fromDir: "a", toDir: "b", ok: true},
+		{from: "a/sub/x.go", to: "b/sub/x.go", fromDir: "a", toDir: "b", ok: true},
+		{from: "src/a/x.go", to: "pkg/b/x.go", fromDir: "src/a", toDir: "pkg/b", ok: true},
+		{from: "a/x.go", to: "b/y.go", ok: false},
+		{from: "x.go", to: "b/x.go", ok: false},
+		{from: "a/x.go", to: "x.go", ok: false},
+	}
+	for _, test := range tests {
+		fromDir, toDir, ok := renameDirs(test.from, test.to)
+		if fromDir != test.fromDir || toDir != test.toDir || ok != test.ok {
+			t.Errorf("renameDirs(%q, %q) = %q, %q, %t; want %q, %q, %t",
+				test.from, test.to, fromDir, toDir, ok, test.fromDir, test.toDir, test.ok)
+		}
+	}
+}
+
+func TestFindDirMoves(t *testing.T) {
+	renames := []fileRename{
+		{from: "a/x.go", to: "b/x.go"},
+		{from: "a/sub/y.go", to: "b/sub/y.go"},
+		{from: "c/x.go", to: "d/x.go"},
+		{from: "c/y.go", to: "d/y.go"},
+		{from: "e/x.go", to: "f/x.go"},
+	}
+	remains := func(dir git.TopPath) (bool, error) {
+		// Some files were left behind in c.
+		return dir == "c", nil
+	}
+	moves, err := findDirMoves(renames, remains)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range moves {
+		got = append(got, string(m.from)+" -> "+string(m.to))
+	}
+	want := []string{"a -> b"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("findDirMoves(...) (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// A divergence is an error that explains why a local branch can't be
+// fast-forwarded to its upstream or pushed over it. Its message suggests
+// a command that fixes the situation.
+type divergence struct {
+	branch   string // local branch name
+	upstream string // name of the counterpart, like "origin/main"
+	push     bool   // whether the branch was being pushed rather than updated
+
+	ahead  int // commits only on the local branch
+	behind int // commits only on upstream, or -1 if they have not been fetched
+
+	// rewrittenFrom is the previous position of upstream if upstream was
+	// moved to a commit that does not descend from it.
+	rewrittenFrom string
+	// firstLocal is the oldest commit on the local branch that was not
+	// in upstream at rewrittenFrom. It is empty if there is no such commit.
+	firstLocal string
+
+	// renamedTo is the name of a remote branch that points to the
+	// last-known commit of upstream, which no longer exists.
+	renamedTo string
+}
+
+func (d *divergence) Error() string {
+	switch {
+	case d.renamedTo != "":
+		return fmt.Sprintf("%s no longer exists, but branch %s on the remote points to the same commit; "+
+			"if the branch was renamed, run 'gg pull' to fetch it, "+
+			"otherwise use --new-branch to push %s anyway", d.upstream, d.renamedTo, d.branch)
+	case d.behind < 0:
+		return fmt.Sprintf("%s has commits that are not in the local repository; "+
+			"run 'gg pull', then 'gg rebase' to move %s onto them", d.upstream, d.branch)
+	case d.rewrittenFrom != "" && d.firstLocal == "":
+		return fmt.Sprintf("%s was rewritten and %s has no commits of its own; "+
+			"run 'gg update %s', then 'gg branch -f %s' to move %s to the new history",
+			d.upstream, d.branch, d.upstream, d.branch, d.branch)
+	case d.rewrittenFrom != "":
+		return fmt.Sprintf("%s was rewritten; run 'gg rebase --source %s --dest %s' to move %s of %s onto the new history",
+			d.upstream, shortHash(d.firstLocal), d.upstream, countCommits(d.ahead), d.branch)
+	case d.ahead == 0:
+		return fmt.Sprintf("%s is %s behind %s; run 'gg pull -u' to update it",
+			d.branch, countCommits(d.behind), d.upstream)
+	case d.push:
+		return fmt.Sprintf("%s has diverged from %s (%s ahead, %s behind); "+
+			"run 'gg pull', then 'gg rebase' to move %s onto it",
+			d.branch, d.upstream, countCommits(d.ahead), countCommits(d.behind), d.branch)
+	default:
+		return fmt.Sprintf("%s has diverged from %s (%s ahead, %s behind); "+
+			"run 'gg rebase' to move %s onto it or 'gg merge' to merge them",
+			d.branch, d.upstream, countCommits(d.ahead), countCommits(d.behind), d.branch)
+	}
+}
+
+// updateDivergence explains why branch can't be fast-forwarded to target,
+// a remote-tracking branch. It detects whether target was rewritten using
+// target's reflog.
+func updateDivergence(ctx context.Context, g *git.Git, branch string, target git.Ref) (*divergence, error) {
+	d := &divergence{
+		branch:   branch,
+		upstream: strings.TrimPrefix(target.String(), "refs/remotes/"),
+	}
+	var err error
+	d.ahead, d.behind, err = aheadBehind(ctx, g, git.BranchRef(branch).String(), target.String())
+	if err != nil {
+		return nil, err
+	}
+	prev, err := g.Output(ctx, "rev-parse", "--verify", "--quiet", target.String()+"@{1}^{commit}")
+	if err != nil {
+		// No reflog entry, so we can't tell whether upstream was rewritten.
+		return d, nil
+	}
+	prev = strings.TrimSuffix(prev, "\n")
+	if fastForward, err := g.IsAncestor(ctx, prev, target.String()); err != nil {
+		return nil, err
+	} else if fastForward {
+		return d, nil
+	}
+	if inBranch, err := g.IsAncestor(ctx, prev, git.BranchRef(branch).String()); err != nil {
+		return nil, err
+	} else if !inBranch {
+		return d, nil
+	}
+	d.rewrittenFrom = prev
+	local, err := g.Output(ctx, "rev-list", "--reverse", prev+".."+git.BranchRef(branch).String(), "--")
+	if err != nil {
+		return nil, err
+	}
+	if first, _, _ := strings.Cut(local, "\n"); first != "" {
+		d.firstLocal = first
+		d.ahead = strings.Count(local, "\n")
+	} else {
+		d.ahead = 0
+	}
+	return d, nil
+}
+
+// pushDivergence explains why pushing localHash over remoteHash
+// was rejected.
+func pushDivergence(ctx context.Context, g *git.Git, branch, upstream string, localHash, remoteHash git.Hash) (*divergence, error) {
+	d := &divergence{
+		branch:   branch,
+		upstream: upstream,
+		push:     true,
+	}
+	if _, err := g.ParseRev(ctx, remoteHash.String()+"^{commit}"); err != nil {
+		d.behind = -1
+		return d, nil
+	}
+	var err error
+	d.ahead, d.behind, err = aheadBehind(ctx, g, localHash.String(), remoteHash.String())
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// renamedDivergence returns a divergence if a branch that no longer exists
+// on a remote appears to have been renamed: some other branch on the remote
+// points to the branch's last-known commit. It returns nil otherwise.
+func renamedDivergence(branch, upstream string, lastKnown git.Hash, remoteRefs map[git.Ref]git.Hash) *divergence {
+	var candidates []string
+	for ref, hash := range remoteRefs {
+		if b := ref.Branch(); b != "" && b != branch && hash == lastKnown {
+			candidates = append(candidates, b)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.Strings(candidates)
+	return &divergence{
+		branch:    branch,
+		upstream:  upstream,
+		push:      true,
+		renamedTo: candidates[0],
+	}
+}
+
+// aheadBehind returns the number of commits reachable from local but not
+// upstream and the number reachable from upstream but not local.
+func aheadBehind(ctx context.Context, g *git.Git, local, upstream string) (ahead, behind int, err error) {
+	out, err := g.Output(ctx, "rev-list", "--left-right", "--count", local+"..."+upstream, "--")
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("count commits: unexpected output %q", out)
+	}
+	ahead, err = strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("count commits: %w", err)
+	}
+	behind, err = strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("count commits: %w", err)
+	}
+	return ahead, behind, nil
+}
+
+func countCommits(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return strconv.Itoa(n) + " commits"
+}
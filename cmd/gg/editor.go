@@ -46,8 +46,28 @@ func (e *editor) open(ctx context.Context, basename string, initial []byte) ([]b
 	if err != nil {
 		return nil, fmt.Errorf("open editor: %w", err)
 	}
-	editor = strings.TrimSuffix(editor, "\n")
+	return e.openWith(ctx, strings.TrimSuffix(editor, "\n"), basename, initial)
+}
 
+// openSequence is like open, but uses the editor Git uses for rebase
+// plans: $GIT_SEQUENCE_EDITOR, sequence.editor, or the default editor.
+func (e *editor) openSequence(ctx context.Context, basename string, initial []byte) ([]byte, error) {
+	if editor := getenv(e.env, "GIT_SEQUENCE_EDITOR"); editor != "" {
+		return e.openWith(ctx, editor, basename, initial)
+	}
+	cfg, err := e.git.ReadConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("open editor: %w", err)
+	}
+	if editor := cfg.Value("sequence.editor"); editor != "" {
+		return e.openWith(ctx, editor, basename, initial)
+	}
+	return e.open(ctx, basename, initial)
+}
+
+// openWith opens the given editor command with the given initial
+// content and waits for it to return.
+func (e *editor) openWith(ctx context.Context, editor string, basename string, initial []byte) ([]byte, error) {
 	workDir, err := e.git.WorkTree(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("open editor: %w", err)
@@ -0,0 +1,93 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// envConfigPrefix is the prefix of environment variables that override
+// gg's settings.
+const envConfigPrefix = "GG_"
+
+// envConfigVariables lists the gg.* variables outside configSettings
+// that GG_* environment variables can set.
+var envConfigVariables = []string{
+	"gg.autoFetch",
+	"gg.backupDays",
+	"gg.hyperlinks",
+	"gg.identity",
+	"gg.journal",
+	"gg.largeFileSize",
+	"gg.maxDateSkew",
+	"gg.oldBranchDays",
+	"gg.protect",
+	subprocessTimeoutSetting,
+}
+
+// envConfigOverrides returns the Git configuration assignments, in
+// name=value form, requested by GG_* variables in environ.
+// Variables named after one of the settings in configSettings
+// (like GG_CONFLICT_STYLE) set that setting's Git variable.
+// Variables named after one of envConfigVariables with the underscores
+// removed set that variable, so GG_LARGE_FILE_SIZE sets gg.largeFileSize.
+// Other GG_* variables are ignored, since they may belong to other
+// programs. warn is called for each variable with an invalid value,
+// which is skipped.
+func envConfigOverrides(environ []string, warn func(error)) []string {
+	var params []string
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		suffix, ok := strings.CutPrefix(name, envConfigPrefix)
+		if !ok {
+			continue
+		}
+		gitName, value, err := envConfigVariable(suffix, value)
+		if err != nil {
+			warn(fmt.Errorf("environment variable %s: %w", name, err))
+			continue
+		}
+		if gitName != "" {
+			params = append(params, gitName+"="+value)
+		}
+	}
+	return params
+}
+
+// envConfigVariable maps the part of a GG_* environment variable name
+// after the prefix to a Git configuration variable. It returns an empty
+// gitName if the name isn't one of gg's settings.
+func envConfigVariable(suffix, value string) (gitName, newValue string, err error) {
+	if setting := findConfigSetting(strings.ToLower(strings.ReplaceAll(suffix, "_", "-"))); setting != nil {
+		value, err := setting.normalize(value)
+		if err != nil {
+			return "", "", err
+		}
+		return setting.gitName, value, nil
+	}
+	key := strings.ReplaceAll(suffix, "_", "")
+	for _, v := range envConfigVariables {
+		if strings.EqualFold(strings.TrimPrefix(v, "gg."), key) {
+			return v, value, nil
+		}
+	}
+	return "", "", nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEnvConfigOverrides(t *testing.T) {
+	tests := []struct {
+		environ  []string
+		want     []string
+		warnings int
+	}{
+		{
+			environ: []string{"HOME=/home/foo", "PATH=/bin"},
+			want:    nil,
+		},
+		{
+			environ: []string{"GG_COLOR=never"},
+			want:    []string{"color.ui=never"},
+		},
+		{
+			environ: []string{"GG_PULL_UPDATE=1"},
+			want:    []string{"gg.pullUpdate=true"},
+		},
+		{
+			environ: []string{"GG_CONFLICT_STYLE=zdiff3", "GG_RERERE=off"},
+			want:    []string{"merge.conflictStyle=zdiff3", "rerere.enabled=false"},
+		},
+		{
+			environ: []string{"GG_LARGE_FILE_SIZE=1m", "GG_MAXDATESKEW=0"},
+			want:    []string{"gg.largeFileSize=1m", "gg.maxDateSkew=0"},
+		},
+		{
+			environ: []string{"GG_FOO=bar", "GG_COLOR=never"},
+			want:    []string{"color.ui=never"},
+		},
+		{
+			environ: []string{"GG_=1", "GG_1X=1", "GG_X-Y=1"},
+			want:    nil,
+		},
+		{
+			environ:  []string{"GG_COLOR=sometimes", "GG_RERERE=on"},
+			want:     []string{"rerere.enabled=true"},
+			warnings: 1,
+		},
+		{
+			environ:  []string{"GG_PULL_UPDATE=maybe"},
+			want:     nil,
+			warnings: 1,
+		},
+	}
+	for _, test := range tests {
+		var warnings []error
+		got := envConfigOverrides(test.environ, func(e error) {
+			warnings = append(warnings, e)
+		})
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("envConfigOverrides(%q) (-want +got):\n%s", test.environ, diff)
+		}
+		if len(warnings) != test.warnings {
+			t.Errorf("envConfigOverrides(%q) warnings = %v; want %d warnings", test.environ, warnings, test.warnings)
+		}
+	}
+}
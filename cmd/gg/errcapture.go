@@ -0,0 +1,206 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// Limits on how much of a failed Git command's error output is included
+// in the error message. The rest is written to a file.
+const (
+	errorOutputInlineBytes = 4096
+	errorOutputInlineLines = 20
+)
+
+// newGit returns a Git object that runs the local Git executable with the
+// given options. Unlike git.New, the error output of Git commands that is
+// captured for errors is not truncated: if it is too long to include in
+// the error message, it is saved to a file in tempDir and the error
+// message gives the file's path. Git subprocesses are stopped if they
+// exceed the given limits, which may be nil.
+func newGit(opts git.Options, tempDir string, limits *subprocessLimits) (*git.Git, error) {
+	l, err := git.NewLocal(opts)
+	if err != nil {
+		return nil, err
+	}
+	return git.Custom(opts.Dir, &spillRunner{Local: l, tempDir: tempDir, limits: limits}, l), nil
+}
+
+// spillRunner is a Git runner that captures the complete error output of
+// commands that do not have their own stderr.
+type spillRunner struct {
+	*git.Local
+	tempDir string
+	limits  *subprocessLimits
+}
+
+func (r *spillRunner) RunGit(ctx context.Context, invoke *git.Invocation) error {
+	if invoke.Stderr != nil {
+		return r.run(ctx, invoke, true)
+	}
+	w := &spillWriter{tempDir: r.tempDir}
+	invoke2 := new(git.Invocation)
+	*invoke2 = *invoke
+	invoke2.Stderr = w
+	runErr := r.run(ctx, invoke2, false)
+	logPath, closeErr := w.close(runErr != nil)
+	if runErr == nil {
+		return nil
+	}
+	var limitErr *subprocessLimitError
+	if errors.As(runErr, &limitErr) {
+		// The error already describes the output that matters.
+		return runErr
+	}
+	name := "git"
+	if len(invoke.Args) > 0 {
+		name += " " + invoke.Args[0]
+	}
+	return &gitOutputError{
+		name:    name,
+		err:     runErr,
+		output:  w.head.String(),
+		logPath: logPath,
+		logErr:  closeErr,
+	}
+}
+
+// run runs Git within r's limits. visible is true if the subprocess's
+// stderr is shown to the user.
+func (r *spillRunner) run(ctx context.Context, invoke *git.Invocation, visible bool) error {
+	if r.limits == nil {
+		return r.Local.RunGit(ctx, invoke)
+	}
+	return r.limits.run(ctx, invoke, visible, r.Local.RunGit)
+}
+
+// gitOutputError is the error for a failed Git command with its error
+// output.
+type gitOutputError struct {
+	name    string
+	err     error
+	output  string // possibly truncated
+	logPath string // file with the complete output, if output is truncated
+	logErr  error  // error writing logPath
+}
+
+func (e *gitOutputError) Error() string {
+	out := strings.TrimRight(e.output, "\n")
+	if out == "" {
+		return e.err.Error()
+	}
+	lines := strings.SplitAfter(out, "\n")
+	truncated := e.logPath != "" || e.logErr != nil
+	if len(lines) > errorOutputInlineLines {
+		lines = lines[:errorOutputInlineLines]
+		truncated = true
+	}
+	out = strings.TrimRight(strings.Join(lines, ""), "\n")
+	sb := new(strings.Builder)
+	sb.WriteString(e.name)
+	if !truncated && !strings.Contains(out, "\n") {
+		sb.WriteString(": ")
+		sb.WriteString(out)
+		return sb.String()
+	}
+	sb.WriteString(":\n")
+	sb.WriteString(out)
+	switch {
+	case e.logPath != "":
+		fmt.Fprintf(sb, "\n... (full output in %s)", e.logPath)
+	case e.logErr != nil:
+		fmt.Fprintf(sb, "\n... (output truncated; saving full output: %v)", e.logErr)
+	case truncated:
+		sb.WriteString("\n...")
+	}
+	return sb.String()
+}
+
+func (e *gitOutputError) Unwrap() error {
+	return e.err
+}
+
+// spillWriter keeps the first errorOutputInlineBytes written to it in
+// memory and, once that is exceeded, writes everything to a temporary
+// file as well.
+type spillWriter struct {
+	tempDir string
+	head    bytes.Buffer
+	file    *os.File
+	err     error
+}
+
+func (w *spillWriter) Write(p []byte) (int, error) {
+	if w.file == nil && w.err == nil && w.head.Len()+len(p) > errorOutputInlineBytes {
+		w.spill()
+	}
+	if n := errorOutputInlineBytes - w.head.Len(); n > 0 {
+		if n > len(p) {
+			n = len(p)
+		}
+		w.head.Write(p[:n])
+	}
+	if w.file != nil && w.err == nil {
+		_, w.err = w.file.Write(p)
+	}
+	// Never fail the Git command because of a problem saving its output.
+	return len(p), nil
+}
+
+// spill creates the temporary file and writes the output so far to it.
+func (w *spillWriter) spill() {
+	w.file, w.err = os.CreateTemp(w.tempDir, "gg-err-*.log")
+	if w.err == nil {
+		_, w.err = w.file.Write(w.head.Bytes())
+	}
+}
+
+// close finishes writing the spill file. If keep is false, the file is
+// removed. If keep is true, the output is saved to a file if it has more
+// lines than fit in an error message, even if it did not overflow.
+// close returns the path of the file if it was kept.
+func (w *spillWriter) close(keep bool) (string, error) {
+	if w.file == nil && w.err == nil && keep && bytes.Count(bytes.TrimRight(w.head.Bytes(), "\n"), []byte("\n")) >= errorOutputInlineLines {
+		w.spill()
+	}
+	if w.file == nil {
+		return "", w.err
+	}
+	path := w.file.Name()
+	closeErr := w.file.Close()
+	if !keep {
+		os.Remove(path)
+		return "", nil
+	}
+	if w.err != nil {
+		os.Remove(path)
+		return "", w.err
+	}
+	if closeErr != nil {
+		os.Remove(path)
+		return "", closeErr
+	}
+	return path, nil
+}
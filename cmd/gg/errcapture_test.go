@@ -0,0 +1,152 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSpillWriter(t *testing.T) {
+	t.Run("Short", func(t *testing.T) {
+		w := &spillWriter{tempDir: t.TempDir()}
+		fmt.Fprintln(w, "error: something went wrong")
+		path, err := w.close(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != "" {
+			t.Errorf("close(true) = %q; want no file", path)
+		}
+		if got, want := w.head.String(), "error: something went wrong\n"; got != want {
+			t.Errorf("head = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("Long", func(t *testing.T) {
+		w := &spillWriter{tempDir: t.TempDir()}
+		full := new(strings.Builder)
+		for i := 0; full.Len() <= 2*errorOutputInlineBytes; i++ {
+			line := fmt.Sprintf("CONFLICT (content): Merge conflict in file%d.txt\n", i)
+			full.WriteString(line)
+			w.Write([]byte(line))
+		}
+		path, err := w.close(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path == "" {
+			t.Fatal("close(true) did not keep a file")
+		}
+		if got := w.head.Len(); got != errorOutputInlineBytes {
+			t.Errorf("len(head) = %d; want %d", got, errorOutputInlineBytes)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != full.String() {
+			t.Errorf("file has %d bytes; want complete output (%d bytes)", len(got), full.Len())
+		}
+	})
+
+	t.Run("ManyLines", func(t *testing.T) {
+		w := &spillWriter{tempDir: t.TempDir()}
+		for i := 0; i < errorOutputInlineLines+5; i++ {
+			fmt.Fprintf(w, "line %d\n", i)
+		}
+		path, err := w.close(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path == "" {
+			t.Fatal("close(true) did not keep a file")
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		dir := t.TempDir()
+		w := &spillWriter{tempDir: dir}
+		w.Write([]byte(strings.Repeat("x", 2*errorOutputInlineBytes)))
+		path, err := w.close(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != "" {
+			t.Errorf("close(false) = %q; want no file", path)
+		}
+		if ents, err := os.ReadDir(dir); err != nil {
+			t.Fatal(err)
+		} else if len(ents) > 0 {
+			t.Errorf("%d files left in temporary directory", len(ents))
+		}
+	})
+}
+
+func TestGitOutputError(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	var manyLines strings.Builder
+	for i := 0; i < errorOutputInlineLines+5; i++ {
+		fmt.Fprintf(&manyLines, "line %d\n", i)
+	}
+	tests := []struct {
+		name string
+		err  *gitOutputError
+		want string
+	}{
+		{
+			name: "NoOutput",
+			err:  &gitOutputError{name: "git rebase", err: exitErr},
+			want: "exit status 1",
+		},
+		{
+			name: "OneLine",
+			err:  &gitOutputError{name: "git rebase", err: exitErr, output: "fatal: no upstream\n"},
+			want: "git rebase: fatal: no upstream",
+		},
+		{
+			name: "MultipleLines",
+			err:  &gitOutputError{name: "git rebase", err: exitErr, output: "error: a\nerror: b\n"},
+			want: "git rebase:\nerror: a\nerror: b",
+		},
+		{
+			name: "Spilled",
+			err: &gitOutputError{
+				name:    "git rebase",
+				err:     exitErr,
+				output:  manyLines.String(),
+				logPath: "/tmp/gg-err-123.log",
+			},
+			want: "git rebase:\n" +
+				strings.Join(strings.SplitAfter(manyLines.String(), "\n")[:errorOutputInlineLines], "") +
+				"... (full output in /tmp/gg-err-123.log)",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.err.Error(); got != test.want {
+				t.Errorf("Error() = %q; want %q", got, test.want)
+			}
+			if !errors.Is(test.err, exitErr) {
+				t.Error("errors.Is(err, exitErr) = false")
+			}
+		})
+	}
+}
@@ -16,7 +16,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"gg-scm.io/pkg/git"
@@ -26,23 +29,39 @@ import (
 const evolveSynopsis = "sync with Gerrit changes in upstream"
 
 func evolve(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg evolve [-l] [-d DST]", evolveSynopsis+`
+	f := flag.NewFlagSet(true, "gg evolve [-l] [-d DST] | --stop", evolveSynopsis+`
 
 	evolve compares HEAD with the ancestors of the given destination. If
 	evolve finds any ancestors of the destination have the same Gerrit
 	change ID as diverging ancestors of HEAD, it rebases the descendants
 	of the latest shared change onto the corresponding commit in the
-	destination.`)
+	destination. Commits made with `+"`gg fixup`"+` are folded into the
+	commits they fix along the way, as in `+"`gg rebase`"+`.
+
+	If the rebase stops because of a conflict, `+"`--stop`"+` ends it early:
+	the branch keeps the commits that were already rebased and the rest are
+	dropped, along with any changes from the step that stopped. The
+	original commits remain available from `+"`ORIG_HEAD`"+`. Use
+	`+"`gg state`"+` to see how far the rebase got.`+protectedBranchHelp)
 	dst := f.String("d", "", "`ref` to compare with (defaults to upstream)")
 	f.Alias("d", "dst")
 	list := f.Bool("l", false, "list commits with match change IDs")
 	f.Alias("l", "list")
+	stop := f.Bool("stop", false, "stop an interrupted evolve, keeping the commits already rebased")
+	autosquash := f.Bool("autosquash", true, "fold fixup! and squash! commits into the commits they fix")
+	forceProtected := f.Bool("force-protected", false, forceProtectedUsage)
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
+	if *stop {
+		if *dst != "" || *list || f.IsSet("autosquash") || *forceProtected || f.NArg() != 0 {
+			return usagef("can't pass other options with --stop")
+		}
+		return stopEvolve(ctx, cc)
+	}
 	// Find our upstream
 	var dstRev *git.Rev
 	if *dst == "" {
@@ -105,7 +124,65 @@ func evolve(ctx context.Context, cc *cmdContext, args []string) error {
 	if last >= len(featureChanges) {
 		return nil
 	}
-	return cc.interactiveGit(ctx, "rebase", "--onto="+submitted[featureChanges[last].id], "--no-fork-point", "--", featureChanges[last].commitHex)
+	if !*forceProtected {
+		if err := checkRewriteProtected(ctx, cc, "evolve"); err != nil {
+			return err
+		}
+	}
+	if err := recordOperation(ctx, cc.git, "evolve", args); err != nil {
+		return err
+	}
+	if err := autoSnapshot(ctx, cc, "evolve"); err != nil {
+		return err
+	}
+	rebaseArgs := []string{"rebase", "--onto=" + submitted[featureChanges[last].id], "--no-fork-point"}
+	if *autosquash {
+		rebaseArgs, err = autosquashRebaseArgs(ctx, cc.git, rebaseArgs, git.Head.String(), featureChanges[last].commitHex)
+		if err != nil {
+			return err
+		}
+	}
+	return cc.interactiveGit(ctx, append(rebaseArgs, "--", featureChanges[last].commitHex)...)
+}
+
+// stopEvolve ends the rebase in progress after the steps that have
+// completed. The step in progress and the remaining steps are dropped.
+func stopEvolve(ctx context.Context, cc *cmdContext) error {
+	gitDir, err := cc.git.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	rebaseDir := filepath.Join(gitDir, "rebase-merge")
+	if !isdir(rebaseDir) {
+		if isdir(filepath.Join(gitDir, "rebase-apply")) {
+			return errors.New("rebase in progress does not support stopping; run 'gg rebase --abort' to cancel it")
+		}
+		return errors.New("no evolve in progress")
+	}
+	origHead := readStateFile(rebaseDir, "orig-head")
+	if command := recordedOperation(gitDir, origHead); command != "gg evolve" && !strings.HasPrefix(command, "gg evolve ") {
+		return errors.New("rebase in progress was not started by gg evolve; run 'gg rebase --abort' to cancel it")
+	}
+	// Discard the step in progress, then give Git an empty list of
+	// remaining steps so that it finishes the rebase where it is.
+	if err := cc.git.Run(ctx, "reset", "--merge", git.Head.String()); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(rebaseDir, "git-rebase-todo"), nil, 0o666); err != nil {
+		return fmt.Errorf("stop evolve: %w", err)
+	}
+	if err := cc.interactiveGit(ctx, "rebase", "--continue"); err != nil {
+		return err
+	}
+	if origHead != "" {
+		// Finishing the rebase points ORIG_HEAD at the new HEAD, but the
+		// original commits are the ones worth keeping track of.
+		if err := cc.git.Run(ctx, "update-ref", "ORIG_HEAD", origHead); err != nil {
+			return err
+		}
+		fmt.Fprintf(cc.stderr, "gg: evolve stopped; the original commits are at %s\n", shortHash(origHead))
+	}
+	return nil
 }
 
 type change struct {
@@ -20,9 +20,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
 )
 
 func TestEvolve_FirstChangeSubmitted(t *testing.T) {
@@ -447,3 +449,156 @@ func TestFindChangeID(t *testing.T) {
 		}
 	}
 }
+
+func TestEvolve_Stop(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	commit := func(file, content, msg string) git.Hash {
+		t.Helper()
+		if err := env.root.Apply(filesystem.Write(file, content)); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, file); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.git.Commit(ctx, msg, git.CommitOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		r, err := env.git.Head(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.Commit
+	}
+	commit("foo.txt", "base\n", "Initial import\n\nChange-Id: xyzzy")
+	if err := env.git.NewBranch(ctx, "topic", git.BranchOptions{Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	commit("bar.txt", "bar\n", "First feature change\n\nChange-Id: abcdef")
+	commit("baz.txt", "baz\n", "Second feature change\n\nChange-Id: ghijkl")
+	origTopic := commit("foo.txt", "topic\n", "Third feature change\n\nChange-Id: mnopqr")
+	if err := env.git.CheckoutBranch(ctx, "main", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "upstream\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	submit1 := commit("bar.txt", "bar\n", "Submitted first feature change\n\nChange-Id: abcdef")
+	if err := env.git.CheckoutBranch(ctx, "topic", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The third change conflicts with the submitted change.
+	if _, err := env.gg(ctx, env.root.String(), "evolve", "-d", "main"); err == nil {
+		t.Fatal("evolve did not return error")
+	}
+	out, err := env.gg(ctx, env.root.String(), "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"rebase in progress (step 2 of 2)\n",
+		"  branch:     topic\n",
+		"  started by: gg evolve -d main\n",
+		"conflicts:\n  foo.txt\n",
+		"run 'gg evolve --stop'",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("gg state output:\n%s\nwant to contain %q", out, want)
+		}
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "evolve", "--stop"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := head.Ref.Branch(); got != "topic" {
+		t.Errorf("after evolve --stop, HEAD is on %q; want topic", got)
+	}
+	if info, err := env.git.CommitInfo(ctx, head.Commit.String()); err != nil {
+		t.Error(err)
+	} else if got, want := info.Summary(), "Second feature change"; got != want {
+		t.Errorf("HEAD summary = %q; want %q", got, want)
+	}
+	if parent, err := env.git.ParseRev(ctx, "HEAD~1"); err != nil {
+		t.Error(err)
+	} else if parent.Commit != submit1 {
+		t.Errorf("HEAD~1 = %v; want %v (submitted change)", parent.Commit, submit1)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Error(err)
+	} else if want := "upstream\n"; got != want {
+		t.Errorf("foo.txt = %q; want %q", got, want)
+	}
+	if origHead, err := env.git.ParseRev(ctx, "ORIG_HEAD"); err != nil {
+		t.Error(err)
+	} else if origHead.Commit != origTopic {
+		t.Errorf("ORIG_HEAD = %v; want %v (original topic)", origHead.Commit, origTopic)
+	}
+	out, err = env.gg(ctx, env.root.String(), "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "no operation in progress\n"; got != want {
+		t.Errorf("gg state after evolve --stop = %q; want %q", got, want)
+	}
+}
+
+func TestEvolve_StopOtherRebase(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	commit := func(content, msg string) {
+		t.Helper()
+		if err := env.root.Apply(filesystem.Write("foo.txt", content)); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, "foo.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.git.Commit(ctx, msg, git.CommitOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("base\n", "Initial import")
+	if err := env.git.NewBranch(ctx, "topic", git.BranchOptions{Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	commit("topic\n", "Topic change")
+	if err := env.git.CheckoutBranch(ctx, "main", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	commit("upstream\n", "Upstream change")
+	if err := env.git.CheckoutBranch(ctx, "topic", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "rebase", "--merge", "main"); err == nil {
+		t.Fatal("git rebase did not stop with a conflict")
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "evolve", "--stop"); err == nil {
+		t.Error("evolve --stop succeeded on a rebase that evolve didn't start")
+	}
+	if _, err := os.Stat(filepath.Join(env.root.String(), ".git", "rebase-merge")); err != nil {
+		t.Errorf("rebase is no longer in progress after evolve --stop: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// An exitStatusError is an error from a Git subprocess that gg passed
+// through to the user. gg exits with the subprocess's status instead of 1
+// so that scripts can tell how Git failed.
+type exitStatusError struct {
+	err    error
+	status int
+}
+
+func (e *exitStatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitStatusError) Unwrap() error {
+	return e.err
+}
+
+// passthroughGit runs Git like interactiveGit does, but if Git fails, gg
+// exits with Git's exit status. Use it for commands that hand the terminal
+// to Git, like an editor session, where callers expect Git's status.
+func (cc *cmdContext) passthroughGit(ctx context.Context, args ...string) error {
+	err := cc.interactiveGit(ctx, args...)
+	if err == nil {
+		return nil
+	}
+	if status, ok := childExitStatus(err); ok {
+		return &exitStatusError{err: err, status: status}
+	}
+	return err
+}
+
+// exitStatus returns the status that gg should exit with for err if err
+// came from a passed-through Git subprocess.
+func exitStatus(err error) (int, bool) {
+	var e *exitStatusError
+	if !errors.As(err, &e) {
+		return 0, false
+	}
+	return e.status, true
+}
+
+// childExitStatus returns the exit status of the subprocess that failed
+// with err. Like POSIX shells, it reports a subprocess terminated by a
+// signal as 128 plus the signal number.
+func childExitStatus(err error) (int, bool) {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 0, false
+	}
+	if sig, ok := terminatingSignal(exitErr.ProcessState); ok {
+		return 128 + sig, true
+	}
+	status := exitErr.ExitCode()
+	if status <= 0 {
+		return 0, false
+	}
+	return status, true
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+func TestChildExitStatus(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		script string
+		want   int
+		ok     bool
+	}{
+		{script: "exit 0", ok: false},
+		{script: "exit 1", want: 1, ok: true},
+		{script: "exit 42", want: 42, ok: true},
+		{script: "kill -TERM $$", want: 128 + 15, ok: true},
+	}
+	for _, test := range tests {
+		runErr := exec.Command("/bin/sh", "-c", test.script).Run()
+		err := fmt.Errorf("git rebase: %w", runErr)
+		got, ok := childExitStatus(err)
+		if got != test.want || ok != test.ok {
+			t.Errorf("childExitStatus(<%q error>) = %d, %t; want %d, %t", test.script, got, ok, test.want, test.ok)
+		}
+		if !ok {
+			continue
+		}
+		wrapped := fmt.Errorf("gg: %w", &exitStatusError{err: err, status: got})
+		if status, ok := exitStatus(wrapped); status != test.want || !ok {
+			t.Errorf("exitStatus(<%q error>) = %d, %t; want %d, true", test.script, status, ok, test.want)
+		}
+	}
+}
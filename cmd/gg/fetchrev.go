@@ -0,0 +1,186 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/terminal"
+)
+
+const autoFetchHelp = `
+
+	If a revision is a full commit hash or a tag name that is not in the
+	local repository, gg can fetch just that object from the current
+	branch's remote (or ` + "`origin`" + `) before giving up. The
+	` + "`gg.autoFetch`" + ` setting controls this: ` + "`ask`" + ` (the default)
+	asks first when run in a terminal, ` + "`true`" + ` fetches without asking,
+	and ` + "`false`" + ` never fetches.`
+
+// fetchMissingRevs fetches the objects named by revs that are not in the
+// local repository, as allowed by gg.autoFetch. Revisions may be ranges
+// like A..B. Revisions that can't be fetched are left for the caller to
+// report, so the error is only for failures to read the configuration.
+func fetchMissingRevs(ctx context.Context, cc *cmdContext, revs ...string) error {
+	var missing []string
+	for _, rev := range revs {
+		ends := []string{rev}
+		if a, b, ok := splitRevRange(rev); ok {
+			ends = []string{a, b}
+		}
+		for _, r := range ends {
+			what := fetchableRev(r)
+			if what == "" {
+				continue
+			}
+			if git.Ref(what).IsTag() {
+				// The name may also be a branch or remote-tracking branch.
+				if _, err := cc.git.ParseRev(ctx, r); err == nil {
+					continue
+				}
+			} else if err := cc.git.Run(ctx, "cat-file", "-e", what); err == nil {
+				// rev-parse accepts any full hash, so check for the object itself.
+				continue
+			}
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	mode := strings.ToLower(cfg.Value("gg.autoFetch"))
+	switch mode {
+	case "", "ask":
+		if !terminal.IsTerminal(cc.stderr) || !stdinIsTerminal(cc) {
+			return nil
+		}
+		mode = "ask"
+	default:
+		auto, err := cfg.Bool("gg.autoFetch")
+		if err != nil {
+			return fmt.Errorf("gg.autoFetch: must be ask, true, or false")
+		}
+		if !auto {
+			return nil
+		}
+	}
+	remote := autoFetchRemote(ctx, cc, cfg)
+	if remote == "" {
+		return nil
+	}
+	for _, rev := range missing {
+		if mode == "ask" {
+			ok, err := confirm(cc, fmt.Sprintf("%s is not in the local repository. Fetch it from %s?", rev, remote))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+		fmt.Fprintf(cc.stderr, "gg: fetching %s from %s\n", rev, remote)
+		if err := fetchRev(ctx, cc, cfg, remote, fetchableRev(rev)); err != nil {
+			fmt.Fprintf(cc.stderr, "gg: could not fetch %s: %v\n", rev, err)
+		}
+	}
+	return nil
+}
+
+// fetchableRev returns what to fetch for rev: the full commit hash or the
+// tag ref that rev starts with, ignoring suffixes like ~1 or ^{tree}.
+// It returns the empty string if rev doesn't name something that can be
+// fetched by itself.
+func fetchableRev(rev string) string {
+	if i := strings.IndexAny(rev, "~^:@"); i != -1 {
+		rev = rev[:i]
+	}
+	if rev == "" || strings.HasPrefix(rev, "-") || rev == git.Head.String() {
+		return ""
+	}
+	if h, err := git.ParseHash(rev); err == nil {
+		return h.String()
+	}
+	if isHex(rev) {
+		// Abbreviated hashes can't be fetched.
+		return ""
+	}
+	tag := strings.TrimPrefix(rev, "refs/tags/")
+	if strings.HasPrefix(tag, "refs/") || !git.TagRef(tag).IsValid() {
+		return ""
+	}
+	return git.TagRef(tag).String()
+}
+
+// stdinIsTerminal reports whether the command reads input from a terminal.
+func stdinIsTerminal(cc *cmdContext) bool {
+	f, ok := cc.stdin.(*os.File)
+	return ok && terminal.IsTerminal(f)
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// autoFetchRemote returns the remote to fetch missing revisions from:
+// the current branch's remote or origin.
+func autoFetchRemote(ctx context.Context, cc *cmdContext, cfg *git.Config) string {
+	remotes := cfg.ListRemotes()
+	if branch := currentBranch(ctx, cc); branch != "" {
+		if r := cfg.Value("branch." + branch + ".remote"); r != "" && remotes[r] != nil {
+			return r
+		}
+	}
+	if remotes["origin"] != nil {
+		return "origin"
+	}
+	return ""
+}
+
+// fetchRev fetches a single commit hash or tag ref from remote using
+// Git's protocol version 2, which lets servers send any reachable
+// commit. A tag is stored as a local tag. A partial clone only fetches
+// the commits and trees.
+func fetchRev(ctx context.Context, cc *cmdContext, cfg *git.Config, remote, what string) error {
+	fetchCC, err := cc.withGitConfig("protocol.version", "2")
+	if err != nil {
+		return err
+	}
+	args := []string{"fetch", "--quiet", "--no-tags"}
+	if cfg.Value("remote."+remote+".promisor") != "" {
+		args = append(args, "--filter=blob:none")
+	}
+	args = append(args, "--", remote)
+	if ref := git.Ref(what); ref.IsTag() {
+		args = append(args, "+"+what+":"+what)
+	} else {
+		args = append(args, what)
+	}
+	return fetchCC.git.Run(ctx, args...)
+}
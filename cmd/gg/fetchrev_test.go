@@ -0,0 +1,111 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestFetchableRev(t *testing.T) {
+	tests := []struct {
+		rev  string
+		want string
+	}{
+		{rev: "0123456789abcdef0123456789abcdef01234567", want: "0123456789abcdef0123456789abcdef01234567"},
+		{rev: "0123456789abcdef0123456789abcdef01234567~2", want: "0123456789abcdef0123456789abcdef01234567"},
+		{rev: "0123456", want: ""},
+		{rev: "v1.2.3", want: "refs/tags/v1.2.3"},
+		{rev: "refs/tags/v1.2.3^{tree}", want: "refs/tags/v1.2.3"},
+		{rev: "refs/heads/main", want: ""},
+		{rev: "HEAD~1", want: ""},
+		{rev: "@{upstream}", want: ""},
+		{rev: "-x", want: ""},
+	}
+	for _, test := range tests {
+		if got := fetchableRev(test.rev); got != test.want {
+			t.Errorf("fetchableRev(%q) = %q; want %q", test.rev, got, test.want)
+		}
+	}
+}
+
+func TestFetchMissingRevs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "clone", "repoA", "repoB"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	newCommit, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitA := env.git.WithDir(env.root.FromSlash("repoA"))
+	if err := gitA.Run(ctx, "tag", "-a", "-m", "release", "v2"); err != nil {
+		t.Fatal(err)
+	}
+	repoBPath := env.root.FromSlash("repoB")
+	gitB := env.git.WithDir(repoBPath)
+
+	// Not a terminal, so gg won't ask and won't fetch.
+	if _, err := env.gg(ctx, repoBPath, "log", "-r", newCommit.String()); err == nil {
+		t.Error("gg log -r NEW succeeded without gg.autoFetch; want error")
+	}
+	if _, err := gitB.ParseRev(ctx, newCommit.String()); err == nil {
+		t.Error("commit was fetched without gg.autoFetch")
+	}
+
+	if err := gitB.Run(ctx, "config", "gg.autoFetch", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, repoBPath, "log", "-r", newCommit.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gitB.ParseRev(ctx, newCommit.String()); err != nil {
+		t.Errorf("commit not fetched: %v", err)
+	}
+	if !strings.Contains(env.stderr.String(), "gg: fetching "+newCommit.String()+" from origin") {
+		t.Errorf("stderr = %q; want fetching message", env.stderr.String())
+	}
+
+	out, err := env.gg(ctx, repoBPath, "cat", "-r", "v2", "foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); got != dummyContent {
+		t.Errorf("gg cat -r v2 foo.txt = %q; want %q", got, dummyContent)
+	}
+	if _, err := gitB.ParseRev(ctx, git.TagRef("v2").String()); err != nil {
+		t.Errorf("tag v2 not fetched: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/terminal"
+)
+
+// defaultFetchParallelism is the number of fetches that runFetches runs
+// at once if Git's fetch.parallel setting is unset or zero.
+const defaultFetchParallelism = 4
+
+// A fetchJob is the work for one remote in runFetches.
+type fetchJob struct {
+	// remote is the name of the remote, used in progress and errors.
+	remote string
+	// fetch does the work, typically listing the remote's refs and running
+	// git fetch. hints are extra arguments to pass to git fetch that
+	// narrow its negotiation to history that is likely to be shared.
+	fetch func(ctx context.Context, hints []string) error
+}
+
+// fetchParallelism returns the number of fetches to run at once,
+// following Git's fetch.parallel setting.
+func fetchParallelism(cfg *git.Config) (int, error) {
+	setting := cfg.Value("fetch.parallel")
+	if setting == "" {
+		return defaultFetchParallelism, nil
+	}
+	n, err := strconv.Atoi(setting)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("fetch.parallel: %q is not a number of fetches", setting)
+	}
+	if n == 0 {
+		return defaultFetchParallelism, nil
+	}
+	return n, nil
+}
+
+// runFetches runs the jobs with at most parallelism of them at a time and
+// returns each job's error in the same order as jobs. A failing job does
+// not stop the others.
+//
+// If one of the jobs is for the primary remote, it runs first on its
+// own. The other remotes are usually forks of the primary, so once its
+// objects are local, the other fetches only download the objects that
+// the primary doesn't have. Their negotiation is narrowed with
+// --negotiation-tip to the primary's remote-tracking branches, their
+// own, and the local branches and tags, which keeps negotiation short in
+// repositories with many refs.
+func runFetches(ctx context.Context, stderr io.Writer, jobs []*fetchJob, parallelism int, primary string) []error {
+	errs := make([]error, len(jobs))
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	progress := newFetchProgress(stderr, len(jobs))
+	defer progress.finish()
+
+	run := func(i int, hints []string) {
+		progress.start(jobs[i].remote)
+		errs[i] = jobs[i].fetch(ctx, hints)
+		progress.done(jobs[i].remote, errs[i])
+	}
+	rest := make([]int, 0, len(jobs))
+	var primaryHints []string
+	for i, job := range jobs {
+		if job.remote == primary {
+			run(i, nil)
+			if errs[i] == nil {
+				primaryHints = []string{"--negotiation-tip=refs/remotes/" + primary + "/*"}
+			}
+		} else {
+			rest = append(rest, i)
+		}
+	}
+
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for _, i := range rest {
+		var hints []string
+		if primaryHints != nil {
+			hints = append(hints, primaryHints...)
+			hints = append(hints,
+				"--negotiation-tip=refs/remotes/"+jobs[i].remote+"/*",
+				"--negotiation-tip=refs/heads/*",
+				"--negotiation-tip=refs/tags/*",
+			)
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, hints []string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			run(i, hints)
+		}(i, hints)
+	}
+	wg.Wait()
+	return errs
+}
+
+// fetchProgress reports the progress of runFetches on stderr. On a
+// terminal, it keeps a single status line with the remotes being fetched
+// up to date. Otherwise, it prints a line as each fetch finishes.
+type fetchProgress struct {
+	w     io.Writer
+	tty   bool
+	total int
+
+	mu       sync.Mutex
+	running  map[string]struct{}
+	finished int
+	drawn    bool
+}
+
+func newFetchProgress(w io.Writer, total int) *fetchProgress {
+	return &fetchProgress{
+		w:       w,
+		tty:     terminal.IsTerminal(w),
+		total:   total,
+		running: make(map[string]struct{}),
+	}
+}
+
+func (p *fetchProgress) start(remote string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running[remote] = struct{}{}
+	p.redraw()
+}
+
+func (p *fetchProgress) done(remote string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.running, remote)
+	p.finished++
+	if err != nil || !p.tty {
+		p.clear()
+		if err != nil {
+			fmt.Fprintf(p.w, "gg: fetch from %s failed: %v\n", remote, err)
+		} else {
+			fmt.Fprintf(p.w, "gg: fetched %s (%d of %d)\n", remote, p.finished, p.total)
+		}
+	}
+	p.redraw()
+}
+
+// finish removes the status line.
+func (p *fetchProgress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+}
+
+func (p *fetchProgress) redraw() {
+	if !p.tty || len(p.running) == 0 {
+		return
+	}
+	names := make([]string, 0, len(p.running))
+	for name := range p.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(p.w, "\rgg: fetching %s (%d of %d done)\x1b[K", strings.Join(names, ", "), p.finished, p.total)
+	p.drawn = true
+}
+
+func (p *fetchProgress) clear() {
+	if p.drawn {
+		io.WriteString(p.w, "\r\x1b[K")
+		p.drawn = false
+	}
+}
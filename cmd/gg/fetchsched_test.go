@@ -0,0 +1,130 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunFetches(t *testing.T) {
+	ctx := context.Background()
+	var (
+		mu           sync.Mutex
+		running      int
+		maxRunning   int
+		originDone   bool
+		startedEarly []string
+		gotHints     = make(map[string][]string)
+	)
+	newJob := func(remote string, err error) *fetchJob {
+		return &fetchJob{
+			remote: remote,
+			fetch: func(ctx context.Context, hints []string) error {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				if remote != "origin" && !originDone {
+					startedEarly = append(startedEarly, remote)
+				}
+				gotHints[remote] = hints
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				running--
+				if remote == "origin" {
+					originDone = true
+				}
+				mu.Unlock()
+				return err
+			},
+		}
+	}
+	errBroken := errors.New("broken")
+	jobs := []*fetchJob{
+		newJob("a", nil),
+		newJob("b", errBroken),
+		newJob("c", nil),
+		newJob("d", nil),
+		newJob("origin", nil),
+	}
+	stderr := new(strings.Builder)
+	errs := runFetches(ctx, stderr, jobs, 2, "origin")
+
+	for i, err := range errs {
+		want := error(nil)
+		if jobs[i].remote == "b" {
+			want = errBroken
+		}
+		if err != want {
+			t.Errorf("error for %s = %v; want %v", jobs[i].remote, err, want)
+		}
+	}
+	if len(startedEarly) > 0 {
+		t.Errorf("%v started before origin finished", startedEarly)
+	}
+	if maxRunning > 2 {
+		t.Errorf("%d fetches ran at once; want at most 2", maxRunning)
+	}
+	if len(gotHints["origin"]) > 0 {
+		t.Errorf("origin hints = %q; want none", gotHints["origin"])
+	}
+	if hints := strings.Join(gotHints["c"], " "); !strings.Contains(hints, "--negotiation-tip=refs/remotes/origin/*") || !strings.Contains(hints, "--negotiation-tip=refs/remotes/c/*") {
+		t.Errorf("c hints = %q; want origin's and c's remote-tracking branches", hints)
+	}
+	if got := stderr.String(); !strings.Contains(got, "fetch from b failed: broken") {
+		t.Errorf("stderr = %q; want to report b's failure", got)
+	}
+}
+
+func TestRunFetches_PrimaryFails(t *testing.T) {
+	ctx := context.Background()
+	var (
+		mu       sync.Mutex
+		gotHints = make(map[string][]string)
+	)
+	newJob := func(remote string, err error) *fetchJob {
+		return &fetchJob{
+			remote: remote,
+			fetch: func(ctx context.Context, hints []string) error {
+				mu.Lock()
+				gotHints[remote] = hints
+				mu.Unlock()
+				return err
+			},
+		}
+	}
+	jobs := []*fetchJob{
+		newJob("origin", errors.New("unreachable")),
+		newJob("fork", nil),
+	}
+	errs := runFetches(ctx, new(strings.Builder), jobs, 4, "origin")
+	if errs[0] == nil || errs[1] != nil {
+		t.Errorf("errors = %v; want only origin to fail", errs)
+	}
+	if len(gotHints["fork"]) > 0 {
+		t.Errorf("fork hints = %q after origin failed; want none", gotHints["fork"])
+	}
+}
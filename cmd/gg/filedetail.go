@@ -0,0 +1,200 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// Git file modes, as they appear in git diff --raw.
+const (
+	modeFile       = "100644"
+	modeExecutable = "100755"
+	modeSymlink    = "120000"
+	modeGitlink    = "160000"
+)
+
+// A fileDetail describes the parts of a change to a file that gg status
+// can't show with a single letter: mode changes, symbolic link targets,
+// and submodule commits.
+type fileDetail struct {
+	OldMode   string `json:"oldMode,omitempty"`
+	NewMode   string `json:"newMode,omitempty"`
+	ModeOnly  bool   `json:"modeOnly,omitempty"` // mode changed but content did not
+	OldTarget string `json:"oldTarget,omitempty"`
+	NewTarget string `json:"newTarget,omitempty"`
+	OldCommit string `json:"oldCommit,omitempty"`
+	NewCommit string `json:"newCommit,omitempty"`
+
+	oldHash string
+}
+
+// modeKind returns a description of a Git file mode.
+func modeKind(mode string) string {
+	switch mode {
+	case modeFile:
+		return "regular file"
+	case modeExecutable:
+		return "executable file"
+	case modeSymlink:
+		return "symbolic link"
+	case modeGitlink:
+		return "submodule"
+	default:
+		return "mode " + mode
+	}
+}
+
+// annotation returns the text that gg status prints after the file's
+// name, or the empty string if there is nothing to add.
+func (d *fileDetail) annotation() string {
+	switch {
+	case d == nil:
+		return ""
+	case d.OldMode == modeGitlink && d.NewMode == modeGitlink && d.OldCommit == d.NewCommit:
+		return fmt.Sprintf(" (submodule %s has changes)", shortHash(d.OldCommit))
+	case d.OldMode == modeGitlink && d.NewMode == modeGitlink:
+		return fmt.Sprintf(" (submodule %s → %s)", shortHash(d.OldCommit), shortHash(d.NewCommit))
+	case d.OldMode == modeSymlink && d.NewMode == modeSymlink:
+		return fmt.Sprintf(" (symbolic link %s → %s)", d.OldTarget, d.NewTarget)
+	case d.OldMode == d.NewMode:
+		return ""
+	case (d.OldMode == modeFile || d.OldMode == modeExecutable) && (d.NewMode == modeFile || d.NewMode == modeExecutable):
+		if d.ModeOnly {
+			return fmt.Sprintf(" (mode %s → %s only)", d.OldMode, d.NewMode)
+		}
+		return fmt.Sprintf(" (mode %s → %s)", d.OldMode, d.NewMode)
+	default:
+		return fmt.Sprintf(" (%s → %s)", modeKind(d.OldMode), modeKind(d.NewMode))
+	}
+}
+
+// readFileDetails returns the details of the changes between HEAD and
+// the working copy to files that have a mode change, are symbolic
+// links, or are submodules. Files with plain content changes are not
+// included.
+func readFileDetails(ctx context.Context, g *git.Git, pathspecs []git.Pathspec) (map[git.TopPath]*fileDetail, error) {
+	if _, err := g.Head(ctx); err != nil {
+		// No commits yet: there are no old modes to compare with.
+		return nil, nil
+	}
+	args := []string{"diff", "--raw", "-z", "--no-abbrev", "--no-renames", "--no-ext-diff", git.Head.String(), "--"}
+	for _, spec := range pathspecs {
+		args = append(args, spec.String())
+	}
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("read file modes: %w", err)
+	}
+	details, err := parseRawDiff(out)
+	if err != nil {
+		return nil, err
+	}
+	if len(details) == 0 {
+		return nil, nil
+	}
+	workTree, err := g.WorkTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var modeChanged []string
+	for name, d := range details {
+		switch {
+		case d.OldMode == modeGitlink && d.NewMode == modeGitlink:
+			// git diff --raw doesn't give the submodule's commit in the
+			// working copy, but the patch does.
+			d.OldCommit = d.oldHash
+			patch, err := g.Output(ctx, "diff", "--no-color", "--no-ext-diff", "--submodule=short", git.Head.String(), "--", name.Pathspec().String())
+			if err != nil {
+				return nil, fmt.Errorf("read submodule %s: %w", name, err)
+			}
+			for _, line := range strings.Split(patch, "\n") {
+				if commit, ok := strings.CutPrefix(line, "+Subproject commit "); ok {
+					d.NewCommit = strings.TrimSuffix(commit, "-dirty")
+				}
+			}
+		case d.OldMode == modeSymlink && d.NewMode == modeSymlink:
+			d.OldTarget, err = g.Output(ctx, "cat-file", "blob", d.oldHash)
+			if err != nil {
+				return nil, fmt.Errorf("read symbolic link %s: %w", name, err)
+			}
+			d.NewTarget, err = os.Readlink(filepath.Join(workTree, filepath.FromSlash(string(name))))
+			if err != nil {
+				return nil, fmt.Errorf("read symbolic link %s: %w", name, err)
+			}
+		case d.OldMode != d.NewMode:
+			modeChanged = append(modeChanged, name.Pathspec().String())
+		}
+	}
+	if len(modeChanged) > 0 {
+		// Files with no added or removed lines only changed their mode.
+		numstat, err := g.Output(ctx, append([]string{"diff", "--numstat", "-z", "--no-renames", "--no-ext-diff", git.Head.String(), "--"}, modeChanged...)...)
+		if err != nil {
+			return nil, fmt.Errorf("read file modes: %w", err)
+		}
+		for _, ent := range strings.Split(numstat, "\x00") {
+			parts := strings.SplitN(ent, "\t", 3)
+			if len(parts) == 3 && parts[0] == "0" && parts[1] == "0" {
+				if d := details[git.TopPath(parts[2])]; d != nil {
+					d.ModeOnly = true
+				}
+			}
+		}
+	}
+	return details, nil
+}
+
+// parseRawDiff parses the output of git diff --raw -z --no-abbrev
+// --no-renames, keeping only the entries that readFileDetails cares
+// about.
+func parseRawDiff(out string) (map[git.TopPath]*fileDetail, error) {
+	details := make(map[git.TopPath]*fileDetail)
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	if len(fields) == 1 && fields[0] == "" {
+		return details, nil
+	}
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("parse diff: odd number of fields")
+	}
+	for i := 0; i < len(fields); i += 2 {
+		// ":oldmode newmode oldhash newhash status"
+		info := strings.Fields(strings.TrimPrefix(fields[i], ":"))
+		if !strings.HasPrefix(fields[i], ":") || len(info) != 5 {
+			return nil, fmt.Errorf("parse diff: malformed entry %q", fields[i])
+		}
+		d := &fileDetail{
+			OldMode: info[0],
+			NewMode: info[1],
+			oldHash: info[2],
+		}
+		if d.OldMode == "000000" || d.NewMode == "000000" {
+			// Added or deleted.
+			continue
+		}
+		if d.OldMode == d.NewMode && d.OldMode != modeSymlink && d.OldMode != modeGitlink {
+			continue
+		}
+		details[git.TopPath(fields[i+1])] = d
+	}
+	return details, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRawDiff(t *testing.T) {
+	const (
+		hash1 = "1de565933b05f74c75ff9a6520af5f9f8a5a2f1d"
+		hash2 = "38eb9341f9db4b958023c7d59863e304450a2af5"
+		zero  = "0000000000000000000000000000000000000000"
+	)
+	out := ":120000 120000 " + hash1 + " " + zero + " M\x00link\x00" +
+		":160000 160000 " + hash2 + " " + zero + " M\x00sub\x00" +
+		":100644 100755 " + hash1 + " " + zero + " M\x00x.sh\x00" +
+		":100644 100644 " + hash1 + " " + zero + " M\x00plain.txt\x00" +
+		":000000 100644 " + zero + " " + zero + " A\x00new.txt\x00"
+	got, err := parseRawDiff(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[git.TopPath]*fileDetail{
+		"link": {OldMode: modeSymlink, NewMode: modeSymlink, oldHash: hash1},
+		"sub":  {OldMode: modeGitlink, NewMode: modeGitlink, oldHash: hash2},
+		"x.sh": {OldMode: modeFile, NewMode: modeExecutable, oldHash: hash1},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(fileDetail{})); diff != "" {
+		t.Errorf("parseRawDiff(...) (-want +got):\n%s", diff)
+	}
+}
+
+func TestFileDetailAnnotation(t *testing.T) {
+	tests := []struct {
+		detail *fileDetail
+		want   string
+	}{
+		{nil, ""},
+		{&fileDetail{OldMode: modeFile, NewMode: modeExecutable, ModeOnly: true}, " (mode 100644 → 100755 only)"},
+		{&fileDetail{OldMode: modeExecutable, NewMode: modeFile}, " (mode 100755 → 100644)"},
+		{&fileDetail{OldMode: modeFile, NewMode: modeSymlink}, " (regular file → symbolic link)"},
+		{&fileDetail{OldMode: modeSymlink, NewMode: modeSymlink, OldTarget: "a", NewTarget: "b"}, " (symbolic link a → b)"},
+		{
+			&fileDetail{
+				OldMode:   modeGitlink,
+				NewMode:   modeGitlink,
+				OldCommit: "38eb9341f9db4b958023c7d59863e304450a2af5",
+				NewCommit: "f8744de9cb15250aecc7163d2aa14ad08b25f3a5",
+			},
+			" (submodule 38eb934 → f8744de)",
+		},
+	}
+	for _, test := range tests {
+		if got := test.detail.annotation(); got != test.want {
+			t.Errorf("%+v.annotation() = %q; want %q", test.detail, got, test.want)
+		}
+	}
+}
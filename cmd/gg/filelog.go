@@ -0,0 +1,218 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const filelogSynopsis = "show the history of a file across renames"
+
+func filelog(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg filelog [-p [--no-textconv]] [--json] [--relative | --root-relative] [-r REV] FILE", filelogSynopsis+`
+
+	Lists the commits that changed FILE, newest first, following the file
+	back through renames. The commit that renamed the file is marked with
+	the name the file had before it.
+
+	With `+"`--json`"+`, gg prints a JSON array with an object for each commit
+	instead, giving the file's name in that commit and, for a rename, its
+	previous name. The JSON names are always relative to the top of the
+	repository.`+textconvHelp+pathStyleHelp)
+	patch := f.Bool("p", false, "show the changes to the file in each commit")
+	jsonOutput := f.Bool("json", false, "print a JSON array instead of a list of commits")
+	noTextconv := f.Bool("no-textconv", false, "with -p, show changes to files' contents without converting them with their textconv commands")
+	rev := f.String("r", git.Head.String(), "`rev`ision to start from")
+	pathStyle := new(pathStyleFlags)
+	pathStyle.addFlags(f)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() != 1 {
+		return usagef("must pass exactly one file")
+	}
+	if *patch && *jsonOutput {
+		return usagef("cannot pass both -p and --json")
+	}
+	if strings.HasPrefix(*rev, "-") {
+		return usagef("revision must not start with '-'")
+	}
+	prefix, err := cc.git.Output(ctx, "rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+	name := git.TopPath(path.Join(strings.TrimSuffix(prefix, "\n"), filepath.ToSlash(f.Arg(0))))
+	out, err := cc.git.Output(ctx, "log", "-z", "--follow", "--name-status",
+		"--date=short", "--format="+fileLogFormat, *rev, "--", name.Pathspec().String())
+	if err != nil {
+		return err
+	}
+	entries, err := parseFileLog(out, name)
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		if entries == nil {
+			entries = []*fileLogEntry{}
+		}
+		enc := json.NewEncoder(cc.stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(entries)
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	pf, err := pathStyle.formatter(ctx, cc, cfg)
+	if err != nil {
+		return err
+	}
+	for i, ent := range entries {
+		if *patch && i > 0 {
+			if _, err := fmt.Fprintln(cc.stdout); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprint(cc.stdout, ent.format(pf)); err != nil {
+			return err
+		}
+		if !*patch {
+			continue
+		}
+		showArgs := []string{"show", "--format=", "-M"}
+		if *noTextconv {
+			showArgs = append(showArgs, "--no-textconv")
+		}
+		showArgs = append(showArgs, ent.Commit, "--", ent.Path.Pathspec().String())
+		if ent.PreviousPath != "" {
+			showArgs = append(showArgs, ent.PreviousPath.Pathspec().String())
+		}
+		if pf.dir == "" {
+			err = cc.interactiveGit(ctx, showArgs...)
+		} else {
+			rw := &diffPathRewriter{pf: pf}
+			err = cc.filteredGit(ctx, rw.line, showArgs...)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileLogFormat is the git log --format for parseFileLog. Each commit
+// starts with a \x01 byte so that the name-status entries, which are
+// separated by NUL bytes like the fields, can be told apart from the
+// next commit.
+const fileLogFormat = "%x01%H%x00%h%x00%an%x00%ae%x00%ad%x00%s"
+
+// A fileLogEntry is a commit in the history of a file.
+type fileLogEntry struct {
+	Commit       string      `json:"commit"`
+	ShortCommit  string      `json:"-"`
+	Author       string      `json:"author"`
+	AuthorEmail  string      `json:"authorEmail"`
+	Date         string      `json:"date"`
+	Summary      string      `json:"summary"`
+	Status       string      `json:"status,omitempty"`
+	Path         git.TopPath `json:"path"`
+	PreviousPath git.TopPath `json:"previousPath,omitempty"`
+}
+
+// String formats the entry as a line of gg filelog's output, followed by
+// a breadcrumb line if the commit renamed the file.
+func (ent *fileLogEntry) String() string {
+	return ent.format(nil)
+}
+
+// format is like String but formats the previous name with pf.
+func (ent *fileLogEntry) format(pf *pathFormatter) string {
+	s := fmt.Sprintf("%s %s %s  %s\n", ent.ShortCommit, ent.Date, ent.Author, ent.Summary)
+	if ent.PreviousPath != "" {
+		s += fmt.Sprintf("\tpreviously %s\n", pf.format(ent.PreviousPath))
+	}
+	return s
+}
+
+// parseFileLog parses the output of git log -z --follow --name-status
+// with fileLogFormat for the file whose name at the starting revision is
+// name.
+func parseFileLog(out string, name git.TopPath) ([]*fileLogEntry, error) {
+	var entries []*fileLogEntry
+	records := strings.Split(out, "\x01")
+	if records[0] != "" {
+		return nil, fmt.Errorf("parse log: unexpected %q before first commit", records[0])
+	}
+	for _, rec := range records[1:] {
+		fields := strings.Split(rec, "\x00")
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("parse log: malformed commit %q", rec)
+		}
+		ent := &fileLogEntry{
+			Commit:      fields[0],
+			ShortCommit: fields[1],
+			Author:      fields[2],
+			AuthorEmail: fields[3],
+			Date:        fields[4],
+			Summary:     fields[5],
+			Path:        name,
+		}
+		// The name-status entries follow a newline. Merge commits have
+		// none, so their file name is the same as in the newer commit.
+		changes := fields[6:]
+		if len(changes) > 0 {
+			changes[0] = strings.TrimPrefix(changes[0], "\n")
+		}
+		for len(changes) > 0 && changes[0] != "" {
+			status := changes[0]
+			switch {
+			case strings.HasPrefix(status, "R") || strings.HasPrefix(status, "C"):
+				if len(changes) < 3 {
+					return nil, fmt.Errorf("parse log: %s: malformed %s entry", ent.Commit, status)
+				}
+				ent.Status = status[:1]
+				ent.PreviousPath = git.TopPath(changes[1])
+				ent.Path = git.TopPath(changes[2])
+				changes = changes[3:]
+			default:
+				if len(changes) < 2 {
+					return nil, fmt.Errorf("parse log: %s: malformed %s entry", ent.Commit, status)
+				}
+				ent.Status = status
+				ent.Path = git.TopPath(changes[1])
+				changes = changes[2:]
+			}
+		}
+		entries = append(entries, ent)
+		name = ent.Path
+		if ent.PreviousPath != "" {
+			name = ent.PreviousPath
+		}
+	}
+	return entries, nil
+}
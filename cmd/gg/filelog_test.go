@@ -0,0 +1,157 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseFileLog(t *testing.T) {
+	out := "\x01cccc3333\x00cccc\x00Alice\x00alice@example.com\x002026-01-03\x00Edit\x00\nM\x00new.go\x00" +
+		"\x01bbbb2222\x00bbbb\x00Bob\x00bob@example.com\x002026-01-02\x00Move\x00\nR100\x00src/old.go\x00new.go\x00" +
+		"\x01aaaa1111\x00aaaa\x00Alice\x00alice@example.com\x002026-01-01\x00Initial commit\x00\nA\x00src/old.go\x00"
+	got, err := parseFileLog(out, "new.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*fileLogEntry{
+		{
+			Commit:      "cccc3333",
+			ShortCommit: "cccc",
+			Author:      "Alice",
+			AuthorEmail: "alice@example.com",
+			Date:        "2026-01-03",
+			Summary:     "Edit",
+			Status:      "M",
+			Path:        "new.go",
+		},
+		{
+			Commit:       "bbbb2222",
+			ShortCommit:  "bbbb",
+			Author:       "Bob",
+			AuthorEmail:  "bob@example.com",
+			Date:         "2026-01-02",
+			Summary:      "Move",
+			Status:       "R",
+			Path:         "new.go",
+			PreviousPath: "src/old.go",
+		},
+		{
+			Commit:      "aaaa1111",
+			ShortCommit: "aaaa",
+			Author:      "Alice",
+			AuthorEmail: "alice@example.com",
+			Date:        "2026-01-01",
+			Summary:     "Initial commit",
+			Status:      "A",
+			Path:        "src/old.go",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseFileLog(...) (-want +got):\n%s", diff)
+	}
+}
+
+func TestFilelog(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("src/old.go", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "src/old.go"); err != nil {
+		t.Fatal(err)
+	}
+	c1, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "mv", "src/old.go", "new.go"); err != nil {
+		t.Fatal(err)
+	}
+	c2, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("new.go", dummyContent+"more\n")); err != nil {
+		t.Fatal(err)
+	}
+	c3, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "filelog", "new.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(lines) != 4 || lines[2] != "\tpreviously src/old.go" {
+		t.Errorf("gg filelog new.go output:\n%s\nwant 3 commits with a breadcrumb after the second", out)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "filelog", "--json", "new.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*fileLogEntry
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal output: %v; output:\n%s", err, out)
+	}
+	type entry struct {
+		commit       git.Hash
+		path         git.TopPath
+		previousPath git.TopPath
+	}
+	want := []entry{
+		{c3, "new.go", ""},
+		{c2, "new.go", "src/old.go"},
+		{c1, "src/old.go", ""},
+	}
+	var gotEntries []entry
+	for _, ent := range got {
+		h, err := git.ParseHash(ent.Commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotEntries = append(gotEntries, entry{h, ent.Path, ent.PreviousPath})
+	}
+	if diff := cmp.Diff(want, gotEntries, cmp.AllowUnexported(entry{})); diff != "" {
+		t.Errorf("gg filelog --json new.go (-want +got):\n%s", diff)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "filelog", "-p", "new.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "+more") || !strings.Contains(string(out), "rename from src/old.go") {
+		t.Errorf("gg filelog -p new.go output:\n%s\nwant diffs for the edit and the rename", out)
+	}
+}
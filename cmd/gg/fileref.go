@@ -0,0 +1,151 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+const fileRefHelp = `
+
+	A file argument of the form BRANCH:PATH names the file at PATH
+	(relative to the top of the repository) on BRANCH, which may be any
+	revision. If BRANCH is checked out in another worktree, the file is
+	read from that worktree, including its uncommitted changes. A file in
+	the working copy whose name contains a colon is still used as is.`
+
+// A fileRef is a file named by a BRANCH:PATH argument.
+type fileRef struct {
+	branch   string // the text before the colon
+	rev      *git.Rev
+	path     git.TopPath
+	worktree string // absolute path of the worktree to read from, or empty
+}
+
+// parseFileRef returns the file that arg names if it is of the form
+// BRANCH:PATH, or nil if arg is an ordinary file argument.
+func parseFileRef(ctx context.Context, cc *cmdContext, arg string) (*fileRef, error) {
+	branch, p, ok := strings.Cut(arg, ":")
+	if !ok || branch == "" || strings.HasPrefix(branch, "-") || isPatternKind(branch) {
+		return nil, nil
+	}
+	if _, err := os.Lstat(cc.abs(arg)); err == nil {
+		return nil, nil
+	}
+	rev, err := cc.git.ParseRev(ctx, branch)
+	if err != nil {
+		return nil, nil
+	}
+	p = path.Clean("/" + filepath.ToSlash(p))[1:]
+	if p == "" {
+		return nil, fmt.Errorf("%s: missing path after colon", arg)
+	}
+	ref := &fileRef{
+		branch: branch,
+		rev:    rev,
+		path:   git.TopPath(p),
+	}
+	if b := rev.Ref.Branch(); b != "" {
+		ref.worktree, err = otherWorktreeFor(ctx, cc, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ref, nil
+}
+
+func (ref *fileRef) String() string {
+	return ref.branch + ":" + ref.path.String()
+}
+
+// open returns the content of the file.
+func (ref *fileRef) open(ctx context.Context, g *git.Git) (io.ReadCloser, error) {
+	if ref.worktree != "" {
+		return os.Open(filepath.Join(ref.worktree, filepath.FromSlash(ref.path.String())))
+	}
+	return g.Cat(ctx, ref.rev.Commit.String(), ref.path)
+}
+
+// objectName returns a Git object name for the file's content, writing
+// a blob to the repository if the file comes from a worktree.
+func (ref *fileRef) objectName(ctx context.Context, g *git.Git) (string, error) {
+	if ref.worktree == "" {
+		return ref.String(), nil
+	}
+	return hashWorkingFile(ctx, g, filepath.Join(ref.worktree, filepath.FromSlash(ref.path.String())))
+}
+
+// hashWorkingFile writes the file at the given path to the repository as
+// a blob and returns the blob's hash.
+func hashWorkingFile(ctx context.Context, g *git.Git, filename string) (string, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return "", err
+	}
+	out, err := g.Output(ctx, "hash-object", "-w", "--", filename)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// otherWorktreeFor returns the path of the worktree other than the
+// current one that has the given branch checked out, or the empty string
+// if there is none.
+func otherWorktreeFor(ctx context.Context, cc *cmdContext, branch string) (string, error) {
+	out, err := cc.git.Output(ctx, "worktree", "list", "--porcelain")
+	if err != nil {
+		return "", err
+	}
+	current, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		// A bare repository has no worktree of its own.
+		current = ""
+	}
+	for _, wt := range parseWorktreeList(out) {
+		if wt.branch == branch && filepath.Clean(wt.path) != filepath.Clean(current) {
+			return wt.path, nil
+		}
+	}
+	return "", nil
+}
+
+// A worktreeEntry is a worktree listed by git worktree list --porcelain.
+type worktreeEntry struct {
+	path   string
+	branch string // empty if detached
+}
+
+func parseWorktreeList(out string) []worktreeEntry {
+	var list []worktreeEntry
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			list = append(list, worktreeEntry{path: strings.TrimPrefix(line, "worktree ")})
+		case strings.HasPrefix(line, "branch ") && len(list) > 0:
+			list[len(list)-1].branch = git.Ref(strings.TrimPrefix(line, "branch ")).Branch()
+		}
+	}
+	return list
+}
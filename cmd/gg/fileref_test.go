@@ -0,0 +1,157 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+// setupFileRefRepo creates a repository in "repo" with foo.txt on main
+// and a topic branch that changes it, checked out in the linked worktree
+// "topic-wt" with an uncommitted change to bar.txt.
+func setupFileRefRepo(ctx context.Context, env *testEnv) error {
+	if err := env.initEmptyRepo(ctx, "repo"); err != nil {
+		return err
+	}
+	if err := env.root.Apply(
+		filesystem.Write("repo/foo.txt", "main foo\n"),
+		filesystem.Write("repo/dir/bar.txt", "main bar\n"),
+	); err != nil {
+		return err
+	}
+	if err := env.addFiles(ctx, "repo/foo.txt", "repo/dir/bar.txt"); err != nil {
+		return err
+	}
+	if _, err := env.newCommit(ctx, "repo"); err != nil {
+		return err
+	}
+	repoGit := env.git.WithDir(env.root.FromSlash("repo"))
+	if err := repoGit.NewBranch(ctx, "topic", git.BranchOptions{}); err != nil {
+		return err
+	}
+	if err := repoGit.Run(ctx, "worktree", "add", "--quiet", env.root.FromSlash("topic-wt"), "topic"); err != nil {
+		return err
+	}
+	if err := env.root.Apply(filesystem.Write("topic-wt/foo.txt", "topic foo\n")); err != nil {
+		return err
+	}
+	if _, err := env.newCommit(ctx, "topic-wt"); err != nil {
+		return err
+	}
+	return env.root.Apply(filesystem.Write("topic-wt/dir/bar.txt", "dirty topic bar\n"))
+}
+
+func TestCat_FileRef(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupFileRefRepo(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		dir  string
+		args []string
+		want string
+	}{
+		{dir: "repo", args: []string{"topic:foo.txt"}, want: "topic foo\n"},
+		{dir: "repo", args: []string{"topic:dir/bar.txt"}, want: "dirty topic bar\n"},
+		{dir: "repo", args: []string{"topic~1:foo.txt"}, want: "main foo\n"},
+		{dir: "repo/dir", args: []string{"topic:foo.txt"}, want: "topic foo\n"},
+		{dir: "topic-wt", args: []string{"main:dir/bar.txt"}, want: "main bar\n"},
+		// From its own worktree, a branch's files come from the commit.
+		{dir: "topic-wt", args: []string{"topic:dir/bar.txt"}, want: "main bar\n"},
+	}
+	for _, test := range tests {
+		out, err := env.gg(ctx, env.root.FromSlash(test.dir), append([]string{"cat"}, test.args...)...)
+		if err != nil {
+			t.Errorf("in %s, gg cat %s: %v", test.dir, strings.Join(test.args, " "), err)
+			continue
+		}
+		if string(out) != test.want {
+			t.Errorf("in %s, gg cat %s = %q; want %q", test.dir, strings.Join(test.args, " "), out, test.want)
+		}
+	}
+}
+
+func TestDiff_FileRef(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupFileRefRepo(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	repoDir := env.root.FromSlash("repo")
+
+	out, err := env.gg(ctx, repoDir, "diff", "main:foo.txt", "topic:foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); !strings.Contains(got, "-main foo\n") || !strings.Contains(got, "+topic foo\n") {
+		t.Errorf("gg diff main:foo.txt topic:foo.txt =\n%s\nwant change from main foo to topic foo", got)
+	}
+
+	// A single argument is compared with the working copy.
+	out, err = env.gg(ctx, repoDir, "diff", "topic:dir/bar.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); !strings.Contains(got, "-dirty topic bar\n") || !strings.Contains(got, "+main bar\n") {
+		t.Errorf("gg diff topic:dir/bar.txt =\n%s\nwant change from dirty topic bar to main bar", got)
+	}
+
+	if _, err := env.gg(ctx, repoDir, "diff", "-r", "HEAD", "topic:foo.txt"); err == nil {
+		t.Error("gg diff -r HEAD topic:foo.txt did not return an error")
+	} else if !isUsage(err) {
+		t.Errorf("gg diff -r HEAD topic:foo.txt error = %v; want usage", err)
+	}
+}
+
+func TestParseWorktreeList(t *testing.T) {
+	const out = "worktree /src/repo\n" +
+		"HEAD 8ed3b1b3b1c0d1b7c2b6a4d58c8f6e8e5a9c3d11\n" +
+		"branch refs/heads/main\n" +
+		"\n" +
+		"worktree /src/topic\n" +
+		"HEAD 2c6f1f8b9d8e7a6b5c4d3e2f1a0b9c8d7e6f5a4b\n" +
+		"branch refs/heads/topic\n" +
+		"\n" +
+		"worktree /src/detached\n" +
+		"HEAD 2c6f1f8b9d8e7a6b5c4d3e2f1a0b9c8d7e6f5a4b\n" +
+		"detached\n" +
+		"\n"
+	want := []worktreeEntry{
+		{path: "/src/repo", branch: "main"},
+		{path: "/src/topic", branch: "topic"},
+		{path: "/src/detached"},
+	}
+	got := parseWorktreeList(out)
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(worktreeEntry{})); diff != "" {
+		t.Errorf("parseWorktreeList(...) (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const fixupSynopsis = "commit changes as a fix to an earlier commit"
+
+func fixup(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg fixup --to REV [--squash] [--no-verify] [FILE [...]]", fixupSynopsis+`
+
+	Commits changes to the given files, or all outstanding changes if no
+	files are given, with a message that marks the commit as a fix to REV.
+	REV must be an ancestor of the working copy's parent. The next
+	`+"`gg rebase`"+`, `+"`gg evolve`"+`, or `+"`gg histedit`"+` that moves both
+	commits folds the fix into REV.
+
+	With `+"`--squash`"+`, the commit is marked with "squash!" instead of
+	"fixup!", and its message is added to REV's message when they are
+	folded together.`+commitGuardHelp)
+	to := f.String("to", "", "`rev`ision to fix")
+	squash := f.Bool("squash", false, "keep this commit's message when folding it into the revision")
+	msg := f.String("m", "", "with --squash, use text as the commit `message` to add")
+	runHooks := f.Bool("hooks", true, "whether to run Git hooks")
+	noVerify := f.Bool("no-verify", false, noVerifyUsage)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if *to == "" {
+		return usagef("must pass --to")
+	}
+	if strings.HasPrefix(*to, "-") {
+		return usagef("--to revision cannot start with '-'")
+	}
+	if *msg != "" && !*squash {
+		return usagef("-m requires --squash")
+	}
+	r, err := cc.git.ParseRev(ctx, *to)
+	if err != nil {
+		return err
+	}
+	target, err := cc.git.CommitInfo(ctx, r.Commit.String())
+	if err != nil {
+		return err
+	}
+	if ancestor, err := cc.git.IsAncestor(ctx, r.Commit.String(), git.Head.String()); err != nil {
+		return err
+	} else if !ancestor {
+		return fmt.Errorf("%s is not an ancestor of the working copy", *to)
+	}
+	var pathspecs []git.Pathspec
+	for _, arg := range f.Args() {
+		pathspecs = append(pathspecs, git.LiteralPath(arg))
+	}
+	if !*noVerify {
+		if err := checkCommitChanges(ctx, cc, pathspecs); err != nil {
+			return err
+		}
+	}
+	prefix := "fixup! "
+	if *squash {
+		prefix = "squash! "
+	}
+	subject := prefix + target.Summary()
+	if !*squash {
+		return doCommit(ctx, cc, subject, "", pathspecs, *runHooks)
+	}
+	if *msg != "" {
+		return doCommit(ctx, cc, subject+"\n\n"+*msg, "", pathspecs, *runHooks)
+	}
+	return doCommit(ctx, cc, "", subject+"\n\n", pathspecs, *runHooks)
+}
+
+// hasAutosquashCommits reports whether any of the commits reachable from
+// tip but not from upstream are fixup!, squash!, or amend! commits that
+// git rebase --autosquash would move.
+func hasAutosquashCommits(ctx context.Context, g *git.Git, tip, upstream string) (bool, error) {
+	out, err := g.Output(ctx, "log", "--no-merges", "--format=%s", tip, "^"+upstream, "--")
+	if err != nil {
+		return false, err
+	}
+	for _, subject := range strings.Split(out, "\n") {
+		if isAutosquashSubject(subject) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isAutosquashSubject(subject string) bool {
+	return strings.HasPrefix(subject, "fixup! ") ||
+		strings.HasPrefix(subject, "squash! ") ||
+		strings.HasPrefix(subject, "amend! ")
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestFixup(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository with two commits on a branch called "topic" and
+	// a diverging commit on "main".
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "topic", git.BranchOptions{Track: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("mainline.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "mainline.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.CheckoutBranch(ctx, "topic", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "foo\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "commit", "--quiet", "-m", "Add foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("bar.txt", "bar\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "commit", "--quiet", "-m", "Add bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fix the first commit.
+	if err := env.root.Apply(filesystem.Write("foo.txt", "fixed foo\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "fixup", "--to=HEAD~1"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := info.Message, "fixup! Add foo\n"; got != want {
+		t.Errorf("fixup commit message = %q; want %q", got, want)
+	}
+
+	// Rebasing should fold the fix into the first commit.
+	if _, err := env.gg(ctx, env.root.String(), "rebase"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.git.Output(ctx, "log", "--format=%s", "main..HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out, "Add bar\nAdd foo\n"; got != want {
+		t.Errorf("commits after rebase:\n%s\nwant:\n%s", got, want)
+	}
+	if got, err := env.git.Output(ctx, "show", "HEAD~1:foo.txt"); err != nil {
+		t.Error(err)
+	} else if want := "fixed foo\n"; got != want {
+		t.Errorf("foo.txt in first commit = %q; want %q", got, want)
+	}
+}
+
+func TestFixup_NotAncestor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "other", git.BranchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.CheckoutBranch(ctx, "other", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("other.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "other.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.CheckoutBranch(ctx, "main", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, env.root.String(), "fixup", "--to=other")
+	if err == nil {
+		t.Fatal("gg fixup --to=other succeeded")
+	}
+	if isUsage(err) {
+		t.Errorf("gg fixup --to=other returned usage error: %v", err)
+	}
+	if !strings.Contains(err.Error(), "not an ancestor") {
+		t.Errorf("error = %v; want to mention ancestor", err)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const foldSynopsis = "combine a series of commits into one"
+
+func fold(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg fold -r REV1::REV2 [-m MSG] [--force] [--force-protected]\n"+
+		"gg fold -r REV [-m MSG] [--force] [--force-protected]", foldSynopsis+`
+
+aliases: squash
+
+	Replaces the commits from REV1 through REV2 with a single commit that
+	has their combined changes, as in Mercurial's fold extension. With a
+	single REV, the commits from REV through the working copy's parent
+	are folded. The commits must be a contiguous run of non-merge commits
+	on the working copy's first-parent history. Commits after REV2 are
+	moved on top of the new commit unchanged.
+
+	An editor is opened with the messages of the folded commits, unless a
+	message is given with `+"`-m`"+`. The new commit keeps the author of
+	REV1. The working copy is not touched.
+
+	`+"`gg fold`"+` refuses to fold commits that are already on a remote
+	branch, since the result would have to be force-pushed. Pass
+	`+"`--force`"+` to fold them anyway.`+protectedBranchHelp)
+	rev := f.String("r", "", "`range` of revisions to fold, like REV1::REV2")
+	msg := f.String("m", "", "use text as the commit `message`")
+	force := f.Bool("force", false, allowRewritePublishedUsage)
+	f.Alias("force", "f")
+	forceProtected := f.Bool("force-protected", false, forceProtectedUsage)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 0 {
+		return usagef("fold takes no arguments")
+	}
+	if *rev == "" {
+		return usagef("must pass -r")
+	}
+	firstRev, lastRev, isRange := strings.Cut(*rev, "::")
+	if !isRange {
+		lastRev = git.Head.String()
+	}
+	if firstRev == "" || lastRev == "" || strings.HasPrefix(firstRev, "-") || strings.HasPrefix(lastRev, "-") {
+		return usagef("invalid revision range %q", *rev)
+	}
+	first, err := cc.git.ParseRev(ctx, firstRev)
+	if err != nil {
+		return err
+	}
+	last, err := cc.git.ParseRev(ctx, lastRev)
+	if err != nil {
+		return err
+	}
+	if !*forceProtected {
+		if err := checkRewriteProtected(ctx, cc, "fold"); err != nil {
+			return err
+		}
+	}
+	return foldCommits(ctx, cc, first.Commit, last.Commit, *msg, *force)
+}
+
+// foldCommits replaces the commits from first through last with a single
+// commit that has last's tree and first's author, then recreates the
+// commits after last up to HEAD on top of it. Like rewordCommits, it
+// uses git commit-tree, so the working copy is not touched. If msg is
+// empty, the user is prompted for a message in an editor.
+func foldCommits(ctx context.Context, cc *cmdContext, first, last git.Hash, msg string, allowPublished bool) error {
+	if ok, err := cc.git.IsAncestor(ctx, last.String(), git.Head.String()); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("%s is not an ancestor of the working copy", shortHash(last.String()))
+	}
+	firstInfo, err := cc.git.CommitInfo(ctx, first.String())
+	if err != nil {
+		return err
+	}
+	if len(firstInfo.Parents) > 1 {
+		return fmt.Errorf("%s is a merge commit; only a linear series can be folded", shortHash(first.String()))
+	}
+	revListArgs := []string{"rev-list", "--reverse", "--first-parent", "--parents", git.Head.String()}
+	var base string
+	if len(firstInfo.Parents) == 1 {
+		base = firstInfo.Parents[0].String()
+		revListArgs = append(revListArgs, "^"+base)
+	}
+	out, err := cc.git.Output(ctx, append(revListArgs, "--")...)
+	if err != nil {
+		return err
+	}
+	var stack []string
+	lastIndex := -1
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			return fmt.Errorf("%s is a merge commit; only a linear series can be folded", shortHash(fields[0]))
+		}
+		if fields[0] == last.String() {
+			lastIndex = len(stack)
+		}
+		stack = append(stack, fields[0])
+	}
+	if len(stack) == 0 || stack[0] != first.String() || lastIndex == -1 {
+		return fmt.Errorf("%s is not an ancestor of %s", shortHash(first.String()), shortHash(last.String()))
+	}
+	if lastIndex == 0 {
+		return errors.New("need at least two commits to fold")
+	}
+	if !allowPublished {
+		revs := []string{git.Head.String()}
+		if base != "" {
+			revs = append(revs, "^"+base)
+		}
+		if err := checkRewritePublished(ctx, cc.git, "fold", revs...); err != nil {
+			return err
+		}
+	}
+
+	folded := make([]*git.CommitInfo, 0, lastIndex+1)
+	for _, c := range stack[:lastIndex+1] {
+		info, err := cc.git.CommitInfo(ctx, c)
+		if err != nil {
+			return err
+		}
+		folded = append(folded, info)
+	}
+	if msg == "" {
+		msg, err = editFoldMessage(ctx, cc, folded)
+		if err != nil {
+			return err
+		}
+	} else {
+		msg = cleanupMessage(msg, "")
+	}
+
+	lastInfo := folded[len(folded)-1]
+	newParent, err := commitTreeLike(ctx, cc, firstInfo, lastInfo.Tree.String(), base, msg, false)
+	if err != nil {
+		return fmt.Errorf("fold: %w", err)
+	}
+	for _, c := range stack[lastIndex+1:] {
+		info, err := cc.git.CommitInfo(ctx, c)
+		if err != nil {
+			return err
+		}
+		newParent, err = commitTreeLike(ctx, cc, info, info.Tree.String(), newParent, info.Message, true)
+		if err != nil {
+			return fmt.Errorf("move %s: %w", shortHash(c), err)
+		}
+	}
+	oldHead := stack[len(stack)-1]
+	if err := cc.git.Run(ctx, "update-ref", "-m", "gg fold", git.Head.String(), newParent, oldHead); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cc.stdout, "folded %d commits into %s\n", len(folded), shortHash(newParent))
+	return err
+}
+
+// editFoldMessage opens an editor with the messages of the commits being
+// folded and returns the result.
+func editFoldMessage(ctx context.Context, cc *cmdContext, folded []*git.CommitInfo) (string, error) {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	commentChar, err := cfg.CommentChar()
+	if err != nil {
+		return "", err
+	}
+	template := new(strings.Builder)
+	for i, info := range folded {
+		if i > 0 {
+			template.WriteString("\n")
+		}
+		template.WriteString(info.Message)
+	}
+	fmt.Fprintf(template, "\n%s Folding %d commits. Lines starting with %q will be ignored,\n", commentChar, len(folded), commentChar)
+	fmt.Fprintf(template, "%s and an empty message aborts the fold.\n", commentChar)
+	edited, err := cc.editor.open(ctx, commitMsgFilename, []byte(template.String()))
+	if err != nil {
+		return "", err
+	}
+	msg := cleanupMessage(string(edited), commentChar)
+	if strings.TrimSpace(msg) == "" {
+		return "", errors.New("empty message; history not changed")
+	}
+	return msg, nil
+}
+
+// commitTreeLike creates a commit with the given tree, parent, and
+// message that has the same author as info. If keepCommitDate is true,
+// the committer date is kept too. An empty parent creates a root commit.
+// It returns the new commit's hash.
+func commitTreeLike(ctx context.Context, cc *cmdContext, info *git.CommitInfo, tree, parent, msg string, keepCommitDate bool) (string, error) {
+	gitOpts := cc.gitOptions
+	gitOpts.Dir = cc.dir
+	gitOpts.Env = append(append([]string(nil), gitOpts.Env...),
+		"GIT_AUTHOR_NAME="+info.Author.Name(),
+		"GIT_AUTHOR_EMAIL="+info.Author.Email(),
+		"GIT_AUTHOR_DATE="+gitDate(info.AuthorTime),
+	)
+	if keepCommitDate {
+		gitOpts.Env = append(gitOpts.Env, "GIT_COMMITTER_DATE="+gitDate(info.CommitTime))
+	}
+	commitGit, err := git.New(gitOpts)
+	if err != nil {
+		return "", err
+	}
+	args := []string{"commit-tree", tree}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	args = append(args, "-m", strings.TrimSuffix(msg, "\n"))
+	out, err := commitGit.Output(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
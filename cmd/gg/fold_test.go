@@ -0,0 +1,152 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+// setupFoldTest creates three commits after the initial commit, each
+// adding a file with the same name as the commit's message.
+func setupFoldTest(ctx context.Context, env *testEnv) error {
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		return err
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if err := env.root.Apply(filesystem.Write(name, name+"\n")); err != nil {
+			return err
+		}
+		if err := env.addFiles(ctx, name); err != nil {
+			return err
+		}
+		if err := env.git.Commit(ctx, name, git.CommitOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestFold(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupFoldTest(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	oldHead, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "fold", "-r", "HEAD~2::HEAD~1", "-m", "a and b"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.git.Output(ctx, "log", "--format=%s", "-3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out, "c\na and b\nremoved dummy file\n"; got != want {
+		t.Errorf("log = %q; want %q", got, want)
+	}
+	newHead, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newHead.Tree != oldHead.Tree {
+		t.Errorf("HEAD tree = %v; want %v", newHead.Tree, oldHead.Tree)
+	}
+	for _, name := range []string{"a", "b"} {
+		if err := objectExists(ctx, env.git, "HEAD~", name); err != nil {
+			t.Errorf("%s missing from folded commit: %v", name, err)
+		}
+	}
+}
+
+func TestFold_Editor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupFoldTest(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.writeConfig([]byte("[core]\neditor = true\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "squash", "-r", "HEAD~1"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := info.Message, "b\n\nc\n"; got != want {
+		t.Errorf("message = %q; want %q", got, want)
+	}
+	if got, want := info.Summary(), "b"; got != want {
+		t.Errorf("summary = %q; want %q", got, want)
+	}
+}
+
+func TestFold_Published(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupFoldTest(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "update-ref", "refs/remotes/origin/main", "HEAD~1"); err != nil {
+		t.Fatal(err)
+	}
+	oldHead, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "fold", "-r", "HEAD~2", "-m", "all"); err == nil {
+		t.Error("fold of published commits succeeded")
+	}
+	if head, err := env.git.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if head.Commit != oldHead.Commit {
+		t.Errorf("HEAD = %v after failed fold; want %v", head.Commit, oldHead.Commit)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "fold", "--force", "-r", "HEAD~2", "-m", "all"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.git.Output(ctx, "log", "--format=%s", "-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out, "all\nremoved dummy file\n"; got != want {
+		t.Errorf("log = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// A forge is a code hosting service that serves web pages for a repository.
+type forge struct {
+	kind    string // "github" or "gitlab"
+	repoURL string // web page of the repository, without a trailing slash
+}
+
+// forgeForRemote returns the forge that hosts the repository at the
+// given remote URL or nil if the URL is not from a known forge.
+func forgeForRemote(remoteURL string) *forge {
+	host, repoPath := splitRemoteURL(remoteURL)
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if repoPath == "" || strings.Contains(repoPath, "//") {
+		return nil
+	}
+	switch host {
+	case "github.com":
+		owner, repo := parseGitHubRemoteURL(remoteURL)
+		if owner == "" {
+			return nil
+		}
+		return &forge{kind: "github", repoURL: "https://github.com/" + owner + "/" + repo}
+	case "gitlab.com":
+		// GitLab permits nested groups, so the path has at least two elements.
+		if !strings.Contains(repoPath, "/") {
+			return nil
+		}
+		return &forge{kind: "gitlab", repoURL: "https://gitlab.com/" + repoPath}
+	default:
+		return nil
+	}
+}
+
+// splitRemoteURL returns the host and path of a Git remote URL in either
+// URL form or scp-like form (like "git@github.com:foo/bar.git").
+func splitRemoteURL(remoteURL string) (host, path string) {
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil || u.RawQuery != "" || u.Fragment != "" {
+			return "", ""
+		}
+		return u.Hostname(), u.Path
+	}
+	i := strings.IndexByte(remoteURL, ':')
+	if i == -1 || strings.ContainsRune(remoteURL[:i], '/') {
+		// Local path.
+		return "", ""
+	}
+	host = remoteURL[:i]
+	if j := strings.LastIndexByte(host, '@'); j != -1 {
+		host = host[j+1:]
+	}
+	return host, remoteURL[i+1:]
+}
+
+// forgeForRepository returns the forge for the repository's default
+// remote: the remote of the current branch or "origin". It returns nil
+// if the remote is not hosted on a known forge.
+func forgeForRepository(cfg *git.Config, branch string) *forge {
+	remotes := cfg.ListRemotes()
+	var remote *git.Remote
+	if branch != "" {
+		remote = remotes[cfg.Value("branch."+branch+".remote")]
+	}
+	if remote == nil {
+		remote = remotes["origin"]
+	}
+	if remote == nil {
+		return nil
+	}
+	return forgeForRemote(remote.FetchURL)
+}
+
+// commitURL returns the URL of the web page for a commit.
+func (f *forge) commitURL(hash string) string {
+	switch f.kind {
+	case "gitlab":
+		return f.repoURL + "/-/commit/" + hash
+	default:
+		return f.repoURL + "/commit/" + hash
+	}
+}
+
+// changeURL returns the URL of the web page for a pull request or (on
+// GitLab) a merge request.
+func (f *forge) changeURL(number string) string {
+	switch f.kind {
+	case "gitlab":
+		return f.repoURL + "/-/merge_requests/" + number
+	default:
+		return f.repoURL + "/pull/" + number
+	}
+}
+
+// fileURL returns the URL of the web page for a file at a commit.
+func (f *forge) fileURL(rev string, file git.TopPath) string {
+	escaped := new(strings.Builder)
+	for i, part := range strings.Split(path.Clean(string(file)), "/") {
+		if i > 0 {
+			escaped.WriteByte('/')
+		}
+		escaped.WriteString(url.PathEscape(part))
+	}
+	switch f.kind {
+	case "gitlab":
+		return f.repoURL + "/-/blob/" + rev + "/" + escaped.String()
+	default:
+		return f.repoURL + "/blob/" + rev + "/" + escaped.String()
+	}
+}
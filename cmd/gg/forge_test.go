@@ -0,0 +1,111 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestForgeForRemote(t *testing.T) {
+	tests := []struct {
+		url  string
+		want *forge
+	}{
+		{
+			url:  "https://github.com/example/foo.git",
+			want: &forge{kind: "github", repoURL: "https://github.com/example/foo"},
+		},
+		{
+			url:  "git@github.com:example/foo.git",
+			want: &forge{kind: "github", repoURL: "https://github.com/example/foo"},
+		},
+		{
+			url:  "ssh://git@github.com/example/foo",
+			want: &forge{kind: "github", repoURL: "https://github.com/example/foo"},
+		},
+		{
+			url:  "https://gitlab.com/group/subgroup/foo.git",
+			want: &forge{kind: "gitlab", repoURL: "https://gitlab.com/group/subgroup/foo"},
+		},
+		{
+			url:  "git@gitlab.com:group/foo.git",
+			want: &forge{kind: "gitlab", repoURL: "https://gitlab.com/group/foo"},
+		},
+		{
+			url:  "https://gitlab.com/foo.git",
+			want: nil,
+		},
+		{
+			url:  "https://example.com/foo.git",
+			want: nil,
+		},
+		{
+			url:  "/home/me/foo.git",
+			want: nil,
+		},
+		{
+			url:  "../foo",
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		got := forgeForRemote(test.url)
+		if diff := cmp.Diff(test.want, got, cmp.AllowUnexported(forge{})); diff != "" {
+			t.Errorf("forgeForRemote(%q) (-want +got):\n%s", test.url, diff)
+		}
+	}
+}
+
+func TestForgeURLs(t *testing.T) {
+	const hash = "8bde7f5b7d57a4b3b1d2d0a6f6a1bb4d11f3b1f5"
+	tests := []struct {
+		forge     *forge
+		commitURL string
+		changeURL string
+		file      git.TopPath
+		fileURL   string
+	}{
+		{
+			forge:     &forge{kind: "github", repoURL: "https://github.com/example/foo"},
+			commitURL: "https://github.com/example/foo/commit/" + hash,
+			changeURL: "https://github.com/example/foo/pull/42",
+			file:      "docs/read me.md",
+			fileURL:   "https://github.com/example/foo/blob/" + hash + "/docs/read%20me.md",
+		},
+		{
+			forge:     &forge{kind: "gitlab", repoURL: "https://gitlab.com/group/foo"},
+			commitURL: "https://gitlab.com/group/foo/-/commit/" + hash,
+			changeURL: "https://gitlab.com/group/foo/-/merge_requests/42",
+			file:      "main.go",
+			fileURL:   "https://gitlab.com/group/foo/-/blob/" + hash + "/main.go",
+		},
+	}
+	for _, test := range tests {
+		if got := test.forge.commitURL(hash); got != test.commitURL {
+			t.Errorf("%s commitURL(...) = %q; want %q", test.forge.kind, got, test.commitURL)
+		}
+		if got := test.forge.changeURL("42"); got != test.changeURL {
+			t.Errorf("%s changeURL(\"42\") = %q; want %q", test.forge.kind, got, test.changeURL)
+		}
+		if got := test.forge.fileURL(hash, test.file); got != test.fileURL {
+			t.Errorf("%s fileURL(..., %q) = %q; want %q", test.forge.kind, test.file, got, test.fileURL)
+		}
+	}
+}
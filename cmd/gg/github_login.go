@@ -18,19 +18,39 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"gg-scm.io/pkg/ghdevice"
 	"gg-scm.io/tool/internal/flag"
+	"golang.org/x/exp/slices"
 )
 
 const gitHubLoginSynopsis = "log into GitHub"
 
 func gitHubLogin(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg github-login", gitHubLoginSynopsis)
+	f := flag.NewFlagSet(true, "gg github-login [--account ACCOUNT] [--check]", gitHubLoginSynopsis+`
+
+	Authorize gg to use your GitHub account and save the token for
+	`+"`requestpull`"+`, `+"`release`"+`, and `+"`pr comments`"+`. gg asks for the `+"`repo`"+`
+	scope to create pull requests and releases and the `+"`read:org`"+` scope to
+	request reviews from teams.
+
+	`+"`--account`"+` saves the token under a name, so that gg can keep tokens
+	for more than one GitHub account. See `+"`gg auth`"+` to pick the account a
+	repository uses.
+
+	`+"`--check`"+` reports the saved token's account, scopes, expiration, and
+	remaining API requests without logging in again. It fails if the token
+	is missing a scope that gg needs.`)
+	check := f.Bool("check", false, "report on the saved token instead of logging in")
+	accountName := f.String("account", "", "`name` of the account to log into or check")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -40,15 +60,145 @@ func gitHubLogin(ctx context.Context, cc *cmdContext, args []string) error {
 	if f.NArg() != 0 {
 		return usagef("github-login takes no arguments")
 	}
+	if *accountName != "" && !identityNameRegexp.MatchString(*accountName) {
+		return usagef("invalid account name %q (must be lowercase letters, digits, '-', or '_')", *accountName)
+	}
+	if *check {
+		name := *accountName
+		if name == "" {
+			name = selectedGitHubAccount(ctx, cc)
+		}
+		acct, err := readGitHubAccount(cc, name)
+		if os.IsNotExist(err) && name == defaultGitHubAccount {
+			return errors.New("not logged into GitHub; run gg github-login")
+		}
+		if os.IsNotExist(err) {
+			return fmt.Errorf("not logged into GitHub account %s; run gg github-login --account %s", name, name)
+		}
+		if err != nil {
+			return err
+		}
+		info, err := checkGitHubToken(ctx, cc.httpClient, acct.token)
+		if err != nil {
+			return err
+		}
+		if err := info.write(cc.stdout); err != nil {
+			return err
+		}
+		if len(info.missing) > 0 {
+			return fmt.Errorf("token is missing the %s scope; run gg github-login to authorize gg again",
+				strings.Join(info.missing, " and "))
+		}
+		return nil
+	}
+	if *accountName == "" {
+		*accountName = defaultGitHubAccount
+	}
 	token, err := gitHubDeviceFlow(ctx, cc.httpClient, loginRequested, cc.stderr)
 	if err != nil {
 		return err
 	}
-	tokenData := append([]byte(token), '\n')
-	if err := cc.xdgDirs.writeSecret(gitHubTokenFilename, tokenData); err != nil {
+	acct := &gitHubAccount{name: *accountName, token: token}
+	info, checkErr := checkGitHubToken(ctx, cc.httpClient, token)
+	if checkErr == nil {
+		acct.login = info.login
+	}
+	if err := writeGitHubAccount(cc, acct); err != nil {
 		return fmt.Errorf("save token: %w", err)
 	}
 	fmt.Fprintln(cc.stderr, "Success! Your account will remembered in the future.")
+	if checkErr != nil {
+		fmt.Fprintln(cc.stderr, "gg: could not check token:", checkErr)
+		return nil
+	}
+	for _, scope := range gitHubScopes {
+		if slices.Contains(info.missing, scope.name) {
+			fmt.Fprintf(cc.stderr, "gg: warning: token does not have the %s scope needed to %s\n", scope.name, scope.use)
+		}
+	}
+	return nil
+}
+
+// gitHubTokenInfo is what GitHub reports about an authorization token.
+type gitHubTokenInfo struct {
+	login string
+	// scopes is nil for fine-grained tokens,
+	// which have permissions instead of scopes.
+	scopes    []string
+	missing   []string
+	expires   string // empty if the token doesn't expire
+	rateLimit gitHubRateLimit
+}
+
+// checkGitHubToken asks GitHub for the account, scopes, and expiration
+// of token.
+func checkGitHubToken(ctx context.Context, client *http.Client, token string) (*gitHubTokenInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+gitHubAPIHost+"/user", nil)
+	if err != nil {
+		return nil, fmt.Errorf("check GitHub token: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgentString())
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "token "+token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("check GitHub token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, errors.New("check GitHub token: token is invalid or revoked; run gg github-login")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("check GitHub token: %w", parseGitHubErrorResponse(resp))
+	}
+	var user struct {
+		Login string
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("check GitHub token: parsing response: %w", err)
+	}
+	info := &gitHubTokenInfo{
+		login:     user.Login,
+		expires:   resp.Header.Get("GitHub-Authentication-Token-Expiration"),
+		rateLimit: parseGitHubRateLimit(resp.Header),
+	}
+	if v, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		info.scopes = parseGitHubScopes(strings.Join(v, ","))
+		if info.scopes == nil {
+			info.scopes = []string{}
+		}
+		for _, scope := range gitHubScopes {
+			if !hasGitHubScope(info.scopes, scope.name) {
+				info.missing = append(info.missing, scope.name)
+			}
+		}
+	}
+	return info, nil
+}
+
+func (info *gitHubTokenInfo) write(w io.Writer) error {
+	fmt.Fprintf(w, "Logged into GitHub as %s\n", info.login)
+	switch {
+	case info.scopes == nil:
+		fmt.Fprintln(w, "Scopes: none (fine-grained token)")
+	case len(info.scopes) == 0:
+		fmt.Fprintln(w, "Scopes: none")
+	default:
+		fmt.Fprintf(w, "Scopes: %s\n", strings.Join(info.scopes, ", "))
+	}
+	if info.expires == "" {
+		fmt.Fprintln(w, "Expires: never")
+	} else {
+		fmt.Fprintf(w, "Expires: %s\n", info.expires)
+	}
+	if rl := info.rateLimit; rl.limit > 0 {
+		fmt.Fprintf(w, "API requests: %d of %d left until %s\n", rl.remaining, rl.limit, rl.reset.Local().Format("15:04"))
+	}
+	for _, scope := range gitHubScopes {
+		if slices.Contains(info.missing, scope.name) {
+			fmt.Fprintf(w, "Missing scope %s, needed to %s\n", scope.name, scope.use)
+		}
+	}
 	return nil
 }
 
@@ -67,7 +217,7 @@ func gitHubDeviceFlow(ctx context.Context, client *http.Client, mode bool, outpu
 	iteration := 0
 	return ghdevice.Flow(ctx, ghdevice.Options{
 		ClientID:   "4f3e4a5a8231ed09c4ab",
-		Scopes:     []string{"repo"},
+		Scopes:     gitHubScopeNames(),
 		HTTPClient: client,
 		Prompter: func(ctx context.Context, p ghdevice.Prompt) error {
 			if mode == firstTimeLogin && iteration == 0 {
@@ -85,3 +235,11 @@ func gitHubDeviceFlow(ctx context.Context, client *http.Client, mode bool, outpu
 		},
 	})
 }
+
+func gitHubScopeNames() []string {
+	names := make([]string, 0, len(gitHubScopes))
+	for _, scope := range gitHubScopes {
+		names = append(names, scope.name)
+	}
+	return names
+}
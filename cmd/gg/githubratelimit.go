@@ -0,0 +1,227 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const gitHubAPIHost = "api.github.com"
+
+// gitHubRateLimitMaxWait is the longest that gg will wait for GitHub's
+// rate limit to reset before giving up on a request.
+const gitHubRateLimitMaxWait = time.Minute
+
+// A gitHubRateLimit is the rate limit state reported in the headers of a
+// GitHub API response.
+type gitHubRateLimit struct {
+	limit     int // zero if not reported
+	remaining int
+	reset     time.Time
+}
+
+// parseGitHubRateLimit reads the X-RateLimit-* headers of a response.
+func parseGitHubRateLimit(h http.Header) gitHubRateLimit {
+	var rl gitHubRateLimit
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return gitHubRateLimit{}
+	}
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return gitHubRateLimit{}
+	}
+	rl.limit = limit
+	rl.remaining = remaining
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.reset = time.Unix(reset, 0)
+	}
+	return rl
+}
+
+// low reports whether less than a tenth of the rate limit is left.
+func (rl gitHubRateLimit) low() bool {
+	return rl.limit > 0 && rl.remaining < rl.limit/10
+}
+
+// gitHubRateLimited reports whether resp was refused because of a primary
+// or secondary rate limit, and if so, how long to wait before trying again.
+func gitHubRateLimited(resp *http.Response, now time.Time) (wait time.Duration, limited bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+	rl := parseGitHubRateLimit(resp.Header)
+	if rl.limit == 0 || rl.remaining > 0 {
+		return 0, false
+	}
+	if rl.reset.IsZero() {
+		// GitHub's documentation says to wait at least a minute.
+		return time.Minute, true
+	}
+	// Reset times have a granularity of a second, so round up.
+	return max(rl.reset.Sub(now)+time.Second, 0), true
+}
+
+// gitHubRateLimiter is an http.RoundTripper for GitHub API requests that
+// warns when the rate limit is close to running out and waits for it to
+// reset before retrying a request that was refused.
+type gitHubRateLimiter struct {
+	base    http.RoundTripper
+	warn    io.Writer
+	maxWait time.Duration
+	now     func() time.Time
+	sleep   func(context.Context, time.Duration) error
+
+	mu     sync.Mutex
+	warned bool
+}
+
+// withGitHubRateLimits returns a copy of client that sends GitHub API
+// requests through a gitHubRateLimiter, writing messages to warn.
+func withGitHubRateLimits(client *http.Client, warn io.Writer) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client2 := new(http.Client)
+	*client2 = *client
+	client2.Transport = &gitHubRateLimiter{
+		base:    base,
+		warn:    warn,
+		maxWait: gitHubRateLimitMaxWait,
+		now:     time.Now,
+		sleep:   sleepContext,
+	}
+	return client2
+}
+
+// RoundTrip sends req, retrying once if GitHub refuses it because of a
+// rate limit that resets soon.
+func (l *gitHubRateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != gitHubAPIHost {
+		return l.base.RoundTrip(req)
+	}
+	resp, err := l.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	wait, limited := gitHubRateLimited(resp, l.now())
+	if !limited {
+		l.warnIfLow(parseGitHubRateLimit(resp.Header))
+		return resp, nil
+	}
+	if wait > l.maxWait || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	fmt.Fprintf(l.warn, "gg: GitHub API rate limit reached; retrying in %v\n", wait.Round(time.Second))
+	if err := l.sleep(req.Context(), wait); err != nil {
+		return nil, err
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return l.base.RoundTrip(retry)
+}
+
+func (l *gitHubRateLimiter) warnIfLow(rl gitHubRateLimit) {
+	if !rl.low() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.warned {
+		return
+	}
+	l.warned = true
+	fmt.Fprintf(l.warn, "gg: warning: %d of %d GitHub API requests left until %s\n",
+		rl.remaining, rl.limit, rl.reset.Local().Format("15:04"))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// gitHubScopes are the OAuth scopes that gg asks for, with what needs them.
+var gitHubScopes = []struct {
+	name string
+	use  string
+}{
+	{"repo", "create pull requests and releases"},
+	{"read:org", "request reviews from teams"},
+}
+
+// parseGitHubScopes parses the comma-separated list of OAuth scopes in a
+// X-OAuth-Scopes or X-Accepted-OAuth-Scopes header.
+func parseGitHubScopes(s string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(s, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// hasGitHubScope reports whether the granted scopes include want,
+// either directly or through a broader scope.
+func hasGitHubScope(granted []string, want string) bool {
+	for _, s := range granted {
+		if s == want {
+			return true
+		}
+		if want == "read:org" && (s == "write:org" || s == "admin:org") {
+			return true
+		}
+	}
+	return false
+}
+
+// missingGitHubScopes returns the scopes in want that are not granted.
+func missingGitHubScopes(granted, want []string) []string {
+	var missing []string
+	for _, s := range want {
+		if !hasGitHubScope(granted, s) {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,232 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGitHubRateLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	newResponse := func(req *http.Request, status int, remaining int) *http.Response {
+		resp := &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader("{}")),
+			Request:    req,
+		}
+		resp.Header.Set("X-RateLimit-Limit", "5000")
+		resp.Header.Set("X-RateLimit-Remaining", fmt.Sprint(remaining))
+		resp.Header.Set("X-RateLimit-Reset", fmt.Sprint(now.Add(30*time.Second).Unix()))
+		return resp
+	}
+
+	t.Run("RetryAfterReset", func(t *testing.T) {
+		calls := 0
+		var bodies []string
+		var waited time.Duration
+		warn := new(strings.Builder)
+		l := &gitHubRateLimiter{
+			base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				body, _ := io.ReadAll(req.Body)
+				bodies = append(bodies, string(body))
+				if calls == 1 {
+					return newResponse(req, http.StatusForbidden, 0), nil
+				}
+				return newResponse(req, http.StatusCreated, 4999), nil
+			}),
+			warn:    warn,
+			maxWait: time.Minute,
+			now:     func() time.Time { return now },
+			sleep: func(ctx context.Context, d time.Duration) error {
+				waited += d
+				return nil
+			},
+		}
+		req, err := http.NewRequest(http.MethodPost, "https://api.github.com/repos/foo/bar/pulls", strings.NewReader("hello"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := l.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("status = %d; want %d", resp.StatusCode, http.StatusCreated)
+		}
+		if calls != 2 || bodies[1] != "hello" {
+			t.Errorf("requests = %q; want the request sent twice with its body", bodies)
+		}
+		if want := 31 * time.Second; waited != want {
+			t.Errorf("waited %v; want %v", waited, want)
+		}
+		if !strings.Contains(warn.String(), "rate limit reached") {
+			t.Errorf("warning = %q; want rate limit message", warn.String())
+		}
+	})
+
+	t.Run("TooLong", func(t *testing.T) {
+		calls := 0
+		l := &gitHubRateLimiter{
+			base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				return newResponse(req, http.StatusForbidden, 0), nil
+			}),
+			warn:    io.Discard,
+			maxWait: 10 * time.Second,
+			now:     func() time.Time { return now },
+			sleep: func(ctx context.Context, d time.Duration) error {
+				t.Error("sleep called")
+				return nil
+			},
+		}
+		req, err := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := l.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 1 {
+			t.Errorf("sent %d requests; want 1", calls)
+		}
+		err = parseGitHubErrorResponse(resp)
+		if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+			t.Errorf("parseGitHubErrorResponse(...) = %v; want rate limit error", err)
+		}
+	})
+
+	t.Run("WarnLow", func(t *testing.T) {
+		warn := new(strings.Builder)
+		l := &gitHubRateLimiter{
+			base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return newResponse(req, http.StatusOK, 42), nil
+			}),
+			warn: warn,
+			now:  func() time.Time { return now },
+		}
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := l.RoundTrip(req); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := strings.Count(warn.String(), "42 of 5000 GitHub API requests left"); got != 1 {
+			t.Errorf("warnings = %q; want one low rate limit warning", warn.String())
+		}
+	})
+}
+
+func TestMissingGitHubScopes(t *testing.T) {
+	tests := []struct {
+		granted string
+		want    []string
+	}{
+		{granted: "repo, read:org", want: nil},
+		{granted: "repo, admin:org", want: nil},
+		{granted: "repo", want: []string{"read:org"}},
+		{granted: "", want: []string{"repo", "read:org"}},
+	}
+	for _, test := range tests {
+		got := missingGitHubScopes(parseGitHubScopes(test.granted), gitHubScopeNames())
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("missingGitHubScopes(%q, ...) = %q; want %q", test.granted, got, test.want)
+		}
+	}
+}
+
+func TestGitHubLoginCheck(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		repoToken = "xyzzy12345"
+		fullToken = "plugh67890"
+	)
+	if err := env.writeGitHubAuth([]byte(repoToken + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var scopes string
+		switch r.Header.Get("Authorization") {
+		case "token " + repoToken:
+			scopes = "repo"
+		case "token " + fullToken:
+			scopes = "repo, read:org"
+		}
+		if r.URL.Path != "/user" || scopes == "" {
+			http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-OAuth-Scopes", scopes)
+		w.Header().Set("GitHub-Authentication-Token-Expiration", "2030-01-02 03:04:05 UTC")
+		w.Write([]byte(`{"login":"octocat"}`))
+	})
+	fakeGitHub := httptest.NewServer(api)
+	defer fakeGitHub.Close()
+	fakeGitHubTransport := &http.Transport{
+		DialTLS: func(network, addr string) (net.Conn, error) {
+			hostport := strings.TrimPrefix(fakeGitHub.URL, "http://")
+			return net.Dial("tcp", hostport)
+		},
+	}
+	defer fakeGitHubTransport.CloseIdleConnections()
+	env.roundTripper = fakeGitHubTransport
+
+	out, err := env.gg(ctx, env.root.String(), "github-login", "--check")
+	if err == nil {
+		t.Error("gg github-login --check succeeded with a token missing read:org")
+	}
+	got := string(out)
+	for _, want := range []string{"octocat", "Scopes: repo\n", "Expires: 2030-01-02 03:04:05 UTC", "Missing scope read:org"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("gg github-login --check output:\n%s\nwant to contain %q", got, want)
+		}
+	}
+
+	if err := env.writeGitHubAuth([]byte(fullToken + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "github-login", "--check"); err != nil {
+		t.Error(err)
+	}
+}
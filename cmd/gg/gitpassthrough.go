@@ -0,0 +1,52 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+
+	"gg-scm.io/tool/internal/flag"
+)
+
+const gitSynopsis = "run a Git command"
+
+func gitPassthrough(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(false, "gg git [--] GITARG [...]", gitSynopsis+`
+
+	Run Git with the arguments as given, for the times that gg has no
+	command that does the job. Git runs the same way that gg's own
+	commands run it: with the Git executable from `+"`--git`"+`, in the
+	directory from `+"`-C`"+`, with settings from `+"`GG_`"+` environment
+	variables, and logged by `+"`--show-git`"+`.
+
+	If `+"`gg.journal`"+` is true, the refs that the Git command changes are
+	recorded in the journal like those of any other gg command (see
+	`+"`gg journal`"+`). If Git fails, gg exits with Git's exit status.
+
+	Put `+"`--`"+` before the Git arguments if the first one starts with a
+	dash, like `+"`gg git -- -c core.pager=cat log`"+`.`)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v (put -- before Git arguments that start with a dash)", err)
+	}
+	if f.NArg() == 0 {
+		return usagef("missing Git arguments")
+	}
+	return cc.passthroughGit(ctx, f.Args()...)
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestGitPassthrough(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "config", "gg.journal", "true"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.ParseRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "git", "--", "commit", "--quiet", "--allow-empty", "-m", "Empty commit"); err != nil {
+		t.Fatal(err)
+	}
+	newHead, err := env.git.ParseRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newHead.Commit == head.Commit {
+		t.Fatal("gg git -- commit did not create a commit")
+	}
+	out, err := env.gg(ctx, env.root.String(), "journal", "export", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []*journalEntry
+	if err := json.Unmarshal(out, &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("journal has %d entries; want 1", len(entries))
+	}
+	const wantCommand = "gg git -- commit --quiet --allow-empty -m 'Empty commit'"
+	if entries[0].Command != wantCommand {
+		t.Errorf("journal command = %q; want %q", entries[0].Command, wantCommand)
+	}
+	if len(entries[0].Refs) != 2 {
+		t.Errorf("journal refs = %+v; want HEAD and refs/heads/main", entries[0].Refs)
+	}
+
+	// Flags for Git need a "--" first.
+	if _, err := env.gg(ctx, env.root.String(), "git", "-c", "core.pager=cat", "log"); !isUsage(err) {
+		t.Errorf("gg git -c ... = %v; want usage error", err)
+	}
+
+	// Failures exit with Git's status.
+	_, err = env.gg(ctx, env.root.String(), "git", "rev-parse", "--verify", "--quiet", "nonexistent")
+	if err == nil {
+		t.Fatal("gg git rev-parse --verify nonexistent succeeded")
+	}
+	if status, ok := exitStatus(err); !ok || status != 1 {
+		t.Errorf("exitStatus(gg git rev-parse --verify nonexistent) = %d, %t; want 1, true", status, ok)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const graftSynopsis = "copy changes from other branches onto the current branch"
+
+func graft(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg graft [-e] [--log] [-r] REV [...]\n"+
+		"gg graft --continue\n"+
+		"gg graft --abort", graftSynopsis+`
+
+	Apply the changes of each `+"`REV`"+` in turn on top of the current
+	commit, creating a new commit for each with the same author, date, and
+	message. This is the opposite of `+"`gg backout`"+`: it brings a commit
+	over from another branch instead of undoing it.
+
+	If a commit does not apply cleanly, the graft stops with the
+	conflicts in the working copy. Resolve them, then run
+	`+"`gg graft --continue`"+` to commit the result and graft the
+	remaining revisions, or `+"`gg graft --abort`"+` to return to the
+	commit the graft started from.
+
+	With `+"`--log`"+`, each new commit's message ends with a line naming
+	the commit it was copied from.`)
+	edit := f.Bool("e", false, "invoke editor on commit messages")
+	f.Alias("e", "edit")
+	logOrigin := f.Bool("log", false, "append a line naming the original commit to each message")
+	revs := f.MultiString("r", "`rev`ision to graft")
+	abort := f.Bool("abort", false, "abort an interrupted graft")
+	continue_ := f.Bool("continue", false, "continue an interrupted graft")
+	conflictStyle := addConflictStyleFlag(f)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if *abort && *continue_ {
+		return usagef("can't specify both --abort and --continue")
+	}
+	if *abort || *continue_ {
+		if f.NArg() != 0 || len(*revs) > 0 {
+			return usagef("can't pass revisions with --abort or --continue")
+		}
+		if *edit || *logOrigin || (*abort && *conflictStyle != "") {
+			return usagef("can't specify other options with --abort or --continue")
+		}
+	}
+	if *abort {
+		return cc.interactiveGit(ctx, "cherry-pick", "--abort")
+	}
+	cc, err := withConflictStyle(cc, *conflictStyle)
+	if err != nil {
+		return err
+	}
+	if *continue_ {
+		err = continueGraft(ctx, cc)
+	} else {
+		revArgs := append(append([]string(nil), *revs...), f.Args()...)
+		if len(revArgs) == 0 {
+			return usagef("must pass at least one revision")
+		}
+		var hashes []git.Hash
+		hashes, err = resolveGraftRevs(ctx, cc.git, revArgs)
+		if err != nil {
+			return err
+		}
+		if err = recordOperation(ctx, cc.git, "graft", args); err == nil {
+			if err = autoSnapshot(ctx, cc, "graft"); err == nil {
+				err = cherryPick(ctx, cc, hashes, cherryPickOptions{edit: *edit, log: *logOrigin})
+			}
+		}
+	}
+	if err != nil {
+		reportReusedResolutions(ctx, cc)
+		return err
+	}
+	return nil
+}
+
+// resolveGraftRevs returns the commits named by revs, in order.
+func resolveGraftRevs(ctx context.Context, g *git.Git, revs []string) ([]git.Hash, error) {
+	hashes := make([]git.Hash, 0, len(revs))
+	for _, r := range revs {
+		if strings.HasPrefix(r, "-") {
+			return nil, usagef("revisions must not start with '-'")
+		}
+		rev, err := g.ParseRev(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, rev.Commit)
+	}
+	return hashes, nil
+}
+
+// cherryPickOptions holds the parameters of cherryPick.
+type cherryPickOptions struct {
+	edit bool // open the editor on each message
+	log  bool // append a "cherry picked from" line to each message
+}
+
+// cherryPick applies the given commits on top of HEAD. If a commit does
+// not apply cleanly, cherryPick returns a *cherryPickConflictError
+// describing where the cherry-pick stopped.
+func cherryPick(ctx context.Context, cc *cmdContext, commits []git.Hash, opts cherryPickOptions) error {
+	pickArgs := []string{"cherry-pick"}
+	if opts.edit {
+		pickArgs = append(pickArgs, "--edit")
+	}
+	if opts.log {
+		pickArgs = append(pickArgs, "-x")
+	}
+	for _, c := range commits {
+		pickArgs = append(pickArgs, c.String())
+	}
+	return checkCherryPickConflict(ctx, cc.git, cc.passthroughGit(ctx, pickArgs...))
+}
+
+// continueGraft adds any modified files to the index and then runs
+// `git cherry-pick --continue`.
+func continueGraft(ctx context.Context, cc *cmdContext) error {
+	op, err := readOperationState(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if op == nil || op.kind != "cherry-pick" {
+		return errors.New("no graft in progress")
+	}
+	status, err := cc.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		return err
+	}
+	hasChanges, err := verifyNoMissingOrUnmerged(status)
+	if err != nil {
+		return err
+	}
+	if hasChanges {
+		if err := cc.git.StageTracked(ctx); err != nil {
+			return err
+		}
+	}
+	return checkCherryPickConflict(ctx, cc.git, cc.passthroughGit(ctx, "cherry-pick", "--continue"))
+}
+
+// A cherryPickConflictError is returned when a cherry-pick stops because
+// a commit did not apply cleanly.
+type cherryPickConflictError struct {
+	err       error
+	commit    string // commit that did not apply
+	summary   string
+	conflicts []git.TopPath
+}
+
+func (e *cherryPickConflictError) Error() string {
+	sb := new(strings.Builder)
+	sb.WriteString("conflicts while grafting ")
+	sb.WriteString(shortHash(e.commit))
+	if e.summary != "" {
+		fmt.Fprintf(sb, " %q", e.summary)
+	}
+	sb.WriteString(":")
+	for _, name := range e.conflicts {
+		sb.WriteString("\n\t")
+		sb.WriteString(string(name))
+	}
+	sb.WriteString("\nresolve the conflicts and run gg graft --continue, or run gg graft --abort")
+	return sb.String()
+}
+
+func (e *cherryPickConflictError) Unwrap() error {
+	return e.err
+}
+
+// checkCherryPickConflict converts err from a cherry-pick into a
+// *cherryPickConflictError if the cherry-pick stopped on a conflict.
+// Other errors are returned unchanged.
+func checkCherryPickConflict(ctx context.Context, g *git.Git, err error) error {
+	if err == nil {
+		return nil
+	}
+	op, stateErr := readOperationState(ctx, g)
+	if stateErr != nil || op == nil || op.kind != "cherry-pick" || len(op.conflicts) == 0 {
+		return err
+	}
+	return &cherryPickConflictError{
+		err:       err,
+		commit:    op.current,
+		summary:   op.summary,
+		conflicts: op.conflicts,
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+// setupGraftRepo creates a repository whose main branch and feature
+// branch both change foo.txt from the same starting point. It returns
+// the feature branch's commit.
+func setupGraftRepo(ctx context.Context, env *testEnv, mainContent, featureContent string) (git.Hash, error) {
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		return git.Hash{}, err
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Hello\n")); err != nil {
+		return git.Hash{}, err
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		return git.Hash{}, err
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		return git.Hash{}, err
+	}
+	if err := env.git.NewBranch(ctx, "feature", git.BranchOptions{Checkout: true}); err != nil {
+		return git.Hash{}, err
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", featureContent)); err != nil {
+		return git.Hash{}, err
+	}
+	if err := env.git.CommitAll(ctx, "feature change", git.CommitOptions{}); err != nil {
+		return git.Hash{}, err
+	}
+	feature, err := env.git.Head(ctx)
+	if err != nil {
+		return git.Hash{}, err
+	}
+	if err := env.git.CheckoutBranch(ctx, "main", git.CheckoutOptions{}); err != nil {
+		return git.Hash{}, err
+	}
+	if mainContent != "" {
+		if err := env.root.Apply(filesystem.Write("foo.txt", mainContent)); err != nil {
+			return git.Hash{}, err
+		}
+		if err := env.git.CommitAll(ctx, "main change", git.CommitOptions{}); err != nil {
+			return git.Hash{}, err
+		}
+	}
+	return feature.Commit, nil
+}
+
+func TestGraft(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feature, err := setupGraftRepo(ctx, env, "", "Hello, World!\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldHead, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "graft", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Ref != git.BranchRef("main") {
+		t.Errorf("HEAD ref = %v; want refs/heads/main", head.Ref)
+	}
+	if head.Commit == feature {
+		t.Error("HEAD is the feature commit; want a new commit")
+	}
+	info, err := env.git.CommitInfo(ctx, head.Commit.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Parents) != 1 || info.Parents[0] != oldHead.Commit {
+		t.Errorf("grafted commit parents = %v; want [%v]", info.Parents, oldHead.Commit)
+	}
+	if got, want := info.Message, "feature change\n"; got != want {
+		t.Errorf("grafted commit message = %q; want %q", got, want)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Error(err)
+	} else if want := "Hello, World!\n"; got != want {
+		t.Errorf("foo.txt = %q; want %q", got, want)
+	}
+}
+
+func TestGraft_Log(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feature, err := setupGraftRepo(ctx, env, "", "Hello, World!\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "graft", "--log", "-r", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(info.Message, feature.String()) {
+		t.Errorf("grafted commit message = %q; want to mention %v", info.Message, feature)
+	}
+}
+
+func TestGraft_Conflict(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := setupGraftRepo(ctx, env, "Hello, main!\n", "Hello, feature!\n"); err != nil {
+		t.Fatal(err)
+	}
+	mainHead, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, env.root.String(), "graft", "feature")
+	if err == nil {
+		t.Fatal("gg graft did not return an error")
+	}
+	if got := err.Error(); !strings.Contains(got, "foo.txt") || !strings.Contains(got, "gg graft --continue") {
+		t.Errorf("gg graft error = %q; want to mention foo.txt and gg graft --continue", got)
+	}
+
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Hello, everyone!\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "graft", "--continue"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Parents) != 1 || info.Parents[0] != mainHead.Commit {
+		t.Errorf("grafted commit parents = %v; want [%v]", info.Parents, mainHead.Commit)
+	}
+	if got, want := info.Summary(), "feature change"; got != want {
+		t.Errorf("grafted commit summary = %q; want %q", got, want)
+	}
+	if got, err := catBlob(ctx, env.git, "HEAD", "foo.txt"); err != nil {
+		t.Error(err)
+	} else if want := "Hello, everyone!\n"; string(got) != want {
+		t.Errorf("foo.txt in HEAD = %q; want %q", got, want)
+	}
+}
+
+func TestGraft_Abort(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := setupGraftRepo(ctx, env, "Hello, main!\n", "Hello, feature!\n"); err != nil {
+		t.Fatal(err)
+	}
+	mainHead, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "graft", "feature"); err == nil {
+		t.Fatal("gg graft did not return an error")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "graft", "--abort"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Commit != mainHead.Commit {
+		t.Errorf("after abort, HEAD = %v; want %v", head.Commit, mainHead.Commit)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Error(err)
+	} else if want := "Hello, main!\n"; got != want {
+		t.Errorf("after abort, foo.txt = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/escape"
+)
+
+// A planStep is a line of a rebase plan that refers to a commit.
+type planStep struct {
+	line   int    // 1-based line number
+	action string // full action name, like "pick"
+	commit string // commit argument as written
+}
+
+// parseHisteditPlan returns the steps of a rebase plan that refer to
+// commits. Lines that start with commentChar are ignored.
+func parseHisteditPlan(plan string, commentChar string) ([]planStep, error) {
+	var steps []planStep
+	for i, line := range strings.Split(plan, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || (commentChar != "" && strings.HasPrefix(line, commentChar)) {
+			continue
+		}
+		fields := strings.Fields(line)
+		action := fields[0]
+		switch action {
+		case "p", "pick":
+			action = "pick"
+		case "r", "reword":
+			action = "reword"
+		case "e", "edit":
+			action = "edit"
+		case "s", "squash":
+			action = "squash"
+		case "f", "fixup":
+			action = "fixup"
+			// fixup -C and -c take the message from the fixup commit.
+			if len(fields) > 1 && (fields[1] == "-C" || fields[1] == "-c") {
+				fields = fields[1:]
+			}
+		case "d", "drop":
+			action = "drop"
+		case "x", "exec", "b", "break", "l", "label", "t", "reset", "m", "merge", "u", "update-ref", "noop":
+			continue
+		default:
+			return nil, fmt.Errorf("line %d: unknown action %q", i+1, action)
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: %s: missing commit", i+1, action)
+		}
+		steps = append(steps, planStep{line: i + 1, action: action, commit: fields[1]})
+	}
+	return steps, nil
+}
+
+// validateHisteditPlan checks that every commit in the plan is one of
+// the commits being edited. It replaces each step's commit with its full
+// hash.
+func validateHisteditPlan(ctx context.Context, g *git.Git, steps []planStep, inRange map[string]bool, rangeName string) error {
+	for i := range steps {
+		step := &steps[i]
+		hash, err := g.Output(ctx, "rev-parse", "--verify", "--quiet", step.commit+"^{commit}")
+		if err != nil {
+			return fmt.Errorf("histedit plan line %d: %s %s: unknown commit", step.line, step.action, step.commit)
+		}
+		hash = strings.TrimSuffix(hash, "\n")
+		if !inRange[hash] {
+			return fmt.Errorf("histedit plan line %d: %s %s: commit is not in %s", step.line, step.action, step.commit, rangeName)
+		}
+		step.commit = hash
+	}
+	return nil
+}
+
+// editHisteditPlan shows the plan Git would use for a rebase with the
+// given arguments onto mergeBase in the user's sequence editor. It
+// validates the edited plan, then returns the path of a file containing it
+// along with the plan's steps.
+func editHisteditPlan(ctx context.Context, cc *cmdContext, mergeBase git.Hash, rebaseArgs []string) (string, []planStep, error) {
+	gitDir, err := cc.git.GitDir(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	// Have Git write the plan, including its help text and any --autosquash
+	// and --exec changes, then stop before it starts rebasing.
+	planPath := filepath.Join(gitDir, filepath.FromSlash(histeditPlanPath))
+	if err := os.MkdirAll(filepath.Dir(planPath), 0o777); err != nil {
+		return "", nil, fmt.Errorf("histedit: %w", err)
+	}
+	os.Remove(planPath)
+	captureEditor := "f() { cp \"$1\" " + escape.Bash(planPath) + "; return 1; }; f"
+	captureArgs := []string{"-c", "sequence.editor=" + captureEditor}
+	captureArgs = append(captureArgs, rebaseArgs...)
+	captureArgs = append(captureArgs, "--no-autostash", "--", mergeBase.String())
+	runErr := cc.git.Run(ctx, captureArgs...)
+	initial, err := os.ReadFile(planPath)
+	if err != nil {
+		if runErr != nil {
+			return "", nil, runErr
+		}
+		return "", nil, fmt.Errorf("histedit: %w", err)
+	}
+
+	edited, err := cc.editor.openSequence(ctx, "git-rebase-todo", initial)
+	if err != nil {
+		return "", nil, err
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	commentChar, err := cfg.CommentChar()
+	if err != nil {
+		return "", nil, err
+	}
+	steps, err := parseHisteditPlan(string(edited), commentChar)
+	if err != nil {
+		return "", nil, fmt.Errorf("histedit plan: %w", err)
+	}
+	if len(steps) == 0 {
+		return "", nil, errors.New("histedit plan is empty; nothing to do")
+	}
+	commits, err := revList(ctx, cc.git, git.Head.String(), "^"+mergeBase.String())
+	if err != nil {
+		return "", nil, err
+	}
+	inRange := make(map[string]bool, len(commits))
+	for _, c := range commits {
+		inRange[c] = true
+	}
+	rangeName := shortHash(mergeBase.String()) + "..HEAD"
+	if err := validateHisteditPlan(ctx, cc.git, steps, inRange, rangeName); err != nil {
+		return "", nil, err
+	}
+	if err := os.WriteFile(planPath, edited, 0o666); err != nil {
+		return "", nil, fmt.Errorf("histedit: %w", err)
+	}
+	return planPath, steps, nil
+}
+
+// editRemainingHisteditPlan opens the rest of the plan of a histedit in
+// progress in the user's sequence editor, like `git rebase --edit-todo`.
+// It validates the edited plan the same way as editHisteditPlan before
+// handing it back to Git.
+func editRemainingHisteditPlan(ctx context.Context, cc *cmdContext) error {
+	gitDir, err := cc.git.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	stateDir := filepath.Join(gitDir, "rebase-merge")
+	todoPath := filepath.Join(stateDir, "git-rebase-todo")
+	initial, err := os.ReadFile(todoPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.New("no histedit in progress")
+	}
+	if err != nil {
+		return fmt.Errorf("histedit: %w", err)
+	}
+	origHead, err := git.ParseHash(readStateFile(stateDir, "orig-head"))
+	if err != nil {
+		return fmt.Errorf("histedit: read original HEAD: %w", err)
+	}
+	onto, err := git.ParseHash(readStateFile(stateDir, "onto"))
+	if err != nil {
+		return fmt.Errorf("histedit: read rebase base: %w", err)
+	}
+
+	edited, err := cc.editor.openSequence(ctx, "git-rebase-todo", initial)
+	if err != nil {
+		return err
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	commentChar, err := cfg.CommentChar()
+	if err != nil {
+		return err
+	}
+	steps, err := parseHisteditPlan(string(edited), commentChar)
+	if err != nil {
+		return fmt.Errorf("histedit plan: %w", err)
+	}
+	commits, err := revList(ctx, cc.git, origHead.String(), "^"+onto.String())
+	if err != nil {
+		return err
+	}
+	inRange := make(map[string]bool, len(commits))
+	for _, c := range commits {
+		inRange[c] = true
+	}
+	rangeName := shortHash(onto.String()) + ".." + shortHash(origHead.String())
+	if err := validateHisteditPlan(ctx, cc.git, steps, inRange, rangeName); err != nil {
+		return err
+	}
+	if err := os.WriteFile(todoPath, edited, 0o666); err != nil {
+		return fmt.Errorf("histedit: %w", err)
+	}
+
+	// Keep the Change-Ids that finishHistedit expects in sync with the
+	// new plan, if gg started the histedit.
+	if _, err := os.Stat(filepath.Join(gitDir, filepath.FromSlash(histeditRecordPath))); err != nil {
+		return nil
+	}
+	doneSteps, err := parseHisteditPlan(readStateFile(stateDir, "done"), commentChar)
+	if err != nil {
+		return fmt.Errorf("histedit: read finished steps: %w", err)
+	}
+	if err := validateHisteditPlan(ctx, cc.git, doneSteps, inRange, rangeName); err != nil {
+		return err
+	}
+	return writeHisteditRecord(ctx, cc.git, origHead, onto, append(doneSteps, steps...))
+}
+
+// histeditPlanPath is the path of the validated histedit plan,
+// relative to the Git directory.
+const histeditPlanPath = "gg/histedit-plan"
+
+// histeditRecordPath is the path of the file that records the Change-Ids
+// a histedit should produce, relative to the Git directory.
+const histeditRecordPath = "gg/histedit"
+
+// histeditChangeIDs returns the Change-Id each commit produced by the
+// plan should have, in order. Squashed and fixed-up commits are folded
+// into the commit before them. An empty string means no Change-Id.
+func histeditChangeIDs(steps []planStep, changeIDs map[string]string) []string {
+	var ids []string
+	for _, step := range steps {
+		switch step.action {
+		case "pick", "reword", "edit":
+			ids = append(ids, changeIDs[step.commit])
+		case "squash", "fixup":
+			if len(ids) > 0 && ids[len(ids)-1] == "" {
+				ids[len(ids)-1] = changeIDs[step.commit]
+			}
+		}
+	}
+	return ids
+}
+
+// writeHisteditRecord saves the Change-Ids that the histedit of the
+// commits between onto and origHead described by steps should produce,
+// so that finishHistedit can check them.
+func writeHisteditRecord(ctx context.Context, g *git.Git, origHead, onto git.Hash, steps []planStep) error {
+	gitDir, err := g.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	changes, err := readChanges(ctx, g, origHead.String(), onto.String())
+	if err != nil {
+		return err
+	}
+	changeIDs := make(map[string]string, len(changes))
+	for _, c := range changes {
+		changeIDs[c.commitHex] = c.id
+	}
+	sb := new(strings.Builder)
+	sb.WriteString(origHead.String() + "\n" + onto.String() + "\n")
+	for _, id := range histeditChangeIDs(steps, changeIDs) {
+		if id == "" {
+			id = "-"
+		}
+		sb.WriteString(id + "\n")
+	}
+	path := filepath.Join(gitDir, filepath.FromSlash(histeditRecordPath))
+	if err := os.WriteFile(path, []byte(sb.String()), 0o666); err != nil {
+		return fmt.Errorf("histedit: %w", err)
+	}
+	return nil
+}
+
+// discardHisteditRecord removes the files saved for an aborted histedit.
+func discardHisteditRecord(ctx context.Context, g *git.Git) {
+	gitDir, err := g.GitDir(ctx)
+	if err != nil {
+		return
+	}
+	os.Remove(filepath.Join(gitDir, filepath.FromSlash(histeditRecordPath)))
+	os.Remove(filepath.Join(gitDir, filepath.FromSlash(histeditPlanPath)))
+}
+
+// finishHistedit checks that the commits produced by a completed histedit
+// kept their Gerrit Change-Id trailers. It restores any trailers that were
+// dropped, for example by rewording. If it can't tell which Change-Id a
+// commit should have, it runs the commit-msg hook to add a new one.
+// It does nothing while the histedit is still in progress.
+func finishHistedit(ctx context.Context, cc *cmdContext) error {
+	gitDir, err := cc.git.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	if isdir(filepath.Join(gitDir, "rebase-merge")) || isdir(filepath.Join(gitDir, "rebase-apply")) {
+		return nil
+	}
+	recordPath := filepath.Join(gitDir, filepath.FromSlash(histeditRecordPath))
+	data, err := os.ReadFile(recordPath)
+	if err != nil {
+		return nil
+	}
+	os.Remove(recordPath)
+	os.Remove(filepath.Join(gitDir, filepath.FromSlash(histeditPlanPath)))
+	lines := strings.Fields(string(data))
+	if len(lines) < 2 {
+		return nil
+	}
+	origHead, onto, wantIDs := lines[0], lines[1], lines[2:]
+	anyIDs := false
+	for i := range wantIDs {
+		if wantIDs[i] == "-" {
+			wantIDs[i] = ""
+		} else {
+			anyIDs = true
+		}
+	}
+	head, err := cc.git.Head(ctx)
+	if err != nil || head.Commit.String() == origHead || !anyIDs {
+		return nil
+	}
+	changes, err := readChanges(ctx, cc.git, head.Commit.String(), onto)
+	if err != nil {
+		return err
+	}
+	// readChanges returns children first.
+	for i, j := 0, len(changes)-1; i < j; i, j = i+1, j-1 {
+		changes[i], changes[j] = changes[j], changes[i]
+	}
+	knownIDs := len(changes) == len(wantIDs)
+	hookInstalled := false
+	if cfg, err := cc.git.ReadConfig(ctx); err == nil {
+		if hookPath, err := commitMsgHookPath(ctx, cfg, cc.git); err == nil {
+			_, err := os.Stat(hookPath)
+			hookInstalled = err == nil
+		}
+	}
+
+	plan := new(strings.Builder)
+	restored, regenerated := 0, 0
+	for i, c := range changes {
+		fmt.Fprintf(plan, "pick %s\n", c.commitHex)
+		if c.id != "" {
+			continue
+		}
+		switch {
+		case knownIDs && wantIDs[i] != "":
+			fmt.Fprintf(plan, "exec %s commit --quiet --amend --no-edit --no-verify --trailer %s\n",
+				escape.Bash(cc.git.Exe()), escape.Bash("Change-Id: "+wantIDs[i]))
+			restored++
+		case !knownIDs && hookInstalled:
+			// Amending runs the commit-msg hook, which adds a new Change-Id.
+			fmt.Fprintf(plan, "exec %s commit --quiet --amend --no-edit\n", escape.Bash(cc.git.Exe()))
+			regenerated++
+		case !knownIDs:
+			fmt.Fprintf(cc.stderr, "gg: warning: %s has no Change-Id trailer\n", shortHash(c.commitHex))
+		}
+	}
+	if restored+regenerated == 0 {
+		return nil
+	}
+	planPath := filepath.Join(gitDir, filepath.FromSlash(histeditPlanPath))
+	if err := os.WriteFile(planPath, []byte(plan.String()), 0o666); err != nil {
+		return fmt.Errorf("restore Change-Id: %w", err)
+	}
+	defer os.Remove(planPath)
+	err = cc.git.Run(ctx,
+		"-c", "sequence.editor=cat "+escape.Bash(planPath)+" >",
+		"rebase", "-i", "--onto="+onto, "--no-fork-point", "--no-autostash", "--", onto)
+	if err != nil {
+		return fmt.Errorf("restore Change-Id: %w", err)
+	}
+	if restored > 0 {
+		fmt.Fprintf(cc.stderr, "gg: restored Change-Id on %s\n", countCommits(restored))
+	}
+	if regenerated > 0 {
+		fmt.Fprintf(cc.stderr, "gg: added a new Change-Id to %s with the commit-msg hook\n", countCommits(regenerated))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseHisteditPlan(t *testing.T) {
+	const plan = `pick 1111111 First
+# A comment
+r 2222222 Second
+exec make test
+fixup -C 3333333 fixup! Second
+
+squash 4444444 More
+break
+d 5555555 Unwanted
+`
+	got, err := parseHisteditPlan(plan, "#")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []planStep{
+		{line: 1, action: "pick", commit: "1111111"},
+		{line: 3, action: "reword", commit: "2222222"},
+		{line: 5, action: "fixup", commit: "3333333"},
+		{line: 7, action: "squash", commit: "4444444"},
+		{line: 9, action: "drop", commit: "5555555"},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(planStep{})); diff != "" {
+		t.Errorf("parseHisteditPlan(...) (-want +got):\n%s", diff)
+	}
+
+	if _, err := parseHisteditPlan("frobnicate 1111111\n", "#"); err == nil {
+		t.Error("parseHisteditPlan with unknown action did not return an error")
+	}
+	if _, err := parseHisteditPlan("pick\n", "#"); err == nil {
+		t.Error("parseHisteditPlan with missing commit did not return an error")
+	}
+}
+
+func TestHisteditChangeIDs(t *testing.T) {
+	steps := []planStep{
+		{action: "pick", commit: "a"},
+		{action: "fixup", commit: "b"},
+		{action: "reword", commit: "c"},
+		{action: "drop", commit: "d"},
+		{action: "pick", commit: "e"},
+		{action: "squash", commit: "f"},
+	}
+	changeIDs := map[string]string{
+		"a": "Ia",
+		"b": "Ib",
+		"c": "",
+		"d": "Id",
+		"e": "",
+		"f": "If",
+	}
+	got := histeditChangeIDs(steps, changeIDs)
+	want := []string{"Ia", "", "If"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("histeditChangeIDs(...) (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gg-scm.io/pkg/git"
+)
+
+// rewordCommits changes the messages of the commits in revs, which must
+// be between mergeBase and HEAD, by opening an editor for each one. If
+// revs is empty, every commit after mergeBase is reworded. The commits
+// are rewritten with git commit-tree, so trees are never merged and the
+// working copy is not touched. Author and committer dates are kept.
+func rewordCommits(ctx context.Context, cc *cmdContext, mergeBase git.Hash, revs []string, allowPublished bool) error {
+	out, err := cc.git.Output(ctx, "rev-list", "--reverse", "--parents", git.Head.String(), "^"+mergeBase.String(), "--")
+	if err != nil {
+		return err
+	}
+	type stackCommit struct {
+		hash   string
+		parent string
+	}
+	var stack []stackCommit
+	index := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("%s is a merge or root commit; only a linear series can be reworded", shortHash(fields[0]))
+		}
+		index[fields[0]] = len(stack)
+		stack = append(stack, stackCommit{hash: fields[0], parent: fields[1]})
+	}
+	if len(stack) == 0 {
+		return errors.New("no commits to reword")
+	}
+	selected := make(map[string]bool)
+	first := len(stack)
+	for _, rev := range revs {
+		r, err := cc.git.ParseRev(ctx, rev)
+		if err != nil {
+			return err
+		}
+		i, ok := index[r.Commit.String()]
+		if !ok {
+			return fmt.Errorf("%s is not in %s..HEAD", rev, shortHash(mergeBase.String()))
+		}
+		selected[r.Commit.String()] = true
+		if i < first {
+			first = i
+		}
+	}
+	if len(revs) == 0 {
+		for _, c := range stack {
+			selected[c.hash] = true
+		}
+		first = 0
+	}
+	if !allowPublished {
+		err := checkRewritePublished(ctx, cc.git, "edit", git.Head.String(), "^"+stack[first].parent)
+		if err != nil {
+			return err
+		}
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	commentChar, err := cfg.CommentChar()
+	if err != nil {
+		return err
+	}
+
+	// Collect all the new messages before writing anything, so that
+	// quitting an editor leaves the history as it was.
+	messages := make(map[string]string, len(selected))
+	for _, c := range stack[first:] {
+		if !selected[c.hash] {
+			continue
+		}
+		info, err := cc.git.CommitInfo(ctx, c.hash)
+		if err != nil {
+			return err
+		}
+		template := new(strings.Builder)
+		template.WriteString(info.Message)
+		fmt.Fprintf(template, "\n%s Rewording %s. Lines starting with %q will be ignored,\n", commentChar, shortHash(c.hash), commentChar)
+		fmt.Fprintf(template, "%s and an empty message aborts the edit.\n", commentChar)
+		edited, err := cc.editor.open(ctx, commitMsgFilename, []byte(template.String()))
+		if err != nil {
+			return err
+		}
+		msg := cleanupMessage(string(edited), commentChar)
+		if strings.TrimSpace(msg) == "" {
+			return fmt.Errorf("empty message for %s; history not changed", shortHash(c.hash))
+		}
+		if id := findChangeID(info.Message); id != "" && findChangeID(msg) == "" {
+			// Keep the Gerrit change associated with the commit.
+			msg = strings.TrimRight(msg, "\n") + "\n\nChange-Id: " + id + "\n"
+		}
+		messages[c.hash] = msg
+	}
+
+	newParent := stack[first].parent
+	for _, c := range stack[first:] {
+		info, err := cc.git.CommitInfo(ctx, c.hash)
+		if err != nil {
+			return err
+		}
+		msg, ok := messages[c.hash]
+		if !ok {
+			msg = info.Message
+		}
+		if msg == info.Message && newParent == c.parent {
+			newParent = c.hash
+			continue
+		}
+		gitOpts := cc.gitOptions
+		gitOpts.Dir = cc.dir
+		gitOpts.Env = append(append([]string(nil), gitOpts.Env...),
+			"GIT_AUTHOR_NAME="+info.Author.Name(),
+			"GIT_AUTHOR_EMAIL="+info.Author.Email(),
+			"GIT_AUTHOR_DATE="+gitDate(info.AuthorTime),
+			"GIT_COMMITTER_DATE="+gitDate(info.CommitTime),
+		)
+		commitGit, err := git.New(gitOpts)
+		if err != nil {
+			return err
+		}
+		newHash, err := commitGit.Output(ctx, "commit-tree", info.Tree.String(), "-p", newParent, "-m", strings.TrimSuffix(msg, "\n"))
+		if err != nil {
+			return fmt.Errorf("reword %s: %w", shortHash(c.hash), err)
+		}
+		newParent = strings.TrimSpace(newHash)
+	}
+	oldHead := stack[len(stack)-1].hash
+	if newParent == oldHead {
+		fmt.Fprintln(cc.stderr, "gg: no messages changed")
+		return nil
+	}
+	return cc.git.Run(ctx, "update-ref", "-m", "gg histedit: reword", git.Head.String(), newParent, oldHead)
+}
+
+// gitDate formats t in Git's internal date format.
+func gitDate(t time.Time) string {
+	return fmt.Sprintf("%d %s", t.Unix(), t.Format("-0700"))
+}
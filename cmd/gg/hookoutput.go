@@ -0,0 +1,204 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gg-scm.io/pkg/git"
+)
+
+const hookOutputHelp = `
+
+	Output from Git hooks is shown as it is written, with each line
+	prefixed by the name of the hook. Pass the global ` + "`--verbose`" + ` flag
+	to see how long each hook took.`
+
+// runGitWithHooks runs a Git command that may run hooks. The command's
+// error output is copied to cc.stderr as it is written (and to
+// invoke.Stderr, if set), and each line that a hook writes is prefixed with the hook's
+// name. Git reports which hook is running through its trace2 event
+// stream (see git-trace2(7)). In verbose mode, the time each hook took
+// is printed afterward.
+func runGitWithHooks(ctx context.Context, cc *cmdContext, invoke *git.Invocation) error {
+	traceFile, err := os.CreateTemp(cc.tempDir, "gg-trace2-*.json")
+	if err != nil {
+		return err
+	}
+	tracePath := traceFile.Name()
+	traceFile.Close()
+	defer os.Remove(tracePath)
+	// Git only reads trace2 settings from the environment or the global
+	// configuration, not from -c options.
+	traceCC, err := cc.withGitEnv("GIT_TRACE2_EVENT=" + tracePath)
+	if err != nil {
+		return err
+	}
+	w := &hookWriter{
+		w:     cc.stderr,
+		trace: &hookTrace{path: tracePath},
+	}
+	if invoke.Stderr != nil {
+		// Let the caller inspect the output too.
+		w.w = io.MultiWriter(cc.stderr, invoke.Stderr)
+	}
+	invoke2 := new(git.Invocation)
+	*invoke2 = *invoke
+	invoke2.Stderr = w
+	runErr := traceCC.git.Runner().RunGit(ctx, invoke2)
+	w.flush()
+	if cc.verbose {
+		w.trace.update()
+		for _, h := range w.trace.finished {
+			fmt.Fprintf(cc.stderr, "gg: hook %s took %v\n", h.name, h.duration.Round(time.Millisecond))
+		}
+	}
+	return runErr
+}
+
+// hookWriter copies Git's error output to w, prefixing lines that are
+// written while a hook is running with the hook's name.
+type hookWriter struct {
+	w     io.Writer
+	trace *hookTrace
+	buf   []byte // incomplete line
+}
+
+func (hw *hookWriter) Write(p []byte) (int, error) {
+	hw.buf = append(hw.buf, p...)
+	for {
+		i := bytes.IndexByte(hw.buf, '\n')
+		if i == -1 {
+			break
+		}
+		hw.writeLine(hw.buf[:i+1])
+		hw.buf = hw.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+func (hw *hookWriter) writeLine(line []byte) {
+	hw.trace.update()
+	if hw.trace.running != "" {
+		io.WriteString(hw.w, hw.trace.running+": ")
+	}
+	hw.w.Write(line)
+}
+
+// flush writes any incomplete last line.
+func (hw *hookWriter) flush() {
+	if len(hw.buf) > 0 {
+		hw.writeLine(append(hw.buf, '\n'))
+		hw.buf = nil
+	}
+}
+
+// hookTrace follows a trace2 event file to find out which hooks run.
+type hookTrace struct {
+	path   string
+	offset int64
+	rootID string // sid of the Git process that gg started
+
+	hooks    map[int]string // running hooks by child_id
+	running  string         // name of the most recently started running hook
+	finished []hookTiming
+}
+
+// A hookTiming is the name of a hook that ran and how long it took.
+type hookTiming struct {
+	name     string
+	duration time.Duration
+}
+
+// update reads the events that were added to the trace file since the
+// last call.
+func (ht *hookTrace) update() {
+	f, err := os.Open(ht.path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	if _, err := f.Seek(ht.offset, io.SeekStart); err != nil {
+		return
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return
+	}
+	// Only consume complete lines, since Git may be in the middle of
+	// writing an event.
+	end := bytes.LastIndexByte(data, '\n')
+	if end == -1 {
+		return
+	}
+	ht.offset += int64(end + 1)
+	for _, line := range bytes.Split(data[:end], []byte("\n")) {
+		ht.handleEvent(line)
+	}
+}
+
+func (ht *hookTrace) handleEvent(line []byte) {
+	var ev struct {
+		Event      string  `json:"event"`
+		SID        string  `json:"sid"`
+		ChildID    int     `json:"child_id"`
+		ChildClass string  `json:"child_class"`
+		HookName   string  `json:"hook_name"`
+		TimeRel    float64 `json:"t_rel"`
+	}
+	if err := json.Unmarshal(line, &ev); err != nil {
+		return
+	}
+	// Git commands run by hooks write to the same file.
+	if ht.rootID == "" {
+		ht.rootID = ev.SID
+	}
+	if ev.SID != ht.rootID {
+		return
+	}
+	switch ev.Event {
+	case "child_start":
+		if ev.ChildClass != "hook" || ev.HookName == "" {
+			return
+		}
+		if ht.hooks == nil {
+			ht.hooks = make(map[int]string)
+		}
+		ht.hooks[ev.ChildID] = ev.HookName
+		ht.running = ev.HookName
+	case "child_exit":
+		name, ok := ht.hooks[ev.ChildID]
+		if !ok {
+			return
+		}
+		delete(ht.hooks, ev.ChildID)
+		ht.finished = append(ht.finished, hookTiming{
+			name:     name,
+			duration: time.Duration(ev.TimeRel * float64(time.Second)),
+		})
+		ht.running = ""
+		for _, other := range ht.hooks {
+			ht.running = other
+		}
+	}
+}
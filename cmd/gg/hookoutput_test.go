@@ -0,0 +1,117 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHookWriter(t *testing.T) {
+	tracePath := filepath.Join(t.TempDir(), "trace.json")
+	appendTrace := func(lines ...string) {
+		t.Helper()
+		f, err := os.OpenFile(tracePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o666)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range lines {
+			if _, err := f.WriteString(line + "\n"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := new(strings.Builder)
+	w := &hookWriter{w: out, trace: &hookTrace{path: tracePath}}
+
+	appendTrace(`{"event":"version","sid":"root"}`)
+	w.Write([]byte("before\n"))
+	appendTrace(
+		`{"event":"child_start","sid":"root","child_id":0,"child_class":"hook","hook_name":"pre-commit"}`,
+		// A Git command run by the hook.
+		`{"event":"version","sid":"root/child"}`,
+		`{"event":"child_start","sid":"root/child","child_id":0,"child_class":"?","argv":["cat"]}`,
+	)
+	w.Write([]byte("formatting"))
+	w.Write([]byte(" files\nall good\n"))
+	appendTrace(`{"event":"child_exit","sid":"root","child_id":0,"code":0,"t_rel":1.5}`)
+	w.Write([]byte("after"))
+	w.flush()
+
+	want := "before\n" +
+		"pre-commit: formatting files\n" +
+		"pre-commit: all good\n" +
+		"after\n"
+	if got := out.String(); got != want {
+		t.Errorf("output:\n%s\nwant:\n%s", got, want)
+	}
+	wantTimings := []hookTiming{{name: "pre-commit", duration: 1500 * time.Millisecond}}
+	if diff := cmp.Diff(wantTimings, w.trace.finished, cmp.AllowUnexported(hookTiming{})); diff != "" {
+		t.Errorf("finished hooks (-want +got):\n%s", diff)
+	}
+}
+
+func TestCommit_HookOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook is a shell script")
+	}
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	const hook = "#!/bin/sh\necho 'checking files' 1>&2\n"
+	if err := env.root.Apply(filesystem.Write(".git/hooks/pre-commit", hook)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(env.root.FromSlash(".git/hooks/pre-commit"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	env.stderr.Reset()
+	if _, err := env.gg(ctx, env.root.String(), "--verbose", "commit", "-m", "add foo"); err != nil {
+		t.Fatal(err)
+	}
+	got := env.stderr.String()
+	if !strings.Contains(got, "pre-commit: checking files\n") {
+		t.Errorf("stderr = %q; want hook output prefixed with hook name", got)
+	}
+	if !strings.Contains(got, "gg: hook pre-commit took ") {
+		t.Errorf("stderr = %q; want hook duration", got)
+	}
+}
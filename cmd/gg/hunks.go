@@ -0,0 +1,239 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// A fileDiff is the changes to a single file in a unified diff, split
+// into hunks that can be selected individually.
+type fileDiff struct {
+	name   git.TopPath
+	header []string // lines from "diff --git" up to the first hunk
+	hunks  []*hunk
+
+	// selected is whether the whole file is selected. It is only used
+	// for files without hunks, like binary files or mode changes.
+	selected bool
+}
+
+// A hunk is a contiguous block of changed lines within a fileDiff.
+type hunk struct {
+	header   string   // the "@@ -a,b +c,d @@" line
+	lines    []string // context, added, and removed lines
+	selected bool
+}
+
+// parseDiff parses the output of git diff --no-renames.
+func parseDiff(out string) ([]*fileDiff, error) {
+	var files []*fileDiff
+	var curr *fileDiff
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			name, err := parseDiffGitLine(line)
+			if err != nil {
+				return nil, err
+			}
+			curr = &fileDiff{name: name, header: []string{line}}
+			files = append(files, curr)
+		case curr == nil:
+			if line != "" {
+				return nil, fmt.Errorf("parse diff: unexpected line %q", line)
+			}
+		case strings.HasPrefix(line, "@@ "):
+			curr.hunks = append(curr.hunks, &hunk{header: line})
+		case len(curr.hunks) > 0:
+			h := curr.hunks[len(curr.hunks)-1]
+			h.lines = append(h.lines, line)
+		default:
+			curr.header = append(curr.header, line)
+		}
+	}
+	return files, nil
+}
+
+// parseDiffGitLine returns the file name from a "diff --git a/X b/X"
+// line. Since renames are not detected, both names are the same.
+func parseDiffGitLine(line string) (git.TopPath, error) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if len(rest)%2 == 0 {
+		return "", fmt.Errorf("parse diff: malformed line %q", line)
+	}
+	first := rest[:len(rest)/2]
+	if strings.HasPrefix(first, `"`) {
+		var err error
+		first, err = strconv.Unquote(first)
+		if err != nil {
+			return "", fmt.Errorf("parse diff: malformed line %q", line)
+		}
+	}
+	name, ok := strings.CutPrefix(first, "a/")
+	if !ok {
+		return "", fmt.Errorf("parse diff: malformed line %q", line)
+	}
+	return git.TopPath(name), nil
+}
+
+// selectAll selects or deselects the whole file.
+func (fd *fileDiff) selectAll(selected bool) {
+	fd.selected = selected
+	for _, h := range fd.hunks {
+		h.selected = selected
+	}
+}
+
+// selection reports whether any and whether all of the file's changes
+// are selected.
+func (fd *fileDiff) selection() (some, all bool) {
+	if len(fd.hunks) == 0 {
+		return fd.selected, fd.selected
+	}
+	all = true
+	for _, h := range fd.hunks {
+		some = some || h.selected
+		all = all && h.selected
+	}
+	return some, all
+}
+
+// selectedPatch returns a patch that contains only the selected changes.
+// It must be applied with git apply --recount, since hunks that were not
+// selected change the line numbers of later hunks.
+func selectedPatch(files []*fileDiff) string {
+	sb := new(strings.Builder)
+	for _, fd := range files {
+		if some, _ := fd.selection(); !some {
+			continue
+		}
+		for _, line := range fd.header {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+		for _, h := range fd.hunks {
+			if !h.selected {
+				continue
+			}
+			sb.WriteString(h.header)
+			sb.WriteByte('\n')
+			for _, line := range h.lines {
+				sb.WriteString(line)
+				sb.WriteByte('\n')
+			}
+		}
+	}
+	return sb.String()
+}
+
+// selectedFiles returns the names of files with selected changes.
+func selectedFiles(files []*fileDiff) []git.TopPath {
+	var names []git.TopPath
+	for _, fd := range files {
+		if some, _ := fd.selection(); some {
+			names = append(names, fd.name)
+		}
+	}
+	return names
+}
+
+// readWorkingDiff returns the changes to tracked files between HEAD and
+// the working copy for the files matched by pathspecs.
+func readWorkingDiff(ctx context.Context, g *git.Git, pathspecs []git.Pathspec) ([]*fileDiff, error) {
+	base := git.Head.String()
+	if _, err := g.Head(ctx); err != nil {
+		// No commits yet: compare against the empty tree.
+		tree, err := g.NullTreeHash(ctx)
+		if err != nil {
+			return nil, err
+		}
+		base = tree.String()
+	}
+	args := []string{"diff", "--no-color", "--no-ext-diff", "--no-renames", "--binary", base, "--"}
+	for _, spec := range pathspecs {
+		args = append(args, spec.String())
+	}
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseDiff(out)
+}
+
+// commitSelected commits the selected changes in files on top of HEAD
+// without touching the working copy. The index entries of the committed
+// files are updated to match the new commit.
+func commitSelected(ctx context.Context, cc *cmdContext, files []*fileDiff, msg string, opts git.CommitOptions) error {
+	names := selectedFiles(files)
+	if len(names) == 0 {
+		return errors.New("no changes selected")
+	}
+	gitDir, err := cc.git.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(cc.abs(gitDir), "gg-commit-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	patchPath := filepath.Join(tmpDir, "selected.patch")
+	if err := os.WriteFile(patchPath, []byte(selectedPatch(files)), 0o666); err != nil {
+		return err
+	}
+
+	// Build the commit in a separate index so that the user's index is
+	// left alone if anything fails.
+	gitOpts := cc.gitOptions
+	gitOpts.Dir = cc.dir
+	gitOpts.Env = append(append([]string(nil), gitOpts.Env...), "GIT_INDEX_FILE="+filepath.Join(tmpDir, "index"))
+	tmpGit, err := git.New(gitOpts)
+	if err != nil {
+		return err
+	}
+	if _, err := cc.git.Head(ctx); err == nil {
+		err = tmpGit.Run(ctx, "read-tree", git.Head.String())
+		if err != nil {
+			return err
+		}
+	} else if err := tmpGit.Run(ctx, "read-tree", "--empty"); err != nil {
+		return err
+	}
+	if err := tmpGit.Run(ctx, "apply", "--cached", "--recount", "--whitespace=nowarn", patchPath); err != nil {
+		return fmt.Errorf("apply selected changes: %w", err)
+	}
+	if err := tmpGit.Commit(ctx, msg, opts); err != nil {
+		return err
+	}
+
+	resetArgs := []string{"reset", "--quiet", "--"}
+	for _, name := range names {
+		resetArgs = append(resetArgs, name.Pathspec().String())
+	}
+	if err := cc.git.Run(ctx, resetArgs...); err != nil {
+		return fmt.Errorf("update index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+const testDiff = `diff --git a/foo.txt b/foo.txt
+index 1111111..2222222 100644
+--- a/foo.txt
++++ b/foo.txt
+@@ -1,3 +1,3 @@
+-one
++ONE
+ two
+ three
+@@ -10,3 +10,4 @@ func main() {
+ ten
+ eleven
+ twelve
++thirteen
+diff --git a/bin.dat b/bin.dat
+index 3333333..4444444 100644
+GIT binary patch
+literal 3
+KcmZ>B%K!iX
+
+diff --git "a/sp\303\244ce.txt" "b/sp\303\244ce.txt"
+old mode 100644
+new mode 100755
+`
+
+func TestParseDiff(t *testing.T) {
+	files, err := parseDiff(testDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("len(files) = %d; want 3", len(files))
+	}
+	wantNames := []git.TopPath{"foo.txt", "bin.dat", "späce.txt"}
+	wantHunks := []int{2, 0, 0}
+	for i, fd := range files {
+		if fd.name != wantNames[i] {
+			t.Errorf("files[%d].name = %q; want %q", i, fd.name, wantNames[i])
+		}
+		if len(fd.hunks) != wantHunks[i] {
+			t.Errorf("len(files[%d].hunks) = %d; want %d", i, len(fd.hunks), wantHunks[i])
+		}
+	}
+	if got, want := len(files[0].header), 4; got != want {
+		t.Errorf("len(files[0].header) = %d; want %d", got, want)
+	}
+	if got, want := files[0].hunks[1].lines, []string{" ten", " eleven", " twelve", "+thirteen"}; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("files[0].hunks[1].lines = %q; want %q", got, want)
+	}
+}
+
+func TestSelectedPatch(t *testing.T) {
+	files, err := parseDiff(testDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files[0].hunks[1].selected = true
+	files[2].selected = true
+	got := selectedPatch(files)
+	const want = `diff --git a/foo.txt b/foo.txt
+index 1111111..2222222 100644
+--- a/foo.txt
++++ b/foo.txt
+@@ -10,3 +10,4 @@ func main() {
+ ten
+ eleven
+ twelve
++thirteen
+diff --git "a/sp\303\244ce.txt" "b/sp\303\244ce.txt"
+old mode 100644
+new mode 100755
+`
+	if got != want {
+		t.Errorf("selectedPatch(...) =\n%s\nwant:\n%s", got, want)
+	}
+	if got, want := selectedFiles(files), []git.TopPath{"foo.txt", "späce.txt"}; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("selectedFiles(...) = %q; want %q", got, want)
+	}
+}
+
+func TestCommitSelected(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	var oldLines []string
+	for i := 1; i <= 20; i++ {
+		oldLines = append(oldLines, strings.Repeat("x", i))
+	}
+	oldContent := strings.Join(oldLines, "\n") + "\n"
+	err = env.root.Apply(
+		filesystem.Write("foo.txt", oldContent),
+		filesystem.Write("bar.txt", dummyContent),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt", "bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	newLines := append([]string(nil), oldLines...)
+	newLines[0] = "first"
+	newLines[19] = "last"
+	newContent := strings.Join(newLines, "\n") + "\n"
+	err = env.root.Apply(
+		filesystem.Write("foo.txt", newContent),
+		filesystem.Write("bar.txt", "changed\n"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := readWorkingDiff(ctx, env.git, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var foo *fileDiff
+	for _, fd := range files {
+		if fd.name == "foo.txt" {
+			foo = fd
+		}
+	}
+	if len(files) != 2 || foo == nil || len(foo.hunks) != 2 {
+		t.Fatalf("readWorkingDiff found %d files; want foo.txt with 2 hunks and bar.txt", len(files))
+	}
+	foo.hunks[0].selected = true
+	cc := &cmdContext{
+		dir: env.root.String(),
+		git: env.git,
+		gitOptions: git.Options{
+			GitExe: env.git.Exe(),
+			Env: append(os.Environ(),
+				"GIT_CONFIG_NOSYSTEM=1",
+				"HOME="+env.topDir.String(),
+				"XDG_CONFIG_HOME="+env.topDir.FromSlash("xdgconfig"),
+				"XDG_CONFIG_DIRS="+env.topDir.FromSlash("xdgconfig"),
+			),
+		},
+	}
+	if err := commitSelected(ctx, cc, files, "partial\n", git.CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The commit should only have the first hunk.
+	wantLines := append([]string(nil), oldLines...)
+	wantLines[0] = "first"
+	if got, err := catBlob(ctx, env.git, "HEAD", "foo.txt"); err != nil {
+		t.Error(err)
+	} else if want := strings.Join(wantLines, "\n") + "\n"; string(got) != want {
+		t.Errorf("foo.txt @ HEAD = %q; want %q", got, want)
+	}
+	if got, err := catBlob(ctx, env.git, "HEAD", "bar.txt"); err != nil {
+		t.Error(err)
+	} else if string(got) != dummyContent {
+		t.Errorf("bar.txt @ HEAD = %q; want %q", got, dummyContent)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Message != "partial\n" {
+		t.Errorf("message = %q; want \"partial\n\"", info.Message)
+	}
+
+	// The working copy should be untouched.
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Error(err)
+	} else if got != newContent {
+		t.Errorf("foo.txt = %q; want %q", got, newContent)
+	}
+	if got, err := env.root.ReadFile("bar.txt"); err != nil {
+		t.Error(err)
+	} else if got != "changed\n" {
+		t.Errorf("bar.txt = %q; want \"changed\n\"", got)
+	}
+
+	// And the remaining changes should be left to commit.
+	files, err = readWorkingDiff(ctx, env.git, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("after commit, %d files changed; want 2", len(files))
+	}
+}
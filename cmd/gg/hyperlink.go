@@ -0,0 +1,83 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/terminal"
+)
+
+// useHyperlinks reports whether output to stdout should include
+// hyperlinks, based on the gg.hyperlinks configuration setting.
+// The setting may be "auto" (the default), "always", "never", or a boolean.
+func useHyperlinks(cc *cmdContext, cfg *git.Config) (bool, error) {
+	switch cfg.Value("gg.hyperlinks") {
+	case "", "auto":
+		return terminal.IsTerminal(cc.stdout) && terminal.SupportsHyperlinks(cc.env), nil
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	default:
+		return cfg.Bool("gg.hyperlinks")
+	}
+}
+
+// ansiPrefix matches the graph drawing and color escape sequences that
+// git log may print before a line's content.
+const ansiPrefix = `(?:[*|/\\_ ]|\x1b\[[0-9;]*m)*`
+
+var (
+	logCommitLineRegexp = regexp.MustCompile(`^(` + ansiPrefix + `commit (?:\x1b\[[0-9;]*m)*)([0-9a-f]{40}(?:[0-9a-f]{24})?)`)
+	logStatLineRegexp   = regexp.MustCompile(`^(` + ansiPrefix + ` )([^\s|{}]+)( +\| +(?:[0-9]+|Bin)\b)`)
+	changeNumberRegexp  = regexp.MustCompile(`(^|[\s(\[])#([0-9]+)\b`)
+)
+
+// A logLinker adds hyperlinks to a forge to lines of git log output:
+// commit hashes link to the commit, file names in --stat output link to
+// the file at that commit, and references like "#123" link to the pull
+// request.
+type logLinker struct {
+	forge  *forge
+	commit string // hash of the commit being shown
+}
+
+// line returns the line with hyperlinks added.
+func (l *logLinker) line(line string) string {
+	if m := logCommitLineRegexp.FindStringSubmatchIndex(line); m != nil {
+		l.commit = line[m[4]:m[5]]
+		return line[:m[3]] + terminal.Hyperlink(l.forge.commitURL(l.commit), l.commit) + line[m[5]:]
+	}
+	if m := logStatLineRegexp.FindStringSubmatchIndex(line); m != nil && l.commit != "" {
+		name := line[m[4]:m[5]]
+		// Skip names that git abbreviated. File names with spaces and
+		// renames are not matched by the regexp, since they can't be
+		// distinguished from commit messages.
+		if !strings.HasPrefix(name, "...") {
+			url := l.forge.fileURL(l.commit, git.TopPath(name))
+			return line[:m[4]] + terminal.Hyperlink(url, name) + line[m[5]:]
+		}
+		return line
+	}
+	return changeNumberRegexp.ReplaceAllStringFunc(line, func(s string) string {
+		i := strings.IndexByte(s, '#')
+		return s[:i] + terminal.Hyperlink(l.forge.changeURL(s[i+1:]), s[i:])
+	})
+}
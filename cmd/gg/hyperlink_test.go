@@ -0,0 +1,68 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"gg-scm.io/tool/internal/terminal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLogLinker(t *testing.T) {
+	const (
+		repoURL = "https://github.com/example/foo"
+		hash    = "8bde7f5b7d57a4b3b1d2d0a6f6a1bb4d11f3b1f5"
+	)
+	commitLink := terminal.Hyperlink(repoURL+"/commit/"+hash, hash)
+	input := []string{
+		"* \x1b[33mcommit " + hash + "\x1b[m (HEAD -> main)",
+		"| Author: Octocat <octocat@example.com>",
+		"| ",
+		"|     Fix the frobnicator (#42)",
+		"|     ",
+		"|     The value | 12 is not a stat line.",
+		"| ",
+		"|  cmd/main.go | 3 ++-",
+		"|  .../deeply/nested/file.go | 1 +",
+		"|  {old => new}/file.go | 0",
+		"|  2 files changed, 2 insertions(+), 1 deletion(-)",
+		"issue#7 is not a reference",
+	}
+	want := []string{
+		"* \x1b[33mcommit " + commitLink + "\x1b[m (HEAD -> main)",
+		"| Author: Octocat <octocat@example.com>",
+		"| ",
+		"|     Fix the frobnicator (" + terminal.Hyperlink(repoURL+"/pull/42", "#42") + ")",
+		"|     ",
+		"|     The value | 12 is not a stat line.",
+		"| ",
+		"|  " + terminal.Hyperlink(repoURL+"/blob/"+hash+"/cmd/main.go", "cmd/main.go") + " | 3 ++-",
+		"|  .../deeply/nested/file.go | 1 +",
+		"|  {old => new}/file.go | 0",
+		"|  2 files changed, 2 insertions(+), 1 deletion(-)",
+		"issue#7 is not a reference",
+	}
+	l := &logLinker{forge: &forge{kind: "github", repoURL: repoURL}}
+	var got []string
+	for _, line := range input {
+		got = append(got, l.line(line))
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("linked output (-want +got):\n%s", diff)
+	}
+}
@@ -17,6 +17,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"sort"
 
 	"gg-scm.io/pkg/git"
@@ -26,7 +27,7 @@ import (
 const identifySynopsis = "identify the working directory or specified revision"
 
 func identify(ctx context.Context, cc *cmdContext, args []string) (err error) {
-	f := flag.NewFlagSet(true, "gg identify [-r REV]", identifySynopsis+`
+	f := flag.NewFlagSet(true, "gg identify [--json] [-r REV]", identifySynopsis+`
 
 aliases: id
 
@@ -34,8 +35,11 @@ aliases: id
 	was provided. The revision's hash identifier is printed, followed by
 	a "+" if the working copy is being summarized and there are
 	uncommitted changes, a list of branches it is the tip of, and a list
-	of tags.`)
+	of tags.
+
+	With `+"`--json`"+`, the same information is printed as a JSON object.`)
 	revFlag := f.String("r", "HEAD", "identify the specified `rev`ision")
+	jsonOutput := f.Bool("json", false, "print a JSON object instead of a line of text")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -46,13 +50,19 @@ aliases: id
 		return usagef("identify takes no arguments")
 	}
 
+	isHead := *revFlag == "HEAD" || *revFlag == "@"
 	rev, err := cc.git.ParseRev(ctx, *revFlag)
 	if err != nil {
+		if isHead {
+			if headRef, refErr := cc.git.HeadRef(ctx); refErr == nil && headRef.IsBranch() {
+				return fmt.Errorf("on branch %s with no commits yet", headRef.Branch())
+			}
+		}
 		return err
 	}
 
 	hasChanges := false
-	if *revFlag == "HEAD" || *revFlag == "@" {
+	if isHead {
 		status, err := cc.git.Status(ctx, git.StatusOptions{})
 		if err != nil {
 			return err
@@ -89,6 +99,21 @@ aliases: id
 	sort.Strings(branchNames)
 	sort.Strings(tagNames)
 
+	if *jsonOutput {
+		v := &identifyJSON{
+			Commit:   rev.Commit.String(),
+			Dirty:    hasChanges,
+			Branches: branchNames,
+			Tags:     tagNames,
+		}
+		if v.Branches == nil {
+			v.Branches = []string{}
+		}
+		if v.Tags == nil {
+			v.Tags = []string{}
+		}
+		return writeJSON(cc.stdout, v)
+	}
 	out := new(bytes.Buffer)
 	out.WriteString(rev.Commit.String())
 	if hasChanges {
@@ -106,3 +131,11 @@ aliases: id
 	_, err = cc.stdout.Write(out.Bytes())
 	return err
 }
+
+// identifyJSON is the output of gg identify --json.
+type identifyJSON struct {
+	Commit   string   `json:"commit"`
+	Dirty    bool     `json:"dirty,omitempty"` // working copy has uncommitted changes
+	Branches []string `json:"branches"`
+	Tags     []string `json:"tags"`
+}
@@ -16,10 +16,12 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestIdentify(t *testing.T) {
@@ -249,3 +251,46 @@ func TestIdentify(t *testing.T) {
 		}
 	})
 }
+
+func TestIdentify_JSON(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "tag", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "identify", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got identifyJSON
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	want := identifyJSON{
+		Commit:   head.Commit.String(),
+		Dirty:    true,
+		Branches: []string{"main"},
+		Tags:     []string{"v1.0.0"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("gg identify --json (-want +got):\n%s", diff)
+	}
+}
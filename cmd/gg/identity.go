@@ -0,0 +1,347 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const identitySynopsis = "manage author identity profiles"
+
+// identitySection is the Git configuration section that stores identity
+// profiles. Each profile is a subsection, as in "ggidentity.work.email".
+const identitySection = "ggidentity"
+
+var identityNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func identity(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg identity [list]\n"+
+		"gg identity add [--name NAME] [--email EMAIL] [--signing-key KEY] [--forge HOST/PATH] PROFILE\n"+
+		"gg identity use PROFILE\n"+
+		"gg identity remove PROFILE", identitySynopsis+`
+
+	An identity profile is a named author name, email address, and
+	optional signing key, like "work" or "personal". Profiles are stored
+	in the user's Git configuration, so they are available in every
+	repository.
+
+	`+"`gg identity add`"+` creates or updates a profile.
+	`+"`gg identity use`"+` sets the repository's `+"`user.name`"+`,
+	`+"`user.email`"+`, and `+"`user.signingKey`"+` from a profile.
+	`+"`gg identity`"+` lists the profiles and marks the one the repository
+	uses.
+
+	`+"`gg commit`"+` warns when the author email does not match the profile
+	the repository uses. If the repository does not use a profile, but its
+	remote is on a forge that a profile was added for with `+"`--forge`"+`
+	(like `+"`github.com/example`"+`), then it warns if the email does not
+	match that profile.`)
+	name := f.String("name", "", "author `name`")
+	email := f.String("email", "", "author `email`")
+	signingKey := f.String("signing-key", "", "signing `key` ID")
+	forgePrefix := f.String("forge", "", "`prefix` of repository URLs that use this profile, like github.com/example")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	sub := f.Arg(0)
+	if sub != "add" && (*name != "" || *email != "" || *signingKey != "" || *forgePrefix != "") {
+		return usagef("--name, --email, --signing-key, and --forge can only be used with add")
+	}
+	switch sub {
+	case "", "list":
+		if f.NArg() > 1 {
+			return usagef("list takes no arguments")
+		}
+		return listIdentities(ctx, cc)
+	case "add", "use", "remove":
+		if f.NArg() != 2 {
+			return usagef("%s takes a single PROFILE", sub)
+		}
+	default:
+		return usagef("unknown subcommand %q", sub)
+	}
+	profileName := f.Arg(1)
+	if !identityNameRegexp.MatchString(profileName) {
+		return usagef("invalid profile name %q (must be lowercase letters, digits, '-', or '_')", profileName)
+	}
+	switch sub {
+	case "add":
+		if *name == "" && *email == "" && *signingKey == "" && *forgePrefix == "" {
+			return usagef("add requires at least one of --name, --email, --signing-key, or --forge")
+		}
+		fields := []struct {
+			key   string
+			value string
+		}{
+			{"name", *name},
+			{"email", *email},
+			{"signingKey", *signingKey},
+			{"forge", normalizeForgePrefix(*forgePrefix)},
+		}
+		for _, field := range fields {
+			if field.value == "" {
+				continue
+			}
+			key := identitySection + "." + profileName + "." + field.key
+			if err := cc.git.Run(ctx, "config", "--global", "--", key, field.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "use":
+		return useIdentity(ctx, cc, profileName)
+	default:
+		profiles, err := readIdentities(ctx, cc.git)
+		if err != nil {
+			return err
+		}
+		if profiles[profileName] == nil {
+			return fmt.Errorf("no identity profile %q", profileName)
+		}
+		return cc.git.Run(ctx, "config", "--global", "--remove-section", "--", identitySection+"."+profileName)
+	}
+}
+
+// An identityProfile is a named author identity.
+type identityProfile struct {
+	name       string // profile name
+	authorName string
+	email      string
+	signingKey string
+	forge      string // URL prefix without scheme, like "github.com/example"
+}
+
+// readIdentities returns the identity profiles in the Git configuration.
+func readIdentities(ctx context.Context, g *git.Git) (map[string]*identityProfile, error) {
+	entries, err := listConfig(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(map[string]*identityProfile)
+	for _, entry := range entries {
+		rest, ok := strings.CutPrefix(entry.key, identitySection+".")
+		if !ok {
+			continue
+		}
+		i := strings.LastIndexByte(rest, '.')
+		if i == -1 {
+			continue
+		}
+		profileName, field := rest[:i], rest[i+1:]
+		p := profiles[profileName]
+		if p == nil {
+			p = &identityProfile{name: profileName}
+			profiles[profileName] = p
+		}
+		switch field {
+		case "name":
+			p.authorName = entry.value
+		case "email":
+			p.email = entry.value
+		case "signingkey":
+			p.signingKey = entry.value
+		case "forge":
+			p.forge = entry.value
+		}
+	}
+	return profiles, nil
+}
+
+// A configEntry is a Git configuration variable. Section and variable
+// names in key are lowercase.
+type configEntry struct {
+	key   string
+	value string
+}
+
+// listConfig returns the Git configuration variables in the order Git
+// reads them. opts are passed to git config, like "--local".
+func listConfig(ctx context.Context, g *git.Git, opts ...string) ([]configEntry, error) {
+	args := append([]string{"config", "--null", "--list"}, opts...)
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var entries []configEntry
+	for _, entry := range strings.Split(out, "\x00") {
+		if entry == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(entry, "\n")
+		entries = append(entries, configEntry{key: key, value: value})
+	}
+	return entries, nil
+}
+
+func listIdentities(ctx context.Context, cc *cmdContext) error {
+	profiles, err := readIdentities(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		_, err := fmt.Fprintln(cc.stdout, "no identity profiles (add one with 'gg identity add')")
+		return err
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	current := cfg.Value("gg.identity")
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		p := profiles[name]
+		marker := ' '
+		if name == current {
+			marker = '*'
+		}
+		var extra []string
+		if p.signingKey != "" {
+			extra = append(extra, "key "+p.signingKey)
+		}
+		if p.forge != "" {
+			extra = append(extra, p.forge)
+		}
+		fmt.Fprintf(tw, "%c %s\t%s <%s>\t%s\n", marker, name, p.authorName, p.email, strings.Join(extra, "  "))
+	}
+	return tw.Flush()
+}
+
+// useIdentity sets the repository's identity from a profile.
+func useIdentity(ctx context.Context, cc *cmdContext, profileName string) error {
+	profiles, err := readIdentities(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	p := profiles[profileName]
+	if p == nil {
+		return fmt.Errorf("no identity profile %q (add one with 'gg identity add')", profileName)
+	}
+	local, err := listConfig(ctx, cc.git, "--local")
+	if err != nil {
+		return err
+	}
+	settings := []struct {
+		key   string
+		value string
+	}{
+		{"user.name", p.authorName},
+		{"user.email", p.email},
+		{"user.signingKey", p.signingKey},
+		{"gg.identity", p.name},
+	}
+	for _, s := range settings {
+		if s.value != "" {
+			if err := cc.git.Run(ctx, "config", "--local", "--", s.key, s.value); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, ent := range local {
+			if ent.key == strings.ToLower(s.key) {
+				if err := cc.git.Run(ctx, "config", "--local", "--unset-all", "--", s.key); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// warnIdentityMismatch prints a warning if the author email for a new
+// commit does not match the identity profile the repository uses. If the
+// repository does not use a profile, it checks against the profile for
+// the forge that hosts the repository, if any.
+func warnIdentityMismatch(ctx context.Context, cc *cmdContext) {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return
+	}
+	ident, err := cc.git.Output(ctx, "var", "GIT_AUTHOR_IDENT")
+	if err != nil {
+		return
+	}
+	email := identEmail(ident)
+	profiles, err := readIdentities(ctx, cc.git)
+	if err != nil || len(profiles) == 0 {
+		return
+	}
+	if current := cfg.Value("gg.identity"); current != "" {
+		if p := profiles[current]; p != nil && p.email != "" && !strings.EqualFold(p.email, email) {
+			fmt.Fprintf(cc.stderr, "gg: warning: committing as %s, but this repository uses identity %q (%s); run 'gg identity use %s' to fix\n",
+				email, current, p.email, current)
+		}
+		return
+	}
+	f := forgeForRepository(cfg, currentBranch(ctx, cc))
+	if f == nil {
+		return
+	}
+	repo := normalizeForgePrefix(f.repoURL)
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := profiles[name]
+		if p.forge == "" || p.email == "" || (repo != p.forge && !strings.HasPrefix(repo, p.forge+"/")) {
+			continue
+		}
+		if !strings.EqualFold(p.email, email) {
+			fmt.Fprintf(cc.stderr, "gg: warning: committing as %s, but identity %q (%s) is for %s; run 'gg identity use %s' to switch\n",
+				email, name, p.email, p.forge, name)
+		}
+		return
+	}
+}
+
+// identEmail returns the email address from a Git identity
+// like "Octocat <octocat@example.com> 1234567890 +0000".
+func identEmail(ident string) string {
+	start := strings.IndexByte(ident, '<')
+	end := strings.LastIndexByte(ident, '>')
+	if start == -1 || end < start {
+		return ""
+	}
+	return ident[start+1 : end]
+}
+
+// normalizeForgePrefix strips the scheme and trailing slashes from a
+// repository URL prefix.
+func normalizeForgePrefix(prefix string) string {
+	if _, rest, ok := strings.Cut(prefix, "://"); ok {
+		prefix = rest
+	}
+	return strings.TrimRight(prefix, "/")
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestIdentity(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	dir := env.root.String()
+	if _, err := env.gg(ctx, dir, "identity", "add", "--name", "Work Me", "--email", "me@work.example", "--signing-key", "ABC123", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, dir, "identity", "add", "--name", "Home Me", "--email", "me@home.example", "personal"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, dir, "identity", "use", "work"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := env.git.ReadConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"user.name":       "Work Me",
+		"user.email":      "me@work.example",
+		"user.signingKey": "ABC123",
+		"gg.identity":     "work",
+	} {
+		if got := cfg.Value(key); got != want {
+			t.Errorf("after identity use work, %s = %q; want %q", key, got, want)
+		}
+	}
+	out, err := env.gg(ctx, dir, "identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "* work ") || !strings.Contains(string(out), "  personal ") {
+		t.Errorf("gg identity =\n%s\nwant work marked as current", out)
+	}
+
+	// Commit with a different email.
+	if err := env.git.Run(ctx, "config", "user.email", "other@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.trackFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, dir, "commit", "-m", "message"); err != nil {
+		t.Fatal(err)
+	}
+	const wantWarning = `gg: warning: committing as other@example.com, but this repository uses identity "work" (me@work.example)`
+	if got := env.stderr.String(); !strings.Contains(got, wantWarning) {
+		t.Errorf("stderr = %q; want to contain %q", got, wantWarning)
+	}
+
+	// Switching to a profile without a signing key removes the key.
+	if _, err := env.gg(ctx, dir, "identity", "use", "personal"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = env.git.ReadConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Value("user.email"); got != "me@home.example" {
+		t.Errorf("after identity use personal, user.email = %q; want %q", got, "me@home.example")
+	}
+	if got := cfg.Value("user.signingKey"); got != "" {
+		t.Errorf("after identity use personal, user.signingKey = %q; want unset", got)
+	}
+
+	if _, err := env.gg(ctx, dir, "identity", "use", "nope"); err == nil {
+		t.Error("identity use nope did not return error")
+	} else if isUsage(err) {
+		t.Errorf("identity use nope returned usage error: %v", err)
+	}
+	if _, err := env.gg(ctx, dir, "identity", "add", "--email", "x@example.com", "Bad Name"); err == nil {
+		t.Error("identity add with invalid name did not return error")
+	} else if !isUsage(err) {
+		t.Errorf("Error = %v; want usage", err)
+	}
+}
+
+func TestIdentity_ForgeWarning(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "remote", "add", "origin", "https://github.com/acme/widget.git"); err != nil {
+		t.Fatal(err)
+	}
+	dir := env.root.String()
+	if _, err := env.gg(ctx, dir, "identity", "add", "--email", "me@acme.example", "--forge", "https://github.com/acme/", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.trackFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, dir, "commit", "-m", "message"); err != nil {
+		t.Fatal(err)
+	}
+	const want = `gg: warning: committing as foo@example.com, but identity "work" (me@acme.example) is for github.com/acme`
+	if got := env.stderr.String(); !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const importSynopsis = "fetch a pull request or Gerrit change into a new branch"
+
+func importChange(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg import [-b BRANCH] [--patch] URL", importSynopsis+`
+
+	URL is the web page of a GitHub pull request (like
+	`+"`https://github.com/example/foo/pull/123`"+`) or a Gerrit change (like
+	`+"`https://go-review.googlesource.com/c/go/+/12345`"+`, optionally with a
+	patch set number). gg fetches the change's commits, creates a local
+	branch for them, and checks it out. The branch is named `+"`pr/123`"+` or
+	`+"`change/12345`"+` unless `+"`-b`"+` is given.
+
+	The new branch tracks the change so that `+"`gg pull`"+` picks up later
+	revisions. For a GitHub pull request whose author allows edits from
+	maintainers, the branch tracks the author's branch, adding a remote
+	named after the author if needed, so `+"`gg push`"+` updates the pull
+	request. For a Gerrit change, the branch tracks the change's target
+	branch, so `+"`gg mail`"+` uploads a new patch set.
+
+	`+"`--patch`"+` downloads the change as patches over HTTPS and applies them
+	on top of HEAD instead of fetching with Git, for when the repository
+	can't be reached over a Git protocol. The new branch doesn't track
+	anything.`)
+	branch := f.String("b", "", "`name` of the branch to create")
+	f.Alias("b", "branch")
+	asPatch := f.Bool("patch", false, "apply the change as patches instead of fetching it")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() != 1 {
+		return usagef("must pass a single URL")
+	}
+	change, err := parseChangeURL(f.Arg(0))
+	if err != nil {
+		return usagef("%v", err)
+	}
+	if *branch == "" {
+		*branch = change.branch
+	}
+	if err := cc.git.Run(ctx, "check-ref-format", "--branch", *branch); err != nil {
+		return fmt.Errorf("invalid branch name %q", *branch)
+	}
+	if _, err := cc.git.ParseRev(ctx, git.BranchRef(*branch).String()); err == nil {
+		return fmt.Errorf("branch %s already exists", *branch)
+	}
+	if *asPatch {
+		return importPatches(ctx, cc, change, *branch)
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	track, err := change.resolve(ctx, cc, cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cc.stderr, "gg: fetching %s from %s\n", change.ref, track.fetchFrom)
+	err = cc.interactiveGit(ctx, "fetch", "--", track.fetchFrom, "+"+change.ref+":"+git.BranchRef(*branch).String())
+	if err != nil {
+		return fmt.Errorf("%w\n(pass --patch to download the change over HTTPS instead)", err)
+	}
+	if track.remote != "" {
+		settings := []struct {
+			key   string
+			value string
+		}{
+			{"branch." + *branch + ".remote", track.remote},
+			{"branch." + *branch + ".merge", track.merge},
+		}
+		for _, s := range settings {
+			if err := cc.git.Run(ctx, "config", "--local", "--", s.key, s.value); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cc.git.CheckoutBranch(ctx, *branch, git.CheckoutOptions{}); err != nil {
+		return err
+	}
+	if track.remote != "" {
+		fmt.Fprintf(cc.stderr, "gg: created branch %s tracking %s of %s\n", *branch, track.merge, track.remote)
+	}
+	return nil
+}
+
+// A changeURL is a parsed GitHub pull request or Gerrit change URL.
+type changeURL struct {
+	kind     string // "github" or "gerrit"
+	number   int
+	patchSet int    // Gerrit patch set, or zero for the latest
+	repoURL  string // Git URL of the repository that has the change
+	ref      string // ref of the change, empty if not known yet
+	branch   string // default name for the local branch
+
+	owner, repo string // GitHub
+	apiBase     string // Gerrit REST API URL, ending with a slash
+	project     string // Gerrit
+}
+
+// parseChangeURL parses the URL of a GitHub pull request or Gerrit change.
+func parseChangeURL(s string) (*changeURL, error) {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("%q is not a pull request or change URL", s)
+	}
+	p := strings.TrimSuffix(u.Path, "/")
+	if u.Host == "github.com" {
+		parts := strings.Split(strings.TrimPrefix(p, "/"), "/")
+		if len(parts) < 4 || parts[2] != "pull" {
+			return nil, fmt.Errorf("%q is not a GitHub pull request URL", s)
+		}
+		n, err := strconv.Atoi(parts[3])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%q is not a GitHub pull request URL", s)
+		}
+		owner, repo := parts[0], strings.TrimSuffix(parts[1], ".git")
+		return &changeURL{
+			kind:    "github",
+			number:  n,
+			repoURL: "https://github.com/" + owner + "/" + repo + ".git",
+			ref:     fmt.Sprintf("refs/pull/%d/head", n),
+			branch:  fmt.Sprintf("pr/%d", n),
+			owner:   owner,
+			repo:    repo,
+		}, nil
+	}
+
+	// Gerrit: [PREFIX]/c/PROJECT/+/NUMBER[/PATCHSET[/FILE]]
+	i := strings.Index(p, "/c/")
+	j := strings.Index(p, "/+/")
+	if i == -1 || j <= i+len("/c/") {
+		return nil, fmt.Errorf("%q is not a GitHub pull request or Gerrit change URL", s)
+	}
+	prefix, project := p[:i+1], p[i+len("/c/"):j]
+	rest := strings.Split(p[j+len("/+/"):], "/")
+	n, err := strconv.Atoi(rest[0])
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("%q is not a Gerrit change URL", s)
+	}
+	change := &changeURL{
+		kind:    "gerrit",
+		number:  n,
+		repoURL: u.Scheme + "://" + u.Host + prefix + project,
+		branch:  fmt.Sprintf("change/%d", n),
+		apiBase: u.Scheme + "://" + u.Host + prefix,
+		project: project,
+	}
+	if len(rest) > 1 {
+		if ps, err := strconv.Atoi(rest[1]); err == nil && ps > 0 {
+			change.patchSet = ps
+			change.ref = gerritChangeRef(n, ps)
+		}
+	}
+	return change, nil
+}
+
+// gerritChangeRef returns the ref that Gerrit stores a patch set under.
+func gerritChangeRef(n, patchSet int) string {
+	return fmt.Sprintf("refs/changes/%02d/%d/%d", n%100, n, patchSet)
+}
+
+// A changeTracking is where to fetch an imported change from and what
+// the new branch should track.
+type changeTracking struct {
+	fetchFrom string // remote name or URL
+	remote    string // branch.*.remote, or empty to not track
+	merge     string // branch.*.merge
+}
+
+// resolve finds the change's ref if it is not known yet and decides what
+// the imported branch should track.
+func (change *changeURL) resolve(ctx context.Context, cc *cmdContext, cfg *git.Config) (*changeTracking, error) {
+	track := &changeTracking{fetchFrom: remoteForURL(cfg, change.repoURL)}
+	if track.fetchFrom == "" {
+		track.fetchFrom = change.repoURL
+	}
+	switch change.kind {
+	case "github":
+		track.remote = track.fetchFrom
+		track.merge = change.ref
+		var token string
+		if acct, err := readGitHubAccount(cc, selectedGitHubAccount(ctx, cc)); err == nil {
+			token = acct.token
+		}
+		pr, err := getGitHubPullRequest(ctx, cc.httpClient, token, change.owner, change.repo, change.number)
+		if err != nil {
+			fmt.Fprintf(cc.stderr, "gg: %v; the branch will track %s\n", err, change.ref)
+			return track, nil
+		}
+		if pr.Head.Repo == nil || !pr.MaintainerCanModify && pr.Head.Repo.CloneURL != change.repoURL {
+			return track, nil
+		}
+		headRemote := remoteForURL(cfg, pr.Head.Repo.CloneURL)
+		if headRemote == "" {
+			headRemote = pr.Head.Repo.Owner.Login
+			if cfg.ListRemotes()[headRemote] != nil || cc.git.Run(ctx, "check-ref-format", "refs/remotes/"+headRemote+"/x") != nil {
+				return track, nil
+			}
+			if err := cc.git.Run(ctx, "remote", "add", "--", headRemote, pr.Head.Repo.CloneURL); err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(cc.stderr, "gg: added remote %s for %s\n", headRemote, pr.Head.Repo.CloneURL)
+		}
+		track.remote = headRemote
+		track.merge = git.BranchRef(pr.Head.Ref).String()
+	case "gerrit":
+		info, err := getGerritChange(ctx, cc.httpClient, change)
+		if err == nil {
+			track.remote = track.fetchFrom
+			track.merge = git.BranchRef(info.Branch).String()
+		}
+		if change.ref != "" {
+			break
+		}
+		if err == nil && info.CurrentRevision != "" {
+			if rev := info.Revisions[info.CurrentRevision]; rev.Ref != "" {
+				change.ref = rev.Ref
+				break
+			}
+		}
+		// Ask the Git server for the latest patch set.
+		ps, lsErr := latestGerritPatchSet(ctx, cc.git, track.fetchFrom, change.number)
+		if lsErr != nil {
+			if err != nil {
+				return nil, fmt.Errorf("find latest patch set of change %d: %v", change.number, err)
+			}
+			return nil, lsErr
+		}
+		change.ref = gerritChangeRef(change.number, ps)
+	}
+	return track, nil
+}
+
+// remoteForURL returns the name of the remote that fetches from the same
+// repository as repoURL, or the empty string if there isn't one.
+func remoteForURL(cfg *git.Config, repoURL string) string {
+	wantHost, wantPath := splitRemoteURL(repoURL)
+	wantPath = strings.TrimSuffix(strings.Trim(wantPath, "/"), ".git")
+	for name, r := range cfg.ListRemotes() {
+		host, path := splitRemoteURL(r.FetchURL)
+		if host == wantHost && strings.TrimSuffix(strings.Trim(path, "/"), ".git") == wantPath {
+			return name
+		}
+	}
+	return ""
+}
+
+// latestGerritPatchSet lists the patch set refs of a Gerrit change on a
+// remote and returns the highest patch set number.
+func latestGerritPatchSet(ctx context.Context, g *git.Git, remote string, n int) (int, error) {
+	prefix := fmt.Sprintf("refs/changes/%02d/%d/", n%100, n)
+	out, err := g.Output(ctx, "ls-remote", "--", remote, prefix+"*")
+	if err != nil {
+		return 0, err
+	}
+	latest := 0
+	for _, line := range strings.Split(out, "\n") {
+		_, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		ps, err := strconv.Atoi(strings.TrimPrefix(ref, prefix))
+		if err == nil && ps > latest {
+			latest = ps
+		}
+	}
+	if latest == 0 {
+		return 0, fmt.Errorf("no patch sets found for change %d on %s", n, remote)
+	}
+	return latest, nil
+}
+
+// gitHubPullRequest is a pull request as returned by the GitHub API.
+type gitHubPullRequest struct {
+	MaintainerCanModify bool `json:"maintainer_can_modify"`
+	Head                struct {
+		Ref  string
+		Repo *struct {
+			CloneURL string `json:"clone_url"`
+			Owner    struct {
+				Login string
+			}
+		}
+	}
+}
+
+// getGitHubPullRequest asks GitHub about a pull request.
+// authToken may be empty for public repositories.
+func getGitHubPullRequest(ctx context.Context, client *http.Client, authToken, owner, repo string, n int) (*gitHubPullRequest, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", url.PathEscape(owner), url.PathEscape(repo), n)
+	pr := new(gitHubPullRequest)
+	if err := getGitHubJSON(ctx, client, authToken, apiURL, pr); err != nil {
+		return nil, fmt.Errorf("get pull request %s/%s#%d: %w", owner, repo, n, err)
+	}
+	return pr, nil
+}
+
+// gerritChange is a change as returned by the Gerrit REST API.
+type gerritChange struct {
+	Branch          string
+	CurrentRevision string `json:"current_revision"`
+	Revisions       map[string]struct {
+		Ref string
+	}
+}
+
+// getGerritChange asks the Gerrit server about a change.
+func getGerritChange(ctx context.Context, client *http.Client, change *changeURL) (*gerritChange, error) {
+	apiURL := fmt.Sprintf("%schanges/%s~%d?o=CURRENT_REVISION", change.apiBase, url.PathEscape(change.project), change.number)
+	body, err := getGerrit(ctx, client, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	info := new(gerritChange)
+	if err := json.Unmarshal(body, info); err != nil {
+		return nil, fmt.Errorf("get change %d: parsing response: %w", change.number, err)
+	}
+	return info, nil
+}
+
+// getGerrit sends a GET request to the Gerrit REST API and returns the
+// response body without the prefix that Gerrit adds to JSON responses.
+func getGerrit(ctx context.Context, client *http.Client, apiURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgentString())
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: HTTP %s", apiURL, resp.Status)
+	}
+	body, err := io.ReadAll(&limitedReader{resp.Body, 64 << 20})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", apiURL, err)
+	}
+	return bytes.TrimPrefix(body, []byte(")]}'\n")), nil
+}
+
+// importPatches downloads a change as patches and applies them on a new
+// branch at HEAD.
+func importPatches(ctx context.Context, cc *cmdContext, change *changeURL, branch string) error {
+	var patches []byte
+	switch change.kind {
+	case "github":
+		patchURL := fmt.Sprintf("https://github.com/%s/%s/pull/%d.patch", url.PathEscape(change.owner), url.PathEscape(change.repo), change.number)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, patchURL, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("User-Agent", userAgentString())
+		resp, err := cc.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("download patches: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("download patches: %s: HTTP %s", patchURL, resp.Status)
+		}
+		patches, err = io.ReadAll(&limitedReader{resp.Body, 64 << 20})
+		if err != nil {
+			return fmt.Errorf("download patches: %w", err)
+		}
+	case "gerrit":
+		revision := "current"
+		if change.patchSet > 0 {
+			revision = strconv.Itoa(change.patchSet)
+		}
+		apiURL := fmt.Sprintf("%schanges/%s~%d/revisions/%s/patch", change.apiBase, url.PathEscape(change.project), change.number, revision)
+		encoded, err := getGerrit(ctx, cc.httpClient, apiURL)
+		if err != nil {
+			return fmt.Errorf("download patch: %w", err)
+		}
+		patches, err = base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+		if err != nil {
+			return fmt.Errorf("download patch: %w", err)
+		}
+	}
+	if len(bytes.TrimSpace(patches)) == 0 {
+		return errors.New("download patches: change has no patches")
+	}
+	if err := cc.git.NewBranch(ctx, branch, git.BranchOptions{Checkout: true}); err != nil {
+		return err
+	}
+	stderr := new(bytes.Buffer)
+	err := cc.git.Runner().RunGit(ctx, &git.Invocation{
+		Args:   []string{"am", "--3way", "--quiet"},
+		Dir:    cc.dir,
+		Stdin:  bytes.NewReader(patches),
+		Stdout: cc.stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		cc.stderr.Write(stderr.Bytes())
+		return fmt.Errorf("apply patches on %s: %w\nresolve the conflicts and run git am --continue, or git am --abort to give up", branch, err)
+	}
+	return nil
+}
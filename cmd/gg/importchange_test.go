@@ -0,0 +1,160 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestParseChangeURL(t *testing.T) {
+	tests := []struct {
+		url         string
+		wantKind    string
+		wantRepoURL string
+		wantRef     string
+		wantBranch  string
+	}{
+		{
+			url:         "https://github.com/example/foo/pull/123",
+			wantKind:    "github",
+			wantRepoURL: "https://github.com/example/foo.git",
+			wantRef:     "refs/pull/123/head",
+			wantBranch:  "pr/123",
+		},
+		{
+			url:         "https://github.com/example/foo/pull/123/files",
+			wantKind:    "github",
+			wantRepoURL: "https://github.com/example/foo.git",
+			wantRef:     "refs/pull/123/head",
+			wantBranch:  "pr/123",
+		},
+		{
+			url:         "https://go-review.googlesource.com/c/go/+/12345",
+			wantKind:    "gerrit",
+			wantRepoURL: "https://go-review.googlesource.com/go",
+			wantBranch:  "change/12345",
+		},
+		{
+			url:         "https://review.example.com/gerrit/c/tools/lint/+/7/3",
+			wantKind:    "gerrit",
+			wantRepoURL: "https://review.example.com/gerrit/tools/lint",
+			wantRef:     "refs/changes/07/7/3",
+			wantBranch:  "change/7",
+		},
+		{url: "https://github.com/example/foo/issues/1"},
+		{url: "https://example.com/foo"},
+		{url: "git@github.com:example/foo.git"},
+	}
+	for _, test := range tests {
+		got, err := parseChangeURL(test.url)
+		if err != nil {
+			if test.wantKind != "" {
+				t.Errorf("parseChangeURL(%q): %v", test.url, err)
+			}
+			continue
+		}
+		if test.wantKind == "" {
+			t.Errorf("parseChangeURL(%q) = %+v; want error", test.url, got)
+			continue
+		}
+		if got.kind != test.wantKind || got.repoURL != test.wantRepoURL || got.ref != test.wantRef || got.branch != test.wantBranch {
+			t.Errorf("parseChangeURL(%q) = {kind: %q, repoURL: %q, ref: %q, branch: %q}; want {kind: %q, repoURL: %q, ref: %q, branch: %q}",
+				test.url, got.kind, got.repoURL, got.ref, got.branch,
+				test.wantKind, test.wantRepoURL, test.wantRef, test.wantBranch)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "upstream"); err != nil {
+		t.Fatal(err)
+	}
+	upstreamGit := env.git.WithDir(env.root.FromSlash("upstream"))
+	if err := upstreamGit.NewBranch(ctx, "contrib", git.BranchOptions{Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("upstream/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "upstream/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	prCommit, err := env.newCommit(ctx, "upstream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := upstreamGit.Run(ctx, "update-ref", "refs/pull/7/head", prCommit.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := upstreamGit.CheckoutBranch(ctx, "main", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := upstreamGit.Run(ctx, "branch", "-D", "contrib"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "upstream", "local"); err != nil {
+		t.Fatal(err)
+	}
+	localDir := env.root.FromSlash("local")
+	localGit := env.git.WithDir(localDir)
+	const repoURL = "https://github.com/example/foo.git"
+	if err := localGit.Run(ctx, "remote", "set-url", "origin", repoURL); err != nil {
+		t.Fatal(err)
+	}
+	if err := localGit.Run(ctx, "config", "url."+env.root.FromSlash("upstream")+".insteadOf", repoURL); err != nil {
+		t.Fatal(err)
+	}
+
+	// The GitHub API is not reachable, so the branch tracks the pull request ref.
+	if _, err := env.gg(ctx, localDir, "import", "https://github.com/example/foo/pull/7"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := localGit.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Ref != git.BranchRef("pr/7") || head.Commit != prCommit {
+		t.Errorf("HEAD = %v (%v); want %v (%v)", head.Ref, head.Commit, git.BranchRef("pr/7"), prCommit)
+	}
+	cfg, err := localGit.ReadConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Value("branch.pr/7.remote"); got != "origin" {
+		t.Errorf("branch.pr/7.remote = %q; want \"origin\"", got)
+	}
+	if got := cfg.Value("branch.pr/7.merge"); got != "refs/pull/7/head" {
+		t.Errorf("branch.pr/7.merge = %q; want \"refs/pull/7/head\"", got)
+	}
+
+	if _, err := env.gg(ctx, localDir, "import", "https://github.com/example/foo/pull/7"); err == nil {
+		t.Error("importing the same pull request again succeeded")
+	} else if !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("importing the same pull request again: %v; want branch exists error", err)
+	}
+}
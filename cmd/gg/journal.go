@@ -0,0 +1,439 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/escape"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const journalSynopsis = "export the log of ref changes made by gg"
+
+// journalPath is the path of the journal relative to the common Git
+// directory.
+const journalPath = "gg/journal.jsonl"
+
+func journal(ctx context.Context, cc *cmdContext, args []string) error {
+	if len(args) == 0 || args[0] != "export" {
+		f := flag.NewFlagSet(true, "gg journal export [--since DATE] [--json]", journalSynopsis+`
+
+	If `+"`gg.journal`"+` is true, gg appends an entry to the repository's
+	journal after each command that changes a ref, recording when the
+	command ran, who ran it, the command line, and each ref's old and new
+	commit. The journal is shared by all worktrees of the repository.
+
+	Each entry holds the SHA-256 hash of the previous entry (`+"`prev`"+`)
+	and of its own JSON encoding with the `+"`hash`"+` field left out, so
+	editing or removing an entry breaks the chain.
+	`+"`gg journal export`"+` checks the chain and fails if it is broken.
+
+	`+"`gg journal export`"+` prints the entries at or after `+"`--since`"+`,
+	which is a date (`+"`2006-01-02`"+`) or an RFC 3339 time. `+"`--json`"+`
+	prints a JSON array of the entries as they are stored.`)
+		if err := f.Parse(args); flag.IsHelp(err) {
+			f.Help(cc.stdout)
+			return nil
+		} else if err != nil {
+			return usagef("%v", err)
+		}
+		if f.NArg() == 0 {
+			return usagef("missing subcommand")
+		}
+		return usagef("unknown subcommand %q", f.Arg(0))
+	}
+	return journalExport(ctx, cc, args[1:])
+}
+
+func journalExport(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg journal export [--since DATE] [--json]", "print the journal after checking its hash chain")
+	sinceArg := f.String("since", "", "only print entries at or after `date`")
+	jsonOutput := f.Bool("json", false, "print a JSON array of entries")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 0 {
+		return usagef("journal export takes no arguments")
+	}
+	var since time.Time
+	if *sinceArg != "" {
+		var err error
+		since, err = parseJournalDate(*sinceArg)
+		if err != nil {
+			return usagef("--since: %v", err)
+		}
+	}
+	commonDir, err := cc.git.CommonDir(ctx)
+	if err != nil {
+		return err
+	}
+	entries, err := readJournal(filepath.Join(commonDir, filepath.FromSlash(journalPath)))
+	if err != nil {
+		return err
+	}
+	if err := verifyJournal(entries); err != nil {
+		return err
+	}
+	n := 0
+	for _, ent := range entries {
+		if t, err := time.Parse(time.RFC3339, ent.Time); err == nil && !t.Before(since) {
+			entries[n] = ent
+			n++
+		}
+	}
+	entries = entries[:n]
+	if *jsonOutput {
+		if entries == nil {
+			entries = []*journalEntry{}
+		}
+		enc := json.NewEncoder(cc.stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(entries)
+	}
+	for _, ent := range entries {
+		if _, err := fmt.Fprintf(cc.stdout, "%d %s %s %s\n", ent.Seq, ent.Time, ent.User, ent.Command); err != nil {
+			return err
+		}
+		for _, c := range ent.Refs {
+			if _, err := fmt.Fprintf(cc.stdout, "\t%s %s -> %s\n", c.Ref, orNone(c.Old), orNone(c.New)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func orNone(hash string) string {
+	if hash == "" {
+		return "(none)"
+	}
+	return shortHash(hash)
+}
+
+// parseJournalDate parses the argument to gg journal export --since.
+func parseJournalDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date (2006-01-02) or RFC 3339 time", s)
+	}
+	return t, nil
+}
+
+// A journalEntry records a gg command that changed refs.
+type journalEntry struct {
+	Seq      int                 `json:"seq"`
+	Time     string              `json:"time"` // RFC 3339
+	User     string              `json:"user"` // operating system user
+	Host     string              `json:"host,omitempty"`
+	Identity string              `json:"identity,omitempty"` // Git user.name and user.email
+	Command  string              `json:"command"`
+	Refs     []*journalRefChange `json:"refs"`
+	Prev     string              `json:"prev"`
+	Hash     string              `json:"hash,omitempty"`
+}
+
+// A journalRefChange is a ref that a journaled command changed. Old or
+// New is empty if the ref was created or deleted.
+type journalRefChange struct {
+	Ref string `json:"ref"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// computeHash returns the hash of ent with its Hash field left out.
+func (ent *journalEntry) computeHash() (string, error) {
+	ent2 := *ent
+	ent2.Hash = ""
+	data, err := json.Marshal(&ent2)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// readJournal reads the entries in the journal file at path. A missing
+// file has no entries.
+func readJournal(path string) ([]*journalEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []*journalEntry
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 1<<24)
+	for line := 1; s.Scan(); line++ {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+		ent := new(journalEntry)
+		if err := json.Unmarshal(s.Bytes(), ent); err != nil {
+			return nil, fmt.Errorf("journal line %d: %w", line, err)
+		}
+		entries = append(entries, ent)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+	return entries, nil
+}
+
+// verifyJournal checks that the entries form an unbroken hash chain.
+func verifyJournal(entries []*journalEntry) error {
+	prev := ""
+	for i, ent := range entries {
+		if ent.Seq != i+1 {
+			return fmt.Errorf("journal entry %d has sequence number %d; entries were removed or reordered", i+1, ent.Seq)
+		}
+		if ent.Prev != prev {
+			return fmt.Errorf("journal entry %d does not follow entry %d; the journal has been modified", ent.Seq, ent.Seq-1)
+		}
+		hash, err := ent.computeHash()
+		if err != nil {
+			return err
+		}
+		if ent.Hash != hash {
+			return fmt.Errorf("journal entry %d does not match its hash; the journal has been modified", ent.Seq)
+		}
+		prev = ent.Hash
+	}
+	return nil
+}
+
+// runJournaled calls run, which runs the gg command with the given
+// name and arguments, and appends an entry to the journal if gg.journal
+// is set in cfg and the command changed any refs. cfg may be nil.
+func runJournaled(ctx context.Context, cc *cmdContext, cfg *git.Config, name string, args []string, run func() error) error {
+	if name == "journal" || !journalEnabled(cfg) {
+		return run()
+	}
+	commonDir, err := cc.git.CommonDir(ctx)
+	if err != nil {
+		// Not in a repository.
+		return run()
+	}
+	before, err := journalRefs(ctx, cc)
+	if err != nil {
+		return run()
+	}
+	runErr := run()
+	after, err := journalRefs(ctx, cc)
+	if err != nil {
+		fmt.Fprintf(cc.stderr, "gg: journal: %v\n", err)
+		return runErr
+	}
+	changes := diffJournalRefs(before, after)
+	if len(changes) == 0 {
+		return runErr
+	}
+	ent := &journalEntry{
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		Command: journalCommandLine(name, args),
+		Refs:    changes,
+	}
+	if u, err := user.Current(); err == nil {
+		ent.User = u.Username
+	}
+	ent.Host, _ = os.Hostname()
+	ent.Identity = strings.TrimSpace(cfg.Value("user.name") + " <" + cfg.Value("user.email") + ">")
+	if err := appendJournal(filepath.Join(commonDir, filepath.FromSlash(journalPath)), ent); err != nil {
+		fmt.Fprintf(cc.stderr, "gg: journal: %v\n", err)
+	}
+	return runErr
+}
+
+// journalCommandLine formats a gg command line for a journal entry,
+// quoting arguments like a shell would need them, since commands like
+// `gg git` pass arbitrary arguments through.
+func journalCommandLine(name string, args []string) string {
+	sb := new(strings.Builder)
+	sb.WriteString("gg ")
+	sb.WriteString(escape.Bash(name))
+	for _, arg := range args {
+		sb.WriteByte(' ')
+		sb.WriteString(escape.Bash(arg))
+	}
+	return sb.String()
+}
+
+// journalEnabled reports whether gg.journal is true in cfg, which may
+// be nil.
+func journalEnabled(cfg *git.Config) bool {
+	if cfg == nil || cfg.Value("gg.journal") == "" {
+		return false
+	}
+	enabled, err := cfg.Bool("gg.journal")
+	return err == nil && enabled
+}
+
+// journalRefs returns the commits that HEAD and every ref point to.
+func journalRefs(ctx context.Context, cc *cmdContext) (map[string]string, error) {
+	out, err := cc.git.Output(ctx, "for-each-ref", "--format=%(objectname) %(refname)")
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		hash, ref, ok := strings.Cut(line, " ")
+		if ok {
+			refs[ref] = hash
+		}
+	}
+	if head, err := cc.git.Head(ctx); err == nil {
+		refs["HEAD"] = head.Commit.String()
+	}
+	return refs, nil
+}
+
+// diffJournalRefs returns the refs that differ between before and after,
+// sorted by name.
+func diffJournalRefs(before, after map[string]string) []*journalRefChange {
+	var changes []*journalRefChange
+	for ref, old := range before {
+		if after[ref] != old {
+			changes = append(changes, &journalRefChange{Ref: ref, Old: old, New: after[ref]})
+		}
+	}
+	for ref, hash := range after {
+		if _, existed := before[ref]; !existed {
+			changes = append(changes, &journalRefChange{Ref: ref, New: hash})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Ref < changes[j].Ref })
+	return changes
+}
+
+// journalLockTimeout is how long appendJournal waits for another gg
+// process to finish appending to the journal.
+const journalLockTimeout = 10 * time.Second
+
+// appendJournal fills in ent's sequence number and hashes and appends it
+// to the journal file at path. It holds a lock on the journal while it
+// reads the last entry and appends, so that concurrent gg processes
+// don't break the hash chain.
+func appendJournal(path string, ent *journalEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return err
+	}
+	unlock, err := lockJournal(path, journalLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o666)
+	if err != nil {
+		return err
+	}
+	last, err := lastJournalEntry(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	ent.Seq = 1
+	ent.Prev = ""
+	if last != nil {
+		ent.Seq = last.Seq + 1
+		ent.Prev = last.Hash
+	}
+	ent.Hash, err = ent.computeHash()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	data, err := json.Marshal(ent)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// lockJournal creates the lock file for the journal at path, like Git's
+// ".lock" files, waiting up to timeout for another process to remove it.
+// The returned function removes the lock file.
+func lockJournal(path string, timeout time.Duration) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked (if no other gg is running, remove %s)", path, lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// lastJournalEntry returns the last entry in the journal file f or nil
+// if it is empty.
+func lastJournalEntry(f io.Reader) (*journalEntry, error) {
+	var last []byte
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 1<<24)
+	for s.Scan() {
+		if len(bytes.TrimSpace(s.Bytes())) > 0 {
+			last = append(last[:0], s.Bytes()...)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, nil
+	}
+	ent := new(journalEntry)
+	if err := json.Unmarshal(last, ent); err != nil {
+		return nil, fmt.Errorf("last entry: %w", err)
+	}
+	return ent, nil
+}
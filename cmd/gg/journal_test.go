@@ -0,0 +1,160 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "config", "gg.journal", "true"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.ParseRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "branch", "topic"); err != nil {
+		t.Fatal(err)
+	}
+	// Commands that don't change refs are not recorded.
+	if _, err := env.gg(ctx, env.root.String(), "status"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "Add foo"); err != nil {
+		t.Fatal(err)
+	}
+	newHead, err := env.git.ParseRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "journal", "export", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []*journalEntry
+	if err := json.Unmarshal(out, &entries); err != nil {
+		t.Fatal(err)
+	}
+	var got [][]*journalRefChange
+	for _, ent := range entries {
+		got = append(got, ent.Refs)
+	}
+	want := [][]*journalRefChange{
+		{{Ref: "refs/heads/topic", New: head.Commit.String()}},
+		{
+			{Ref: "HEAD", Old: head.Commit.String(), New: newHead.Commit.String()},
+			{Ref: "refs/heads/main", Old: head.Commit.String(), New: newHead.Commit.String()},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("journal ref changes (-want +got):\n%s", diff)
+	}
+	if len(entries) == 2 {
+		if !strings.HasPrefix(entries[1].Command, "gg commit") {
+			t.Errorf("entries[1].Command = %q; want gg commit", entries[1].Command)
+		}
+		if entries[1].Prev != entries[0].Hash {
+			t.Errorf("entries[1].Prev = %q; want %q", entries[1].Prev, entries[0].Hash)
+		}
+	}
+
+	tomorrow := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	out, err = env.gg(ctx, env.root.String(), "journal", "export", "--json", "--since", tomorrow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "[]" {
+		t.Errorf("gg journal export --since %s = %s; want []", tomorrow, got)
+	}
+
+	// Editing an entry breaks the chain.
+	gitDir, err := env.git.CommonDir(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(gitDir, filepath.FromSlash(journalPath))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), "gg branch topic", "gg branch other", 1))
+	if err := os.WriteFile(path, data, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "journal", "export"); err == nil {
+		t.Error("gg journal export of a modified journal did not return an error")
+	}
+}
+
+func TestAppendJournal_Concurrent(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "gg", "journal")
+	const n = 20
+	errc := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			errc <- appendJournal(path, &journalEntry{
+				Time:    time.Now().UTC().Format(time.RFC3339),
+				Command: "gg commit",
+			})
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errc; err != nil {
+			t.Error(err)
+		}
+	}
+	entries, err := readJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != n {
+		t.Errorf("journal has %d entries; want %d", len(entries), n)
+	}
+	if err := verifyJournal(entries); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind (err = %v)", err)
+	}
+}
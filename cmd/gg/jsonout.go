@@ -0,0 +1,65 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"gg-scm.io/pkg/git/object"
+)
+
+// writeJSON writes v to w as indented JSON followed by a newline. Commands
+// that support --json use it so that their output is formatted the same way.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(v)
+}
+
+// A commitJSON is the JSON representation of a commit
+// in the output of gg log --json, gg branch --json, and gg identify --json.
+type commitJSON struct {
+	Commit      string   `json:"commit"`
+	Parents     []string `json:"parents"`
+	Author      string   `json:"author"`
+	AuthorEmail string   `json:"authorEmail"`
+	Date        string   `json:"date"` // author date in RFC 3339 format
+	Summary     string   `json:"summary"`
+	Message     string   `json:"message,omitempty"`
+}
+
+// newCommitJSON returns the JSON representation of c.
+// The full message is included only if withMessage is true.
+func newCommitJSON(c *object.Commit, withMessage bool) *commitJSON {
+	j := &commitJSON{
+		Commit:      c.SHA1().String(),
+		Parents:     make([]string, 0, len(c.Parents)),
+		Author:      c.Author.Name(),
+		AuthorEmail: c.Author.Email(),
+		Date:        c.AuthorTime.Format(time.RFC3339),
+		Summary:     c.Summary(),
+	}
+	for _, p := range c.Parents {
+		j.Parents = append(j.Parents, p.String())
+	}
+	if withMessage {
+		j.Message = c.Message
+	}
+	return j
+}
@@ -0,0 +1,289 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/pkg/git/object"
+	"gg-scm.io/tool/internal/flag"
+	"gg-scm.io/tool/internal/sigterm"
+)
+
+const lintHistorySynopsis = "check the commits on a branch before review"
+
+func lintHistory(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg lint-history [-r REV] [--base REV] [--exec CMD]", lintHistorySynopsis+`
+
+	Checks the commits in REV (default HEAD) that are not in its upstream
+	(or the `+"`--base`"+` revision), oldest first, and prints a report of
+	the problems found. The command fails if there are any, so it can be
+	used in continuous integration as well as before `+"`gg mail`"+` or
+	`+"`gg requestpull`"+`.
+
+	The checks are:
+
+	- No merge commits.
+	- No `+"`fixup!`"+`, `+"`squash!`"+`, or `+"`amend!`"+` commits left to squash.
+	- The commit message has a summary line of at most
+	  `+"`gg.lint.maxSummaryLength`"+` characters (default 72, 0 for no
+	  limit) followed by a blank line. If `+"`gg.lint.summaryPattern`"+` is
+	  set, the summary must match the regular expression.
+	- The author and committer email addresses are the same.
+
+	Set `+"`gg.lint.merges`"+`, `+"`gg.lint.fixups`"+`, `+"`gg.lint.message`"+`, or
+	`+"`gg.lint.identity`"+` to false to turn off a check.
+
+	With `+"`--exec`"+`, the shell command is also run on each commit, checked
+	out in a temporary worktree, and the commit fails the check if the
+	command fails. This verifies that each commit builds on the one
+	before it, not just the last.`)
+	rev := f.String("r", git.Head.String(), "`rev`ision to check")
+	base := f.String("base", "", "check commits after this `rev`ision (default is the upstream)")
+	execCmd := f.String("exec", "", "shell `command` to run on each commit")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 0 {
+		return usagef("lint-history takes no arguments")
+	}
+	if strings.HasPrefix(*rev, "-") || strings.HasPrefix(*base, "-") {
+		return usagef("revision cannot start with '-'")
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	rules, err := readLintRules(cfg)
+	if err != nil {
+		return err
+	}
+	if *base == "" {
+		*base = *rev + "@{upstream}"
+		if _, err := cc.git.ParseRev(ctx, *base); err != nil {
+			return fmt.Errorf("%s has no upstream; pass --base", *rev)
+		}
+	}
+	commitLog, err := cc.git.Log(ctx, git.LogOptions{
+		Revs:    []string{"^" + *base, *rev},
+		Reverse: true,
+	})
+	if err != nil {
+		return err
+	}
+	var commits []*object.Commit
+	for commitLog.Next() {
+		commits = append(commits, commitLog.CommitInfo())
+	}
+	if err := commitLog.Close(); err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		_, err := fmt.Fprintf(cc.stdout, "no commits after %s\n", *base)
+		return err
+	}
+
+	report := make([][]string, len(commits))
+	for i, c := range commits {
+		report[i] = rules.check(c)
+	}
+	if *execCmd != "" {
+		if err := lintExec(ctx, cc, *execCmd, commits, report); err != nil {
+			return err
+		}
+	}
+	nproblems, ncommits := 0, 0
+	for i, c := range commits {
+		if len(report[i]) == 0 {
+			continue
+		}
+		ncommits++
+		nproblems += len(report[i])
+		fmt.Fprintf(cc.stdout, "%s %s\n", c.SHA1().Short(), c.Summary())
+		for _, p := range report[i] {
+			fmt.Fprintf(cc.stdout, "\t%s\n", p)
+		}
+	}
+	if nproblems > 0 {
+		return fmt.Errorf("found %s in %d of %d commits", countProblems(nproblems), ncommits, len(commits))
+	}
+	_, err = fmt.Fprintf(cc.stdout, "no problems in %s\n", countCommits(len(commits)))
+	return err
+}
+
+// lintRules is the set of checks run by gg lint-history, read from the
+// gg.lint.* configuration variables.
+type lintRules struct {
+	merges   bool
+	fixups   bool
+	message  bool
+	identity bool
+
+	maxSummaryLength int
+	summaryPattern   *regexp.Regexp
+}
+
+func readLintRules(cfg *git.Config) (*lintRules, error) {
+	rules := &lintRules{
+		merges:           true,
+		fixups:           true,
+		message:          true,
+		identity:         true,
+		maxSummaryLength: 72,
+	}
+	for _, b := range []struct {
+		name string
+		ptr  *bool
+	}{
+		{"gg.lint.merges", &rules.merges},
+		{"gg.lint.fixups", &rules.fixups},
+		{"gg.lint.message", &rules.message},
+		{"gg.lint.identity", &rules.identity},
+	} {
+		if cfg.Value(b.name) == "" {
+			continue
+		}
+		var err error
+		*b.ptr, err = cfg.Bool(b.name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v := cfg.Value("gg.lint.maxSummaryLength"); v != "" {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("gg.lint.maxSummaryLength: invalid length %q", v)
+		}
+		rules.maxSummaryLength = n
+	}
+	if v := cfg.Value("gg.lint.summaryPattern"); v != "" {
+		var err error
+		rules.summaryPattern, err = regexp.Compile(v)
+		if err != nil {
+			return nil, fmt.Errorf("gg.lint.summaryPattern: %w", err)
+		}
+	}
+	return rules, nil
+}
+
+// check returns the problems with c.
+func (rules *lintRules) check(c *object.Commit) []string {
+	var problems []string
+	if rules.merges && len(c.Parents) > 1 {
+		problems = append(problems, "merge commit")
+	}
+	summary := c.Summary()
+	if rules.fixups {
+		for _, prefix := range []string{"fixup!", "squash!", "amend!"} {
+			if strings.HasPrefix(summary, prefix) {
+				problems = append(problems, prefix+" commit has not been squashed")
+				break
+			}
+		}
+	}
+	if rules.message {
+		problems = append(problems, rules.checkMessage(c.Message)...)
+	}
+	if rules.identity && !strings.EqualFold(c.Author.Email(), c.Committer.Email()) {
+		problems = append(problems, fmt.Sprintf("author <%s> does not match committer <%s>", c.Author.Email(), c.Committer.Email()))
+	}
+	return problems
+}
+
+func (rules *lintRules) checkMessage(msg string) []string {
+	lines := strings.Split(strings.TrimRight(msg, "\n"), "\n")
+	summary := strings.TrimSpace(lines[0])
+	if summary == "" {
+		return []string{"commit message has no summary line"}
+	}
+	var problems []string
+	if n := utf8.RuneCountInString(summary); rules.maxSummaryLength > 0 && n > rules.maxSummaryLength {
+		problems = append(problems, fmt.Sprintf("summary is %d characters long (limit is %d)", n, rules.maxSummaryLength))
+	}
+	if rules.summaryPattern != nil && !rules.summaryPattern.MatchString(summary) {
+		problems = append(problems, fmt.Sprintf("summary does not match gg.lint.summaryPattern %q", rules.summaryPattern))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		problems = append(problems, "summary is not followed by a blank line")
+	}
+	return problems
+}
+
+// lintExec runs the shell command line on each of commits in a temporary
+// worktree, adding a problem to report for each commit where it fails.
+func lintExec(ctx context.Context, cc *cmdContext, line string, commits []*object.Commit, report [][]string) (err error) {
+	dir, err := os.MkdirTemp(cc.tempDir, "gg-lint-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	worktree := filepath.Join(dir, "worktree")
+	if err := cc.git.Run(ctx, "worktree", "add", "--detach", "--quiet", worktree, commits[0].SHA1().String()); err != nil {
+		return err
+	}
+	defer func() {
+		if rmErr := cc.git.Run(ctx, "worktree", "remove", "--force", worktree); rmErr != nil && err == nil {
+			err = rmErr
+		}
+	}()
+	wtGit := cc.git.WithDir(worktree)
+	for i, c := range commits {
+		if err := wtGit.Run(ctx, "checkout", "--detach", "--quiet", c.SHA1().String()); err != nil {
+			return err
+		}
+		fmt.Fprintf(cc.stderr, "gg: lint-history: running command on %s\n", c.SHA1().Short())
+		cmd, err := bashCommand(cc.git.Exe(), line)
+		if err != nil {
+			return err
+		}
+		cmd.Dir = worktree
+		cmd.Env = cc.env
+		if len(cmd.Env) == 0 {
+			cmd.Env = []string{} // force empty
+		}
+		cmd.Stdout = cc.stderr
+		cmd.Stderr = cc.stderr
+		if err := sigterm.Run(ctx, cmd); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				return err
+			}
+			report[i] = append(report[i], fmt.Sprintf("command failed: %v", err))
+		}
+	}
+	return nil
+}
+
+func countProblems(n int) string {
+	if n == 1 {
+		return "1 problem"
+	}
+	return strconv.Itoa(n) + " problems"
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/pkg/git/object"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLintRules(t *testing.T) {
+	defaultRules := &lintRules{
+		merges:           true,
+		fixups:           true,
+		message:          true,
+		identity:         true,
+		maxSummaryLength: 72,
+	}
+	const me = object.User("Octocat <octocat@example.com>")
+	tests := []struct {
+		name   string
+		rules  *lintRules
+		commit *object.Commit
+		want   []string
+	}{
+		{
+			name:   "Good",
+			rules:  defaultRules,
+			commit: &object.Commit{Author: me, Committer: me, Message: "Add a feature\n\nDetails.\n"},
+		},
+		{
+			name:  "Merge",
+			rules: defaultRules,
+			commit: &object.Commit{
+				Parents:   []git.Hash{{1}, {2}},
+				Author:    me,
+				Committer: me,
+				Message:   "Merge branch 'main'\n",
+			},
+			want: []string{"merge commit"},
+		},
+		{
+			name:   "Fixup",
+			rules:  defaultRules,
+			commit: &object.Commit{Author: me, Committer: me, Message: "fixup! Add a feature\n"},
+			want:   []string{"fixup! commit has not been squashed"},
+		},
+		{
+			name:   "LongSummary",
+			rules:  defaultRules,
+			commit: &object.Commit{Author: me, Committer: me, Message: strings.Repeat("x", 80) + "\n"},
+			want:   []string{"summary is 80 characters long (limit is 72)"},
+		},
+		{
+			name:   "NoBlankLine",
+			rules:  defaultRules,
+			commit: &object.Commit{Author: me, Committer: me, Message: "Add a feature\nDetails.\n"},
+			want:   []string{"summary is not followed by a blank line"},
+		},
+		{
+			name: "SummaryPattern",
+			rules: &lintRules{
+				message:        true,
+				summaryPattern: regexp.MustCompile(`^[a-z/]+: `),
+			},
+			commit: &object.Commit{Author: me, Committer: me, Message: "Add a feature\n"},
+			want:   []string{"summary does not match gg.lint.summaryPattern \"^[a-z/]+: \""},
+		},
+		{
+			name:  "IdentityMismatch",
+			rules: defaultRules,
+			commit: &object.Commit{
+				Author:    me,
+				Committer: "Octocat <octocat@work.example.com>",
+				Message:   "Add a feature\n",
+			},
+			want: []string{"author <octocat@example.com> does not match committer <octocat@work.example.com>"},
+		},
+		{
+			name:  "IdentityOff",
+			rules: &lintRules{message: true},
+			commit: &object.Commit{
+				Author:    me,
+				Committer: "Octocat <octocat@work.example.com>",
+				Message:   "Add a feature\n",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.rules.check(test.commit)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("problems (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLintHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "topic", git.BranchOptions{Checkout: true, StartPoint: "main", Track: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "Add foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "lint-history")
+	if err != nil {
+		t.Fatalf("gg lint-history: %v; output:\n%s", err, out)
+	}
+	if got, want := string(out), "no problems in 1 commit\n"; got != want {
+		t.Errorf("gg lint-history = %q; want %q", got, want)
+	}
+
+	if err := env.root.Apply(filesystem.Write("foo.txt", "fixed\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "fixup! Add foo"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, env.root.String(), "lint-history")
+	if err == nil {
+		t.Error("gg lint-history with a fixup! commit did not return an error")
+	}
+	if got, want := string(out), "fixup! commit has not been squashed"; !strings.Contains(got, want) {
+		t.Errorf("gg lint-history = %q; want to contain %q", got, want)
+	}
+}
+
+func TestLintHistory_Exec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("command uses a POSIX shell")
+	}
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	base, err := env.git.ParseRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first commit breaks the "build" and the second fixes it.
+	if err := env.root.Apply(filesystem.Write("broken.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "broken.txt"); err != nil {
+		t.Fatal(err)
+	}
+	first, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "rm", "broken.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(),
+		"lint-history", "--base", base.Commit.String(), "--exec", "test ! -e broken.txt")
+	if err == nil {
+		t.Error("gg lint-history did not return an error")
+	}
+	if got, want := string(out), first.Short()+" "; !strings.HasPrefix(got, want) {
+		t.Errorf("gg lint-history =\n%s\nwant report to start with %q", got, want)
+	}
+	if got, want := strings.Count(string(out), "command failed"), 1; got != want {
+		t.Errorf("gg lint-history reported %d command failures; want %d. Output:\n%s", got, want, out)
+	}
+	if wt, err := env.git.Output(ctx, "worktree", "list"); err != nil {
+		t.Error(err)
+	} else if n := strings.Count(wt, "\n"); n != 1 {
+		t.Errorf("git worktree list =\n%s\nwant temporary worktree removed", wt)
+	}
+}
@@ -16,42 +16,112 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"strings"
+	"time"
 
+	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
+	"gg-scm.io/tool/internal/graph"
+	"gg-scm.io/tool/internal/template"
+	"gg-scm.io/tool/internal/terminal"
 )
 
 const logSynopsis = "show revision history of entire repository or files"
 
 func log(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg log [OPTION [...]] [FILE]", logSynopsis+`
+	f := flag.NewFlagSet(true, "gg log [OPTION [...]] [FILE [...]]", logSynopsis+`
 
-aliases: history`)
+aliases: history
+
+	If the terminal supports hyperlinks and the repository is hosted on
+	GitHub or GitLab, commit hashes, file names, and pull request
+	references like #123 link to their pages on the forge. Set the
+	`+"`gg.hyperlinks`"+` configuration setting to always or never to
+	override the detection.
+
+	`+"`--grep`"+`, `+"`--author`"+`, `+"`-S`"+`, and `+"`--grep-diff`"+` limit the log to
+	matching commits and can be combined with revisions and files. A
+	commit must match every kind of filter given, but only one of the
+	patterns given for a repeated `+"`--grep`"+` or `+"`--author`"+`. `+"`-S`"+` finds
+	commits that add or remove occurrences of a string, like when a
+	function was introduced or deleted, while `+"`--grep-diff`"+` finds
+	commits with any added or removed line matching a regular expression.
+	(`+"`-G`"+` is short for `+"`--graph`"+`, as in Mercurial, not Git's `+"`-G`"+`.)
+
+	`+"`--graph`"+` draws the commits as a graph with one line per commit,
+	showing each commit's short hash, branches and tags, and summary. The
+	working copy's parent is marked with `+"`@`"+`. With `+"`--stat`"+`, Git's
+	`+"`--graph`"+` output is shown instead.
+
+	With `+"`--json`"+`, the commits are printed as a JSON array of objects
+	with the commit hash, parent hashes, author, author date, summary,
+	and full message. `+"`--json`"+` cannot be combined with `+"`--graph`"+` or
+	`+"`--stat`"+`.
+
+	`+"`-T`"+` prints each commit with a Mercurial-style template, like
+	`+"`-T '{node|short} {desc|firstline}\\n'`"+`. The keywords are `+"`node`"+`,
+	`+"`parents`"+`, `+"`author`"+`, `+"`date`"+`, and `+"`desc`"+`.`+templateHelp+autoFetchHelp+notesHelp)
 	follow := f.Bool("follow", false, "follow file history across copies and renames")
 	followFirst := f.Bool("follow-first", false, "only follow the first parent of merge commits")
-	graph := f.Bool("graph", false, "show the revision DAG")
+	showGraph := f.Bool("graph", false, "show the revision DAG")
 	f.Alias("graph", "G")
 	rev := f.MultiString("r", "show the specified `rev`ision or range")
 	reverse := f.Bool("reverse", false, "reverse order of commits")
 	stat := f.Bool("stat", false, "include diffstat-style summary of each commit")
+	grep := f.MultiString("grep", "show only commits with a message matching the `regex`")
+	author := f.MultiString("author", "show only commits with an author matching the `regex`")
+	pickaxe := f.String("S", "", "show only commits that change the number of occurrences of `string`")
+	grepDiff := f.String("grep-diff", "", "show only commits that add or remove a line matching the `regex`")
+	jsonOutput := f.Bool("json", false, "print a JSON array of commits")
+	tmplString := f.String("template", "", "print each commit with a `template`")
+	f.Alias("template", "T")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
-	if f.NArg() > 1 {
-		return usagef("only one file allowed")
+	if *follow && f.NArg() > 1 {
+		return usagef("only one file allowed with --follow")
+	}
+	if *pickaxe != "" && *grepDiff != "" {
+		return usagef("cannot pass both -S and --grep-diff")
+	}
+	if *jsonOutput && (*showGraph || *stat) {
+		return usagef("cannot pass --graph or --stat with --json")
+	}
+	if *showGraph && *reverse {
+		return usagef("cannot pass both --graph and --reverse")
+	}
+	var tmpl *template.Template
+	if f.IsSet("template") {
+		if *jsonOutput || *showGraph || *stat {
+			return usagef("cannot pass --graph, --stat, or --json with --template")
+		}
+		var err error
+		tmpl, err = parseTemplate(*tmplString, logTemplateKeywords)
+		if err != nil {
+			return err
+		}
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
 	}
 	var logArgs []string
 	logArgs = append(logArgs, "log", "--decorate=auto", "--date-order")
+	if cfg.Value("gg.notes.ref") != "" {
+		logArgs = append(logArgs, "--notes="+notesRef(cfg))
+	}
 	if *follow {
 		logArgs = append(logArgs, "--follow")
 	}
 	if *followFirst {
 		logArgs = append(logArgs, "--first-parent")
 	}
-	if *graph {
+	if *showGraph && *stat {
 		logArgs = append(logArgs, "--graph")
 	}
 	if *reverse {
@@ -60,17 +130,318 @@ aliases: history`)
 	if *stat {
 		logArgs = append(logArgs, "--stat")
 	}
+	for _, pattern := range *grep {
+		logArgs = append(logArgs, "--grep="+pattern)
+	}
+	for _, pattern := range *author {
+		logArgs = append(logArgs, "--author="+pattern)
+	}
+	if *pickaxe != "" {
+		logArgs = append(logArgs, "-S"+*pickaxe)
+	}
+	if *grepDiff != "" {
+		logArgs = append(logArgs, "-G"+*grepDiff)
+	}
 	for _, r := range *rev {
 		if strings.HasPrefix(r, "-") {
 			return usagef("revisions must not start with '-'")
 		}
 	}
+	if err := fetchMissingRevs(ctx, cc, *rev...); err != nil {
+		return err
+	}
 	if len(*rev) == 0 {
+		// git log --all prints nothing in a repository without commits,
+		// which is easy to mistake for a broken command.
+		if empty, err := hasNoCommits(ctx, cc.git); err != nil {
+			return err
+		} else if empty && *jsonOutput {
+			return writeJSON(cc.stdout, []*commitJSON{})
+		} else if empty && tmpl != nil {
+			return nil
+		} else if empty {
+			_, err := fmt.Fprintln(cc.stdout, "no commits yet")
+			return err
+		}
 		logArgs = append(logArgs, "--all")
 	} else {
 		logArgs = append(logArgs, *rev...)
 	}
 	logArgs = append(logArgs, "--")
 	logArgs = append(logArgs, f.Args()...)
-	return cc.interactiveGit(ctx, logArgs...)
+	if *jsonOutput || tmpl != nil {
+		logArgs = append([]string{"log", "--format=" + logJSONFormat}, logArgs[1:]...)
+		out, err := cc.git.Output(ctx, logArgs...)
+		if err != nil {
+			return err
+		}
+		commits, err := parseLogJSON(out)
+		if err != nil {
+			return err
+		}
+		if tmpl == nil {
+			return writeJSON(cc.stdout, commits)
+		}
+		return writeLogTemplate(cc.stdout, tmpl, commits)
+	}
+	if *showGraph && !*stat {
+		// Draw the graph as git log prints each commit, through the same
+		// pager as the plain log.
+		colorize, err := cfg.ColorBool("color.diff", terminal.IsTerminal(cc.stdout))
+		if err != nil {
+			return err
+		}
+		grapher := &logGrapher{g: new(graph.Graph)}
+		if colorize {
+			grapher.colors, err = readLogGraphColors(cfg)
+			if err != nil {
+				return err
+			}
+		}
+		// --parents rewrites the parents to the commits shown when the
+		// log is limited to files, so the graph stays connected.
+		// --decorate=full lets the refs be colored by their kind.
+		logArgs = append([]string{"log", "--parents", "--decorate=full", "--format=" + logGraphFormat}, logArgs[2:]...)
+		err = cc.filteredGit(ctx, grapher.line, logArgs...)
+		if grapher.err != nil {
+			return grapher.err
+		}
+		return err
+	}
+
+	links, err := useHyperlinks(cc, cfg)
+	if err != nil {
+		return err
+	}
+	var fg *forge
+	if links {
+		headRef, err := cc.git.HeadRef(ctx)
+		if err != nil {
+			return err
+		}
+		fg = forgeForRepository(cfg, headRef.Branch())
+	}
+	if fg == nil {
+		return cc.interactiveGit(ctx, logArgs...)
+	}
+	// Git's output will be piped through gg, so make decisions that
+	// depend on the terminal here.
+	isTerm := terminal.IsTerminal(cc.stdout)
+	if isTerm {
+		logArgs[1] = "--decorate=short"
+	}
+	colorize, err := cfg.ColorBool("color.diff", isTerm)
+	if err != nil {
+		return err
+	}
+	if colorize {
+		logArgs = append([]string{"log", "--color=always"}, logArgs[1:]...)
+	}
+	linker := &logLinker{forge: fg}
+	return cc.filteredGit(ctx, linker.line, logArgs...)
+}
+
+// logJSONFormat is the git log --format for parseLogJSON. Each commit
+// starts with a \x01 byte and its fields are separated by NUL bytes.
+const logJSONFormat = "%x01%H%x00%P%x00%an%x00%ae%x00%aI%x00%B"
+
+// parseLogJSON parses the output of git log --format=logJSONFormat.
+func parseLogJSON(out string) ([]*commitJSON, error) {
+	commits := []*commitJSON{}
+	for _, rec := range strings.Split(out, "\x01")[1:] {
+		fields := strings.SplitN(rec, "\x00", 6)
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("parse log: unexpected record %q", rec)
+		}
+		date, err := time.Parse(time.RFC3339, fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("parse log: commit %s: %w", fields[0], err)
+		}
+		// git log ends each record with a newline after the message.
+		msg := strings.TrimSuffix(fields[5], "\n")
+		summary, _, _ := strings.Cut(msg, "\n")
+		c := &commitJSON{
+			Commit:      fields[0],
+			Parents:     strings.Fields(fields[1]),
+			Author:      fields[2],
+			AuthorEmail: fields[3],
+			Date:        date.Format(time.RFC3339),
+			Summary:     summary,
+			Message:     msg,
+		}
+		if c.Parents == nil {
+			c.Parents = []string{}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// logGraphFormat is the git log --format for parseLogGraph. Each commit
+// is a line that starts with a \x01 byte and its fields are separated by
+// NUL bytes. The refs are expected to be decorated with --decorate=full.
+const logGraphFormat = "%x01%H%x00%h%x00%P%x00%D%x00%s"
+
+// logGrapher draws the lines of git log --format=logGraphFormat as a
+// graph.
+type logGrapher struct {
+	g      *graph.Graph
+	colors *logGraphColors // nil for no color
+	err    error           // first parse error
+}
+
+// line is a filter for cmdContext.filteredGit that returns the graph
+// lines for a commit.
+func (lg *logGrapher) line(line string) string {
+	n, err := parseLogGraph(line, lg.colors)
+	if err != nil {
+		if lg.err == nil {
+			lg.err = err
+		}
+		return line
+	}
+	return strings.Join(lg.g.Add(n), "\n")
+}
+
+// logGraphColors holds the escape sequences for the parts of a graph
+// node's text.
+type logGraphColors struct {
+	commit       []byte
+	branch       []byte
+	remoteBranch []byte
+	tag          []byte
+}
+
+// readLogGraphColors reads the colors that git log uses for commit
+// hashes and decorations.
+func readLogGraphColors(cfg *git.Config) (*logGraphColors, error) {
+	colors := new(logGraphColors)
+	var err error
+	colors.commit, err = cfg.Color("color.diff.commit", "yellow")
+	if err != nil {
+		return nil, err
+	}
+	colors.branch, err = cfg.Color("color.decorate.branch", "bold green")
+	if err != nil {
+		return nil, err
+	}
+	colors.remoteBranch, err = cfg.Color("color.decorate.remoteBranch", "bold red")
+	if err != nil {
+		return nil, err
+	}
+	colors.tag, err = cfg.Color("color.decorate.tag", "bold yellow")
+	if err != nil {
+		return nil, err
+	}
+	return colors, nil
+}
+
+// parseLogGraph parses a line of git log --format=logGraphFormat into a
+// graph node labeled with its short hash, refs, and summary. colors may
+// be nil for no color.
+func parseLogGraph(line string, colors *logGraphColors) (*graph.Node, error) {
+	fields := strings.SplitN(strings.TrimPrefix(line, "\x01"), "\x00", 5)
+	if !strings.HasPrefix(line, "\x01") || len(fields) != 5 {
+		return nil, fmt.Errorf("parse log: unexpected record %q", line)
+	}
+	n := &graph.Node{ID: fields[0], Parents: strings.Fields(fields[2])}
+	var refs []string
+	for _, ref := range strings.Split(fields[3], ", ") {
+		if ref == "HEAD" {
+			n.Glyph = '@'
+			continue
+		}
+		if branch, ok := strings.CutPrefix(ref, "HEAD -> "); ok {
+			n.Glyph = '@'
+			ref = branch
+		}
+		if ref != "" {
+			refs = append(refs, colorLogGraphRef(ref, colors))
+		}
+	}
+	sb := new(strings.Builder)
+	if colors != nil {
+		sb.Write(colors.commit)
+		sb.WriteString(fields[1])
+		sb.WriteString(resetColor)
+	} else {
+		sb.WriteString(fields[1])
+	}
+	if len(refs) > 0 {
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(refs, ", "))
+		sb.WriteString(")")
+	}
+	sb.WriteString(" ")
+	sb.WriteString(fields[4])
+	n.Text = sb.String()
+	return n, nil
+}
+
+// resetColor is the escape sequence that resets the text style.
+const resetColor = "\x1b[m"
+
+// colorLogGraphRef shortens a ref decorated by --decorate=full and
+// colors it by its kind.
+func colorLogGraphRef(ref string, colors *logGraphColors) string {
+	prefix := ""
+	if tag, ok := strings.CutPrefix(ref, "tag: "); ok {
+		prefix = "tag: "
+		ref = tag
+	}
+	var color []byte
+	if short, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+		ref = short
+		if colors != nil {
+			color = colors.branch
+		}
+	} else if short, ok := strings.CutPrefix(ref, "refs/remotes/"); ok {
+		ref = short
+		if colors != nil {
+			color = colors.remoteBranch
+		}
+	} else if short, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+		ref = short
+		if colors != nil {
+			color = colors.tag
+		}
+	}
+	if len(color) == 0 {
+		return prefix + ref
+	}
+	return string(color) + prefix + ref + resetColor
+}
+
+// logTemplateKeywords is the list of keywords available to gg log -T.
+var logTemplateKeywords = []string{"node", "parents", "author", "date", "desc"}
+
+// writeLogTemplate executes tmpl for each commit.
+func writeLogTemplate(w io.Writer, tmpl *template.Template, commits []*commitJSON) error {
+	for _, c := range commits {
+		date, err := time.Parse(time.RFC3339, c.Date)
+		if err != nil {
+			return fmt.Errorf("commit %s: %w", c.Commit, err)
+		}
+		err = tmpl.Execute(w, template.Keywords{
+			"node":    c.Commit,
+			"parents": c.Parents,
+			"author":  fmt.Sprintf("%s <%s>", c.Author, c.AuthorEmail),
+			"date":    date,
+			"desc":    strings.TrimSpace(c.Message),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasNoCommits reports whether no ref in the repository points to a
+// commit, as in a freshly initialized repository.
+func hasNoCommits(ctx context.Context, g *git.Git) (bool, error) {
+	out, err := g.Output(ctx, "rev-list", "--all", "--max-count=1")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) == "", nil
 }
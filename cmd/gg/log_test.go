@@ -17,10 +17,16 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
+	"gg-scm.io/pkg/git/object"
 	"gg-scm.io/tool/internal/filesystem"
+	"gg-scm.io/tool/internal/graph"
+	"gg-scm.io/tool/internal/terminal"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestLog(t *testing.T) {
@@ -57,3 +63,317 @@ func TestLog(t *testing.T) {
 		t.Errorf("log does not contain either %q or %q. Output:\n%s", hex, wantMsg, out)
 	}
 }
+
+func TestLog_Hyperlinks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "remote", "add", "origin", "https://github.com/example/foo.git"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "config", "gg.hyperlinks", "always"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Commit(ctx, "Add foo (#42)", git.CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	rev, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "log", "--stat")
+	if err != nil {
+		t.Error(err)
+	}
+	hash := rev.Commit.String()
+	wantLinks := []string{
+		terminal.Hyperlink("https://github.com/example/foo/commit/"+hash, hash),
+		terminal.Hyperlink("https://github.com/example/foo/pull/42", "#42"),
+		terminal.Hyperlink("https://github.com/example/foo/blob/"+hash+"/foo.txt", "foo.txt"),
+	}
+	for _, link := range wantLinks {
+		if !bytes.Contains(out, []byte(link)) {
+			t.Errorf("log does not contain %q. Output:\n%s", link, out)
+		}
+	}
+
+	// Hyperlinks can be turned off.
+	if err := env.git.Run(ctx, "config", "gg.hyperlinks", "never"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, env.root.String(), "log")
+	if err != nil {
+		t.Error(err)
+	}
+	if bytes.Contains(out, []byte("\x1b]8;")) {
+		t.Errorf("log contains hyperlinks with gg.hyperlinks = never. Output:\n%q", out)
+	}
+}
+
+func TestLog_Search(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	commits := []struct {
+		file    string
+		content string
+		msg     string
+		author  object.User
+	}{
+		{"foo.txt", "func parseWidget() {}\n", "Add widget parser", "Alice <alice@example.com>"},
+		{"bar.txt", "unrelated\n", "Fix typo in docs", "Bob <bob@example.com>"},
+		{"foo.txt", "func parseGadget() {}\n", "Rename parser", "Bob <bob@example.com>"},
+	}
+	for _, c := range commits {
+		if err := env.root.Apply(filesystem.Write(c.file, c.content)); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, c.file); err != nil {
+			t.Fatal(err)
+		}
+		err := env.git.Commit(ctx, c.msg, git.CommitOptions{
+			Author:    c.author,
+			Committer: c.author,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{
+			args: []string{"--grep", "parser"},
+			want: []string{"Add widget parser", "Rename parser"},
+		},
+		{
+			args: []string{"--author", "bob", "--grep", "parser"},
+			want: []string{"Rename parser"},
+		},
+		{
+			args: []string{"-S", "parseWidget"},
+			want: []string{"Add widget parser", "Rename parser"},
+		},
+		{
+			args: []string{"--grep-diff", "^unrelated$"},
+			want: []string{"Fix typo in docs"},
+		},
+		{
+			args: []string{"--author", "alice", "foo.txt", "bar.txt"},
+			want: []string{"Add widget parser"},
+		},
+	}
+	for _, test := range tests {
+		args := append([]string{"log"}, test.args...)
+		out, err := env.gg(ctx, env.root.String(), args...)
+		if err != nil {
+			t.Errorf("gg %q: %v", args, err)
+			continue
+		}
+		for _, c := range commits {
+			want := false
+			for _, msg := range test.want {
+				want = want || msg == c.msg
+			}
+			if got := bytes.Contains(out, []byte(c.msg)); got != want {
+				t.Errorf("gg %q output contains %q = %t; want %t. Output:\n%s", args, c.msg, got, want, out)
+			}
+		}
+	}
+}
+
+func TestLog_JSON(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "log", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*commitJSON
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	if len(got) != 2 {
+		t.Fatalf("gg log --json returned %d commits; want 2. Output:\n%s", len(got), out)
+	}
+	if got[0].Commit != head.Commit.String() {
+		t.Errorf("commit[0] = %s; want %v", got[0].Commit, head.Commit)
+	}
+	if want := "removed dummy file"; got[0].Summary != want {
+		t.Errorf("commit[0].summary = %q; want %q", got[0].Summary, want)
+	}
+	if len(got[0].Parents) != 1 || got[0].Parents[0] != got[1].Commit {
+		t.Errorf("commit[0].parents = %q; want [%q]", got[0].Parents, got[1].Commit)
+	}
+	if want := "initial import\n"; got[1].Message != want {
+		t.Errorf("commit[1].message = %q; want %q", got[1].Message, want)
+	}
+	if len(got[1].Parents) != 0 {
+		t.Errorf("commit[1].parents = %q; want []", got[1].Parents)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "log", "--json", "--graph"); err == nil {
+		t.Error("gg log --json --graph did not return an error")
+	} else if !isUsage(err) {
+		t.Errorf("gg log --json --graph returned non-usage error: %v", err)
+	}
+}
+
+func TestLog_Graph(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	abbrev, err := env.git.Output(ctx, "log", "--format=%h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes := strings.Fields(abbrev)
+	if len(hashes) != 2 {
+		t.Fatalf("git log returned %d commits; want 2", len(hashes))
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "log", "--graph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "@  " + hashes[0] + " (main) removed dummy file\n" +
+		"o  " + hashes[1] + " initial import\n"
+	if got := string(out); got != want {
+		t.Errorf("gg log --graph output:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "log", "--graph", "--reverse"); err == nil {
+		t.Error("gg log --graph --reverse did not return an error")
+	} else if !isUsage(err) {
+		t.Errorf("gg log --graph --reverse returned non-usage error: %v", err)
+	}
+}
+
+func TestParseLogGraph(t *testing.T) {
+	const line = "\x01abc123\x00abc\x00def456\x00HEAD -> refs/heads/main, tag: refs/tags/v1, refs/remotes/origin/main\x00Fix the thing"
+	n, err := parseLogGraph(line, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &graph.Node{
+		ID:      "abc123",
+		Parents: []string{"def456"},
+		Glyph:   '@',
+		Text:    "abc (main, tag: v1, origin/main) Fix the thing",
+	}
+	if diff := cmp.Diff(want, n); diff != "" {
+		t.Errorf("parseLogGraph(%q, nil) (-want +got):\n%s", line, diff)
+	}
+
+	colors := &logGraphColors{
+		commit:       []byte("<commit>"),
+		branch:       []byte("<branch>"),
+		remoteBranch: []byte("<remote>"),
+		tag:          []byte("<tag>"),
+	}
+	n, err = parseLogGraph(line, colors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantText := "<commit>abc" + resetColor + " (" +
+		"<branch>main" + resetColor + ", " +
+		"<tag>tag: v1" + resetColor + ", " +
+		"<remote>origin/main" + resetColor + ") Fix the thing"
+	if n.Text != wantText {
+		t.Errorf("parseLogGraph(%q, colors).Text = %q; want %q", line, n.Text, wantText)
+	}
+
+	if _, err := parseLogGraph("not a record", nil); err == nil {
+		t.Error("parseLogGraph(\"not a record\", nil) did not return an error")
+	}
+}
+
+func TestLog_Template(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := env.git.ParseRev(ctx, "HEAD~")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "log", "-T", `{node|short} {desc|firstline}\n`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := head.Commit.String()[:12] + " removed dummy file\n" +
+		first.Commit.String()[:12] + " initial import\n"
+	if got := string(out); got != want {
+		t.Errorf("gg log -T output:\n%s\nwant:\n%s", got, want)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "log", "-r", "HEAD", "--template", "{parents}|{author|email}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), first.Commit.String()+"|foo@example.com"; got != want {
+		t.Errorf("gg log --template output = %q; want %q", got, want)
+	}
+
+	for _, args := range [][]string{
+		{"log", "-T", "{node}", "--json"},
+		{"log", "-T", "{node}", "--graph"},
+		{"log", "-T", "{bogus}"},
+		{"log", "-T", "{node|bogus}"},
+	} {
+		if _, err := env.gg(ctx, env.root.String(), args...); err == nil {
+			t.Errorf("gg %q did not return an error", args)
+		} else if !isUsage(err) {
+			t.Errorf("gg %q returned non-usage error: %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMail(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gerrit, err := newFakeGerrit(ctx, t, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoPath, err := gerrit.cloneWithHistory(ctx, env, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repo/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repo/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	commit, err := env.newCommit(ctx, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, repoPath, "mail",
+		"-R", "a@a.com,c@r.com",
+		"--cc", "b@o.com",
+		"-m", "PTAL, thanks!",
+		"--notify", "owner_reviewers",
+		"--notify-to", "d@zombo.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []fakeGerritChange{{
+		base:   "refs/for/main",
+		commit: commit,
+		opts: map[string][]string{
+			"no-publish-comments": nil,
+			"r":                   {"a@a.com", "c@r.com"},
+			"cc":                  {"b@o.com"},
+			"m":                   {"PTAL, thanks!"},
+			"notify":              {"OWNER_REVIEWERS"},
+			"notify-to":           {"d@zombo.com"},
+		},
+	}}
+	if diff := cmp.Diff(want, gerrit.received(), cmp.AllowUnexported(fakeGerritChange{})); diff != "" {
+		t.Errorf("changes received (-want +got):\n%s", diff)
+	}
+}
+
+func TestMail_Destination(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gerrit, err := newFakeGerrit(ctx, t, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoPath, err := gerrit.cloneWithHistory(ctx, env, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoGit := env.git.WithDir(repoPath)
+	if err := repoGit.Run(ctx, "push", "origin", "main:refs/heads/release"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repo/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repo/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	commit, err := env.newCommit(ctx, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "Branch", args: []string{"mail", "-d", "release"}},
+		{name: "ForRef", args: []string{"mail", "--for=refs/for/release"}},
+		{name: "PublishComments", args: []string{"mail", "-p", "-d", "release"}},
+	}
+	for _, test := range tests {
+		gerrit.reset()
+		if _, err := env.gg(ctx, repoPath, test.args...); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		got := gerrit.received()
+		if len(got) != 1 {
+			t.Errorf("%s: received %d changes; want 1", test.name, len(got))
+			continue
+		}
+		if got[0].base != "refs/for/release" {
+			t.Errorf("%s: pushed to %q; want %q", test.name, got[0].base, "refs/for/release")
+		}
+		if got[0].commit != commit {
+			t.Errorf("%s: pushed %v; want %v", test.name, got[0].commit, commit)
+		}
+	}
+}
+
+func TestMail_Errors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gerrit, err := newFakeGerrit(ctx, t, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoPath, err := gerrit.cloneWithHistory(ctx, env, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repo/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repo/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantUsage bool
+	}{
+		{name: "Dirty", args: []string{"mail"}},
+		{name: "BadNotify", args: []string{"mail", "--allow-dirty", "--notify=everyone"}, wantUsage: true},
+		{name: "HeadsRef", args: []string{"mail", "--allow-dirty", "-d", "refs/heads/main"}, wantUsage: true},
+		{name: "Percent", args: []string{"mail", "--allow-dirty", "-d", "main%wip"}, wantUsage: true},
+		{name: "MultipleDestinations", args: []string{"mail", "--allow-dirty", "origin", "origin"}, wantUsage: true},
+		{name: "UnknownBranch", args: []string{"mail", "--allow-dirty", "-d", "nonexistent"}},
+	}
+	for _, test := range tests {
+		_, err := env.gg(ctx, repoPath, test.args...)
+		if err == nil {
+			t.Errorf("%s: gg %s did not return an error", test.name, strings.Join(test.args, " "))
+			continue
+		}
+		if got := isUsage(err); got != test.wantUsage {
+			t.Errorf("%s: gg %s = %v; isUsage(err) = %t, want %t", test.name, strings.Join(test.args, " "), err, got, test.wantUsage)
+		}
+	}
+	if got := gerrit.received(); len(got) > 0 {
+		t.Errorf("Gerrit received %d changes; want 0", len(got))
+	}
+}
+
+// fakeGerrit is a Git smart HTTP server that accepts pushes to refs/for/
+// like a Gerrit server and records the changes it receives.
+type fakeGerrit struct {
+	url     string // URL of the Git repository
+	git     *git.Git
+	errorer errorer
+	backend *cgi.Handler
+
+	mu      sync.Mutex
+	changes []fakeGerritChange
+}
+
+// fakeGerritChange is a single push to a magic refs/for/ ref.
+type fakeGerritChange struct {
+	base   git.Ref
+	opts   map[string][]string
+	commit git.Hash
+}
+
+// newFakeGerrit creates a bare repository with an HTTP server in front of it.
+// The server is stopped at the end of the test.
+func newFakeGerrit(ctx context.Context, tb testing.TB, env *testEnv) (*fakeGerrit, error) {
+	projectRoot := env.topDir.FromSlash("gerrit")
+	repoDir := env.topDir.FromSlash("gerrit/repo.git")
+	if err := env.git.InitBare(ctx, repoDir); err != nil {
+		return nil, fmt.Errorf("start fake Gerrit: %w", err)
+	}
+	gerrit := &fakeGerrit{
+		git:     env.git.WithDir(repoDir),
+		errorer: tb,
+		backend: &cgi.Handler{
+			Path: env.git.Exe(),
+			Args: []string{"http-backend"},
+			Dir:  projectRoot,
+			Env: []string{
+				"GIT_CONFIG_NOSYSTEM=1",
+				"GIT_HTTP_EXPORT_ALL=1",
+				"GIT_PROJECT_ROOT=" + projectRoot,
+				"HOME=" + env.topDir.String(),
+				// git-http-backend only enables receive-pack for authenticated users.
+				"REMOTE_USER=gerrit",
+			},
+		},
+	}
+	srv := httptest.NewServer(gerrit)
+	tb.Cleanup(srv.Close)
+	gerrit.url = srv.URL + "/repo.git"
+	return gerrit, nil
+}
+
+// cloneWithHistory creates a repository with some dummy commits at the
+// slash-separated path relative to env.root, then pushes its main branch
+// to the Gerrit server and sets it as the upstream. It returns the
+// absolute path to the repository.
+func (gerrit *fakeGerrit) cloneWithHistory(ctx context.Context, env *testEnv, dir string) (string, error) {
+	if err := env.initRepoWithHistory(ctx, dir); err != nil {
+		return "", err
+	}
+	repoPath := env.root.FromSlash(dir)
+	repoGit := env.git.WithDir(repoPath)
+	if err := repoGit.Run(ctx, "remote", "add", "origin", gerrit.url); err != nil {
+		return "", err
+	}
+	if err := repoGit.Run(ctx, "push", "--set-upstream", "origin", "main"); err != nil {
+		return "", err
+	}
+	gerrit.reset()
+	return repoPath, nil
+}
+
+// received returns the changes pushed since the last call to reset.
+func (gerrit *fakeGerrit) received() []fakeGerritChange {
+	gerrit.mu.Lock()
+	defer gerrit.mu.Unlock()
+	return append([]fakeGerritChange(nil), gerrit.changes...)
+}
+
+// reset clears the list of received changes.
+func (gerrit *fakeGerrit) reset() {
+	gerrit.mu.Lock()
+	gerrit.changes = nil
+	gerrit.mu.Unlock()
+}
+
+func (gerrit *fakeGerrit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || !strings.HasSuffix(r.URL.Path, "/git-receive-pack") {
+		gerrit.backend.ServeHTTP(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		gerrit.errorer.Errorf("Reading receive-pack request: %v", err)
+		http.Error(w, "could not read request", http.StatusBadRequest)
+		return
+	}
+	cmds, err := readReceivePackCommands(body)
+	if err != nil {
+		gerrit.errorer.Errorf("Parsing receive-pack request: %v", err)
+		http.Error(w, "could not parse request", http.StatusBadRequest)
+		return
+	}
+	var changes []fakeGerritChange
+	for _, cmd := range cmds {
+		if !strings.HasPrefix(cmd.ref.String(), "refs/for/") {
+			// Direct pushes are permitted for setting up tests.
+			continue
+		}
+		base, opts, err := parseGerritRef(cmd.ref)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid ref %s: %v", cmd.ref, err), http.StatusBadRequest)
+			return
+		}
+		branch := git.BranchRef(strings.TrimPrefix(base.String(), "refs/for/"))
+		if _, err := gerrit.git.ParseRev(r.Context(), branch.String()); err != nil {
+			http.Error(w, fmt.Sprintf("branch %s not found", branch.Branch()), http.StatusNotFound)
+			return
+		}
+		changes = append(changes, fakeGerritChange{
+			base:   base,
+			opts:   opts,
+			commit: cmd.newHash,
+		})
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	gerrit.backend.ServeHTTP(w, r)
+
+	// Like Gerrit, don't keep the magic refs around. Otherwise, pushing the
+	// same commit again would be a no-op.
+	muts := make(map[git.Ref]git.RefMutation)
+	for _, cmd := range cmds {
+		if strings.HasPrefix(cmd.ref.String(), "refs/for/") {
+			muts[cmd.ref] = git.DeleteRef()
+		}
+	}
+	if len(muts) > 0 {
+		if err := gerrit.git.MutateRefs(r.Context(), muts); err != nil {
+			gerrit.errorer.Errorf("Clearing magic refs: %v", err)
+		}
+	}
+
+	gerrit.mu.Lock()
+	gerrit.changes = append(gerrit.changes, changes...)
+	gerrit.mu.Unlock()
+}
+
+// receivePackCommand is a single ref update in a receive-pack request.
+type receivePackCommand struct {
+	oldHash git.Hash
+	newHash git.Hash
+	ref     git.Ref
+}
+
+// readReceivePackCommands parses the ref update commands at the start of a
+// git-receive-pack request body, up to the first flush packet.
+func readReceivePackCommands(body []byte) ([]receivePackCommand, error) {
+	var cmds []receivePackCommand
+	r := bufio.NewReader(bytes.NewReader(body))
+	for {
+		var lenHex [4]byte
+		if _, err := io.ReadFull(r, lenHex[:]); err != nil {
+			return nil, fmt.Errorf("read pkt-line: %w", err)
+		}
+		n, err := strconv.ParseUint(string(lenHex[:]), 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("read pkt-line: %w", err)
+		}
+		if n == 0 {
+			return cmds, nil
+		}
+		if n < 4 {
+			return nil, fmt.Errorf("read pkt-line: invalid length %d", n)
+		}
+		line := make([]byte, n-4)
+		if _, err := io.ReadFull(r, line); err != nil {
+			return nil, fmt.Errorf("read pkt-line: %w", err)
+		}
+		if i := bytes.IndexByte(line, 0); i != -1 {
+			// Strip capabilities.
+			line = line[:i]
+		}
+		fields := strings.Fields(string(line))
+		if len(fields) != 3 {
+			return nil, errors.New("read pkt-line: malformed command")
+		}
+		oldHash, err := git.ParseHash(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("read pkt-line: %w", err)
+		}
+		newHash, err := git.ParseHash(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("read pkt-line: %w", err)
+		}
+		cmds = append(cmds, receivePackCommand{
+			oldHash: oldHash,
+			newHash: newHash,
+			ref:     git.Ref(fields[2]),
+		})
+	}
+}
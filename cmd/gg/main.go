@@ -36,6 +36,8 @@ import (
 	"gg-scm.io/tool/internal/flag"
 	"gg-scm.io/tool/internal/repocache"
 	"gg-scm.io/tool/internal/sigterm"
+	"gg-scm.io/tool/internal/suggest"
+	"gg-scm.io/tool/internal/terminal"
 )
 
 func main() {
@@ -63,67 +65,60 @@ func main() {
 		if isUsage(err) {
 			os.Exit(64)
 		}
+		if status, ok := exitStatus(err); ok {
+			os.Exit(status)
+		}
 		os.Exit(1)
 	}
 }
 
 func run(ctx context.Context, pctx *processContext, args []string) error {
 	const synopsis = "gg [options] COMMAND [ARG [...]]"
-	const description = "Git with less typing\n\n" +
+	description := "Git with less typing\n\n" +
 		"basic commands:\n" +
-		"  add           " + addSynopsis + "\n" +
-		"  addremove     " + addRemoveSynopsis + "\n" +
-		"  branch        " + branchSynopsis + "\n" +
-		"  cat           " + catSynopsis + "\n" +
-		"  clone         " + cloneSynopsis + "\n" +
-		"  commit        " + commitSynopsis + "\n" +
-		"  diff          " + diffSynopsis + "\n" +
-		"  identify      " + identifySynopsis + "\n" +
-		"  init          " + initSynopsis + "\n" +
-		"  log           " + logSynopsis + "\n" +
-		"  merge         " + mergeSynopsis + "\n" +
-		"  pull          " + pullSynopsis + "\n" +
-		"  push          " + pushSynopsis + "\n" +
-		"  remove        " + removeSynopsis + "\n" +
-		"  requestpull   " + requestPullSynopsis + "\n" +
-		"  revert        " + revertSynopsis + "\n" +
-		"  status        " + statusSynopsis + "\n" +
-		"  update        " + updateSynopsis + "\n" +
+		commandList(false) + "\n" +
 		"\nadvanced commands:\n" +
-		"  backout       " + backoutSynopsis + "\n" +
-		"  evolve        " + evolveSynopsis + "\n" +
-		"  gerrithook    " + gerrithookSynopsis + "\n" +
-		"  github-login  " + gitHubLoginSynopsis + "\n" +
-		"  histedit      " + histeditSynopsis + "\n" +
-		"  mail          " + mailSynopsis + "\n" +
-		"  rebase        " + rebaseSynopsis + "\n" +
-		"  upstream      " + upstreamSynopsis
+		commandList(true) +
+		readOnlyHelp
 
 	globalFlags := flag.NewFlagSet(false, synopsis, description)
 	gitPath := globalFlags.String("git", "", "`path` to git executable")
 	showArgs := globalFlags.Bool("show-git", false, "log git invocations")
+	verbose := globalFlags.Bool("verbose", false, "print more details, like how long Git hooks take")
+	globalFlags.Alias("verbose", "v")
 	versionFlag := globalFlags.Bool("version", false, "display version information")
+	chdir := globalFlags.String("C", "", "run as if gg was started in `path`")
+	gitDirFlag := globalFlags.String("git-dir", "", "`path` to the repository's Git directory (sets GIT_DIR)")
+	workTreeFlag := globalFlags.String("work-tree", "", "`path` to the working tree (sets GIT_WORK_TREE)")
+	readOnlyFlag := globalFlags.Bool("read-only", false, "refuse to run commands that change the repository")
 	if err := globalFlags.Parse(args); flag.IsHelp(err) {
 		globalFlags.Help(pctx.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
-	if globalFlags.NArg() == 0 && !*versionFlag {
-		globalFlags.Help(pctx.stdout)
-		return nil
+	pctx, err := withRepositoryFlags(pctx, *chdir, *gitDirFlag, *workTreeFlag)
+	if err != nil {
+		return fmt.Errorf("gg: %w", err)
 	}
+	noCommand := globalFlags.NArg() == 0 && !*versionFlag
 	if *gitPath == "" {
 		var err error
 		*gitPath, err = pctx.lookPath("git")
-		if err != nil {
+		if err != nil && noCommand {
+			globalFlags.Help(pctx.stdout)
+			return nil
+		} else if err != nil {
 			return fmt.Errorf("gg: %w", err)
 		}
 	}
+	configOverrides := envConfigOverrides(pctx.env, func(e error) {
+		fmt.Fprintf(pctx.stderr, "gg: %v; ignoring\n", e)
+	})
 	opts := git.Options{
 		GitExe: *gitPath,
 		Dir:    pctx.dir,
-		Env:    pctx.env,
+		Env:    appendGitConfigParams(pctx.env, configOverrides...),
 	}
 	if *showArgs {
 		opts.LogHook = func(_ context.Context, args []string) {
@@ -143,14 +138,54 @@ func run(ctx context.Context, pctx *processContext, args []string) error {
 			pctx.stderr.Write(buf.Bytes())
 		}
 	}
-	git, err := git.New(opts)
+	stdinFile, _ := pctx.stdin.(*os.File)
+	limits := &subprocessLimits{
+		interactive: stdinFile != nil && terminal.IsTerminal(stdinFile),
+		promptWait:  promptWait,
+	}
+	git, err := newGit(opts, pctx.tempDir, limits)
 	if err != nil {
 		return fmt.Errorf("gg: %w", err)
 	}
+	// Read the configuration once for the settings that apply to every
+	// command. If it can't be read, configuration problems will be
+	// reported by the command.
+	cfg, err := git.ReadConfig(ctx)
+	if err != nil {
+		cfg = nil
+	}
+	limits.warn = func(e error) {
+		fmt.Fprintf(pctx.stderr, "gg: %v; ignoring\n", e)
+	}
+	if err := limits.load(cfg); err != nil {
+		limits.warn(err)
+	}
+	readOnly := *readOnlyFlag
+	if !readOnly && cfg != nil {
+		readOnly, err = readOnlyEnabled(cfg)
+		if err != nil {
+			return fmt.Errorf("gg: %w", err)
+		}
+	}
+	if readOnly {
+		// Keep Git from refreshing the index as a side effect of reading
+		// it, and from fetching missing revisions.
+		opts.Env = append(appendGitConfigParams(opts.Env, "gg.autoFetch=false"), "GIT_OPTIONAL_LOCKS=0")
+		git, err = newGit(opts, pctx.tempDir, limits)
+		if err != nil {
+			return fmt.Errorf("gg: %w", err)
+		}
+	}
 	cc := &cmdContext{
-		dir:     pctx.dir,
-		xdgDirs: newXDGDirs(pctx.env),
-		git:     git,
+		dir:        pctx.dir,
+		tempDir:    pctx.tempDir,
+		verbose:    *verbose,
+		readOnly:   readOnly,
+		xdgDirs:    newXDGDirs(pctx.env),
+		git:        git,
+		gitOptions: opts,
+		limits:     limits,
+		env:        pctx.env,
 		editor: &editor{
 			git:      git,
 			tempRoot: pctx.tempDir,
@@ -163,7 +198,7 @@ func run(ctx context.Context, pctx *processContext, args []string) error {
 				fmt.Fprintln(pctx.stderr, "gg:", e)
 			},
 		},
-		httpClient: pctx.httpClient,
+		httpClient: withGitHubRateLimits(pctx.httpClient, pctx.stderr),
 		stdin:      pctx.stdin,
 		stdout:     pctx.stdout,
 		stderr:     pctx.stderr,
@@ -174,31 +209,48 @@ func run(ctx context.Context, pctx *processContext, args []string) error {
 		}
 		return nil
 	}
-	err = dispatch(ctx, cc, globalFlags, globalFlags.Arg(0), globalFlags.Args()[1:])
+	if noCommand {
+		if err := defaultCommand(ctx, cc, globalFlags); err != nil {
+			return fmt.Errorf("gg: %w", explainGitError(err))
+		}
+		return nil
+	}
+	name, cmdArgs := globalFlags.Arg(0), globalFlags.Args()[1:]
+	err = runJournaled(ctx, cc, cfg, name, cmdArgs, func() error {
+		return dispatch(ctx, cc, globalFlags, name, cmdArgs)
+	})
+	// Keep the conflict manifest current for editor plugins. Outside a
+	// repository there is nothing to update, so errors are ignored.
+	if c := findCommand(name); c != nil && c.conflicts && !cc.readOnly {
+		refreshConflictManifest(ctx, cc.git)
+	}
 	if err != nil {
-		return fmt.Errorf("gg: %w", err)
+		return fmt.Errorf("gg: %w", explainGitError(err))
 	}
 	return nil
 }
 
 type cmdContext struct {
-	dir     string
-	xdgDirs *xdgDirs
+	dir      string
+	tempDir  string
+	verbose  bool
+	readOnly bool // refuse commands that change the repository
+	xdgDirs  *xdgDirs
 
 	git        *git.Git
+	gitOptions git.Options       // options used to create git
+	limits     *subprocessLimits // limits on git's subprocesses
 	editor     *editor
 	httpClient *http.Client
 
+	env    []string // process environment
 	stdin  io.Reader
 	stdout io.Writer
 	stderr io.Writer
 }
 
 func (cc *cmdContext) abs(path string) string {
-	if filepath.IsAbs(path) {
-		return filepath.Clean(path)
-	}
-	return filepath.Join(cc.dir, path)
+	return absPath(cc.dir, path)
 }
 
 func (cc *cmdContext) withDir(path string) *cmdContext {
@@ -209,6 +261,82 @@ func (cc *cmdContext) withDir(path string) *cmdContext {
 	return cc2
 }
 
+// withGitConfig returns a copy of cc whose Git subprocesses see the given
+// configuration variable set, as if by `git -c name=value`.
+func (cc *cmdContext) withGitConfig(name, value string) (*cmdContext, error) {
+	opts := cc.gitOptions
+	opts.Dir = cc.dir
+	opts.Env = appendGitConfigParams(opts.Env, name+"="+value)
+	g, err := newGit(opts, cc.tempDir, cc.limits)
+	if err != nil {
+		return nil, err
+	}
+	cc2 := new(cmdContext)
+	*cc2 = *cc
+	cc2.git = g
+	cc2.gitOptions = opts
+	return cc2, nil
+}
+
+// withGitEnv returns a copy of cc whose Git subprocesses see the given
+// NAME=value environment variables in addition to cc's environment.
+func (cc *cmdContext) withGitEnv(vars ...string) (*cmdContext, error) {
+	opts := cc.gitOptions
+	opts.Dir = cc.dir
+	env := opts.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	opts.Env = append(append([]string(nil), env...), vars...)
+	g, err := newGit(opts, cc.tempDir, cc.limits)
+	if err != nil {
+		return nil, err
+	}
+	cc2 := new(cmdContext)
+	*cc2 = *cc
+	cc2.git = g
+	cc2.gitOptions = opts
+	return cc2, nil
+}
+
+// appendGitConfigParams returns a copy of environ with the given
+// name=value assignments added to GIT_CONFIG_PARAMETERS, as if they were
+// passed to Git with -c. Assignments already in environ are kept, but
+// the new ones take precedence.
+func appendGitConfigParams(environ []string, params ...string) []string {
+	newEnv := append([]string(nil), environ...)
+	if len(params) == 0 {
+		return newEnv
+	}
+	// Git passes -c options to subprocesses in GIT_CONFIG_PARAMETERS
+	// as a space-separated list of shell-quoted items.
+	quoted := make([]string, 0, len(params))
+	for _, p := range params {
+		quoted = append(quoted, sqQuote(p))
+	}
+	param := strings.Join(quoted, " ")
+	found := false
+	for i, kv := range newEnv {
+		if v, ok := strings.CutPrefix(kv, "GIT_CONFIG_PARAMETERS="); ok {
+			if v != "" {
+				param = v + " " + param
+			}
+			newEnv[i] = "GIT_CONFIG_PARAMETERS=" + param
+			found = true
+		}
+	}
+	if !found {
+		newEnv = append(newEnv, "GIT_CONFIG_PARAMETERS="+param)
+	}
+	return newEnv
+}
+
+// sqQuote quotes s in single quotes for a POSIX shell. Unlike escape.Bash,
+// it always adds quotes, since Git requires them in GIT_CONFIG_PARAMETERS.
+func sqQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func (cc *cmdContext) interactiveGit(ctx context.Context, args ...string) error {
 	err := cc.git.Runner().RunGit(ctx, &git.Invocation{
 		Dir:    cc.dir,
@@ -224,41 +352,96 @@ func (cc *cmdContext) interactiveGit(ctx context.Context, args ...string) error
 }
 
 func dispatch(ctx context.Context, cc *cmdContext, globalFlags *flag.FlagSet, name string, args []string) error {
+	if cc.readOnly {
+		if err := checkReadOnly(name, args); err != nil {
+			return err
+		}
+	}
 	switch name {
+	case "absorb":
+		return absorb(ctx, cc, args)
 	case "add":
 		return add(ctx, cc, args)
 	case "addremove":
 		return addRemove(ctx, cc, args)
+	case "amend":
+		return amend(ctx, cc, args)
+	case "apply":
+		return apply(ctx, cc, args)
+	case "attrs":
+		return attrs(ctx, cc, args)
+	case "auth":
+		return auth(ctx, cc, args)
 	case "backout":
 		return backout(ctx, cc, args)
+	case "backups":
+		return backups(ctx, cc, args)
 	case "branch":
 		return branch(ctx, cc, args)
 	case "cat":
 		return cat(ctx, cc, args)
+	case "cd":
+		// gg cd only works through the function that gg repos shell prints,
+		// since a child process can't change the shell's directory.
+		return errors.New(`gg cd needs a shell function; add 'eval "$(gg repos shell)"' to your shell's startup file`)
+	case "changelog":
+		return changelog(ctx, cc, args)
 	case "clone":
 		return clone(ctx, cc, args)
 	case "commit", "ci":
 		return commit(ctx, cc, args)
+	case "config":
+		return config(ctx, cc, args)
+	case "debug":
+		return debug(ctx, cc, args)
 	case "diff":
 		return diff(ctx, cc, args)
 	case "evolve":
 		return evolve(ctx, cc, args)
+	case "filelog":
+		return filelog(ctx, cc, args)
+	case "fixup":
+		return fixup(ctx, cc, args)
+	case "fold", "squash":
+		return fold(ctx, cc, args)
 	case "gerrithook":
 		return gerrithook(ctx, cc, args)
+	case "git":
+		return gitPassthrough(ctx, cc, args)
 	case "github-login":
 		return gitHubLogin(ctx, cc, args)
+	case "graft":
+		return graft(ctx, cc, args)
 	case "histedit":
 		return histedit(ctx, cc, args)
 	case "identify", "id":
 		return identify(ctx, cc, args)
+	case "identity":
+		return identity(ctx, cc, args)
+	case "import":
+		return importChange(ctx, cc, args)
+	case "incoming":
+		return incoming(ctx, cc, args)
 	case "init":
 		return init_(ctx, cc, args)
+	case "journal":
+		return journal(ctx, cc, args)
+	case "lint-history":
+		return lintHistory(ctx, cc, args)
 	case "log", "history":
 		return log(ctx, cc, args)
 	case "mail":
 		return mail(ctx, cc, args)
 	case "merge":
 		return merge(ctx, cc, args)
+	case "note":
+		return note(ctx, cc, args)
+	case "old":
+		return old(ctx, cc, args)
+	case "outgoing":
+		return outgoing(ctx, cc, args)
+	case "paths":
+		return paths(ctx, cc, args)
 	case "pull":
 		return pull(ctx, cc, args)
 	case "push":
@@ -267,27 +450,74 @@ func dispatch(ctx context.Context, cc *cmdContext, globalFlags *flag.FlagSet, na
 		return remove(ctx, cc, args)
 	case "rebase":
 		return rebase(ctx, cc, args)
+	case "recent":
+		return recent(ctx, cc, args)
+	case "release":
+		return release(ctx, cc, args)
+	case "remote":
+		return remote(ctx, cc, args)
+	case "repos":
+		return repos(ctx, cc, args)
 	case "requestpull", "pr":
 		return requestPull(ctx, cc, args)
+	case "resolve":
+		return resolve(ctx, cc, args)
+	case "resolve-rev":
+		return resolveRev(ctx, cc, args)
+	case "restore-from":
+		return restoreFrom(ctx, cc, args)
 	case "revert":
 		return revert(ctx, cc, args)
+	case "shelve":
+		return shelve(ctx, cc, args)
+	case "snapshot":
+		return snapshot(ctx, cc, args)
+	case "stage":
+		return stage(ctx, cc, args)
+	case "state":
+		return state(ctx, cc, args)
+	case "stats-repo":
+		return statsRepo(ctx, cc, args)
 	case "status", "st", "check":
 		return status(ctx, cc, args)
+	case "trust":
+		return trust(ctx, cc, args)
+	case "unstage":
+		return unstage(ctx, cc, args)
+	case "unshelve":
+		return unshelve(ctx, cc, args)
+	case "untrack-changes":
+		return untrackChanges(ctx, cc, args)
 	case "update", "up", "checkout", "co":
 		return update(ctx, cc, args)
 	case "upstream":
 		return upstream(ctx, cc, args)
+	case "view":
+		return view(ctx, cc, args)
 	case "version":
 		return showVersion(ctx, cc)
 	case "help":
-		if len(args) == 0 {
-			globalFlags.Help(cc.stdout)
+		f := flag.NewFlagSet(true, "gg help [--json] [COMMAND]", "show help for gg or a command\n\n"+
+			"With `--json`, print the commands, their aliases, and their flags\n"+
+			"as JSON for use by other programs.")
+		jsonOutput := f.Bool("json", false, "print command metadata as JSON")
+		if err := f.Parse(args); flag.IsHelp(err) {
+			f.Help(cc.stdout)
 			return nil
+		} else if err != nil {
+			return usagef("%v", err)
+		}
+		if f.NArg() > 1 {
+			return usagef("help [--json] [command]")
 		}
-		if len(args) > 1 || strings.HasPrefix(args[0], "-") {
-			return usagef("help [command]")
+		if *jsonOutput {
+			return writeHelpJSON(ctx, cc, globalFlags, f.Arg(0))
 		}
-		return dispatch(ctx, cc, globalFlags, args[0], []string{"--help"})
+		if f.NArg() == 0 {
+			globalFlags.Help(cc.stdout)
+			return nil
+		}
+		return dispatch(ctx, cc, globalFlags, f.Arg(0), []string{"--help"})
 	case "ez":
 		f := flag.NewFlagSet(true, "gg ez [-re=0]", "")
 		re := f.Bool("re", true, "rematch")
@@ -299,6 +529,9 @@ func dispatch(ctx context.Context, cc *cmdContext, globalFlags *flag.FlagSet, na
 		}
 		return nil
 	default:
+		if hint := suggest.Phrase("", suggest.Similar(name, commandNames())); hint != "" {
+			return usagef("unknown command %s (%s)", name, hint)
+		}
 		return usagef("unknown command %s", name)
 	}
 }
@@ -417,6 +650,50 @@ func osProcessContext() (*processContext, error) {
 	}, nil
 }
 
+// withRepositoryFlags returns a copy of pctx that runs in the directory
+// given by -C and points Git at the Git directory and working tree given
+// by --git-dir and --work-tree. As with Git, relative paths are resolved
+// after changing to the -C directory. GIT_DIR and GIT_WORK_TREE from the
+// environment are made absolute so that they keep working when a command
+// runs Git in another directory.
+func withRepositoryFlags(pctx *processContext, chdir, gitDir, workTree string) (*processContext, error) {
+	pctx2 := new(processContext)
+	*pctx2 = *pctx
+	if chdir != "" {
+		pctx2.dir = absPath(pctx.dir, chdir)
+		if info, err := os.Stat(pctx2.dir); err != nil {
+			return nil, fmt.Errorf("-C: %w", err)
+		} else if !info.IsDir() {
+			return nil, fmt.Errorf("-C: %s is not a directory", chdir)
+		}
+	}
+	if gitDir == "" {
+		gitDir = getenv(pctx.env, "GIT_DIR")
+	}
+	if workTree == "" {
+		workTree = getenv(pctx.env, "GIT_WORK_TREE")
+	}
+	if gitDir == "" && workTree == "" {
+		return pctx2, nil
+	}
+	pctx2.env = append([]string(nil), pctx.env...)
+	if gitDir != "" {
+		pctx2.env = append(pctx2.env, "GIT_DIR="+absPath(pctx2.dir, gitDir))
+	}
+	if workTree != "" {
+		pctx2.env = append(pctx2.env, "GIT_WORK_TREE="+absPath(pctx2.dir, workTree))
+	}
+	return pctx2, nil
+}
+
+// absPath resolves path relative to dir.
+func absPath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
+
 // getenv is like os.Getenv but reads from the given list of environment
 // variables.
 func getenv(environ []string, name string) string {
@@ -439,6 +716,8 @@ type xdgDirs struct {
 	configHome string
 	configDirs []string
 	cacheHome  string
+	stateHome  string
+	runtimeDir string
 }
 
 // newXDGDirs reads directory locations from the given environment variables.
@@ -447,6 +726,8 @@ func newXDGDirs(environ []string) *xdgDirs {
 		configHome: getenv(environ, "XDG_CONFIG_HOME"),
 		configDirs: filepath.SplitList(getenv(environ, "XDG_CONFIG_DIRS")),
 		cacheHome:  getenv(environ, "XDG_CACHE_HOME"),
+		stateHome:  getenv(environ, "XDG_STATE_HOME"),
+		runtimeDir: getenv(environ, "XDG_RUNTIME_DIR"),
 	}
 	if x.configHome == "" {
 		if home := getenv(environ, "HOME"); home != "" {
@@ -461,11 +742,16 @@ func newXDGDirs(environ []string) *xdgDirs {
 			x.cacheHome = filepath.Join(home, ".cache")
 		}
 	}
+	if x.stateHome == "" {
+		if home := getenv(environ, "HOME"); home != "" {
+			x.stateHome = filepath.Join(home, ".local", "state")
+		}
+	}
 	return x
 }
 
-// configDirname is the name of the subdirectory inside the user's configuration
-// or cache directory to store files.
+// configDirname is the name of the subdirectory inside the user's configuration,
+// cache, or state directory to store files.
 const configDirname = "gg"
 
 // readConfig reads the file at the given slash-separated path relative
@@ -544,6 +830,60 @@ func (x *xdgDirs) createCache(name string) (*os.File, error) {
 	return f, nil
 }
 
+// writeState writes the file at the given slash-separated path relative
+// to the gg state directory. Any non-existent parent directories will be
+// created.
+func (x *xdgDirs) writeState(name string, value []byte) error {
+	if x.stateHome == "" {
+		return fmt.Errorf("write state %s: no $XDG_STATE_HOME variable set", name)
+	}
+	path := filepath.Join(x.stateHome, configDirname, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("write state %s: %w", name, err)
+	}
+	if err := os.WriteFile(path, value, 0644); err != nil {
+		return fmt.Errorf("write state %s: %w", name, err)
+	}
+	return nil
+}
+
+// readRuntime reads the file at the given slash-separated path relative
+// to the gg runtime directory. The runtime directory lasts as long as the
+// user's login session, so it has no default location.
+func (x *xdgDirs) readRuntime(name string) ([]byte, error) {
+	if x.runtimeDir == "" {
+		return nil, fmt.Errorf("read %s: no $XDG_RUNTIME_DIR variable set", name)
+	}
+	return os.ReadFile(filepath.Join(x.runtimeDir, configDirname, filepath.FromSlash(name)))
+}
+
+// writeRuntimeSecret writes the file at the given slash-separated path
+// relative to the gg runtime directory with restricted permissions.
+func (x *xdgDirs) writeRuntimeSecret(name string, value []byte) error {
+	if x.runtimeDir == "" {
+		return fmt.Errorf("write %s: no $XDG_RUNTIME_DIR variable set", name)
+	}
+	path := filepath.Join(x.runtimeDir, configDirname, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, value, 0600)
+}
+
+// removeRuntime removes the file at the given slash-separated path
+// relative to the gg runtime directory. It is not an error if the file
+// does not exist.
+func (x *xdgDirs) removeRuntime(name string) error {
+	if x.runtimeDir == "" {
+		return nil
+	}
+	err := os.Remove(filepath.Join(x.runtimeDir, configDirname, filepath.FromSlash(name)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
 type usageError string
 
 func usagef(format string, args ...interface{}) error {
@@ -56,6 +56,7 @@ func TestNewXDGDirs(t *testing.T) {
 				configHome: filepath.Join("/home/foo", ".config"),
 				configDirs: []string{"/etc/xdg"},
 				cacheHome:  filepath.Join("/home/foo", ".cache"),
+				stateHome:  filepath.Join("/home/foo", ".local", "state"),
 			},
 		},
 		{
@@ -93,6 +94,25 @@ func TestNewXDGDirs(t *testing.T) {
 				configDirs: []string{"/etc/xdg"},
 			},
 		},
+		{
+			name:    "StateHome",
+			environ: []string{"XDG_STATE_HOME=/on/the/range"},
+			want: xdgDirs{
+				stateHome:  "/on/the/range",
+				configDirs: []string{"/etc/xdg"},
+			},
+		},
+		{
+			name:    "RuntimeDir",
+			environ: []string{"HOME=/home/foo", "XDG_RUNTIME_DIR=/run/user/1000"},
+			want: xdgDirs{
+				configHome: filepath.Join("/home/foo", ".config"),
+				configDirs: []string{"/etc/xdg"},
+				cacheHome:  filepath.Join("/home/foo", ".cache"),
+				stateHome:  filepath.Join("/home/foo", ".local", "state"),
+				runtimeDir: "/run/user/1000",
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -107,6 +127,51 @@ func TestNewXDGDirs(t *testing.T) {
 	}
 }
 
+func TestRepositoryFlags(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repo/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	repoGit := env.git.WithDir(env.root.FromSlash("repo"))
+
+	// -C runs the command as if gg was started in the directory.
+	if _, err := env.gg(ctx, env.root.String(), "-C", "repo", "add", "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	st, err := repoGit.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st) != 1 || st[0].Name != "foo.txt" || !st[0].Code.IsAdded() {
+		t.Errorf("status after gg -C repo add foo.txt = %v; want foo.txt added", st)
+	}
+
+	// --git-dir and --work-tree point at the repository from outside it.
+	_, err = env.gg(ctx, env.root.String(), "--git-dir=repo/.git", "--work-tree=repo", "commit", "-m", "Add foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := repoGit.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := info.Message, "Add foo\n"; got != want {
+		t.Errorf("HEAD message = %q; want %q", got, want)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "-C", "nonexistent", "status"); err == nil {
+		t.Error("gg -C nonexistent status succeeded")
+	}
+}
+
 type testEnv struct {
 	// root is the path to a directory guaranteed to be empty at the
 	// beginning of the test.
@@ -119,6 +184,10 @@ type testEnv struct {
 	// It defaults to a stub.
 	roundTripper http.RoundTripper
 
+	// environ is a list of additional environment variables
+	// to pass to gg.
+	environ []string
+
 	// The following are fields managed by testEnv, and should not be
 	// referred to in tests.
 
@@ -138,7 +207,7 @@ var (
 
 func newTestEnv(ctx context.Context, tb testing.TB) (*testEnv, error) {
 	globalGitOnce.Do(func() {
-		globalGit, globalGitError = git.New(git.Options{})
+		globalGit, globalGitError = git.New(git.Options{GitExe: *testGitExe})
 	})
 	if globalGitError != nil {
 		tb.Skipf("could not find git, skipping (error: %v)", globalGitError)
@@ -276,6 +345,7 @@ func (env *testEnv) gg(ctx context.Context, dir string, args ...string) ([]byte,
 			return "", errors.New("look path stubbed")
 		},
 	}
+	pctx.env = append(pctx.env, env.environ...)
 	err := run(ctx, pctx, args)
 	return out.Bytes(), err
 }
@@ -0,0 +1,114 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	stdflag "flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	testGitExe = stdflag.String("git", "", "`path` of the Git executable to run the integration tests with (default is git from PATH)")
+
+	testGitMatrix = stdflag.String("git-matrix", "", "`list` of Git executables, separated by the OS path list separator, for TestMatrix to run the tests with")
+	testMatrixRun = stdflag.String("git-matrix-run", "", "`regexp` of tests for TestMatrix to run with each Git (default all)")
+)
+
+// TestMatrix runs the tests once for each Git executable listed in
+// -git-matrix, so that changes can be checked against the older Git
+// versions that users still have. For example:
+//
+//	go test -run=TestMatrix ./cmd/gg -args -git-matrix=/opt/git-2.25/bin/git:/opt/git-2.43/bin/git
+//
+// Each executable runs the tests in a new process of the test binary.
+// TestMatrix reports the tests that failed with each version.
+func TestMatrix(t *testing.T) {
+	if *testGitMatrix == "" {
+		t.Skip("no -git-matrix given")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, gitExe := range filepath.SplitList(*testGitMatrix) {
+		if gitExe == "" {
+			continue
+		}
+		version, err := matrixGitVersion(ctx, gitExe)
+		if err != nil {
+			t.Errorf("%s: %v", gitExe, err)
+			continue
+		}
+		t.Run(version, func(t *testing.T) {
+			args := []string{"-test.v", "-git=" + gitExe}
+			if *testMatrixRun != "" {
+				args = append(args, "-test.run="+*testMatrixRun)
+			}
+			if testing.Short() {
+				args = append(args, "-test.short")
+			}
+			cmd := exec.CommandContext(ctx, exe, args...)
+			out, runErr := cmd.CombinedOutput()
+			failed := matrixFailures(out)
+			if len(failed) > 0 {
+				t.Errorf("git %s (%s): %d tests failed:\n%s", version, gitExe, len(failed), strings.Join(failed, "\n"))
+			} else if runErr != nil {
+				t.Errorf("git %s (%s): %v; output:\n%s", version, gitExe, runErr, out)
+			}
+		})
+	}
+}
+
+// matrixGitVersion returns the version number that a Git executable
+// reports, like "2.43.0".
+func matrixGitVersion(ctx context.Context, gitExe string) (string, error) {
+	out, err := exec.CommandContext(ctx, gitExe, "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	version := strings.TrimSpace(string(out))
+	version = strings.TrimPrefix(version, "git version ")
+	if version == "" {
+		return "", errors.New("git --version printed nothing")
+	}
+	return version, nil
+}
+
+var matrixFailRegexp = regexp.MustCompile(`^\s*--- FAIL: (\S+)`)
+
+// matrixFailures returns the names of the failed tests in the output
+// of a verbose test run.
+func matrixFailures(out []byte) []string {
+	var failed []string
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		if m := matrixFailRegexp.FindSubmatch(s.Bytes()); m != nil {
+			failed = append(failed, string(m[1]))
+		}
+	}
+	return failed
+}
@@ -15,17 +15,37 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
+	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
 )
 
 const mergeSynopsis = "merge another revision into working directory"
 
 func merge(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg merge [[-r] REV]", mergeSynopsis)
+	f := flag.NewFlagSet(true, "gg merge [--conflict-style STYLE] [--log[=N]] [[-r] REV]\n"+
+		"gg merge --into BRANCH [--log[=N]]", mergeSynopsis+`
+
+	If Git's rerere feature is enabled, conflicts that were resolved
+	before are resolved the same way again. See `+"`gg config rerere`"+`.
+
+	`+"`--into`"+` merges the current branch into BRANCH instead, without
+	checking BRANCH out or touching the working copy. If BRANCH can be
+	fast-forwarded, it is moved to the current commit. Otherwise, the
+	merge commit is created in memory, which requires Git 2.38 or later.
+	If the merge has conflicts, nothing is changed and the conflicted
+	files are listed; check out BRANCH and merge there to resolve them.`+mergeMessageHelp)
 	rev := f.String("r", "", "`rev`ision to merge")
 	abort := f.Bool("abort", false, "abort the ongoing merge")
+	into := f.String("into", "", "merge the current branch into `branch` without checking it out")
+	conflictStyle := addConflictStyleFlag(f)
+	var logLimit mergeLogValue
+	f.Var(&logLimit, "log", "list up to `N` merged commits in the commit message")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -36,19 +56,169 @@ func merge(ctx context.Context, cc *cmdContext, args []string) error {
 		if f.NArg() != 0 || *rev != "" {
 			return usagef("cannot specify revision with --abort")
 		}
+		if *conflictStyle != "" {
+			return usagef("cannot specify --conflict-style with --abort")
+		}
+		if f.IsSet("log") {
+			return usagef("cannot specify --log with --abort")
+		}
+		if f.IsSet("into") {
+			return usagef("cannot specify both --abort and --into")
+		}
 		return cc.git.AbortMerge(ctx)
 	}
 	if f.NArg() > 1 || (f.Arg(0) != "" && *rev != "") {
 		return usagef("must pass at most one revision to merge")
 	}
+	if f.IsSet("into") {
+		if f.NArg() != 0 || *rev != "" {
+			return usagef("cannot specify revision with --into; it merges the current branch")
+		}
+		if *conflictStyle != "" {
+			return usagef("cannot specify --conflict-style with --into")
+		}
+		if *into == "" || strings.HasPrefix(*into, "-") {
+			return usagef("invalid branch %q for --into", *into)
+		}
+	}
 	if *rev == "" {
 		*rev = f.Arg(0)
 	}
 	if *rev == "" {
 		*rev = "@{upstream}"
 	}
-	if err := cc.git.Merge(ctx, []string{*rev}); err != nil {
+	cc, err := withConflictStyle(cc, *conflictStyle)
+	if err != nil {
+		return err
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if !f.IsSet("log") {
+		if v := cfg.Value("merge.log"); v != "" {
+			if err := logLimit.Set(v); err != nil {
+				return fmt.Errorf("merge.log: %w", err)
+			}
+		}
+	}
+	if f.IsSet("into") {
+		return mergeInto(ctx, cc, cfg, *into, int(logLimit))
+	}
+	if err := recordOperation(ctx, cc.git, "merge", args); err != nil {
 		return err
 	}
+	mergeErr := cc.git.Merge(ctx, []string{*rev})
+	if err := writeMergeMessage(ctx, cc.git, cfg, *rev, int(logLimit)); err != nil {
+		fmt.Fprintf(cc.stderr, "gg: %v\n", err)
+	}
+	if mergeErr != nil {
+		reportReusedResolutions(ctx, cc)
+		return mergeErr
+	}
 	return nil
 }
+
+// mergeInto merges HEAD into the given branch without checking it out.
+func mergeInto(ctx context.Context, cc *cmdContext, cfg *git.Config, branch string, logLimit int) error {
+	head, err := cc.git.Head(ctx)
+	if err != nil {
+		return err
+	}
+	target, err := cc.git.ParseRev(ctx, git.BranchRef(branch).String())
+	if err != nil {
+		return fmt.Errorf("merge into %s: %w", branch, err)
+	}
+	if head.Ref == target.Ref {
+		return fmt.Errorf("%s is already checked out; use gg merge to merge into it", branch)
+	}
+	if path, err := otherWorktreeFor(ctx, cc, branch); err != nil {
+		return err
+	} else if path != "" {
+		return fmt.Errorf("%s is checked out in %s; merge there instead", branch, path)
+	}
+	source := head.Commit.Short()
+	if head.Ref.IsBranch() {
+		source = head.Ref.Branch()
+	}
+
+	if merged, err := cc.git.IsAncestor(ctx, head.Commit.String(), target.Commit.String()); err != nil {
+		return err
+	} else if merged {
+		_, err := fmt.Fprintf(cc.stdout, "%s already contains %s\n", branch, source)
+		return err
+	}
+	if fastForward, err := cc.git.IsAncestor(ctx, target.Commit.String(), head.Commit.String()); err != nil {
+		return err
+	} else if fastForward {
+		err := cc.git.Run(ctx, "update-ref", "-m", "gg merge --into: fast-forward",
+			target.Ref.String(), head.Commit.String(), target.Commit.String())
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(cc.stdout, "fast-forwarded %s to %s\n", branch, head.Commit.Short())
+		return err
+	}
+
+	tree, conflicts, err := mergeTrees(ctx, cc, target.Commit.String(), head.Commit.String())
+	if err != nil {
+		return fmt.Errorf("merge %s into %s: %w\ncheck out %s and run gg merge %s instead", source, branch, err, branch, source)
+	}
+	if len(conflicts) > 0 {
+		sb := new(strings.Builder)
+		fmt.Fprintf(sb, "merging %s into %s has conflicts:", source, branch)
+		for _, name := range conflicts {
+			sb.WriteString("\n\t")
+			sb.WriteString(name)
+		}
+		fmt.Fprintf(sb, "\nto resolve them, check out %s and run gg merge %s", branch, source)
+		return errors.New(sb.String())
+	}
+	msg, err := mergeMessage(ctx, cc.git, cfg, git.Head.String(), branch, target.Commit.String()+".."+head.Commit.String(), logLimit)
+	if err != nil {
+		return err
+	}
+	out, err := cc.git.Output(ctx, "commit-tree", tree,
+		"-p", target.Commit.String(),
+		"-p", head.Commit.String(),
+		"-m", strings.TrimSuffix(msg, "\n"))
+	if err != nil {
+		return err
+	}
+	mergeCommit := strings.TrimSpace(out)
+	err = cc.git.Run(ctx, "update-ref", "-m", "gg merge --into: merge "+source,
+		target.Ref.String(), mergeCommit, target.Commit.String())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cc.stdout, "merged %s into %s as %s\n", source, branch, shortHash(mergeCommit))
+	return err
+}
+
+// mergeTrees merges the commits ours and theirs without touching the
+// index or the working copy, using git merge-tree --write-tree, which
+// requires Git 2.38 or later. If the merge is clean, mergeTrees returns
+// the merged tree. Otherwise, it returns the files with conflicts.
+func mergeTrees(ctx context.Context, cc *cmdContext, ours, theirs string) (tree string, conflicts []string, _ error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	err := cc.git.Runner().RunGit(ctx, &git.Invocation{
+		Args:   []string{"merge-tree", "--write-tree", "--name-only", "--no-messages", ours, theirs},
+		Dir:    cc.dir,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if err != nil {
+		// Exit status 1 means the merge has conflicts; anything else,
+		// like the usage error from older Git versions, is a failure.
+		if status, ok := childExitStatus(err); ok && status == 1 && len(lines) > 1 {
+			return "", lines[1:], nil
+		}
+		if msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); msg != "" {
+			return "", nil, fmt.Errorf("git merge-tree: %s (Git 2.38 or later is required)", msg)
+		}
+		return "", nil, fmt.Errorf("git merge-tree: %w (Git 2.38 or later is required)", err)
+	}
+	return lines[0], nil, nil
+}
@@ -16,10 +16,12 @@ package main
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/filesystem"
+	"golang.org/x/exp/slices"
 )
 
 func TestMerge(t *testing.T) {
@@ -200,3 +202,424 @@ func TestMerge_Conflict(t *testing.T) {
 			prettyCommit(feature, names))
 	}
 }
+
+// setupMergeConflict creates a repository in env.root with a main branch
+// and a feature branch that both change foo.txt. main is checked out.
+func setupMergeConflict(ctx context.Context, env *testEnv) error {
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		return err
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "In the beginning...\n")); err != nil {
+		return err
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		return err
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		return err
+	}
+	if err := env.git.NewBranch(ctx, "feature", git.BranchOptions{Checkout: true}); err != nil {
+		return err
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "feature content\n")); err != nil {
+		return err
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		return err
+	}
+	if err := env.git.CheckoutBranch(ctx, "main", git.CheckoutOptions{}); err != nil {
+		return err
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "boring text\n")); err != nil {
+		return err
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		return err
+	}
+	return nil
+}
+
+func TestMerge_ConflictStyle(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupMergeConflict(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "merge", "--conflict-style=bogus", "feature"); err == nil {
+		t.Error("merge --conflict-style=bogus did not return error")
+	} else if !isUsage(err) {
+		t.Errorf("merge --conflict-style=bogus error = %v; want usage", err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "merge", "--conflict-style=diff3", "feature"); err == nil {
+		t.Error("merge did not return error")
+	} else if isUsage(err) {
+		t.Errorf("merge returned usage error: %v", err)
+	}
+	got, err := env.root.ReadFile("foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// diff3 style includes the merge base's content.
+	if !strings.Contains(got, "|||||||") || !strings.Contains(got, "In the beginning...") {
+		t.Errorf("foo.txt after merge = %q; want diff3-style conflict markers", got)
+	}
+}
+
+func TestMerge_Rerere(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupMergeConflict(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "config", "rerere", "on"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resolve the conflict once and record the resolution.
+	const resolved = "boring feature content\n"
+	if _, err := env.gg(ctx, env.root.String(), "merge", "feature"); err == nil {
+		t.Fatal("first merge did not return error")
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", resolved)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "rerere"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.AbortMerge(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Merging again should reuse the resolution and say so.
+	env.stderr.Reset()
+	if _, err := env.gg(ctx, env.root.String(), "merge", "feature"); err == nil {
+		t.Fatal("second merge did not return error")
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Fatal(err)
+	} else if got != resolved {
+		t.Errorf("foo.txt after second merge = %q; want %q", got, resolved)
+	}
+	if got := env.stderr.String(); !strings.Contains(got, "resolution reused for foo.txt") {
+		t.Errorf("stderr = %q; want to contain notice about foo.txt", got)
+	}
+
+	// Forgetting the resolution restores the conflict on the next merge.
+	if _, err := env.gg(ctx, env.root.String(), "resolve", "--forget", "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.AbortMerge(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "merge", "feature"); err == nil {
+		t.Fatal("third merge did not return error")
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(got, "<<<<<<<") {
+		t.Errorf("foo.txt after forgetting resolution = %q; want conflict markers", got)
+	}
+}
+
+func TestMerge_Message(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "feature", git.BranchOptions{Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "commit", "--quiet", "-m", "Add foo\n\nFixes #12"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("baz.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "baz.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "commit", "--quiet", "-m", "Add baz"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.CheckoutBranch(ctx, "main", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("bar.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "merge", "--log", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := env.root.ReadFile(".git/MERGE_MSG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "Merge branch 'feature' into main\n" +
+		"\n" +
+		"References: #12\n" +
+		"\n" +
+		"* Add baz\n" +
+		"* Add foo\n"
+	if got != want {
+		t.Errorf("MERGE_MSG = %q; want %q", got, want)
+	}
+}
+
+func TestFormatMergeMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		tmpl     string
+		refs     []string
+		subjects []string
+		logLimit int
+		want     string
+	}{
+		{
+			name: "Default",
+			tmpl: defaultMergeTemplate,
+			want: "Merge branch 'feature' into main\n",
+		},
+		{
+			name:     "LogAndRefs",
+			tmpl:     defaultMergeTemplate,
+			refs:     []string{"#1", "org/repo#2", "#1"},
+			subjects: []string{"Third", "Second", "First"},
+			logLimit: 2,
+			want: "Merge branch 'feature' into main\n" +
+				"\n" +
+				"References: #1, org/repo#2\n" +
+				"\n" +
+				"* Third\n" +
+				"* Second\n" +
+				"  ...\n",
+		},
+		{
+			name:     "NoLog",
+			tmpl:     defaultMergeTemplate,
+			subjects: []string{"First"},
+			want:     "Merge branch 'feature' into main\n",
+		},
+		{
+			name:     "Custom",
+			tmpl:     "{target}: merge {source}\n\n{log}\n\nReviewed-by: Alice",
+			subjects: []string{"First"},
+			logLimit: 20,
+			want:     "main: merge branch 'feature'\n\n* First\n\nReviewed-by: Alice\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := formatMergeMessage(test.tmpl, "branch 'feature'", "main", test.refs, test.subjects, test.logLimit)
+			if got != test.want {
+				t.Errorf("formatMergeMessage(...) = %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIssueReferences(t *testing.T) {
+	got := issueReferences("Fix the thing (#34)\n\nFixes #12 and gg-scm/gg#56.\nNot an issue: a#7 or #x.")
+	want := []string{"#34", "#12", "gg-scm/gg#56"}
+	if !slices.Equal(got, want) {
+		t.Errorf("issueReferences(...) = %q; want %q", got, want)
+	}
+}
+
+// setupMergeInto creates a repository with main and feature branches
+// that both start from a commit of foo.txt. The feature branch is checked
+// out. mainFiles and featureFiles are the files that each branch's
+// commit writes.
+func setupMergeInto(ctx context.Context, env *testEnv, mainFiles, featureFiles map[string]string) error {
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		return err
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "Hello\n")); err != nil {
+		return err
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		return err
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		return err
+	}
+	commitFiles := func(files map[string]string, msg string) error {
+		if len(files) == 0 {
+			return nil
+		}
+		var names []string
+		for name, content := range files {
+			if err := env.root.Apply(filesystem.Write(name, content)); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		if err := env.addFiles(ctx, names...); err != nil {
+			return err
+		}
+		return env.git.Commit(ctx, msg, git.CommitOptions{})
+	}
+	if err := env.git.NewBranch(ctx, "feature", git.BranchOptions{}); err != nil {
+		return err
+	}
+	if err := commitFiles(mainFiles, "main change"); err != nil {
+		return err
+	}
+	if err := env.git.CheckoutBranch(ctx, "feature", git.CheckoutOptions{}); err != nil {
+		return err
+	}
+	return commitFiles(featureFiles, "feature change")
+}
+
+func TestMerge_IntoFastForward(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupMergeInto(ctx, env, nil, map[string]string{"bar.txt": "feature\n"}); err != nil {
+		t.Fatal(err)
+	}
+	feature, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "merge", "--into", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "fast-forwarded main") {
+		t.Errorf("output = %q; want to mention fast-forwarding main", out)
+	}
+	mainRev, err := env.git.ParseRev(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mainRev.Commit != feature.Commit {
+		t.Errorf("main = %v; want %v", mainRev.Commit, feature.Commit)
+	}
+	if head, err := env.git.Head(ctx); err != nil {
+		t.Error(err)
+	} else if head.Ref != git.BranchRef("feature") {
+		t.Errorf("HEAD = %v; want refs/heads/feature", head.Ref)
+	}
+}
+
+func TestMerge_Into(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = setupMergeInto(ctx, env,
+		map[string]string{"baz.txt": "main\n"},
+		map[string]string{"bar.txt": "feature\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "merge-tree", "--write-tree", "HEAD", "HEAD"); err != nil {
+		t.Skip("git merge-tree --write-tree not supported:", err)
+	}
+	feature, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldMain, err := env.git.ParseRev(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "merge", "--into", "main"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []git.Hash{oldMain.Commit, feature.Commit}; !slices.Equal(info.Parents, want) {
+		t.Errorf("main parents = %v; want %v", info.Parents, want)
+	}
+	if got, want := info.Summary(), "Merge branch 'feature' into main"; got != want {
+		t.Errorf("merge summary = %q; want %q", got, want)
+	}
+	for _, name := range []git.TopPath{"foo.txt", "bar.txt", "baz.txt"} {
+		if err := objectExists(ctx, env.git, "main", name); err != nil {
+			t.Errorf("merge commit: %v", err)
+		}
+	}
+	if head, err := env.git.Head(ctx); err != nil {
+		t.Error(err)
+	} else if head.Ref != git.BranchRef("feature") || head.Commit != feature.Commit {
+		t.Errorf("HEAD = %v at %v; want refs/heads/feature at %v", head.Ref, head.Commit, feature.Commit)
+	}
+	if exists, err := env.root.Exists("baz.txt"); err != nil {
+		t.Error(err)
+	} else if exists {
+		t.Error("baz.txt appeared in the working copy")
+	}
+}
+
+func TestMerge_IntoConflict(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = setupMergeInto(ctx, env,
+		map[string]string{"foo.txt": "main\n"},
+		map[string]string{"foo.txt": "feature\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "merge-tree", "--write-tree", "HEAD", "HEAD"); err != nil {
+		t.Skip("git merge-tree --write-tree not supported:", err)
+	}
+	oldMain, err := env.git.ParseRev(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, env.root.String(), "merge", "--into", "main")
+	if err == nil {
+		t.Fatal("gg merge --into did not return an error")
+	}
+	if got := err.Error(); !strings.Contains(got, "foo.txt") {
+		t.Errorf("error = %q; want to mention foo.txt", got)
+	}
+	if mainRev, err := env.git.ParseRev(ctx, "main"); err != nil {
+		t.Error(err)
+	} else if mainRev.Commit != oldMain.Commit {
+		t.Errorf("main = %v; want unchanged %v", mainRev.Commit, oldMain.Commit)
+	}
+}
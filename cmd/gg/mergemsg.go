@@ -0,0 +1,286 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// mergeMessageHelp is the paragraph of merge's help text that describes
+// the generated commit message.
+const mergeMessageHelp = `
+
+	After the merge, the commit message that ` + "`gg commit`" + ` suggests
+	names the merged revision and the current branch, and lists any
+	issues and pull requests (like "#123") that the merged commits refer
+	to. ` + "`--log`" + ` adds the summaries of up to N merged commits (20 if N is
+	not given), as in ` + "`git merge --log`" + `. The merge.log Git setting
+	gives the default.
+
+	The message comes from the gg.merge.template setting, in which
+	"{source}", "{target}", "{refs}", and "{log}" are replaced with the
+	merged revision, the current branch, the references line, and the
+	list of commits. Lines left empty by a replacement are removed. The
+	default template is:
+
+	  Merge {source} into {target}
+
+	  {refs}
+
+	  {log}`
+
+// defaultMergeTemplate is the default value of gg.merge.template.
+const defaultMergeTemplate = "Merge {source} into {target}\n\n{refs}\n\n{log}"
+
+// defaultMergeLogLimit is the number of commits that merge --log lists
+// if no number is given, the same as Git's.
+const defaultMergeLogLimit = 20
+
+// mergeLogValue is the value of merge's --log flag: the maximum number
+// of merged commits to list. --log without a number means
+// defaultMergeLogLimit.
+type mergeLogValue int
+
+func (n *mergeLogValue) Set(s string) error {
+	limit, err := parseMergeLog(s)
+	if err != nil {
+		return err
+	}
+	*n = mergeLogValue(limit)
+	return nil
+}
+
+func (n mergeLogValue) Get() interface{} {
+	return int(n)
+}
+
+func (n mergeLogValue) String() string {
+	return strconv.Itoa(int(n))
+}
+
+func (n mergeLogValue) IsBoolFlag() bool { return true }
+
+// parseMergeLog parses a boolean or a number of commits, as accepted by
+// --log and the merge.log setting.
+func parseMergeLog(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on":
+		return defaultMergeLogLimit, nil
+	case "false", "no", "off", "":
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("%q is not a boolean or a number of commits", s)
+	}
+	return limit, nil
+}
+
+// writeMergeMessage replaces the MERGE_MSG file of a merge in progress
+// with a message generated from gg.merge.template. rev is the revision
+// the user asked to merge. Comments that Git added to the message, like
+// the list of conflicts, are kept.
+func writeMergeMessage(ctx context.Context, g *git.Git, cfg *git.Config, rev string, logLimit int) error {
+	gitDir, err := g.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "MERGE_HEAD")); err != nil {
+		// Nothing to commit, like when the revision was already merged.
+		return nil
+	}
+	target := "HEAD"
+	if ref, err := g.HeadRef(ctx); err == nil && ref.IsBranch() {
+		target = ref.Branch()
+	}
+	msg, err := mergeMessage(ctx, g, cfg, rev, target, "HEAD..MERGE_HEAD", logLimit)
+	if err != nil {
+		return err
+	}
+
+	msgPath := filepath.Join(gitDir, "MERGE_MSG")
+	if old, err := os.ReadFile(msgPath); err == nil {
+		commentChar, err := cfg.CommentChar()
+		if err != nil {
+			return err
+		}
+		msg += gitMergeComments(string(old), commentChar)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.WriteFile(msgPath, []byte(msg), 0o666); err != nil {
+		return fmt.Errorf("write merge message: %w", err)
+	}
+	return nil
+}
+
+// mergeMessage returns the message generated from gg.merge.template for
+// merging rev into the branch named target. merged is the git log range
+// of the commits being merged, like "HEAD..MERGE_HEAD".
+func mergeMessage(ctx context.Context, g *git.Git, cfg *git.Config, rev, target, merged string, logLimit int) (string, error) {
+	out, err := g.Output(ctx, "log", "--no-merges", "--format=%s%x00%B%x00", merged, "--")
+	if err != nil {
+		return "", err
+	}
+	var subjects []string
+	var refs []string
+	if n := pullRequestNumber(ctx, g, rev); n != "" {
+		refs = append(refs, "#"+n)
+	}
+	fields := strings.Split(out, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		subjects = append(subjects, strings.TrimSpace(fields[i]))
+		refs = append(refs, issueReferences(fields[i+1])...)
+	}
+	tmpl := cfg.Value("gg.merge.template")
+	if tmpl == "" {
+		tmpl = defaultMergeTemplate
+	}
+	return formatMergeMessage(tmpl, mergeSourceName(ctx, g, rev), target, refs, subjects, logLimit), nil
+}
+
+// formatMergeMessage fills in a merge message template. subjects are the
+// summaries of the merged commits, newest first, of which up to logLimit
+// are listed.
+func formatMergeMessage(tmpl, source, target string, refs, subjects []string, logLimit int) string {
+	var refsLine string
+	if refs = dedupeStrings(refs); len(refs) > 0 {
+		refsLine = "References: " + strings.Join(refs, ", ")
+	}
+	log := new(strings.Builder)
+	if logLimit > 0 {
+		for i, s := range subjects {
+			if i >= logLimit {
+				log.WriteString("  ...\n")
+				break
+			}
+			fmt.Fprintf(log, "* %s\n", s)
+		}
+	}
+	msg := strings.NewReplacer(
+		"{source}", source,
+		"{target}", target,
+		"{refs}", refsLine,
+		"{log}", strings.TrimSuffix(log.String(), "\n"),
+	).Replace(tmpl)
+
+	// Remove the blank lines left by empty replacements.
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(msg, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// mergeSourceName describes rev for a merge message, like Git does:
+// "branch 'feature'" or "remote-tracking branch 'origin/main'".
+func mergeSourceName(ctx context.Context, g *git.Git, rev string) string {
+	ref, _ := g.Output(ctx, "rev-parse", "--symbolic-full-name", rev, "--")
+	ref = strings.TrimSpace(ref)
+	switch r := git.Ref(ref); {
+	case r.IsBranch():
+		return fmt.Sprintf("branch '%s'", r.Branch())
+	case r.IsTag():
+		return fmt.Sprintf("tag '%s'", r.Tag())
+	case strings.HasPrefix(ref, "refs/remotes/"):
+		return fmt.Sprintf("remote-tracking branch '%s'", strings.TrimPrefix(ref, "refs/remotes/"))
+	}
+	if n := pullRequestNumber(ctx, g, rev); n != "" {
+		return "pull request #" + n
+	}
+	if r, err := g.ParseRev(ctx, rev); err == nil {
+		return "commit " + r.Commit.Short()
+	}
+	return rev
+}
+
+var pullRequestRefPattern = regexp.MustCompile(`^refs/pull/([0-9]+)/head$`)
+
+// pullRequestNumber returns the GitHub pull request number of rev if it
+// names a pull request's head ref, as fetched by gg requestpull.
+func pullRequestNumber(ctx context.Context, g *git.Git, rev string) string {
+	ref, err := g.Output(ctx, "rev-parse", "--symbolic-full-name", rev, "--")
+	if err != nil {
+		return ""
+	}
+	m := pullRequestRefPattern.FindStringSubmatch(strings.TrimSpace(ref))
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+var issueReferencePattern = regexp.MustCompile(`(?:^|[\s(])((?:[\w.-]+/[\w.-]+)?#[0-9]+)\b`)
+
+// issueReferences returns the issue and pull request references like
+// "#123" or "owner/repo#123" in a commit message.
+func issueReferences(msg string) []string {
+	var refs []string
+	for _, m := range issueReferencePattern.FindAllStringSubmatch(msg, -1) {
+		refs = append(refs, m[1])
+	}
+	return refs
+}
+
+// gitMergeComments returns the comment lines at the end of a MERGE_MSG
+// written by Git, like the list of conflicted files.
+func gitMergeComments(msg, commentChar string) string {
+	lines := strings.SplitAfter(msg, "\n")
+	start := len(lines)
+	for start > 0 && (strings.HasPrefix(lines[start-1], commentChar) || strings.TrimSpace(lines[start-1]) == "") {
+		start--
+	}
+	comments := strings.Join(lines[start:], "")
+	if strings.TrimSpace(comments) == "" {
+		return ""
+	}
+	return "\n" + strings.TrimLeft(comments, "\n")
+}
+
+// dedupeStrings returns list with later duplicates removed.
+func dedupeStrings(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	n := 0
+	for _, s := range list {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		list[n] = s
+		n++
+	}
+	return list[:n]
+}
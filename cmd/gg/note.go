@@ -0,0 +1,168 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const noteSynopsis = "attach notes to commits without changing them"
+
+// notesHelp is appended to the help of commands that show or transfer
+// notes.
+const notesHelp = `
+
+	Notes are read from and written to the ref in ` + "`gg.notes.ref`" + `,
+	which defaults to ` + "`refs/notes/commits`" + `. If ` + "`gg.notes.sync`" + `
+	is true, then ` + "`gg pull`" + ` merges the source's notes into the local
+	notes and ` + "`gg push`" + ` pushes the local notes along with the branches.`
+
+// defaultNotesRef is the notes ref Git uses when none is configured.
+const defaultNotesRef = "refs/notes/commits"
+
+func note(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg note [show] [-r REV]\n"+
+		"gg note add [-f] [-m MSG] [-r REV]\n"+
+		"gg note remove [-r REV]", noteSynopsis+`
+
+	Notes are text attached to a commit, like review metadata or a build
+	ID, that are stored separately from the commit so that adding one
+	doesn't rewrite history. `+"`gg log`"+` shows them after the commit
+	message. This is a wrapper around git-notes(1).
+
+	`+"`gg note add`"+` opens an editor for the note unless `+"`-m`"+` is given.
+	It refuses to replace an existing note unless `+"`-f`"+` is given.
+	`+"`gg note show`"+` prints the note and `+"`gg note remove`"+` deletes it.`+notesHelp)
+	rev := f.String("r", git.Head.String(), "`rev`ision to annotate")
+	msg := f.String("m", "", "use text as the note `message`")
+	force := f.Bool("f", false, "replace an existing note")
+	f.Alias("f", "force")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 1 {
+		return usagef("note takes at most one subcommand")
+	}
+	sub := f.Arg(0)
+	if sub != "add" && (f.IsSet("m") || *force) {
+		return usagef("-m and -f can only be used with add")
+	}
+	if strings.HasPrefix(*rev, "-") {
+		return usagef("revision must not start with '-'")
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	r, err := cc.git.ParseRev(ctx, *rev)
+	if err != nil {
+		return err
+	}
+	notesArgs := []string{"notes", "--ref=" + notesRef(cfg)}
+	switch sub {
+	case "", "show":
+		return cc.interactiveGit(ctx, append(notesArgs, "show", r.Commit.String())...)
+	case "add":
+		notesArgs = append(notesArgs, "add")
+		if *force {
+			notesArgs = append(notesArgs, "-f")
+		}
+		if *msg != "" {
+			notesArgs = append(notesArgs, "-m", *msg)
+		}
+		return cc.interactiveGit(ctx, append(notesArgs, r.Commit.String())...)
+	case "remove":
+		return cc.interactiveGit(ctx, append(notesArgs, "remove", r.Commit.String())...)
+	default:
+		return usagef("unknown subcommand %q", sub)
+	}
+}
+
+// notesRef returns the full name of the notes ref in gg.notes.ref.
+// Like git notes --ref, a name that doesn't start with "refs/" is taken
+// to be under refs/notes/.
+func notesRef(cfg *git.Config) string {
+	ref := cfg.Value("gg.notes.ref")
+	switch {
+	case ref == "":
+		return defaultNotesRef
+	case strings.HasPrefix(ref, "refs/"):
+		return ref
+	case strings.HasPrefix(ref, "notes/"):
+		return "refs/" + ref
+	default:
+		return "refs/notes/" + ref
+	}
+}
+
+// syncNotes reports whether gg.notes.sync is on.
+func syncNotes(cfg *git.Config) (bool, error) {
+	if cfg.Value("gg.notes.sync") == "" {
+		return false, nil
+	}
+	sync, err := cfg.Bool("gg.notes.sync")
+	if err != nil {
+		return false, fmt.Errorf("gg.notes.sync: %w", err)
+	}
+	return sync, nil
+}
+
+// pullNotes fetches the notes ref from repo and merges it into the local
+// notes ref, concatenating notes that both sides changed. It does
+// nothing if repo has no notes.
+func pullNotes(ctx context.Context, cc *cmdContext, cfg *git.Config, repo string) (err error) {
+	ref := notesRef(cfg)
+	out, err := cc.git.Output(ctx, "ls-remote", "--", repo, ref)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(out) == "" {
+		return nil
+	}
+	tmpRef := "refs/notes/gg-pull/" + strings.TrimPrefix(ref, "refs/")
+	if err := cc.git.Run(ctx, "fetch", "--quiet", "--", repo, "+"+ref+":"+tmpRef); err != nil {
+		return err
+	}
+	defer func() {
+		if delErr := cc.git.Run(ctx, "update-ref", "-d", tmpRef); delErr != nil && err == nil {
+			err = delErr
+		}
+	}()
+	if _, err := cc.git.ParseRev(ctx, ref); err != nil {
+		// No local notes yet.
+		return cc.git.Run(ctx, "update-ref", "-m", "gg pull: notes", ref, tmpRef)
+	}
+	return cc.git.Run(ctx, "notes", "--ref="+ref, "merge", "--quiet", "--strategy=cat_sort_uniq", tmpRef)
+}
+
+// pushNotes pushes the local notes ref to dstRepo. It does nothing if
+// there are no local notes.
+func pushNotes(ctx context.Context, cc *cmdContext, cfg *git.Config, dstRepo string) error {
+	ref := notesRef(cfg)
+	if _, err := cc.git.ParseRev(ctx, ref); err != nil {
+		return nil
+	}
+	return cc.git.Run(ctx, "push", "--quiet", "--", dstRepo, ref+":"+ref)
+}
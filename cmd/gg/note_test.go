@@ -0,0 +1,135 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestNote(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.writeConfig([]byte("[gg \"notes\"]\nref = review\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "note", "add", "-m", "Reviewed-by: Alice"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.git.Output(ctx, "notes", "--ref=refs/notes/review", "show", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out, "Reviewed-by: Alice\n"; got != want {
+		t.Errorf("note in refs/notes/review = %q; want %q", got, want)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "note", "add", "-m", "Reviewed-by: Bob"); err == nil {
+		t.Error("note add without -f replaced an existing note")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "note", "add", "-f", "-m", "Reviewed-by: Bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	out2, err := env.gg(ctx, env.root.String(), "note", "show")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out2), "Reviewed-by: Bob\n"; got != want {
+		t.Errorf("gg note show = %q; want %q", got, want)
+	}
+	out2, err = env.gg(ctx, env.root.String(), "log", "-r", "HEAD", "-r", "^HEAD~")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out2), "Reviewed-by: Bob") {
+		t.Errorf("gg log output does not include note:\n%s", out2)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "note", "remove"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.git.Output(ctx, "notes", "--ref=refs/notes/review", "show", "HEAD"); err == nil {
+		t.Error("note still exists after gg note remove")
+	}
+}
+
+func TestNote_Sync(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--bare", "--quiet", "repoA", "remote.git"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.writeConfig([]byte("[gg \"notes\"]\nsync = true\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"repoB", "repoC"} {
+		if _, err := env.gg(ctx, env.root.String(), "clone", "remote.git", dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Push a commit and its note from repoB.
+	if err := env.root.Apply(filesystem.Write("repoB/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoB/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "repoB"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.FromSlash("repoB"), "note", "add", "-m", "Build: 42"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.FromSlash("repoB"), "push"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pull them into repoC.
+	if _, err := env.gg(ctx, env.root.FromSlash("repoC"), "pull", "-u"); err != nil {
+		t.Fatal(err)
+	}
+	gitC := env.git.WithDir(env.root.FromSlash("repoC"))
+	out, err := gitC.Output(ctx, "notes", "show", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out, "Build: 42\n"; got != want {
+		t.Errorf("note in repoC = %q; want %q", got, want)
+	}
+	if _, err := gitC.Output(ctx, "rev-parse", "--verify", "--quiet", "refs/notes/gg-pull/notes/commits"); err == nil {
+		t.Error("temporary notes ref left behind after pull")
+	}
+}
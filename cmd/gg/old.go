@@ -0,0 +1,323 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const oldSynopsis = "list, restore, or delete branches that were deleted upstream"
+
+// oldNamespace is the prefix of the refs that gg pull saves local
+// branches under when their upstream branch is deleted.
+const oldNamespace = "refs/gg-old/"
+
+// oldBranchTimesPath is the path, relative to the common Git directory, of
+// the file that records when each branch was saved under oldNamespace.
+// It is a JSON object that maps branch names to RFC 3339 times.
+const oldBranchTimesPath = "gg/old-branches.json"
+
+func old(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg old [list]\n"+
+		"gg old restore BRANCH\n"+
+		"gg old prune [BRANCH [...]]", oldSynopsis+`
+
+	When a branch is deleted on a remote, `+"`gg pull`"+` deletes the local
+	branch that tracked it if the local branch has no other commits, and
+	saves it as `+"`refs/gg-old/BRANCH`"+` so that it can be recovered.
+
+	`+"`gg old`"+` lists the saved branches with when they were saved.
+	`+"`gg old restore`"+` creates BRANCH again from the saved ref, and
+	`+"`gg old prune`"+` deletes the named saved branches, or all of them if
+	none are named.
+
+	If `+"`gg.oldBranchDays`"+` is set to a number of days, `+"`gg pull`"+`
+	deletes saved branches that are older than that.`)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	switch sub := f.Arg(0); sub {
+	case "", "list":
+		if f.NArg() > 1 {
+			return usagef("list takes no arguments")
+		}
+		return listOldBranches(ctx, cc)
+	case "restore":
+		if f.NArg() != 2 {
+			return usagef("restore takes a single BRANCH")
+		}
+		return restoreOldBranch(ctx, cc, f.Arg(1))
+	case "prune":
+		return pruneOldBranches(ctx, cc.git, f.Args()[1:])
+	default:
+		return usagef("unknown subcommand %q", sub)
+	}
+}
+
+// An oldBranch is a branch saved under oldNamespace.
+type oldBranch struct {
+	name    string
+	commit  string // abbreviated hash
+	summary string
+	saved   time.Time // zero if not known
+}
+
+// readOldBranches returns the branches saved under oldNamespace sorted by
+// name.
+func readOldBranches(ctx context.Context, g *git.Git) ([]*oldBranch, error) {
+	out, err := g.Output(ctx, "for-each-ref", "--format=%(refname)%00%(objectname:short)%00%(subject)", oldNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("list old branches: %w", err)
+	}
+	times, err := readOldBranchTimes(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	var branches []*oldBranch
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		ref, rest, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		b := &oldBranch{name: strings.TrimPrefix(ref, oldNamespace)}
+		b.commit, b.summary, _ = strings.Cut(rest, "\x00")
+		b.saved = times[b.name]
+		branches = append(branches, b)
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].name < branches[j].name
+	})
+	return branches, nil
+}
+
+func listOldBranches(ctx context.Context, cc *cmdContext) error {
+	branches, err := readOldBranches(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if len(branches) == 0 {
+		_, err := fmt.Fprintln(cc.stdout, "no branches were deleted upstream")
+		return err
+	}
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, b := range branches {
+		saved := "unknown"
+		if !b.saved.IsZero() {
+			saved = b.saved.Local().Format("2006-01-02")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", b.name, b.commit, saved, b.summary)
+	}
+	return tw.Flush()
+}
+
+func restoreOldBranch(ctx context.Context, cc *cmdContext, name string) error {
+	if strings.HasPrefix(name, "-") {
+		return usagef("branch name cannot start with '-'")
+	}
+	ref := git.Ref(oldNamespace + name)
+	rev, err := cc.git.ParseRev(ctx, ref.String())
+	if err != nil {
+		return fmt.Errorf("no saved branch %q (see 'gg old list')", name)
+	}
+	if _, err := cc.git.ParseRev(ctx, git.BranchRef(name).String()); err == nil {
+		return fmt.Errorf("branch %s already exists", name)
+	}
+	err = cc.git.MutateRefs(ctx, map[git.Ref]git.RefMutation{
+		git.BranchRef(name): git.SetRef(rev.Commit.String()),
+		ref:                 git.DeleteRefIfMatches(rev.Commit.String()),
+	})
+	if err != nil {
+		return err
+	}
+	if err := forgetOldBranchTimes(ctx, cc.git, []string{name}); err != nil {
+		fmt.Fprintln(cc.stderr, "gg:", err)
+	}
+	_, err = fmt.Fprintf(cc.stderr, "gg: restored branch %s at %v\n", name, rev.Commit.Short())
+	return err
+}
+
+// pruneOldBranches deletes the named saved branches, or all of them if
+// names is empty.
+func pruneOldBranches(ctx context.Context, g *git.Git, names []string) error {
+	if len(names) == 0 {
+		branches, err := readOldBranches(ctx, g)
+		if err != nil {
+			return err
+		}
+		for _, b := range branches {
+			names = append(names, b.name)
+		}
+	}
+	muts := make(map[git.Ref]git.RefMutation, len(names))
+	for _, name := range names {
+		ref := git.Ref(oldNamespace + name)
+		if _, err := g.ParseRev(ctx, ref.String()); err != nil {
+			return fmt.Errorf("no saved branch %q (see 'gg old list')", name)
+		}
+		muts[ref] = git.DeleteRef()
+	}
+	if err := g.MutateRefs(ctx, muts); err != nil {
+		return err
+	}
+	return forgetOldBranchTimes(ctx, g, names)
+}
+
+// expireOldBranches deletes the saved branches that were saved more than
+// the number of days given by gg.oldBranchDays before now. Branches saved
+// before gg recorded times are treated as saved now. It returns the names
+// of the deleted branches.
+func expireOldBranches(ctx context.Context, g *git.Git, cfg *git.Config, now time.Time) ([]string, error) {
+	setting := cfg.Value("gg.oldBranchDays")
+	if setting == "" {
+		return nil, nil
+	}
+	days, err := strconv.Atoi(setting)
+	if err != nil || days < 0 {
+		return nil, fmt.Errorf("gg.oldBranchDays: %q is not a number of days", setting)
+	}
+	if days == 0 {
+		return nil, nil
+	}
+	branches, err := readOldBranches(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	var expired []string
+	var unknown []string
+	for _, b := range branches {
+		switch {
+		case b.saved.IsZero():
+			unknown = append(unknown, b.name)
+		case now.Sub(b.saved) > time.Duration(days)*24*time.Hour:
+			expired = append(expired, b.name)
+		}
+	}
+	if len(unknown) > 0 {
+		if err := recordOldBranchTimes(ctx, g, unknown, now); err != nil {
+			return nil, err
+		}
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+	if err := pruneOldBranches(ctx, g, expired); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// writeSavedBranchesReport tells the user which branches gg pull saved
+// under oldNamespace and how to manage them.
+func writeSavedBranchesReport(w io.Writer, saved, expired []string) {
+	if len(saved) > 0 {
+		sort.Strings(saved)
+		fmt.Fprintln(w, "gg: branches deleted upstream were saved under refs/gg-old/:")
+		for _, name := range saved {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+		fmt.Fprintln(w, "gg: restore one with 'gg old restore BRANCH' or delete them with 'gg old prune'")
+	}
+	if len(expired) > 0 {
+		fmt.Fprintf(w, "gg: deleted saved branches older than gg.oldBranchDays: %s\n", strings.Join(expired, ", "))
+	}
+}
+
+func oldBranchTimesFile(ctx context.Context, g *git.Git) (string, error) {
+	commonDir, err := g.CommonDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, filepath.FromSlash(oldBranchTimesPath)), nil
+}
+
+func readOldBranchTimes(ctx context.Context, g *git.Git) (map[string]time.Time, error) {
+	path, err := oldBranchTimesFile(ctx, g)
+	if err != nil {
+		return nil, fmt.Errorf("read old branch times: %w", err)
+	}
+	times := make(map[string]time.Time)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return times, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read old branch times: %w", err)
+	}
+	if err := json.Unmarshal(data, &times); err != nil {
+		return nil, fmt.Errorf("read old branch times: %w", err)
+	}
+	return times, nil
+}
+
+// updateOldBranchTimes calls f on the recorded times and saves the result.
+func updateOldBranchTimes(ctx context.Context, g *git.Git, f func(times map[string]time.Time)) error {
+	times, err := readOldBranchTimes(ctx, g)
+	if err != nil {
+		return err
+	}
+	f(times)
+	path, err := oldBranchTimesFile(ctx, g)
+	if err != nil {
+		return fmt.Errorf("write old branch times: %w", err)
+	}
+	data, err := json.Marshal(times)
+	if err != nil {
+		return fmt.Errorf("write old branch times: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return fmt.Errorf("write old branch times: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o666); err != nil {
+		return fmt.Errorf("write old branch times: %w", err)
+	}
+	return nil
+}
+
+// recordOldBranchTimes records that the named branches were saved at t.
+func recordOldBranchTimes(ctx context.Context, g *git.Git, names []string, t time.Time) error {
+	return updateOldBranchTimes(ctx, g, func(times map[string]time.Time) {
+		for _, name := range names {
+			times[name] = t.UTC().Truncate(time.Second)
+		}
+	})
+}
+
+// forgetOldBranchTimes removes the named branches from the recorded times.
+func forgetOldBranchTimes(ctx context.Context, g *git.Git, names []string) error {
+	return updateOldBranchTimes(ctx, g, func(times map[string]time.Time) {
+		for _, name := range names {
+			delete(times, name)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOld(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits, err := setupPullTest(ctx, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoBPath := env.root.FromSlash("repoB")
+	gitB := env.git.WithDir(repoBPath)
+	if _, err := env.gg(ctx, repoBPath, "pull"); err != nil {
+		t.Fatal(err)
+	}
+	if got := env.stderr.String(); !strings.Contains(got, "  delbranch\n") || !strings.Contains(got, "gg old restore") {
+		t.Errorf("gg pull stderr = %q; want to list delbranch and mention gg old restore", got)
+	}
+
+	out, err := env.gg(ctx, repoBPath, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(string(out))
+	today := time.Now().Format("2006-01-02")
+	if len(fields) < 3 || fields[0] != "delbranch" || !strings.HasPrefix(commits.originalMain.String(), fields[1]) || fields[2] != today {
+		t.Errorf("gg old = %q; want delbranch saved today at %v", out, commits.originalMain)
+	}
+
+	if _, err := env.gg(ctx, repoBPath, "old", "restore", "delbranch"); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := gitB.ParseRev(ctx, "refs/heads/delbranch"); err != nil {
+		t.Error(err)
+	} else if r.Commit != commits.originalMain {
+		t.Errorf("delbranch = %v after restore; want %v", r.Commit, commits.originalMain)
+	}
+	if _, err := gitB.ParseRev(ctx, "refs/gg-old/delbranch"); err == nil {
+		t.Error("refs/gg-old/delbranch still exists after restore")
+	}
+	if _, err := env.gg(ctx, repoBPath, "old", "restore", "delbranch"); err == nil {
+		t.Error("restoring delbranch a second time succeeded")
+	}
+
+	if err := gitB.Run(ctx, "update-ref", "refs/gg-old/stale", commits.originalMain.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, repoBPath, "old", "prune"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gitB.ParseRev(ctx, "refs/gg-old/stale"); err == nil {
+		t.Error("refs/gg-old/stale still exists after prune")
+	}
+}
+
+func TestExpireOldBranches(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"expired", "recent", "unknown"} {
+		if err := env.git.Run(ctx, "update-ref", oldNamespace+name, head.Commit.String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC)
+	if err := recordOldBranchTimes(ctx, env.git, []string{"expired"}, now.AddDate(0, 0, -10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordOldBranchTimes(ctx, env.git, []string{"recent"}, now.AddDate(0, 0, -3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "config", "gg.oldBranchDays", "7"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := env.git.ReadConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := expireOldBranches(ctx, env.git, cfg, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"expired"}, expired); diff != "" {
+		t.Errorf("expired branches (-want +got):\n%s", diff)
+	}
+	branches, err := readOldBranches(ctx, env.git)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]time.Time)
+	for _, b := range branches {
+		got[b.name] = b.saved
+	}
+	want := map[string]time.Time{
+		"recent":  now.AddDate(0, 0, -3),
+		"unknown": now,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("saved branches after expiry (-want +got):\n%s", diff)
+	}
+	if _, err := env.git.ParseRev(ctx, git.Ref(oldNamespace+"expired").String()); err == nil {
+		t.Error("expired branch still exists")
+	}
+}
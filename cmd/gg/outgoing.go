@@ -0,0 +1,209 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const (
+	outgoingSynopsis = "show commits that are not in the upstream branch"
+	incomingSynopsis = "show upstream commits that the current branch lacks"
+)
+
+func outgoing(ctx context.Context, cc *cmdContext, args []string) error {
+	return compareWithUpstream(ctx, cc, "outgoing", args)
+}
+
+func incoming(ctx context.Context, cc *cmdContext, args []string) error {
+	return compareWithUpstream(ctx, cc, "incoming", args)
+}
+
+// compareWithUpstream runs gg outgoing or gg incoming.
+func compareWithUpstream(ctx context.Context, cc *cmdContext, name string, args []string) error {
+	synopsis, description := outgoingSynopsis, `
+
+	Lists the commits on the current branch (or REV) that are not in its
+	upstream branch, newest first. If the current branch has no upstream,
+	it is compared with the remote-tracking branch for the name it is
+	pushed as (see `+"`gg push --help`"+`), if that has been fetched.`
+	if name == "incoming" {
+		synopsis, description = incomingSynopsis, `
+
+	Lists the commits in the current branch's upstream branch that are not
+	in the current branch (or REV), newest first.`
+	}
+	f := flag.NewFlagSet(true, "gg "+name+" [--upstream [--fetch=0]] [-r REV] [REPO]", synopsis+description+`
+
+	With `+"`--upstream`"+`, the comparison is with the default branch of the
+	repository that a fork was made from instead. That is the remote the
+	current branch pulls from if it pushes somewhere else, the remote
+	named "upstream" if there is one, or origin. The default branch is
+	fetched first unless `+"`--fetch=0`"+` is given.
+
+	With a REPO, the comparison is with the branch of the same name as the
+	current branch in that repository, which is fetched first. REPO can be
+	a remote, a URL, or a path alias defined by a `+"`gg.paths.NAME`"+`
+	configuration variable (see `+"`gg paths`"+`).`)
+	upstreamRepo := f.Bool("upstream", false, "compare with the default branch of the upstream repository")
+	fetch := f.Bool("fetch", true, "with --upstream, fetch from the upstream repository first")
+	rev := f.String("r", git.Head.String(), "`rev`ision to compare")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 1 {
+		return usagef("can't pass multiple repositories")
+	}
+	if f.NArg() == 1 && *upstreamRepo {
+		return usagef("can't pass both --upstream and a repository")
+	}
+	if f.IsSet("fetch") && !*upstreamRepo {
+		return usagef("--fetch requires --upstream")
+	}
+	if strings.HasPrefix(*rev, "-") {
+		return usagef("revision cannot start with '-'")
+	}
+	target := "@{upstream}"
+	if repoArg := f.Arg(0); repoArg != "" {
+		if strings.HasPrefix(repoArg, "-") {
+			return usagef("repository cannot start with '-'")
+		}
+		cfg, err := cc.git.ReadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		branch := currentBranch(ctx, cc)
+		if branch == "" {
+			return fmt.Errorf("no branch currently checked out to compare with %s", repoArg)
+		}
+		repo := resolvePathAlias(cfg, repoArg)
+		if err := cc.interactiveGit(ctx, "fetch", "--quiet", repo, git.BranchRef(branch).String()); err != nil {
+			return err
+		}
+		target = "FETCH_HEAD"
+	} else if *upstreamRepo {
+		cfg, err := cc.git.ReadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		remote := forkUpstreamRemote(cfg, currentBranch(ctx, cc))
+		if *fetch {
+			if err := cc.interactiveGit(ctx, "fetch", "--quiet", remote); err != nil {
+				return err
+			}
+		}
+		branch, err := remoteDefaultBranch(ctx, cc.git, remote)
+		if err != nil {
+			return err
+		}
+		if branch == "" {
+			return fmt.Errorf("default branch of %s is not known; run 'gg remote set-default %s'", remote, remote)
+		}
+		target = "refs/remotes/" + remote + "/" + branch
+	} else if name == "outgoing" && *rev == git.Head.String() {
+		pushed, err := pushedTrackingRef(ctx, cc)
+		if err != nil {
+			return err
+		}
+		if pushed != "" {
+			target = pushed.String()
+		}
+	}
+	if _, err := cc.git.ParseRev(ctx, target); err != nil {
+		return err
+	}
+	revs := []string{*rev, "^" + target}
+	if name == "incoming" {
+		revs = []string{target, "^" + *rev}
+	}
+	out, err := cc.git.Output(ctx, append(append([]string{"log", "--format=%h %s"}, revs...), "--")...)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err := fmt.Fprintf(cc.stdout, "no %s commits\n", name)
+		return err
+	}
+	_, err = fmt.Fprint(cc.stdout, out)
+	return err
+}
+
+// forkUpstreamRemote returns the remote for the repository that a fork
+// was made from: the remote that branch pulls from if it pushes to a
+// different remote, the remote named "upstream" if there is one, or
+// origin.
+func forkUpstreamRemote(cfg *git.Config, branch string) string {
+	if branch != "" {
+		pull := cfg.Value("branch." + branch + ".remote")
+		push, err := inferPushRepo(cfg, branch)
+		if pull != "" && pull != "." && err == nil && pull != push {
+			return pull
+		}
+	}
+	if _, ok := cfg.ListRemotes()["upstream"]; ok {
+		return "upstream"
+	}
+	return "origin"
+}
+
+// pushedTrackingRef returns the remote-tracking branch for where the
+// current branch is pushed, if the branch has no upstream and the
+// remote-tracking branch exists. Otherwise, it returns the empty string.
+func pushedTrackingRef(ctx context.Context, cc *cmdContext) (git.Ref, error) {
+	branch := currentBranch(ctx, cc)
+	if branch == "" {
+		return "", nil
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if cfg.Value("branch."+branch+".merge") != "" {
+		return "", nil
+	}
+	remoteName, err := inferPushRepo(cfg, branch)
+	if err != nil {
+		return "", nil
+	}
+	remote := cfg.ListRemotes()[remoteName]
+	if remote == nil {
+		return "", nil
+	}
+	dst, err := remoteBranchName(cfg, cc.env, branch)
+	if err != nil {
+		return "", err
+	}
+	tracking := remote.MapFetch(git.BranchRef(dst))
+	if tracking == "" {
+		return "", nil
+	}
+	if _, err := cc.git.ParseRev(ctx, tracking.String()); err != nil {
+		return "", nil
+	}
+	if dst != branch {
+		fmt.Fprintf(cc.stderr, "gg: outgoing: %s → %s/%s\n", branch, remoteName, dst)
+	}
+	return tracking, nil
+}
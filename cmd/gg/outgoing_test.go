@@ -0,0 +1,92 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestOutgoingIncoming(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "upstream"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "upstream", "fork"); err != nil {
+		t.Fatal(err)
+	}
+	forkGit := env.git.WithDir(env.root.FromSlash("fork"))
+
+	// Add a commit to each repository.
+	if err := env.root.Apply(filesystem.Write("upstream/upstream.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "upstream/upstream.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.WithDir(env.root.FromSlash("upstream")).Run(ctx, "commit", "--quiet", "-m", "Upstream change"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("fork/fork.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "fork/fork.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := forkGit.Run(ctx, "commit", "--quiet", "-m", "Fork change"); err != nil {
+		t.Fatal(err)
+	}
+	forkHead, err := forkGit.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.FromSlash("fork"), "outgoing", "--upstream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCommitLine(string(out), forkHead.Commit.String(), "Fork change") {
+		t.Errorf("gg outgoing --upstream = %q; want one line for %v", out, forkHead.Commit)
+	}
+
+	out, err = env.gg(ctx, env.root.FromSlash("fork"), "incoming", "--upstream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstreamHead, err := forkGit.ParseRev(ctx, "origin/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCommitLine(string(out), upstreamHead.Commit.String(), "Upstream change") {
+		t.Errorf("gg incoming --upstream = %q; want one line for %v", out, upstreamHead.Commit)
+	}
+}
+
+// isCommitLine reports whether out is a single line with an abbreviation
+// of hash followed by summary.
+func isCommitLine(out, hash, summary string) bool {
+	short, rest, ok := strings.Cut(strings.TrimSuffix(out, "\n"), " ")
+	return ok && short != "" && strings.HasPrefix(hash, short) && rest == summary && strings.Count(out, "\n") == 1
+}
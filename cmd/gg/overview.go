@@ -0,0 +1,170 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+// overviewMaxFiles is the number of changed files that the overview
+// lists before summarizing the rest, so that it fits on one screen.
+const overviewMaxFiles = 10
+
+// defaultCommand runs when gg is invoked without a command. Inside a
+// working copy, it prints an overview of the repository unless the
+// default-command setting is "help". Otherwise, it prints the list of
+// commands.
+func defaultCommand(ctx context.Context, cc *cmdContext, globalFlags *flag.FlagSet) error {
+	if _, err := cc.git.WorkTree(ctx); err != nil {
+		globalFlags.Help(cc.stdout)
+		return nil
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	switch v := cfg.Value("gg.defaultCommand"); v {
+	case "", "overview":
+		return overview(ctx, cc, cfg)
+	case "help":
+		globalFlags.Help(cc.stdout)
+		return nil
+	default:
+		fmt.Fprintf(cc.stderr, "gg: warning: unknown gg.defaultCommand %q; showing help\n", v)
+		globalFlags.Help(cc.stdout)
+		return nil
+	}
+}
+
+// overview prints the current branch, how it compares to its upstream,
+// the changed files, and the commands that are likely to be run next.
+func overview(ctx context.Context, cc *cmdContext, cfg *git.Config) error {
+	sb := new(strings.Builder)
+	summary, err := readStatusSummary(ctx, cc.git, nil)
+	if err != nil {
+		return err
+	}
+	b := summary.branch
+	var ahead, behind int
+	var upstream string
+	if b.Name != "" && b.Commit != "" && b.Upstream != "" && !b.UpstreamGone {
+		ahead, behind, upstream = b.Ahead, b.Behind, b.Upstream
+	}
+	sb.WriteString(describeStatusBranch(summary) + "\n")
+
+	op, err := readOperationState(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if op != nil {
+		fmt.Fprintf(sb, "%s in progress (see 'gg state')\n", op.kind)
+	}
+
+	st := summary.entries
+	pf, err := new(pathStyleFlags).formatter(ctx, cc, cfg)
+	if err != nil {
+		return err
+	}
+	var changed, untracked bool
+	for i, ent := range st {
+		letter := overviewStatusLetter(ent.Code)
+		if letter == '?' {
+			untracked = true
+		} else {
+			changed = true
+		}
+		if i == overviewMaxFiles {
+			fmt.Fprintf(sb, "... and %d more (see 'gg status')\n", len(st)-i)
+			continue
+		}
+		if i < overviewMaxFiles {
+			fmt.Fprintf(sb, "%c %s\n", letter, pf.format(ent.Name))
+		}
+	}
+	if len(st) == 0 {
+		sb.WriteString("no changes\n")
+	}
+
+	sb.WriteString("\n")
+	suggest := func(cmd, desc string) {
+		fmt.Fprintf(sb, "  %-12s %s\n", cmd, desc)
+	}
+	switch {
+	case op != nil:
+		suggest("gg state", "show how to continue or cancel the "+op.kind)
+	case changed:
+		suggest("gg diff", "show the changes")
+		suggest("gg commit", "record the changes")
+	}
+	if untracked {
+		suggest("gg add", "track new files")
+	}
+	if ahead > 0 && behind == 0 {
+		suggest("gg push", "send "+countCommits(ahead)+" to "+upstream)
+	}
+	if behind > 0 {
+		if ahead == 0 {
+			suggest("gg pull -u", "update to "+upstream)
+		} else {
+			suggest("gg rebase", "move "+countCommits(ahead)+" onto "+upstream)
+		}
+	}
+	suggest("gg log", "show history")
+	suggest("gg help", "list all commands")
+	_, err = io.WriteString(cc.stdout, sb.String())
+	return err
+}
+
+// describeAheadBehind describes how a branch compares to its upstream.
+func describeAheadBehind(ahead, behind int, upstream string) string {
+	switch {
+	case ahead == 0 && behind == 0:
+		return "up to date with " + upstream
+	case behind == 0:
+		return countCommits(ahead) + " ahead of " + upstream
+	case ahead == 0:
+		return countCommits(behind) + " behind " + upstream
+	default:
+		return fmt.Sprintf("%s ahead and %s behind %s", countCommits(ahead), countCommits(behind), upstream)
+	}
+}
+
+// overviewStatusLetter returns the letter that gg status uses for code.
+func overviewStatusLetter(code git.StatusCode) byte {
+	switch {
+	case code.IsUnmerged():
+		return 'U'
+	case code.IsModified():
+		return 'M'
+	case code.IsAdded(), code.IsCopied(), code.IsRenamed():
+		return 'A'
+	case code.IsRemoved():
+		return 'R'
+	case code.IsMissing():
+		return '!'
+	case code.IsUntracked():
+		return '?'
+	default:
+		return ' '
+	}
+}
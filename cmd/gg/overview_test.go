@@ -0,0 +1,105 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestOverview(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repo1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "repo1", "repo2"); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("repo2/foo.txt", dummyContent),
+		filesystem.Write("repo2/bar.txt", dummyContent),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	git2 := env.git.WithDir(env.root.FromSlash("repo2"))
+	if err := git2.Add(ctx, []git.Pathspec{"foo.txt"}, git.AddOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := git2.Commit(ctx, "add foo.txt", git.CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.FromSlash("repo2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"on branch main, 1 commit ahead of origin/main\n",
+		"? bar.txt\n",
+		"gg add",
+		"gg push",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("gg output = %q; want to contain %q", out, want)
+		}
+	}
+	if strings.Contains(string(out), "basic commands") {
+		t.Errorf("gg output = %q; want overview instead of help", out)
+	}
+}
+
+func TestOverview_Help(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Outside a repository, gg prints the list of commands.
+	out, err := env.gg(ctx, env.root.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "basic commands") {
+		t.Errorf("gg output outside repository = %q; want help", out)
+	}
+
+	if _, err := env.gg(ctx, env.root.FromSlash("repo"), "config", "default-command", "help"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, env.root.FromSlash("repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "basic commands") {
+		t.Errorf("gg output with default-command=help = %q; want help", out)
+	}
+}
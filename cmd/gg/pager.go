@@ -0,0 +1,152 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/sigterm"
+	"gg-scm.io/tool/internal/terminal"
+)
+
+// filteredGit runs git with the given arguments, passing each line of its
+// output through filter. Like Git, it shows the output in the user's pager
+// if stdout is a terminal. Since Git's output is not a terminal, callers
+// must pass any color flags explicitly.
+func (cc *cmdContext) filteredGit(ctx context.Context, filter func(line string) string, args ...string) error {
+	out := cc.stdout
+	var pagerIn io.WriteCloser
+	var waitPager func() error
+	if terminal.IsTerminal(cc.stdout) {
+		pager, err := cc.git.Output(ctx, "var", "GIT_PAGER")
+		if err != nil {
+			return err
+		}
+		pager = strings.TrimSuffix(pager, "\n")
+		if pager != "" && pager != "cat" {
+			c, err := bashCommand(cc.git.Exe(), pager)
+			if err != nil {
+				return fmt.Errorf("start pager: %w", err)
+			}
+			c.Dir = cc.dir
+			c.Stdout = cc.stdout
+			c.Stderr = cc.stderr
+			c.Env = pagerEnv(cc.env)
+			pagerIn, err = c.StdinPipe()
+			if err != nil {
+				return fmt.Errorf("start pager: %w", err)
+			}
+			waitPager, err = sigterm.Start(ctx, c)
+			if err != nil {
+				return fmt.Errorf("start pager: %w", err)
+			}
+			out = pagerIn
+		}
+	}
+
+	fw := &lineFilterWriter{w: out, filter: filter}
+	gitErr := cc.git.Runner().RunGit(ctx, &git.Invocation{
+		Dir:    cc.dir,
+		Args:   args,
+		Stdin:  cc.stdin,
+		Stdout: fw,
+		Stderr: cc.stderr,
+	})
+	flushErr := fw.flush()
+	if waitPager != nil {
+		pagerIn.Close()
+		if err := waitPager(); err != nil {
+			return fmt.Errorf("pager: %w", err)
+		}
+		if fw.err != nil {
+			// The user quit the pager before reading all of the output.
+			return nil
+		}
+	}
+	if gitErr != nil {
+		return fmt.Errorf("git %s: %w", args[0], gitErr)
+	}
+	return flushErr
+}
+
+// pagerEnv returns the environment for a pager subprocess. Like Git, it
+// configures less and lv to pass through color escape sequences unless
+// the user has configured them.
+func pagerEnv(env []string) []string {
+	env = append([]string(nil), env...)
+	for _, def := range []string{"LESS=FRX", "LV=-c"} {
+		name := def[:strings.IndexByte(def, '=')+1]
+		found := false
+		for _, kv := range env {
+			if strings.HasPrefix(kv, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			env = append(env, def)
+		}
+	}
+	return env
+}
+
+// A lineFilterWriter passes each line written to it through a filter
+// function before writing it to an underlying writer.
+type lineFilterWriter struct {
+	w      io.Writer
+	filter func(line string) string
+	buf    []byte
+	err    error // first error from w
+}
+
+func (fw *lineFilterWriter) Write(p []byte) (int, error) {
+	if fw.err != nil {
+		return 0, fw.err
+	}
+	fw.buf = append(fw.buf, p...)
+	for {
+		i := bytes.IndexByte(fw.buf, '\n')
+		if i == -1 {
+			break
+		}
+		line := fw.filter(string(fw.buf[:i]))
+		fw.buf = fw.buf[i+1:]
+		if _, err := io.WriteString(fw.w, line+"\n"); err != nil {
+			fw.err = err
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush writes any partial line left in the buffer.
+func (fw *lineFilterWriter) flush() error {
+	if fw.err != nil || len(fw.buf) == 0 {
+		return fw.err
+	}
+	_, err := io.WriteString(fw.w, fw.filter(string(fw.buf)))
+	fw.buf = nil
+	if err != nil {
+		fw.err = err
+	}
+	return err
+}
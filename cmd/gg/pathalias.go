@@ -0,0 +1,160 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"gg-scm.io/pkg/git"
+)
+
+// A pathAlias is an untracked path in the working copy that names the same
+// file as a tracked path. This happens on case-insensitive or
+// normalization-insensitive file systems (like on macOS and Windows) when
+// Git is not configured to match (see core.ignoreCase and
+// core.precomposeUnicode in git-config(1)). Git then reports the file
+// twice: once under its tracked name and once as untracked.
+type pathAlias struct {
+	untracked git.TopPath // name as read from the working copy
+	tracked   git.TopPath // name recorded in the index
+}
+
+// isCaseRename reports whether the alias differs from its tracked name
+// only in case. File systems preserve the case they are given, so this
+// means the file was renamed.
+func (a pathAlias) isCaseRename() bool {
+	return strings.EqualFold(string(a.untracked), string(a.tracked))
+}
+
+// findPathAliases finds the untracked entries in a status list that name
+// the same file as a tracked path.
+func findPathAliases(ctx context.Context, g *git.Git, st []git.StatusEntry) ([]pathAlias, error) {
+	var untracked []git.TopPath
+	for _, ent := range st {
+		if ent.Code.IsUntracked() {
+			untracked = append(untracked, ent.Name)
+		}
+	}
+	if len(untracked) == 0 {
+		return nil, nil
+	}
+
+	// List the tracked siblings of each untracked path.
+	dirs := make(map[string]map[string]struct{})
+	listArgs := []string{"ls-files", "-z", "--full-name", "--"}
+	for _, u := range untracked {
+		dir := path.Dir(strings.TrimSuffix(string(u), "/"))
+		if _, listed := dirs[dir]; listed {
+			continue
+		}
+		dirs[dir] = make(map[string]struct{})
+		if dir == "." {
+			dir = ""
+		}
+		listArgs = append(listArgs, git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true}, dir).String())
+	}
+	out, err := g.Output(ctx, listArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("find aliased paths: %w", err)
+	}
+	for _, name := range strings.Split(out, "\x00") {
+		if name == "" {
+			continue
+		}
+		for dir, children := range dirs {
+			rest := name
+			if dir != "." {
+				if !strings.HasPrefix(name, dir+"/") {
+					continue
+				}
+				rest = name[len(dir)+1:]
+			}
+			if i := strings.IndexByte(rest, '/'); i != -1 {
+				// Directories keep the trailing slash, like in status.
+				rest = rest[:i+1]
+			}
+			children[rest] = struct{}{}
+		}
+	}
+
+	// Compare untracked paths to their plausible aliases on disk.
+	workTree, err := g.WorkTree(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find aliased paths: %w", err)
+	}
+	var aliases []pathAlias
+	for _, u := range untracked {
+		dir := path.Dir(strings.TrimSuffix(string(u), "/"))
+		base := strings.TrimPrefix(string(u), dir+"/")
+		if dir == "." {
+			base = string(u)
+		}
+		var uInfo os.FileInfo
+		for child := range dirs[dir] {
+			if child == base || !mayAliasPath(child, base) {
+				continue
+			}
+			if uInfo == nil {
+				uInfo, err = os.Stat(filepath.Join(workTree, filepath.FromSlash(string(u))))
+				if err != nil {
+					break
+				}
+			}
+			tracked := child
+			if dir != "." {
+				tracked = dir + "/" + child
+			}
+			tInfo, err := os.Stat(filepath.Join(workTree, filepath.FromSlash(tracked)))
+			if err != nil || !os.SameFile(uInfo, tInfo) {
+				continue
+			}
+			aliases = append(aliases, pathAlias{
+				untracked: u,
+				tracked:   git.TopPath(tracked),
+			})
+			break
+		}
+	}
+	return aliases, nil
+}
+
+// mayAliasPath reports whether two distinct names could refer to the same
+// file on a case-insensitive or normalization-insensitive file system.
+// It errs on the side of returning true: callers must check the file system.
+func mayAliasPath(name1, name2 string) bool {
+	if strings.EqualFold(name1, name2) {
+		return true
+	}
+	// Names that differ in Unicode normalization both contain multi-byte
+	// sequences. Pure ASCII names can only alias by case.
+	return !isASCII(name1) && !isASCII(name2)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const (
+	nfcName = "caf\u00e9.txt"  // precomposed
+	nfdName = "cafe\u0301.txt" // decomposed
+)
+
+func TestMayAliasPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name1, name2 string
+		want         bool
+	}{
+		{"foo.txt", "foo.txt", true},
+		{"Foo.txt", "foo.txt", true},
+		{"FOO/", "foo/", true},
+		{"foo.txt", "bar.txt", false},
+		{nfcName, nfdName, true},
+		{nfcName, "cafe.txt", false},
+		{"Été", "été", true},
+	}
+	for _, test := range tests {
+		if got := mayAliasPath(test.name1, test.name2); got != test.want {
+			t.Errorf("mayAliasPath(%q, %q) = %t; want %t", test.name1, test.name2, got, test.want)
+		}
+	}
+}
+
+func TestStatus_CaseRename(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := fileSystemAliases(env.root.String(), "Foo.txt", "foo.txt"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Skip("File system is case-sensitive")
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "config", "core.ignorecase", "false"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("Foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "Foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(env.root.FromSlash("Foo.txt"), env.root.FromSlash("foo.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := parseGGStatus(out, t)
+	want := []ggStatusLine{
+		{letter: '?', name: "foo.txt"},
+		{letter: '!', name: "Foo.txt"},
+	}
+	diff := cmp.Diff(want, got,
+		cmp.AllowUnexported(ggStatusLine{}),
+		cmp.Transformer("Map", ggStatusMap),
+		cmpopts.EquateEmpty())
+	if diff != "" {
+		t.Errorf("status output differs (-want +got):\n%s", diff)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "addremove"); err != nil {
+		t.Fatal(err)
+	}
+	st, err := env.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantStatus := []git.StatusEntry{
+		{Code: git.StatusCode{'D', ' '}, Name: "Foo.txt"},
+		{Code: git.StatusCode{' ', 'A'}, Name: "foo.txt"},
+	}
+	if diff := cmp.Diff(wantStatus, st, cmpopts.SortSlices(func(a, b git.StatusEntry) bool { return a.Name < b.Name })); diff != "" {
+		t.Errorf("status after addremove (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatus_NormalizationAlias(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := fileSystemAliases(env.root.String(), nfcName, nfdName); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Skip("File system does not normalize Unicode names")
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "config", "core.precomposeunicode", "false"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write(nfcName, dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, nfcName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	// Store the name decomposed, as HFS+ always does.
+	if err := os.Rename(env.root.FromSlash(nfcName), env.root.FromSlash(nfdName)); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parseGGStatus(out, t); len(got) > 0 {
+		t.Errorf("status = %+v; want no changes", got)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "addremove"); err != nil {
+		t.Fatal(err)
+	}
+	st, err := env.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ent := range st {
+		if ent.Code.IsAdded() {
+			t.Errorf("addremove added %q", ent.Name)
+		}
+	}
+}
+
+// fileSystemAliases reports whether the file system at dir treats the
+// two names as the same file.
+func fileSystemAliases(dir string, name1, name2 string) (bool, error) {
+	probe, err := os.MkdirTemp(dir, "probe")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(probe)
+	if err := os.WriteFile(filepath.Join(probe, name1), nil, 0o666); err != nil {
+		return false, err
+	}
+	info1, err := os.Stat(filepath.Join(probe, name1))
+	if err != nil {
+		return false, err
+	}
+	info2, err := os.Stat(filepath.Join(probe, name2))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(info1, info2), nil
+}
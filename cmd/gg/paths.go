@@ -0,0 +1,147 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const pathsSynopsis = "list named repositories"
+
+// pathAliasHelp is appended to the help of commands that take a
+// repository argument.
+const pathAliasHelp = `The repository can also be given as a path alias defined by a
+	` + "`gg.paths.NAME`" + ` configuration variable (see ` + "`gg paths`" + `).`
+
+// pathsConfigPrefix is the prefix of the Git configuration variables that
+// name repositories, like the [paths] section of a Mercurial configuration.
+const pathsConfigPrefix = "gg.paths."
+
+// pathAliasNameRegexp matches the names that Git permits as the last part
+// of a configuration variable.
+var pathAliasNameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*$`)
+
+func paths(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg paths [NAME]", pathsSynopsis+`
+
+	Path aliases name repositories for `+"`pull`"+`, `+"`push`"+`, `+"`mail`"+`,
+	`+"`incoming`"+`, `+"`outgoing`"+`, and `+"`clone`"+` without creating a Git
+	remote. Define one with `+"`gg config gg.paths.NAME URL`"+` (with
+	`+"`--global`"+` to use it in every repository) and then run commands like
+	`+"`gg pull NAME`"+`. The value can be a URL, a local path, or the name
+	of a remote. A remote with the same name as an alias takes precedence
+	over the alias.
+
+	With no arguments, list the aliases and remotes with their URLs.
+	With a NAME, print the repository it refers to.`)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 1 {
+		return usagef("paths takes at most one NAME")
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	remotes := cfg.ListRemotes()
+	if name := f.Arg(0); name != "" {
+		repo := resolvePathAlias(cfg, name)
+		if r := remotes[repo]; r != nil {
+			repo = r.FetchURL
+		} else if repo == name {
+			return fmt.Errorf("no remote or path alias named %q", name)
+		}
+		_, err := fmt.Fprintln(cc.stdout, repo)
+		return err
+	}
+
+	aliases, err := listPathAliases(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	type namedPath struct {
+		name string
+		url  string
+		kind string
+	}
+	var list []namedPath
+	for name, r := range remotes {
+		list = append(list, namedPath{name: name, url: r.FetchURL, kind: "remote"})
+	}
+	for name, value := range aliases {
+		if remotes[name] == nil {
+			list = append(list, namedPath{name: name, url: value, kind: "alias"})
+		}
+	}
+	if len(list) == 0 {
+		_, err := fmt.Fprintln(cc.stdout, "no remotes or path aliases (add one with 'gg config gg.paths.NAME URL')")
+		return err
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, p := range list {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p.name, p.url, p.kind)
+	}
+	return tw.Flush()
+}
+
+// listPathAliases returns the path aliases defined in the Git
+// configuration, keyed by name.
+func listPathAliases(ctx context.Context, g *git.Git) (map[string]string, error) {
+	entries, err := listConfig(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	aliases := make(map[string]string)
+	for _, ent := range entries {
+		name, ok := strings.CutPrefix(ent.key, pathsConfigPrefix)
+		if ok && pathAliasNameRegexp.MatchString(name) {
+			aliases[name] = ent.value
+		}
+	}
+	return aliases, nil
+}
+
+// resolvePathAlias returns the repository that a command's SOURCE or
+// DESTINATION argument refers to. name is returned unchanged if it is a
+// remote or if it is not a path alias.
+func resolvePathAlias(cfg *git.Config, name string) string {
+	if !pathAliasNameRegexp.MatchString(name) {
+		return name
+	}
+	if _, isRemote := cfg.ListRemotes()[name]; isRemote {
+		return name
+	}
+	if value := cfg.Value(pathsConfigPrefix + name); value != "" {
+		return value
+	}
+	return name
+}
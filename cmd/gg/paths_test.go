@@ -0,0 +1,105 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestPathAliases(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "upstream"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "upstream", "fork"); err != nil {
+		t.Fatal(err)
+	}
+	upstreamGit := env.git.WithDir(env.root.FromSlash("upstream"))
+	forkGit := env.git.WithDir(env.root.FromSlash("fork"))
+	if err := env.root.Apply(filesystem.Write("upstream/upstream.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "upstream/upstream.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := upstreamGit.Run(ctx, "commit", "--quiet", "-m", "Upstream change"); err != nil {
+		t.Fatal(err)
+	}
+	upstreamHead, err := upstreamGit.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstreamPath := env.root.FromSlash("upstream")
+	if err := forkGit.Run(ctx, "config", "gg.paths.up", upstreamPath); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.FromSlash("fork"), "paths")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][]string
+	for _, line := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			t.Fatalf("gg paths line %q does not have 3 fields", line)
+		}
+		got = append(got, []string{fields[0], fields[2]})
+	}
+	if len(got) != 2 || got[0][0] != "origin" || got[0][1] != "remote" || got[1][0] != "up" || got[1][1] != "alias" {
+		t.Errorf("gg paths =\n%s\nwant origin remote and up alias", out)
+	}
+
+	out, err = env.gg(ctx, env.root.FromSlash("fork"), "paths", "up")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSuffix(string(out), "\n"); got != upstreamPath {
+		t.Errorf("gg paths up = %q; want %q", got, upstreamPath)
+	}
+	if _, err := env.gg(ctx, env.root.FromSlash("fork"), "paths", "nope"); err == nil {
+		t.Error("gg paths nope succeeded")
+	}
+
+	out, err = env.gg(ctx, env.root.FromSlash("fork"), "incoming", "up")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCommitLine(string(out), upstreamHead.Commit.String(), "Upstream change") {
+		t.Errorf("gg incoming up = %q; want one line for %v", out, upstreamHead.Commit)
+	}
+
+	if _, err := env.gg(ctx, env.root.FromSlash("fork"), "pull", "up"); err != nil {
+		t.Fatal(err)
+	}
+	pulled, err := forkGit.ParseRev(ctx, "refs/ggpull/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pulled.Commit != upstreamHead.Commit {
+		t.Errorf("refs/ggpull/main = %v after gg pull up; want %v", pulled.Commit, upstreamHead.Commit)
+	}
+}
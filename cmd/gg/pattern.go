@@ -0,0 +1,370 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+// patternHelp is the help text appended to commands that accept patterns.
+const patternHelp = `
+
+	File arguments may be prefixed with a pattern kind, as in Mercurial:
+
+	  path:P      the file or directory P, relative to the repository root
+	  relpath:P   the file or directory P, relative to the current directory
+	  glob:G      shell glob G, relative to the current directory
+	  relglob:G   shell glob G, matched in any subdirectory
+	  rootglob:G  shell glob G, relative to the repository root
+	  iglob:G     case-insensitive glob:G
+	  ipath:P     case-insensitive path:P
+	  re:R        files whose repository-relative path matches regexp R
+	  set:S       a file set: patterns joined with "or", followed by
+	              exclusions introduced by "-" or "and not"
+
+	--include and --exclude patterns default to glob:.`
+
+// A patternSet is the set of file patterns given to a command: its
+// positional arguments plus any --include and --exclude flags.
+type patternSet struct {
+	args     []string
+	includes []string
+	excludes []string
+
+	// rawArgs indicates that positional arguments without a pattern kind
+	// prefix should be passed to Git as pathspecs verbatim instead of
+	// being treated as literal paths.
+	rawArgs bool
+}
+
+// addFlags registers the --include and --exclude flags on f.
+func (ps *patternSet) addFlags(f *flag.FlagSet) {
+	f.MultiStringVar(&ps.includes, "I", "include names matching the given `pattern`")
+	f.Alias("I", "include")
+	f.MultiStringVar(&ps.excludes, "X", "exclude names matching the given `pattern`")
+	f.Alias("X", "exclude")
+}
+
+// isEmpty reports whether no patterns were given.
+func (ps *patternSet) isEmpty() bool {
+	return len(ps.args) == 0 && len(ps.includes) == 0 && len(ps.excludes) == 0
+}
+
+// hasIncludes reports whether the set has any positional arguments or
+// --include patterns. Pathspecs for a set without includes match every
+// file not excluded.
+func (ps *patternSet) hasIncludes() bool {
+	return len(ps.args) > 0 || len(ps.includes) > 0
+}
+
+// pathspecs translates the pattern set into Git pathspecs.
+// If the set is empty, then pathspecs returns nil, which Git interprets
+// as matching every file.
+func (ps *patternSet) pathspecs(ctx context.Context, g *git.Git) ([]git.Pathspec, error) {
+	var files []git.TopPath
+	filesLoaded := false
+	listFiles := func() ([]git.TopPath, error) {
+		if filesLoaded {
+			return files, nil
+		}
+		var err error
+		files, err = listPatternFiles(ctx, g)
+		if err != nil {
+			return nil, err
+		}
+		filesLoaded = true
+		return files, nil
+	}
+	var specs []git.Pathspec
+	add := func(arg, defaultKind string, exclude bool) error {
+		pats, err := parsePattern(arg, defaultKind)
+		if err != nil {
+			return err
+		}
+		for _, pat := range pats {
+			if exclude && pat.exclude {
+				return fmt.Errorf("%s: exclusions cannot be nested in --exclude", arg)
+			}
+			pat.exclude = pat.exclude || exclude
+			if pat.kind != "re" {
+				specs = append(specs, pat.pathspec())
+				continue
+			}
+			files, err := listFiles()
+			if err != nil {
+				return err
+			}
+			matches, err := pat.expand(files)
+			if err != nil {
+				return err
+			}
+			if len(matches) == 0 && !pat.exclude {
+				return fmt.Errorf("%s: no files match", arg)
+			}
+			specs = append(specs, matches...)
+		}
+		return nil
+	}
+	argKind := "relpath"
+	if ps.rawArgs {
+		argKind = ""
+	}
+	for _, arg := range ps.args {
+		if err := add(arg, argKind, false); err != nil {
+			return nil, err
+		}
+	}
+	for _, inc := range ps.includes {
+		if err := add(inc, "glob", false); err != nil {
+			return nil, err
+		}
+	}
+	for _, exc := range ps.excludes {
+		if err := add(exc, "glob", true); err != nil {
+			return nil, err
+		}
+	}
+	return specs, nil
+}
+
+// A pattern is a single parsed file pattern.
+type pattern struct {
+	kind    string // one of the kinds in patternHelp or "" for a raw pathspec
+	value   string
+	exclude bool
+}
+
+// parsePattern parses a command-line file pattern. defaultKind is used
+// if the argument does not have a recognized kind prefix. Set patterns
+// may produce more than one pattern.
+func parsePattern(arg string, defaultKind string) ([]pattern, error) {
+	kind, value, ok := splitPatternKind(arg)
+	if !ok {
+		kind, value = defaultKind, arg
+	}
+	switch kind {
+	case "set":
+		return parseFileSet(value)
+	case "re":
+		if _, err := regexp.Compile(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", arg, err)
+		}
+	}
+	return []pattern{{kind: kind, value: value}}, nil
+}
+
+// hasPatternKind reports whether arg starts with a pattern kind prefix.
+func hasPatternKind(arg string) bool {
+	_, _, ok := splitPatternKind(arg)
+	return ok
+}
+
+func splitPatternKind(arg string) (kind, value string, ok bool) {
+	i := strings.IndexByte(arg, ':')
+	if i == -1 || !isPatternKind(arg[:i]) {
+		return "", arg, false
+	}
+	return arg[:i], arg[i+1:], true
+}
+
+func isPatternKind(kind string) bool {
+	switch kind {
+	case "path", "relpath", "ipath", "glob", "relglob", "rootglob", "iglob", "re", "set":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseFileSet parses the body of a set: pattern. Git pathspecs can only
+// express a union of patterns minus a union of exclusions, so only file
+// sets of that shape are accepted.
+func parseFileSet(expr string) ([]pattern, error) {
+	tokens, err := tokenizeFileSet(expr)
+	if err != nil {
+		return nil, fmt.Errorf("set:%s: %w", expr, err)
+	}
+	var pats []pattern
+	exclude := false
+	wantTerm := true
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case !tok.quoted && (tok.s == "or" || tok.s == "|"):
+			if wantTerm {
+				return nil, fmt.Errorf("set:%s: unexpected %q", expr, tok.s)
+			}
+			if exclude {
+				return nil, fmt.Errorf("set:%s: %q after an exclusion is not supported", expr, tok.s)
+			}
+			wantTerm = true
+		case !tok.quoted && (tok.s == "-" || tok.s == "and"):
+			if wantTerm {
+				return nil, fmt.Errorf("set:%s: unexpected %q", expr, tok.s)
+			}
+			if tok.s == "and" {
+				if i+1 >= len(tokens) || tokens[i+1].quoted || tokens[i+1].s != "not" {
+					return nil, fmt.Errorf("set:%s: only \"and not\" is supported", expr)
+				}
+				i++
+			}
+			exclude = true
+			wantTerm = true
+		case !tok.quoted && tok.s == "not":
+			return nil, fmt.Errorf("set:%s: \"not\" must follow \"and\"", expr)
+		default:
+			if !wantTerm {
+				return nil, fmt.Errorf("set:%s: missing operator before %q", expr, tok.s)
+			}
+			if strings.HasPrefix(tok.s, "set:") {
+				return nil, fmt.Errorf("set:%s: nested sets are not supported", expr)
+			}
+			sub, err := parsePattern(tok.s, "glob")
+			if err != nil {
+				return nil, err
+			}
+			for _, pat := range sub {
+				pat.exclude = exclude
+				pats = append(pats, pat)
+			}
+			wantTerm = false
+		}
+	}
+	if wantTerm {
+		return nil, fmt.Errorf("set:%s: missing pattern", expr)
+	}
+	return pats, nil
+}
+
+type fileSetToken struct {
+	s      string
+	quoted bool
+}
+
+func tokenizeFileSet(expr string) ([]fileSetToken, error) {
+	var tokens []fileSetToken
+	for {
+		expr = strings.TrimLeft(expr, " \t\n")
+		if expr == "" {
+			return tokens, nil
+		}
+		if q := expr[0]; q == '\'' || q == '"' {
+			end := strings.IndexByte(expr[1:], q)
+			if end == -1 {
+				return nil, errors.New("unterminated string")
+			}
+			tokens = append(tokens, fileSetToken{s: expr[1 : end+1], quoted: true})
+			expr = expr[end+2:]
+			continue
+		}
+		end := strings.IndexAny(expr, " \t\n")
+		if end == -1 {
+			end = len(expr)
+		}
+		tokens = append(tokens, fileSetToken{s: expr[:end]})
+		expr = expr[end:]
+	}
+}
+
+// pathspec converts the pattern into a Git pathspec.
+// It must not be called on a re: pattern.
+func (pat pattern) pathspec() git.Pathspec {
+	var magic git.PathspecMagic
+	value := pat.value
+	switch pat.kind {
+	case "":
+		if !pat.exclude {
+			return git.Pathspec(value)
+		}
+		magic, value = git.Pathspec(value).SplitMagic()
+	case "path":
+		magic = git.PathspecMagic{Top: true, Literal: true}
+	case "ipath":
+		magic = git.PathspecMagic{Top: true, Literal: true, CaseInsensitive: true}
+	case "relpath":
+		magic = git.PathspecMagic{Literal: true}
+	case "glob":
+		magic = git.PathspecMagic{Glob: true}
+	case "relglob":
+		magic = git.PathspecMagic{Glob: true}
+		value = "**/" + value
+	case "rootglob":
+		magic = git.PathspecMagic{Top: true, Glob: true}
+	case "iglob":
+		magic = git.PathspecMagic{Glob: true, CaseInsensitive: true}
+	default:
+		panic("unhandled pattern kind " + pat.kind)
+	}
+	if pat.kind == "path" || pat.kind == "ipath" {
+		value = strings.TrimPrefix(value, "/")
+		if value == "." {
+			value = ""
+		}
+	}
+	magic.Exclude = pat.exclude
+	return git.JoinPathspecMagic(magic, value)
+}
+
+// expand returns pathspecs for each of the files that match a re: pattern.
+// Like Mercurial, the regular expression is matched against the beginning
+// of the path relative to the repository root.
+func (pat pattern) expand(files []git.TopPath) ([]git.Pathspec, error) {
+	re, err := regexp.Compile(`^(?:` + pat.value + `)`)
+	if err != nil {
+		return nil, fmt.Errorf("re:%s: %w", pat.value, err)
+	}
+	var specs []git.Pathspec
+	for _, f := range files {
+		if re.MatchString(f.String()) {
+			specs = append(specs, git.JoinPathspecMagic(git.PathspecMagic{
+				Top:     true,
+				Literal: true,
+				Exclude: pat.exclude,
+			}, f.String()))
+		}
+	}
+	return specs, nil
+}
+
+// listPatternFiles returns the tracked and untracked (but not ignored)
+// files in the working copy, which are the candidates for re: patterns.
+func listPatternFiles(ctx context.Context, g *git.Git) ([]git.TopPath, error) {
+	out, err := g.Output(ctx, "ls-files", "-z", "--cached", "--others", "--exclude-standard", "--full-name", "--", ":/")
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	var files []git.TopPath
+	seen := make(map[string]struct{})
+	for _, name := range strings.Split(out, "\x00") {
+		if name == "" {
+			continue
+		}
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+		files = append(files, git.TopPath(name))
+	}
+	return files, nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePattern(t *testing.T) {
+	t.Parallel()
+	type spec struct {
+		Magic   git.PathspecMagic
+		Pattern string
+	}
+	tests := []struct {
+		arg         string
+		defaultKind string
+		want        []spec
+	}{
+		{
+			arg:         "foo.txt",
+			defaultKind: "relpath",
+			want:        []spec{{git.PathspecMagic{Literal: true}, "foo.txt"}},
+		},
+		{
+			arg:         "*.txt",
+			defaultKind: "",
+			want:        []spec{{git.PathspecMagic{}, "*.txt"}},
+		},
+		{
+			arg:         "foo:bar.txt",
+			defaultKind: "relpath",
+			want:        []spec{{git.PathspecMagic{Literal: true}, "foo:bar.txt"}},
+		},
+		{
+			arg:         "path:foo/bar",
+			defaultKind: "relpath",
+			want:        []spec{{git.PathspecMagic{Top: true, Literal: true}, "foo/bar"}},
+		},
+		{
+			arg:         "path:.",
+			defaultKind: "relpath",
+			want:        []spec{{git.PathspecMagic{Top: true, Literal: true}, ""}},
+		},
+		{
+			arg:         "ipath:README",
+			defaultKind: "relpath",
+			want:        []spec{{git.PathspecMagic{Top: true, Literal: true, CaseInsensitive: true}, "README"}},
+		},
+		{
+			arg:         "relpath:*.txt",
+			defaultKind: "",
+			want:        []spec{{git.PathspecMagic{Literal: true}, "*.txt"}},
+		},
+		{
+			arg:         "glob:*.go",
+			defaultKind: "relpath",
+			want:        []spec{{git.PathspecMagic{Glob: true}, "*.go"}},
+		},
+		{
+			arg:         "*.go",
+			defaultKind: "glob",
+			want:        []spec{{git.PathspecMagic{Glob: true}, "*.go"}},
+		},
+		{
+			arg:         "relglob:*.go",
+			defaultKind: "relpath",
+			want:        []spec{{git.PathspecMagic{Glob: true}, "**/*.go"}},
+		},
+		{
+			arg:         "rootglob:src/*.c",
+			defaultKind: "relpath",
+			want:        []spec{{git.PathspecMagic{Top: true, Glob: true}, "src/*.c"}},
+		},
+		{
+			arg:         "iglob:*.jpg",
+			defaultKind: "relpath",
+			want:        []spec{{git.PathspecMagic{Glob: true, CaseInsensitive: true}, "*.jpg"}},
+		},
+		{
+			arg:         "set:*.go or path:docs - glob:*_test.go",
+			defaultKind: "relpath",
+			want: []spec{
+				{git.PathspecMagic{Glob: true}, "*.go"},
+				{git.PathspecMagic{Top: true, Literal: true}, "docs"},
+				{git.PathspecMagic{Glob: true, Exclude: true}, "*_test.go"},
+			},
+		},
+		{
+			arg:         "set:'a file.txt' and not \"b file.txt\"",
+			defaultKind: "relpath",
+			want: []spec{
+				{git.PathspecMagic{Glob: true}, "a file.txt"},
+				{git.PathspecMagic{Glob: true, Exclude: true}, "b file.txt"},
+			},
+		},
+	}
+	for _, test := range tests {
+		pats, err := parsePattern(test.arg, test.defaultKind)
+		if err != nil {
+			t.Errorf("parsePattern(%q, %q): %v", test.arg, test.defaultKind, err)
+			continue
+		}
+		var got []spec
+		for _, pat := range pats {
+			magic, p := pat.pathspec().SplitMagic()
+			got = append(got, spec{magic, p})
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("parsePattern(%q, %q) pathspecs (-want +got):\n%s", test.arg, test.defaultKind, diff)
+		}
+	}
+}
+
+func TestParsePattern_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []string{
+		"re:(",
+		"set:",
+		"set:or a",
+		"set:a b",
+		"set:a or",
+		"set:not a",
+		"set:a and b",
+		"set:a - b or c",
+		"set:'a",
+		"set:set:a",
+	}
+	for _, arg := range tests {
+		if pats, err := parsePattern(arg, "relpath"); err == nil {
+			t.Errorf("parsePattern(%q, \"relpath\") = %+v, <nil>; want error", arg, pats)
+		}
+	}
+}
+
+func TestPatternExpand(t *testing.T) {
+	t.Parallel()
+	files := []git.TopPath{
+		"README.md",
+		"cmd/gg/main.go",
+		"cmd/gg/main_test.go",
+		"internal/flag/flag.go",
+	}
+	tests := []struct {
+		pat  pattern
+		want []git.Pathspec
+	}{
+		{
+			pat: pattern{kind: "re", value: `.*_test\.go$`},
+			want: []git.Pathspec{
+				git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true}, "cmd/gg/main_test.go"),
+			},
+		},
+		{
+			// Matches are anchored at the start of the path.
+			pat:  pattern{kind: "re", value: `gg/`},
+			want: nil,
+		},
+		{
+			pat: pattern{kind: "re", value: `(?i)readme`, exclude: true},
+			want: []git.Pathspec{
+				git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true, Exclude: true}, "README.md"),
+			},
+		},
+		{
+			pat: pattern{kind: "re", value: `cmd|internal`},
+			want: []git.Pathspec{
+				git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true}, "cmd/gg/main.go"),
+				git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true}, "cmd/gg/main_test.go"),
+				git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true}, "internal/flag/flag.go"),
+			},
+		},
+	}
+	for _, test := range tests {
+		got, err := test.pat.expand(files)
+		if err != nil {
+			t.Errorf("%+v.expand(...): %v", test.pat, err)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%+v.expand(...) (-want +got):\n%s", test.pat, diff)
+		}
+	}
+}
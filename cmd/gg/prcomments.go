@@ -0,0 +1,472 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const prCommentsSynopsis = "show review comments on a GitHub pull request"
+
+func prComments(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg requestpull comments [--json] [NUMBER]", prCommentsSynopsis+`
+
+aliases: pr comments
+
+	Fetches the review comments on pull request NUMBER, or on the pull
+	request for the current branch if NUMBER is not given, and prints
+	them grouped by file and line.
+
+	Line numbers refer to the files in the working copy. If the branch
+	moved since a comment was made, gg follows the line through the
+	changes made since the commented commit. Comments on lines that have
+	since been changed are marked as outdated.
+
+	With `+"`--json`"+`, the comments are printed as a JSON array so that
+	editors can show them inline.`)
+	jsonOutput := f.Bool("json", false, "print the comments as JSON")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 1 {
+		return usagef("only one pull request allowed")
+	}
+	var prNum uint64
+	if f.NArg() == 1 {
+		var err error
+		prNum, err = strconv.ParseUint(strings.TrimPrefix(f.Arg(0), "#"), 10, 64)
+		if err != nil || prNum == 0 {
+			return usagef("%q is not a pull request number", f.Arg(0))
+		}
+	}
+
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	branch := currentBranch(ctx, cc)
+	if prNum == 0 && branch == "" {
+		return errors.New("no branch currently checked out; pass a pull request number")
+	}
+	remote := cfg.Value("branch." + branch + ".remote")
+	if branch == "" || remote == "" {
+		if _, ok := cfg.ListRemotes()["origin"]; !ok {
+			return errors.New("branch has no remote and no remote named \"origin\" found")
+		}
+		remote = "origin"
+	}
+	remoteURL := cfg.Value("remote." + remote + ".url")
+	owner, repo := parseGitHubRemoteURL(remoteURL)
+	if owner == "" || repo == "" {
+		return fmt.Errorf("%s is not a GitHub repository", remoteURL)
+	}
+	token, err := readGitHubToken(ctx, cc)
+	if err != nil {
+		return err
+	}
+	if prNum == 0 {
+		headRemote, err := inferPushRepo(cfg, branch)
+		if err != nil {
+			return err
+		}
+		headOwner, _ := parseGitHubRemoteURL(cfg.Value("remote." + headRemote + ".url"))
+		if headOwner == "" {
+			headOwner = owner
+		}
+		prNum, err = findPullRequest(ctx, cc.httpClient, string(token), owner, repo, headOwner+":"+branch)
+		if err != nil {
+			return err
+		}
+	}
+	apiComments, err := listReviewComments(ctx, cc.httpClient, string(token), owner, repo, prNum)
+	if err != nil {
+		return err
+	}
+	comments, err := locateReviewComments(ctx, cc.git, remote, prNum, apiComments)
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		if comments == nil {
+			comments = []*prComment{}
+		}
+		enc := json.NewEncoder(cc.stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(comments)
+	}
+	if len(comments) == 0 {
+		_, err := fmt.Fprintf(cc.stdout, "no review comments on pull request #%d\n", prNum)
+		return err
+	}
+	return writeReviewComments(cc.stdout, comments)
+}
+
+// A prComment is a review comment on a pull request, placed in the
+// working copy.
+type prComment struct {
+	ID   int64  `json:"id"`
+	Path string `json:"path"`
+	// Line is the line in the working copy that the comment refers to,
+	// or zero if it could not be determined.
+	Line int `json:"line,omitempty"`
+	// OriginalLine is the line in Commit that the comment was made on.
+	OriginalLine int    `json:"original_line,omitempty"`
+	Commit       string `json:"commit,omitempty"`
+	// Outdated is true if the commented line has changed since the
+	// comment was made or the comment is on a removed line.
+	Outdated  bool      `json:"outdated"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	InReplyTo int64     `json:"in_reply_to,omitempty"`
+	URL       string    `json:"url"`
+}
+
+// gitHubReviewComment is a review comment as returned by the GitHub API.
+type gitHubReviewComment struct {
+	ID               int64
+	Path             string
+	Line             *int
+	OriginalLine     *int `json:"original_line"`
+	Side             string
+	Position         *int
+	CommitID         string `json:"commit_id"`
+	OriginalCommitID string `json:"original_commit_id"`
+	DiffHunk         string `json:"diff_hunk"`
+	InReplyToID      int64  `json:"in_reply_to_id"`
+	Body             string
+	CreatedAt        time.Time `json:"created_at"`
+	HTMLURL          string    `json:"html_url"`
+	User             struct {
+		Login string
+	}
+}
+
+// findPullRequest returns the number of the most recent pull request
+// whose head is the given "owner:branch".
+func findPullRequest(ctx context.Context, client *http.Client, authToken, owner, repo, head string) (uint64, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls?state=all&head=%s",
+		url.PathEscape(owner), url.PathEscape(repo), url.QueryEscape(head))
+	var prs []struct {
+		Number uint64
+	}
+	if err := getGitHubJSON(ctx, client, authToken, apiURL, &prs); err != nil {
+		return 0, fmt.Errorf("find pull request for %s: %w", head, err)
+	}
+	if len(prs) == 0 {
+		return 0, fmt.Errorf("no pull request found for %s in %s/%s", head, owner, repo)
+	}
+	return prs[0].Number, nil
+}
+
+// listReviewComments returns all the review comments on a pull request,
+// oldest first.
+func listReviewComments(ctx context.Context, client *http.Client, authToken, owner, repo string, prNum uint64) ([]*gitHubReviewComment, error) {
+	const perPage = 100
+	var comments []*gitHubReviewComment
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/comments?per_page=%d&page=%d",
+			url.PathEscape(owner), url.PathEscape(repo), prNum, perPage, page)
+		var pageComments []*gitHubReviewComment
+		if err := getGitHubJSON(ctx, client, authToken, apiURL, &pageComments); err != nil {
+			return nil, fmt.Errorf("list comments on %s/%s/pulls/%d: %w", owner, repo, prNum, err)
+		}
+		comments = append(comments, pageComments...)
+		if len(pageComments) < perPage {
+			return comments, nil
+		}
+	}
+}
+
+// getGitHubJSON sends a GET request to the GitHub API and decodes the
+// JSON response into v.
+func getGitHubJSON(ctx context.Context, client *http.Client, authToken, apiURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgentString())
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if authToken != "" {
+		req.Header.Set("Authorization", "token "+authToken)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return parseGitHubErrorResponse(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
+
+// locateReviewComments converts GitHub review comments into prComments,
+// mapping the commented lines to lines in the working copy.
+func locateReviewComments(ctx context.Context, g *git.Git, remote string, prNum uint64, apiComments []*gitHubReviewComment) ([]*prComment, error) {
+	var comments []*prComment
+	var left []bool
+	missing := false
+	for _, c := range apiComments {
+		pc := &prComment{
+			ID:        c.ID,
+			Path:      c.Path,
+			Author:    c.User.Login,
+			Body:      c.Body,
+			CreatedAt: c.CreatedAt,
+			InReplyTo: c.InReplyToID,
+			URL:       c.HTMLURL,
+		}
+		isLeft := c.Side == "LEFT"
+		switch {
+		case c.Line != nil:
+			pc.OriginalLine = *c.Line
+			pc.Commit = c.CommitID
+		case c.OriginalLine != nil:
+			// The comment's line is not in the pull request's latest diff.
+			pc.OriginalLine = *c.OriginalLine
+			pc.Commit = c.OriginalCommitID
+			pc.Outdated = true
+		default:
+			// Comments made before GitHub tracked lines only have a
+			// position in the diff. The diff hunk ends at that position.
+			line, l, ok := diffHunkLine(c.DiffHunk)
+			if ok {
+				pc.OriginalLine = line
+				isLeft = l
+			}
+			pc.Commit = c.OriginalCommitID
+			pc.Outdated = c.Position == nil
+		}
+		if isLeft {
+			// Removed lines don't exist in the working copy.
+			pc.Outdated = true
+		}
+		if pc.Commit != "" && !commitExists(ctx, g, pc.Commit) {
+			missing = true
+		}
+		comments = append(comments, pc)
+		left = append(left, isLeft)
+	}
+	if missing {
+		// The commented commits may have been rebased away locally.
+		// GitHub keeps them reachable from the pull request's ref.
+		// If the fetch fails, those comments are shown at their original
+		// lines.
+		_ = g.Run(ctx, "fetch", "--quiet", remote, fmt.Sprintf("refs/pull/%d/head", prNum))
+	}
+
+	diffs := make(map[string]string)
+	for i, pc := range comments {
+		if left[i] || pc.OriginalLine == 0 || pc.Commit == "" || !commitExists(ctx, g, pc.Commit) {
+			continue
+		}
+		key := pc.Commit + "\x00" + pc.Path
+		diff, ok := diffs[key]
+		if !ok {
+			var err error
+			diff, err = g.Output(ctx, "diff", "--no-color", "--no-ext-diff", "--no-renames", "-U0",
+				pc.Commit, "--", git.TopPath(pc.Path).Pathspec().String())
+			if err != nil {
+				return nil, err
+			}
+			diffs[key] = diff
+		}
+		line, changed := mapLineThroughDiff(diff, pc.OriginalLine)
+		pc.Line = line
+		pc.Outdated = pc.Outdated || changed
+	}
+	return comments, nil
+}
+
+func commitExists(ctx context.Context, g *git.Git, hash string) bool {
+	return g.Run(ctx, "cat-file", "-e", hash+"^{commit}") == nil
+}
+
+// diffHunkLine returns the line that a GitHub diff hunk ends at, which is
+// the line a position-based comment refers to. left is true if the line
+// was removed, in which case line is its number in the old file.
+func diffHunkLine(hunk string) (line int, left bool, ok bool) {
+	lines := strings.Split(strings.TrimSuffix(hunk, "\n"), "\n")
+	oldStart, _, newStart, _, ok := parseHunkHeader(lines[0])
+	if !ok || len(lines) < 2 {
+		return 0, false, false
+	}
+	oldLine, newLine := oldStart-1, newStart-1
+	for _, l := range lines[1:] {
+		switch {
+		case strings.HasPrefix(l, "-"):
+			oldLine++
+			left = true
+		case strings.HasPrefix(l, "+"):
+			newLine++
+			left = false
+		case strings.HasPrefix(l, `\`):
+			// "\ No newline at end of file"
+		default:
+			oldLine++
+			newLine++
+			left = false
+		}
+	}
+	if left {
+		return oldLine, true, true
+	}
+	return newLine, false, true
+}
+
+// parseHunkHeader parses a "@@ -a,b +c,d @@" line. A missing count is 1.
+func parseHunkHeader(line string) (oldStart, oldCount, newStart, newCount int, ok bool) {
+	rest, found := strings.CutPrefix(line, "@@ -")
+	if !found {
+		return 0, 0, 0, 0, false
+	}
+	end := strings.Index(rest, " @@")
+	if end == -1 {
+		return 0, 0, 0, 0, false
+	}
+	oldRange, newRange, found := strings.Cut(rest[:end], " +")
+	if !found {
+		return 0, 0, 0, 0, false
+	}
+	parseRange := func(s string) (start, count int, ok bool) {
+		startStr, countStr, hasCount := strings.Cut(s, ",")
+		start, err := strconv.Atoi(startStr)
+		if err != nil {
+			return 0, 0, false
+		}
+		if !hasCount {
+			return start, 1, true
+		}
+		count, err = strconv.Atoi(countStr)
+		if err != nil {
+			return 0, 0, false
+		}
+		return start, count, true
+	}
+	oldStart, oldCount, ok1 := parseRange(oldRange)
+	newStart, newCount, ok2 := parseRange(newRange)
+	if !ok1 || !ok2 {
+		return 0, 0, 0, 0, false
+	}
+	return oldStart, oldCount, newStart, newCount, true
+}
+
+// mapLineThroughDiff returns the line in the new file that corresponds to
+// line in the old file, given a diff between them generated with -U0.
+// changed is true if the line was modified or removed, in which case the
+// returned line is the nearest line in the new file.
+func mapLineThroughDiff(diff string, line int) (newLine int, changed bool) {
+	delta := 0
+	for _, l := range strings.Split(diff, "\n") {
+		oldStart, oldCount, newStart, newCount, ok := parseHunkHeader(l)
+		if !ok {
+			continue
+		}
+		if oldCount == 0 {
+			// Pure insertion after oldStart.
+			if line <= oldStart {
+				break
+			}
+			delta += newCount
+			continue
+		}
+		if line < oldStart {
+			break
+		}
+		if line < oldStart+oldCount {
+			if newCount == 0 {
+				// Lines removed after newStart.
+				return newStart + 1, true
+			}
+			return newStart + min(line-oldStart, newCount-1), true
+		}
+		delta += newCount - oldCount
+	}
+	return line + delta, false
+}
+
+// writeReviewComments prints comments grouped by file and line, with
+// replies after the comment they reply to.
+func writeReviewComments(w io.Writer, comments []*prComment) error {
+	ids := make(map[int64]bool)
+	for _, c := range comments {
+		ids[c.ID] = true
+	}
+	replies := make(map[int64][]*prComment)
+	var threads []*prComment
+	for _, c := range comments {
+		if ids[c.InReplyTo] {
+			replies[c.InReplyTo] = append(replies[c.InReplyTo], c)
+		} else {
+			threads = append(threads, c)
+		}
+	}
+	sort.SliceStable(threads, func(i, j int) bool {
+		if threads[i].Path != threads[j].Path {
+			return threads[i].Path < threads[j].Path
+		}
+		return threads[i].Line < threads[j].Line
+	})
+	sb := new(strings.Builder)
+	for i, t := range threads {
+		if i == 0 || t.Path != threads[i-1].Path {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(t.Path + "\n")
+		}
+		switch {
+		case t.Line == 0:
+			sb.WriteString("  line unknown")
+		default:
+			fmt.Fprintf(sb, "  line %d", t.Line)
+		}
+		if t.Outdated {
+			sb.WriteString(" (outdated)")
+		}
+		sb.WriteString("\n")
+		for _, c := range append([]*prComment{t}, replies[t.ID]...) {
+			bodyLines := strings.Split(strings.TrimSpace(strings.ReplaceAll(c.Body, "\r\n", "\n")), "\n")
+			fmt.Fprintf(sb, "    %s: %s\n", c.Author, bodyLines[0])
+			for _, l := range bodyLines[1:] {
+				sb.WriteString(strings.TrimRight("      "+l, " ") + "\n")
+			}
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
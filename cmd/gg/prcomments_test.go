@@ -0,0 +1,213 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestMapLineThroughDiff(t *testing.T) {
+	const diff = "diff --git a/foo.txt b/foo.txt\n" +
+		"--- a/foo.txt\n" +
+		"+++ b/foo.txt\n" +
+		"@@ -0,0 +1,2 @@\n" +
+		"+new1\n" +
+		"+new2\n" +
+		"@@ -3 +5 @@\n" +
+		"-old3\n" +
+		"+new3\n" +
+		"@@ -6,2 +7,0 @@\n" +
+		"-old6\n" +
+		"-old7\n"
+	tests := []struct {
+		line        int
+		want        int
+		wantChanged bool
+	}{
+		{line: 1, want: 3},
+		{line: 2, want: 4},
+		{line: 3, want: 5, wantChanged: true},
+		{line: 4, want: 6},
+		{line: 5, want: 7},
+		{line: 6, want: 8, wantChanged: true},
+		{line: 7, want: 8, wantChanged: true},
+		{line: 8, want: 8},
+		{line: 20, want: 20},
+	}
+	for _, test := range tests {
+		got, changed := mapLineThroughDiff(diff, test.line)
+		if got != test.want || changed != test.wantChanged {
+			t.Errorf("mapLineThroughDiff(diff, %d) = %d, %t; want %d, %t", test.line, got, changed, test.want, test.wantChanged)
+		}
+	}
+}
+
+func TestDiffHunkLine(t *testing.T) {
+	tests := []struct {
+		hunk   string
+		line   int
+		left   bool
+		wantOK bool
+	}{
+		{
+			hunk:   "@@ -10,4 +10,5 @@ func foo() {\n context\n-removed\n+added\n+added again",
+			line:   12,
+			wantOK: true,
+		},
+		{
+			hunk:   "@@ -10,4 +10,5 @@ func foo() {\n context\n-removed",
+			line:   11,
+			left:   true,
+			wantOK: true,
+		},
+		{
+			hunk:   "@@ -1 +1 @@\n-a\n+b",
+			line:   1,
+			wantOK: true,
+		},
+		{
+			hunk: "not a hunk",
+		},
+	}
+	for _, test := range tests {
+		line, left, ok := diffHunkLine(test.hunk)
+		if line != test.line || left != test.left || ok != test.wantOK {
+			t.Errorf("diffHunkLine(%q) = %d, %t, %t; want %d, %t, %t", test.hunk, line, left, ok, test.line, test.left, test.wantOK)
+		}
+	}
+}
+
+func TestPRComments(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const authToken = "xyzzy12345"
+	if err := env.writeGitHubAuth([]byte(authToken + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "origin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "origin", "local"); err != nil {
+		t.Fatal(err)
+	}
+	localDir := env.root.FromSlash("local")
+	localGit := env.git.WithDir(localDir)
+	if err := localGit.Run(ctx, "remote", "set-url", "origin", "https://github.com/example/foo.git"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("local/foo.txt", "1\n2\n3\n4\n5\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "local/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	commit, err := env.newCommit(ctx, "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Change the file after the comments were made.
+	if err := env.root.Apply(filesystem.Write("local/foo.txt", "0a\n0b\n1\nTWO\n3\n4\n5\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "token "+authToken; got != want {
+			t.Errorf("Authorization header = %q; want %q", got, want)
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		switch r.URL.Path {
+		case "/repos/example/foo/pulls":
+			if got, want := r.URL.Query().Get("head"), "example:main"; got != want {
+				t.Errorf("head = %q; want %q", got, want)
+			}
+			fmt.Fprint(w, `[{"number": 42}]`)
+		case "/repos/example/foo/pulls/42/comments":
+			comments := []map[string]interface{}{
+				{"id": 1, "path": "foo.txt", "line": 4, "side": "RIGHT", "commit_id": commit.String(),
+					"body": "Fix this.", "user": map[string]string{"login": "alice"}},
+				{"id": 2, "path": "foo.txt", "line": 2, "side": "RIGHT", "commit_id": commit.String(),
+					"body": "And this.\nPlease.", "user": map[string]string{"login": "alice"}},
+				{"id": 3, "path": "foo.txt", "line": 4, "side": "RIGHT", "commit_id": commit.String(),
+					"in_reply_to_id": 1, "body": "Done.", "user": map[string]string{"login": "bob"}},
+			}
+			json.NewEncoder(w).Encode(comments)
+		default:
+			t.Logf("unhandled API request %s %s", r.Method, r.URL)
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		}
+	})
+	fakeGitHub := httptest.NewServer(api)
+	defer fakeGitHub.Close()
+	fakeGitHubTransport := &http.Transport{
+		DialTLS: func(network, addr string) (net.Conn, error) {
+			hostport := strings.TrimPrefix(fakeGitHub.URL, "http://")
+			return net.Dial("tcp", hostport)
+		},
+	}
+	defer fakeGitHubTransport.CloseIdleConnections()
+	env.roundTripper = fakeGitHubTransport
+
+	out, err := env.gg(ctx, localDir, "pr", "comments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "foo.txt\n" +
+		"  line 4 (outdated)\n" +
+		"    alice: And this.\n" +
+		"      Please.\n" +
+		"  line 6\n" +
+		"    alice: Fix this.\n" +
+		"    bob: Done.\n"
+	if got := string(out); got != want {
+		t.Errorf("gg pr comments output:\n%s\nwant:\n%s", got, want)
+	}
+
+	out, err = env.gg(ctx, localDir, "pr", "comments", "--json", "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*prComment
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d comments in JSON; want 3", len(got))
+	}
+	if got[0].Line != 6 || got[0].OriginalLine != 4 || got[0].Outdated {
+		t.Errorf("comment 1 at line %d (original %d, outdated = %t); want line 6 (original 4, outdated = false)",
+			got[0].Line, got[0].OriginalLine, got[0].Outdated)
+	}
+	if got[1].Line != 4 || !got[1].Outdated {
+		t.Errorf("comment 2 at line %d (outdated = %t); want line 4 (outdated = true)", got[1].Line, got[1].Outdated)
+	}
+	if got[2].InReplyTo != 1 {
+		t.Errorf("comment 3 in reply to %d; want 1", got[2].InReplyTo)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"gg-scm.io/pkg/git"
+)
+
+// protectedBranchHelp is appended to the help of commands that refuse to
+// rewrite protected branches.
+const protectedBranchHelp = `
+
+	Branches that match a pattern in ` + "`gg.protect`" + `, a comma- or
+	space-separated list of glob patterns like "main, release/*", are
+	protected: gg refuses to rewrite their commits or move them to a
+	commit that doesn't descend from their current one. A * in a pattern
+	does not match a slash. This is independent of any protection on the
+	server. Pass ` + "`--force-protected`" + ` to change a protected branch anyway.`
+
+const forceProtectedUsage = "allow rewriting branches protected by gg.protect"
+
+// readProtectedBranches returns the branch name patterns in gg.protect.
+func readProtectedBranches(cfg *git.Config) ([]string, error) {
+	patterns := strings.FieldsFunc(cfg.Value("gg.protect"), func(c rune) bool {
+		return c == ',' || unicode.IsSpace(c)
+	})
+	for _, pat := range patterns {
+		if _, err := path.Match(pat, ""); err != nil {
+			return nil, fmt.Errorf("gg.protect: invalid pattern %q", pat)
+		}
+	}
+	return patterns, nil
+}
+
+// matchProtectedBranch returns the first pattern that matches the branch
+// name or the empty string if none do.
+func matchProtectedBranch(patterns []string, branch string) string {
+	for _, pat := range patterns {
+		if ok, _ := path.Match(pat, branch); ok {
+			return pat
+		}
+	}
+	return ""
+}
+
+// checkRewriteProtected returns an error if the current branch is
+// protected by gg.protect. op is what the user is doing to the branch,
+// like "rebase".
+func checkRewriteProtected(ctx context.Context, cc *cmdContext, op string) error {
+	b := currentBranch(ctx, cc)
+	if b == "" {
+		return nil
+	}
+	return checkProtectedBranch(ctx, cc, op, b)
+}
+
+// checkProtectedBranch returns an error if the named branch is protected
+// by gg.protect.
+func checkProtectedBranch(ctx context.Context, cc *cmdContext, op string, branch string) error {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	patterns, err := readProtectedBranches(cfg)
+	if err != nil {
+		return err
+	}
+	if pat := matchProtectedBranch(patterns, branch); pat != "" {
+		return &protectedBranchError{op: op, branch: branch, pattern: pat}
+	}
+	return nil
+}
+
+// checkMoveProtectedBranch returns an error if moving the branch to the
+// target commit would drop commits from it and the branch matches one of
+// the gg.protect patterns.
+func checkMoveProtectedBranch(ctx context.Context, g *git.Git, patterns []string, branch string, target git.Hash) error {
+	pat := matchProtectedBranch(patterns, branch)
+	if pat == "" {
+		return nil
+	}
+	old, err := g.ParseRev(ctx, git.BranchRef(branch).String())
+	if err != nil {
+		// New branch.
+		return nil
+	}
+	if fastForward, err := g.IsAncestor(ctx, old.Commit.String(), target.String()); err != nil {
+		return err
+	} else if fastForward {
+		return nil
+	}
+	return &protectedBranchError{op: "move it", branch: branch, pattern: pat}
+}
+
+// A protectedBranchError is returned when a command would rewrite a
+// branch that matches gg.protect.
+type protectedBranchError struct {
+	op      string // what the user is doing, like "amend"
+	branch  string
+	pattern string // pattern in gg.protect that matched branch
+}
+
+func (e *protectedBranchError) Error() string {
+	var what string
+	if e.pattern == e.branch {
+		what = fmt.Sprintf("branch %s is protected by gg.protect", e.branch)
+	} else {
+		what = fmt.Sprintf("branch %s is protected by %q in gg.protect", e.branch, e.pattern)
+	}
+	return what + fmt.Sprintf("; pass --force-protected to %s anyway", e.op)
+}
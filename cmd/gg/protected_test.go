@@ -0,0 +1,130 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+)
+
+func TestMatchProtectedBranch(t *testing.T) {
+	patterns := []string{"main", "release/*"}
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{branch: "main", want: "main"},
+		{branch: "release/1.0", want: "release/*"},
+		{branch: "release/1.0/hotfix", want: ""},
+		{branch: "mainline", want: ""},
+		{branch: "feature", want: ""},
+	}
+	for _, test := range tests {
+		if got := matchProtectedBranch(patterns, test.branch); got != test.want {
+			t.Errorf("matchProtectedBranch(%q, %q) = %q; want %q", patterns, test.branch, got, test.want)
+		}
+	}
+}
+
+func TestProtectedBranches(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "config", "gg.protect", "main, release/*"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := env.git.ParseRev(ctx, "HEAD~")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantProtected := func(t *testing.T, err error) {
+		t.Helper()
+		if err == nil {
+			t.Error("command succeeded on a protected branch")
+			return
+		}
+		if !errors.As(err, new(*protectedBranchError)) {
+			t.Errorf("error = %v; want protected branch error", err)
+		}
+	}
+	t.Run("Amend", func(t *testing.T) {
+		_, err := env.gg(ctx, env.root.String(), "commit", "--amend", "-m", "amended")
+		wantProtected(t, err)
+	})
+	t.Run("Rebase", func(t *testing.T) {
+		_, err := env.gg(ctx, env.root.String(), "rebase", "--dest", parent.Commit.String())
+		wantProtected(t, err)
+	})
+	t.Run("Histedit", func(t *testing.T) {
+		_, err := env.gg(ctx, env.root.String(), "histedit", "--edit-message-only", parent.Commit.String())
+		wantProtected(t, err)
+	})
+	t.Run("BranchForce", func(t *testing.T) {
+		if _, err := env.gg(ctx, env.root.String(), "branch", "-r", parent.Commit.String(), "release/1.0"); err != nil {
+			t.Fatal(err)
+		}
+		// Moving forward is fine.
+		if _, err := env.gg(ctx, env.root.String(), "branch", "-f", "-r", head.Commit.String(), "release/1.0"); err != nil {
+			t.Error(err)
+		}
+		_, err := env.gg(ctx, env.root.String(), "branch", "-f", "-r", parent.Commit.String(), "release/1.0")
+		wantProtected(t, err)
+		if r, err := env.git.ParseRev(ctx, "release/1.0"); err != nil {
+			t.Error(err)
+		} else if r.Commit != head.Commit {
+			t.Errorf("release/1.0 = %v; want %v", r.Commit, head.Commit)
+		}
+	})
+
+	if got, err := env.git.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if got.Commit != head.Commit {
+		t.Fatalf("HEAD = %v after refused commands; want %v", got.Commit, head.Commit)
+	}
+
+	// --force-protected overrides the protection.
+	if _, err := env.gg(ctx, env.root.String(), "commit", "--amend", "--force-protected", "-m", "amended"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := env.git.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if got.Commit == head.Commit {
+		t.Error("commit --amend --force-protected did not amend")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "branch", "-f", "--force-protected", "-r", parent.Commit.String(), "release/1.0"); err != nil {
+		t.Error(err)
+	}
+	if r, err := env.git.ParseRev(ctx, git.BranchRef("release/1.0").String()); err != nil {
+		t.Error(err)
+	} else if r.Commit != parent.Commit {
+		t.Errorf("release/1.0 = %v after branch -f --force-protected; want %v", r.Commit, parent.Commit)
+	}
+}
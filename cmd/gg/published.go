@@ -0,0 +1,131 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+const allowRewritePublishedUsage = "allow rewriting commits that are already on a remote branch"
+
+// checkRewritePublished returns an error if any of the commits that op
+// would rewrite are reachable from a remote-tracking branch. revs are
+// rev-list arguments that select the commits, like "HEAD" and "^main".
+// Branches that are the head of a pull request are remote branches too,
+// so they are covered once fetched.
+func checkRewritePublished(ctx context.Context, g *git.Git, op string, revs ...string) error {
+	all, err := revList(ctx, g, revs...)
+	if err != nil {
+		return err
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	unpublished, err := revList(ctx, g, append(revs, "--not", "--remotes")...)
+	if err != nil {
+		return err
+	}
+	unpublishedSet := make(map[string]struct{}, len(unpublished))
+	for _, c := range unpublished {
+		unpublishedSet[c] = struct{}{}
+	}
+	var published []string
+	for _, c := range all {
+		if _, ok := unpublishedSet[c]; !ok {
+			published = append(published, c)
+		}
+	}
+	if len(published) == 0 {
+		return nil
+	}
+	e := &rewritePublishedError{
+		op:     op,
+		commit: published[0],
+		count:  len(published),
+	}
+	if info, err := g.CommitInfo(ctx, e.commit); err == nil {
+		e.summary = info.Summary()
+	}
+	e.refs, err = remoteBranchesContaining(ctx, g, e.commit)
+	if err != nil {
+		return err
+	}
+	return e
+}
+
+// A rewritePublishedError is returned when a command would rewrite commits
+// that were already pushed.
+type rewritePublishedError struct {
+	op      string   // what the user is doing, like "amend"
+	commit  string   // newest published commit
+	summary string   // summary of commit
+	count   int      // number of published commits to be rewritten
+	refs    []string // remote-tracking branches that contain commit
+}
+
+func (e *rewritePublishedError) Error() string {
+	where := "a remote branch"
+	switch n := len(e.refs); {
+	case n == 1:
+		where = e.refs[0]
+	case n == 2:
+		where = e.refs[0] + " and " + e.refs[1]
+	case n > 2:
+		where = fmt.Sprintf("%s and %d other remote branches", e.refs[0], n-1)
+	}
+	var what string
+	if e.count == 1 {
+		what = fmt.Sprintf("%s %q is already on %s", shortHash(e.commit), e.summary, where)
+	} else {
+		what = fmt.Sprintf("%d commits to %s are already on %s, including %s %q",
+			e.count, e.op, where, shortHash(e.commit), e.summary)
+	}
+	return what + fmt.Sprintf("; if you %s, you must then force-push with 'gg push -f', "+
+		"which replaces the published history for anyone who has fetched it. "+
+		"Pass --allow-rewrite-published to %s anyway", e.op, e.op)
+}
+
+// revList returns the hashes of the commits selected by the given
+// rev-list arguments, newest first.
+func revList(ctx context.Context, g *git.Git, revs ...string) ([]string, error) {
+	out, err := g.Output(ctx, append(append([]string{"rev-list"}, revs...), "--")...)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// remoteBranchesContaining returns the short names of the remote-tracking
+// branches that contain the given commit.
+func remoteBranchesContaining(ctx context.Context, g *git.Git, commit string) ([]string, error) {
+	out, err := g.Output(ctx, "for-each-ref", "--contains="+commit, "--format=%(refname)", "refs/remotes/")
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, ref := range strings.Fields(out) {
+		if strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+		refs = append(refs, strings.TrimPrefix(ref, "refs/remotes/"))
+	}
+	return refs, nil
+}
@@ -16,19 +16,24 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
+	"gg-scm.io/tool/internal/terminal"
 )
 
 const pullSynopsis = "pull changes from the specified source"
 
 func pull(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg pull [-u] [-r REV [...]] [SOURCE]", pullSynopsis+`
+	f := flag.NewFlagSet(true, "gg pull [-u] [--json] [-r REV [...]] [SOURCE]\n"+
+		"gg pull --all [-u] [--json] [-p PATTERN]", pullSynopsis+`
 
 	If no source repository is given, the remote called `+"`origin`"+` is used.
 	If the source repository is not a named remote, then the branches will be
@@ -36,19 +41,38 @@ func pull(ctx context.Context, cc *cmdContext, args []string) error {
 
 	Local branches with the same name as a remote branch will be
 	fast-forwarded if possible. The currently checked out branch will not be
-	fast-forwarded unless `+"`-u`"+` is passed. If a branch is removed from
+	fast-forwarded unless `+"`-u`"+` is passed or the pull-update setting is
+	on (see `+"`gg config`"+`). If a branch is removed from
 	all known remotes and the local branch points to the last-known commit for
-	that branch, then it will be moved under `+"`refs/gg-old/`"+`.
+	that branch, then it will be moved under `+"`refs/gg-old/`"+` and listed
+	after the pull. See `+"`gg old`"+` to restore or delete these branches.
 
 	If no revisions are specified, then all the remote's branches and tags
 	will be fetched. If the source is a named remote, then its remote
-	tracking branches will be pruned.`)
+	tracking branches will be pruned.
+
+	With `+"`--all`"+`, gg pulls from every remote at once, running as many
+	fetches in parallel as Git's `+"`fetch.parallel`"+` setting allows
+	(default 4). The `+"`origin`"+` remote is fetched first so that the others,
+	which usually share its history, only download what it doesn't have.
+	A remote that fails doesn't stop the others. Local branches are only
+	fast-forwarded from the remote they track, and new local branches are
+	only created for `+"`origin`"+`'s branches.
+
+	After pulling, `+"`gg pull`"+` prints a table of the local refs that were
+	created, updated, forced (moved to a commit that is not a descendant),
+	deleted, or pruned. `+"`--json`"+` prints the same information as a JSON
+	array of objects with `+"`ref`"+`, `+"`kind`"+`, `+"`old`"+`, and `+"`new`"+` fields.
+
+	`+pathAliasHelp+notesHelp)
 	var input pullInput
 	f.MultiStringVar(&input.remoteRefArgs, "r", "`ref`s to pull")
 	f.RegexpVar(&input.remoteRefPattern, "p", "`regexp` of branch or tag names to pull (can be specified multiple times)")
 	f.Alias("p", "pattern")
 	f.BoolVar(&input.forceTags, "force-tags", false, "update any tags pulled")
 	update := f.Bool("u", false, "update to new head if new descendants were pulled")
+	jsonOutput := f.Bool("json", false, "print ref changes as JSON")
+	all := f.Bool("all", false, "pull from all remotes")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -58,13 +82,28 @@ func pull(ctx context.Context, cc *cmdContext, args []string) error {
 	if f.NArg() > 1 {
 		return usagef("can't pass multiple sources")
 	}
+	if *all && f.NArg() > 0 {
+		return usagef("can't pass a source with --all")
+	}
+	if *all && len(input.remoteRefArgs) > 0 {
+		return usagef("can't pass -r with --all (use -p)")
+	}
 	cfg, err := cc.git.ReadConfig(ctx)
 	if err != nil {
 		return err
 	}
+	if !f.IsSet("u") && cfg.Value("gg.pullUpdate") != "" {
+		*update, err = cfg.Bool("gg.pullUpdate")
+		if err != nil {
+			return err
+		}
+	}
 	input.remotes = cfg.ListRemotes()
 	headBranch := currentBranch(ctx, cc)
-	input.repo = f.Arg(0)
+	if *all {
+		return pullAll(ctx, cc, cfg, &input, headBranch, *update, *jsonOutput)
+	}
+	input.repo = resolvePathAlias(cfg, f.Arg(0))
 	if input.repo == "" {
 		input.repo = "origin"
 		if _, ok := input.remotes[input.repo]; !ok {
@@ -106,34 +145,198 @@ func pull(ctx context.Context, cc *cmdContext, args []string) error {
 	}
 
 	if len(gitArgs) > 0 {
-		err = cc.interactiveGit(ctx, gitArgs...)
+		// Git's list of updated refs is replaced by the summary below,
+		// but keep showing progress for long fetches.
+		fetchArgs := []string{gitArgs[0], "--quiet"}
+		if terminal.IsTerminal(cc.stderr) {
+			fetchArgs = append(fetchArgs, "--progress")
+		}
+		err = cc.interactiveGit(ctx, append(fetchArgs, gitArgs[1:]...)...)
 		if err != nil {
 			return err
 		}
 	}
-	if err := ops.reconcile(ctx, cc.git, cc.stderr, headBranch); err != nil {
+	reconcileErr := ops.reconcile(ctx, cc.git, cc.stderr, headBranch)
+	if sync, err := syncNotes(cfg); err != nil {
+		fmt.Fprintln(cc.stderr, "gg:", err)
+	} else if sync {
+		if err := pullNotes(ctx, cc, cfg, input.repo); err != nil {
+			fmt.Fprintf(cc.stderr, "gg: pull notes: %v\n", err)
+		}
+	}
+	expired, err := expireOldBranches(ctx, cc.git, cfg, time.Now())
+	if err != nil {
+		fmt.Fprintln(cc.stderr, "gg:", err)
+	}
+	if reconcileErr == nil && *update && headBranch != "" {
+		target := pullUpdateTarget(remote, headBranch)
+		warnUntrackedChanges(ctx, cc, target.String())
+		if err := updateToBranch(ctx, cc.git, headBranch, target, git.MergeLocal); err != nil {
+			return err
+		}
+	}
+
+	newLocalRefs, err := refIteratorToMap(cc.git.IterateRefs(ctx, git.IterateRefsOptions{}))
+	if err != nil {
 		return err
 	}
-	if *update && headBranch != "" {
-		var target git.Ref
-		if remote != nil {
-			headRef := git.BranchRef(headBranch)
-			for _, spec := range remote.Fetch {
-				target = spec.Map(headRef)
-				if target != "" {
-					break
+	changes, err := diffRefs(ctx, cc.git, input.localRefs, newLocalRefs)
+	if err != nil {
+		return err
+	}
+	if err := writeRefChanges(cc.stdout, changes, *jsonOutput); err != nil {
+		return err
+	}
+	writeSavedBranchesReport(cc.stderr, ops.savedBranches, expired)
+	return reconcileErr
+}
+
+// pullAll pulls from every remote at once. input holds the flags that
+// apply to each remote.
+func pullAll(ctx context.Context, cc *cmdContext, cfg *git.Config, input *pullInput, headBranch string, update, jsonOutput bool) error {
+	if len(input.remotes) == 0 {
+		return errors.New("no remotes to pull from")
+	}
+	parallelism, err := fetchParallelism(cfg)
+	if err != nil {
+		return err
+	}
+	localRefs, err := refIteratorToMap(cc.git.IterateRefs(ctx, git.IterateRefsOptions{
+		IncludeHead: true,
+	}))
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(input.remotes))
+	for name := range input.remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	primary := ""
+	if _, ok := input.remotes["origin"]; ok {
+		primary = "origin"
+	}
+
+	allOps := make([]*deferredFetchOps, len(names))
+	jobs := make([]*fetchJob, len(names))
+	for i, name := range names {
+		i, name := i, name
+		jobs[i] = &fetchJob{
+			remote: name,
+			fetch: func(ctx context.Context, hints []string) error {
+				remoteInput := *input
+				remoteInput.repo = name
+				remoteInput.localRefs = localRefs
+				var err error
+				remoteInput.remoteRefs, err = refIteratorToMap(cc.git.IterateRemoteRefs(ctx, name, git.IterateRemoteRefsOptions{
+					LimitToBranches: true,
+					LimitToTags:     true,
+				}))
+				if err != nil {
+					return err
 				}
-			}
-		} else {
-			target = git.Ref("refs/ggpull/" + headBranch)
+				gitArgs, ops, err := remoteInput.buildFetchArgs()
+				if err != nil {
+					return err
+				}
+				ops.branches = pullAllBranches(cfg, ops.branches, name, primary, localRefs)
+				if len(gitArgs) > 0 {
+					fetchArgs := append([]string{gitArgs[0], "--quiet"}, hints...)
+					if err := cc.git.Run(ctx, append(fetchArgs, gitArgs[1:]...)...); err != nil {
+						return err
+					}
+				}
+				allOps[i] = ops
+				return nil
+			},
 		}
-		if err := updateToBranch(ctx, cc.git, headBranch, target, git.MergeLocal); err != nil {
-			return err
+	}
+	fetchErrs := runFetches(ctx, cc.stderr, jobs, parallelism, primary)
+
+	var failed []error
+	var savedBranches []string
+	for i, ops := range allOps {
+		if fetchErrs[i] != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", names[i], fetchErrs[i]))
+			continue
 		}
+		if err := ops.reconcile(ctx, cc.git, cc.stderr, headBranch); err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", names[i], err))
+		}
+		savedBranches = append(savedBranches, ops.savedBranches...)
+	}
+	expired, err := expireOldBranches(ctx, cc.git, cfg, time.Now())
+	if err != nil {
+		fmt.Fprintln(cc.stderr, "gg:", err)
+	}
+	if update && headBranch != "" {
+		// Update to the branch's upstream if its remote was pulled.
+		upstreamRemote := cfg.Value("branch." + headBranch + ".remote")
+		for i, name := range names {
+			if name != upstreamRemote || fetchErrs[i] != nil {
+				continue
+			}
+			target := pullUpdateTarget(input.remotes[name], headBranch)
+			warnUntrackedChanges(ctx, cc, target.String())
+			if err := updateToBranch(ctx, cc.git, headBranch, target, git.MergeLocal); err != nil {
+				return err
+			}
+		}
+	}
+
+	newLocalRefs, err := refIteratorToMap(cc.git.IterateRefs(ctx, git.IterateRefsOptions{}))
+	if err != nil {
+		return err
+	}
+	changes, err := diffRefs(ctx, cc.git, localRefs, newLocalRefs)
+	if err != nil {
+		return err
+	}
+	if err := writeRefChanges(cc.stdout, changes, jsonOutput); err != nil {
+		return err
+	}
+	writeSavedBranchesReport(cc.stderr, savedBranches, expired)
+	if len(failed) > 0 {
+		return fmt.Errorf("pull from %d of %d remotes failed:\n%w", len(failed), len(names), errors.Join(failed...))
 	}
 	return nil
 }
 
+// pullAllBranches returns the branches from a remote that pull --all
+// should update local branches from. A local branch is only updated
+// from the remote it tracks, so that remotes with branches of the same
+// name don't fight over it, and new local branches are only created
+// for the primary remote's branches.
+func pullAllBranches(cfg *git.Config, branches []git.Ref, remote, primary string, localRefs map[git.Ref]git.Hash) []git.Ref {
+	var keep []git.Ref
+	for _, ref := range branches {
+		if _, exists := localRefs[ref]; exists {
+			if cfg.Value("branch."+ref.Branch()+".remote") == remote {
+				keep = append(keep, ref)
+			}
+		} else if remote == primary {
+			keep = append(keep, ref)
+		}
+	}
+	return keep
+}
+
+// pullUpdateTarget returns the ref that pull -u updates the branch to
+// after fetching from remote, which is nil for a repository that isn't
+// a named remote.
+func pullUpdateTarget(remote *git.Remote, headBranch string) git.Ref {
+	if remote == nil {
+		return git.Ref("refs/ggpull/" + headBranch)
+	}
+	headRef := git.BranchRef(headBranch)
+	for _, spec := range remote.Fetch {
+		if target := spec.Map(headRef); target != "" {
+			return target
+		}
+	}
+	return ""
+}
+
 type pullInput struct {
 	remoteRefArgs    []string
 	remoteRefPattern *regexp.Regexp
@@ -155,6 +358,10 @@ type deferredFetchOps struct {
 	remoteRefs  map[git.Ref]git.Hash
 	branches    []git.Ref
 	deletedRefs map[git.Ref]git.Hash
+
+	// savedBranches is the list of local branches that reconcile deleted
+	// after saving them under oldNamespace.
+	savedBranches []string
 }
 
 // buildFetchArgs computes the set of branches and tags to fetch.
@@ -341,23 +548,13 @@ func (ops *deferredFetchOps) reconcile(ctx context.Context, g *git.Git, stderr i
 	}
 
 	if len(ops.deletedRefs) > 0 {
-		const oldNamespace = "refs/gg-old/"
-		deleteOldOlds := make(map[git.Ref]git.RefMutation)
-		for ref := range ops.localRefs {
-			if strings.HasPrefix(string(ref), oldNamespace) {
-				deleteOldOlds[ref] = git.DeleteRef()
-			}
-		}
-		if err := g.MutateRefs(ctx, deleteOldOlds); err != nil {
-			report(err)
-		}
-
 		mutations := make(map[git.Ref]git.RefMutation)
-		var branchesToDelete []string
+		var savedBranches, branchesToDelete []string
 		for ref, expectHash := range ops.deletedRefs {
 			val := expectHash.String()
 			if branchName := ref.Branch(); branchName != "" {
 				mutations[git.Ref(oldNamespace+branchName)] = git.SetRef(val)
+				savedBranches = append(savedBranches, branchName)
 				if branchName == headBranch {
 					fmt.Fprintf(stderr, "gg: currently-checked out branch '%s' was deleted on remote\n", headBranch)
 					if err := g.Run(ctx, "config", "--unset-all", "branch."+branchName+".remote"); err != nil {
@@ -375,6 +572,8 @@ func (ops *deferredFetchOps) reconcile(ctx context.Context, g *git.Git, stderr i
 		}
 		if err := g.MutateRefs(ctx, mutations); err != nil {
 			report(err)
+		} else if err := recordOldBranchTimes(ctx, g, savedBranches, time.Now()); err != nil {
+			report(err)
 		}
 		err := g.DeleteBranches(ctx, branchesToDelete, git.DeleteBranchOptions{
 			Force: true,
@@ -382,6 +581,7 @@ func (ops *deferredFetchOps) reconcile(ctx context.Context, g *git.Git, stderr i
 		if err != nil {
 			report(err)
 		}
+		ops.savedBranches = branchesToDelete
 	}
 
 	if !success {
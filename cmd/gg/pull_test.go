@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"gg-scm.io/pkg/git"
@@ -258,6 +259,42 @@ func TestPull(t *testing.T) {
 	}
 }
 
+func TestPull_JSON(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := setupPullTest(ctx, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.FromSlash("repoB"), "pull", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*refChange
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	original := commits.originalMain.String()
+	want := []*refChange{
+		{Ref: "refs/heads/delbranch", Kind: "deleted", Old: original},
+		{Ref: "refs/heads/newbranch", Kind: "created", New: original},
+		{Ref: "refs/remotes/origin/delbranch", Kind: "pruned", Old: original},
+		{Ref: "refs/remotes/origin/delbranch-local", Kind: "pruned", Old: original},
+		{Ref: "refs/remotes/origin/diverge", Kind: "updated", Old: original, New: commits.divergeCommitA.String()},
+		{Ref: "refs/remotes/origin/main", Kind: "updated", Old: original, New: commits.newMain.String()},
+		{Ref: "refs/remotes/origin/newbranch", Kind: "created", New: original},
+		{Ref: "refs/tags/second", Kind: "created", New: commits.newMain.String()},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ref changes (-want +got):\n%s", diff)
+	}
+}
 func TestPullWithArgument(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -424,6 +461,34 @@ func TestPullUpdate(t *testing.T) {
 	}
 }
 
+func TestPullUpdate_Env(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := setupPullTest(ctx, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Call gg to pull from A into B, with -u turned on by the environment.
+	env.environ = []string{"GG_PULL_UPDATE=1"}
+	repoBPath := env.root.FromSlash("repoB")
+	if _, err := env.gg(ctx, repoBPath, "pull"); err != nil {
+		t.Error(err)
+	}
+	gitB := env.git.WithDir(repoBPath)
+	if head, err := gitB.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if head.Commit != commits.newMain {
+		names := commits.Names()
+		t.Errorf("HEAD = %s; want %s", prettyCommit(head.Commit, names), prettyCommit(commits.newMain, names))
+	}
+}
+
 func TestPullRev(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -1012,3 +1077,54 @@ func hashLiteral(s string) git.Hash {
 	}
 	return h
 }
+
+func TestPull_All(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits, err := setupPullTest(ctx, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--bare", "repoA", "fork.git"); err != nil {
+		t.Fatal(err)
+	}
+	gitFork := env.git.WithDir(env.root.FromSlash("fork.git"))
+	if err := gitFork.Run(ctx, "branch", "forkbranch", "main"); err != nil {
+		t.Fatal(err)
+	}
+	repoBPath := env.root.FromSlash("repoB")
+	gitB := env.git.WithDir(repoBPath)
+	if err := gitB.Run(ctx, "remote", "add", "fork", env.root.FromSlash("fork.git")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitB.Run(ctx, "remote", "add", "broken", env.root.FromSlash("nonexistent")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, repoBPath, "pull", "--all")
+	if err == nil {
+		t.Error("gg pull --all succeeded with a broken remote")
+	}
+	if r, err := gitB.ParseRev(ctx, "refs/remotes/origin/main"); err != nil {
+		t.Error(err)
+	} else if r.Commit != commits.newMain {
+		t.Errorf("origin/main = %v; want %v", r.Commit, commits.newMain)
+	}
+	if r, err := gitB.ParseRev(ctx, "refs/remotes/fork/forkbranch"); err != nil {
+		t.Error(err)
+	} else if r.Commit != commits.newMain {
+		t.Errorf("fork/forkbranch = %v; want %v", r.Commit, commits.newMain)
+	}
+	if _, err := gitB.ParseRev(ctx, "refs/heads/forkbranch"); err == nil {
+		t.Error("pull --all created a local branch for fork/forkbranch")
+	}
+	if r, err := gitB.ParseRev(ctx, "refs/heads/newbranch"); err != nil {
+		t.Error(err)
+	} else if r.Commit != commits.originalMain {
+		t.Errorf("newbranch = %v; want %v", r.Commit, commits.originalMain)
+	}
+}
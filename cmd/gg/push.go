@@ -15,6 +15,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -28,7 +29,7 @@ import (
 const pushSynopsis = "push changes to the specified destination"
 
 func push(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg push [-f] [-r REF [...]] [--new-branch] [DST]", pushSynopsis+`
+	f := flag.NewFlagSet(true, "gg push [-f] [-r REF [...]] [--exclude REV [...]] [--new-branch | --set-upstream] [--json] [--skip-mirrors] [--signed[=if-asked]] [--signing-key KEY] [DST]", pushSynopsis+`
 
 	`+"`gg push`"+` pushes branches and tags to mirror the local repository in the
 	destination repository. It does not permit diverging commits unless `+"`-f`"+`
@@ -44,12 +45,45 @@ func push(ctx context.Context, cc *cmdContext, args []string) error {
 	By default, `+"`gg push`"+` will fail instead of creating a new ref in the
 	destination repository. If this is desired (e.g. you are creating a new
 	branch), then you can pass `+"`--new-branch`"+` to override this check.
-	`+"`-f`"+` will also skip this check.`)
+	`+"`-f`"+` will also skip this check.
+
+	`+"`--set-upstream`"+` is like `+"`--new-branch`"+`, but also makes each pushed
+	branch that does not have an upstream track the branch it was pushed to,
+	as if it had been set with `+"`gg upstream`"+`. Without `+"`-r`"+`, it pushes
+	the current branch. If Git's `+"`push.autoSetupRemote`"+` setting is on,
+	`+"`--new-branch`"+` sets upstreams the same way. The new upstream is
+	printed. A branch's description (`+"`git branch --edit-description`"+`)
+	is used as the default body by `+"`gg requestpull`"+`.
+
+	After pushing, `+"`gg push`"+` prints a table of the refs that were created,
+	updated, forced, or rejected in the destination repository. `+"`--json`"+`
+	prints the same information as a JSON array of objects with `+"`ref`"+`,
+	`+"`kind`"+`, `+"`old`"+`, `+"`new`"+`, and `+"`reason`"+` fields.
+
+	`+pushNameHelp+`
+
+	`+pathAliasHelp+`
+
+	`+pushMirrorsHelp+`
+
+	`+pushSignedHelp+`
+
+	`+pushExcludeHelp+`
+
+	`+pushLimitsHelp+hookOutputHelp+notesHelp)
 	create := f.Bool("new-branch", false, "allow pushing a new ref")
+	setUpstream := f.Bool("set-upstream", false, "allow pushing new branches and track them from the local branches")
 	force := f.Bool("f", false, "allow overwriting ref if it is not an ancestor, as long as it matches the remote-tracking branch")
 	f.Alias("f", "force")
 	runHooks := f.Bool("hooks", true, "whether to run Git hooks")
 	refArgs := f.MultiString("r", "source `ref`s")
+	excludeArgs := f.MultiString("exclude", "leave the `rev`ision out of the push")
+	jsonOutput := f.Bool("json", false, "print ref changes as JSON")
+	overrideLimits := f.Bool("override-limits", false, overrideLimitsUsage)
+	skipMirrors := f.Bool("skip-mirrors", false, "only push to the primary destination")
+	var signed pushSignedValue
+	f.Var(&signed, "signed", "send a signed push certificate: true, false, or if-asked")
+	signingKey := f.String("signing-key", "", "`key` to sign the push certificate with")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -59,36 +93,74 @@ func push(ctx context.Context, cc *cmdContext, args []string) error {
 	if f.NArg() > 1 {
 		return usagef("can't pass multiple destinations")
 	}
+	if *create && *setUpstream {
+		return usagef("can't pass both --new-branch and --set-upstream")
+	}
+	if *setUpstream && len(*refArgs) == 0 {
+		branch := currentBranch(ctx, cc)
+		if branch == "" {
+			return errors.New("--set-upstream: no branch currently checked out (pass -r)")
+		}
+		*refArgs = []string{git.BranchRef(branch).String()}
+	}
 	refsImplicit := len(*refArgs) == 0
 	if refsImplicit && (*force || *create) {
 		return usagef("can't pass --force or --new-branch without specifying refs")
 	}
-	dstRepo := f.Arg(0)
+	if len(*excludeArgs) > 0 && len(*refArgs) != 1 {
+		return usagef("--exclude requires a single branch in -r")
+	}
+	for _, rev := range *excludeArgs {
+		if rev == "" || strings.HasPrefix(rev, "-") {
+			return usagef("invalid revision %q for --exclude", rev)
+		}
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	dstRepo := resolvePathAlias(cfg, f.Arg(0))
 	if dstRepo == "" {
-		cfg, err := cc.git.ReadConfig(ctx)
+		dstRepo, err = inferPushRepo(cfg, "")
 		if err != nil {
 			return err
 		}
-		dstRepo, err = inferPushRepo(cfg, "")
+	}
+	dstRemote := cfg.ListRemotes()[dstRepo]
+	signedMode, err := pushSignedMode(cfg, signed)
+	if err != nil {
+		return err
+	}
+	if *signingKey != "" && signedMode == "false" {
+		return usagef("--signing-key requires --signed or push.gpgSign")
+	}
+	if *create && cfg.Value("push.autoSetupRemote") != "" {
+		// Git's push.autoSetupRemote makes new branches track themselves.
+		autoSetup, err := cfg.Bool("push.autoSetupRemote")
 		if err != nil {
 			return err
 		}
+		*setUpstream = autoSetup
+	}
+	localRefs, err := refIteratorToMap(cc.git.IterateRefs(ctx, git.IterateRefsOptions{
+		LimitToBranches: true,
+		LimitToTags:     true,
+	}))
+	if err != nil {
+		return err
 	}
 	var refsToPush []git.Ref
 	if refsImplicit {
-		iter := cc.git.IterateRefs(ctx, git.IterateRefsOptions{
-			LimitToBranches: true,
-			LimitToTags:     true,
-		})
-		for iter.Next() {
-			refsToPush = append(refsToPush, iter.Ref())
-		}
-		if err := iter.Close(); err != nil {
-			return err
+		for ref := range localRefs {
+			refsToPush = append(refsToPush, ref)
 		}
 		sort.Slice(refsToPush, func(i, j int) bool { return refsToPush[i] < refsToPush[j] })
 	} else {
 		for _, arg := range *refArgs {
+			arg, err := resolveBranchCase(ctx, cc.git, cfg, arg)
+			if err != nil {
+				return err
+			}
 			resolved, err := cc.git.ParseRev(ctx, arg)
 			if err != nil {
 				return err
@@ -99,28 +171,37 @@ func push(ctx context.Context, cc *cmdContext, args []string) error {
 			refsToPush = append(refsToPush, resolved.Ref)
 		}
 	}
-
-	if !*force && !*create {
-		sharedRefs := make(map[git.Ref]bool)
-		for _, ref := range refsToPush {
-			sharedRefs[ref] = false
+	// pushDsts maps the refs to push to the refs they are pushed to, if
+	// different. See remotePushRef.
+	pushDsts := make(map[git.Ref]git.Ref)
+	for _, ref := range refsToPush {
+		dst, err := remotePushRef(cfg, cc.env, ref)
+		if err != nil {
+			return err
 		}
-		remoteIter := cc.git.IterateRemoteRefs(ctx, dstRepo, git.IterateRemoteRefsOptions{
-			LimitToBranches: true,
-			LimitToTags:     true,
-		})
-		for remoteIter.Next() {
-			if _, presentLocally := sharedRefs[remoteIter.Ref()]; presentLocally {
-				sharedRefs[remoteIter.Ref()] = true
-			}
+		if dst != ref {
+			pushDsts[ref] = dst
 		}
-		if err := remoteIter.Close(); err != nil {
-			return err
+	}
+	pushDst := func(ref git.Ref) git.Ref {
+		if dst, ok := pushDsts[ref]; ok {
+			return dst
 		}
+		return ref
+	}
+	remoteRefs, err := refIteratorToMap(cc.git.IterateRemoteRefs(ctx, dstRepo, git.IterateRemoteRefsOptions{
+		LimitToBranches: true,
+		LimitToTags:     true,
+	}))
+	if err != nil {
+		return err
+	}
+
+	if !*force && !*create && !*setUpstream {
 		n := 0
 		conflicts := false
 		for _, ref := range refsToPush {
-			if presentOnRemote := sharedRefs[ref]; presentOnRemote || ref.IsTag() {
+			if _, presentOnRemote := remoteRefs[pushDst(ref)]; presentOnRemote || ref.IsTag() {
 				refsToPush[n] = ref
 				n++
 				continue
@@ -130,41 +211,290 @@ func push(ctx context.Context, cc *cmdContext, args []string) error {
 				continue
 			}
 			conflicts = true
-			fmt.Fprintf(cc.stderr, "gg: push: %q does not exist on remote\n", ref)
+			if d := pushRenamedBranch(ctx, cc.git, dstRemote, pushDst(ref), remoteRefs); d != nil {
+				fmt.Fprintf(cc.stderr, "gg: push: %v\n", d)
+			} else {
+				fmt.Fprintf(cc.stderr, "gg: push: %q does not exist on remote\n", pushDst(ref))
+			}
 		}
 		if conflicts {
-			return errors.New("push would create refs (if this is what you want, run again with --new-branch)")
+			return errors.New("push would create refs (if this is what you want, run again with --new-branch, or --set-upstream to also track them)")
 		}
 		refsToPush = refsToPush[:n]
 	}
+	for _, ref := range refsToPush {
+		if err := checkNewRemoteBranch(ctx, cc.git, pushDst(ref), remoteRefs); err != nil {
+			return fmt.Errorf("push: %w", err)
+		}
+	}
 
 	if len(refsToPush) == 0 {
 		return errors.New("no refs to push")
 	}
+	// pushSources maps the refs to push to the local refs to push them from.
+	pushSources := make(map[git.Ref]git.Ref)
+	if len(*excludeArgs) > 0 {
+		ref := refsToPush[0]
+		if !ref.IsBranch() {
+			return fmt.Errorf("--exclude requires a branch, not %s", ref)
+		}
+		var remoteHashes []string
+		for _, hash := range remoteRefs {
+			remoteHashes = append(remoteHashes, hash.String())
+		}
+		newTip, copied, dropped, err := buildExcludedPush(ctx, cc, localRefs[ref], *excludeArgs, remoteHashes)
+		if err != nil {
+			return err
+		}
+		err = cc.git.MutateRefs(ctx, map[git.Ref]git.RefMutation{
+			pushExcludeRef: git.SetRef(newTip.String()),
+		})
+		if err != nil {
+			return err
+		}
+		defer cc.git.MutateRefs(ctx, map[git.Ref]git.RefMutation{
+			pushExcludeRef: git.DeleteRef(),
+		})
+		localRefs[pushExcludeRef] = newTip
+		pushSources[ref] = pushExcludeRef
+		fmt.Fprintf(cc.stderr, "gg: push: leaving out %s of %s (the local branch is unchanged)\n", countCommits(dropped), ref.Branch())
+		if copied > 0 {
+			fmt.Fprintf(cc.stderr, "gg: push: pushing copies of the later %s, ending in %s\n", countCommits(copied), newTip.Short())
+		}
+	}
+	if !*overrideLimits {
+		limits, err := readPushLimits(cfg)
+		if err != nil {
+			return err
+		}
+		revs := make([]string, 0, len(refsToPush))
+		for _, ref := range refsToPush {
+			if src, ok := pushSources[ref]; ok {
+				ref = src
+			}
+			revs = append(revs, localRefs[ref].String())
+		}
+		exclude := make([]string, 0, len(remoteRefs))
+		for _, hash := range remoteRefs {
+			exclude = append(exclude, hash.String())
+		}
+		if err := checkPushLimits(ctx, cc, limits, revs, exclude); err != nil {
+			return err
+		}
+	}
 
-	var pushArgs []string
-	pushArgs = append(pushArgs, "push")
+	var pushFlags []string
+	pushFlags = append(pushFlags, "push", "--porcelain")
 	if *force {
-		pushArgs = append(pushArgs, "--force-with-lease")
+		pushFlags = append(pushFlags, "--force-with-lease")
 	}
 	if !*runHooks {
-		pushArgs = append(pushArgs, "--no-verify")
+		pushFlags = append(pushFlags, "--no-verify")
+	}
+	if signed != "" {
+		pushFlags = append(pushFlags, "--signed="+string(signed))
 	}
-	pushArgs = append(pushArgs, "--", dstRepo)
+	pushArgs := append(append([]string(nil), pushFlags...), "--", dstRepo)
+	// pushedFrom maps the refs in the destination to the local refs
+	// pushed to them.
+	pushedFrom := make(map[git.Ref]git.Ref)
+	// refspecs maps the refs to push to their arguments to git push.
+	refspecs := make(map[git.Ref][]string)
 	for _, ref := range refsToPush {
-		if tag := ref.Tag(); tag != "" {
-			pushArgs = append(pushArgs, "tag", tag)
+		dst := pushDst(ref)
+		pushedFrom[dst] = ref
+		if dst != ref {
+			fmt.Fprintf(cc.stderr, "gg: push: %s → %s\n", ref.Branch(), dst.Branch())
+		}
+		if src, ok := pushSources[ref]; ok {
+			refspecs[ref] = []string{src.String() + ":" + dst.String()}
+		} else if tag := ref.Tag(); tag != "" {
+			refspecs[ref] = []string{"tag", tag}
 		} else {
-			pushArgs = append(pushArgs, ref.String()+":"+ref.String())
+			refspecs[ref] = []string{ref.String() + ":" + dst.String()}
+		}
+		pushArgs = append(pushArgs, refspecs[ref]...)
+	}
+	signCC := cc
+	if signedMode != "false" {
+		for _, param := range pushSigningConfig(cfg, *signingKey) {
+			name, value, _ := strings.Cut(param, "=")
+			signCC, err = signCC.withGitConfig(name, value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Rejected branches are explained below instead of by Git.
+	pushCC, err := signCC.withGitConfig("advice.pushUpdateRejected", "false")
+	if err != nil {
+		return err
+	}
+	pushCC, err = pushCC.withGitConfig("core.abbrev", pushAbbrev)
+	if err != nil {
+		return err
+	}
+	porcelain := new(bytes.Buffer)
+	pushStderr := new(strings.Builder)
+	pushErr := runGitWithHooks(ctx, pushCC, &git.Invocation{
+		Dir:    cc.dir,
+		Args:   pushArgs,
+		Stdin:  cc.stdin,
+		Stdout: porcelain,
+		Stderr: pushStderr,
+	})
+	changes := parsePushPorcelain(porcelain.String(), localRefs)
+	if err := writeRefChanges(cc.stdout, changes, *jsonOutput); err != nil {
+		return err
+	}
+	if pushErr != nil {
+		for _, c := range changes {
+			branch := c.Ref.Branch()
+			remoteHash, existed := remoteRefs[c.Ref]
+			if c.Kind != refRejected || branch == "" || !existed || !strings.HasPrefix(c.Reason, "[rejected]") {
+				continue
+			}
+			upstream := branch + " in " + dstRepo
+			if dstRemote != nil {
+				upstream = dstRepo + "/" + branch
+			}
+			d, err := pushDivergence(ctx, cc.git, pushedFrom[c.Ref].Branch(), upstream, localRefs[pushedFrom[c.Ref]], remoteHash)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(cc.stderr, "gg: push: %v\n", d)
+		}
+		if signedMode != "false" {
+			if hint := explainSignedPushError(dstRepo, pushStderr.String()); hint != "" {
+				fmt.Fprintf(cc.stderr, "gg: push: %s\n", hint)
+			}
+		}
+		return fmt.Errorf("git push: %w", pushErr)
+	}
+	if signedMode == "true" && len(changes) > 0 {
+		fmt.Fprintf(cc.stderr, "gg: push: %s accepted the signed push certificate\n", dstRepo)
+	}
+	if sync, err := syncNotes(cfg); err != nil {
+		fmt.Fprintln(cc.stderr, "gg:", err)
+	} else if sync {
+		if err := pushNotes(ctx, cc, cfg, dstRepo); err != nil {
+			fmt.Fprintf(cc.stderr, "gg: push notes: %v\n", err)
+		}
+	}
+	if *setUpstream {
+		if err := setPushedUpstreams(ctx, cc, cfg, dstRepo, refsToPush, pushDst); err != nil {
+			return err
+		}
+	}
+	if f.Arg(0) != "" || *skipMirrors {
+		return nil
+	}
+	return pushMirrors(ctx, signCC, cfg, dstRepo, refsToPush, pushFlags, refspecs, localRefs)
+}
+
+// setPushedUpstreams makes each pushed branch without an upstream track
+// the branch it was pushed to in dstRepo.
+func setPushedUpstreams(ctx context.Context, cc *cmdContext, cfg *git.Config, dstRepo string, refs []git.Ref, pushDst func(git.Ref) git.Ref) error {
+	for _, ref := range refs {
+		branch := ref.Branch()
+		if branch == "" || cfg.Value("branch."+branch+".merge") != "" {
+			continue
+		}
+		if err := cc.git.Run(ctx, "config", "branch."+branch+".remote", dstRepo); err != nil {
+			return err
+		}
+		dst := pushDst(ref)
+		if err := cc.git.Run(ctx, "config", "branch."+branch+".merge", dst.String()); err != nil {
+			return err
 		}
+		fmt.Fprintf(cc.stderr, "gg: push: branch %s now tracks %s/%s\n", branch, dstRepo, dst.Branch())
 	}
-	return cc.interactiveGit(ctx, pushArgs...)
+	return nil
+}
+
+// pushRenamedBranch returns a divergence if ref does not exist in the
+// remote, but a different branch on the remote points to the commit
+// that ref's remote-tracking branch last saw.
+func pushRenamedBranch(ctx context.Context, g *git.Git, remote *git.Remote, ref git.Ref, remoteRefs map[git.Ref]git.Hash) *divergence {
+	if remote == nil || !ref.IsBranch() {
+		return nil
+	}
+	trackingRef := remote.MapFetch(ref)
+	if trackingRef == "" {
+		return nil
+	}
+	lastKnown, err := g.ParseRev(ctx, trackingRef.String())
+	if err != nil {
+		return nil
+	}
+	return renamedDivergence(ref.Branch(), remote.Name+"/"+ref.Branch(), lastKnown.Commit, remoteRefs)
+}
+
+// pushAbbrev is the core.abbrev setting that makes `git push --porcelain`
+// print whole commit hashes in its summaries.
+const pushAbbrev = "40"
+
+// parsePushPorcelain returns the ref changes reported in the output of
+// `git push --porcelain`. The old and new hashes come from Git's summary
+// of each ref, so the push should run with core.abbrev set to pushAbbrev.
+// Git doesn't print hashes for created or rejected refs, so those are
+// filled in from localRefs. Refs that were already up-to-date are omitted.
+func parsePushPorcelain(out string, localRefs map[git.Ref]git.Hash) []*refChange {
+	var changes []*refChange
+	for _, line := range strings.Split(out, "\n") {
+		// Lines are of the form "<flag> \t <from>:<to> \t <summary>".
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 || len(parts[0]) != 1 {
+			continue
+		}
+		from, to, ok := strings.Cut(parts[1], ":")
+		if !ok {
+			continue
+		}
+		c := &refChange{Ref: git.Ref(to)}
+		switch parts[0][0] {
+		case ' ':
+			c.Kind = refUpdated
+			c.Old, c.New, _ = strings.Cut(parts[2], "..")
+		case '+':
+			c.Kind = refForced
+			c.Old, c.New, _ = strings.Cut(strings.TrimSuffix(parts[2], " (forced update)"), "...")
+		case '*':
+			c.Kind = refCreated
+			if hash, ok := localRefs[git.Ref(from)]; ok {
+				c.New = hash.String()
+			}
+		case '-':
+			c.Kind = refDeleted
+		case '!':
+			c.Kind = refRejected
+			c.Reason = parts[2]
+			if hash, ok := localRefs[git.Ref(from)]; ok {
+				c.New = hash.String()
+			}
+		default:
+			// Up-to-date.
+			continue
+		}
+		changes = append(changes, c)
+	}
+	sortRefChanges(changes)
+	return changes
 }
 
 const mailSynopsis = "creates or updates a Gerrit change"
 
 func mail(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg mail [options] [DST]", mailSynopsis)
+	f := flag.NewFlagSet(true, "gg mail [options] [DST]", mailSynopsis+`
+
+	If `+"`-d`"+` is not given, the change is sent for review on the source
+	branch's upstream, or the branch's push name if it has no upstream.
+
+	`+pushNameHelp+`
+
+	`+pushLimitsHelp+`
+
+	`+pathAliasHelp)
 	allowDirty := f.Bool("allow-dirty", false, "allow mailing when working copy has uncommitted changes")
 	dstBranch := f.String("d", "", "destination `branch`")
 	f.Alias("d", "dest", "for")
@@ -181,6 +511,7 @@ func mail(ctx context.Context, cc *cmdContext, args []string) error {
 	f.StringVar(&gopts.message, "m", "", "use text as comment `message`")
 	f.BoolVar(&gopts.publishComments, "p", false, "publish draft comments")
 	f.Alias("p", "publish-comments")
+	overrideLimits := f.Bool("override-limits", false, overrideLimitsUsage)
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -218,14 +549,19 @@ func mail(ctx context.Context, cc *cmdContext, args []string) error {
 				"Either commit them, stash them, or use gg mail --allow-dirty if this is intentional.")
 		}
 	}
-	dstRepo := f.Arg(0)
-	var cfg *git.Config
-	if dstRepo == "" || *dstBranch == "" {
-		var err error
-		cfg, err = cc.git.ReadConfig(ctx)
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	dstRepo := resolvePathAlias(cfg, f.Arg(0))
+	if !*overrideLimits {
+		limits, err := readPushLimits(cfg)
 		if err != nil {
 			return err
 		}
+		if err := checkPushLimits(ctx, cc, limits, []string{src.Commit.String()}, nil); err != nil {
+			return err
+		}
 	}
 	if dstRepo == "" {
 		var err error
@@ -244,6 +580,16 @@ func mail(ctx context.Context, cc *cmdContext, args []string) error {
 		if *dstBranch == "" {
 			return fmt.Errorf("cannot infer destination (upstream %s is not a branch). Use -d to specify destination branch.", up)
 		}
+		if cfg.Value("branch."+branch+".merge") == "" {
+			// No upstream: use the name the branch is pushed as.
+			*dstBranch, err = remoteBranchName(cfg, cc.env, branch)
+			if err != nil {
+				return err
+			}
+			if *dstBranch != branch {
+				fmt.Fprintf(cc.stderr, "gg: mail: %s → %s\n", branch, *dstBranch)
+			}
+		}
 	} else {
 		*dstBranch = strings.TrimPrefix(*dstBranch, "refs/for/")
 	}
@@ -16,12 +16,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"net/url"
 	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestPush(t *testing.T) {
@@ -87,6 +89,174 @@ func TestPush(t *testing.T) {
 	}
 }
 
+func TestPush_JSON(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	repoAPath := env.root.FromSlash("repoA")
+	gitA := env.git.WithDir(repoAPath)
+	rev1, err := gitA.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.InitBare(ctx, env.root.FromSlash("repoB")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "remote", "add", "origin", env.root.FromSlash("repoB")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "push", "--set-upstream", "origin", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	commit2, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.NewBranch(ctx, "feature", git.BranchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, repoAPath, "push", "--json", "--new-branch", "-r", "main", "-r", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*refChange
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	want := []*refChange{
+		{Ref: "refs/heads/feature", Kind: "created", New: commit2.String()},
+		{Ref: "refs/heads/main", Kind: "updated", Old: rev1.Commit.String(), New: commit2.String()},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ref changes (-want +got):\n%s", diff)
+	}
+}
+
+func TestPush_Diverged(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	repoAPath := env.root.FromSlash("repoA")
+	gitA := env.git.WithDir(repoAPath)
+	if err := env.git.InitBare(ctx, env.root.FromSlash("repoB")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "remote", "add", "origin", env.root.FromSlash("repoB")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "push", "--set-upstream", "origin", "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Push a commit to repo B from another clone.
+	if err := env.git.Run(ctx, "clone", "repoB", "repoC"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoC/bar.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoC/bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "repoC"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.WithDir(env.root.FromSlash("repoC")).Run(ctx, "push", "origin", "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a different commit in repo A.
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, repoAPath, "push"); err == nil {
+		t.Error("push did not return error")
+	}
+	const wantUnfetched = "gg: push: origin/main has commits that are not in the local repository; run 'gg pull', then 'gg rebase'"
+	if got := env.stderr.String(); !strings.Contains(got, wantUnfetched) {
+		t.Errorf("stderr = %q; want to contain %q", got, wantUnfetched)
+	}
+
+	if err := gitA.Run(ctx, "fetch", "origin"); err != nil {
+		t.Fatal(err)
+	}
+	env.stderr.Reset()
+	if _, err := env.gg(ctx, repoAPath, "push"); err == nil {
+		t.Error("push did not return error")
+	}
+	const wantDiverged = "gg: push: main has diverged from origin/main (1 commit ahead, 1 commit behind); run 'gg pull', then 'gg rebase'"
+	if got := env.stderr.String(); !strings.Contains(got, wantDiverged) {
+		t.Errorf("stderr = %q; want to contain %q", got, wantDiverged)
+	}
+}
+
+func TestPush_RenamedBranch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	repoAPath := env.root.FromSlash("repoA")
+	gitA := env.git.WithDir(repoAPath)
+	if err := gitA.NewBranch(ctx, "feature", git.BranchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.InitBare(ctx, env.root.FromSlash("repoB")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "remote", "add", "origin", env.root.FromSlash("repoB")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "push", "origin", "main", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.WithDir(env.root.FromSlash("repoB")).Run(ctx, "branch", "-m", "feature", "feature2"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, repoAPath, "push", "-r", "feature")
+	if err == nil {
+		t.Error("push did not return error")
+	}
+	const want = "gg: push: origin/feature no longer exists, but branch feature2 on the remote points to the same commit"
+	if got := env.stderr.String(); !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+}
+
 func TestPush_Arg(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -322,6 +492,86 @@ func TestPush_CreateRef(t *testing.T) {
 	}
 }
 
+func TestPush_SetUpstream(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		autoConfig bool
+		args       []string
+	}{
+		{name: "Flag", args: []string{"push", "--set-upstream"}},
+		{name: "AutoSetupRemote", autoConfig: true, args: []string{"push", "-r", "foo", "--new-branch"}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			env, err := newTestEnv(ctx, t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+				t.Fatal(err)
+			}
+			repoAPath := env.root.FromSlash("repoA")
+			gitA := env.git.WithDir(repoAPath)
+			if err := env.git.InitBare(ctx, "repoB"); err != nil {
+				t.Fatal(err)
+			}
+			repoBPath := env.root.FromSlash("repoB")
+			if err := gitA.Run(ctx, "remote", "add", "origin", repoBPath); err != nil {
+				t.Fatal(err)
+			}
+			if err := gitA.Run(ctx, "push", "--set-upstream", "origin", "main"); err != nil {
+				t.Fatal(err)
+			}
+			if test.autoConfig {
+				if err := gitA.Run(ctx, "config", "push.autoSetupRemote", "true"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := gitA.NewBranch(ctx, "foo", git.BranchOptions{Checkout: true}); err != nil {
+				t.Fatal(err)
+			}
+			if err := env.root.Apply(filesystem.Write("repoA/foo.txt", dummyContent)); err != nil {
+				t.Fatal(err)
+			}
+			if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+				t.Fatal(err)
+			}
+			commit2, err := env.newCommit(ctx, "repoA")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			env.stderr.Reset()
+			if _, err := env.gg(ctx, repoAPath, test.args...); err != nil {
+				t.Fatal(err)
+			}
+			gitB := env.git.WithDir(repoBPath)
+			if r, err := gitB.ParseRev(ctx, "refs/heads/foo"); err != nil {
+				t.Error(err)
+			} else if r.Commit != commit2 {
+				t.Errorf("refs/heads/foo = %v; want %v", r.Commit, commit2)
+			}
+			cfg, err := gitA.ReadConfig(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := cfg.Value("branch.foo.remote"), "origin"; got != want {
+				t.Errorf("branch.foo.remote = %q; want %q", got, want)
+			}
+			if got, want := cfg.Value("branch.foo.merge"), "refs/heads/foo"; got != want {
+				t.Errorf("branch.foo.merge = %q; want %q", got, want)
+			}
+			if got, want := env.stderr.String(), "foo now tracks origin/foo"; !strings.Contains(got, want) {
+				t.Errorf("stderr = %q; want to contain %q", got, want)
+			}
+		})
+	}
+}
+
 func TestPush_RewindFails(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -744,6 +994,103 @@ func TestPush_NoCreatePushURLMissingBranch(t *testing.T) {
 	}
 }
 
+func TestPush_Limits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create repository with some junk history and push it to repo B.
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	repoAPath := env.root.FromSlash("repoA")
+	gitA := env.git.WithDir(repoAPath)
+	rev1, err := gitA.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.InitBare(ctx, env.root.FromSlash("repoB")); err != nil {
+		t.Fatal(err)
+	}
+	repoBPath := env.root.FromSlash("repoB")
+	if err := gitA.Run(ctx, "remote", "add", "origin", repoBPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "push", "--set-upstream", "origin", "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create two new commits in repo A, the second with a 4 KiB file.
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoA/big.bin", strings.Repeat("x", 4096))); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/big.bin"); err != nil {
+		t.Fatal(err)
+	}
+	commit3, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gitB := env.git.WithDir(repoBPath)
+	checkRemoteMain := func(want git.Hash) {
+		t.Helper()
+		if r, err := gitB.ParseRev(ctx, "refs/heads/main"); err != nil {
+			t.Error(err)
+		} else if r.Commit != want {
+			names := map[git.Hash]string{
+				rev1.Commit: "shared commit",
+				commit3:     "local commit",
+			}
+			t.Errorf("refs/heads/main = %s; want %s",
+				prettyCommit(r.Commit, names),
+				prettyCommit(want, names))
+		}
+	}
+
+	// Too many commits.
+	if err := env.writeConfig([]byte("[gg \"push\"]\nmaxCommits = 1\n")); err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.gg(ctx, repoAPath, "push")
+	if err == nil {
+		t.Error("push of 2 commits succeeded with gg.push.maxCommits = 1")
+	} else if want := "push would send 2 commits"; !strings.Contains(err.Error(), want) {
+		t.Errorf("push error = %q; want to contain %q", err, want)
+	}
+	checkRemoteMain(rev1.Commit)
+
+	// Too large.
+	if err := env.writeConfig([]byte("[gg \"push\"]\nmaxCommits = 0\nmaxSize = 1k\n")); err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.gg(ctx, repoAPath, "push")
+	if err == nil {
+		t.Error("push of 4 KiB file succeeded with gg.push.maxSize = 1k")
+	} else if want := "the largest is big.bin (4.0 KiB)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("push error = %q; want to contain %q", err, want)
+	}
+	checkRemoteMain(rev1.Commit)
+
+	// Override.
+	if _, err := env.gg(ctx, repoAPath, "push", "--override-limits"); err != nil {
+		t.Error(err)
+	}
+	checkRemoteMain(commit3)
+}
+
 func TestGerritPushRef(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -954,3 +1301,118 @@ func gerritOptionsEqual(m1, m2 map[string][]string) bool {
 	}
 	return true
 }
+
+func TestParsePushPorcelain(t *testing.T) {
+	const (
+		hash1 = "8bde7f5b7d57a4b3b1d2d0a6f6a1bb4d11f3b1f5"
+		hash2 = "2c7c9ac1f2b3a6d16fbf0cda31e27c4cb2c28c0e"
+		hash3 = "44a0d627ffb95e358f3300a9bfd7de32efccde11"
+	)
+	local := map[git.Ref]git.Hash{
+		"refs/heads/main":  mustParseHash(t, hash2),
+		"refs/heads/new":   mustParseHash(t, hash2),
+		"refs/heads/stale": mustParseHash(t, hash1),
+		"refs/heads/topic": mustParseHash(t, hash3),
+		"refs/tags/v1":     mustParseHash(t, hash1),
+	}
+	out := "To https://example.com/foo.git\n" +
+		" \trefs/heads/main:refs/heads/main\t" + hash1 + ".." + hash2 + "\n" +
+		"*\trefs/heads/new:refs/heads/new\t[new branch]\n" +
+		"!\trefs/heads/stale:refs/heads/stale\t[rejected] (non-fast-forward)\n" +
+		"+\trefs/heads/topic:refs/heads/topic\t" + hash1 + "..." + hash3 + " (forced update)\n" +
+		"-\t:refs/heads/old\t[deleted]\n" +
+		"=\trefs/tags/v1:refs/tags/v1\t[up to date]\n" +
+		"Done\n"
+	got := parsePushPorcelain(out, local)
+	want := []*refChange{
+		{Ref: "refs/heads/main", Kind: "updated", Old: hash1, New: hash2},
+		{Ref: "refs/heads/new", Kind: "created", New: hash2},
+		{Ref: "refs/heads/old", Kind: "deleted"},
+		{Ref: "refs/heads/stale", Kind: "rejected", New: hash1, Reason: "[rejected] (non-fast-forward)"},
+		{Ref: "refs/heads/topic", Kind: "forced", Old: hash1, New: hash3},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parsePushPorcelain(...) (-want +got):\n%s", diff)
+	}
+}
+
+func mustParseHash(tb testing.TB, s string) git.Hash {
+	tb.Helper()
+	h, err := git.ParseHash(s)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return h
+}
+
+func TestPush_Exclude(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	repoAPath := env.root.FromSlash("repoA")
+	gitA := env.git.WithDir(repoAPath)
+	if err := env.git.InitBare(ctx, env.root.FromSlash("repoB")); err != nil {
+		t.Fatal(err)
+	}
+	repoBPath := env.root.FromSlash("repoB")
+	if err := gitA.Run(ctx, "remote", "add", "origin", repoBPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "push", "--set-upstream", "origin", "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create three new commits that touch different files.
+	var commits []git.Hash
+	for _, name := range []string{"foo.txt", "bar.txt", "baz.txt"} {
+		if err := env.root.Apply(filesystem.Write("repoA/"+name, dummyContent)); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, "repoA/"+name); err != nil {
+			t.Fatal(err)
+		}
+		c, err := env.newCommit(ctx, "repoA")
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, c)
+	}
+	if _, err := env.gg(ctx, repoAPath, "push", "-r", "main", "--exclude", commits[1].String()); err != nil {
+		t.Fatal(err)
+	}
+
+	gitB := env.git.WithDir(repoBPath)
+	pushed, err := gitB.CommitInfo(ctx, "refs/heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pushed.Parents) != 1 || pushed.Parents[0] != commits[0] {
+		t.Errorf("parents of pushed main = %v; want [%v]", pushed.Parents, commits[0])
+	}
+	if _, err := gitB.Output(ctx, "cat-file", "-e", "refs/heads/main:baz.txt"); err != nil {
+		t.Error("baz.txt not in pushed commit")
+	}
+	if _, err := gitB.Output(ctx, "cat-file", "-e", "refs/heads/main:bar.txt"); err == nil {
+		t.Error("bar.txt is in pushed commit; want excluded")
+	}
+	if r, err := gitA.ParseRev(ctx, "refs/heads/main"); err != nil {
+		t.Error(err)
+	} else if r.Commit != commits[2] {
+		t.Errorf("local main = %v; want unchanged %v", r.Commit, commits[2])
+	}
+	if _, err := gitA.ParseRev(ctx, pushExcludeRef.String()); err == nil {
+		t.Errorf("%s still exists after push", pushExcludeRef)
+	}
+
+	if _, err := env.gg(ctx, repoAPath, "push", "--exclude", "HEAD"); err == nil {
+		t.Error("push --exclude without -r succeeded")
+	} else if !isUsage(err) {
+		t.Errorf("push --exclude without -r = %v; want usage error", err)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// pushExcludeRef is the temporary ref that holds the commits to push
+// when push --exclude leaves out some commits.
+const pushExcludeRef git.Ref = "refs/gg/push-exclude"
+
+// pushExcludeHelp is the paragraph of push's help text that describes
+// --exclude.
+const pushExcludeHelp = `With ` + "`--exclude`" + `, the given commits are left out of the push.
+	This requires a single branch in ` + "`-r`" + `. The new commits that come
+	after the first excluded commit are copied without the excluded
+	ones, and the copies are pushed, so their hashes differ from the
+	local commits. The local branch is not changed. This is useful for
+	sending only the reviewed commits of a branch. The push fails if a
+	remaining commit does not apply without the excluded ones.`
+
+// buildExcludedPush returns the tip of the history to push instead of tip
+// so that the commits named by exclude are left out. Commits that come
+// after an excluded commit and are not reachable from any
+// remote-tracking branch or the hashes in remote are copied without
+// the excluded commits. The working copy is not touched: the commits are
+// copied in a temporary worktree.
+func buildExcludedPush(ctx context.Context, cc *cmdContext, tip git.Hash, exclude []string, remote []string) (newTip git.Hash, copied, dropped int, err error) {
+	revListArgs := []string{"rev-list", "--reverse", "--topo-order", "--parents", "--ignore-missing", tip.String(), "--not", "--remotes"}
+	revListArgs = append(revListArgs, remote...)
+	out, err := cc.git.Output(ctx, append(revListArgs, "--")...)
+	if err != nil {
+		return git.Hash{}, 0, 0, fmt.Errorf("list commits to push: %w", err)
+	}
+	var commits []string
+	inRange := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			return git.Hash{}, 0, 0, fmt.Errorf("cannot exclude commits from a push that contains merge commit %s", fields[0][:12])
+		}
+		if len(fields) == 1 {
+			return git.Hash{}, 0, 0, fmt.Errorf("cannot exclude commits from a push that contains root commit %s", fields[0][:12])
+		}
+		commits = append(commits, fields[0])
+		inRange[fields[0]] = true
+	}
+	if len(commits) == 0 {
+		return git.Hash{}, 0, 0, errors.New("no commits to push")
+	}
+	excluded := make(map[string]bool)
+	for _, rev := range exclude {
+		r, err := cc.git.ParseRev(ctx, rev)
+		if err != nil {
+			return git.Hash{}, 0, 0, fmt.Errorf("exclude: %w", err)
+		}
+		if !inRange[r.Commit.String()] {
+			return git.Hash{}, 0, 0, fmt.Errorf("exclude: %s is not one of the commits being pushed", rev)
+		}
+		excluded[r.Commit.String()] = true
+	}
+
+	// Commits before the first excluded one are pushed as they are.
+	first := 0
+	for !excluded[commits[first]] {
+		first++
+	}
+	base, err := cc.git.ParseRev(ctx, commits[first]+"~")
+	if err != nil {
+		return git.Hash{}, 0, 0, err
+	}
+	var picks []string
+	for _, c := range commits[first:] {
+		if !excluded[c] {
+			picks = append(picks, c)
+		}
+	}
+	if len(picks) == 0 {
+		if first == 0 {
+			return git.Hash{}, 0, 0, errors.New("all commits to push are excluded")
+		}
+		return base.Commit, 0, len(excluded), nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gg-push-exclude-")
+	if err != nil {
+		return git.Hash{}, 0, 0, err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := cc.git.Run(ctx, "worktree", "add", "--quiet", "--detach", tmpDir, base.Commit.String()); err != nil {
+		return git.Hash{}, 0, 0, fmt.Errorf("create temporary worktree: %w", err)
+	}
+	defer cc.git.Run(ctx, "worktree", "remove", "--force", tmpDir)
+	tmpGit := cc.git.WithDir(tmpDir)
+	for _, c := range picks {
+		if err := tmpGit.Run(ctx, "cherry-pick", "--allow-empty", "--keep-redundant-commits", c); err != nil {
+			return git.Hash{}, 0, 0, fmt.Errorf("commit %s does not apply without the excluded commits", c[:12])
+		}
+	}
+	head, err := tmpGit.Head(ctx)
+	if err != nil {
+		return git.Hash{}, 0, 0, err
+	}
+	return head.Commit, len(picks), len(excluded), nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// Pushes larger than these limits print a warning unless
+// gg.push.maxCommits or gg.push.maxSize is set.
+const (
+	defaultPushMaxCommits = 100
+	defaultPushMaxSize    = 50 << 20
+)
+
+const overrideLimitsUsage = "push even if it exceeds gg.push.maxCommits or gg.push.maxSize"
+
+// pushLimitsHelp is the paragraph of help text shared by push and mail.
+const pushLimitsHelp = `To catch accidental pushes of a merged-in main branch or of large
+	binaries, gg warns if the push would send more than 100 new commits
+	or more than 50m of new objects. If ` + "`gg.push.maxCommits`" + ` or
+	` + "`gg.push.maxSize`" + ` is set, the push is refused instead when it
+	exceeds that limit. A limit of 0 disables the check. Pass
+	` + "`--override-limits`" + ` to push anyway.`
+
+// pushLimits is the maximum amount of new history that push and mail
+// send without a warning. Zero means no limit.
+type pushLimits struct {
+	maxCommits int
+	maxSize    int64
+
+	// refuseCommits and refuseSize are true if the corresponding limit
+	// was set in the configuration, in which case exceeding it is an
+	// error rather than a warning.
+	refuseCommits bool
+	refuseSize    bool
+}
+
+func readPushLimits(cfg *git.Config) (pushLimits, error) {
+	limits := pushLimits{
+		maxCommits: defaultPushMaxCommits,
+		maxSize:    defaultPushMaxSize,
+	}
+	if v := cfg.Value("gg.push.maxCommits"); v != "" {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || n < 0 {
+			return pushLimits{}, fmt.Errorf("gg.push.maxCommits: invalid count %q", v)
+		}
+		limits.maxCommits = n
+		limits.refuseCommits = true
+	}
+	if v := cfg.Value("gg.push.maxSize"); v != "" {
+		n, err := parseFileSize(v)
+		if err != nil {
+			return pushLimits{}, fmt.Errorf("gg.push.maxSize: %w", err)
+		}
+		limits.maxSize = n
+		limits.refuseSize = true
+	}
+	return limits, nil
+}
+
+// checkPushLimits checks whether the commits reachable from revs,
+// but not from exclude or any remote-tracking branch, exceed limits.
+// It returns an error for a configured limit and prints a warning for
+// a default one. Revisions in exclude that are not present locally
+// are ignored.
+func checkPushLimits(ctx context.Context, cc *cmdContext, limits pushLimits, revs, exclude []string) error {
+	if limits.maxCommits == 0 && limits.maxSize == 0 {
+		return nil
+	}
+	revListArgs := []string{"rev-list", "--ignore-missing"}
+	revListArgs = append(revListArgs, revs...)
+	revListArgs = append(revListArgs, "--not", "--remotes")
+	revListArgs = append(revListArgs, exclude...)
+	if limits.maxCommits > 0 {
+		out, err := cc.git.Output(ctx, append(append([]string(nil), revListArgs...), "--count", "--")...)
+		if err != nil {
+			return fmt.Errorf("count new commits: %w", err)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(out))
+		if err != nil {
+			return fmt.Errorf("count new commits: %w", err)
+		}
+		if n > limits.maxCommits {
+			if limits.refuseCommits {
+				return fmt.Errorf("push would send %s, more than gg.push.maxCommits (%d). "+
+					"Check that you did not merge another branch by mistake, "+
+					"or pass --override-limits to push anyway", countCommits(n), limits.maxCommits)
+			}
+			fmt.Fprintf(cc.stderr, "gg: warning: pushing %s; check that you did not merge another branch by mistake\n", countCommits(n))
+		}
+	}
+	if limits.maxSize > 0 {
+		total, largest, largestPath, err := newObjectsSize(ctx, cc, append(revListArgs, "--objects", "--"))
+		if err != nil {
+			return fmt.Errorf("measure new objects: %w", err)
+		}
+		if total > limits.maxSize {
+			var largestMsg string
+			if largestPath != "" {
+				largestMsg = fmt.Sprintf("; the largest is %s (%s)", largestPath, formatFileSize(largest))
+			}
+			if limits.refuseSize {
+				return fmt.Errorf("push would send %s of new objects, more than gg.push.maxSize (%s)%s. "+
+					"Pass --override-limits to push anyway",
+					formatFileSize(total), formatFileSize(limits.maxSize), largestMsg)
+			}
+			fmt.Fprintf(cc.stderr, "gg: warning: pushing %s of new objects%s\n", formatFileSize(total), largestMsg)
+		}
+	}
+	return nil
+}
+
+// newObjectsSize sums the uncompressed sizes of the objects listed by
+// the given `git rev-list --objects` invocation. It also returns the
+// size and path of the largest file.
+func newObjectsSize(ctx context.Context, cc *cmdContext, revListArgs []string) (total, largest int64, largestPath string, err error) {
+	out, err := cc.git.Output(ctx, revListArgs...)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if out == "" {
+		return 0, 0, "", nil
+	}
+	paths := make(map[string]string)
+	ids := new(strings.Builder)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		id, path, _ := strings.Cut(line, " ")
+		paths[id] = path
+		ids.WriteString(id)
+		ids.WriteByte('\n')
+	}
+	sizes := new(bytes.Buffer)
+	err = cc.git.Runner().RunGit(ctx, &git.Invocation{
+		Args:   []string{"cat-file", "--batch-check=%(objectname) %(objecttype) %(objectsize)"},
+		Dir:    cc.dir,
+		Stdin:  strings.NewReader(ids.String()),
+		Stdout: sizes,
+	})
+	if err != nil {
+		return 0, 0, "", err
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(sizes.String(), "\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		n, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		total += n
+		if fields[1] == "blob" && n > largest {
+			largest, largestPath = n, paths[fields[0]]
+		}
+	}
+	return total, largest, largestPath, nil
+}
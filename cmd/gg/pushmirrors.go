@@ -0,0 +1,109 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"golang.org/x/exp/slices"
+)
+
+const pushMirrorsHelp = `When no destination repository is given, the pushed refs are also sent
+	to the mirror remotes named in the space-separated
+	` + "`gg.push.mirrors`" + ` setting, or in ` + "`branch.<name>.ggPushMirrors`" + `
+	for one branch. Mirrors are pushed after the primary destination
+	succeeds, in the order given, and the result for each mirror is
+	printed. A failed mirror does not stop the others, but makes the
+	command fail. ` + "`--skip-mirrors`" + ` only pushes to the primary
+	destination.`
+
+// readPushMirrors returns the mirror remotes for a push of refs to
+// dstRepo, in the order they should be pushed to. Branches use
+// branch.<name>.ggPushMirrors if it is set and gg.push.mirrors
+// otherwise.
+func readPushMirrors(cfg *git.Config, dstRepo string, refs []git.Ref) []string {
+	var mirrors []string
+	seen := map[string]bool{dstRepo: true}
+	for _, ref := range refs {
+		for _, m := range refPushMirrors(cfg, ref) {
+			if !seen[m] {
+				seen[m] = true
+				mirrors = append(mirrors, m)
+			}
+		}
+	}
+	return mirrors
+}
+
+// refPushMirrors returns the mirror remotes that ref is pushed to.
+func refPushMirrors(cfg *git.Config, ref git.Ref) []string {
+	if branch := ref.Branch(); branch != "" {
+		if v := cfg.Value("branch." + branch + ".ggPushMirrors"); v != "" {
+			return strings.Fields(v)
+		}
+	}
+	return strings.Fields(cfg.Value("gg.push.mirrors"))
+}
+
+// pushMirrors pushes each of refs to the mirror remotes that it is
+// configured for, after a successful push to dstRepo. pushFlags are the
+// arguments to git push that come before the destination and refspecs
+// gives the rest of the arguments for each ref.
+func pushMirrors(ctx context.Context, cc *cmdContext, cfg *git.Config, dstRepo string, refs []git.Ref, pushFlags []string, refspecs map[git.Ref][]string, localRefs map[git.Ref]git.Hash) error {
+	var failed []string
+	for _, m := range readPushMirrors(cfg, dstRepo, refs) {
+		args := append(append([]string(nil), pushFlags...), "--", m)
+		for _, ref := range refs {
+			if slices.Contains(refPushMirrors(cfg, ref), m) {
+				args = append(args, refspecs[ref]...)
+			}
+		}
+		porcelain := new(bytes.Buffer)
+		err := runGitWithHooks(ctx, cc, &git.Invocation{
+			Dir:    cc.dir,
+			Args:   args,
+			Stdin:  cc.stdin,
+			Stdout: porcelain,
+		})
+		if err != nil {
+			fmt.Fprintf(cc.stderr, "gg: push: mirror %s: failed: %v\n", m, err)
+			failed = append(failed, m)
+			continue
+		}
+		changes := parsePushPorcelain(porcelain.String(), localRefs)
+		if len(changes) == 0 {
+			fmt.Fprintf(cc.stderr, "gg: push: mirror %s: up to date\n", m)
+		} else {
+			fmt.Fprintf(cc.stderr, "gg: push: mirror %s: %s changed\n", m, countRefs(len(changes)))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("push to %s succeeded, but push to mirror(s) %s failed", dstRepo, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func countRefs(n int) string {
+	if n == 1 {
+		return "1 ref"
+	}
+	return fmt.Sprintf("%d refs", n)
+}
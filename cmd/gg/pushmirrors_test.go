@@ -0,0 +1,118 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestPush_Mirrors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	repoAPath := env.root.FromSlash("repoA")
+	gitA := env.git.WithDir(repoAPath)
+	for _, name := range []string{"primary", "mirror1", "mirror2"} {
+		if err := env.git.InitBare(ctx, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gitA.Run(ctx, "remote", "add", "origin", env.root.FromSlash("primary")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "remote", "add", "mirror1", env.root.FromSlash("mirror1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "remote", "add", "mirror2", env.root.FromSlash("mirror2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "config", "gg.push.mirrors", "mirror1 mirror2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	commit, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMain := func(repo string, want git.Hash) {
+		t.Helper()
+		r, err := env.git.WithDir(env.root.FromSlash(repo)).ParseRev(ctx, "refs/heads/main")
+		if err != nil {
+			t.Errorf("%s: %v", repo, err)
+			return
+		}
+		if r.Commit != want {
+			t.Errorf("%s main = %v; want %v", repo, r.Commit, want)
+		}
+	}
+
+	env.stderr.Reset()
+	if _, err := env.gg(ctx, repoAPath, "push", "-r", "main", "--new-branch"); err != nil {
+		t.Fatal(err)
+	}
+	checkMain("primary", commit)
+	checkMain("mirror1", commit)
+	checkMain("mirror2", commit)
+	for _, want := range []string{"mirror mirror1: 1 ref changed", "mirror mirror2: 1 ref changed"} {
+		if got := env.stderr.String(); !strings.Contains(got, want) {
+			t.Errorf("stderr = %q; want to contain %q", got, want)
+		}
+	}
+
+	// --skip-mirrors only pushes to the primary destination.
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", "second\n")); err != nil {
+		t.Fatal(err)
+	}
+	commit2, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, repoAPath, "push", "--skip-mirrors"); err != nil {
+		t.Fatal(err)
+	}
+	checkMain("primary", commit2)
+	checkMain("mirror1", commit)
+
+	// A failing mirror does not stop the others.
+	if err := gitA.Run(ctx, "remote", "set-url", "mirror1", env.root.FromSlash("nonexistent")); err != nil {
+		t.Fatal(err)
+	}
+	env.stderr.Reset()
+	if _, err := env.gg(ctx, repoAPath, "push"); err == nil {
+		t.Error("gg push with a broken mirror did not return an error")
+	}
+	checkMain("mirror2", commit2)
+	if got, want := env.stderr.String(), "mirror mirror1: failed"; !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+}
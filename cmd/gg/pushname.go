@@ -0,0 +1,70 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+
+	"gg-scm.io/pkg/git"
+)
+
+const pushNameHelp = `A local branch can be pushed under a different name. The
+	` + "`branch.<name>.ggPushName`" + ` setting gives the remote name for one
+	branch. Otherwise, the ` + "`gg.push.branchTemplate`" + ` setting is used
+	if it is set: ` + "`${NAME}`" + ` is replaced with the local branch name
+	and other ` + "`${VAR}`" + `s with environment variables, like
+	` + "`users/${USER}/${NAME}`" + `. The template is not used for a branch
+	that tracks a branch with a different name, so branches like main
+	keep pushing to themselves. ` + "`gg push`" + `, ` + "`gg mail`" + `,
+	` + "`gg requestpull`" + `, and ` + "`gg outgoing`" + ` all use the mapped name.`
+
+// remoteBranchName returns the name that the local branch is pushed as,
+// following the branch.<name>.ggPushName and gg.push.branchTemplate
+// settings. If neither applies, it returns branch.
+func remoteBranchName(cfg *git.Config, environ []string, branch string) (string, error) {
+	if name := cfg.Value("branch." + branch + ".ggPushName"); name != "" {
+		return name, nil
+	}
+	tmpl := cfg.Value("gg.push.branchTemplate")
+	if tmpl == "" {
+		return branch, nil
+	}
+	policy := &branchNamePolicy{environ: environ}
+	name, err := policy.expand(tmpl, branch, func(v string) string { return v })
+	if err != nil {
+		return "", fmt.Errorf("gg.push.branchTemplate: %w", err)
+	}
+	if merge := git.Ref(cfg.Value("branch." + branch + ".merge")); merge != "" && merge.Branch() != name {
+		// The branch already tracks a branch with a different name.
+		return branch, nil
+	}
+	return name, nil
+}
+
+// remotePushRef returns the ref that ref is pushed to. Only branches are
+// mapped.
+func remotePushRef(cfg *git.Config, environ []string, ref git.Ref) (git.Ref, error) {
+	branch := ref.Branch()
+	if branch == "" {
+		return ref, nil
+	}
+	name, err := remoteBranchName(cfg, environ, branch)
+	if err != nil {
+		return "", err
+	}
+	return git.BranchRef(name), nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestPush_BranchTemplate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	repoAPath := env.root.FromSlash("repoA")
+	gitA := env.git.WithDir(repoAPath)
+	if err := env.git.InitBare(ctx, "repoB"); err != nil {
+		t.Fatal(err)
+	}
+	repoBPath := env.root.FromSlash("repoB")
+	gitB := env.git.WithDir(repoBPath)
+	if err := gitA.Run(ctx, "remote", "add", "origin", repoBPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "push", "--set-upstream", "origin", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "config", "gg.push.branchTemplate", "users/alice/${NAME}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.NewBranch(ctx, "fix-login", git.BranchOptions{Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	commit2, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env.stderr.Reset()
+	if _, err := env.gg(ctx, repoAPath, "push", "--set-upstream"); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := gitB.ParseRev(ctx, "refs/heads/users/alice/fix-login"); err != nil {
+		t.Error(err)
+	} else if r.Commit != commit2 {
+		t.Errorf("refs/heads/users/alice/fix-login = %v; want %v", r.Commit, commit2)
+	}
+	if _, err := gitB.ParseRev(ctx, "refs/heads/fix-login"); err == nil {
+		t.Error("refs/heads/fix-login was pushed")
+	}
+	if got, want := env.stderr.String(), "fix-login → users/alice/fix-login"; !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+	cfg, err := gitA.ReadConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.Value("branch.fix-login.merge"), "refs/heads/users/alice/fix-login"; got != want {
+		t.Errorf("branch.fix-login.merge = %q; want %q", got, want)
+	}
+
+	// Now that the branch tracks its mapped name, a plain push updates it.
+	// main tracks a branch with its own name, so it is not mapped.
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", "second\n")); err != nil {
+		t.Fatal(err)
+	}
+	commit3, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, repoAPath, "push"); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := gitB.ParseRev(ctx, "refs/heads/users/alice/fix-login"); err != nil {
+		t.Error(err)
+	} else if r.Commit != commit3 {
+		t.Errorf("refs/heads/users/alice/fix-login = %v; want %v", r.Commit, commit3)
+	}
+	if _, err := gitB.ParseRev(ctx, "refs/heads/users/alice/main"); err == nil {
+		t.Error("refs/heads/users/alice/main was pushed")
+	}
+}
+
+func TestPush_PushName(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	repoAPath := env.root.FromSlash("repoA")
+	gitA := env.git.WithDir(repoAPath)
+	if err := env.git.InitBare(ctx, "repoB"); err != nil {
+		t.Fatal(err)
+	}
+	repoBPath := env.root.FromSlash("repoB")
+	if err := gitA.Run(ctx, "remote", "add", "origin", repoBPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "push", "origin", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.NewBranch(ctx, "foo", git.BranchOptions{Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "config", "branch.foo.ggPushName", "review/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	commit2, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, repoAPath, "push", "-r", "foo", "--new-branch"); err != nil {
+		t.Fatal(err)
+	}
+	gitB := env.git.WithDir(repoBPath)
+	if r, err := gitB.ParseRev(ctx, "refs/heads/review/foo"); err != nil {
+		t.Error(err)
+	} else if r.Commit != commit2 {
+		t.Errorf("refs/heads/review/foo = %v; want %v", r.Commit, commit2)
+	}
+
+	// Without an upstream, outgoing compares with where the branch was pushed.
+	if err := gitA.Run(ctx, "fetch", "--quiet", "origin"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.gg(ctx, repoAPath, "outgoing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "no outgoing commits\n"; got != want {
+		t.Errorf("gg outgoing = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+const pushSignedHelp = "`--signed`" + ` sends a push certificate signed with your GPG or SSH
+	key, for servers that require signed pushes. The push fails if the
+	server does not support them. ` + "`--signed=if-asked`" + ` only signs if
+	the server supports it. Without the flag, Git's ` + "`push.gpgSign`" + `
+	setting is used. The key is ` + "`--signing-key`" + `, or else
+	` + "`gg.push.signingKey`" + `, or else Git's ` + "`user.signingKey`" + `. Keys
+	that look like SSH public keys or ` + "`.pub`" + ` files are used with
+	` + "`gpg.format=ssh`" + `.`
+
+// pushSignedValue is the value of gg push --signed: "true", "false",
+// "if-asked", or empty if the flag was not given.
+type pushSignedValue string
+
+func (v *pushSignedValue) Set(s string) error {
+	mode, err := parsePushSigned(s)
+	if err != nil {
+		return err
+	}
+	*v = pushSignedValue(mode)
+	return nil
+}
+
+func (v pushSignedValue) Get() interface{} {
+	return string(v)
+}
+
+func (v pushSignedValue) String() string {
+	return string(v)
+}
+
+func (v pushSignedValue) IsBoolFlag() bool { return true }
+
+// parsePushSigned parses a boolean or "if-asked", as accepted by
+// --signed and the push.gpgSign setting.
+func parsePushSigned(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on", "1":
+		return "true", nil
+	case "false", "no", "off", "0", "":
+		return "false", nil
+	case "if-asked":
+		return "if-asked", nil
+	default:
+		return "", fmt.Errorf("%q is not a boolean or \"if-asked\"", s)
+	}
+}
+
+// pushSignedMode returns whether a push should be signed: "true",
+// "false", or "if-asked". flagValue is the value of --signed, if given.
+func pushSignedMode(cfg *git.Config, flagValue pushSignedValue) (string, error) {
+	if flagValue != "" {
+		return string(flagValue), nil
+	}
+	mode, err := parsePushSigned(cfg.Value("push.gpgSign"))
+	if err != nil {
+		return "", fmt.Errorf("push.gpgSign: %w", err)
+	}
+	return mode, nil
+}
+
+// pushSigningConfig returns the Git configuration assignments that make
+// git push sign with key, which may be empty to use gg.push.signingKey
+// or Git's own settings.
+func pushSigningConfig(cfg *git.Config, key string) []string {
+	if key == "" {
+		key = cfg.Value("gg.push.signingKey")
+	}
+	if key == "" {
+		return nil
+	}
+	params := []string{"user.signingKey=" + key}
+	if isSSHSigningKey(key) {
+		params = append(params, "gpg.format=ssh")
+	}
+	return params
+}
+
+// isSSHSigningKey reports whether key looks like an SSH public key or
+// the path to one rather than a GPG key ID.
+func isSSHSigningKey(key string) bool {
+	return strings.HasPrefix(key, "ssh-") ||
+		strings.HasPrefix(key, "ecdsa-sha2-") ||
+		strings.HasPrefix(key, "sk-") ||
+		strings.HasPrefix(key, "key::") ||
+		strings.HasSuffix(key, ".pub")
+}
+
+// explainSignedPushError returns a hint for a failed signed push, based
+// on Git's error output, or the empty string if the failure does not
+// look related to signing.
+func explainSignedPushError(dstRepo, stderr string) string {
+	switch {
+	case strings.Contains(stderr, "does not support --signed push"):
+		return dstRepo + " does not accept signed pushes (use --signed=if-asked to push unsigned to such servers)"
+	case strings.Contains(stderr, "failed to sign the push certificate"),
+		strings.Contains(stderr, "gpg failed to sign"),
+		strings.Contains(stderr, "ssh-keygen"):
+		return "could not sign the push certificate (check the key from --signing-key, gg.push.signingKey, or user.signingKey)"
+	case strings.Contains(stderr, "remote rejected"):
+		return dstRepo + " rejected the push; if it verifies push certificates, make sure it knows your signing key"
+	default:
+		return ""
+	}
+}
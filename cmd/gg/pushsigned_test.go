@@ -0,0 +1,98 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestIsSSHSigningKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"ABCDEF0123456789", false},
+		{"octocat@example.com", false},
+		{"ssh-ed25519 AAAAC3Nza octocat@example.com", true},
+		{"key::ssh-ed25519 AAAAC3Nza", true},
+		{"~/.ssh/id_ed25519.pub", true},
+	}
+	for _, test := range tests {
+		if got := isSSHSigningKey(test.key); got != test.want {
+			t.Errorf("isSSHSigningKey(%q) = %t; want %t", test.key, got, test.want)
+		}
+	}
+}
+
+func TestPush_Signed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	repoAPath := env.root.FromSlash("repoA")
+	gitA := env.git.WithDir(repoAPath)
+	// Without receive.certNonceSeed, the remote does not accept signed pushes.
+	if err := env.git.InitBare(ctx, "repoB"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitA.Run(ctx, "remote", "add", "origin", env.root.FromSlash("repoB")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, repoAPath, "push", "-r", "main", "--new-branch"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoA/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	commit, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env.stderr.Reset()
+	if _, err := env.gg(ctx, repoAPath, "push", "--signed"); err == nil {
+		t.Error("gg push --signed to a server without signed push support did not return an error")
+	}
+	if got, want := env.stderr.String(), "does not accept signed pushes"; !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+
+	if _, err := env.gg(ctx, repoAPath, "push", "--signed=if-asked"); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := env.git.WithDir(env.root.FromSlash("repoB")).ParseRev(ctx, "refs/heads/main"); err != nil {
+		t.Fatal(err)
+	} else if r.Commit != commit {
+		t.Errorf("repoB main = %v; want %v", r.Commit, commit)
+	}
+
+	if _, err := env.gg(ctx, repoAPath, "push", "--signed=maybe"); err == nil {
+		t.Error("gg push --signed=maybe did not return an error")
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// readOnlyHelp is the paragraph of the top-level help that describes
+// read-only mode.
+const readOnlyHelp = `
+
+read-only mode:
+	With ` + "`--read-only`" + `, or if the ` + "`gg.readOnly`" + ` setting or the
+	GG_READ_ONLY environment variable is true, gg refuses to run commands
+	that could change the repository, its settings, or gg's own state,
+	and never fetches revisions automatically. Commands that only look,
+	like ` + "`status`" + `, ` + "`log`" + `, ` + "`diff`" + `, and listing branches, still work.`
+
+// A readOnlyRule reports whether running a command with the given
+// arguments leaves the repository and settings unchanged, so that it is
+// allowed in read-only mode. A command with a nil rule is never allowed.
+type readOnlyRule func(args []string) bool
+
+// alwaysReadOnly is the rule for commands that never change anything.
+func alwaysReadOnly([]string) bool { return true }
+
+// readOnlySubcommands returns a rule for a command whose first argument
+// selects a subcommand. The empty string stands for running the command
+// without a subcommand.
+func readOnlySubcommands(names ...string) readOnlyRule {
+	return func(args []string) bool {
+		sub := ""
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			sub = args[0]
+		}
+		for _, name := range names {
+			if sub == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// readOnlyWithout returns a rule for a command that only changes
+// something when given more than maxArgs positional arguments or one of
+// the given flags. valueFlags are the command's flags that take a value,
+// so that their values aren't counted as positional arguments.
+func readOnlyWithout(maxArgs int, flags, valueFlags []string) readOnlyRule {
+	return func(args []string) bool {
+		n := 0
+		for i := 0; i < len(args); i++ {
+			arg := args[i]
+			if arg == "--" {
+				n += len(args) - i - 1
+				break
+			}
+			name, ok := flagName(arg)
+			if !ok {
+				n++
+				continue
+			}
+			name, _, hasValue := strings.Cut(name, "=")
+			for _, f := range flags {
+				if name == f {
+					return false
+				}
+			}
+			for _, f := range valueFlags {
+				if name == f && !hasValue {
+					i++
+					break
+				}
+			}
+		}
+		return n <= maxArgs
+	}
+}
+
+// readOnlyWith returns a rule for a command that only leaves everything
+// unchanged when given one of the given boolean flags, like a listing
+// flag. valueFlags are the command's flags that take a value, so that
+// their values aren't mistaken for flags.
+func readOnlyWith(flags, valueFlags []string) readOnlyRule {
+	return func(args []string) bool {
+		for i := 0; i < len(args); i++ {
+			if args[i] == "--" {
+				break
+			}
+			name, ok := flagName(args[i])
+			if !ok {
+				continue
+			}
+			name, value, hasValue := strings.Cut(name, "=")
+			for _, f := range flags {
+				if name != f {
+					continue
+				}
+				if hasValue {
+					if b, err := strconv.ParseBool(value); err != nil || !b {
+						return false
+					}
+				}
+				return true
+			}
+			for _, f := range valueFlags {
+				if name == f && !hasValue {
+					i++
+					break
+				}
+			}
+		}
+		return false
+	}
+}
+
+// flagName returns the name of the flag in arg, without leading dashes.
+func flagName(arg string) (string, bool) {
+	if len(arg) < 2 || arg[0] != '-' {
+		return "", false
+	}
+	return strings.TrimLeft(arg, "-"), true
+}
+
+// isHelpRequest reports whether args ask for a command's help, which is
+// allowed in read-only mode regardless of the command. Only a help flag
+// in the first position counts: anywhere else it could be the value of
+// another flag, as in "commit -m -h".
+func isHelpRequest(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	name, ok := flagName(args[0])
+	return ok && (name == "h" || name == "help")
+}
+
+// readOnlyEnabled reports whether read-only mode is turned on by the
+// gg.readOnly setting, which GG_READ_ONLY also sets.
+func readOnlyEnabled(cfg *git.Config) (bool, error) {
+	if cfg.Value("gg.readOnly") == "" {
+		return false, nil
+	}
+	readOnly, err := cfg.Bool("gg.readOnly")
+	if err != nil {
+		return false, fmt.Errorf("gg.readOnly: %w", err)
+	}
+	return readOnly, nil
+}
+
+// checkReadOnly returns an error if the named command may not run with
+// the given arguments in read-only mode.
+func checkReadOnly(name string, args []string) error {
+	c := findCommand(name)
+	if c == nil || isHelpRequest(args) {
+		// Unknown commands fail on their own.
+		return nil
+	}
+	if c.readOnly != nil && c.readOnly(args) {
+		return nil
+	}
+	return fmt.Errorf("%s can change the repository; refusing to run in read-only mode", name)
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestCheckReadOnly(t *testing.T) {
+	tests := []struct {
+		args    []string
+		allowed bool
+	}{
+		{args: []string{"status"}, allowed: true},
+		{args: []string{"st", "-b"}, allowed: true},
+		{args: []string{"log", "-r", "main"}, allowed: true},
+		{args: []string{"commit", "-m", "hi"}, allowed: false},
+		{args: []string{"commit", "--help"}, allowed: true},
+		{args: []string{"commit", "-m", "-h"}, allowed: false},
+		{args: []string{"commit", "-m", "--help"}, allowed: false},
+		{args: []string{"branch"}, allowed: true},
+		{args: []string{"branch", "-p", "feature/.*"}, allowed: true},
+		{args: []string{"branch", "--sort=name"}, allowed: true},
+		{args: []string{"branch", "foo"}, allowed: false},
+		{args: []string{"branch", "-d", "foo"}, allowed: false},
+		{args: []string{"config"}, allowed: true},
+		{args: []string{"config", "user.name"}, allowed: true},
+		{args: []string{"config", "user.name", "Octocat"}, allowed: false},
+		{args: []string{"config", "--unset", "user.name"}, allowed: false},
+		{args: []string{"note"}, allowed: true},
+		{args: []string{"note", "-r", "HEAD~"}, allowed: true},
+		{args: []string{"note", "add", "-m", "hi"}, allowed: false},
+		{args: []string{"outgoing"}, allowed: true},
+		{args: []string{"outgoing", "--upstream"}, allowed: false},
+		{args: []string{"outgoing", "origin"}, allowed: false},
+		{args: []string{"snapshot"}, allowed: false},
+		{args: []string{"snapshot", "list"}, allowed: true},
+		{args: []string{"shelve", "--list"}, allowed: true},
+		{args: []string{"shelve", "-l"}, allowed: true},
+		{args: []string{"shelve"}, allowed: false},
+		{args: []string{"shelve", "-m", "-l"}, allowed: false},
+		{args: []string{"shelve", "--list=false"}, allowed: false},
+		{args: []string{"shelve", "--", "-l"}, allowed: false},
+		{args: []string{"git", "status"}, allowed: false},
+		{args: []string{"help"}, allowed: true},
+	}
+	for _, test := range tests {
+		err := checkReadOnly(test.args[0], test.args[1:])
+		if got := err == nil; got != test.allowed {
+			t.Errorf("checkReadOnly(%q, %q) = %v; want allowed = %t", test.args[0], test.args[1:], err, test.allowed)
+		}
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		flags  []string
+		config string
+		env    string
+	}{
+		{name: "Flag", flags: []string{"--read-only"}},
+		{name: "Config", config: "[gg]\n\treadOnly = true\n"},
+		{name: "Env", env: "GG_READ_ONLY=1"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			env, err := newTestEnv(ctx, t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := env.initRepoWithHistory(ctx, "."); err != nil {
+				t.Fatal(err)
+			}
+			if err := env.writeConfig([]byte(test.config)); err != nil {
+				t.Fatal(err)
+			}
+			if test.env != "" {
+				env.environ = append(env.environ, test.env)
+			}
+			if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+				t.Fatal(err)
+			}
+			if err := env.addFiles(ctx, "foo.txt"); err != nil {
+				t.Fatal(err)
+			}
+			head, err := env.git.Head(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ggArgs := func(args ...string) []string {
+				return append(append([]string(nil), test.flags...), args...)
+			}
+			if out, err := env.gg(ctx, env.root.String(), ggArgs("status")...); err != nil {
+				t.Error("gg status:", err)
+			} else if !strings.Contains(string(out), "foo.txt") {
+				t.Errorf("gg status output = %q; want to mention foo.txt", out)
+			}
+			if _, err := env.gg(ctx, env.root.String(), ggArgs("commit", "-m", "oops")...); err == nil {
+				t.Error("gg commit succeeded in read-only mode")
+			} else if !strings.Contains(err.Error(), "read-only") {
+				t.Errorf("gg commit error = %v; want to mention read-only mode", err)
+			}
+			if _, err := env.gg(ctx, env.root.String(), ggArgs("branch", "feature")...); err == nil {
+				t.Error("gg branch feature succeeded in read-only mode")
+			}
+			if after, err := env.git.Head(ctx); err != nil {
+				t.Error(err)
+			} else if after.Commit != head.Commit {
+				t.Errorf("HEAD = %v after read-only commands; want %v", after.Commit, head.Commit)
+			}
+			if _, err := env.git.ParseRev(ctx, "feature"); err == nil {
+				t.Error("branch feature was created in read-only mode")
+			}
+		})
+	}
+}
@@ -29,18 +29,52 @@ const rebaseSynopsis = "move revision (and descendants) to a different branch"
 
 func rebase(ctx context.Context, cc *cmdContext, args []string) error {
 	const upstreamRev = "@{upstream}"
-	f := flag.NewFlagSet(true, "gg rebase [--src REV | --base REV] [--dst REV] [options]", rebaseSynopsis+`
+	f := flag.NewFlagSet(true, "gg rebase [--source REV | --base REV] [--dest REV] [options]", rebaseSynopsis+`
 
 	Rebasing will replay a set of changes on top of the destination
 	revision and set the current branch to the final revision.
 
-	If neither `+"`--src`"+` or `+"`--base`"+` is specified, it acts as if
-	`+"`--base="+upstreamRev+"`"+` was specified.`)
+	`+"`--source`"+` moves the given revision and its descendants. `+"`--base`"+`
+	moves the commits on the current branch after the point where it
+	branched from the given revision. If neither is specified, the whole
+	stack of commits on the current branch that are not in the destination
+	is moved, so `+"`gg rebase -d main`"+` moves the current branch onto main.
+	The destination defaults to the current branch's upstream
+	(`+"`"+upstreamRev+"`"+`), or the default branch of origin if the current
+	branch has no upstream (see `+"`gg remote`"+`).
+
+	With `+"`--preview`"+`, gg prints a graph of the commits before and after the
+	rebase without changing anything.
+
+	With `+"`--reset-dates`"+`, the rebased commits get the current time as
+	both their author and commit date, so that the dates of the new series
+	are in order. Otherwise, the author dates are kept.
+
+	`+"`rebase`"+` refuses to move commits that are already on a remote
+	branch, since the rebased commits would have to be force-pushed. Pass
+	`+"`--allow-rewrite-published`"+` to rebase them anyway.
+
+	Commits made with `+"`gg fixup`"+` (or any commit whose summary starts
+	with "fixup!", "squash!", or "amend!") are moved after the commit they
+	fix and folded into it, as if by `+"`git rebase --autosquash`"+`. Pass
+	`+"`--autosquash=0`"+` to rebase them as ordinary commits.
+
+	If Git's rerere feature is enabled, conflicts that were resolved
+	before are resolved the same way again. See `+"`gg config rerere`"+`.`+protectedBranchHelp)
 	base := f.String("base", "", "rebase everything from branching point of specified `rev`ision")
-	dst := f.String("dst", upstreamRev, "rebase onto the specified `rev`ision")
-	src := f.String("src", "", "rebase the specified `rev`ision and descendants")
+	f.Alias("base", "b")
+	dst := f.String("dest", upstreamRev, "rebase onto the specified `rev`ision")
+	f.Alias("dest", "dst", "d")
+	src := f.String("source", "", "rebase the specified `rev`ision and descendants")
+	f.Alias("source", "src", "s")
+	preview := f.Bool("preview", false, "show the commits that would be moved without rebasing")
 	abort := f.Bool("abort", false, "abort an interrupted rebase")
 	continue_ := f.Bool("continue", false, "continue an interrupted rebase")
+	resetDates := f.Bool("reset-dates", false, "set the author date of rebased commits to the current time")
+	allowPublished := f.Bool("allow-rewrite-published", false, allowRewritePublishedUsage)
+	forceProtected := f.Bool("force-protected", false, forceProtectedUsage)
+	autosquash := f.Bool("autosquash", true, "fold fixup! and squash! commits into the commits they fix")
+	conflictStyle := addConflictStyleFlag(f)
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -48,70 +82,209 @@ func rebase(ctx context.Context, cc *cmdContext, args []string) error {
 		return usagef("%v", err)
 	}
 	if f.NArg() != 0 {
-		return usagef("no arguments expected")
+		return usagef("no arguments expected; use --source, --base, or --dest to choose revisions")
 	}
 	if *abort && *continue_ {
 		return usagef("can't specify both --abort and --continue")
 	}
-	if (*abort || *continue_) && (*base != "" || *dst != upstreamRev || *src != "") {
+	if (*abort || *continue_) && (f.IsSet("base") || f.IsSet("dest") || f.IsSet("source") || *preview || *resetDates || *allowPublished || *forceProtected || f.IsSet("autosquash")) {
 		return usagef("can't specify other options with --abort or --continue")
 	}
 	if *abort {
+		if *conflictStyle != "" {
+			return usagef("can't specify --conflict-style with --abort")
+		}
 		return cc.interactiveGit(ctx, "rebase", "--abort")
 	}
+	if f.IsSet("base") && f.IsSet("source") {
+		return usagef("can't specify both --source and --base; " +
+			"--source moves a revision and its descendants, --base moves the current branch")
+	}
+	for _, rev := range []struct{ flag, value string }{{"base", *base}, {"source", *src}, {"dest", *dst}} {
+		if f.IsSet(rev.flag) && rev.value == "" {
+			return usagef("--%s requires a revision", rev.flag)
+		}
+		if strings.HasPrefix(rev.value, "-") {
+			return usagef("--%s revision cannot start with '-'", rev.flag)
+		}
+	}
+	cc, err := withConflictStyle(cc, *conflictStyle)
+	if err != nil {
+		return err
+	}
+	if !f.IsSet("dest") && !*continue_ {
+		if *dst, err = defaultRebaseDest(ctx, cc.git); err != nil {
+			return err
+		}
+	}
+	if !*continue_ && !*preview && !*forceProtected {
+		if err := checkRewriteProtected(ctx, cc, "rebase"); err != nil {
+			return err
+		}
+	}
 	if *continue_ {
-		return continueRebase(ctx, cc)
+		err = continueRebase(ctx, cc)
+	} else if *preview {
+		err = previewRebase(ctx, cc, *base, *src, *dst)
+	} else if err = recordOperation(ctx, cc.git, "rebase", args); err == nil {
+		if err = autoSnapshot(ctx, cc, "rebase"); err == nil {
+			err = startRebase(ctx, cc, *base, *src, *dst, *resetDates, *allowPublished, *autosquash)
+		}
+	}
+	if err != nil {
+		reportReusedResolutions(ctx, cc)
+		return err
+	}
+	return nil
+}
+
+// defaultRebaseDest returns the destination of a rebase when --dest is
+// not given: the current branch's upstream if it has one or the default
+// branch of its remote otherwise.
+func defaultRebaseDest(ctx context.Context, g *git.Git) (string, error) {
+	const upstreamRev = "@{upstream}"
+	if _, err := g.ParseRev(ctx, upstreamRev); err == nil {
+		return upstreamRev, nil
+	}
+	cfg, err := g.ReadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	headRef, err := g.HeadRef(ctx)
+	if err != nil {
+		return "", err
+	}
+	remote, branch, err := defaultBranchRef(ctx, g, cfg, headRef.Branch())
+	if err != nil {
+		return "", err
 	}
+	if branch == "" {
+		// Let the rebase report that there is no upstream.
+		return upstreamRev, nil
+	}
+	return remote + "/" + branch, nil
+}
+
+// A rebasePlan is the range of commits that a rebase moves:
+// the commits reachable from tip but not from upstream.
+type rebasePlan struct {
+	dst      string
+	upstream string
+	tip      string
+
+	// src is the first commit to move if the commits are on a branch
+	// other than the current one. It is empty otherwise.
+	src string
+}
+
+// planRebase validates the --base, --source, and --dest revisions and
+// returns the commits that the rebase would move.
+func planRebase(ctx context.Context, g *git.Git, base, src, dst string) (*rebasePlan, error) {
 	// Verify that -dst exists to give the user a better error message.
 	// See https://github.com/gg-scm/gg/issues/127
-	if _, err := cc.git.ParseRev(ctx, *dst); err != nil {
-		return fmt.Errorf("destination: %w", err)
+	if _, err := g.ParseRev(ctx, dst); err != nil {
+		return nil, fmt.Errorf("destination: %w", err)
 	}
+	plan := &rebasePlan{dst: dst, tip: git.Head.String()}
 	switch {
-	case *base != "" && *src != "":
-		return usagef("can't specify both -s and -b")
-	case *base != "":
-		return cc.interactiveGit(ctx, "rebase", "--onto="+*dst, "--no-fork-point", "--", *base)
-	case *src != "":
-		if strings.HasPrefix(*src, "-") {
-			return fmt.Errorf("revision cannot start with '-'")
-		}
-		ancestor, err := cc.git.IsAncestor(ctx, *src, git.Head.String())
+	case base != "":
+		if _, err := g.ParseRev(ctx, base); err != nil {
+			return nil, fmt.Errorf("base: %w", err)
+		}
+		plan.upstream = base
+	case src != "":
+		if _, err := g.ParseRev(ctx, src); err != nil {
+			return nil, fmt.Errorf("source: %w", err)
+		}
+		if onto, err := g.IsAncestor(ctx, src, dst); err != nil {
+			return nil, err
+		} else if onto {
+			return nil, fmt.Errorf("can't rebase %s onto %s: destination already contains it", src, dst)
+		}
+		plan.upstream = src + "~"
+		ancestor, err := g.IsAncestor(ctx, src, git.Head.String())
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if ancestor {
 			// Simple case: this is an ancestor revision.
-			return cc.interactiveGit(ctx, "rebase", "--onto="+*dst, "--no-fork-point", "--", *src+"~")
+			return plan, nil
 		}
-
-		// More complicated: this is on an unrelated branch.
-		//
-		// Non-interactive git rebase does not permit this, so we have to
-		// kick off an interactive rebase with the plan we want.
-		descend, err := findDescendants(ctx, cc.git, *src)
+		descend, err := findDescendants(ctx, g, src)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if len(descend) == 0 {
-			return fmt.Errorf("%s is not part of any branch", *src)
+			return nil, fmt.Errorf("%s is not part of any branch", src)
 		}
 		if len(descend) > 1 {
-			return fmt.Errorf("%s is in multiple branches", *src)
-		}
-		editorCmd := fmt.Sprintf(
-			"%s log --reverse --first-parent --pretty='tformat:pick %%H' %s~..%s >",
-			escape.Bash(cc.git.Exe()), escape.Bash(*src), escape.Bash(descend[0].String()))
-		return cc.interactiveGit(ctx,
-			"-c", "sequence.editor="+editorCmd,
-			"rebase",
-			"-i",
-			"--onto="+*dst,
-			"--no-fork-point",
-			git.Head.String())
+			return nil, fmt.Errorf("%s is in multiple branches", src)
+		}
+		plan.tip = descend[0].String()
+		plan.src = src
 	default:
-		return cc.interactiveGit(ctx, "rebase", "--onto="+*dst, "--no-fork-point")
+		// Move the whole stack: everything that isn't in the destination.
+		plan.upstream = dst
+	}
+	return plan, nil
+}
+
+// startRebase starts a rebase of either base or src onto dst.
+// If resetDates is true, then the rebased commits' author dates
+// are set to the current time. Unless allowPublished is true, it returns
+// an error if any of the commits to rebase are on a remote branch.
+// If autosquash is true, then fixup! and squash! commits are folded into
+// the commits they fix.
+func startRebase(ctx context.Context, cc *cmdContext, base, src, dst string, resetDates, allowPublished, autosquash bool) error {
+	plan, err := planRebase(ctx, cc.git, base, src, dst)
+	if err != nil {
+		return err
+	}
+	if !allowPublished {
+		if err := checkRewritePublished(ctx, cc.git, "rebase", plan.tip, "^"+plan.upstream); err != nil {
+			return err
+		}
+	}
+	rebaseArgs := []string{"rebase", "--onto=" + dst, "--no-fork-point"}
+	if resetDates {
+		rebaseArgs = append(rebaseArgs, "--reset-author-date")
 	}
+	if plan.src == "" {
+		if autosquash {
+			rebaseArgs, err = autosquashRebaseArgs(ctx, cc.git, rebaseArgs, plan.tip, plan.upstream)
+			if err != nil {
+				return err
+			}
+		}
+		return cc.interactiveGit(ctx, append(rebaseArgs, "--", plan.upstream)...)
+	}
+
+	// The commits are on an unrelated branch.
+	//
+	// Non-interactive git rebase does not permit this, so we have to
+	// kick off an interactive rebase with the plan we want.
+	editorCmd := fmt.Sprintf(
+		"%s log --reverse --first-parent --pretty='tformat:pick %%H' %s..%s >",
+		escape.Bash(cc.git.Exe()), escape.Bash(plan.upstream), escape.Bash(plan.tip))
+	args := []string{"-c", "sequence.editor=" + editorCmd}
+	args = append(args, rebaseArgs...)
+	args = append(args, "-i", git.Head.String())
+	return cc.interactiveGit(ctx, args...)
+}
+
+// autosquashRebaseArgs returns rebaseArgs changed to reorder and fold
+// any fixup! and squash! commits between upstream and tip. If there are
+// none, rebaseArgs is returned unchanged.
+func autosquashRebaseArgs(ctx context.Context, g *git.Git, rebaseArgs []string, tip, upstream string) ([]string, error) {
+	found, err := hasAutosquashCommits(ctx, g, tip, upstream)
+	if err != nil || !found {
+		return rebaseArgs, err
+	}
+	// Older versions of Git only autosquash during an interactive rebase.
+	// The plan is already in the right order, so accept it as is.
+	args := []string{"-c", "sequence.editor=true"}
+	args = append(args, rebaseArgs...)
+	return append(args, "-i", "--autosquash"), nil
 }
 
 const histeditSynopsis = "interactively edit revision history"
@@ -126,17 +299,38 @@ func histedit(ctx context.Context, cc *cmdContext, args []string) error {
 
 	Unlike `+"`git rebase -i`"+`, continuing a `+"`histedit`"+` will automatically
 	amend the current commit if any changes are made. In most cases,
-	you do not need to run `+"`commit --amend`"+` yourself.`)
+	you do not need to run `+"`commit --amend`"+` yourself.
+
+	With `+"`--edit-message-only`"+`, gg opens your editor for the message of
+	each commit given with `+"`-r`"+`, or of every commit in the series if
+	`+"`-r`"+` is not given, and changes nothing else. The commits are
+	rewritten directly instead of replayed, so this never stops for
+	conflicts or touches the working copy, and the commits keep their
+	author and commit dates.
+
+	`+"`histedit`"+` refuses to edit commits that are already on a remote
+	branch, since the edited commits would have to be force-pushed. Pass
+	`+"`--allow-rewrite-published`"+` to edit them anyway.`+protectedBranchHelp)
 	abort := f.Bool("abort", false, "abort an edit already in progress")
 	continue_ := f.Bool("continue", false, "continue an edit already in progress")
 	editPlan := f.Bool("edit-plan", false, "edit remaining actions list")
+	allowPublished := f.Bool("allow-rewrite-published", false, allowRewritePublishedUsage)
+	forceProtected := f.Bool("force-protected", false, forceProtectedUsage)
 	exec := f.MultiString("exec", "execute the shell `command` after each line creating a commit (can be specified multiple times)")
+	messageOnly := f.Bool("edit-message-only", false, "only change commit messages, without replaying the commits")
+	rewordRevs := f.MultiString("r", "with --edit-message-only, `rev`ision whose message to change (can be specified multiple times)")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
+	if len(*rewordRevs) > 0 && !*messageOnly {
+		return usagef("-r requires --edit-message-only")
+	}
+	if *messageOnly && (*abort || *continue_ || *editPlan || len(*exec) > 0) {
+		return usagef("can't pass --edit-message-only with --abort, --continue, --edit-plan, or --exec")
+	}
 	switch {
 	case !*abort && !*continue_ && !*editPlan:
 		if f.NArg() > 1 {
@@ -153,27 +347,69 @@ func histedit(ctx context.Context, cc *cmdContext, args []string) error {
 		if err != nil {
 			return err
 		}
+		if !*forceProtected {
+			if err := checkRewriteProtected(ctx, cc, "edit"); err != nil {
+				return err
+			}
+		}
+		if *messageOnly {
+			return rewordCommits(ctx, cc, mergeBase, *rewordRevs, *allowPublished)
+		}
 		rebaseArgs := []string{"rebase", "-i", "--onto=" + mergeBase.String(), "--no-fork-point", "--autosquash"}
 		for _, cmd := range *exec {
 			rebaseArgs = append(rebaseArgs, "--exec="+cmd)
 		}
+		if !*allowPublished {
+			err := checkRewritePublished(ctx, cc.git, "edit", git.Head.String(), "^"+mergeBase.String())
+			if err != nil {
+				return err
+			}
+		}
+		planPath, steps, err := editHisteditPlan(ctx, cc, mergeBase, rebaseArgs)
+		if err != nil {
+			return err
+		}
+		if err := recordOperation(ctx, cc.git, "histedit", args); err != nil {
+			return err
+		}
+		if err := autoSnapshot(ctx, cc, "histedit"); err != nil {
+			return err
+		}
+		head, err := cc.git.Head(ctx)
+		if err != nil {
+			return err
+		}
+		if err := writeHisteditRecord(ctx, cc.git, head.Commit, mergeBase, steps); err != nil {
+			return err
+		}
+		rebaseArgs = append([]string{"-c", "sequence.editor=cat " + escape.Bash(planPath) + " >"}, rebaseArgs...)
 		rebaseArgs = append(rebaseArgs, "--", mergeBase.String())
-		return cc.interactiveGit(ctx, rebaseArgs...)
+		if err := cc.passthroughGit(ctx, rebaseArgs...); err != nil {
+			return err
+		}
+		return finishHistedit(ctx, cc)
 	case *abort && !*continue_ && !*editPlan:
 		if f.NArg() != 0 {
 			return usagef("can't pass arguments with --abort")
 		}
-		return cc.interactiveGit(ctx, "rebase", "--abort")
+		if err := cc.interactiveGit(ctx, "rebase", "--abort"); err != nil {
+			return err
+		}
+		discardHisteditRecord(ctx, cc.git)
+		return nil
 	case !*abort && *continue_ && !*editPlan:
 		if f.NArg() != 0 {
 			return usagef("can't pass arguments with --continue")
 		}
-		return continueRebase(ctx, cc)
+		if err := continueRebase(ctx, cc); err != nil {
+			return err
+		}
+		return finishHistedit(ctx, cc)
 	case !*abort && !*continue_ && *editPlan:
 		if f.NArg() != 0 {
-			return usagef("can't pass arguments with --edit-todo")
+			return usagef("can't pass arguments with --edit-plan")
 		}
-		return cc.interactiveGit(ctx, "rebase", "--edit-todo")
+		return editRemainingHisteditPlan(ctx, cc)
 	default:
 		return usagef("must specify at most one of --abort, --continue, or --edit-plan")
 	}
@@ -195,7 +431,7 @@ func continueRebase(ctx context.Context, cc *cmdContext) error {
 			return err
 		}
 	}
-	return cc.interactiveGit(ctx, "rebase", "--continue")
+	return cc.passthroughGit(ctx, "rebase", "--continue")
 }
 
 // findDescendants returns the set of distinct heads under refs/heads/
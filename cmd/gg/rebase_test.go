@@ -17,7 +17,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/escape"
@@ -569,6 +571,141 @@ func TestRebase_ResetUpstream(t *testing.T) {
 	})
 }
 
+func TestRebase_ResetDates(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository with an old commit on "topic" and
+	// a diverging commit on "main".
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "topic", git.BranchOptions{Track: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("mainline.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "mainline.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.CheckoutBranch(ctx, "topic", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "commit", "--quiet", "--date=2001-02-03T04:05:06Z", "-m", "old change"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(-time.Minute)
+	if _, err := env.gg(ctx, env.root.String(), "rebase", "--reset-dates"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.AuthorTime.Before(start) {
+		t.Errorf("after rebase --reset-dates, author date = %v; want current time", info.AuthorTime)
+	}
+}
+
+func TestRebase_Published(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository A and clone it to repository B.
+	if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "repoA", "repoB"); err != nil {
+		t.Fatal(err)
+	}
+	repoBPath := env.root.FromSlash("repoB")
+	gitB := env.git.WithDir(repoBPath)
+
+	// Commit to a topic branch in repository B and push it.
+	err = gitB.NewBranch(ctx, "topic", git.BranchOptions{
+		Checkout:   true,
+		Track:      true,
+		StartPoint: "origin/main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("repoB/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoB/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	topic, err := env.newCommit(ctx, "repoB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gitB.Run(ctx, "push", "--quiet", "origin", "topic"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Move main forward in repository A and fetch it.
+	if err := env.root.Apply(filesystem.Write("repoA/bar.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "repoA/bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	upstream, err := env.newCommit(ctx, "repoA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gitB.Run(ctx, "fetch", "--quiet", "origin"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, repoBPath, "rebase")
+	if err == nil {
+		t.Fatal("gg rebase of published commit succeeded")
+	}
+	if isUsage(err) {
+		t.Errorf("gg rebase error: %v; want failure, not usage", err)
+	}
+	if got, want := err.Error(), "is already on origin/topic"; !strings.Contains(got, want) {
+		t.Errorf("gg rebase error = %q; want to contain %q", got, want)
+	}
+	if head, err := gitB.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if head.Commit != topic {
+		t.Errorf("HEAD = %v; want unchanged %v", head.Commit, topic)
+	}
+
+	if _, err := env.gg(ctx, repoBPath, "rebase", "--allow-rewrite-published"); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := gitB.ParseRev(ctx, "HEAD~")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent.Commit != upstream {
+		t.Errorf("HEAD~ = %v; want %v", parent.Commit, upstream)
+	}
+}
+
 func TestRebase_NoUpstream(t *testing.T) {
 	// Regression test for https://github.com/gg-scm/gg/issues/127
 
@@ -593,6 +730,126 @@ func TestRebase_NoUpstream(t *testing.T) {
 	}
 }
 
+func TestRebase_DestOnly(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository with a topic branch that has no upstream:
+	//
+	// *-----*  main
+	//  \
+	//   *-*  topic
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "topic", git.BranchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("mainline.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "mainline.txt"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.CheckoutBranch(ctx, "topic", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"foo.txt", "bar.txt"} {
+		if err := env.root.Apply(filesystem.Write(name, dummyContent)); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, name); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := env.newCommit(ctx, "."); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.gg(ctx, env.root.String(), "rebase", "--preview", "-d", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"before:\n", "  | o  " + head.Short(), "after:\n", "  o  did stuff\n"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("gg rebase --preview output = %q; want to contain %q", out, want)
+		}
+	}
+	if curr, err := env.git.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if curr.Commit != before.Commit {
+		t.Errorf("after gg rebase --preview, HEAD = %v; want %v", curr.Commit, before.Commit)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "rebase", "-d", "main"); err != nil {
+		t.Fatal(err)
+	}
+	curr, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := git.Ref("refs/heads/topic"); curr.Ref != want {
+		t.Errorf("rebase changed ref to %s; want %s", curr.Ref, want)
+	}
+	grandparent, err := env.git.ParseRev(ctx, "HEAD~2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grandparent.Commit != head {
+		t.Errorf("HEAD~2 = %v; want %v", grandparent.Commit, head)
+	}
+	for _, name := range []git.TopPath{"foo.txt", "bar.txt", "mainline.txt"} {
+		if err := objectExists(ctx, env.git, curr.Commit.String(), name); err != nil {
+			t.Errorf("%s not in rebased change: %v", name, err)
+		}
+	}
+}
+
+func TestRebase_InvalidRevisions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args  []string
+		usage bool
+	}{
+		{args: []string{"rebase", "-s", "HEAD", "-b", "HEAD", "-d", "HEAD~"}, usage: true},
+		{args: []string{"rebase", "main"}, usage: true},
+		{args: []string{"rebase", "--preview", "--continue"}, usage: true},
+		{args: []string{"rebase", "-s", "bogus", "-d", "HEAD~"}},
+		{args: []string{"rebase", "-s", "HEAD~", "-d", "HEAD"}},
+	}
+	for _, test := range tests {
+		_, err := env.gg(ctx, env.root.String(), test.args...)
+		if err == nil {
+			t.Errorf("gg %s succeeded; want error", strings.Join(test.args, " "))
+			continue
+		}
+		if got := isUsage(err); got != test.usage {
+			t.Errorf("gg %s = %v; usage error = %t, want %t", strings.Join(test.args, " "), err, got, test.usage)
+		}
+	}
+}
+
 func TestHistedit(t *testing.T) {
 	t.Parallel()
 	runRebaseArgVariants(t, func(t *testing.T, argFunc rebaseArgFunc) {
@@ -701,6 +958,231 @@ func TestHistedit(t *testing.T) {
 	})
 }
 
+func TestHistedit_PlanOutsideRange(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "foo", git.BranchOptions{Track: true}); err != nil {
+		t.Fatal(err)
+	}
+	// Create a commit on main.
+	if err := env.root.Apply(filesystem.Write("upstream.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "upstream.txt"); err != nil {
+		t.Fatal(err)
+	}
+	upstream, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Check out foo and create a commit.
+	if err := env.git.CheckoutBranch(ctx, "foo", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	c, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Call gg histedit with a plan that picks the main commit.
+	rebaseEditor, err := env.editorCmd([]byte("pick " + c.String() + "\npick " + upstream.String() + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf("[sequence]\neditor = %s\n", escape.GitConfig(rebaseEditor))
+	if err := env.writeConfig([]byte(config)); err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.gg(ctx, env.root.String(), "histedit")
+	if err == nil {
+		t.Fatal("histedit succeeded with a plan that references a commit outside the range")
+	}
+	if want := "line 2: pick " + upstream.String() + ": commit is not in"; !strings.Contains(err.Error(), want) {
+		t.Errorf("histedit error = %q; want to contain %q", err, want)
+	}
+	if curr, err := env.git.Head(ctx); err != nil {
+		t.Fatal(err)
+	} else if curr.Commit != c {
+		t.Errorf("HEAD = %v; want unchanged %v", curr.Commit, c)
+	}
+	if state, err := env.gg(ctx, env.root.String(), "state"); err != nil {
+		t.Error(err)
+	} else if got, want := string(state), "no operation in progress\n"; got != want {
+		t.Errorf("gg state = %q; want %q", got, want)
+	}
+}
+
+func TestHistedit_EditPlanOutsideRange(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "foo", git.BranchOptions{Track: true}); err != nil {
+		t.Fatal(err)
+	}
+	// Create a commit on main.
+	if err := env.root.Apply(filesystem.Write("upstream.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "upstream.txt"); err != nil {
+		t.Fatal(err)
+	}
+	upstream, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Check out foo and create two commits.
+	if err := env.git.CheckoutBranch(ctx, "foo", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	c1, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("bar.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	c2, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Start a histedit that stops at the first commit.
+	rebaseEditor, err := env.editorCmd([]byte("edit " + c1.String() + "\npick " + c2.String() + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf("[sequence]\neditor = %s\n", escape.GitConfig(rebaseEditor))
+	if err := env.writeConfig([]byte(config)); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := env.gg(ctx, env.root.String(), "histedit"); err != nil {
+		t.Fatalf("failed: %v; output:\n%s", err, out)
+	}
+
+	// Edit the rest of the plan to pick the main commit.
+	rebaseEditor, err = env.editorCmd([]byte("pick " + upstream.String() + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config = fmt.Sprintf("[sequence]\neditor = %s\n", escape.GitConfig(rebaseEditor))
+	if err := env.writeConfig([]byte(config)); err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.gg(ctx, env.root.String(), "histedit", "--edit-plan")
+	if err == nil {
+		t.Fatal("histedit --edit-plan succeeded with a plan that references a commit outside the range")
+	}
+	if want := "line 1: pick " + upstream.String() + ": commit is not in"; !strings.Contains(err.Error(), want) {
+		t.Errorf("histedit --edit-plan error = %q; want to contain %q", err, want)
+	}
+
+	// Continuing should still pick the second commit.
+	if out, err := env.gg(ctx, env.root.String(), "histedit", "--continue"); err != nil {
+		t.Fatalf("failed: %v; output:\n%s", err, out)
+	}
+	if exists, err := env.root.Exists("upstream.txt"); err != nil {
+		t.Error(err)
+	} else if exists {
+		t.Error("upstream.txt exists after histedit; want the main commit left out")
+	}
+	if exists, err := env.root.Exists("bar.txt"); err != nil {
+		t.Error(err)
+	} else if !exists {
+		t.Error("bar.txt does not exist after histedit")
+	}
+}
+
+func TestHistedit_RestoresChangeID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "foo", git.BranchOptions{Track: true, Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	// Create a commit with a Change-Id.
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	const changeID = "I0123456789abcdef0123456789abcdef01234567"
+	if err := env.git.Commit(ctx, "Add foo\n\nChange-Id: "+changeID+"\n", git.CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reword the commit with a message that drops the Change-Id.
+	rebaseEditor, err := env.editorCmd([]byte("reword " + c.Commit.String() + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgEditor, err := env.editorCmd([]byte("Add foo.txt\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf("[sequence]\neditor = %s\n[core]\neditor = %s\n",
+		escape.GitConfig(rebaseEditor), escape.GitConfig(msgEditor))
+	if err := env.writeConfig([]byte(config)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "histedit"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "Add foo.txt\n\nChange-Id: " + changeID + "\n"
+	if info.Message != want {
+		t.Errorf("commit message = %q; want %q", info.Message, want)
+	}
+	if got, want := env.stderr.String(), "restored Change-Id on 1 commit"; !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+}
+
 func TestHistedit_ContinueWithModifications(t *testing.T) {
 	t.Parallel()
 	runRebaseArgVariants(t, func(t *testing.T, argFunc rebaseArgFunc) {
@@ -1075,3 +1557,103 @@ func appendNonEmpty(args []string, s string) []string {
 	}
 	return append(args, s)
 }
+
+func TestHistedit_EditMessageOnly(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	baseRev, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "foo", git.BranchOptions{Track: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.CheckoutBranch(ctx, "foo", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "commit", "--quiet", "--date=2001-02-03T04:05:06Z", "-m", "Add fo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("bar.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "commit", "--quiet", "-m", "Add bar"); err != nil {
+		t.Fatal(err)
+	}
+	oldFirst, err := env.git.CommitInfo(ctx, "HEAD~1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldSecond, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const wantMessage = "Add foo\n"
+	msgEditor, err := env.editorCmd([]byte(wantMessage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf("[core]\neditor = %s\n", escape.GitConfig(msgEditor))
+	if err := env.writeConfig([]byte(config)); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.gg(ctx, env.root.String(), "histedit", "--edit-message-only", "-r", "HEAD~1")
+	if err != nil {
+		t.Fatalf("failed: %v; output:\n%s", err, out)
+	}
+
+	curr, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := git.Ref("refs/heads/foo"); curr.Ref != want {
+		t.Errorf("histedit changed ref to %s; want %s", curr.Ref, want)
+	}
+	first, err := env.git.CommitInfo(ctx, "HEAD~1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Message != wantMessage {
+		t.Errorf("HEAD~1 message = %q; want %q", first.Message, wantMessage)
+	}
+	if first.Tree != oldFirst.Tree {
+		t.Errorf("HEAD~1 tree = %v; want %v", first.Tree, oldFirst.Tree)
+	}
+	if !first.AuthorTime.Equal(oldFirst.AuthorTime) {
+		t.Errorf("HEAD~1 author date = %v; want %v", first.AuthorTime, oldFirst.AuthorTime)
+	}
+	if len(first.Parents) != 1 || first.Parents[0] != baseRev.Commit {
+		t.Errorf("HEAD~1 parents = %v; want [%v]", first.Parents, baseRev.Commit)
+	}
+	second, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Message != oldSecond.Message {
+		t.Errorf("HEAD message = %q; want %q", second.Message, oldSecond.Message)
+	}
+	if second.Tree != oldSecond.Tree {
+		t.Errorf("HEAD tree = %v; want %v", second.Tree, oldSecond.Tree)
+	}
+	if !second.CommitTime.Equal(oldSecond.CommitTime) {
+		t.Errorf("HEAD commit date = %v; want %v", second.CommitTime, oldSecond.CommitTime)
+	}
+}
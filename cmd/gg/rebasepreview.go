@@ -0,0 +1,143 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// rebasePreviewMax is the number of moved commits that rebase --preview
+// lists before summarizing the rest.
+const rebasePreviewMax = 10
+
+// A rebasePreview is the information needed to draw the commit graph
+// before and after a rebase. Commits are described by their short hash
+// and summary.
+type rebasePreview struct {
+	moved []string // commits to move, newest first
+
+	from    string // commit that the moved commits are based on
+	fromGap int    // number of commits after fork up to and including from
+	dst     string
+	dstGap  int    // number of commits after fork up to and including dst
+	fork    string // newest common ancestor of from and dst
+}
+
+// previewRebase prints the commit graph before and after the rebase that
+// startRebase would perform.
+func previewRebase(ctx context.Context, cc *cmdContext, base, src, dst string) error {
+	plan, err := planRebase(ctx, cc.git, base, src, dst)
+	if err != nil {
+		return err
+	}
+	out, err := cc.git.Output(ctx, "log", "--no-merges", "--format=%h %s", plan.upstream+".."+plan.tip, "--")
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err := fmt.Fprintf(cc.stdout, "nothing to rebase: %s is already in %s\n", plan.tip, plan.upstream)
+		return err
+	}
+	p := &rebasePreview{moved: strings.Split(strings.TrimSuffix(out, "\n"), "\n")}
+	fromHash, err := cc.git.MergeBase(ctx, plan.upstream, plan.tip)
+	if err != nil {
+		return err
+	}
+	forkHash, err := cc.git.MergeBase(ctx, fromHash.String(), dst)
+	if err != nil {
+		return err
+	}
+	if p.fromGap, err = countRevs(ctx, cc.git, forkHash.String()+".."+fromHash.String()); err != nil {
+		return err
+	}
+	if p.dstGap, err = countRevs(ctx, cc.git, forkHash.String()+".."+dst); err != nil {
+		return err
+	}
+	if p.fromGap == 0 && p.dstGap == 0 {
+		_, err := fmt.Fprintf(cc.stdout, "nothing to rebase: commits are already based on %s\n", dst)
+		return err
+	}
+	for _, c := range []struct {
+		rev string
+		s   *string
+	}{{fromHash.String(), &p.from}, {dst, &p.dst}, {forkHash.String(), &p.fork}} {
+		line, err := cc.git.Output(ctx, "log", "-1", "--format=%h %s", c.rev, "--")
+		if err != nil {
+			return err
+		}
+		*c.s = strings.TrimSuffix(line, "\n")
+	}
+	_, err = io.WriteString(cc.stdout, p.String())
+	return err
+}
+
+// countRevs returns the number of commits in a git rev-list range.
+func countRevs(ctx context.Context, g *git.Git, revRange string) (int, error) {
+	out, err := g.Output(ctx, "rev-list", "--count", revRange, "--")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
+
+// String draws the commit graph before and after the rebase.
+func (p *rebasePreview) String() string {
+	sb := new(strings.Builder)
+	sb.WriteString("before:\n")
+	p.writeMoved(sb, true)
+	if p.fromGap > 0 {
+		fmt.Fprintf(sb, "  o  %s\n", p.from)
+		if p.fromGap > 1 {
+			sb.WriteString("  :\n")
+		}
+	}
+	if p.dstGap > 0 {
+		fmt.Fprintf(sb, "  | o  %s\n", p.dst)
+		if p.dstGap > 1 {
+			sb.WriteString("  | :\n")
+		}
+		sb.WriteString("  |/\n")
+	}
+	fmt.Fprintf(sb, "  o  %s\n", p.fork)
+
+	sb.WriteString("after:\n")
+	p.writeMoved(sb, false)
+	fmt.Fprintf(sb, "  o  %s\n", p.dst)
+	return sb.String()
+}
+
+// writeMoved writes a line for each of the moved commits. The rebased
+// commits get new hashes, so the hashes are omitted unless before is
+// true.
+func (p *rebasePreview) writeMoved(sb *strings.Builder, before bool) {
+	for i, c := range p.moved {
+		if i == rebasePreviewMax-1 && len(p.moved) > rebasePreviewMax {
+			fmt.Fprintf(sb, "  :  (%d more)\n", len(p.moved)-i)
+			break
+		}
+		if !before {
+			_, c, _ = strings.Cut(c, " ")
+		}
+		fmt.Fprintf(sb, "  o  %s\n", c)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRebasePreview(t *testing.T) {
+	tests := []struct {
+		name    string
+		preview *rebasePreview
+		want    string
+	}{
+		{
+			name: "Diverged",
+			preview: &rebasePreview{
+				moved: []string{"ccc3333 change 2", "bbb2222 change 1"},
+				from:  "aaa1111 initial import",
+				dst:   "ddd4444 mainline change",
+				fork:  "aaa1111 initial import",
+
+				dstGap: 1,
+			},
+			want: "before:\n" +
+				"  o  ccc3333 change 2\n" +
+				"  o  bbb2222 change 1\n" +
+				"  | o  ddd4444 mainline change\n" +
+				"  |/\n" +
+				"  o  aaa1111 initial import\n" +
+				"after:\n" +
+				"  o  change 2\n" +
+				"  o  change 1\n" +
+				"  o  ddd4444 mainline change\n",
+		},
+		{
+			name: "BothSidesAdvanced",
+			preview: &rebasePreview{
+				moved:   []string{"ccc3333 change"},
+				from:    "bbb2222 parent",
+				fromGap: 2,
+				dst:     "ddd4444 mainline change",
+				dstGap:  3,
+				fork:    "aaa1111 initial import",
+			},
+			want: "before:\n" +
+				"  o  ccc3333 change\n" +
+				"  o  bbb2222 parent\n" +
+				"  :\n" +
+				"  | o  ddd4444 mainline change\n" +
+				"  | :\n" +
+				"  |/\n" +
+				"  o  aaa1111 initial import\n" +
+				"after:\n" +
+				"  o  change\n" +
+				"  o  ddd4444 mainline change\n",
+		},
+		{
+			name: "OntoAncestor",
+			preview: &rebasePreview{
+				moved:   []string{"ccc3333 change"},
+				from:    "bbb2222 parent",
+				fromGap: 1,
+				dst:     "aaa1111 initial import",
+				fork:    "aaa1111 initial import",
+			},
+			want: "before:\n" +
+				"  o  ccc3333 change\n" +
+				"  o  bbb2222 parent\n" +
+				"  o  aaa1111 initial import\n" +
+				"after:\n" +
+				"  o  change\n" +
+				"  o  aaa1111 initial import\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, test.preview.String()); diff != "" {
+				t.Errorf("preview (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Long", func(t *testing.T) {
+		p := &rebasePreview{
+			from:   "aaa1111 initial import",
+			dst:    "ddd4444 mainline change",
+			dstGap: 1,
+			fork:   "aaa1111 initial import",
+		}
+		for i := 0; i < 15; i++ {
+			p.moved = append(p.moved, fmt.Sprintf("%07d change %d", i, i))
+		}
+		got := p.String()
+		want := "  o  0000008 change 8\n  :  (6 more)\n"
+		if !strings.Contains(got, want) {
+			t.Errorf("preview = %q; want to contain %q", got, want)
+		}
+	})
+}
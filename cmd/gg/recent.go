@@ -0,0 +1,124 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const recentSynopsis = "list branches by when they were last checked out"
+
+func recent(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg recent [-n N] [--json]", recentSynopsis+`
+
+	Lists the local branches, starting with the current branch and then
+	the most recently checked out, with the summary of each branch's tip
+	commit and how long ago the branch was last checked out. The times
+	come from the HEAD reflog, so branches that have not been checked out
+	since the reflog began (or were checked out only in another worktree)
+	are not listed.`)
+	n := f.Int("n", 10, "list at most `N` branches (0 for all)")
+	jsonOutput := f.Bool("json", false, "print a JSON array instead of a table")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 0 {
+		return usagef("recent takes no arguments")
+	}
+	if *n < 0 {
+		return usagef("-n must not be negative")
+	}
+	branches, err := recentBranches(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	headRef, err := cc.git.HeadRef(ctx)
+	if err != nil {
+		return err
+	}
+	var used []*recentBranch
+	for _, b := range branches {
+		if !b.LastUsed.IsZero() || b.Name == headRef.Branch() {
+			used = append(used, b)
+		}
+	}
+	if *n > 0 && len(used) > *n {
+		used = used[:*n]
+	}
+	refs := make(map[git.Ref]git.Hash, len(used))
+	for _, b := range used {
+		refs[git.BranchRef(b.Name)] = b.Tip
+	}
+	commits, err := refsCommitInfo(ctx, cc.git, refs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entries := make([]*recentEntry, 0, len(used))
+	for _, b := range used {
+		ent := &recentEntry{
+			Branch:  b.Name,
+			Commit:  b.Tip.String(),
+			Current: b.Name == headRef.Branch(),
+		}
+		if c := commits[b.Tip]; c != nil {
+			ent.Summary = c.Summary()
+		}
+		if !b.LastUsed.IsZero() {
+			ent.LastUsed = b.LastUsed.UTC().Format(time.RFC3339)
+		}
+		switch {
+		case ent.Current:
+			ent.ago = "current"
+		case ent.LastUsed != "":
+			ent.ago = relativeTime(now, b.LastUsed)
+		}
+		entries = append(entries, ent)
+	}
+	if *jsonOutput {
+		enc := json.NewEncoder(cc.stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(entries)
+	}
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, ent := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", ent.Branch, ent.ago, ent.Summary)
+	}
+	return tw.Flush()
+}
+
+// A recentEntry is a branch listed by gg recent.
+type recentEntry struct {
+	Branch   string `json:"branch"`
+	Commit   string `json:"commit"`
+	Summary  string `json:"summary"`
+	Current  bool   `json:"current,omitempty"`
+	LastUsed string `json:"lastUsed,omitempty"` // RFC 3339
+
+	ago string // relative time for the table
+}
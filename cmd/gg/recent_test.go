@@ -0,0 +1,69 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRecent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"never", "feature"} {
+		if err := env.git.Run(ctx, "branch", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"feature", "main"} {
+		if err := env.git.Run(ctx, "checkout", "--quiet", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "recent", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []*recentEntry
+	if err := json.Unmarshal(out, &entries); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	if len(entries) != 2 ||
+		entries[0].Branch != "main" || !entries[0].Current ||
+		entries[1].Branch != "feature" || entries[1].LastUsed == "" ||
+		entries[1].Summary != "removed dummy file" {
+		t.Errorf("gg recent --json = %s; want main (current) then feature", out)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "recent", "-n", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n"); len(lines) != 1 || !strings.HasPrefix(lines[0], "main") || !strings.Contains(lines[0], "current") {
+		t.Errorf("gg recent -n 1 output:\n%s\nwant only main", out)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gg-scm.io/pkg/git"
+)
+
+// Kinds of ref changes.
+const (
+	refCreated  = "created"
+	refUpdated  = "updated" // fast-forward
+	refForced   = "forced"
+	refDeleted  = "deleted"
+	refPruned   = "pruned" // remote-tracking branch deleted
+	refRejected = "rejected"
+)
+
+// A refChange describes an update to a ref made by gg pull or gg push.
+type refChange struct {
+	Ref    git.Ref `json:"ref"`
+	Kind   string  `json:"kind"`
+	Old    string  `json:"old,omitempty"`
+	New    string  `json:"new,omitempty"`
+	Reason string  `json:"reason,omitempty"` // only set for rejected refs
+}
+
+// diffRefs returns the changes between two snapshots of a repository's refs,
+// sorted by ref name. HEAD and refs under refs/gg-old/ are ignored.
+func diffRefs(ctx context.Context, g *git.Git, before, after map[git.Ref]git.Hash) ([]*refChange, error) {
+	var changes []*refChange
+	for ref, newHash := range after {
+		if ignoreRefChange(ref) {
+			continue
+		}
+		oldHash, existed := before[ref]
+		switch {
+		case !existed:
+			changes = append(changes, &refChange{Ref: ref, Kind: refCreated, New: newHash.String()})
+		case oldHash != newHash:
+			kind := refUpdated
+			isAncestor, err := g.IsAncestor(ctx, oldHash.String(), newHash.String())
+			if err != nil {
+				return nil, err
+			}
+			if !isAncestor {
+				kind = refForced
+			}
+			changes = append(changes, &refChange{Ref: ref, Kind: kind, Old: oldHash.String(), New: newHash.String()})
+		}
+	}
+	for ref, oldHash := range before {
+		if _, exists := after[ref]; exists || ignoreRefChange(ref) {
+			continue
+		}
+		kind := refDeleted
+		if strings.HasPrefix(string(ref), "refs/remotes/") {
+			kind = refPruned
+		}
+		changes = append(changes, &refChange{Ref: ref, Kind: kind, Old: oldHash.String()})
+	}
+	sortRefChanges(changes)
+	return changes, nil
+}
+
+func ignoreRefChange(ref git.Ref) bool {
+	return ref == git.Head || strings.HasPrefix(string(ref), "refs/gg-old/")
+}
+
+func sortRefChanges(changes []*refChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Ref < changes[j].Ref
+	})
+}
+
+// writeRefChanges writes a summary of ref changes to w, either as a table
+// or as a JSON array.
+func writeRefChanges(w io.Writer, changes []*refChange, asJSON bool) error {
+	if asJSON {
+		if changes == nil {
+			changes = []*refChange{}
+		}
+		out, err := json.MarshalIndent(changes, "", "\t")
+		if err != nil {
+			return err
+		}
+		out = append(out, '\n')
+		_, err = w.Write(out)
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, c := range changes {
+		var detail string
+		switch c.Kind {
+		case refCreated:
+			detail = shortHash(c.New)
+		case refUpdated:
+			detail = shortHash(c.Old) + " → " + shortHash(c.New)
+		case refForced:
+			detail = shortHash(c.Old) + " → " + shortHash(c.New) + " (forced)"
+		case refDeleted, refPruned:
+			if c.Old != "" {
+				detail = "was " + shortHash(c.Old)
+			}
+		case refRejected:
+			detail = c.Reason
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Kind, displayRef(c.Ref), detail)
+	}
+	return tw.Flush()
+}
+
+// displayRef returns a short name for a ref that is still unambiguous
+// between branches and tags.
+func displayRef(ref git.Ref) string {
+	if b := ref.Branch(); b != "" {
+		return b
+	}
+	if t := ref.Tag(); t != "" {
+		return "tag " + t
+	}
+	return strings.TrimPrefix(string(ref), "refs/remotes/")
+}
+
+func shortHash(s string) string {
+	const n = 7
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
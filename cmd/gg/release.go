@@ -0,0 +1,347 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const releaseSynopsis = "tag, push, and publish a release"
+
+func release(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg release [-n] [-s] [--notes-from-log] [--version-file FILE] [--push=0] [--github=0] [--draft] VERSION", releaseSynopsis+`
+
+	Creates an annotated tag named VERSION at the current commit, pushes
+	it, and creates a GitHub release for it. The working copy must not
+	have any uncommitted changes.
+
+	If a version file is given with `+"`--version-file`"+` or the
+	`+"`gg.release.versionFile`"+` setting (relative to the top of the
+	repository), the version in it is updated and committed before
+	tagging. A file that only holds a version is replaced with VERSION
+	(without a leading "v"). Otherwise, the first occurrence of the
+	previous tag's version in the file is replaced. The current branch is
+	pushed along with the tag so that the commit is not left behind.
+
+	With `+"`--notes-from-log`"+`, the tag message and the release notes list
+	the commits since the previous tag, grouped as by `+"`gg changelog`"+`.
+
+	The tag and branch are pushed to the remote named by
+	`+"`remote.pushDefault`"+`, the branch's remote, or `+"`origin`"+`. The
+	GitHub release is only created if that remote is on GitHub, using the
+	same authorization as `+"`gg requestpull`"+`.`)
+	dryRun := f.Bool("n", false, "print what would be done without doing it")
+	f.Alias("n", "dry-run")
+	sign := f.Bool("s", false, "create a GPG-signed tag")
+	f.Alias("s", "sign")
+	notesFromLog := f.Bool("notes-from-log", false, "write release notes from the commits since the previous tag")
+	versionFile := f.String("version-file", "", "`file` to update with the new version")
+	doPush := f.Bool("push", true, "push the tag to the remote")
+	github := f.Bool("github", true, "create a GitHub release (requires --push)")
+	draft := f.Bool("draft", false, "create the GitHub release as a draft")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() != 1 {
+		return usagef("must pass a single version")
+	}
+	version := f.Arg(0)
+	if strings.HasPrefix(version, "-") {
+		return usagef("invalid version %q", version)
+	}
+	if !*doPush && f.IsSet("github") && *github {
+		return usagef("cannot create a GitHub release with --push=0")
+	}
+	if !*doPush {
+		*github = false
+	}
+	tagRef := git.TagRef(version)
+	if err := cc.git.Run(ctx, "check-ref-format", tagRef.String()); err != nil {
+		return fmt.Errorf("%q is not a valid tag name", version)
+	}
+	if _, err := cc.git.ParseRev(ctx, tagRef.String()); err == nil {
+		return fmt.Errorf("tag %s already exists", version)
+	}
+	if clean, err := isClean(ctx, cc.git); err != nil {
+		return err
+	} else if !clean {
+		return errors.New("working copy has uncommitted changes")
+	}
+	head, err := cc.git.Head(ctx)
+	if err != nil {
+		return err
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	branch := head.Ref.Branch()
+	remote, err := inferPushRepo(cfg, branch)
+	if err != nil {
+		return err
+	}
+
+	prevTag, err := previousTag(ctx, cc.git, head.Commit.String())
+	if err != nil {
+		return err
+	}
+	var notes string
+	if *notesFromLog {
+		revs := []string{head.Commit.String()}
+		if prevTag != "" {
+			revs = append(revs, "^"+prevTag)
+		}
+		entries, err := readChangelogEntries(ctx, cc.git, changelogTrailer(cfg), revs)
+		if err != nil {
+			return err
+		}
+		notes = formatChangelog(entries, changelogMarkdown, "", time.Now())
+	}
+
+	top, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	bumpName, bumpPath := *versionFile, cc.abs(*versionFile)
+	if bumpName == "" {
+		bumpName = cfg.Value("gg.release.versionFile")
+		bumpPath = filepath.Join(top, filepath.FromSlash(bumpName))
+	}
+	var bumped []byte
+	var bumpTopPath git.TopPath
+	if bumpName != "" {
+		rel, err := filepath.Rel(top, bumpPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("version file %s is outside the repository", bumpName)
+		}
+		bumpTopPath = git.TopPath(filepath.ToSlash(rel))
+		if branch == "" {
+			return errors.New("cannot update the version file on a detached HEAD; check out a branch first")
+		}
+		old, err := os.ReadFile(bumpPath)
+		if err != nil {
+			return err
+		}
+		bumped, err = bumpVersion(old, strings.TrimPrefix(prevTag, "v"), strings.TrimPrefix(version, "v"))
+		if err != nil {
+			return fmt.Errorf("%s: %w", bumpName, err)
+		}
+	}
+
+	tagMsg := "Release " + version + "\n"
+	if notes != "" {
+		tagMsg += "\n" + notes
+	}
+	if *dryRun {
+		return printReleasePlan(cc, version, head, prevTag, bumpName, remote, *doPush, *github, tagMsg)
+	}
+
+	if bumped != nil {
+		if err := os.WriteFile(bumpPath, bumped, 0o666); err != nil {
+			return err
+		}
+		if err := cc.git.CommitFiles(ctx, "Release "+version, []git.Pathspec{bumpTopPath.Pathspec()}, git.CommitOptions{}); err != nil {
+			return err
+		}
+		head, err = cc.git.Head(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	tagArgs := []string{"tag", "--annotate", "--cleanup=whitespace", "--file=-"}
+	if *sign {
+		tagArgs[1] = "--sign"
+	}
+	tagArgs = append(tagArgs, "--", version, head.Commit.String())
+	err = cc.git.Runner().RunGit(ctx, &git.Invocation{
+		Args:   tagArgs,
+		Dir:    cc.dir,
+		Stdin:  strings.NewReader(tagMsg),
+		Stderr: cc.stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("create tag: %w", err)
+	}
+	if !*doPush {
+		_, err := fmt.Fprintf(cc.stdout, "tagged %v as %s\n", head.Commit.Short(), version)
+		return err
+	}
+
+	localRefs := map[git.Ref]git.Hash{tagRef: head.Commit}
+	pushArgs := []string{"push", "--porcelain", "--atomic", "--", remote, tagRef.String() + ":" + tagRef.String()}
+	if bumped != nil {
+		localRefs[head.Ref] = head.Commit
+		pushArgs = append(pushArgs, head.Ref.String()+":"+head.Ref.String())
+	}
+	pushCC, err := cc.withGitConfig("core.abbrev", pushAbbrev)
+	if err != nil {
+		return err
+	}
+	porcelain := new(bytes.Buffer)
+	pushErr := pushCC.git.Runner().RunGit(ctx, &git.Invocation{
+		Dir:    cc.dir,
+		Args:   pushArgs,
+		Stdin:  cc.stdin,
+		Stdout: porcelain,
+		Stderr: cc.stderr,
+	})
+	if err := writeRefChanges(cc.stdout, parsePushPorcelain(porcelain.String(), localRefs), false); err != nil {
+		return err
+	}
+	if pushErr != nil {
+		return fmt.Errorf("git push: %w (the tag %s was created locally)", pushErr, version)
+	}
+
+	if !*github {
+		return nil
+	}
+	remoteURL := cfg.Value("remote." + remote + ".url")
+	owner, repo := parseGitHubRemoteURL(remoteURL)
+	if owner == "" || repo == "" {
+		fmt.Fprintf(cc.stderr, "gg: %s is not a GitHub repository; not creating a GitHub release\n", remote)
+		return nil
+	}
+	token, err := readGitHubToken(ctx, cc)
+	if err != nil {
+		return err
+	}
+	releaseURL, err := createGitHubRelease(ctx, cc.httpClient, gitHubReleaseParams{
+		authToken: string(token),
+		owner:     owner,
+		repo:      repo,
+		tag:       version,
+		name:      version,
+		body:      notes,
+		draft:     *draft,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cc.stdout, "Created release at %s\n", releaseURL)
+	return err
+}
+
+// bumpVersion returns the contents of a version file with the version
+// changed to newVersion. A file that only contains a version is replaced
+// entirely. Otherwise, the first occurrence of oldVersion is replaced.
+func bumpVersion(content []byte, oldVersion, newVersion string) ([]byte, error) {
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && !bytes.ContainsAny(trimmed, " \t\n") {
+		return []byte(newVersion + "\n"), nil
+	}
+	if oldVersion == "" {
+		return nil, errors.New("no previous tag to find the old version")
+	}
+	i := bytes.Index(content, []byte(oldVersion))
+	if i == -1 {
+		return nil, fmt.Errorf("version %s not found", oldVersion)
+	}
+	bumped := make([]byte, 0, len(content)-len(oldVersion)+len(newVersion))
+	bumped = append(bumped, content[:i]...)
+	bumped = append(bumped, newVersion...)
+	bumped = append(bumped, content[i+len(oldVersion):]...)
+	return bumped, nil
+}
+
+func printReleasePlan(cc *cmdContext, version string, head *git.Rev, prevTag, bumpName, remote string, push, github bool, tagMsg string) error {
+	sb := new(strings.Builder)
+	if bumpName != "" {
+		fmt.Fprintf(sb, "update version in %s and commit\n", bumpName)
+	}
+	fmt.Fprintf(sb, "tag %s at %v", version, head.Commit.Short())
+	if prevTag != "" {
+		fmt.Fprintf(sb, " (previous tag %s)", prevTag)
+	}
+	sb.WriteString("\n")
+	if push {
+		fmt.Fprintf(sb, "push to %s\n", remote)
+	}
+	if github {
+		sb.WriteString("create GitHub release\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(tagMsg)
+	_, err := fmt.Fprint(cc.stdout, sb.String())
+	return err
+}
+
+type gitHubReleaseParams struct {
+	authToken string
+
+	owner string
+	repo  string
+	tag   string
+	name  string
+	body  string
+	draft bool
+}
+
+func createGitHubRelease(ctx context.Context, client *http.Client, params gitHubReleaseParams) (string, error) {
+	reqBody := map[string]interface{}{
+		"tag_name": params.tag,
+		"name":     params.name,
+		"draft":    params.draft,
+	}
+	if params.body != "" {
+		reqBody["body"] = params.body
+	}
+	reqBodyJSON, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("create release for %s/%s: %w", params.owner, params.repo, err)
+	}
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases",
+		url.PathEscape(params.owner), url.PathEscape(params.repo))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(reqBodyJSON))
+	if err != nil {
+		return "", fmt.Errorf("create release for %s/%s: %w", params.owner, params.repo, err)
+	}
+	req.Header.Set("User-Agent", userAgentString())
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "token "+params.authToken)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("create release for %s/%s: %w", params.owner, params.repo, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		err := parseGitHubErrorResponse(resp)
+		return "", fmt.Errorf("create release for %s/%s: %w", params.owner, params.repo, err)
+	}
+	var respDoc struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respDoc); err != nil {
+		return "", fmt.Errorf("create release for %s/%s: parsing response: %w", params.owner, params.repo, err)
+	}
+	return respDoc.HTMLURL, nil
+}
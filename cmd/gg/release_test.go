@@ -0,0 +1,190 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestRelease(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "--bare", "work", "origin.git"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "origin.git", "local"); err != nil {
+		t.Fatal(err)
+	}
+	localDir := env.root.FromSlash("local")
+	localGit := env.git.WithDir(localDir)
+	if err := localGit.Run(ctx, "tag", "--annotate", "--message=v1.0.0", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("local/VERSION", "1.0.0\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "local/VERSION"); err != nil {
+		t.Fatal(err)
+	}
+	if err := localGit.Commit(ctx, "Add version file", git.CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("local/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "local/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := localGit.Commit(ctx, "Add foo.txt", git.CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := localGit.Run(ctx, "config", "gg.release.versionFile", "VERSION"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, localDir, "release", "--notes-from-log", "v1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := env.root.ReadFile("local/VERSION"); err != nil {
+		t.Error(err)
+	} else if want := "1.1.0\n"; got != want {
+		t.Errorf("VERSION = %q; want %q", got, want)
+	}
+	head, err := localGit.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := head.Summary(), "Release v1.1.0"; got != want {
+		t.Errorf("HEAD summary = %q; want %q", got, want)
+	}
+	headRev, err := localGit.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tagged, err := localGit.ParseRev(ctx, "v1.1.0^{commit}"); err != nil {
+		t.Error(err)
+	} else if tagged.Commit != headRev.Commit {
+		t.Errorf("v1.1.0 = %v; want %v", tagged.Commit, headRev.Commit)
+	}
+	tagMsg, err := localGit.Output(ctx, "tag", "--list", "--format=%(contents)", "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Release v1.1.0", "- Add version file", "- Add foo.txt"} {
+		if !strings.Contains(tagMsg, want) {
+			t.Errorf("tag message = %q; want to contain %q", tagMsg, want)
+		}
+	}
+
+	originGit := env.git.WithDir(env.root.FromSlash("origin.git"))
+	if got, err := originGit.ParseRev(ctx, "refs/tags/v1.1.0^{commit}"); err != nil {
+		t.Error(err)
+	} else if got.Commit != headRev.Commit {
+		t.Errorf("v1.1.0 on remote = %v; want %v", got.Commit, headRev.Commit)
+	}
+	if got, err := originGit.ParseRev(ctx, "refs/heads/main"); err != nil {
+		t.Error(err)
+	} else if got.Commit != headRev.Commit {
+		t.Errorf("main on remote = %v; want %v", got.Commit, headRev.Commit)
+	}
+}
+
+func TestRelease_DryRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "origin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "origin", "local"); err != nil {
+		t.Fatal(err)
+	}
+	localDir := env.root.FromSlash("local")
+	out, err := env.gg(ctx, localDir, "release", "-n", "v0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); !strings.Contains(got, "tag v0.1.0 at ") || !strings.Contains(got, "push to origin\n") {
+		t.Errorf("gg release -n output:\n%s\nwant tag and push steps", got)
+	}
+	localGit := env.git.WithDir(localDir)
+	if _, err := localGit.ParseRev(ctx, "refs/tags/v0.1.0"); err == nil {
+		t.Error("gg release -n created the tag")
+	}
+
+	if err := env.root.Apply(filesystem.Write("local/foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "local/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, localDir, "release", "-n", "v0.1.0"); err == nil {
+		t.Error("gg release succeeded with uncommitted changes")
+	}
+}
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		content    string
+		oldVersion string
+		newVersion string
+		want       string
+		wantErr    bool
+	}{
+		{content: "1.0.0\n", oldVersion: "1.0.0", newVersion: "1.1.0", want: "1.1.0\n"},
+		{content: "0.9\n", oldVersion: "", newVersion: "1.0", want: "1.0\n"},
+		{
+			content:    "package main\n\nconst version = \"1.0.0\"\n",
+			oldVersion: "1.0.0",
+			newVersion: "1.0.1",
+			want:       "package main\n\nconst version = \"1.0.1\"\n",
+		},
+		{content: "const version = \"2.0\"\n", oldVersion: "1.0", newVersion: "1.1", wantErr: true},
+		{content: "const version = \"1.0\"\n", oldVersion: "", newVersion: "1.1", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := bumpVersion([]byte(test.content), test.oldVersion, test.newVersion)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("bumpVersion(%q, %q, %q) = _, %v", test.content, test.oldVersion, test.newVersion, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("bumpVersion(%q, %q, %q) = %q, <nil>; want error", test.content, test.oldVersion, test.newVersion, got)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("bumpVersion(%q, %q, %q) = %q; want %q", test.content, test.oldVersion, test.newVersion, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+// pathStyleHelp is the paragraph of help text for commands that accept
+// pathStyleFlags.
+const pathStyleHelp = `
+
+	Paths are printed relative to the top of the repository unless
+	` + "`--relative`" + ` is given or the relative-paths setting is on
+	(see ` + "`gg config`" + `), in which case they are printed relative to the
+	current directory. ` + "`--root-relative`" + ` overrides the setting.`
+
+// pathStyleFlags is the set of flags that select how a command prints
+// file paths.
+type pathStyleFlags struct {
+	relative     bool
+	rootRelative bool
+}
+
+func (psf *pathStyleFlags) addFlags(f *flag.FlagSet) {
+	f.BoolVar(&psf.relative, "relative", false, "print paths relative to the current directory")
+	f.BoolVar(&psf.rootRelative, "root-relative", false, "print paths relative to the top of the repository")
+}
+
+// isRelative reports whether paths should be printed relative to the
+// current directory, based on the flags and the gg.relativePaths
+// setting.
+func (psf *pathStyleFlags) isRelative(cfg *git.Config) (bool, error) {
+	switch {
+	case psf.relative && psf.rootRelative:
+		return false, usagef("can't pass both --relative and --root-relative")
+	case psf.relative:
+		return true, nil
+	case psf.rootRelative:
+		return false, nil
+	case cfg.Value("gg.relativePaths") == "":
+		return false, nil
+	default:
+		return cfg.Bool("gg.relativePaths")
+	}
+}
+
+// formatter returns a pathFormatter for the current directory.
+func (psf *pathStyleFlags) formatter(ctx context.Context, cc *cmdContext, cfg *git.Config) (*pathFormatter, error) {
+	relative, err := psf.isRelative(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !relative {
+		return new(pathFormatter), nil
+	}
+	prefix, err := cc.git.Output(ctx, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	return &pathFormatter{dir: strings.TrimSuffix(strings.TrimSuffix(prefix, "\n"), "/")}, nil
+}
+
+// A pathFormatter converts paths relative to the top of the working
+// tree into the form that the user asked to see.
+// The zero value prints paths unchanged.
+type pathFormatter struct {
+	// dir is the slash-separated path of the directory that paths are
+	// printed relative to, or empty for the top of the working tree.
+	dir string
+}
+
+// format returns p relative to the formatter's directory, using ".."
+// for files outside of it.
+func (pf *pathFormatter) format(p git.TopPath) string {
+	name := p.String()
+	if pf == nil || pf.dir == "" || name == "" {
+		return name
+	}
+	dir := pf.dir
+	up := 0
+	for dir != "" && !strings.HasPrefix(name, dir+"/") {
+		up++
+		if i := strings.LastIndexByte(dir, '/'); i >= 0 {
+			dir = dir[:i]
+		} else {
+			dir = ""
+		}
+	}
+	if dir != "" {
+		name = name[len(dir)+1:]
+	}
+	return strings.Repeat("../", up) + name
+}
+
+// diffRelativeStatArgs keeps git diff --stat from shortening file
+// names, so that rewriteDiffStat sees whole paths.
+var diffRelativeStatArgs = []string{"--stat-width=4096", "--stat-name-width=4096", "--stat-graph-width=40"}
+
+// A diffPathRewriter is a line filter for cmdContext.filteredGit that
+// rewrites the paths in the headers of git diff's patch output with a
+// pathFormatter. Unlike git diff --relative, it doesn't leave out the
+// files outside of the current directory.
+type diffPathRewriter struct {
+	pf       *pathFormatter
+	inHeader bool
+}
+
+func (r *diffPathRewriter) line(line string) string {
+	start, text, end := splitColorEscapes(line)
+	switch {
+	case strings.HasPrefix(text, "diff --git "):
+		r.inHeader = true
+		text = "diff --git " + r.formatGitHeader(strings.TrimPrefix(text, "diff --git "))
+	case strings.HasPrefix(text, "diff --cc "), strings.HasPrefix(text, "diff --combined "):
+		r.inHeader = true
+		cmd, name, _ := strings.Cut(text[len("diff "):], " ")
+		text = "diff " + cmd + " " + r.formatName(name)
+	case strings.HasPrefix(text, "@@"):
+		r.inHeader = false
+	case !r.inHeader:
+	case strings.HasPrefix(text, "--- a/"):
+		text = "--- a/" + r.formatName(text[len("--- a/"):])
+	case strings.HasPrefix(text, "+++ b/"):
+		text = "+++ b/" + r.formatName(text[len("+++ b/"):])
+	default:
+		for _, prefix := range []string{"rename from ", "rename to ", "copy from ", "copy to "} {
+			if name, ok := strings.CutPrefix(text, prefix); ok {
+				text = prefix + r.formatName(name)
+				break
+			}
+		}
+	}
+	return start + text + end
+}
+
+// formatGitHeader formats the "a/OLD b/NEW" part of a diff --git line.
+func (r *diffPathRewriter) formatGitHeader(names string) string {
+	if !strings.HasPrefix(names, "a/") {
+		return names
+	}
+	// The names may contain spaces, so look for the split that gives
+	// the same name on both sides first, as in all but renames.
+	if n := len(names) - len("a/ b/"); n > 0 && n%2 == 0 {
+		oldName, newName := names[2:2+n/2], names[len(names)-n/2:]
+		if names[2+n/2:len(names)-n/2] == " b/" && oldName == newName {
+			return "a/" + r.formatName(oldName) + " b/" + r.formatName(newName)
+		}
+	}
+	if strings.Count(names, " b/") != 1 {
+		return names
+	}
+	oldName, newName, _ := strings.Cut(names[2:], " b/")
+	return "a/" + r.formatName(oldName) + " b/" + r.formatName(newName)
+}
+
+// formatName formats a top-level path from git diff's output. Quoted
+// names are left alone.
+func (r *diffPathRewriter) formatName(name string) string {
+	if name == "" || strings.HasPrefix(name, `"`) {
+		return name
+	}
+	return r.pf.format(git.TopPath(name))
+}
+
+// rewriteDiffStat rewrites the paths in the output of
+// git diff --stat --summary with diffRelativeStatArgs, realigning the
+// stat lines to the new names.
+func rewriteDiffStat(out string, pf *pathFormatter) string {
+	lines := strings.SplitAfter(out, "\n")
+	type statLine struct {
+		i    int
+		name string
+		rest string
+	}
+	var stats []statLine
+	width := 0
+	for i, line := range lines {
+		if !strings.HasPrefix(line, " ") {
+			continue
+		}
+		if j := strings.Index(line, " | "); j != -1 {
+			name := formatDiffStatName(strings.TrimRight(line[1:j], " "), pf)
+			stats = append(stats, statLine{i: i, name: name, rest: line[j:]})
+			if n := utf8.RuneCountInString(name); n > width {
+				width = n
+			}
+			continue
+		}
+		lines[i] = rewriteDiffSummary(line, pf)
+	}
+	for _, st := range stats {
+		lines[st.i] = " " + st.name + strings.Repeat(" ", width-utf8.RuneCountInString(st.name)) + st.rest
+	}
+	return strings.Join(lines, "")
+}
+
+// rewriteDiffSummary rewrites the path in a line of git diff --summary,
+// like " create mode 100644 foo.txt".
+func rewriteDiffSummary(line string, pf *pathFormatter) string {
+	text := strings.TrimSuffix(line, "\n")
+	nl := line[len(text):]
+	for _, prefix := range []string{" create mode ", " delete mode "} {
+		if rest, ok := strings.CutPrefix(text, prefix); ok {
+			mode, name, ok := strings.Cut(rest, " ")
+			if ok {
+				return prefix + mode + " " + formatDiffStatName(name, pf) + nl
+			}
+		}
+	}
+	if rest, ok := strings.CutPrefix(text, " mode change "); ok {
+		if i := strings.Index(rest, " => "); i != -1 {
+			if modes, name, ok := strings.Cut(rest[i+len(" => "):], " "); ok {
+				return " mode change " + rest[:i+len(" => ")] + modes + " " + formatDiffStatName(name, pf) + nl
+			}
+		}
+	}
+	for _, prefix := range []string{" rename ", " copy ", " rewrite "} {
+		if rest, ok := strings.CutPrefix(text, prefix); ok {
+			if i := strings.LastIndex(rest, " ("); i != -1 && strings.HasSuffix(rest, "%)") {
+				return prefix + formatDiffStatName(rest[:i], pf) + rest[i:] + nl
+			}
+		}
+	}
+	return line
+}
+
+// formatDiffStatName formats a name from git diff --stat, which may be
+// a rename like "dir/{old => new}/file" or "old => new".
+func formatDiffStatName(name string, pf *pathFormatter) string {
+	if strings.HasPrefix(name, `"`) {
+		return name
+	}
+	oldName, newName, isRename := strings.Cut(name, " => ")
+	if !isRename {
+		return pf.format(git.TopPath(name))
+	}
+	if i, j := strings.Index(name, "{"), strings.LastIndex(name, "}"); i != -1 && i < j {
+		prefix, suffix := name[:i], name[j+1:]
+		oldName, newName, _ = strings.Cut(name[i+1:j], " => ")
+		// An empty side leaves a doubled or leading slash behind, as in
+		// "{ => dir}/file".
+		oldName = strings.TrimPrefix(path.Clean(prefix+oldName+suffix), "/")
+		newName = strings.TrimPrefix(path.Clean(prefix+newName+suffix), "/")
+	}
+	return pf.format(git.TopPath(oldName)) + " => " + pf.format(git.TopPath(newName))
+}
+
+// splitColorEscapes splits the SGR escape sequences at the start and end
+// of a line of colored Git output from the text between them.
+func splitColorEscapes(line string) (start, text, end string) {
+	text = line
+	for strings.HasPrefix(text, "\x1b[") {
+		i := strings.IndexByte(text, 'm')
+		if i == -1 {
+			break
+		}
+		text = text[i+1:]
+	}
+	start = line[:len(line)-len(text)]
+	for strings.HasSuffix(text, "m") {
+		i := strings.LastIndex(text, "\x1b[")
+		if i == -1 || strings.Trim(text[i+2:len(text)-1], "0123456789;") != "" {
+			break
+		}
+		text = text[:i]
+	}
+	end = line[len(start)+len(text):]
+	return start, text, end
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"gg-scm.io/pkg/git"
+)
+
+func TestPathFormatter(t *testing.T) {
+	tests := []struct {
+		dir  string
+		path git.TopPath
+		want string
+	}{
+		{dir: "", path: "foo.txt", want: "foo.txt"},
+		{dir: "", path: "a/b/foo.txt", want: "a/b/foo.txt"},
+		{dir: "a", path: "a/foo.txt", want: "foo.txt"},
+		{dir: "a", path: "foo.txt", want: "../foo.txt"},
+		{dir: "a/b", path: "a/b/c/foo.txt", want: "c/foo.txt"},
+		{dir: "a/b", path: "a/c/foo.txt", want: "../c/foo.txt"},
+		{dir: "a/b", path: "x/foo.txt", want: "../../x/foo.txt"},
+		{dir: "ab", path: "a/foo.txt", want: "../a/foo.txt"},
+		{dir: "a", path: "ab/foo.txt", want: "../ab/foo.txt"},
+	}
+	for _, test := range tests {
+		pf := &pathFormatter{dir: test.dir}
+		if got := pf.format(test.path); got != test.want {
+			t.Errorf("(&pathFormatter{dir: %q}).format(%q) = %q; want %q", test.dir, test.path, got, test.want)
+		}
+	}
+}
+
+func TestDiffPathRewriter(t *testing.T) {
+	pf := &pathFormatter{dir: "sub"}
+	tests := []struct {
+		lines []string
+		want  []string
+	}{
+		{
+			lines: []string{
+				"diff --git a/sub/inner.txt b/sub/inner.txt",
+				"index 1234567..89abcde 100644",
+				"--- a/sub/inner.txt",
+				"+++ b/sub/inner.txt",
+				"@@ -1 +1 @@",
+				"--- a/sub/inner.txt",
+			},
+			want: []string{
+				"diff --git a/inner.txt b/inner.txt",
+				"index 1234567..89abcde 100644",
+				"--- a/inner.txt",
+				"+++ b/inner.txt",
+				"@@ -1 +1 @@",
+				"--- a/sub/inner.txt",
+			},
+		},
+		{
+			lines: []string{
+				"diff --git a/top.txt b/sub/moved.txt",
+				"similarity index 100%",
+				"rename from top.txt",
+				"rename to sub/moved.txt",
+			},
+			want: []string{
+				"diff --git a/../top.txt b/moved.txt",
+				"similarity index 100%",
+				"rename from ../top.txt",
+				"rename to moved.txt",
+			},
+		},
+		{
+			lines: []string{
+				"\x1b[1mdiff --git a/my file.txt b/my file.txt\x1b[m",
+				"\x1b[1m--- a/my file.txt\x1b[m",
+				"\x1b[1m+++ /dev/null\x1b[m",
+			},
+			want: []string{
+				"\x1b[1mdiff --git a/../my file.txt b/../my file.txt\x1b[m",
+				"\x1b[1m--- a/../my file.txt\x1b[m",
+				"\x1b[1m+++ /dev/null\x1b[m",
+			},
+		},
+	}
+	for _, test := range tests {
+		r := &diffPathRewriter{pf: pf}
+		for i, line := range test.lines {
+			if got := r.line(line); got != test.want[i] {
+				t.Errorf("line(%q) = %q; want %q", line, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestRewriteDiffStat(t *testing.T) {
+	pf := &pathFormatter{dir: "sub"}
+	const out = " sub/inner.txt | 2 +-\n" +
+		" top.txt       | 1 +\n" +
+		" {sub => x}/a.txt | 0\n" +
+		" 3 files changed, 2 insertions(+), 1 deletion(-)\n" +
+		" create mode 100644 top.txt\n" +
+		" rename {sub => x}/a.txt (100%)\n"
+	const want = " inner.txt           | 2 +-\n" +
+		" ../top.txt          | 1 +\n" +
+		" a.txt => ../x/a.txt | 0\n" +
+		" 3 files changed, 2 insertions(+), 1 deletion(-)\n" +
+		" create mode 100644 ../top.txt\n" +
+		" rename a.txt => ../x/a.txt (100%)\n"
+	if got := rewriteDiffStat(out, pf); got != want {
+		t.Errorf("rewriteDiffStat(...) =\n%s\nwant:\n%s", got, want)
+	}
+}
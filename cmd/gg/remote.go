@@ -0,0 +1,144 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const remoteSynopsis = "list remotes or refresh their default branches"
+
+func remote(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg remote [set-default [REMOTE [BRANCH]]]", remoteSynopsis+`
+
+	With no arguments, list the remotes with their URLs and default
+	branches. The default branch of a remote is the branch that its HEAD
+	points to, which is recorded locally as `+"`refs/remotes/REMOTE/HEAD`"+`
+	when the repository is cloned.
+
+	`+"`set-default`"+` asks REMOTE (origin if not given) for its default
+	branch and records it, which is needed if the default branch was
+	renamed after cloning or the remote was added with `+"`git remote add`"+`.
+	If BRANCH is given, it is recorded without contacting the remote.
+
+	gg uses the default branch in `+"`update --default`"+` and as the base for
+	`+"`rebase`"+` and `+"`requestpull`"+` when the current branch has no upstream.`)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	switch f.Arg(0) {
+	case "":
+		return listRemotes(ctx, cc)
+	case "set-default":
+		if f.NArg() > 3 {
+			return usagef("set-default takes at most a remote and a branch")
+		}
+		name := f.Arg(1)
+		if name == "" {
+			name = "origin"
+		}
+		return setRemoteDefault(ctx, cc, name, f.Arg(2))
+	default:
+		return usagef("unknown subcommand %q", f.Arg(0))
+	}
+}
+
+func listRemotes(ctx context.Context, cc *cmdContext) error {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	remotes := cfg.ListRemotes()
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		def, err := remoteDefaultBranch(ctx, cc.git, name)
+		if err != nil {
+			return err
+		}
+		if def == "" {
+			def = "(unknown; run 'gg remote set-default " + name + "')"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, remotes[name].FetchURL, def)
+	}
+	return tw.Flush()
+}
+
+func setRemoteDefault(ctx context.Context, cc *cmdContext, name, branch string) error {
+	if strings.HasPrefix(name, "-") || strings.HasPrefix(branch, "-") {
+		return errors.New("remote and branch names cannot start with '-'")
+	}
+	setHeadArgs := []string{"remote", "set-head", name, "--auto"}
+	if branch != "" {
+		setHeadArgs = []string{"remote", "set-head", name, branch}
+	}
+	if err := cc.git.Run(ctx, setHeadArgs...); err != nil {
+		return fmt.Errorf("set default branch of %s: %w", name, err)
+	}
+	def, err := remoteDefaultBranch(ctx, cc.git, name)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cc.stdout, "default branch of %s is %s\n", name, def)
+	return err
+}
+
+// remoteDefaultBranch returns the name of the branch that the given
+// remote's HEAD points to, as recorded in refs/remotes/REMOTE/HEAD.
+// It returns the empty string if the default branch is not known.
+func remoteDefaultBranch(ctx context.Context, g *git.Git, remote string) (string, error) {
+	headRef := "refs/remotes/" + remote + "/HEAD"
+	out, err := g.Output(ctx, "for-each-ref", "--format=%(symref)", "--", headRef)
+	if err != nil {
+		return "", fmt.Errorf("default branch of %s: %w", remote, err)
+	}
+	target := strings.TrimSuffix(out, "\n")
+	return strings.TrimPrefix(target, "refs/remotes/"+remote+"/"), nil
+}
+
+// defaultBranchRef returns the remote-tracking branch of the default
+// branch of the remote that localBranch pulls from, or origin if
+// localBranch has no remote. It returns the empty string if the remote's
+// default branch is not known.
+func defaultBranchRef(ctx context.Context, g *git.Git, cfg *git.Config, localBranch string) (remote, branch string, err error) {
+	remote = "origin"
+	if localBranch != "" {
+		if r := cfg.Value("branch." + localBranch + ".remote"); r != "" && r != "." {
+			remote = r
+		}
+	}
+	branch, err = remoteDefaultBranch(ctx, g, remote)
+	if err != nil || branch == "" {
+		return "", "", err
+	}
+	return remote, branch, nil
+}
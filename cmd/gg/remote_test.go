@@ -0,0 +1,75 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRemote(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "origin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "origin", "local"); err != nil {
+		t.Fatal(err)
+	}
+	localDir := env.root.FromSlash("local")
+
+	out, err := env.gg(ctx, localDir, "remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); !strings.HasPrefix(got, "origin ") || !strings.HasSuffix(got, " main\n") {
+		t.Errorf("gg remote = %q; want origin with default branch main", got)
+	}
+
+	// Rename the default branch on the remote.
+	originGit := env.git.WithDir(env.root.FromSlash("origin"))
+	if err := originGit.Run(ctx, "branch", "--move", "main", "trunk"); err != nil {
+		t.Fatal(err)
+	}
+	localGit := env.git.WithDir(localDir)
+	if err := localGit.Run(ctx, "fetch", "--quiet", "--prune", "origin"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, localDir, "remote", "set-default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "default branch of origin is trunk\n"; got != want {
+		t.Errorf("gg remote set-default = %q; want %q", got, want)
+	}
+	if got, err := remoteDefaultBranch(ctx, localGit, "origin"); err != nil {
+		t.Error(err)
+	} else if got != "trunk" {
+		t.Errorf("default branch after set-default = %q; want \"trunk\"", got)
+	}
+
+	if _, err := env.gg(ctx, localDir, "remote", "bogus"); err == nil {
+		t.Error("gg remote bogus succeeded")
+	} else if !isUsage(err) {
+		t.Errorf("gg remote bogus = %v; want usage error", err)
+	}
+}
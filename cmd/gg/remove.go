@@ -16,7 +16,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
@@ -25,29 +29,64 @@ import (
 const removeSynopsis = "remove the specified files on the next commit"
 
 func remove(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg remove [-f] [-r] [-after] FILE [...]", removeSynopsis+"\n\n"+
-		"aliases: rm")
+	f := flag.NewFlagSet(true, "gg remove [-f] [--after [--relative | --root-relative]] [-I PATTERN] [-X PATTERN] FILE [...]", removeSynopsis+`
+
+aliases: rm
+
+	Schedule the given files to be removed from the repository at the next
+	commit and delete them from the working copy. Directories are removed
+	with all the files under them unless `+"`-r=false`"+` is given. An
+	argument that contains shell glob characters (`+"`*`"+`, `+"`?`"+`, or
+	`+"`[`"+`) and is not the name of an existing file is matched as a glob,
+	so patterns work even where the shell does not expand them, like on
+	Windows.
+
+	By default, `+"`remove`"+` refuses to remove files that were added or
+	modified since the last commit unless `+"`-f`"+` is given. It also refuses
+	to remove files that are already missing from the working copy, since
+	that usually means the wrong file was named. With `+"`--after`"+`, only
+	files that are already missing are removed from the repository, and
+	files that still exist are left alone.`+patternHelp+pathStyleHelp)
 	after := f.Bool("after", false, "record delete for missing files")
 	force := f.Bool("f", false, "forget added files, delete modified files")
 	f.Alias("f", "force")
-	recursive := f.Bool("r", false, "remove files under any directory specified")
+	recursive := f.Bool("r", true, "remove files under any directory specified; -r=false refuses to remove directories")
+	pats := new(patternSet)
+	pats.addFlags(f)
+	pathStyle := new(pathStyleFlags)
+	pathStyle.addFlags(f)
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
-	if f.NArg() == 0 {
+	if f.NArg() == 0 && len(pats.includes) == 0 {
 		return usagef("must pass one or more files to remove")
 	}
-	if !*after {
-		if err := verifyPresent(ctx, cc.git, f.Args()); err != nil {
+	if *after && *force {
+		return usagef("cannot pass both --after and -f")
+	}
+	for _, arg := range f.Args() {
+		pats.args = append(pats.args, removeArgPattern(cc.dir, arg))
+	}
+	pathspecs, err := pats.pathspecs(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if *after {
+		cfg, err := cc.git.ReadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		pf, err := pathStyle.formatter(ctx, cc, cfg)
+		if err != nil {
 			return err
 		}
+		return removeMissing(ctx, cc, pf, pathspecs)
 	}
-	pathspecs := make([]git.Pathspec, 0, f.NArg())
-	for _, arg := range f.Args() {
-		pathspecs = append(pathspecs, git.LiteralPath(arg))
+	if err := verifyPresent(ctx, cc.git, pathspecs); err != nil {
+		return err
 	}
 	return cc.git.Remove(ctx, pathspecs, git.RemoveOptions{
 		Recursive: *recursive,
@@ -55,13 +94,87 @@ func remove(ctx context.Context, cc *cmdContext, args []string) error {
 	})
 }
 
-func verifyPresent(ctx context.Context, g *git.Git, args []string) error {
-	statusArgs := make([]git.Pathspec, len(args))
-	for i := range args {
-		statusArgs[i] = git.LiteralPath(args[i])
+// removeArgPattern returns the pattern to use for a remove argument.
+// Arguments that name an existing file or already have a pattern kind
+// are returned verbatim. Otherwise, arguments with glob characters are
+// treated as glob: patterns, since the shell may not have expanded them.
+func removeArgPattern(dir string, arg string) string {
+	if hasPatternKind(arg) || !strings.ContainsAny(arg, "*?[") {
+		return arg
+	}
+	path := arg
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return arg
+	}
+	return "glob:" + filepath.ToSlash(arg)
+}
+
+// removeMissing removes the tracked files matched by pathspecs that are
+// missing from the working copy, like hg remove --after. Files that
+// still exist are reported, formatted with pf, and left alone.
+func removeMissing(ctx context.Context, cc *cmdContext, pf *pathFormatter, pathspecs []git.Pathspec) error {
+	st, err := cc.git.Status(ctx, git.StatusOptions{
+		Pathspecs: pathspecs,
+	})
+	if err != nil {
+		return err
+	}
+	var missing []git.Pathspec
+	missingSet := make(map[git.TopPath]struct{})
+	for _, ent := range st {
+		if ent.Code.IsMissing() {
+			missing = append(missing, ent.Name.Pathspec())
+			missingSet[ent.Name] = struct{}{}
+		}
+	}
+	tracked, err := listTrackedFiles(ctx, cc.git, pathspecs)
+	if err != nil {
+		return err
+	}
+	for _, name := range tracked {
+		if _, isMissing := missingSet[name]; !isMissing {
+			fmt.Fprintf(cc.stderr, "gg: not removing %s: file still exists\n", pf.format(name))
+		}
+	}
+	if len(missing) == 0 {
+		return errors.New("no missing files to remove")
+	}
+	return cc.git.Remove(ctx, missing, git.RemoveOptions{
+		KeepWorkingCopy: true,
+	})
+}
+
+// listTrackedFiles returns the files in the index that match pathspecs.
+func listTrackedFiles(ctx context.Context, g *git.Git, pathspecs []git.Pathspec) ([]git.TopPath, error) {
+	args := []string{"ls-files", "-z", "--cached", "--full-name", "--"}
+	for _, spec := range pathspecs {
+		args = append(args, spec.String())
+	}
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
 	}
+	var files []git.TopPath
+	seen := make(map[string]struct{})
+	for _, name := range strings.Split(out, "\x00") {
+		if name == "" {
+			continue
+		}
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+		files = append(files, git.TopPath(name))
+	}
+	return files, nil
+}
+
+func verifyPresent(ctx context.Context, g *git.Git, pathspecs []git.Pathspec) error {
 	st, err := g.Status(ctx, git.StatusOptions{
-		Pathspecs: statusArgs,
+		Pathspecs: pathspecs,
 	})
 	if err != nil {
 		return err
@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
@@ -367,6 +368,16 @@ func TestRemove_Recursive(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Removing the directory without recursion should fail.
+	if _, err := env.gg(ctx, env.root.String(), "rm", "-r=false", "foo"); err == nil {
+		t.Error("gg rm -r=false foo succeeded")
+	}
+	if exists, err := env.root.Exists("foo/bar.txt"); err != nil {
+		t.Error(err)
+	} else if !exists {
+		t.Error("foo/bar.txt does not exist after gg rm -r=false")
+	}
+
 	// Call gg to remove the foo directory.
 	if _, err := env.gg(ctx, env.root.String(), "rm", "-r", "foo"); err != nil {
 		t.Error(err)
@@ -480,3 +491,154 @@ func TestRemove_RecursiveMissingAfter(t *testing.T) {
 		t.Errorf("status (-want +got):\n%s", diff)
 	}
 }
+
+func TestRemove_DirectoryRecursesByDefault(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository with a committed foo/bar.txt file.
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo/bar.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo/bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	// Call gg to remove the foo directory without -r.
+	if _, err := env.gg(ctx, env.root.String(), "rm", "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify that foo/bar.txt is not in the working copy or the index.
+	if exists, err := env.root.Exists("foo/bar.txt"); err != nil {
+		t.Error(err)
+	} else if exists {
+		t.Error("foo/bar.txt exists after gg rm")
+	}
+	st, err := env.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []git.StatusEntry{
+		{Code: git.StatusCode{'D', ' '}, Name: "foo/bar.txt"},
+	}
+	if diff := cmp.Diff(want, st); diff != "" {
+		t.Errorf("status (-want +got):\n%s", diff)
+	}
+}
+
+func TestRemove_Glob(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository with committed a.txt, b.txt, and c.md files.
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("a.txt", dummyContent),
+		filesystem.Write("b.txt", dummyContent),
+		filesystem.Write("c.md", dummyContent),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "a.txt", "b.txt", "c.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	// Call gg with an unexpanded glob, as a shell on Windows would.
+	if _, err := env.gg(ctx, env.root.String(), "rm", "*.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := env.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []git.StatusEntry{
+		{Code: git.StatusCode{'D', ' '}, Name: "a.txt"},
+		{Code: git.StatusCode{'D', ' '}, Name: "b.txt"},
+	}
+	if diff := cmp.Diff(want, st); diff != "" {
+		t.Errorf("status (-want +got):\n%s", diff)
+	}
+	if exists, err := env.root.Exists("c.md"); err != nil {
+		t.Error(err)
+	} else if !exists {
+		t.Error("c.md was removed")
+	}
+}
+
+func TestRemove_AfterKeepsExisting(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository with committed foo/a.txt and foo/b.txt files.
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("foo/a.txt", dummyContent),
+		filesystem.Write("foo/b.txt", dummyContent),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo/a.txt", "foo/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	// Remove foo/a.txt without informing Git.
+	if err := env.root.Apply(filesystem.Remove("foo/a.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Call gg to record removals in foo.
+	if _, err := env.gg(ctx, env.root.String(), "rm", "--after", "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify that only foo/a.txt was removed.
+	st, err := env.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []git.StatusEntry{
+		{Code: git.StatusCode{'D', ' '}, Name: "foo/a.txt"},
+	}
+	if diff := cmp.Diff(want, st); diff != "" {
+		t.Errorf("status (-want +got):\n%s", diff)
+	}
+	if exists, err := env.root.Exists("foo/b.txt"); err != nil {
+		t.Error(err)
+	} else if !exists {
+		t.Error("foo/b.txt was deleted")
+	}
+	if got, want := env.stderr.String(), "not removing foo/b.txt: file still exists"; !strings.Contains(got, want) {
+		t.Errorf("stderr = %q; want to contain %q", got, want)
+	}
+}
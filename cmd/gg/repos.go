@@ -0,0 +1,305 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const reposSynopsis = "bookmark repositories and show their status"
+
+// reposSection is the Git configuration section that stores repository
+// bookmarks. Each bookmark is a subsection, as in "ggrepo.web.path".
+const reposSection = "ggrepo"
+
+// reposStatusParallelism is the number of repositories that
+// gg repos status checks at once.
+const reposStatusParallelism = 8
+
+// reposShellFunction wraps gg so that gg cd changes the shell's
+// directory, which a child process can't do.
+const reposShellFunction = `gg() {
+  if [ "$1" = cd ]; then
+    local dir
+    dir="$(command gg repos path "$2")" && cd -- "$dir"
+  else
+    command gg "$@"
+  fi
+}
+`
+
+func repos(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg repos [list]\n"+
+		"gg repos add NAME [PATH]\n"+
+		"gg repos remove NAME\n"+
+		"gg repos path NAME\n"+
+		"gg repos status\n"+
+		"gg repos shell", reposSynopsis+`
+
+	Repository bookmarks give short names to the repositories you work in
+	most. They are stored in the user's Git configuration, so they can be
+	used from any directory.
+
+	`+"`gg repos add`"+` bookmarks PATH, or the current working copy if no
+	PATH is given. `+"`gg repos path`"+` prints the path of a bookmark, and
+	`+"`gg repos status`"+` prints the branch of each bookmarked repository,
+	whether it has uncommitted changes, and how many commits it is ahead
+	of and behind its upstream.
+
+	`+"`gg repos shell`"+` prints a shell function for bash and zsh that makes
+	`+"`gg cd NAME`"+` change to a bookmarked repository. Add
+	`+"`eval \"$(gg repos shell)\"`"+` to your shell's startup file to use it.`)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	sub := f.Arg(0)
+	switch sub {
+	case "", "list":
+		if f.NArg() > 1 {
+			return usagef("list takes no arguments")
+		}
+		return listRepos(ctx, cc)
+	case "status":
+		if f.NArg() > 1 {
+			return usagef("status takes no arguments")
+		}
+		return reposStatus(ctx, cc)
+	case "shell":
+		if f.NArg() > 1 {
+			return usagef("shell takes no arguments")
+		}
+		_, err := fmt.Fprint(cc.stdout, reposShellFunction)
+		return err
+	case "add":
+		if f.NArg() != 2 && f.NArg() != 3 {
+			return usagef("add takes a NAME and an optional PATH")
+		}
+	case "remove", "path":
+		if f.NArg() != 2 {
+			return usagef("%s takes a single NAME", sub)
+		}
+	default:
+		return usagef("unknown subcommand %q", sub)
+	}
+	name := f.Arg(1)
+	if !identityNameRegexp.MatchString(name) {
+		return usagef("invalid bookmark name %q (must be lowercase letters, digits, '-', or '_')", name)
+	}
+	switch sub {
+	case "add":
+		path := f.Arg(2)
+		if path == "" {
+			top, err := cc.git.WorkTree(ctx)
+			if err != nil {
+				return err
+			}
+			path = top
+		}
+		path = cc.abs(path)
+		if info, err := os.Stat(path); err != nil {
+			return err
+		} else if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", path)
+		}
+		return cc.git.Run(ctx, "config", "--global", "--", reposSection+"."+name+".path", path)
+	case "path":
+		bookmarks, err := readRepoBookmarks(ctx, cc.git)
+		if err != nil {
+			return err
+		}
+		path := bookmarks[name]
+		if path == "" {
+			return fmt.Errorf("no repository bookmark %q", name)
+		}
+		_, err = fmt.Fprintln(cc.stdout, path)
+		return err
+	default:
+		bookmarks, err := readRepoBookmarks(ctx, cc.git)
+		if err != nil {
+			return err
+		}
+		if bookmarks[name] == "" {
+			return fmt.Errorf("no repository bookmark %q", name)
+		}
+		return cc.git.Run(ctx, "config", "--global", "--remove-section", "--", reposSection+"."+name)
+	}
+}
+
+// readRepoBookmarks returns the paths of the repository bookmarks in the
+// Git configuration, keyed by name.
+func readRepoBookmarks(ctx context.Context, g *git.Git) (map[string]string, error) {
+	entries, err := listConfig(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	bookmarks := make(map[string]string)
+	for _, entry := range entries {
+		rest, ok := strings.CutPrefix(entry.key, reposSection+".")
+		if !ok {
+			continue
+		}
+		name, ok := strings.CutSuffix(rest, ".path")
+		if ok && identityNameRegexp.MatchString(name) {
+			bookmarks[name] = entry.value
+		}
+	}
+	return bookmarks, nil
+}
+
+// sortedRepoNames returns the names of the bookmarks in order.
+func sortedRepoNames(bookmarks map[string]string) []string {
+	names := make([]string, 0, len(bookmarks))
+	for name := range bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func listRepos(ctx context.Context, cc *cmdContext) error {
+	bookmarks, err := readRepoBookmarks(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if len(bookmarks) == 0 {
+		_, err := fmt.Fprintln(cc.stdout, "no repository bookmarks (add one with 'gg repos add')")
+		return err
+	}
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, name := range sortedRepoNames(bookmarks) {
+		fmt.Fprintf(tw, "%s\t%s\n", name, bookmarks[name])
+	}
+	return tw.Flush()
+}
+
+// A repoSummary is the state of a bookmarked repository.
+type repoSummary struct {
+	branch string // empty if HEAD is detached
+	dirty  bool
+
+	hasUpstream   bool
+	ahead, behind int
+}
+
+func reposStatus(ctx context.Context, cc *cmdContext) error {
+	bookmarks, err := readRepoBookmarks(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if len(bookmarks) == 0 {
+		_, err := fmt.Fprintln(cc.stdout, "no repository bookmarks (add one with 'gg repos add')")
+		return err
+	}
+	names := sortedRepoNames(bookmarks)
+	summaries := make([]*repoSummary, len(names))
+	errs := make([]error, len(names))
+	sem := make(chan struct{}, reposStatusParallelism)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			summaries[i], errs[i] = readRepoSummary(ctx, cc.git.WithDir(path))
+		}(i, bookmarks[name])
+	}
+	wg.Wait()
+
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	var failed []string
+	for i, name := range names {
+		if errs[i] != nil {
+			msg, _, _ := strings.Cut(errs[i].Error(), "\n")
+			fmt.Fprintf(tw, "%s\terror: %s\n", name, msg)
+			failed = append(failed, name)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", name, summaries[i])
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not read status of %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// readRepoSummary reads the branch, working copy, and upstream state of
+// the repository that g operates in.
+func readRepoSummary(ctx context.Context, g *git.Git) (*repoSummary, error) {
+	out, err := g.Output(ctx, "status", "--porcelain=v2", "--branch", "--ignore-submodules")
+	if err != nil {
+		return nil, err
+	}
+	s := new(repoSummary)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "# branch.head "):
+			if head := strings.TrimPrefix(line, "# branch.head "); head != "(detached)" {
+				s.branch = head
+			}
+		case strings.HasPrefix(line, "# branch.ab "):
+			if _, err := fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &s.ahead, &s.behind); err != nil {
+				return nil, fmt.Errorf("parse status: %q: %w", line, err)
+			}
+			s.hasUpstream = true
+		case strings.HasPrefix(line, "#"):
+		default:
+			s.dirty = true
+		}
+	}
+	return s, nil
+}
+
+// String formats the summary as tab-separated columns.
+func (s *repoSummary) String() string {
+	sb := new(strings.Builder)
+	if s.branch == "" {
+		sb.WriteString("(detached)")
+	} else {
+		sb.WriteString(s.branch)
+	}
+	if s.dirty {
+		sb.WriteString("\tmodified")
+	} else {
+		sb.WriteString("\tclean")
+	}
+	switch {
+	case !s.hasUpstream:
+		sb.WriteString("\tno upstream")
+	case s.ahead == 0 && s.behind == 0:
+		sb.WriteString("\tup to date")
+	default:
+		fmt.Fprintf(sb, "\t%d ahead, %d behind", s.ahead, s.behind)
+	}
+	return sb.String()
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestRepos(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "api"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("api/.dummy", "changed\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.FromSlash("web"), "repos", "add", "web"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "repos", "add", "api", "api"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.gg(ctx, env.root.String(), "repos", "path", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), env.root.FromSlash("api")+"\n"; got != want {
+		t.Errorf("gg repos path api = %q; want %q", got, want)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "repos", "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	want := []*regexp.Regexp{
+		regexp.MustCompile(`^api\s+main\s+modified\s+no upstream$`),
+		regexp.MustCompile(`^web\s+main\s+clean\s+no upstream$`),
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("gg repos status output:\n%s\nwant %d lines", out, len(want))
+	}
+	for i, line := range lines {
+		if !want[i].MatchString(line) {
+			t.Errorf("gg repos status line %d = %q; want to match %v", i+1, line, want[i])
+		}
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "repos", "remove", "web"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, env.root.String(), "repos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "web") || !strings.Contains(string(out), "api") {
+		t.Errorf("gg repos after removing web =\n%s\nwant only api", out)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "repos", "path", "web"); err == nil {
+		t.Error("gg repos path web succeeded after removing it")
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"os"
 	"strings"
 	"text/template"
+	"time"
 	"unicode"
 
 	"gg-scm.io/pkg/git"
@@ -40,20 +41,42 @@ const requestPullSynopsis = "create a GitHub pull request"
 var requestPullEditorTemplate string
 
 func requestPull(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg requestpull [-n] [-e=0] [--title=MSG [--body=MSG]] [--draft] [-R user1[,user2]] [BRANCH]", requestPullSynopsis+`
+	if len(args) > 0 && args[0] == "comments" {
+		return prComments(ctx, cc, args[1:])
+	}
+	f := flag.NewFlagSet(true, "gg requestpull [-n] [-e=0] [--title=MSG [--body=MSG]] [--draft] [-R user1[,user2]] [--paths PATTERN [...]] [BRANCH]", requestPullSynopsis+`
 
 aliases: pr
 
 	Create a new GitHub pull request for the given branch (defaults to the
 	one currently checked out). The source will be inferred from the
-	branch's remote push information and the destination will be inferred
-	from upstream fetch information. This command does not push any new
-	commits; it just creates a pull request.
+	branch's remote push information and push name (see `+"`gg push --help`"+`)
+	and the destination will be inferred
+	from upstream fetch information. If the branch has no upstream, the
+	pull request targets the default branch of the repository. This
+	command does not push any new commits; it just creates a pull request.
+
+	`+"`gg requestpull comments`"+` shows the review comments on a pull
+	request instead. Run `+"`gg requestpull comments --help`"+` for details.
+	To create a pull request for a branch named "comments", pass
+	`+"`refs/heads/comments`"+`.
 
 	Before sending the pull request, gg will open an editor with a summary
 	of the commits it knows about. The first line will be the pull request
 	title, and any subsequent lines will be used as the body. You can exit
-	your editor without modifications to accept the default summary.
+	your editor without modifications to accept the default summary. If
+	the branch has a description (set with
+	`+"`git branch --edit-description`"+`), it is the default body instead
+	of the commit messages.
+
+	`+"`--paths`"+` limits the files that the branch may change, for
+	repositories where different teams own different directories. If the
+	branch changes any file that doesn't match one of the patterns, gg
+	prints a summary of those changes and doesn't create the pull request.
+	Otherwise, the patterns are listed at the end of the pull request body.
+	The patterns default to `+"`rootglob:`"+`, so `+"`--paths 'src/feature/**'`"+`
+	matches everything under src/feature. See `+"`gg status --help`"+` for the
+	other pattern kinds.
 
 	The first time you run requestpull, it will ask you to authorize access to
 	GitHub. A token will be saved to `+"`$XDG_CONFIG_HOME/gg/github_token`"+`
@@ -69,6 +92,7 @@ aliases: pr
 	reviewers := f.MultiString("R", "GitHub `user`names of reviewers to add")
 	f.Alias("R", "reviewer")
 	titleFlag := f.String("title", "", "pull request title")
+	paths := f.MultiString("paths", "require the branch to only change files matching the `pattern`")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
@@ -82,30 +106,21 @@ aliases: pr
 	if *bodyFlag != "" && *titleFlag == "" {
 		return usagef("cannot specify --body without specifying --title")
 	}
+	for _, arg := range *paths {
+		if _, err := parsePattern(arg, "rootglob"); err != nil {
+			return usagef("--paths: %v", err)
+		}
+	}
 	cfg, err := cc.git.ReadConfig(ctx)
 	if err != nil {
 		return err
 	}
 	var token []byte
 	if !*dryRun {
-		var err error
-		token, err = cc.xdgDirs.readConfig(gitHubTokenFilename)
-		if os.IsNotExist(err) {
-			newToken, err := gitHubDeviceFlow(ctx, cc.httpClient, firstTimeLogin, cc.stderr)
-			if err != nil {
-				return err
-			}
-			token = append([]byte(newToken), '\n')
-			if err := cc.xdgDirs.writeSecret(gitHubTokenFilename, token); err != nil {
-				fmt.Fprintln(cc.stderr, "gg is authorized, but failed to save the authorization:", err)
-				fmt.Fprintln(cc.stderr, "You will need to connect again the next time you run requestpull.")
-			} else {
-				fmt.Fprintln(cc.stderr, "Success! Your account will remembered in the future.")
-			}
-		} else if err != nil {
+		token, err = readGitHubToken(ctx, cc)
+		if err != nil {
 			return err
 		}
-		token = bytes.TrimSpace(token)
 	}
 
 	// Find local branch name.
@@ -141,6 +156,23 @@ aliases: pr
 		return fmt.Errorf("%s is not a GitHub repository", baseURL)
 	}
 	baseBranch := inferUpstream(cfg, branch).Branch()
+	baseRev := branch + "@{upstream}"
+	if cfg.Value("branch."+branch+".merge") == "" {
+		// No upstream: propose merging into the remote's default branch
+		// instead of a branch with the same name.
+		def, err := remoteDefaultBranch(ctx, cc.git, baseRemote)
+		if err != nil {
+			return err
+		}
+		if def == "" {
+			def, err = gitHubDefaultBranch(ctx, cc.httpClient, string(bytes.TrimSpace(token)), baseOwner, baseRepo)
+			if err != nil {
+				return err
+			}
+		}
+		baseBranch = def
+		baseRev = "refs/remotes/" + baseRemote + "/" + def
+	}
 
 	// Find head repository and ref.
 	headRemote, err := inferPushRepo(cfg, branch)
@@ -155,23 +187,41 @@ aliases: pr
 	if headOwner == "" {
 		return fmt.Errorf("%s is not a GitHub repository", headURL)
 	}
+	headBranch, err := remoteBranchName(cfg, cc.env, branch)
+	if err != nil {
+		return err
+	}
 
 	// Create pull request. Run message inference no matter what, since it
 	// has the side effect of detecting no change.
-	title, body, err := inferPullRequestMessage(ctx, cc.git, branch+"@{upstream}", branch)
+	title, body, err := inferPullRequestMessage(ctx, cc.git, baseRev, branch)
 	if err != nil {
 		return err
 	}
+	if desc := strings.TrimSpace(cfg.Value("branch." + branch + ".description")); desc != "" {
+		// A branch description says what the branch is for
+		// better than a list of its commits.
+		body = desc
+		if template := readPullRequestTemplate(ctx, cc.git); template != "" {
+			body += "\n\n" + strings.TrimSpace(template)
+		}
+	}
 	if *titleFlag != "" {
 		title, body = *titleFlag, *bodyFlag
 	}
+	if len(*paths) > 0 {
+		if err := checkPullRequestPaths(ctx, cc.git, baseRev, branch, *paths); err != nil {
+			return err
+		}
+		body = appendPathScope(body, *paths)
+	}
 	if *dryRun {
 		draftText := ""
 		if *draft {
 			draftText = "[DRAFT] "
 		}
 		_, err := fmt.Fprintf(cc.stdout, "%s%s/%s: %s\nMerge into %s:%s from %s:%s\n",
-			draftText, baseOwner, baseRepo, title, baseOwner, baseBranch, headOwner, branch)
+			draftText, baseOwner, baseRepo, title, baseOwner, baseBranch, headOwner, headBranch)
 		if err != nil {
 			return err
 		}
@@ -196,7 +246,7 @@ aliases: pr
 			"BaseRepo":   baseRepo,
 			"BaseBranch": baseBranch,
 			"HeadOwner":  headOwner,
-			"Branch":     branch,
+			"Branch":     headBranch,
 		})
 		if err != nil {
 			return err
@@ -216,7 +266,7 @@ aliases: pr
 		baseRepo:               baseRepo,
 		baseBranch:             baseBranch,
 		headOwner:              headOwner,
-		headBranch:             branch,
+		headBranch:             headBranch,
 		title:                  title,
 		body:                   body,
 		draft:                  *draft,
@@ -248,6 +298,130 @@ aliases: pr
 	return nil
 }
 
+// readGitHubToken returns the saved GitHub authorization token for the
+// repository's account, asking the user to authorize gg if there isn't
+// one. It reports which account it is acting as on stderr.
+func readGitHubToken(ctx context.Context, cc *cmdContext) ([]byte, error) {
+	name := selectedGitHubAccount(ctx, cc)
+	acct, err := readGitHubAccount(cc, name)
+	if os.IsNotExist(err) && name != defaultGitHubAccount {
+		return nil, fmt.Errorf("no GitHub account %q (log into it with 'gg github-login --account %s')", name, name)
+	}
+	if os.IsNotExist(err) {
+		newToken, err := gitHubDeviceFlow(ctx, cc.httpClient, firstTimeLogin, cc.stderr)
+		if err != nil {
+			return nil, err
+		}
+		acct = &gitHubAccount{name: name, token: newToken}
+		if info, err := checkGitHubToken(ctx, cc.httpClient, newToken); err == nil {
+			acct.login = info.login
+		}
+		if err := writeGitHubAccount(cc, acct); err != nil {
+			fmt.Fprintln(cc.stderr, "gg is authorized, but failed to save the authorization:", err)
+			fmt.Fprintln(cc.stderr, "You will need to connect again the next time you run requestpull.")
+		} else {
+			fmt.Fprintln(cc.stderr, "Success! Your account will remembered in the future.")
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if acct.login != "" || acct.name != defaultGitHubAccount {
+		fmt.Fprintf(cc.stderr, "gg: acting as GitHub %s\n", acct)
+	}
+	return []byte(acct.token), nil
+}
+
+// checkPullRequestPaths returns an error with a diffstat of the files
+// that head changes since its merge base with base that don't match any
+// of the given patterns.
+func checkPullRequestPaths(ctx context.Context, g *git.Git, base, head string, patterns []string) error {
+	diffRange := base + "..." + head
+	changed, err := diffFileNames(ctx, g, diffRange, nil)
+	if err != nil {
+		return err
+	}
+	var specs []git.Pathspec
+	for _, arg := range patterns {
+		pats, err := parsePattern(arg, "rootglob")
+		if err != nil {
+			return err
+		}
+		for _, pat := range pats {
+			if pat.kind != "re" {
+				specs = append(specs, pat.pathspec())
+				continue
+			}
+			matches, err := pat.expand(changed)
+			if err != nil {
+				return err
+			}
+			specs = append(specs, matches...)
+		}
+	}
+	inScope := make(map[git.TopPath]bool)
+	if len(specs) > 0 {
+		matched, err := diffFileNames(ctx, g, diffRange, specs)
+		if err != nil {
+			return err
+		}
+		for _, name := range matched {
+			inScope[name] = true
+		}
+	}
+	statArgs := []string{"diff", "--stat", "--no-renames", diffRange, "--"}
+	n := 0
+	for _, name := range changed {
+		if !inScope[name] {
+			statArgs = append(statArgs, string(git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true}, name.String())))
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	stat, err := g.Output(ctx, statArgs...)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%s changes %d file(s) outside --paths:\n%s", head, n, strings.TrimRight(stat, "\n"))
+}
+
+// diffFileNames returns the names of the files that differ in the given
+// range, limited to the files matching specs if any are given.
+func diffFileNames(ctx context.Context, g *git.Git, diffRange string, specs []git.Pathspec) ([]git.TopPath, error) {
+	args := []string{"diff", "--name-only", "-z", "--no-renames", diffRange, "--"}
+	for _, spec := range specs {
+		args = append(args, string(spec))
+	}
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list changed files: %w", err)
+	}
+	var names []git.TopPath
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			names = append(names, git.TopPath(name))
+		}
+	}
+	return names, nil
+}
+
+// appendPathScope adds the --paths patterns to a pull request body.
+func appendPathScope(body string, patterns []string) string {
+	sb := new(strings.Builder)
+	if body != "" {
+		sb.WriteString(body)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("This pull request only changes files matching:\n")
+	for _, pat := range patterns {
+		sb.WriteString("\n- `")
+		sb.WriteString(pat)
+		sb.WriteString("`")
+	}
+	return sb.String()
+}
+
 func inferPullRequestMessage(ctx context.Context, g *git.Git, base, head string) (title, body string, _ error) {
 	// Read commit messages of divergent commits.
 	commits, err := g.Log(ctx, git.LogOptions{
@@ -432,6 +606,40 @@ func createPullRequest(ctx context.Context, client *http.Client, params pullRequ
 	return respDoc.Number, respDoc.HTMLURL, nil
 }
 
+// gitHubDefaultBranch asks GitHub for the default branch of a repository.
+// authToken may be empty for public repositories.
+func gitHubDefaultBranch(ctx context.Context, client *http.Client, authToken, owner, repo string) (string, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("get default branch of %s/%s: %w", owner, repo, err)
+	}
+	req.Header.Set("User-Agent", userAgentString())
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if authToken != "" {
+		req.Header.Set("Authorization", "token "+authToken)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("get default branch of %s/%s: %w", owner, repo, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err := parseGitHubErrorResponse(resp)
+		return "", fmt.Errorf("get default branch of %s/%s: %w", owner, repo, err)
+	}
+	var respDoc struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respDoc); err != nil {
+		return "", fmt.Errorf("get default branch of %s/%s: parsing response: %w", owner, repo, err)
+	}
+	if respDoc.DefaultBranch == "" {
+		return "", fmt.Errorf("get default branch of %s/%s: no default branch", owner, repo)
+	}
+	return respDoc.DefaultBranch, nil
+}
+
 type pullRequestReviewParams struct {
 	authToken string
 
@@ -454,14 +662,6 @@ func addPullRequestReviewers(ctx context.Context, client *http.Client, params pu
 
 	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/requested_reviewers",
 		url.PathEscape(params.owner), url.PathEscape(params.repo), params.prNum)
-	req, err := http.NewRequest("POST", apiURL, nil)
-	if err != nil {
-		return fmt.Errorf("add pull request reviewers to %s/%s/pulls/%d: %w", params.owner, params.repo, params.prNum, err)
-	}
-	req.Header.Set("User-Agent", userAgentString())
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("Authorization", "token "+params.authToken)
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	reqBody := map[string]interface{}{
 		"reviewers": params.users,
 	}
@@ -469,10 +669,16 @@ func addPullRequestReviewers(ctx context.Context, client *http.Client, params pu
 	if err != nil {
 		return fmt.Errorf("add pull request reviewers: %w", err)
 	}
-	req.Body = io.NopCloser(bytes.NewReader(reqBodyJSON))
-	req.Header.Set("Content-Length", fmt.Sprint(len(reqBodyJSON)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(reqBodyJSON))
+	if err != nil {
+		return fmt.Errorf("add pull request reviewers to %s/%s/pulls/%d: %w", params.owner, params.repo, params.prNum, err)
+	}
+	req.Header.Set("User-Agent", userAgentString())
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "token "+params.authToken)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
-	resp, err := client.Do(req.WithContext(ctx))
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("add pull request reviewers to %s/%s/pulls/%d: %w", params.owner, params.repo, params.prNum, err)
 	}
@@ -502,6 +708,20 @@ func inferUpstream(cfg *git.Config, localBranch string) git.Ref {
 const draftPRAPIAccept = "application/vnd.github.shadow-cat-preview+json"
 
 func parseGitHubErrorResponse(resp *http.Response) error {
+	if _, limited := gitHubRateLimited(resp, time.Now()); limited {
+		if rl := parseGitHubRateLimit(resp.Header); !rl.reset.IsZero() {
+			return fmt.Errorf("GitHub API rate limit exceeded; try again after %s", rl.reset.Local().Format("15:04"))
+		}
+		return errors.New("GitHub API rate limit exceeded; try again later")
+	}
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound {
+		granted := parseGitHubScopes(resp.Header.Get("X-OAuth-Scopes"))
+		accepted := parseGitHubScopes(resp.Header.Get("X-Accepted-OAuth-Scopes"))
+		if resp.Header.Get("X-OAuth-Scopes") != "" && len(accepted) > 0 && len(missingGitHubScopes(granted, accepted)) == len(accepted) {
+			return fmt.Errorf("GitHub API HTTP %s: token needs the %s scope (run gg github-login to authorize gg again)",
+				resp.Status, strings.Join(accepted, " or "))
+		}
+	}
 	t, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
 	if err != nil || t != "application/json" {
 		return fmt.Errorf("GitHub API HTTP %s", resp.Status)
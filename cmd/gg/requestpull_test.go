@@ -42,6 +42,8 @@ func TestRequestPull(t *testing.T) {
 		branch      string
 		upstreamURL string
 		forkURL     string
+		description string
+		pushName    string
 		args        []string
 
 		headOwner string
@@ -153,6 +155,28 @@ func TestRequestPull(t *testing.T) {
 			body:      "Commit description",
 			draft:     true,
 		},
+		{
+			name:        "BranchDescription",
+			branch:      "shared",
+			upstreamURL: "https://github.com/example/foo.git",
+			description: "What this branch is for\n",
+
+			headOwner: "example",
+			headRef:   "shared",
+			title:     "Commit title",
+			body:      "What this branch is for",
+		},
+		{
+			name:        "PushName",
+			branch:      "shared",
+			upstreamURL: "https://github.com/example/foo.git",
+			pushName:    "users/alice/shared",
+
+			headOwner: "example",
+			headRef:   "users/alice/shared",
+			title:     "Commit title",
+			body:      "Commit description",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -228,6 +252,16 @@ func TestRequestPull(t *testing.T) {
 			if err := localGit.CheckoutBranch(ctx, test.branch, git.CheckoutOptions{}); err != nil {
 				t.Fatal(err)
 			}
+			if test.description != "" {
+				if err := localGit.Run(ctx, "config", "branch."+test.branch+".description", test.description); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.pushName != "" {
+				if err := localGit.Run(ctx, "config", "branch."+test.branch+".ggPushName", test.pushName); err != nil {
+					t.Fatal(err)
+				}
+			}
 
 			args := append([]string{"requestpull", "--edit=0"}, test.args...)
 			if _, err := env.gg(ctx, localDir, args...); err != nil {
@@ -527,6 +561,66 @@ func TestRequestPull_Editor(t *testing.T) {
 	}
 }
 
+func TestRequestPull_Paths(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "origin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "origin", "local"); err != nil {
+		t.Fatal(err)
+	}
+	localDir := env.root.FromSlash("local")
+	localGit := env.git.WithDir(localDir)
+	if err := localGit.Run(ctx, "remote", "set-url", "origin", "https://github.com/example/foo.git"); err != nil {
+		t.Fatal(err)
+	}
+	if err := localGit.NewBranch(ctx, "feature", git.BranchOptions{StartPoint: "origin/main", Track: true, Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("local/src/feature/a.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "local/src/feature/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "local"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, localDir, "requestpull", "-n", "--paths", "src/feature/**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "This pull request only changes files matching:\n\n- `src/feature/**`\n"; !strings.HasSuffix(string(out), want) {
+		t.Errorf("gg requestpull -n --paths output:\n%s\nwant suffix:\n%s", out, want)
+	}
+
+	if err := env.root.Apply(filesystem.Write("local/other.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "local/other.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "local"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.gg(ctx, localDir, "requestpull", "-n", "--paths", "src/feature/**")
+	if err == nil {
+		t.Fatal("gg requestpull -n --paths did not return an error")
+	}
+	if !strings.Contains(err.Error(), "other.txt") || strings.Contains(err.Error(), "a.txt") {
+		t.Errorf("gg requestpull -n --paths error = %q; want only other.txt listed", err)
+	}
+	if _, err := env.gg(ctx, localDir, "requestpull", "-n", "--paths", "src/feature/**", "--paths", "other.txt"); err != nil {
+		t.Errorf("gg requestpull -n --paths src/feature/** --paths other.txt: %v", err)
+	}
+}
+
 func TestInferUpstream(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -0,0 +1,275 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const resolveSynopsis = "manage conflict resolutions"
+
+func resolve(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg resolve --forget FILE [...]\n"+
+		"gg resolve --take local|other|union FILE [...]\n"+
+		"gg resolve --re-merge [--conflict-style STYLE] FILE [...]\n"+
+		"gg resolve --manifest", resolveSynopsis+`
+
+	With `+"`--forget`"+`, drop the resolutions that Git recorded for the
+	given conflicted files, so that the conflicts can be resolved again.
+	This undoes a bad resolution that was reused by a merge or rebase.
+	Resolutions are only recorded if Git's rerere feature is enabled.
+	See `+"`gg config rerere`"+` and git-rerere(1) for details.
+
+	With `+"`--take`"+`, resolve the given conflicted files without opening an
+	editor and mark them as resolved. `+"`--take local`"+` keeps the version
+	from the working copy's parent and `+"`--take other`"+` keeps the version
+	being merged in. During a rebase, the local version is the one being
+	rebased onto. `+"`--take union`"+` keeps the lines from both versions,
+	local lines first, which suits files like changelogs where both sides
+	added entries. If the chosen side deleted the file, it is removed.
+
+	With `+"`--re-merge`"+`, merge the given files again and write the
+	conflict markers anew, discarding any edits. Use
+	`+"`--conflict-style`"+` to pick a different style of conflict markers
+	than the first merge used, such as diff3 to see the common ancestor.
+	This only works for files that have not been marked as resolved.
+
+	While a merge, rebase, or other operation is in progress, gg keeps a
+	manifest of it in `+"`.git/gg/conflicts.json`"+` for editor plugins,
+	rewriting it after each gg command that can create or clear conflicts
+	and removing it when the operation ends. The manifest is a JSON object with the `+"`operation`"+` kind and
+	its `+"`branch`"+`, `+"`onto`"+`, and `+"`current`"+` commits, the
+	conflicted `+"`files`"+` with the blob hashes of their `+"`base`"+`,
+	`+"`local`"+`, and `+"`other`"+` versions, and the files `+"`resolved`"+`
+	since it was first written. `+"`--manifest`"+` updates the manifest and
+	prints it.`)
+	forget := f.Bool("forget", false, "forget recorded resolutions for the given files")
+	take := f.String("take", "", "resolve the files by taking the `side`: local, other, or union")
+	reMerge := f.Bool("re-merge", false, "merge the files again, restoring the conflict markers")
+	manifest := f.Bool("manifest", false, "print the conflict manifest as JSON")
+	conflictStyle := addConflictStyleFlag(f)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	modes := 0
+	for _, b := range []bool{*forget, *take != "", *reMerge, *manifest} {
+		if b {
+			modes++
+		}
+	}
+	if modes == 0 {
+		return usagef("must pass one of --forget, --take, --re-merge, or --manifest")
+	}
+	if modes > 1 {
+		return usagef("can only pass one of --forget, --take, --re-merge, or --manifest")
+	}
+	if *conflictStyle != "" && !*reMerge {
+		return usagef("--conflict-style requires --re-merge")
+	}
+	if *manifest {
+		if f.NArg() > 0 {
+			return usagef("--manifest takes no files")
+		}
+		return printConflictManifest(ctx, cc)
+	}
+	if f.NArg() == 0 {
+		return usagef("must pass one or more files")
+	}
+	var pathspecs []git.Pathspec
+	for _, arg := range f.Args() {
+		pathspecs = append(pathspecs, git.LiteralPath(arg))
+	}
+	switch {
+	case *take != "":
+		return resolveTake(ctx, cc, *take, pathspecs)
+	case *reMerge:
+		cc, err := withConflictStyle(cc, *conflictStyle)
+		if err != nil {
+			return err
+		}
+		if _, err := unmergedFiles(ctx, cc.git, pathspecs); err != nil {
+			return err
+		}
+		checkoutArgs := []string{"checkout", "--merge", "--"}
+		for _, p := range pathspecs {
+			checkoutArgs = append(checkoutArgs, p.String())
+		}
+		return cc.git.Run(ctx, checkoutArgs...)
+	default:
+		forgetArgs := []string{"rerere", "forget", "--"}
+		for _, p := range pathspecs {
+			forgetArgs = append(forgetArgs, p.String())
+		}
+		return cc.interactiveGit(ctx, forgetArgs...)
+	}
+}
+
+// An unmergedFile is a file with conflicting versions in the index.
+// A zero hash means that the version does not exist: for example, the
+// file was added on both sides, so it has no base, or it was deleted
+// on one side.
+type unmergedFile struct {
+	name  git.TopPath
+	base  git.Hash // stage 1
+	local git.Hash // stage 2
+	other git.Hash // stage 3
+}
+
+// unmergedFiles returns the unmerged files that match the given pathspecs.
+// It returns an error if a pathspec does not match any unmerged file.
+func unmergedFiles(ctx context.Context, g *git.Git, pathspecs []git.Pathspec) ([]*unmergedFile, error) {
+	var files []*unmergedFile
+	for _, p := range pathspecs {
+		out, err := g.Output(ctx, "ls-files", "--unmerged", "--full-name", "-z", "--", p.String())
+		if err != nil {
+			return nil, err
+		}
+		if out == "" {
+			return nil, fmt.Errorf("%s: no unmerged files", p)
+		}
+		byName := make(map[git.TopPath]*unmergedFile)
+		for _, ent := range strings.Split(strings.TrimSuffix(out, "\x00"), "\x00") {
+			// Each entry is "MODE HASH STAGE\tPATH".
+			info, name, ok := strings.Cut(ent, "\t")
+			fields := strings.Fields(info)
+			if !ok || len(fields) != 3 {
+				return nil, fmt.Errorf("parse ls-files output: %q", ent)
+			}
+			h, err := git.ParseHash(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("parse ls-files output: %w", err)
+			}
+			f := byName[git.TopPath(name)]
+			if f == nil {
+				f = &unmergedFile{name: git.TopPath(name)}
+				byName[f.name] = f
+				files = append(files, f)
+			}
+			switch fields[2] {
+			case "1":
+				f.base = h
+			case "2":
+				f.local = h
+			case "3":
+				f.other = h
+			default:
+				return nil, fmt.Errorf("parse ls-files output: unknown stage %q", fields[2])
+			}
+		}
+	}
+	return files, nil
+}
+
+// resolveTake resolves the unmerged files matching the given pathspecs
+// by taking one side of the conflict.
+func resolveTake(ctx context.Context, cc *cmdContext, side string, pathspecs []git.Pathspec) error {
+	if side != "local" && side != "other" && side != "union" {
+		return usagef("unknown --take side %q (must be local, other, or union)", side)
+	}
+	files, err := unmergedFiles(ctx, cc.git, pathspecs)
+	if err != nil {
+		return err
+	}
+	workTree, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := takeSide(ctx, cc.git, workTree, f, side); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+func takeSide(ctx context.Context, g *git.Git, workTree string, f *unmergedFile, side string) error {
+	top := ":(top,literal)" + f.name.String()
+	var zero git.Hash
+	switch {
+	case side == "local" && f.local == zero, side == "other" && f.other == zero:
+		return g.Run(ctx, "rm", "--quiet", "--force", "--", top)
+	case side == "local":
+		if err := g.Run(ctx, "checkout", "--ours", "--", top); err != nil {
+			return err
+		}
+	case side == "other":
+		if err := g.Run(ctx, "checkout", "--theirs", "--", top); err != nil {
+			return err
+		}
+	default:
+		if f.local == zero || f.other == zero {
+			return errors.New("cannot take union: file was deleted on one side")
+		}
+		merged, err := unionMerge(ctx, g, f)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.name.FromSlash(workTree), merged, 0o666); err != nil {
+			return err
+		}
+	}
+	return g.Run(ctx, "add", "--", top)
+}
+
+// unionMerge returns the result of merging the file's versions and
+// keeping the lines from both sides of each conflict.
+func unionMerge(ctx context.Context, g *git.Git, f *unmergedFile) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "gg-resolve")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	versions := []struct {
+		name string
+		hash git.Hash
+	}{
+		{"local", f.local},
+		{"base", f.base},
+		{"other", f.other},
+	}
+	var paths []string
+	for _, v := range versions {
+		var content string
+		if v.hash != (git.Hash{}) {
+			content, err = g.Output(ctx, "cat-file", "blob", v.hash.String())
+			if err != nil {
+				return nil, err
+			}
+		}
+		path := filepath.Join(dir, v.name)
+		if err := os.WriteFile(path, []byte(content), 0o666); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	out, err := g.Output(ctx, append([]string{"merge-file", "--stdout", "--union"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolve_Take(t *testing.T) {
+	tests := []struct {
+		side string
+		want string
+	}{
+		{side: "local", want: "boring text\n"},
+		{side: "other", want: "feature content\n"},
+		{side: "union", want: "boring text\nfeature content\n"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.side, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			env, err := newTestEnv(ctx, t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := setupMergeConflict(ctx, env); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := env.gg(ctx, env.root.String(), "merge", "feature"); err == nil {
+				t.Fatal("merge did not return error")
+			}
+
+			if _, err := env.gg(ctx, env.root.String(), "resolve", "--take", test.side, "foo.txt"); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := env.root.ReadFile("foo.txt"); err != nil {
+				t.Fatal(err)
+			} else if got != test.want {
+				t.Errorf("foo.txt = %q; want %q", got, test.want)
+			}
+			st, err := env.git.Status(ctx, git.StatusOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ent := range st {
+				if ent.Code.IsUnmerged() {
+					t.Errorf("%s still unmerged after resolve --take", ent.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestResolve_ReMerge(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupMergeConflict(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "merge", "--conflict-style=merge", "feature"); err == nil {
+		t.Fatal("merge did not return error")
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Fatal(err)
+	} else if strings.Contains(got, "|||||||") {
+		t.Fatalf("foo.txt after merge = %q; want merge-style conflict markers", got)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "resolve", "--re-merge", "--conflict-style=diff3", "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := env.root.ReadFile("foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "|||||||") || !strings.Contains(got, "In the beginning...") {
+		t.Errorf("foo.txt after re-merge = %q; want diff3-style conflict markers", got)
+	}
+}
+
+func TestResolve_Usage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	tests := [][]string{
+		{"resolve", "foo.txt"},
+		{"resolve", "--forget", "--take=local", "foo.txt"},
+		{"resolve", "--take=bogus", "foo.txt"},
+		{"resolve", "--conflict-style=diff3", "--take=local", "foo.txt"},
+		{"resolve", "--take=local"},
+	}
+	for _, args := range tests {
+		if _, err := env.gg(ctx, env.root.String(), args...); err == nil {
+			t.Errorf("gg %s did not return an error", strings.Join(args, " "))
+		} else if !isUsage(err) {
+			t.Errorf("gg %s error = %v; want usage", strings.Join(args, " "), err)
+		}
+	}
+}
+
+func TestResolve_Manifest(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupMergeConflict(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "resolve", "--manifest"); err == nil {
+		t.Error("resolve --manifest succeeded with no operation in progress")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "merge", "feature"); err == nil {
+		t.Fatal("merge did not return error")
+	}
+	manifestPath := filepath.Join(env.root.String(), ".git", filepath.FromSlash(conflictManifestPath))
+	readManifest := func() *conflictManifest {
+		t.Helper()
+		data, err := os.ReadFile(manifestPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := new(conflictManifest)
+		if err := json.Unmarshal(data, m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	m := readManifest()
+	if m.Operation != "merge" || len(m.Files) != 1 || m.Files[0].Path != "foo.txt" ||
+		m.Files[0].Local == "" || m.Files[0].Other == "" || len(m.Resolved) != 0 {
+		t.Errorf("manifest after merge = %+v; want merge with foo.txt conflicted", m)
+	}
+
+	// Commands that can't change conflicts leave the manifest alone.
+	if err := os.Remove(manifestPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "status"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(manifestPath); !os.IsNotExist(err) {
+		t.Errorf("status wrote the manifest (err = %v)", err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "resolve", "--manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	printed := new(conflictManifest)
+	if err := json.Unmarshal(out, printed); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	if diff := cmp.Diff(m, printed); diff != "" {
+		t.Errorf("resolve --manifest (-file +printed):\n%s", diff)
+	}
+
+	if err := env.root.Apply(filesystem.Write("foo.txt", "resolved\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "add", "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	m = readManifest()
+	if len(m.Files) != 0 || len(m.Resolved) != 1 || m.Resolved[0] != "foo.txt" {
+		t.Errorf("manifest after add = %+v; want foo.txt resolved", m)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "Merge feature"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(manifestPath); !os.IsNotExist(err) {
+		t.Errorf("manifest still exists after committing the merge (err = %v)", err)
+	}
+}
+
+func TestResolve_ManifestAfterGit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupMergeConflict(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+	// A merge run through gg git stops with the same conflicts as
+	// gg merge, so it must write the manifest too.
+	if _, err := env.gg(ctx, env.root.String(), "git", "merge", "feature"); err == nil {
+		t.Fatal("gg git merge did not return error")
+	}
+	manifestPath := filepath.Join(env.root.String(), ".git", filepath.FromSlash(conflictManifestPath))
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(conflictManifest)
+	if err := json.Unmarshal(data, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Operation != "merge" || len(m.Files) != 1 || m.Files[0].Path != "foo.txt" {
+		t.Errorf("manifest after gg git merge = %+v; want merge with foo.txt conflicted", m)
+	}
+}
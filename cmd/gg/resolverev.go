@@ -0,0 +1,156 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const resolveRevSynopsis = "print the commit hashes that revisions refer to"
+
+func resolveRev(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg resolve-rev [--json] EXPR [...]", resolveRevSynopsis+`
+
+	Prints the full hash of the commit that each EXPR refers to, one per
+	line, in the order given. An EXPR that names a range of commits, like
+	`+"`main..HEAD`"+`, prints every commit in the range, newest first.
+
+	Revisions are resolved the same way other gg commands resolve them.
+	In particular, `+"`@{upstream}`"+` (or `+"`@{u}`"+`) falls back to the
+	default branch of the current branch's remote when the current branch
+	has no upstream, as in `+"`gg rebase`"+`.
+
+	With `+"`--json`"+`, gg prints a JSON array with an object for each
+	commit instead, giving the expression it came from and, for a single
+	revision that names a ref, the full ref name.`)
+	jsonOutput := f.Bool("json", false, "print a JSON array instead of one hash per line")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() == 0 {
+		return usagef("must pass at least one revision")
+	}
+	var resolved []*resolvedRev
+	for _, expr := range f.Args() {
+		if strings.HasPrefix(expr, "-") {
+			return usagef("revision %q cannot start with '-'", expr)
+		}
+		revs, err := resolveRevExpr(ctx, cc.git, expr)
+		if err != nil {
+			return err
+		}
+		resolved = append(resolved, revs...)
+	}
+	if *jsonOutput {
+		if resolved == nil {
+			resolved = []*resolvedRev{}
+		}
+		enc := json.NewEncoder(cc.stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(resolved)
+	}
+	out := new(strings.Builder)
+	for _, r := range resolved {
+		out.WriteString(r.Commit)
+		out.WriteByte('\n')
+	}
+	_, err := fmt.Fprint(cc.stdout, out.String())
+	return err
+}
+
+// A resolvedRev is a commit that an expression passed to resolve-rev
+// refers to.
+type resolvedRev struct {
+	Expr   string  `json:"expr"`
+	Commit string  `json:"commit"`
+	Ref    git.Ref `json:"ref,omitempty"`
+}
+
+// resolveRevExpr returns the commits that expr refers to.
+func resolveRevExpr(ctx context.Context, g *git.Git, expr string) ([]*resolvedRev, error) {
+	if a, b, ok := splitRevRange(expr); ok {
+		var err error
+		if a != "" {
+			if a, err = inferUpstreamRev(ctx, g, a); err != nil {
+				return nil, fmt.Errorf("resolve %s: %w", expr, err)
+			}
+		}
+		if b != "" {
+			if b, err = inferUpstreamRev(ctx, g, b); err != nil {
+				return nil, fmt.Errorf("resolve %s: %w", expr, err)
+			}
+		}
+		sep := ".."
+		if strings.Contains(expr, "...") {
+			sep = "..."
+		}
+		commits, err := revList(ctx, g, a+sep+b)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %s: %w", expr, err)
+		}
+		revs := make([]*resolvedRev, 0, len(commits))
+		for _, c := range commits {
+			revs = append(revs, &resolvedRev{Expr: expr, Commit: c})
+		}
+		return revs, nil
+	}
+	spec, err := inferUpstreamRev(ctx, g, expr)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", expr, err)
+	}
+	r, err := g.ParseRev(ctx, spec)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", expr, err)
+	}
+	return []*resolvedRev{{Expr: expr, Commit: r.Commit.String(), Ref: r.Ref}}, nil
+}
+
+// splitRevRange splits a revision range like "A..B" or "A...B" into its
+// endpoints. Either endpoint may be empty, meaning HEAD.
+func splitRevRange(expr string) (a, b string, ok bool) {
+	if a, b, ok := strings.Cut(expr, "..."); ok {
+		return a, b, true
+	}
+	return strings.Cut(expr, "..")
+}
+
+// inferUpstreamRev replaces a leading "@{upstream}" or "@{u}" in rev with
+// the default branch of the current branch's remote if the current
+// branch has no upstream.
+func inferUpstreamRev(ctx context.Context, g *git.Git, rev string) (string, error) {
+	for _, prefix := range []string{"@{upstream}", "@{u}"} {
+		rest, ok := strings.CutPrefix(rev, prefix)
+		if !ok {
+			continue
+		}
+		dst, err := defaultRebaseDest(ctx, g)
+		if err != nil {
+			return "", err
+		}
+		return dst + rest, nil
+	}
+	return rev, nil
+}
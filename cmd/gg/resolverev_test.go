@@ -0,0 +1,101 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolveRev(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	base, err := env.git.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "topic", git.BranchOptions{Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	c1, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("bar.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	c2, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "resolve-rev", "main", "HEAD~1", "main..topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := base.Commit.String() + "\n" +
+		c1.String() + "\n" +
+		c2.String() + "\n" +
+		c1.String() + "\n"
+	if got := string(out); got != want {
+		t.Errorf("gg resolve-rev output:\n%s\nwant:\n%s", got, want)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "resolve-rev", "--json", "HEAD", "main..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*resolvedRev
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal output: %v; output:\n%s", err, out)
+	}
+	wantJSON := []*resolvedRev{
+		{Expr: "HEAD", Commit: c2.String(), Ref: "refs/heads/topic"},
+		{Expr: "main..", Commit: c2.String()},
+		{Expr: "main..", Commit: c1.String()},
+	}
+	if diff := cmp.Diff(wantJSON, got); diff != "" {
+		t.Errorf("gg resolve-rev --json (-want +got):\n%s", diff)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "resolve-rev", "nonexistent"); err == nil {
+		t.Error("gg resolve-rev nonexistent succeeded")
+	} else if isUsage(err) {
+		t.Errorf("gg resolve-rev nonexistent returned usage error: %v", err)
+	}
+}
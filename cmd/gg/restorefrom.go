@@ -0,0 +1,228 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const restoreFromSynopsis = "copy files from a revision into the working copy"
+
+func restoreFrom(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg restore-from [-n] REV FILE [...]", restoreFromSynopsis+`
+
+	Copies the contents of the given files, or all the files in the given
+	directories, as of REV into the working copy. HEAD, the current
+	branch, and the index are not changed, so the copied files show up as
+	local changes. Unlike `+"`gg revert`"+`, files that do not exist in REV
+	are left alone and no backups are made.
+
+	Each file that is created or overwritten is listed. Files that
+	already have the same contents are skipped. `+"`--dry-run`"+` lists the
+	files without changing them.`+pathStyleHelp)
+	dryRun := f.Bool("n", false, "list the files that would be copied without copying them")
+	f.Alias("n", "dry-run")
+	pathStyle := new(pathStyleFlags)
+	pathStyle.addFlags(f)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() < 2 {
+		return usagef("must pass a revision and at least one file")
+	}
+	if strings.HasPrefix(f.Arg(0), "-") {
+		return usagef("revision cannot start with '-'")
+	}
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	pf, err := pathStyle.formatter(ctx, cc, cfg)
+	if err != nil {
+		return err
+	}
+	rev, err := cc.git.ParseRev(ctx, f.Arg(0))
+	if err != nil {
+		return err
+	}
+	top, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	files, err := restoreFromList(ctx, cc, top, f.Arg(0), rev.Commit, f.Args()[1:])
+	if err != nil {
+		return err
+	}
+	var toWrite []*restoreFile
+	for _, file := range files {
+		if file.kind != restoreUnchanged {
+			toWrite = append(toWrite, file)
+		}
+	}
+	if !*dryRun && len(toWrite) > 0 {
+		err := withTempIndex(ctx, cc, func(idxCC *cmdContext) error {
+			if err := idxCC.git.Run(ctx, "read-tree", rev.Commit.String()); err != nil {
+				return err
+			}
+			coArgs := []string{"checkout-index", "--force", "--"}
+			for _, file := range toWrite {
+				coArgs = append(coArgs, file.name.String())
+			}
+			return idxCC.git.WithDir(top).Run(ctx, coArgs...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, file := range toWrite {
+		verb := string(file.kind)
+		if *dryRun {
+			verb = "would " + restoreDryRunVerbs[file.kind]
+		}
+		if _, err := fmt.Fprintf(cc.stdout, "%s %s\n", verb, pf.format(file.name)); err != nil {
+			return err
+		}
+	}
+	if len(toWrite) == 0 {
+		_, err := fmt.Fprintf(cc.stdout, "files already match %s\n", f.Arg(0))
+		return err
+	}
+	return nil
+}
+
+// restoreKind is what gg restore-from does to a file.
+type restoreKind string
+
+const (
+	restoreCreated     restoreKind = "created"
+	restoreOverwritten restoreKind = "overwrote"
+	restoreUnchanged   restoreKind = "unchanged"
+)
+
+// restoreDryRunVerbs is how gg restore-from --dry-run describes each
+// kind of change.
+var restoreDryRunVerbs = map[restoreKind]string{
+	restoreCreated:     "create",
+	restoreOverwritten: "overwrite",
+}
+
+// A restoreFile is a file that gg restore-from copies.
+type restoreFile struct {
+	name git.TopPath
+	kind restoreKind
+}
+
+// restoreFromList returns the files in commit (named revName) that match
+// the paths (which are relative to the current directory) and what
+// restoring each would do to the working copy. It returns an error if a
+// path does not match any file.
+func restoreFromList(ctx context.Context, cc *cmdContext, top, revName string, commit git.Hash, paths []string) ([]*restoreFile, error) {
+	lsArgs := []string{"ls-tree", "-r", "-z", "--full-name", commit.String(), "--"}
+	var prefixes []string
+	for _, p := range paths {
+		name, err := worktreeRelativePath(cc, top, p)
+		if err != nil && filepath.Clean(cc.abs(p)) != filepath.Clean(top) {
+			return nil, err
+		}
+		prefixes = append(prefixes, name.String())
+		lsArgs = append(lsArgs, p)
+	}
+	out, err := cc.git.Output(ctx, lsArgs...)
+	if err != nil {
+		return nil, err
+	}
+	var files []*restoreFile
+	matched := make([]bool, len(paths))
+	var hashes map[git.TopPath]string
+	for _, ent := range strings.Split(strings.TrimSuffix(out, "\x00"), "\x00") {
+		// Entries are of the form "<mode> SP <type> SP <object> TAB <file>".
+		info, name, ok := strings.Cut(ent, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(info)
+		if len(fields) != 3 {
+			continue
+		}
+		for i, prefix := range prefixes {
+			if prefix == "" || name == prefix || strings.HasPrefix(name, prefix+"/") {
+				matched[i] = true
+			}
+		}
+		if fields[1] != "blob" {
+			fmt.Fprintf(cc.stderr, "gg: restore-from: skipping submodule %s\n", name)
+			continue
+		}
+		file := &restoreFile{name: git.TopPath(name), kind: restoreOverwritten}
+		switch st, err := os.Lstat(filepath.Join(top, filepath.FromSlash(name))); {
+		case errors.Is(err, fs.ErrNotExist):
+			file.kind = restoreCreated
+		case err != nil:
+			return nil, err
+		case st.Mode().IsRegular() && fields[0] != "120000":
+			if hashes == nil {
+				hashes = make(map[git.TopPath]string)
+			}
+			hashes[file.name] = fields[2]
+		}
+		files = append(files, file)
+	}
+	for i, ok := range matched {
+		if !ok {
+			return nil, fmt.Errorf("%s: no such file in %s", paths[i], revName)
+		}
+	}
+	if len(hashes) > 0 {
+		// Skip files that already have the same contents.
+		hashArgs := []string{"hash-object", "--"}
+		var names []git.TopPath
+		for name := range hashes {
+			names = append(names, name)
+			hashArgs = append(hashArgs, name.String())
+		}
+		out, err := cc.git.WithDir(top).Output(ctx, hashArgs...)
+		if err != nil {
+			return nil, err
+		}
+		got := strings.Fields(out)
+		if len(got) != len(names) {
+			return nil, fmt.Errorf("git hash-object: got %d hashes for %d files", len(got), len(names))
+		}
+		same := make(map[git.TopPath]bool)
+		for i, name := range names {
+			same[name] = got[i] == hashes[name]
+		}
+		for _, file := range files {
+			if same[file.name] {
+				file.kind = restoreUnchanged
+			}
+		}
+	}
+	return files, nil
+}
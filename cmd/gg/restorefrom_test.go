@@ -0,0 +1,114 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestRestoreFrom(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(
+		filesystem.Write("dir/a.txt", "old a\n"),
+		filesystem.Write("dir/b.txt", "old b\n"),
+		filesystem.Write("same.txt", "same\n"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "dir/a.txt", "dir/b.txt", "same.txt"); err != nil {
+		t.Fatal(err)
+	}
+	old, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "rm", "dir/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(
+		filesystem.Write("dir/a.txt", "new a\n"),
+		filesystem.Write("dir/c.txt", "new c\n"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "dir/a.txt", "dir/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "restore-from", "--dry-run", old.String(), "dir", "same.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const wantDryRun = "would overwrite dir/a.txt\nwould create dir/b.txt\n"
+	if string(out) != wantDryRun {
+		t.Errorf("gg restore-from --dry-run =\n%s\nwant:\n%s", out, wantDryRun)
+	}
+	if got, err := env.root.ReadFile("dir/a.txt"); err != nil {
+		t.Fatal(err)
+	} else if got != "new a\n" {
+		t.Errorf("dir/a.txt after dry run = %q; want %q", got, "new a\n")
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "restore-from", old.String(), "dir", "same.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "overwrote dir/a.txt\ncreated dir/b.txt\n"
+	if string(out) != want {
+		t.Errorf("gg restore-from =\n%s\nwant:\n%s", out, want)
+	}
+	for path, want := range map[string]string{
+		"dir/a.txt": "old a\n",
+		"dir/b.txt": "old b\n",
+		"dir/c.txt": "new c\n",
+	} {
+		if got, err := env.root.ReadFile(path); err != nil {
+			t.Error(err)
+		} else if got != want {
+			t.Errorf("%s = %q; want %q", path, got, want)
+		}
+	}
+	if r, err := env.git.ParseRev(ctx, "HEAD"); err != nil {
+		t.Fatal(err)
+	} else if r.Commit != head {
+		t.Errorf("HEAD = %v; want %v", r.Commit, head)
+	}
+	if got, err := env.git.Output(ctx, "diff", "--cached", "--name-only"); err != nil {
+		t.Fatal(err)
+	} else if got != "" {
+		t.Errorf("index changed:\n%s", got)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "restore-from", old.String(), "nope.txt"); err == nil {
+		t.Error("gg restore-from with a missing file did not return an error")
+	}
+}
@@ -28,13 +28,17 @@ import (
 const revertSynopsis = "restore files to their checkout state"
 
 func revert(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg revert [-r REV] [--all] [--no-backup] [FILE [...]]", revertSynopsis+`
+	f := flag.NewFlagSet(true, "gg revert [-r REV] [--all] [--no-backup] [-I PATTERN] [-X PATTERN] [FILE [...]]", revertSynopsis+`
 
 	With no revision specified, revert the specified files or directories
 	to the contents they had at HEAD.
 	
-	Modified files are saved with a .orig suffix before reverting. To
-	disable these backups, use `+"`--no-backup`.")
+	Modified files are saved with a .orig suffix before reverting. With
+	`+"`--all`"+` and no files, they are saved in a backup under
+	`+"`.git/gg/backups`"+` instead, which `+"`gg backups restore`"+` copies
+	back. To disable these backups, use `+"`--no-backup`."+patternHelp)
+	pats := new(patternSet)
+	pats.addFlags(f)
 	all := f.Bool("all", false, "revert all changes when no arguments given")
 	noBackups := f.Bool("C", false, "do not save backup copies of files")
 	f.Alias("C", "no-backup")
@@ -45,9 +49,14 @@ func revert(ctx context.Context, cc *cmdContext, args []string) error {
 	} else if err != nil {
 		return usagef("%v", err)
 	}
-	if f.NArg() == 0 && !*all {
+	pats.args = f.Args()
+	if !pats.hasIncludes() && !*all {
 		return usagef("no arguments given.  Use -all to revert entire repository.")
 	}
+	pathspecs, err := pats.pathspecs(ctx, cc.git)
+	if err != nil {
+		return err
+	}
 
 	revObj, err := cc.git.ParseRev(ctx, *rev)
 	if err != nil {
@@ -55,10 +64,15 @@ func revert(ctx context.Context, cc *cmdContext, args []string) error {
 			// If HEAD fails to parse (empty repo), then just use reset.
 			rmArgs := []string{"reset", "--"}
 			for _, f := range f.Args() {
+				if hasPatternKind(f) {
+					continue
+				}
 				if _, err := os.Stat(cc.abs(f)); err != nil {
 					return err
 				}
-				rmArgs = append(rmArgs, git.LiteralPath(f).String())
+			}
+			for _, p := range pathspecs {
+				rmArgs = append(rmArgs, p.String())
 			}
 			return cc.git.Run(ctx, rmArgs...)
 		}
@@ -68,6 +82,9 @@ func revert(ctx context.Context, cc *cmdContext, args []string) error {
 	// Check whether files are known to Git or exist in the working tree.
 	var unknowns []int
 	for i, arg := range f.Args() {
+		if hasPatternKind(arg) {
+			continue
+		}
 		if _, err := os.Stat(cc.abs(arg)); err != nil {
 			unknowns = append(unknowns, i)
 		}
@@ -103,10 +120,6 @@ func revert(ctx context.Context, cc *cmdContext, args []string) error {
 
 	// Find the list of files that have changed between the revision and
 	// the working tree.
-	var pathspecs []git.Pathspec
-	for _, f := range f.Args() {
-		pathspecs = append(pathspecs, git.LiteralPath(f))
-	}
 	st, err := cc.git.DiffStatus(ctx, git.DiffStatusOptions{
 		Commit1:        revObj.Commit.String(),
 		Pathspecs:      pathspecs,
@@ -131,7 +144,13 @@ func revert(ctx context.Context, cc *cmdContext, args []string) error {
 
 	// Find the list of files that need to be backed up: these are
 	// modified locally beyond what's in HEAD.
-	if !*noBackups {
+	if !*noBackups && *all && !pats.hasIncludes() {
+		if len(mods) > 0 {
+			if err := backupBeforeDiscard(ctx, cc, "revert --all", mods); err != nil {
+				return err
+			}
+		}
+	} else if !*noBackups {
 		if err := backupForRevert(ctx, cc, mods); err != nil {
 			return err
 		}
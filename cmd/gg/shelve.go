@@ -0,0 +1,338 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	shelveSynopsis   = "set aside changes in the working copy"
+	unshelveSynopsis = "restore changes set aside by shelve"
+)
+
+// shelfMessagePrefix starts the message of the stash entries that gg
+// shelve creates. The shelf's name follows it.
+const shelfMessagePrefix = "gg shelf "
+
+func shelve(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg shelve [-n NAME] [-m MSG] [-k] [-u] [FILE [...]]\n"+
+		"gg shelve --list\n"+
+		"gg shelve -d NAME [...]", shelveSynopsis+`
+
+	Saves the uncommitted changes to the named files, or all files if
+	none are given, as a shelf and reverts them in the working copy, as
+	in Mercurial. `+"`gg unshelve`"+` brings them back, on the same branch
+	or another.
+
+	Shelves are Git stash entries, so they also show up in
+	`+"`git stash list`"+`. A shelf is named after the current branch unless
+	`+"`--name`"+` is given, with a number added if the name is taken.
+	Names follow the same rules as branch names.
+
+	`+"`--list`"+` shows the shelves, newest first, with the branch each was
+	created on. Stash entries made without gg are listed by their stash
+	names, like `+"`stash@{1}`"+`, which can be used wherever a shelf name is
+	expected.`+patternHelp)
+	name := f.String("n", "", "shelve under the given `name`")
+	f.Alias("n", "name")
+	msg := f.String("m", "", "describe the shelf with the given `message`")
+	keep := f.Bool("k", false, "shelve the changes but leave them in the working copy")
+	f.Alias("k", "keep")
+	unknown := f.Bool("u", false, "also shelve untracked files")
+	f.Alias("u", "unknown")
+	list := f.Bool("l", false, "list shelves")
+	f.Alias("l", "list")
+	del := f.Bool("d", false, "delete the named shelves")
+	f.Alias("d", "delete")
+	pats := new(patternSet)
+	pats.addFlags(f)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	pats.args = f.Args()
+	creating := *name != "" || *msg != "" || *keep || *unknown
+	switch {
+	case *list && *del:
+		return usagef("can't pass both --list and --delete")
+	case *list:
+		if creating || !pats.isEmpty() {
+			return usagef("--list takes no other arguments")
+		}
+		return listShelves(ctx, cc)
+	case *del:
+		if creating || len(pats.includes)+len(pats.excludes) > 0 {
+			return usagef("--delete takes only shelf names")
+		}
+		if f.NArg() == 0 {
+			return usagef("must pass shelf names to delete")
+		}
+		return deleteShelves(ctx, cc.git, f.Args())
+	}
+
+	shelves, err := readShelves(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		*name = defaultShelfName(currentBranch(ctx, cc), shelves)
+	} else if problem := branchNameProblem(*name); problem != "" {
+		return fmt.Errorf("invalid shelf name %q: %s", *name, problem)
+	} else if findShelf(shelves, *name) != nil {
+		return fmt.Errorf("shelf %q already exists (delete it with 'gg shelve -d %s')", *name, *name)
+	}
+	pathspecs, err := pats.pathspecs(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	stashMsg := shelfMessagePrefix + *name
+	if *msg != "" {
+		stashMsg += ": " + *msg
+	}
+	oldTop, _ := cc.git.ParseRev(ctx, "refs/stash")
+	pushArgs := []string{"stash", "push", "--quiet", "--message=" + stashMsg}
+	if *unknown {
+		pushArgs = append(pushArgs, "--include-untracked")
+	}
+	pushArgs = append(pushArgs, "--")
+	for _, spec := range pathspecs {
+		pushArgs = append(pushArgs, spec.String())
+	}
+	if err := cc.git.Run(ctx, pushArgs...); err != nil {
+		return fmt.Errorf("shelve: %w", err)
+	}
+	newTop, err := cc.git.ParseRev(ctx, "refs/stash")
+	if err != nil || (oldTop != nil && newTop.Commit == oldTop.Commit) {
+		return errors.New("shelve: no changes to shelve")
+	}
+	if *keep {
+		// Put the changes back exactly as they were, staged or not.
+		if err := cc.git.Run(ctx, "stash", "apply", "--quiet", "--index", "stash@{0}"); err != nil {
+			return fmt.Errorf("shelve: restore working copy: %w (changes are saved in shelf %s)", err, *name)
+		}
+	}
+	_, err = fmt.Fprintf(cc.stderr, "gg: shelved as %s\n", *name)
+	return err
+}
+
+func unshelve(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg unshelve [-k] [NAME]", unshelveSynopsis+`
+
+	Applies the changes in the named shelf, or the newest shelf if none
+	is named, to the working copy and deletes the shelf. The changes can
+	be applied on a different branch from the one they were shelved on.
+
+	If the changes conflict with the working copy, the conflicts are
+	left in the files and the shelf is kept. Resolve the conflicts, then
+	delete the shelf with `+"`gg shelve -d NAME`"+`.`)
+	keep := f.Bool("k", false, "keep the shelf after applying it")
+	f.Alias("k", "keep")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 1 {
+		return usagef("can pass only one shelf")
+	}
+	shelves, err := readShelves(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if len(shelves) == 0 {
+		return errors.New("no shelves to restore")
+	}
+	s := shelves[0]
+	if f.NArg() == 1 {
+		s = findShelf(shelves, f.Arg(0))
+		if s == nil {
+			return fmt.Errorf("no shelf named %q", f.Arg(0))
+		}
+	}
+	op := "pop"
+	if *keep {
+		op = "apply"
+	}
+	if err := cc.interactiveGit(ctx, "stash", op, "--quiet", s.stashRef); err != nil {
+		if unmerged, uerr := unmergedFiles(ctx, cc.git, nil); uerr == nil && len(unmerged) > 0 {
+			return fmt.Errorf("unshelve %s: conflicts in %d files; resolve them, then run 'gg shelve -d %s'", s.name, len(unmerged), s.name)
+		}
+		return fmt.Errorf("unshelve %s: %w", s.name, err)
+	}
+	_, err = fmt.Fprintf(cc.stderr, "gg: unshelved %s\n", s.name)
+	return err
+}
+
+// A shelf is a stash entry as shown by gg shelve --list.
+type shelf struct {
+	stashRef string // like "stash@{0}"
+	index    int    // N in stash@{N}
+
+	// name is the name given to gg shelve or stashRef for stash entries
+	// that gg did not create.
+	name    string
+	branch  string // empty if HEAD was detached
+	message string
+	created time.Time
+}
+
+// readShelves returns the stash entries, newest first.
+func readShelves(ctx context.Context, g *git.Git) ([]*shelf, error) {
+	out, err := g.Output(ctx, "stash", "list", "--format=%gd%x00%ct%x00%gs")
+	if err != nil {
+		return nil, fmt.Errorf("list shelves: %w", err)
+	}
+	var shelves []*shelf
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		s, err := parseShelf(fields[0], fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("list shelves: %w", err)
+		}
+		if sec, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			s.created = time.Unix(sec, 0)
+		}
+		shelves = append(shelves, s)
+	}
+	return shelves, nil
+}
+
+// parseShelf parses a stash entry from its reflog selector, like
+// "stash@{0}", and its reflog subject, like "On main: gg shelf main".
+func parseShelf(stashRef, subject string) (*shelf, error) {
+	n, ok := strings.CutPrefix(stashRef, "stash@{")
+	n, ok2 := strings.CutSuffix(n, "}")
+	index, err := strconv.Atoi(n)
+	if !ok || !ok2 || err != nil {
+		return nil, fmt.Errorf("unexpected stash name %q", stashRef)
+	}
+	s := &shelf{stashRef: stashRef, index: index, name: stashRef}
+	// Git writes "On BRANCH: MESSAGE" for entries with a message
+	// and "WIP on BRANCH: HASH SUBJECT" for the rest.
+	rest := subject
+	if r, ok := strings.CutPrefix(rest, "WIP on "); ok {
+		rest = r
+	} else if r, ok := strings.CutPrefix(rest, "On "); ok {
+		rest = r
+	}
+	branch, desc, ok := strings.Cut(rest, ": ")
+	if !ok {
+		s.message = subject
+		return s, nil
+	}
+	if branch != "(no branch)" {
+		s.branch = branch
+	}
+	s.message = desc
+	if name, ok := strings.CutPrefix(desc, shelfMessagePrefix); ok {
+		s.name, s.message, _ = strings.Cut(name, ": ")
+	}
+	return s, nil
+}
+
+// findShelf returns the shelf with the given name or stash name or nil
+// if there is none.
+func findShelf(shelves []*shelf, name string) *shelf {
+	for _, s := range shelves {
+		if s.name == name || s.stashRef == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// defaultShelfName returns the name for a new shelf made on branch:
+// the branch name, or "default" if HEAD is detached, with a number
+// added if the name is already taken.
+func defaultShelfName(branch string, shelves []*shelf) string {
+	base := branch
+	if base == "" {
+		base = "default"
+	}
+	name := base
+	for i := 1; findShelf(shelves, name) != nil; i++ {
+		name = base + "-" + strconv.Itoa(i)
+	}
+	return name
+}
+
+func listShelves(ctx context.Context, cc *cmdContext) error {
+	shelves, err := readShelves(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, s := range shelves {
+		branch := s.branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		age := ""
+		if !s.created.IsZero() {
+			age = relativeTime(now, s.created)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.name, branch, age, s.message)
+	}
+	return tw.Flush()
+}
+
+func deleteShelves(ctx context.Context, g *git.Git, names []string) error {
+	shelves, err := readShelves(ctx, g)
+	if err != nil {
+		return err
+	}
+	var doomed []*shelf
+	for _, name := range names {
+		s := findShelf(shelves, name)
+		if s == nil {
+			return fmt.Errorf("no shelf named %q", name)
+		}
+		if !slices.Contains(doomed, s) {
+			doomed = append(doomed, s)
+		}
+	}
+	// Dropping a stash entry renumbers the older ones, so start with the
+	// oldest.
+	sort.Slice(doomed, func(i, j int) bool {
+		return doomed[i].index > doomed[j].index
+	})
+	for _, s := range doomed {
+		if err := g.Run(ctx, "stash", "drop", "--quiet", s.stashRef); err != nil {
+			return fmt.Errorf("delete shelf %s: %w", s.name, err)
+		}
+	}
+	return nil
+}
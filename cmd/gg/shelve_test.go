@@ -0,0 +1,155 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseShelf(t *testing.T) {
+	tests := []struct {
+		ref     string
+		subject string
+		want    shelf
+	}{
+		{
+			ref:     "stash@{0}",
+			subject: "On main: gg shelf main",
+			want:    shelf{stashRef: "stash@{0}", index: 0, name: "main", branch: "main"},
+		},
+		{
+			ref:     "stash@{1}",
+			subject: "On feature/x: gg shelf wip-1: half of the fix",
+			want:    shelf{stashRef: "stash@{1}", index: 1, name: "wip-1", branch: "feature/x", message: "half of the fix"},
+		},
+		{
+			ref:     "stash@{2}",
+			subject: "WIP on main: 1234567 Add a feature",
+			want:    shelf{stashRef: "stash@{2}", index: 2, name: "stash@{2}", branch: "main", message: "1234567 Add a feature"},
+		},
+		{
+			ref:     "stash@{3}",
+			subject: "On (no branch): gg shelf default",
+			want:    shelf{stashRef: "stash@{3}", index: 3, name: "default"},
+		},
+	}
+	for _, test := range tests {
+		got, err := parseShelf(test.ref, test.subject)
+		if err != nil {
+			t.Errorf("parseShelf(%q, %q): %v", test.ref, test.subject, err)
+			continue
+		}
+		if diff := cmp.Diff(test.want, *got, cmp.AllowUnexported(shelf{})); diff != "" {
+			t.Errorf("parseShelf(%q, %q) (-want +got):\n%s", test.ref, test.subject, diff)
+		}
+	}
+}
+
+func TestShelve(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "original\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", "changed\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "shelve", "-m", "try something"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Fatal(err)
+	} else if got != "original\n" {
+		t.Errorf("foo.txt after shelve = %q; want %q", got, "original\n")
+	}
+	out, err := env.gg(ctx, env.root.String(), "shelve", "--list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields := strings.Fields(string(out)); len(fields) < 2 || fields[0] != "main" || fields[1] != "main" || !strings.Contains(string(out), "try something") {
+		t.Errorf("gg shelve --list = %q; want shelf main from branch main with message", out)
+	}
+
+	// A second shelf from the same branch gets a new name.
+	if err := env.root.Apply(filesystem.Write("foo.txt", "other\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "shelve", "--keep"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Fatal(err)
+	} else if got != "other\n" {
+		t.Errorf("foo.txt after shelve --keep = %q; want %q", got, "other\n")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "shelve", "-d", "main-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "checkout", "--", "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "unshelve", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := env.root.ReadFile("foo.txt"); err != nil {
+		t.Fatal(err)
+	} else if got != "changed\n" {
+		t.Errorf("foo.txt after unshelve = %q; want %q", got, "changed\n")
+	}
+	out, err = env.gg(ctx, env.root.String(), "shelve", "--list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) > 0 {
+		t.Errorf("gg shelve --list after unshelve = %q; want empty", out)
+	}
+}
+
+func TestShelve_NoChanges(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "shelve"); err == nil {
+		t.Error("gg shelve with no changes succeeded")
+	}
+}
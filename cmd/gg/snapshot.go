@@ -0,0 +1,341 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const snapshotSynopsis = "save the working copy without committing"
+
+// snapshotRefPrefix is the prefix of the refs that hold snapshots.
+const snapshotRefPrefix = "refs/gg-snapshots/"
+
+func snapshot(ctx context.Context, cc *cmdContext, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "list":
+			return snapshotList(ctx, cc, args[1:])
+		case "restore":
+			return snapshotRestore(ctx, cc, args[1:])
+		case "diff":
+			return snapshotDiff(ctx, cc, args[1:])
+		}
+	}
+	f := flag.NewFlagSet(true, "gg snapshot [-m MSG] | list | restore [NAME] | diff [--stat] [NAME]", snapshotSynopsis+`
+
+	Saves the state of the working copy, including untracked files that
+	are not ignored, as a hidden commit under `+"`refs/gg-snapshots/`"+`.
+	The index, HEAD, and the working copy are not changed. The snapshot's
+	parent is the commit that was checked out, so the snapshot also keeps
+	the commits of a branch that is later rewritten. The name of the new
+	snapshot is printed.
+
+	`+"`gg snapshot list`"+` lists the snapshots, newest first.
+
+	`+"`gg snapshot restore`"+` writes the files in a snapshot (by default the
+	newest) back to the working copy. Files that are not in the snapshot
+	are left alone, and the index and HEAD are not changed. The working
+	copy is snapshotted first, so a restore can be undone.
+
+	`+"`gg snapshot diff`"+` shows the changes from a snapshot (by default
+	the newest) to the working copy.
+
+	If `+"`gg.snapshot.auto`"+` is true, `+"`gg rebase`"+`, `+"`gg histedit`"+`,
+	and `+"`gg evolve`"+` take a snapshot before they start. Snapshots
+	can be deleted with `+"`git update-ref -d`"+`.`)
+	msg := f.String("m", "", "snapshot `message`")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 0 {
+		return usagef("unknown subcommand %q", f.Arg(0))
+	}
+	name, err := takeSnapshot(ctx, cc, *msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cc.stdout, name)
+	return err
+}
+
+// autoSnapshot takes a snapshot before the named operation if
+// gg.snapshot.auto is set.
+func autoSnapshot(ctx context.Context, cc *cmdContext, op string) error {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if cfg.Value("gg.snapshot.auto") == "" {
+		return nil
+	}
+	auto, err := cfg.Bool("gg.snapshot.auto")
+	if err != nil || !auto {
+		return err
+	}
+	name, err := takeSnapshot(ctx, cc, "before "+op)
+	if err != nil {
+		return fmt.Errorf("snapshot before %s: %w", op, err)
+	}
+	fmt.Fprintf(cc.stderr, "gg: saved snapshot %s before %s\n", name, op)
+	return nil
+}
+
+// takeSnapshot saves the working copy as a new snapshot and returns its
+// name.
+func takeSnapshot(ctx context.Context, cc *cmdContext, msg string) (string, error) {
+	tree, err := workingCopyTree(ctx, cc)
+	if err != nil {
+		return "", err
+	}
+	if msg == "" {
+		msg = "snapshot"
+		if branch := currentBranch(ctx, cc); branch != "" {
+			msg += " of " + branch
+		}
+	}
+	commitArgs := []string{"commit-tree", tree, "-m", msg}
+	if head, err := cc.git.Head(ctx); err == nil {
+		commitArgs = append(commitArgs, "-p", head.Commit.String())
+	}
+	out, err := cc.git.Output(ctx, commitArgs...)
+	if err != nil {
+		return "", err
+	}
+	commit := strings.TrimSpace(out)
+	name := time.Now().UTC().Format("20060102T150405Z")
+	for i := 2; ; i++ {
+		if _, err := cc.git.ParseRev(ctx, snapshotRefPrefix+name); err != nil {
+			break
+		}
+		name = strings.TrimSuffix(name, "-"+strconv.Itoa(i-1)) + "-" + strconv.Itoa(i)
+	}
+	err = cc.git.MutateRefs(ctx, map[git.Ref]git.RefMutation{
+		git.Ref(snapshotRefPrefix + name): git.SetRef(commit),
+	})
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// workingCopyTree writes the working copy, including untracked files
+// that are not ignored, to a tree object using a temporary index and
+// returns the tree's hash.
+func workingCopyTree(ctx context.Context, cc *cmdContext) (string, error) {
+	var tree string
+	err := withTempIndex(ctx, cc, func(idxCC *cmdContext) error {
+		if err := idxCC.git.Run(ctx, "add", "--all"); err != nil {
+			return err
+		}
+		out, err := idxCC.git.Output(ctx, "write-tree")
+		tree = strings.TrimSpace(out)
+		return err
+	})
+	return tree, err
+}
+
+// withTempIndex calls f with a copy of cc whose Git subprocesses use a
+// temporary copy of the index.
+func withTempIndex(ctx context.Context, cc *cmdContext, f func(idxCC *cmdContext) error) error {
+	gitDir, err := cc.git.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	tempIndex, err := os.CreateTemp(cc.tempDir, "gg-index-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempIndex.Name())
+	index, err := os.Open(filepath.Join(gitDir, "index"))
+	if err == nil {
+		_, err = io.Copy(tempIndex, index)
+		index.Close()
+	} else if errors.Is(err, os.ErrNotExist) {
+		// A new repository has no index yet.
+		err = nil
+	}
+	if closeErr := tempIndex.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(tempIndex.Name()); err == nil && info.Size() == 0 {
+		// Git rejects an empty index file, but creates one if it is missing.
+		os.Remove(tempIndex.Name())
+	}
+	idxCC, err := cc.withGitEnv("GIT_INDEX_FILE=" + tempIndex.Name())
+	if err != nil {
+		return err
+	}
+	return f(idxCC)
+}
+
+// resolveSnapshot returns the full ref of the named snapshot or the
+// newest snapshot if name is empty.
+func resolveSnapshot(ctx context.Context, g *git.Git, name string) (git.Ref, error) {
+	if name != "" {
+		ref := git.Ref(snapshotRefPrefix + strings.TrimPrefix(name, snapshotRefPrefix))
+		if _, err := g.ParseRev(ctx, ref.String()); err != nil {
+			return "", fmt.Errorf("no snapshot named %q", name)
+		}
+		return ref, nil
+	}
+	snapshots, err := listSnapshots(ctx, g)
+	if err != nil {
+		return "", err
+	}
+	if len(snapshots) == 0 {
+		return "", errors.New("no snapshots")
+	}
+	return git.Ref(snapshotRefPrefix + snapshots[0].name), nil
+}
+
+// A snapshotInfo describes a snapshot for gg snapshot list.
+type snapshotInfo struct {
+	name    string
+	commit  git.Hash
+	time    time.Time
+	message string
+}
+
+// listSnapshots returns the snapshots, newest first.
+func listSnapshots(ctx context.Context, g *git.Git) ([]*snapshotInfo, error) {
+	out, err := g.Output(ctx, "for-each-ref", "--sort=-refname",
+		"--format=%(refname)%00%(objectname)%00%(creatordate:unix)%00%(subject)", snapshotRefPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*snapshotInfo
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		hash, err := git.ParseHash(fields[1])
+		if err != nil {
+			continue
+		}
+		sec, _ := strconv.ParseInt(fields[2], 10, 64)
+		snapshots = append(snapshots, &snapshotInfo{
+			name:    strings.TrimPrefix(fields[0], snapshotRefPrefix),
+			commit:  hash,
+			time:    time.Unix(sec, 0),
+			message: fields[3],
+		})
+	}
+	return snapshots, nil
+}
+
+func snapshotList(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg snapshot list", "list snapshots, newest first")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 0 {
+		return usagef("snapshot list takes no arguments")
+	}
+	snapshots, err := listSnapshots(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	for _, s := range snapshots {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.name, relativeTime(now, s.time), s.message)
+	}
+	return tw.Flush()
+}
+
+func snapshotRestore(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg snapshot restore [NAME]", "write the files in a snapshot to the working copy")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 1 {
+		return usagef("snapshot restore takes at most one snapshot")
+	}
+	ref, err := resolveSnapshot(ctx, cc.git, f.Arg(0))
+	if err != nil {
+		return err
+	}
+	topDir, err := cc.git.WorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	backup, err := takeSnapshot(ctx, cc, "before restoring "+strings.TrimPrefix(ref.String(), snapshotRefPrefix))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cc.stderr, "gg: saved snapshot %s of the working copy\n", backup)
+	return withTempIndex(ctx, cc, func(idxCC *cmdContext) error {
+		if err := idxCC.git.Run(ctx, "read-tree", ref.String()+"^{tree}"); err != nil {
+			return err
+		}
+		return idxCC.git.WithDir(topDir).Run(ctx, "checkout-index", "--all", "--force")
+	})
+}
+
+func snapshotDiff(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg snapshot diff [--stat] [NAME]", "show changes from a snapshot to the working copy")
+	stat := f.Bool("stat", false, "output diffstat-style summary of changes")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 1 {
+		return usagef("snapshot diff takes at most one snapshot")
+	}
+	ref, err := resolveSnapshot(ctx, cc.git, f.Arg(0))
+	if err != nil {
+		return err
+	}
+	tree, err := workingCopyTree(ctx, cc)
+	if err != nil {
+		return err
+	}
+	diffArgs := []string{"diff"}
+	if *stat {
+		diffArgs = append(diffArgs, "--stat")
+	}
+	diffArgs = append(diffArgs, ref.String()+"^{tree}", tree, "--")
+	return cc.interactiveGit(ctx, diffArgs...)
+}
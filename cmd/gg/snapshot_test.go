@@ -0,0 +1,145 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(
+		filesystem.Write("tracked.txt", "original\n"),
+		filesystem.Write(".gitignore", "*.log\n"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "tracked.txt", ".gitignore"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(
+		filesystem.Write("tracked.txt", "modified\n"),
+		filesystem.Write("untracked.txt", "new file\n"),
+		filesystem.Write("ignored.log", "noise\n"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	statusBefore, err := env.git.Output(ctx, "status", "--porcelain")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "snapshot", "-m", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := strings.TrimSpace(string(out))
+	if name == "" {
+		t.Fatal("gg snapshot did not print a name")
+	}
+	ref := snapshotRefPrefix + name
+	if got, err := env.git.Output(ctx, "status", "--porcelain"); err != nil {
+		t.Fatal(err)
+	} else if got != statusBefore {
+		t.Errorf("status after snapshot =\n%s\nwant unchanged:\n%s", got, statusBefore)
+	}
+	if r, err := env.git.ParseRev(ctx, "HEAD"); err != nil {
+		t.Fatal(err)
+	} else if r.Commit != head {
+		t.Errorf("HEAD = %v; want %v", r.Commit, head)
+	}
+	for path, want := range map[string]string{"tracked.txt": "modified\n", "untracked.txt": "new file\n"} {
+		got, err := env.git.Output(ctx, "cat-file", "blob", ref+":"+path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s in snapshot = %q; want %q", path, got, want)
+		}
+	}
+	if _, err := env.git.Output(ctx, "cat-file", "-e", ref+":ignored.log"); err == nil {
+		t.Error("snapshot contains ignored file")
+	}
+	if parent, err := env.git.ParseRev(ctx, ref+"^"); err != nil {
+		t.Error(err)
+	} else if parent.Commit != head {
+		t.Errorf("snapshot parent = %v; want %v", parent.Commit, head)
+	}
+
+	// Change the working copy and then restore the snapshot.
+	if err := env.root.Apply(
+		filesystem.Write("tracked.txt", "oops\n"),
+		filesystem.Remove("untracked.txt"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, env.root.String(), "snapshot", "diff", "--stat", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); !strings.Contains(got, "tracked.txt") || !strings.Contains(got, "untracked.txt") {
+		t.Errorf("gg snapshot diff --stat =\n%s\nwant to mention tracked.txt and untracked.txt", got)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "snapshot", "restore", name); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{"tracked.txt": "modified\n", "untracked.txt": "new file\n"} {
+		got, err := env.root.ReadFile(path)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s after restore = %q; want %q", path, got, want)
+		}
+	}
+	if got, err := env.git.Output(ctx, "diff", "--cached", "--name-only"); err != nil {
+		t.Fatal(err)
+	} else if got != "" {
+		t.Errorf("index changed by restore:\n%s", got)
+	}
+
+	// The restore saved the working copy before overwriting it.
+	out, err = env.gg(ctx, env.root.String(), "snapshot", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("gg snapshot list =\n%s\nwant 2 snapshots", out)
+	}
+	if !strings.Contains(lines[0], "before restoring "+name) || !strings.Contains(lines[1], "checkpoint") {
+		t.Errorf("gg snapshot list =\n%s\nwant the restore backup, then %q", out, name)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const (
+	stageSynopsis   = "copy changes to the index"
+	unstageSynopsis = "remove changes from the index"
+)
+
+// indexHelp is the paragraph of help text shared by stage and unstage
+// that explains where they fit in.
+const indexHelp = `
+
+	Most gg commands act as if there is no index: ` + "`gg commit`" + ` commits
+	the files it is given straight from the working copy. ` + "`gg stage`" + `
+	and ` + "`gg unstage`" + ` are for the rare cases where fine control over
+	Git's index matters, such as preparing a commit for ` + "`git commit`" + `.
+	Use ` + "`gg status --staged`" + ` and ` + "`gg diff --staged`" + ` to see the index.`
+
+func stage(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg stage [-p] FILE [...]", stageSynopsis+`
+
+	Copies the working copy's version of each FILE into the index, like
+	`+"`git add`"+`. With `+"`-p`"+`, the changes to stage are chosen
+	interactively, hunk by hunk.`+indexHelp)
+	patch := f.Bool("p", false, "interactively choose hunks to stage")
+	f.Alias("p", "patch")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() == 0 {
+		return usagef("must pass one or more files to stage")
+	}
+	addArgs := []string{"add"}
+	if *patch {
+		addArgs = append(addArgs, "--patch")
+	}
+	addArgs = append(addArgs, "--")
+	for _, arg := range f.Args() {
+		addArgs = append(addArgs, git.LiteralPath(arg).String())
+	}
+	return cc.interactiveGit(ctx, addArgs...)
+}
+
+func unstage(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg unstage [FILE [...]]", unstageSynopsis+`
+
+	Resets the index entry of each FILE (or every file if none are given)
+	to its version in HEAD, leaving the working copy alone. Files that
+	are new in the index stay tracked, as if by `+"`gg add`"+`.`+indexHelp)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	pathspecs := make([]string, 0, f.NArg())
+	for _, arg := range f.Args() {
+		pathspecs = append(pathspecs, git.LiteralPath(arg).String())
+	}
+	if len(pathspecs) == 0 {
+		pathspecs = append(pathspecs, git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true}, "").String())
+	}
+
+	// Unstaging a file that isn't in HEAD removes it from the index, so
+	// note which files are tracked to mark them as tracked again.
+	before, err := listIndexFiles(ctx, cc.git, pathspecs)
+	if err != nil {
+		return err
+	}
+	if _, err := cc.git.Head(ctx); err == nil {
+		err = cc.git.Run(ctx, append([]string{"reset", "--quiet", git.Head.String(), "--"}, pathspecs...)...)
+		if err != nil {
+			return err
+		}
+	} else {
+		// No commits yet: every file in the index is new.
+		err := cc.git.Run(ctx, append([]string{"rm", "--cached", "--quiet", "-r", "--ignore-unmatch", "--"}, pathspecs...)...)
+		if err != nil {
+			return err
+		}
+	}
+	after, err := listIndexFiles(ctx, cc.git, pathspecs)
+	if err != nil {
+		return err
+	}
+	stillTracked := make(map[git.TopPath]bool, len(after))
+	for _, name := range after {
+		stillTracked[name] = true
+	}
+	var untracked []git.Pathspec
+	for _, name := range before {
+		if !stillTracked[name] {
+			untracked = append(untracked, name.Pathspec())
+		}
+	}
+	if len(untracked) == 0 {
+		return nil
+	}
+	return cc.git.Add(ctx, untracked, git.AddOptions{IntentToAdd: true})
+}
+
+// listIndexFiles returns the files in the index that match pathspecs.
+func listIndexFiles(ctx context.Context, g *git.Git, pathspecs []string) ([]git.TopPath, error) {
+	out, err := g.Output(ctx, append([]string{"ls-files", "-z", "--full-name", "--"}, pathspecs...)...)
+	if err != nil {
+		return nil, err
+	}
+	var names []git.TopPath
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			names = append(names, git.TopPath(name))
+		}
+	}
+	return names, nil
+}
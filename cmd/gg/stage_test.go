@@ -0,0 +1,124 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestStage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("foo.txt", "foo\n"),
+		filesystem.Write("bar.txt", "bar\n"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt", "bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("foo.txt", "staged\n"),
+		filesystem.Write("bar.txt", "unstaged\n"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "stage", "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.gg(ctx, env.root.String(), "status", "--staged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "M foo.txt\n"; got != want {
+		t.Errorf("gg status --staged = %q; want %q", got, want)
+	}
+	out, err = env.gg(ctx, env.root.String(), "status", "--unstaged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "M bar.txt\n"; got != want {
+		t.Errorf("gg status --unstaged = %q; want %q", got, want)
+	}
+	out, err = env.gg(ctx, env.root.String(), "diff", "--staged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out, []byte("+staged")) || bytes.Contains(out, []byte("bar.txt")) {
+		t.Errorf("gg diff --staged output:\n%s\nwant only the change to foo.txt", out)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "unstage", "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, env.root.String(), "status", "--staged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) > 0 {
+		t.Errorf("gg status --staged after unstage = %q; want empty", out)
+	}
+}
+
+func TestUnstage_NewFile(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "stage", "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unstaging a new file must leave it tracked, as after gg add.
+	if _, err := env.gg(ctx, env.root.String(), "unstage"); err != nil {
+		t.Fatal(err)
+	}
+	st, err := env.git.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st) != 1 || st[0].Name != "foo.txt" || st[0].Code != (git.StatusCode{' ', 'A'}) {
+		t.Errorf("status after gg unstage = %v; want foo.txt added with intent to add", st)
+	}
+}
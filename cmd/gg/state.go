@@ -0,0 +1,270 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const stateSynopsis = "show the operation in progress"
+
+func state(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg state", stateSynopsis+`
+
+	Show the multi-step operation that is in progress in the working copy,
+	if any: a rebase (including `+"`gg evolve`"+` and `+"`gg histedit`"+`), merge,
+	cherry-pick, revert, patch application, or bisect. For a rebase, it
+	shows which step the rebase stopped at. It also lists the files with
+	conflicts, the gg command that started the operation, and the commands
+	that continue or cancel it.`)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() != 0 {
+		return usagef("no arguments expected")
+	}
+	op, err := readOperationState(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	if op == nil {
+		_, err := fmt.Fprintln(cc.stdout, "no operation in progress")
+		return err
+	}
+	return op.write(cc.stdout)
+}
+
+// An operationState describes a multi-step Git operation in progress.
+type operationState struct {
+	kind    string // "rebase", "merge", "cherry-pick", "revert", "am", or "bisect"
+	step    int    // 1-based step of a rebase, or 0 if unknown
+	total   int    // number of steps in a rebase, or 0 if unknown
+	branch  string // branch being rebased
+	onto    string // commit being rebased onto
+	current string // commit being applied or merged
+	summary string // summary of current
+
+	// command is the gg command line that started the operation,
+	// or empty if it was not started by gg.
+	command   string
+	conflicts []git.TopPath
+}
+
+// readOperationState inspects the Git directory for an operation in
+// progress. It returns nil if there is none.
+func readOperationState(ctx context.Context, g *git.Git) (*operationState, error) {
+	gitDir, err := g.GitDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	op := new(operationState)
+	// origHead is the HEAD commit when the operation started.
+	var origHead string
+	if dir := filepath.Join(gitDir, "rebase-merge"); isdir(dir) {
+		op.kind = "rebase"
+		op.step, _ = strconv.Atoi(readStateFile(dir, "msgnum"))
+		op.total, _ = strconv.Atoi(readStateFile(dir, "end"))
+		op.branch = readStateFile(dir, "head-name")
+		op.onto = readStateFile(dir, "onto")
+		op.current = readStateFile(dir, "stopped-sha")
+		origHead = readStateFile(dir, "orig-head")
+	} else if dir := filepath.Join(gitDir, "rebase-apply"); isdir(dir) {
+		op.kind = "rebase"
+		if _, err := os.Stat(filepath.Join(dir, "applying")); err == nil {
+			op.kind = "am"
+		}
+		op.step, _ = strconv.Atoi(readStateFile(dir, "next"))
+		op.total, _ = strconv.Atoi(readStateFile(dir, "last"))
+		op.branch = readStateFile(dir, "head-name")
+		op.onto = readStateFile(dir, "onto")
+		origHead = readStateFile(dir, "orig-head")
+	} else if h := readStateFile(gitDir, "MERGE_HEAD"); h != "" {
+		op.kind = "merge"
+		op.current, _, _ = strings.Cut(h, "\n")
+		// A merge doesn't move HEAD until it is committed.
+		if head, err := g.Head(ctx); err == nil {
+			origHead = head.Commit.String()
+		}
+	} else if h := readStateFile(gitDir, "CHERRY_PICK_HEAD"); h != "" {
+		op.kind = "cherry-pick"
+		op.current = h
+	} else if h := readStateFile(gitDir, "REVERT_HEAD"); h != "" {
+		op.kind = "revert"
+		op.current = h
+	} else if readStateFile(gitDir, "BISECT_LOG") != "" {
+		op.kind = "bisect"
+	} else {
+		return nil, nil
+	}
+	op.branch = strings.TrimPrefix(op.branch, "refs/heads/")
+	if op.current != "" {
+		if c, err := g.CommitInfo(ctx, op.current); err == nil {
+			op.summary = c.Summary()
+		}
+	}
+	if origHead != "" {
+		op.command = recordedOperation(gitDir, origHead)
+	}
+
+	st, err := g.Status(ctx, git.StatusOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ent := range st {
+		if ent.Code.IsUnmerged() {
+			op.conflicts = append(op.conflicts, ent.Name)
+		}
+	}
+	return op, nil
+}
+
+func (op *operationState) write(w io.Writer) error {
+	sb := new(strings.Builder)
+	sb.WriteString(op.kind + " in progress")
+	if op.step > 0 && op.total > 0 {
+		fmt.Fprintf(sb, " (step %d of %d)", op.step, op.total)
+	}
+	sb.WriteString("\n")
+	if op.branch != "" {
+		fmt.Fprintf(sb, "  branch:     %s\n", op.branch)
+	}
+	if op.onto != "" {
+		fmt.Fprintf(sb, "  onto:       %s\n", shortHash(op.onto))
+	}
+	if op.current != "" {
+		fmt.Fprintf(sb, "  stopped at: %s %s\n", shortHash(op.current), op.summary)
+	}
+	if op.command != "" {
+		fmt.Fprintf(sb, "  started by: %s\n", op.command)
+	}
+	if len(op.conflicts) > 0 {
+		sb.WriteString("conflicts:\n")
+		for _, name := range op.conflicts {
+			fmt.Fprintf(sb, "  %s\n", name)
+		}
+	}
+	for _, hint := range op.hints() {
+		sb.WriteString(hint + "\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// hints returns suggestions for how to continue or cancel the operation.
+func (op *operationState) hints() []string {
+	startedBy := func(name string) bool {
+		return strings.HasPrefix(op.command, "gg "+name+" ") || op.command == "gg "+name
+	}
+	var hints []string
+	if len(op.conflicts) > 0 {
+		hints = append(hints, "Resolve the conflicts and mark them resolved with 'gg add'.")
+	}
+	switch op.kind {
+	case "rebase":
+		if startedBy("histedit") {
+			hints = append(hints,
+				"To continue, run 'gg histedit --continue'.",
+				"To cancel, run 'gg histedit --abort'.")
+			break
+		}
+		hints = append(hints, "To continue, run 'gg rebase --continue'.")
+		if startedBy("evolve") {
+			hints = append(hints, "To stop and keep the commits rebased so far, run 'gg evolve --stop'.")
+		}
+		hints = append(hints, "To cancel, run 'gg rebase --abort'.")
+	case "merge":
+		hints = append(hints,
+			"To finish, run 'gg commit'.",
+			"To cancel, run 'gg merge --abort'.")
+	case "cherry-pick":
+		hints = append(hints,
+			"To continue, run 'gg graft --continue'.",
+			"To cancel, run 'gg graft --abort'.")
+	case "revert", "am":
+		hints = append(hints,
+			"To continue, run 'git "+op.kind+" --continue'.",
+			"To cancel, run 'git "+op.kind+" --abort'.")
+	case "bisect":
+		hints = append(hints, "To finish, run 'git bisect reset'.")
+	}
+	return hints
+}
+
+// operationRecordPath is the path of the file that records which gg
+// command started the operation in progress, relative to the Git directory.
+const operationRecordPath = "gg/operation"
+
+// recordOperation notes that the gg command with the given arguments is
+// starting a multi-step operation, so that gg state can show it later.
+// The record is ignored unless the operation in progress started from the
+// same HEAD commit, so stale records are not shown.
+func recordOperation(ctx context.Context, g *git.Git, name string, args []string) error {
+	gitDir, err := g.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+	head, err := g.Head(ctx)
+	if err != nil {
+		// No commits yet, so no operation could be matched to the record.
+		return nil
+	}
+	command := strings.Join(append([]string{"gg", name}, args...), " ")
+	path := filepath.Join(gitDir, filepath.FromSlash(operationRecordPath))
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return fmt.Errorf("record operation: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(head.Commit.String()+"\n"+command+"\n"), 0o666); err != nil {
+		return fmt.Errorf("record operation: %w", err)
+	}
+	return nil
+}
+
+// recordedOperation returns the command line saved by recordOperation
+// if it was recorded at origHead.
+func recordedOperation(gitDir string, origHead string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(operationRecordPath)))
+	if err != nil {
+		return ""
+	}
+	head, command, _ := strings.Cut(strings.TrimSuffix(string(data), "\n"), "\n")
+	if head != origHead {
+		return ""
+	}
+	return command
+}
+
+// readStateFile returns the trimmed content of a file in dir
+// or the empty string if it can't be read.
+func readStateFile(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("None", func(t *testing.T) {
+		env, err := newTestEnv(ctx, t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := env.initRepoWithHistory(ctx, "."); err != nil {
+			t.Fatal(err)
+		}
+		out, err := env.gg(ctx, env.root.String(), "state")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(out), "no operation in progress\n"; got != want {
+			t.Errorf("gg state = %q; want %q", got, want)
+		}
+	})
+	t.Run("Merge", func(t *testing.T) {
+		env, err := newTestEnv(ctx, t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := setupMergeConflict(ctx, env); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := env.gg(ctx, env.root.String(), "merge", "feature"); err == nil {
+			t.Fatal("merge did not return error")
+		}
+		out, err := env.gg(ctx, env.root.String(), "state")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			"merge in progress\n",
+			"  started by: gg merge feature\n",
+			"conflicts:\n  foo.txt\n",
+			"To cancel, run 'gg merge --abort'.\n",
+		} {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("gg state output:\n%s\nwant to contain %q", out, want)
+			}
+		}
+	})
+}
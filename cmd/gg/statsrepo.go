@@ -0,0 +1,425 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const statsRepoSynopsis = "show repository size and history statistics"
+
+func statsRepo(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg stats-repo [--json] [-n N]", statsRepoSynopsis+`
+
+	Reports how many objects of each type the repository stores and how
+	big they are, how they are stored on disk, the largest files, the
+	number of commits on each local branch, the number of commits made
+	each year, and how much Git LFS content there is.
+
+	Run it before and after `+"`git gc`"+`, or when deciding whether a
+	partial clone would help, to see where the space goes.`)
+	jsonOutput := f.Bool("json", false, "print statistics as JSON")
+	n := f.Int("n", 10, "show the `N` largest files")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 0 {
+		return usagef("stats-repo takes no arguments")
+	}
+	if *n < 0 {
+		return usagef("-n must not be negative")
+	}
+	stats, err := collectRepoStats(ctx, cc, *n)
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		out, err := json.MarshalIndent(stats, "", "\t")
+		if err != nil {
+			return err
+		}
+		out = append(out, '\n')
+		_, err = cc.stdout.Write(out)
+		return err
+	}
+	return stats.write(cc)
+}
+
+// repoStats is the output of gg stats-repo. Sizes are in bytes.
+type repoStats struct {
+	Objects      []*objectTypeStats `json:"objects"`
+	Storage      *repoStorageStats  `json:"storage"`
+	LargestBlobs []*largeBlob       `json:"largestBlobs"`
+	Branches     []*branchStats     `json:"branches"`
+	Growth       []*yearStats       `json:"growth"`
+	LFS          *lfsStats          `json:"lfs"`
+}
+
+// objectTypeStats summarizes the objects of one type.
+type objectTypeStats struct {
+	Type     string `json:"type"`
+	Count    int64  `json:"count"`
+	Size     int64  `json:"size"`     // total uncompressed size
+	DiskSize int64  `json:"diskSize"` // total size on disk after compression and deltas
+}
+
+// repoStorageStats describes how objects are stored,
+// as reported by git count-objects.
+type repoStorageStats struct {
+	LooseObjects  int64 `json:"looseObjects"`
+	LooseSize     int64 `json:"looseSize"`
+	PackedObjects int64 `json:"packedObjects"`
+	Packs         int64 `json:"packs"`
+	PackSize      int64 `json:"packSize"`
+	Garbage       int64 `json:"garbage"`
+}
+
+// largeBlob is one of the largest blobs in the repository.
+// Path is one of the names the blob was committed under,
+// or empty if it is not reachable from any ref.
+type largeBlob struct {
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+	Path string `json:"path,omitempty"`
+}
+
+// branchStats is the number of commits reachable from a local branch.
+type branchStats struct {
+	Name    string `json:"name"`
+	Commits int64  `json:"commits"`
+}
+
+// yearStats is the number of commits made in a year
+// and in that year and all those before it.
+type yearStats struct {
+	Year    int   `json:"year"`
+	Commits int64 `json:"commits"`
+	Total   int64 `json:"total"`
+}
+
+// lfsStats describes Git LFS usage. TrackedFiles counts the files in the
+// index that use the LFS filter. LocalObjects and LocalSize describe the
+// LFS content downloaded into the repository.
+type lfsStats struct {
+	TrackedFiles int64 `json:"trackedFiles"`
+	LocalObjects int64 `json:"localObjects"`
+	LocalSize    int64 `json:"localSize"`
+}
+
+func collectRepoStats(ctx context.Context, cc *cmdContext, nBlobs int) (*repoStats, error) {
+	stats := new(repoStats)
+	var blobs []*largeBlob
+	var err error
+	stats.Objects, blobs, err = repoObjectStats(ctx, cc.git)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(blobs, func(i, j int) bool {
+		return blobs[i].Size > blobs[j].Size
+	})
+	if len(blobs) > nBlobs {
+		blobs = blobs[:nBlobs]
+	}
+	if err := findBlobPaths(ctx, cc.git, blobs); err != nil {
+		return nil, err
+	}
+	stats.LargestBlobs = blobs
+
+	out, err := cc.git.Output(ctx, "count-objects", "-v")
+	if err != nil {
+		return nil, err
+	}
+	stats.Storage, err = parseCountObjects(out)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Branches = []*branchStats{}
+	iter := cc.git.IterateRefs(ctx, git.IterateRefsOptions{
+		LimitToBranches: true,
+	})
+	for iter.Next() {
+		stats.Branches = append(stats.Branches, &branchStats{Name: iter.Ref().Branch()})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	for _, b := range stats.Branches {
+		out, err := cc.git.Output(ctx, "rev-list", "--count", git.BranchRef(b.Name).String(), "--")
+		if err != nil {
+			return nil, err
+		}
+		b.Commits, err = strconv.ParseInt(strings.TrimSuffix(out, "\n"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("count commits on %s: %v", b.Name, err)
+		}
+	}
+
+	stats.Growth = []*yearStats{}
+	if len(stats.Branches) > 0 {
+		out, err := cc.git.Output(ctx, "log", "--all", "--format=%cd", "--date=format:%Y", "--")
+		if err != nil {
+			return nil, err
+		}
+		stats.Growth, err = parseCommitYears(out)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stats.LFS, err = repoLFSStats(ctx, cc.git)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// repoObjectStats summarizes every object in the repository by type
+// and returns the blobs, whose paths are not filled in.
+func repoObjectStats(ctx context.Context, g *git.Git) ([]*objectTypeStats, []*largeBlob, error) {
+	out, err := g.Output(ctx, "cat-file", "--batch-all-objects", "--batch-check=%(objecttype) %(objectsize) %(objectsize:disk) %(objectname)")
+	if err != nil {
+		return nil, nil, err
+	}
+	types := []*objectTypeStats{
+		{Type: "commit"},
+		{Type: "tree"},
+		{Type: "blob"},
+		{Type: "tag"},
+	}
+	blobs := []*largeBlob{}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return nil, nil, fmt.Errorf("count objects: unexpected line %q from git cat-file", line)
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("count objects: %v", err)
+		}
+		diskSize, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("count objects: %v", err)
+		}
+		for _, ts := range types {
+			if ts.Type == fields[0] {
+				ts.Count++
+				ts.Size += size
+				ts.DiskSize += diskSize
+			}
+		}
+		if fields[0] == "blob" {
+			blobs = append(blobs, &largeBlob{Hash: fields[3], Size: size})
+		}
+	}
+	return types, blobs, nil
+}
+
+// findBlobPaths fills in the Path field of the given blobs from the
+// objects reachable from any ref.
+func findBlobPaths(ctx context.Context, g *git.Git, blobs []*largeBlob) error {
+	if len(blobs) == 0 {
+		return nil
+	}
+	byHash := make(map[string]*largeBlob, len(blobs))
+	for _, b := range blobs {
+		byHash[b.Hash] = b
+	}
+	out, err := g.Output(ctx, "rev-list", "--objects", "--all", "--")
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		hash, path, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		if b := byHash[hash]; b != nil && b.Path == "" {
+			b.Path = path
+		}
+	}
+	return nil
+}
+
+// parseCountObjects parses the output of git count-objects -v.
+func parseCountObjects(out string) (*repoStorageStats, error) {
+	stats := new(repoStorageStats)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("count objects: unexpected line %q from git count-objects", line)
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("count objects: %s: %v", key, err)
+		}
+		// Sizes are reported in KiB.
+		switch key {
+		case "count":
+			stats.LooseObjects = n
+		case "size":
+			stats.LooseSize = n << 10
+		case "in-pack":
+			stats.PackedObjects = n
+		case "packs":
+			stats.Packs = n
+		case "size-pack":
+			stats.PackSize = n << 10
+		case "garbage":
+			stats.Garbage = n
+		}
+	}
+	return stats, nil
+}
+
+// parseCommitYears counts the lines of git log --format=%cd --date=format:%Y
+// output by year, oldest first.
+func parseCommitYears(out string) ([]*yearStats, error) {
+	counts := make(map[int]int64)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		year, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("commit history: unexpected date %q", line)
+		}
+		counts[year]++
+	}
+	growth := make([]*yearStats, 0, len(counts))
+	for year, n := range counts {
+		growth = append(growth, &yearStats{Year: year, Commits: n})
+	}
+	sort.Slice(growth, func(i, j int) bool {
+		return growth[i].Year < growth[j].Year
+	})
+	var total int64
+	for _, ys := range growth {
+		total += ys.Commits
+		ys.Total = total
+	}
+	return growth, nil
+}
+
+func repoLFSStats(ctx context.Context, g *git.Git) (*lfsStats, error) {
+	stats := new(lfsStats)
+	out, err := g.Output(ctx, "ls-files", "-z", "--", ":(top,attr:filter=lfs)")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			stats.TrackedFiles++
+		}
+	}
+	commonDir, err := g.CommonDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = filepath.WalkDir(filepath.Join(commonDir, "lfs", "objects"), func(path string, ent fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !ent.Type().IsRegular() {
+			return nil
+		}
+		info, err := ent.Info()
+		if err != nil {
+			return err
+		}
+		stats.LocalObjects++
+		stats.LocalSize += info.Size()
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (stats *repoStats) write(cc *cmdContext) error {
+	tw := tabwriter.NewWriter(cc.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "objects:")
+	for _, ts := range stats.Objects {
+		fmt.Fprintf(tw, "  %ss\t%d\t%s\t(%s on disk)\n", ts.Type, ts.Count, formatFileSize(ts.Size), formatFileSize(ts.DiskSize))
+	}
+	fmt.Fprintln(tw, "storage:")
+	fmt.Fprintf(tw, "  loose\t%d objects\t%s\n", stats.Storage.LooseObjects, formatFileSize(stats.Storage.LooseSize))
+	fmt.Fprintf(tw, "  packed\t%d objects in %d packs\t%s\n", stats.Storage.PackedObjects, stats.Storage.Packs, formatFileSize(stats.Storage.PackSize))
+	if stats.Storage.Garbage > 0 {
+		fmt.Fprintf(tw, "  garbage\t%d files\t\n", stats.Storage.Garbage)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(stats.LargestBlobs) > 0 {
+		fmt.Fprintln(tw, "largest files:")
+		for _, b := range stats.LargestBlobs {
+			path := b.Path
+			if path == "" {
+				path = "(unreachable)"
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", formatFileSize(b.Size), b.Hash[:8], path)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	if len(stats.Branches) > 0 {
+		fmt.Fprintln(tw, "branches:")
+		for _, b := range stats.Branches {
+			fmt.Fprintf(tw, "  %s\t%d commits\n", b.Name, b.Commits)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	if len(stats.Growth) > 0 {
+		fmt.Fprintln(tw, "commits per year:")
+		for _, ys := range stats.Growth {
+			fmt.Fprintf(tw, "  %d\t%d\t(%d total)\n", ys.Year, ys.Commits, ys.Total)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	if stats.LFS.TrackedFiles > 0 || stats.LFS.LocalObjects > 0 {
+		_, err := fmt.Fprintf(cc.stdout, "lfs:\n  %s tracked, %d objects stored locally (%s)\n",
+			countFiles(int(stats.LFS.TrackedFiles)), stats.LFS.LocalObjects, formatFileSize(stats.LFS.LocalSize))
+		return err
+	}
+	return nil
+}
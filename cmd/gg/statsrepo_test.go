@@ -0,0 +1,126 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCountObjects(t *testing.T) {
+	const out = "count: 3\n" +
+		"size: 12\n" +
+		"in-pack: 40\n" +
+		"packs: 1\n" +
+		"size-pack: 100\n" +
+		"prune-packable: 0\n" +
+		"garbage: 0\n" +
+		"size-garbage: 0\n"
+	got, err := parseCountObjects(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &repoStorageStats{
+		LooseObjects:  3,
+		LooseSize:     12 << 10,
+		PackedObjects: 40,
+		Packs:         1,
+		PackSize:      100 << 10,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseCountObjects(...) (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseCommitYears(t *testing.T) {
+	got, err := parseCommitYears("2024\n2022\n2024\n2023\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*yearStats{
+		{Year: 2022, Commits: 1, Total: 1},
+		{Year: 2023, Commits: 1, Total: 2},
+		{Year: 2024, Commits: 2, Total: 4},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseCommitYears(...) (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatsRepo(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	big := strings.Repeat("x", 4096)
+	err = env.root.Apply(
+		filesystem.Write("small.txt", dummyContent),
+		filesystem.Write("big.txt", big),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "small.txt", "big.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "stats-repo", "--json", "-n", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats *repoStats
+	if err := json.Unmarshal(out, &stats); err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	counts := make(map[string]int64)
+	for _, ts := range stats.Objects {
+		counts[ts.Type] = ts.Count
+	}
+	if want := map[string]int64{"commit": 1, "tree": 1, "blob": 2, "tag": 0}; !cmp.Equal(want, counts) {
+		t.Errorf("object counts = %v; want %v", counts, want)
+	}
+	if len(stats.LargestBlobs) != 1 || stats.LargestBlobs[0].Path != "big.txt" || stats.LargestBlobs[0].Size != int64(len(big)) {
+		t.Errorf("largest blobs = %s; want only big.txt", out)
+	}
+	if len(stats.Branches) != 1 || stats.Branches[0].Name != "main" || stats.Branches[0].Commits != 1 {
+		t.Errorf("branches = %s; want main with 1 commit", out)
+	}
+	if len(stats.Growth) != 1 || stats.Growth[0].Total != 1 {
+		t.Errorf("growth = %s; want 1 commit in 1 year", out)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "stats-repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "big.txt\n") || !strings.Contains(string(out), "main") {
+		t.Errorf("gg stats-repo output:\n%s\nwant big.txt and main listed", out)
+	}
+}
@@ -18,24 +18,81 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
+	"gg-scm.io/tool/internal/template"
 	"gg-scm.io/tool/internal/terminal"
 )
 
 const statusSynopsis = "show changed files in the working directory"
 
 func status(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg status [FILE [...]]", statusSynopsis+`
+	f := flag.NewFlagSet(true, "gg status [--json | -T TEMPLATE | --staged | --unstaged] [-b] [--expand-renames] [--relative | --root-relative] [-I PATTERN] [-X PATTERN] [FILE [...]]", statusSynopsis+`
 
-aliases: st, check`)
+aliases: st, check
+
+	When all of the files in a directory have been renamed into another
+	directory, the move is shown as a single "directory renamed" line.
+	`+"`--expand-renames`"+` lists the renamed files individually instead.
+
+	gg usually shows the changes between HEAD and the working copy,
+	regardless of what is in Git's index. `+"`--staged`"+` shows only the
+	changes in the index and `+"`--unstaged`"+` shows only the changes in the
+	working copy that are not in the index. See `+"`gg stage`"+`.
+
+	Mode changes, symbolic link targets, and submodule commits are shown
+	in parentheses after a modified file's name. `+"`--json`"+` prints a JSON
+	array with an object for each line of the output instead, with these
+	details in separate fields. The object for a renamed or copied file
+	has the similarity percentage in its `+"`score`"+` field.
+
+	`+"`-b`"+` first prints the current branch, how it compares to its
+	upstream, and the number of stashes. With `+"`--json`"+`, it prints an
+	object with `+"`branch`"+`, `+"`stashes`"+`, and `+"`files`"+` fields instead
+	of an array.
+
+	`+"`-T`"+` prints each file with a Mercurial-style template, like
+	`+"`-T '{status} {path}\\n'`"+`. The keywords are `+"`status`"+` (the letter
+	gg status prints), `+"`path`"+`, and `+"`source`"+` (the file a copied or
+	renamed file came from, or empty).`+templateHelp+patternHelp+pathStyleHelp)
+	pats := &patternSet{rawArgs: true}
+	pats.addFlags(f)
+	pathStyle := new(pathStyleFlags)
+	pathStyle.addFlags(f)
+	expandRenames := f.Bool("expand-renames", false, "list the files in renamed directories individually")
+	staged := f.Bool("staged", false, "show only the changes in the index")
+	unstaged := f.Bool("unstaged", false, "show only the changes that are not in the index")
+	jsonOutput := f.Bool("json", false, "print a JSON array instead of a list of files")
+	tmplString := f.String("template", "", "print each file with a `template`")
+	f.Alias("template", "T")
+	showBranch := f.Bool("b", false, "show the branch, its upstream, and the number of stashes")
+	f.Alias("b", "branch")
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
+	if *staged && *unstaged {
+		return usagef("cannot pass both --staged and --unstaged")
+	}
+	if *jsonOutput && (*staged || *unstaged) {
+		return usagef("--json cannot be used with --staged or --unstaged")
+	}
+	var tmpl *template.Template
+	if f.IsSet("template") {
+		if *jsonOutput || *staged || *unstaged {
+			return usagef("--template cannot be used with --json, --staged, or --unstaged")
+		}
+		var err error
+		tmpl, err = parseTemplate(*tmplString, statusTemplateKeywords)
+		if err != nil {
+			return err
+		}
+	}
 	var (
 		addedColor     []byte
 		modifiedColor  []byte
@@ -43,6 +100,7 @@ aliases: st, check`)
 		missingColor   []byte
 		untrackedColor []byte
 		unmergedColor  []byte
+		skippedColor   []byte
 	)
 	cfg, err := cc.git.ReadConfig(ctx)
 	if err != nil {
@@ -76,27 +134,187 @@ aliases: st, check`)
 		if err != nil {
 			fmt.Fprintln(cc.stderr, "gg:", err)
 		}
+		skippedColor, err = cfg.Color("color.ggstatus.skipped", "yellow")
+		if err != nil {
+			fmt.Fprintln(cc.stderr, "gg:", err)
+		}
+	}
+	pf, err := pathStyle.formatter(ctx, cc, cfg)
+	if err != nil {
+		return err
+	}
+	pats.args = f.Args()
+	pathspecs, err := pats.pathspecs(ctx, cc.git)
+	if err != nil {
+		return err
+	}
+	summary, statusErr := readStatusSummary(ctx, cc.git, pathspecs)
+	if summary == nil {
+		summary = new(statusSummary)
+	}
+	st := summary.entries
+	var details map[git.TopPath]*fileDetail
+	if !*staged && !*unstaged {
+		details, err = readFileDetails(ctx, cc.git, pathspecs)
+		if err != nil {
+			fmt.Fprintln(cc.stderr, "gg:", err)
+		}
 	}
-	pathspecs := make([]git.Pathspec, f.NArg())
-	for i, arg := range f.Args() {
-		pathspecs[i] = git.Pathspec(arg)
+	if *jsonOutput {
+		marked, err := listUntrackedChanges(ctx, cc.git, pathspecs)
+		if err != nil {
+			fmt.Fprintln(cc.stderr, "gg:", err)
+		}
+		var v interface{} = statusJSON(summary, details, marked)
+		if *showBranch {
+			v = &statusBranchJSON{
+				Branch:  summary.branch,
+				Stashes: summary.stashes,
+				Files:   v.([]*statusJSONEntry),
+			}
+		}
+		if err := writeJSON(cc.stdout, v); err != nil {
+			return err
+		}
+		return statusErr
+	}
+	if *showBranch && statusErr == nil {
+		if _, err := io.WriteString(cc.stdout, describeStatusBranch(summary)+"\n"); err != nil {
+			return err
+		}
+	}
+	if tmpl != nil {
+		marked, err := listUntrackedChanges(ctx, cc.git, pathspecs)
+		if err != nil {
+			fmt.Fprintln(cc.stderr, "gg:", err)
+		}
+		for _, e := range statusJSON(summary, details, marked) {
+			source := ""
+			if e.From != "" {
+				source = pf.format(e.From)
+			}
+			err := tmpl.Execute(cc.stdout, template.Keywords{
+				"status": e.Status,
+				"path":   pf.format(e.Name),
+				"source": source,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return statusErr
 	}
-	st, statusErr := cc.git.Status(ctx, git.StatusOptions{
-		Pathspecs: pathspecs,
-	})
 	if colorize {
 		if err := terminal.ResetTextStyle(cc.stdout); err != nil {
 			return err
 		}
 	}
+	if *staged || *unstaged {
+		colors := map[byte][]byte{
+			'A': addedColor,
+			'M': modifiedColor,
+			'R': removedColor,
+			'!': missingColor,
+			'?': untrackedColor,
+			'U': unmergedColor,
+		}
+		for _, ent := range st {
+			for _, line := range indexStatusLines(ent, *staged) {
+				if _, err := fmt.Fprintf(cc.stdout, "%s%c %s\n", colors[line.letter], line.letter, pf.format(line.name)); err != nil {
+					return err
+				}
+				if colorize {
+					if err := terminal.ResetTextStyle(cc.stdout); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return statusErr
+	}
+	aliases, err := findPathAliases(ctx, cc.git, st)
+	if err != nil {
+		fmt.Fprintln(cc.stderr, "gg:", err)
+	}
+	aliasOf := make(map[git.TopPath]pathAlias)
+	caseRenamed := make(map[git.TopPath]bool)
+	for _, a := range aliases {
+		aliasOf[a.untracked] = a
+		if a.isCaseRename() {
+			caseRenamed[a.tracked] = true
+		} else {
+			fmt.Fprintf(cc.stderr, "gg: ignoring %s: same file as %s (file system normalizes names)\n", a.untracked, a.tracked)
+		}
+	}
+	moveOf := make(map[git.TopPath]*dirMove)
+	if !*expandRenames {
+		var renames []fileRename
+		for _, ent := range st {
+			if ent.Code.IsRenamed() {
+				renames = append(renames, fileRename{from: ent.From, to: ent.Name})
+			}
+		}
+		moves, err := findDirMoves(renames, trackedFilesRemain(ctx, cc.git, ""))
+		if err != nil {
+			fmt.Fprintln(cc.stderr, "gg:", err)
+		}
+		for _, m := range moves {
+			for _, r := range m.renames {
+				moveOf[r.to] = m
+			}
+		}
+	}
+	shownMoves := make(map[*dirMove]bool)
 	foundUnrecognized := false
 	hitRenameBug := false
 	for _, ent := range st {
+		if caseRenamed[ent.Name] {
+			// Reported along with the alias.
+			continue
+		}
+		if m := moveOf[ent.Name]; m != nil && ent.Code.IsRenamed() {
+			if shownMoves[m] {
+				continue
+			}
+			shownMoves[m] = true
+			_, err := fmt.Fprintf(cc.stdout, "%sdirectory renamed %s/ → %s/ (%d files)\n", addedColor, pf.format(m.from), pf.format(m.to), len(m.renames))
+			if err != nil {
+				return err
+			}
+			if colorize {
+				if err := terminal.ResetTextStyle(cc.stdout); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if a, ok := aliasOf[ent.Name]; ok {
+			if a.isCaseRename() {
+				// Present the rename the same way as on a case-sensitive
+				// file system.
+				_, err = fmt.Fprintf(cc.stdout, "%s? %s\n", untrackedColor, pf.format(a.untracked))
+				if err == nil && colorize {
+					err = terminal.ResetTextStyle(cc.stdout)
+				}
+				if err == nil {
+					_, err = fmt.Fprintf(cc.stdout, "%s! %s\n", missingColor, pf.format(a.tracked))
+				}
+				if err != nil {
+					return err
+				}
+				if colorize {
+					if err := terminal.ResetTextStyle(cc.stdout); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
 		switch {
 		case ent.Code.IsModified():
-			_, err = fmt.Fprintf(cc.stdout, "%sM %s\n", modifiedColor, ent.Name)
+			_, err = fmt.Fprintf(cc.stdout, "%sM %s%s\n", modifiedColor, pf.format(ent.Name), details[ent.Name].annotation())
 		case ent.Code.IsAdded():
-			name := ent.Name
+			name := pf.format(ent.Name)
 			if name == "" {
 				// See https://github.com/gg-scm/gg/issues/60 for explanation.
 				name = "???"
@@ -110,12 +328,12 @@ aliases: st, check`)
 						return err
 					}
 				}
-				_, err = fmt.Fprintf(cc.stdout, "%s! %s\n", missingColor, ent.From)
+				_, err = fmt.Fprintf(cc.stdout, "%s! %s\n", missingColor, pf.format(ent.From))
 			}
 		case ent.Code.IsRemoved():
-			_, err = fmt.Fprintf(cc.stdout, "%sR %s\n", removedColor, ent.Name)
+			_, err = fmt.Fprintf(cc.stdout, "%sR %s\n", removedColor, pf.format(ent.Name))
 		case ent.Code.IsCopied():
-			if _, err := fmt.Fprintf(cc.stdout, "%sA %s\n", addedColor, ent.Name); err != nil {
+			if _, err := fmt.Fprintf(cc.stdout, "%sA %s\n", addedColor, pf.format(ent.Name)); err != nil {
 				return err
 			}
 			if colorize {
@@ -123,21 +341,21 @@ aliases: st, check`)
 					return err
 				}
 			}
-			_, err = fmt.Fprintf(cc.stdout, "  %s\n", ent.From)
+			_, err = fmt.Fprintf(cc.stdout, "  %s\n", pf.format(ent.From))
 		case ent.Code.IsRenamed():
-			fmt.Fprintf(cc.stdout, "%sA %s\n", addedColor, ent.Name)
+			fmt.Fprintf(cc.stdout, "%sA %s\n", addedColor, pf.format(ent.Name))
 			if colorize {
 				if err := terminal.ResetTextStyle(cc.stdout); err != nil {
 					return err
 				}
 			}
-			_, err = fmt.Fprintf(cc.stdout, "  %s\n%sR %s\n", ent.From, removedColor, ent.From)
+			_, err = fmt.Fprintf(cc.stdout, "  %s\n%sR %s\n", pf.format(ent.From), removedColor, pf.format(ent.From))
 		case ent.Code.IsMissing():
-			_, err = fmt.Fprintf(cc.stdout, "%s! %s\n", missingColor, ent.Name)
+			_, err = fmt.Fprintf(cc.stdout, "%s! %s\n", missingColor, pf.format(ent.Name))
 		case ent.Code.IsUntracked():
-			_, err = fmt.Fprintf(cc.stdout, "%s? %s\n", untrackedColor, ent.Name)
+			_, err = fmt.Fprintf(cc.stdout, "%s? %s\n", untrackedColor, pf.format(ent.Name))
 		case ent.Code.IsUnmerged():
-			_, err = fmt.Fprintf(cc.stdout, "%sU %s\n", unmergedColor, ent.Name)
+			_, err = fmt.Fprintf(cc.stdout, "%sU %s\n", unmergedColor, pf.format(ent.Name))
 		default:
 			fmt.Fprintf(cc.stderr, "gg: unrecognized status for %s: '%v'\n", ent.Name, ent.Code)
 			foundUnrecognized = true
@@ -151,6 +369,20 @@ aliases: st, check`)
 			}
 		}
 	}
+	marked, err := listUntrackedChanges(ctx, cc.git, pathspecs)
+	if err != nil {
+		fmt.Fprintln(cc.stderr, "gg:", err)
+	}
+	for _, name := range marked {
+		if _, err := fmt.Fprintf(cc.stdout, "%sS %s\n", skippedColor, pf.format(name)); err != nil {
+			return err
+		}
+		if colorize {
+			if err := terminal.ResetTextStyle(cc.stdout); err != nil {
+				return err
+			}
+		}
+	}
 	if foundUnrecognized {
 		return errors.New("unrecognized output from git status. Please file a bug at https://github.com/gg-scm/gg/issues/new and include the output from this command.")
 	}
@@ -162,3 +394,134 @@ aliases: st, check`)
 	}
 	return nil
 }
+
+// An indexStatusLine is a line of gg status --staged or --unstaged.
+// A letter of ' ' marks the source of a rename or copy.
+type indexStatusLine struct {
+	letter byte
+	name   git.TopPath
+}
+
+// indexStatusLines returns the lines of gg status --staged (or
+// --unstaged, if staged is false) for a status entry, using the same
+// letters as gg status.
+func indexStatusLines(ent git.StatusEntry, staged bool) []indexStatusLine {
+	if ent.Code.IsUnmerged() {
+		return []indexStatusLine{{'U', ent.Name}}
+	}
+	code := ent.Code[1]
+	if staged {
+		code = ent.Code[0]
+	}
+	switch code {
+	case 'M', 'T':
+		return []indexStatusLine{{'M', ent.Name}}
+	case 'A':
+		return []indexStatusLine{{'A', ent.Name}}
+	case 'D':
+		if staged {
+			return []indexStatusLine{{'R', ent.Name}}
+		}
+		return []indexStatusLine{{'!', ent.Name}}
+	case 'R':
+		if !staged {
+			// A file added with intent to add that Git pairs with a
+			// missing file.
+			return []indexStatusLine{{'A', ent.Name}, {'!', ent.From}}
+		}
+		return []indexStatusLine{{'A', ent.Name}, {' ', ent.From}, {'R', ent.From}}
+	case 'C':
+		return []indexStatusLine{{'A', ent.Name}, {' ', ent.From}}
+	case '?':
+		return []indexStatusLine{{'?', ent.Name}}
+	default:
+		return nil
+	}
+}
+
+// describeStatusBranch returns the line that gg status -b prints.
+func describeStatusBranch(summary *statusSummary) string {
+	sb := new(strings.Builder)
+	b := summary.branch
+	switch {
+	case b.Name != "" && b.Commit == "":
+		fmt.Fprintf(sb, "on branch %s (no commits yet)", b.Name)
+	case b.Name != "":
+		fmt.Fprintf(sb, "on branch %s", b.Name)
+		if b.UpstreamGone {
+			fmt.Fprintf(sb, ", upstream %s is gone", b.Upstream)
+		} else if b.Upstream != "" {
+			sb.WriteString(", " + describeAheadBehind(b.Ahead, b.Behind, b.Upstream))
+		}
+	case b.Commit != "":
+		short := b.Commit
+		if h, err := git.ParseHash(b.Commit); err == nil {
+			short = h.Short()
+		}
+		fmt.Fprintf(sb, "HEAD detached at %s", short)
+	default:
+		sb.WriteString("no commits yet")
+	}
+	if summary.stashes > 0 {
+		fmt.Fprintf(sb, " (%s)", countStashes(summary.stashes))
+	}
+	return sb.String()
+}
+
+// statusTemplateKeywords is the list of keywords available to gg status -T.
+var statusTemplateKeywords = []string{"status", "path", "source"}
+
+// statusBranchJSON is the output of gg status --json -b.
+type statusBranchJSON struct {
+	Branch  statusBranch       `json:"branch"`
+	Stashes int                `json:"stashes"`
+	Files   []*statusJSONEntry `json:"files"`
+}
+
+// A statusJSONEntry is an element of gg status --json's output.
+type statusJSONEntry struct {
+	Status string      `json:"status"` // the letter gg status prints
+	Name   git.TopPath `json:"name"`
+	From   git.TopPath `json:"from,omitempty"`  // source of a copy or rename
+	Score  int         `json:"score,omitempty"` // similarity percentage of a copy or rename
+	fileDetail
+}
+
+// statusJSON returns the entries for gg status --json. marked is the
+// list of files marked with untrack-changes.
+func statusJSON(summary *statusSummary, details map[git.TopPath]*fileDetail, marked []git.TopPath) []*statusJSONEntry {
+	entries := []*statusJSONEntry{}
+	for _, ent := range summary.entries {
+		switch {
+		case ent.Code.IsModified():
+			e := &statusJSONEntry{Status: "M", Name: ent.Name}
+			if d := details[ent.Name]; d != nil {
+				e.fileDetail = *d
+			}
+			entries = append(entries, e)
+		case ent.Code.IsAdded():
+			entries = append(entries, &statusJSONEntry{Status: "A", Name: ent.Name})
+			if ent.Code.IsOriginalMissing() {
+				entries = append(entries, &statusJSONEntry{Status: "!", Name: ent.From})
+			}
+		case ent.Code.IsRemoved():
+			entries = append(entries, &statusJSONEntry{Status: "R", Name: ent.Name})
+		case ent.Code.IsCopied():
+			entries = append(entries, &statusJSONEntry{Status: "A", Name: ent.Name, From: ent.From, Score: summary.scores[ent.Name]})
+		case ent.Code.IsRenamed():
+			entries = append(entries,
+				&statusJSONEntry{Status: "A", Name: ent.Name, From: ent.From, Score: summary.scores[ent.Name]},
+				&statusJSONEntry{Status: "R", Name: ent.From})
+		case ent.Code.IsMissing():
+			entries = append(entries, &statusJSONEntry{Status: "!", Name: ent.Name})
+		case ent.Code.IsUntracked():
+			entries = append(entries, &statusJSONEntry{Status: "?", Name: ent.Name})
+		case ent.Code.IsUnmerged():
+			entries = append(entries, &statusJSONEntry{Status: "U", Name: ent.Name})
+		}
+	}
+	for _, name := range marked {
+		entries = append(entries, &statusJSONEntry{Status: "S", Name: name})
+	}
+	return entries
+}
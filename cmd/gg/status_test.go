@@ -17,6 +17,9 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"os"
+	"runtime"
 	"testing"
 
 	"gg-scm.io/pkg/git"
@@ -91,6 +94,64 @@ func TestStatus(t *testing.T) {
 	}
 }
 
+func TestStatus_Patterns(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	files := []string{"a.go", "b.txt", "sub/c.go", "sub/D.GO"}
+	for _, name := range files {
+		if err := env.root.Apply(filesystem.Write(name, dummyContent)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := env.addFiles(ctx, files...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range files {
+		if err := env.root.Apply(filesystem.Write(name, "Modified!\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{args: []string{"glob:*.go"}, want: []string{"a.go"}},
+		{args: []string{"relglob:*.go"}, want: []string{"a.go", "sub/c.go"}},
+		{args: []string{"iglob:**/*.go"}, want: []string{"a.go", "sub/c.go", "sub/D.GO"}},
+		{args: []string{"path:sub"}, want: []string{"sub/c.go", "sub/D.GO"}},
+		{args: []string{"re:sub/.*\\.go$"}, want: []string{"sub/c.go"}},
+		{args: []string{"-I", "rootglob:sub/*"}, want: []string{"sub/c.go", "sub/D.GO"}},
+		{args: []string{"-X", "*.go"}, want: []string{"b.txt", "sub/c.go", "sub/D.GO"}},
+		{args: []string{"-I", "relglob:*.go", "-X", "path:sub"}, want: []string{"a.go"}},
+		{args: []string{"set:relglob:*.go - sub/c.go"}, want: []string{"a.go"}},
+	}
+	for _, test := range tests {
+		out, err := env.gg(ctx, env.root.String(), append([]string{"status"}, test.args...)...)
+		if err != nil {
+			t.Errorf("gg status %q: %v", test.args, err)
+			continue
+		}
+		var got []string
+		for _, line := range parseGGStatus(out, t) {
+			got = append(got, line.name)
+		}
+		if diff := cmp.Diff(test.want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+			t.Errorf("gg status %q files (-want +got):\n%s", test.args, diff)
+		}
+	}
+}
+
 // TestStatus_RenamedLocally is a regression test for
 // https://github.com/gg-scm/gg/issues/44.
 func TestStatus_RenamedLocally(t *testing.T) {
@@ -216,6 +277,71 @@ func parseGGStatus(out []byte, e errorer) []ggStatusLine {
 	return lines
 }
 
+func TestStatus_Relative(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("top.txt", dummyContent),
+		filesystem.Write("a/b/inner.txt", dummyContent),
+		filesystem.Write("a/c/sibling.txt", dummyContent),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.trackFiles(ctx, "top.txt", "a/b/inner.txt", "a/c/sibling.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := env.root.FromSlash("a/b")
+	rootWant := []ggStatusLine{
+		{letter: 'A', name: "a/b/inner.txt"},
+		{letter: 'A', name: "a/c/sibling.txt"},
+		{letter: 'A', name: "top.txt"},
+	}
+	relativeWant := []ggStatusLine{
+		{letter: 'A', name: "inner.txt"},
+		{letter: 'A', name: "../c/sibling.txt"},
+		{letter: 'A', name: "../../top.txt"},
+	}
+	tests := []struct {
+		name   string
+		config string
+		args   []string
+		want   []ggStatusLine
+	}{
+		{name: "Default", want: rootWant},
+		{name: "Flag", args: []string{"--relative"}, want: relativeWant},
+		{name: "Config", config: "[gg]\nrelativePaths = true\n", want: relativeWant},
+		{name: "RootOverride", config: "[gg]\nrelativePaths = true\n", args: []string{"--root-relative"}, want: rootWant},
+	}
+	for _, test := range tests {
+		if err := env.writeConfig([]byte(test.config)); err != nil {
+			t.Fatal(err)
+		}
+		out, err := env.gg(ctx, dir, append([]string{"status"}, test.args...)...)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		got := parseGGStatus(out, t)
+		diff := cmp.Diff(test.want, got,
+			cmp.AllowUnexported(ggStatusLine{}),
+			cmp.Transformer("Map", ggStatusMap),
+			cmpopts.EquateEmpty())
+		if diff != "" {
+			t.Errorf("%s: output differs (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
 func TestParseGGStatus(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -292,3 +418,170 @@ type recordErrorer bool
 func (e *recordErrorer) Errorf(format string, args ...interface{}) {
 	*e = true
 }
+
+func TestStatus_DirectoryRenamed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("old/foo.txt", "foo\n"),
+		filesystem.Write("old/sub/bar.txt", "bar\n"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "old/foo.txt", "old/sub/bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "mv", "old", "new"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "directory renamed old/ → new/ (2 files)\n"; got != want {
+		t.Errorf("gg status = %q; want %q", got, want)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "status", "--expand-renames")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := parseGGStatus(out, t)
+	want := []ggStatusLine{
+		{letter: 'A', name: "new/foo.txt", from: "old/foo.txt"},
+		{letter: 'R', name: "old/foo.txt"},
+		{letter: 'A', name: "new/sub/bar.txt", from: "old/sub/bar.txt"},
+		{letter: 'R', name: "old/sub/bar.txt"},
+	}
+	diff := cmp.Diff(want, got,
+		cmp.AllowUnexported(ggStatusLine{}),
+		cmpopts.SortSlices(func(l1, l2 ggStatusLine) bool { return l1.name < l2.name }))
+	if diff != "" {
+		t.Errorf("gg status --expand-renames (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatus_FileDetails(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes and symbolic links not supported on Windows")
+	}
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("script.sh", "echo hi\n"),
+		filesystem.Symlink("script.sh", "link"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "script.sh", "link"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(env.root.FromSlash("script.sh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Remove("link"),
+		filesystem.Symlink("other.sh", "link"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "M link (symbolic link script.sh → other.sh)\n" +
+		"M script.sh (mode 100644 → 100755 only)\n"
+	if got := string(out); got != want {
+		t.Errorf("gg status output:\n%s\nwant:\n%s", got, want)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "status", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*statusJSONEntry
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal output: %v; output:\n%s", err, out)
+	}
+	wantJSON := []*statusJSONEntry{
+		{Status: "M", Name: "link", fileDetail: fileDetail{OldMode: "120000", NewMode: "120000", OldTarget: "script.sh", NewTarget: "other.sh"}},
+		{Status: "M", Name: "script.sh", fileDetail: fileDetail{OldMode: "100644", NewMode: "100755", ModeOnly: true}},
+	}
+	if diff := cmp.Diff(wantJSON, got, cmp.AllowUnexported(statusJSONEntry{}, fileDetail{})); diff != "" {
+		t.Errorf("gg status --json (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatus_Template(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("a.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("a.txt", "something else\n"),
+		filesystem.Write("b.txt", dummyContent),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "status", "-T", `{status}:{path}\n`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "M:a.txt\n?:b.txt\n"
+	if got := string(out); got != want {
+		t.Errorf("gg status -T output:\n%s\nwant:\n%s", got, want)
+	}
+
+	for _, args := range [][]string{
+		{"status", "-T", "{status}", "--json"},
+		{"status", "-T", "{status}", "--staged"},
+		{"status", "-T", "{bogus}"},
+	} {
+		if _, err := env.gg(ctx, env.root.String(), args...); err == nil {
+			t.Errorf("gg %q did not return an error", args)
+		} else if !isUsage(err) {
+			t.Errorf("gg %q returned non-usage error: %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+)
+
+// A statusSummary is the state of the working copy as reported by
+// git status --porcelain=v2 --branch --show-stash.
+type statusSummary struct {
+	branch  statusBranch
+	stashes int
+	entries []git.StatusEntry
+	// scores is the similarity percentage of each renamed or copied
+	// file, keyed by the file's new name.
+	scores map[git.TopPath]int
+}
+
+// statusBranch describes HEAD and how the current branch compares to
+// its upstream.
+type statusBranch struct {
+	Name         string `json:"name,omitempty"`   // empty if HEAD is detached
+	Commit       string `json:"commit,omitempty"` // empty if there are no commits yet
+	Upstream     string `json:"upstream,omitempty"`
+	UpstreamGone bool   `json:"upstreamGone,omitempty"` // the upstream branch no longer exists
+	Ahead        int    `json:"ahead"`
+	Behind       int    `json:"behind"`
+}
+
+// readStatusSummary runs git status --porcelain=v2 for the files
+// matching pathspecs (or all files if empty).
+func readStatusSummary(ctx context.Context, g *git.Git, pathspecs []git.Pathspec) (*statusSummary, error) {
+	args := []string{"status", "--porcelain=v2", "-z", "-unormal", "--branch", "--"}
+	for _, p := range pathspecs {
+		args = append(args, p.String())
+	}
+	withStash := append([]string{args[0], "--show-stash"}, args[1:]...)
+	out, err := g.Output(ctx, withStash...)
+	if err != nil {
+		// --show-stash was added in Git 2.35. Try again without it.
+		var retryErr error
+		out, retryErr = g.Output(ctx, args...)
+		if retryErr != nil {
+			return nil, err
+		}
+	}
+	return parseStatusV2(out)
+}
+
+// parseStatusV2 parses the output of git status --porcelain=v2 -z.
+func parseStatusV2(out string) (*statusSummary, error) {
+	s := &statusSummary{scores: make(map[git.TopPath]int)}
+	records := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	for i := 0; i < len(records); i++ {
+		rec := records[i]
+		if rec == "" {
+			continue
+		}
+		switch rec[0] {
+		case '#':
+			if err := s.parseHeader(rec); err != nil {
+				return nil, err
+			}
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			fields := strings.SplitN(rec, " ", 9)
+			if len(fields) != 9 {
+				return nil, fmt.Errorf("parse status: bad entry %q", rec)
+			}
+			s.entries = append(s.entries, git.StatusEntry{
+				Code: statusV2Code(fields[1]),
+				Name: git.TopPath(fields[8]),
+			})
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path> NUL <origPath>
+			fields := strings.SplitN(rec, " ", 10)
+			if len(fields) != 10 || len(fields[8]) < 2 || i+1 >= len(records) {
+				return nil, fmt.Errorf("parse status: bad entry %q", rec)
+			}
+			score, err := strconv.Atoi(fields[8][1:])
+			if err != nil {
+				return nil, fmt.Errorf("parse status: bad score in %q", rec)
+			}
+			i++
+			ent := git.StatusEntry{
+				Code: statusV2Code(fields[1]),
+				Name: git.TopPath(fields[9]),
+				From: git.TopPath(records[i]),
+			}
+			s.entries = append(s.entries, ent)
+			s.scores[ent.Name] = score
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(rec, " ", 11)
+			if len(fields) != 11 {
+				return nil, fmt.Errorf("parse status: bad entry %q", rec)
+			}
+			s.entries = append(s.entries, git.StatusEntry{
+				Code: statusV2Code(fields[1]),
+				Name: git.TopPath(fields[10]),
+			})
+		case '?':
+			s.entries = append(s.entries, git.StatusEntry{
+				Code: git.StatusCode{'?', '?'},
+				Name: git.TopPath(strings.TrimPrefix(rec, "? ")),
+			})
+		case '!':
+			// Ignored files are only listed if asked for.
+		default:
+			return nil, fmt.Errorf("parse status: unknown entry %q", rec)
+		}
+	}
+	return s, nil
+}
+
+// parseHeader parses a "# ..." line of git status --porcelain=v2.
+func (s *statusSummary) parseHeader(rec string) error {
+	key, value, _ := strings.Cut(strings.TrimPrefix(rec, "# "), " ")
+	switch key {
+	case "branch.oid":
+		if value != "(initial)" {
+			s.branch.Commit = value
+		}
+	case "branch.head":
+		if value != "(detached)" {
+			s.branch.Name = value
+		}
+	case "branch.upstream":
+		s.branch.Upstream = value
+		// Git leaves out branch.ab if the upstream is gone.
+		s.branch.UpstreamGone = true
+	case "branch.ab":
+		s.branch.UpstreamGone = false
+		if _, err := fmt.Sscanf(value, "+%d -%d", &s.branch.Ahead, &s.branch.Behind); err != nil {
+			return fmt.Errorf("parse status: bad header %q", rec)
+		}
+	case "stash":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("parse status: bad header %q", rec)
+		}
+		s.stashes = n
+	}
+	return nil
+}
+
+// statusV2Code converts the XY field of git status --porcelain=v2, which
+// uses '.' for an unchanged side, to the codes of the short format.
+func statusV2Code(xy string) git.StatusCode {
+	var code git.StatusCode
+	for i := 0; i < len(code) && i < len(xy); i++ {
+		code[i] = xy[i]
+		if code[i] == '.' {
+			code[i] = ' '
+		}
+	}
+	return code
+}
+
+// countStashes formats a number of stash entries.
+func countStashes(n int) string {
+	if n == 1 {
+		return "1 stash"
+	}
+	return fmt.Sprintf("%d stashes", n)
+}
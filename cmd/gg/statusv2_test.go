@@ -0,0 +1,127 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseStatusV2(t *testing.T) {
+	const out = "# branch.oid 0123456789abcdef0123456789abcdef01234567\x00" +
+		"# branch.head main\x00" +
+		"# branch.upstream origin/main\x00" +
+		"# branch.ab +2 -1\x00" +
+		"# stash 3\x00" +
+		"1 .M N... 100644 100644 100644 0123456789abcdef0123456789abcdef01234567 0123456789abcdef0123456789abcdef01234567 has space.txt\x00" +
+		"2 R. N... 100644 100644 100644 0123456789abcdef0123456789abcdef01234567 0123456789abcdef0123456789abcdef01234567 R87 new.txt\x00old.txt\x00" +
+		"u UU N... 100644 100644 100644 100644 0123456789abcdef0123456789abcdef01234567 0123456789abcdef0123456789abcdef01234567 0123456789abcdef0123456789abcdef01234567 conflict.txt\x00" +
+		"? untracked.txt\x00"
+	got, err := parseStatusV2(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &statusSummary{
+		branch: statusBranch{
+			Name:     "main",
+			Commit:   "0123456789abcdef0123456789abcdef01234567",
+			Upstream: "origin/main",
+			Ahead:    2,
+			Behind:   1,
+		},
+		stashes: 3,
+		entries: []git.StatusEntry{
+			{Code: git.StatusCode{' ', 'M'}, Name: "has space.txt"},
+			{Code: git.StatusCode{'R', ' '}, Name: "new.txt", From: "old.txt"},
+			{Code: git.StatusCode{'U', 'U'}, Name: "conflict.txt"},
+			{Code: git.StatusCode{'?', '?'}, Name: "untracked.txt"},
+		},
+		scores: map[git.TopPath]int{"new.txt": 87},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(statusSummary{})); diff != "" {
+		t.Errorf("parseStatusV2(...) (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseStatusV2_UpstreamGone(t *testing.T) {
+	const out = "# branch.oid 0123456789abcdef0123456789abcdef01234567\x00" +
+		"# branch.head topic\x00" +
+		"# branch.upstream origin/topic\x00"
+	got, err := parseStatusV2(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.branch.UpstreamGone {
+		t.Error("branch.UpstreamGone = false; want true")
+	}
+	if got, want := describeStatusBranch(got), "on branch topic, upstream origin/topic is gone"; got != want {
+		t.Errorf("describeStatusBranch(...) = %q; want %q", got, want)
+	}
+}
+
+func TestStatus_Branch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.git.Output(ctx, "mv", "foo.txt", "bar.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "status", "-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "on branch main\nA bar.txt\n  foo.txt\nR foo.txt\n"
+	if got := string(out); got != want {
+		t.Errorf("gg status -b =\n%s\nwant:\n%s", got, want)
+	}
+
+	out, err = env.gg(ctx, env.root.String(), "status", "-b", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got statusBranchJSON
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Branch.Name != "main" {
+		t.Errorf("branch.name = %q; want \"main\"", got.Branch.Name)
+	}
+	if len(got.Files) == 0 || got.Files[0].Name != "bar.txt" || got.Files[0].Score != 100 {
+		t.Errorf("files = %+v; want first to be bar.txt with score 100", got.Files)
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"gg-scm.io/pkg/git"
+)
+
+// subprocessTimeoutSetting is the Git configuration variable that limits
+// how long each Git subprocess may run. A subsection names a Git
+// subcommand to give it its own limit, like gg.subprocessTimeout.fetch.
+const subprocessTimeoutSetting = "gg.subprocessTimeout"
+
+// promptWait is how long a Git subprocess may sit at what looks like a
+// prompt for input before gg stops it, when nobody can answer the prompt.
+const promptWait = 15 * time.Second
+
+// subprocessLimits bounds how long gg waits for Git subprocesses.
+// A nil *subprocessLimits has no limits.
+type subprocessLimits struct {
+	// timeout is the value of gg.subprocessTimeout, or zero for none.
+	timeout time.Duration
+	// interactive is true if the user can answer prompts that Git shows
+	// on stderr, because gg's stdin is a terminal.
+	interactive bool
+	// promptWait is how long to wait at a prompt that nobody can answer.
+	promptWait time.Duration
+	// cfg is the configuration that per-subcommand timeouts are read
+	// from. It may be nil.
+	cfg *git.Config
+	// warn is called with invalid per-subcommand timeouts. It may be nil.
+	warn func(error)
+
+	mu sync.Mutex
+	// commands maps Git subcommands to their own timeouts.
+	commands map[string]time.Duration
+	// checked holds the subcommands whose settings have been read from cfg.
+	checked map[string]bool
+}
+
+// load reads the gg.subprocessTimeout setting from cfg, which may be
+// nil, and keeps cfg for reading the per-subcommand settings later.
+func (l *subprocessLimits) load(cfg *git.Config) error {
+	l.mu.Lock()
+	l.cfg = cfg
+	l.checked = nil
+	l.mu.Unlock()
+	if cfg == nil {
+		return nil
+	}
+	v := cfg.Value(subprocessTimeoutSetting)
+	if v == "" {
+		return nil
+	}
+	d, err := parseTimeout(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", subprocessTimeoutSetting, err)
+	}
+	l.timeout = d
+	return nil
+}
+
+// parseTimeout parses a duration like "90s" or "5m", or a number of
+// seconds. Zero means no timeout.
+func parseTimeout(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	var d time.Duration
+	var err error
+	if n, ok := parseSeconds(s); ok {
+		d = time.Duration(n) * time.Second
+	} else if d, err = time.ParseDuration(s); err != nil {
+		return 0, fmt.Errorf("invalid timeout %q (use a duration like 90s or 5m)", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid timeout %q (must not be negative)", s)
+	}
+	return d, nil
+}
+
+func parseSeconds(s string) (int64, bool) {
+	if s == "" || len(s) > 9 {
+		return 0, false
+	}
+	var n int64
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int64(c-'0')
+	}
+	return n, true
+}
+
+// timeoutFor returns the timeout for the Git subcommand and the name of
+// the setting it came from.
+func (l *subprocessLimits) timeoutFor(subcommand string) (time.Duration, string) {
+	subcommand = strings.ToLower(subcommand)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.checked[subcommand] {
+		l.readCommandTimeout(subcommand)
+	}
+	if d, ok := l.commands[subcommand]; ok {
+		return d, subprocessTimeoutSetting + "." + subcommand
+	}
+	return l.timeout, subprocessTimeoutSetting
+}
+
+// readCommandTimeout reads the subcommand's own timeout from l.cfg.
+// The caller must hold l.mu.
+func (l *subprocessLimits) readCommandTimeout(subcommand string) {
+	if l.checked == nil {
+		l.checked = make(map[string]bool)
+	}
+	l.checked[subcommand] = true
+	if l.cfg == nil || subcommand == "" {
+		return
+	}
+	setting := subprocessTimeoutSetting + "." + subcommand
+	v := l.cfg.Value(setting)
+	if v == "" {
+		return
+	}
+	d, err := parseTimeout(v)
+	if err != nil {
+		if l.warn != nil {
+			l.warn(fmt.Errorf("%s: %w", setting, err))
+		}
+		return
+	}
+	if l.commands == nil {
+		l.commands = make(map[string]time.Duration)
+	}
+	l.commands[subcommand] = d
+}
+
+// run calls runGit with a context that is canceled if the Git subprocess
+// takes longer than its timeout or waits at a prompt that nobody can
+// answer. visible is true if the subprocess's stderr is shown to the user.
+func (l *subprocessLimits) run(ctx context.Context, invoke *git.Invocation, visible bool, runGit func(context.Context, *git.Invocation) error) error {
+	subcommand := gitSubcommand(invoke.Args)
+	timeout, setting := l.timeoutFor(subcommand)
+	wait := l.promptWait
+	if visible && l.interactive {
+		wait = 0
+	}
+	if timeout == 0 && wait == 0 {
+		return runGit(ctx, invoke)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, timeout, errSubprocessTimeout)
+		defer cancelTimeout()
+	}
+	w := &promptWatcher{w: invoke.Stderr}
+	if w.w == nil {
+		w.w = io.Discard
+	}
+	invoke2 := new(git.Invocation)
+	*invoke2 = *invoke
+	invoke2.Stderr = w
+	done := make(chan struct{})
+	defer close(done)
+	if wait > 0 {
+		go func() {
+			tick := time.NewTicker(wait / 5)
+			defer tick.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case now := <-tick.C:
+					if w.stuck(now, wait) {
+						cancel(errStuckAtPrompt)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	err := runGit(ctx, invoke2)
+	if err == nil {
+		return nil
+	}
+	e := &subprocessLimitError{
+		name:   "git " + subcommand,
+		prompt: w.prompt(),
+		err:    err,
+	}
+	switch cause := context.Cause(ctx); cause {
+	case errSubprocessTimeout:
+		e.timeout = timeout
+		e.setting = setting
+	case errStuckAtPrompt:
+	default:
+		return err
+	}
+	return e
+}
+
+// gitSubcommand returns the Git subcommand in a Git command line,
+// skipping any global options like "-c name=value".
+func gitSubcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "-c" || a == "-C":
+			i++
+		case !strings.HasPrefix(a, "-"):
+			return a
+		}
+	}
+	return ""
+}
+
+var (
+	errSubprocessTimeout = errors.New("subprocess timed out")
+	errStuckAtPrompt     = errors.New("subprocess waiting for input")
+)
+
+// A subprocessLimitError is returned when gg stops a Git subprocess
+// because it ran too long or was waiting for input.
+type subprocessLimitError struct {
+	name    string        // like "git fetch"
+	timeout time.Duration // zero if stopped at a prompt
+	setting string        // configuration variable that set timeout
+	prompt  string        // last line of output if it looked like a prompt
+	err     error
+}
+
+func (e *subprocessLimitError) Error() string {
+	sb := new(strings.Builder)
+	if e.timeout > 0 {
+		fmt.Fprintf(sb, "%s did not finish within %v (%s)", e.name, e.timeout, e.setting)
+		if e.prompt != "" {
+			fmt.Fprintf(sb, "; it was waiting for input: %q", e.prompt)
+		}
+	} else {
+		fmt.Fprintf(sb, "%s stopped: it was waiting for input that gg can't give it: %q", e.name, e.prompt)
+	}
+	if e.prompt != "" {
+		sb.WriteString("\nFor SSH, load your key into ssh-agent or set GIT_SSH_COMMAND " +
+			"(GIT_SSH_COMMAND='ssh -o BatchMode=yes' fails instead of prompting). " +
+			"For HTTPS, set up a credential helper (see git-credential(1)).")
+	}
+	return sb.String()
+}
+
+func (e *subprocessLimitError) Unwrap() error {
+	return e.err
+}
+
+// promptWatcher passes output through to w and remembers when it was
+// last written and the last line if it is unfinished.
+type promptWatcher struct {
+	w io.Writer
+
+	mu      sync.Mutex
+	partial []byte // output after the last newline
+	written time.Time
+}
+
+func (pw *promptWatcher) Write(p []byte) (int, error) {
+	pw.mu.Lock()
+	if i := bytes.LastIndexAny(p, "\r\n"); i >= 0 {
+		pw.partial = append(pw.partial[:0], p[i+1:]...)
+	} else if len(pw.partial) < 1024 {
+		pw.partial = append(pw.partial, p...)
+	}
+	pw.written = time.Now()
+	pw.mu.Unlock()
+	return pw.w.Write(p)
+}
+
+// prompt returns the unfinished last line of output if it looks like a
+// prompt for input.
+func (pw *promptWatcher) prompt() string {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	if isInputPrompt(string(pw.partial)) {
+		return strings.TrimSpace(string(pw.partial))
+	}
+	return ""
+}
+
+// stuck reports whether the output has ended with a prompt for at least
+// the given duration.
+func (pw *promptWatcher) stuck(now time.Time, wait time.Duration) bool {
+	pw.mu.Lock()
+	since := now.Sub(pw.written)
+	pw.mu.Unlock()
+	return since >= wait && pw.prompt() != ""
+}
+
+// isInputPrompt reports whether an unfinished line of output looks like a
+// request for a password, passphrase, user name, or confirmation.
+func isInputPrompt(line string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.HasSuffix(line, ":") && !strings.HasSuffix(line, "?") {
+		return false
+	}
+	lower := strings.ToLower(line)
+	for _, word := range []string{"passphrase", "password", "username", "yes/no"} {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gg-scm.io/pkg/git"
+)
+
+func TestParseTimeout(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{s: "0", want: 0},
+		{s: "30", want: 30 * time.Second},
+		{s: "90s", want: 90 * time.Second},
+		{s: "5m", want: 5 * time.Minute},
+		{s: "-1s", wantErr: true},
+		{s: "soon", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := parseTimeout(test.s)
+		if got != test.want || (err != nil) != test.wantErr {
+			t.Errorf("parseTimeout(%q) = %v, %v; want %v, error = %t", test.s, got, err, test.want, test.wantErr)
+		}
+	}
+}
+
+func TestGitSubcommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"fetch", "origin"}, want: "fetch"},
+		{args: []string{"-c", "sequence.editor=true", "rebase", "-i"}, want: "rebase"},
+		{args: []string{"--no-pager", "log"}, want: "log"},
+		{args: nil, want: ""},
+	}
+	for _, test := range tests {
+		if got := gitSubcommand(test.args); got != test.want {
+			t.Errorf("gitSubcommand(%q) = %q; want %q", test.args, got, test.want)
+		}
+	}
+}
+
+func TestIsInputPrompt(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{line: "Enter passphrase for key '/home/me/.ssh/id_ed25519': ", want: true},
+		{line: "Password for 'https://me@example.com': ", want: true},
+		{line: "Username for 'https://example.com': ", want: true},
+		{line: "Are you sure you want to continue connecting (yes/no/[fingerprint])? ", want: true},
+		{line: "Receiving objects:  50% (5/10)", want: false},
+		{line: "remote: Counting objects:", want: false},
+	}
+	for _, test := range tests {
+		if got := isInputPrompt(test.line); got != test.want {
+			t.Errorf("isInputPrompt(%q) = %t; want %t", test.line, got, test.want)
+		}
+	}
+}
+
+func TestSubprocessLimits(t *testing.T) {
+	t.Parallel()
+	// blockAfter returns a fake Git that writes output to stderr and then
+	// waits until it is stopped.
+	blockAfter := func(output string) func(context.Context, *git.Invocation) error {
+		return func(ctx context.Context, invoke *git.Invocation) error {
+			io.WriteString(invoke.Stderr, output)
+			<-ctx.Done()
+			return errors.New("signal: terminated")
+		}
+	}
+	invoke := &git.Invocation{Args: []string{"fetch", "origin"}, Stderr: io.Discard}
+
+	t.Run("Timeout", func(t *testing.T) {
+		l := &subprocessLimits{
+			timeout:  time.Hour,
+			commands: map[string]time.Duration{"fetch": 10 * time.Millisecond},
+		}
+		err := l.run(context.Background(), invoke, true, blockAfter("Receiving objects: 1%\r"))
+		var e *subprocessLimitError
+		if !errors.As(err, &e) {
+			t.Fatalf("run(...) = %v; want subprocess limit error", err)
+		}
+		if got := err.Error(); !strings.Contains(got, "gg.subprocessTimeout.fetch") || strings.Contains(got, "ssh-agent") {
+			t.Errorf("error = %q; want to name gg.subprocessTimeout.fetch without prompt advice", got)
+		}
+	})
+	t.Run("Prompt", func(t *testing.T) {
+		l := &subprocessLimits{promptWait: 50 * time.Millisecond}
+		err := l.run(context.Background(), invoke, false, blockAfter("Enter passphrase for key 'id_rsa': "))
+		var e *subprocessLimitError
+		if !errors.As(err, &e) {
+			t.Fatalf("run(...) = %v; want subprocess limit error", err)
+		}
+		if got := err.Error(); !strings.Contains(got, "Enter passphrase") || !strings.Contains(got, "GIT_SSH_COMMAND") {
+			t.Errorf("error = %q; want to show prompt and suggest GIT_SSH_COMMAND", got)
+		}
+	})
+	t.Run("InteractiveVisiblePrompt", func(t *testing.T) {
+		l := &subprocessLimits{interactive: true, promptWait: 10 * time.Millisecond}
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		err := l.run(ctx, invoke, true, blockAfter("Password: "))
+		if errors.As(err, new(*subprocessLimitError)) {
+			t.Errorf("run(...) = %v; want prompt left for the user to answer", err)
+		}
+	})
+	t.Run("Success", func(t *testing.T) {
+		l := &subprocessLimits{timeout: time.Hour, promptWait: time.Hour}
+		err := l.run(context.Background(), invoke, false, func(ctx context.Context, invoke *git.Invocation) error {
+			return nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
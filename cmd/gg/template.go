@@ -0,0 +1,52 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "gg-scm.io/tool/internal/template"
+
+// templateHelp is the help text shared by the commands that take -T.
+const templateHelp = `
+
+	An expression in a template is a keyword in braces, optionally
+	followed by filters separated by ` + "`|`" + `. The filters are:
+
+	  short        the first 12 characters of a hash
+	  firstline    the first line of the text
+	  strip        the text without leading and trailing whitespace
+	  lower        the text in lowercase
+	  upper        the text in uppercase
+	  person       the name part of an author
+	  email        the email part of an author
+	  date         a date like "Mon Jan 02 15:04:05 2006 -0700"
+	  isodate      a date like "2006-01-02 15:04 -0700"
+	  shortdate    a date like "2006-01-02"
+	  rfc3339date  a date like "2006-01-02T15:04:05-07:00"
+
+	` + "`\\n`" + ` and ` + "`\\t`" + ` in a template are a newline and a tab.`
+
+// parseTemplate parses a template given on the command line and checks
+// that it only uses the given keywords.
+func parseTemplate(s string, keywords []string) (*template.Template, error) {
+	tmpl, err := template.Parse(s)
+	if err != nil {
+		return nil, usagef("%v", err)
+	}
+	if err := tmpl.Check(keywords); err != nil {
+		return nil, usagef("template: %v", err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/sigterm"
+)
+
+const textconvHelp = `
+
+	Files whose ` + "`diff`" + ` attribute in ` + "`.gitattributes`" + ` names a driver
+	with a ` + "`diff.<driver>.textconv`" + ` command are shown as that command's
+	output, like the text of a word processor document or the metadata of
+	an image. ` + "`--no-textconv`" + ` shows the files' contents instead.`
+
+// textconvCommand returns the textconv command that Git would use for
+// the file at path (relative to the top of the working copy) or the
+// empty string if there is none.
+func textconvCommand(ctx context.Context, g *git.Git, cfg *git.Config, path git.TopPath) (string, error) {
+	top, err := g.WorkTree(ctx)
+	if err != nil {
+		// Attributes can't be checked without a working copy.
+		return "", nil
+	}
+	out, err := g.WithDir(top).Output(ctx, "check-attr", "-z", "diff", "--", path.String())
+	if err != nil {
+		return "", err
+	}
+	// Output is of the form "<path> NUL diff NUL <value> NUL".
+	fields := strings.Split(out, "\x00")
+	if len(fields) < 3 {
+		return "", nil
+	}
+	switch driver := fields[2]; driver {
+	case "set", "unset", "unspecified":
+		return "", nil
+	default:
+		return cfg.Value("diff." + driver + ".textconv"), nil
+	}
+}
+
+// runTextconv copies the output of the textconv command line for the
+// content in r to w. Like Git, it runs the command with the name of a
+// temporary file holding the content.
+func runTextconv(ctx context.Context, cc *cmdContext, line string, r io.Reader, w io.Writer) error {
+	f, err := os.CreateTemp(cc.tempDir, "gg-textconv-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	cmd, err := bashCommand(cc.git.Exe(), line+` "$@"`)
+	if err != nil {
+		return err
+	}
+	cmd.Args = append(cmd.Args, "textconv", f.Name())
+	cmd.Dir = cc.dir
+	cmd.Env = cc.env
+	if len(cmd.Env) == 0 {
+		cmd.Env = []string{} // force empty
+	}
+	stderr := new(bytes.Buffer)
+	cmd.Stdout = w
+	cmd.Stderr = stderr
+	if err := sigterm.Run(ctx, cmd); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("textconv %s: %w\n%s", line, err, msg)
+		}
+		return fmt.Errorf("textconv %s: %w", line, err)
+	}
+	return nil
+}
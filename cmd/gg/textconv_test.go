@@ -0,0 +1,103 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestTextconv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("textconv command uses a POSIX shell")
+	}
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "config", "diff.upper.textconv", "tr a-z A-Z <"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(
+		filesystem.Write(".gitattributes", "*.doc diff=upper\n"),
+		filesystem.Write("notes.doc", "old text\n"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, ".gitattributes", "notes.doc"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.NewBranch(ctx, "topic", git.BranchOptions{Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("notes.doc", "new text\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "notes.doc"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Cat", func(t *testing.T) {
+		out, err := env.gg(ctx, env.root.String(), "cat", "--textconv", "notes.doc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(out), "NEW TEXT\n"; got != want {
+			t.Errorf("gg cat --textconv = %q; want %q", got, want)
+		}
+		// Not a terminal, so the file is printed as is.
+		out, err = env.gg(ctx, env.root.String(), "cat", "notes.doc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(out), "new text\n"; got != want {
+			t.Errorf("gg cat = %q; want %q", got, want)
+		}
+	})
+	t.Run("DiffFileRef", func(t *testing.T) {
+		out, err := env.gg(ctx, env.root.String(), "diff", "main:notes.doc", "topic:notes.doc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(out); !strings.Contains(got, "-OLD TEXT") || !strings.Contains(got, "+NEW TEXT") {
+			t.Errorf("gg diff main:notes.doc topic:notes.doc =\n%s\nwant converted text", got)
+		}
+		out, err = env.gg(ctx, env.root.String(), "diff", "--no-textconv", "main:notes.doc", "topic:notes.doc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(out); !strings.Contains(got, "-old text") || !strings.Contains(got, "+new text") {
+			t.Errorf("gg diff --no-textconv main:notes.doc topic:notes.doc =\n%s\nwant file contents", got)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gg-scm.io/tool/internal/terminal"
+)
+
+// encryptedTokenHeader is the first line of a token file whose token is
+// encrypted with a passphrase.
+const encryptedTokenHeader = "gg encrypted token v1"
+
+// tokenKeyIterations is the number of PBKDF2-HMAC-SHA256 rounds used to
+// derive a token key from a passphrase.
+const tokenKeyIterations = 600000
+
+// tokenKeyCacheFile is the slash-separated path of the file inside the gg
+// runtime directory that holds the unlocked token key for the rest of the
+// login session.
+const tokenKeyCacheFile = "token_key"
+
+// A tokenKey is an AES-256 key derived from the user's passphrase.
+type tokenKey struct {
+	salt []byte
+	key  []byte
+}
+
+// deriveTokenKey derives the key for the passphrase and salt.
+func deriveTokenKey(passphrase, salt []byte) *tokenKey {
+	return &tokenKey{
+		salt: salt,
+		key:  pbkdf2SHA256(passphrase, salt, tokenKeyIterations, 32),
+	}
+}
+
+// newTokenKey derives a key for the passphrase with a new random salt.
+func newTokenKey(passphrase []byte) (*tokenKey, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	return deriveTokenKey(passphrase, salt), nil
+}
+
+// pbkdf2SHA256 implements PBKDF2 from RFC 8018 with HMAC-SHA256 as the
+// pseudorandom function.
+func pbkdf2SHA256(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var dk []byte
+	u := make([]byte, 0, sha256.Size)
+	t := make([]byte, sha256.Size)
+	var blockIndex [4]byte
+	for i := uint32(1); len(dk) < keyLen; i++ {
+		binary.BigEndian.PutUint32(blockIndex[:], i)
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(blockIndex[:])
+		u = prf.Sum(u[:0])
+		copy(t, u)
+		for n := 1; n < iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		dk = append(dk, t...)
+	}
+	return dk[:keyLen]
+}
+
+func (k *tokenKey) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext and returns the nonce followed by the ciphertext.
+func (k *tokenKey) seal(plaintext []byte) ([]byte, error) {
+	aead, err := k.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the output of seal.
+func (k *tokenKey) open(sealed []byte) ([]byte, error) {
+	aead, err := k.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("encrypted token too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("wrong passphrase for GitHub tokens")
+	}
+	return plaintext, nil
+}
+
+// An encryptedToken is the content of a token file written by
+// gg auth encrypt. The login is stored in plain text so that gg auth can
+// list accounts without asking for the passphrase.
+type encryptedToken struct {
+	salt   []byte
+	login  string
+	sealed []byte
+}
+
+// isEncryptedToken reports whether data is the content of a token file
+// with an encrypted token.
+func isEncryptedToken(data []byte) bool {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	return string(bytes.TrimSpace(line)) == encryptedTokenHeader
+}
+
+func parseEncryptedToken(data []byte) (*encryptedToken, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	tok := new(encryptedToken)
+	for _, line := range lines[1:] {
+		k, v, _ := strings.Cut(strings.TrimSpace(line), " ")
+		var err error
+		switch k {
+		case "salt":
+			tok.salt, err = base64.StdEncoding.DecodeString(v)
+		case "login":
+			tok.login = v
+		case "data":
+			tok.sealed, err = base64.StdEncoding.DecodeString(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse encrypted token: %s: %w", k, err)
+		}
+	}
+	if len(tok.salt) == 0 || len(tok.sealed) == 0 {
+		return nil, errors.New("parse encrypted token: missing salt or data")
+	}
+	return tok, nil
+}
+
+func (tok *encryptedToken) marshal() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString(encryptedTokenHeader + "\n")
+	buf.WriteString("salt " + base64.StdEncoding.EncodeToString(tok.salt) + "\n")
+	if tok.login != "" {
+		buf.WriteString("login " + tok.login + "\n")
+	}
+	buf.WriteString("data " + base64.StdEncoding.EncodeToString(tok.sealed) + "\n")
+	return buf.Bytes()
+}
+
+// unlockToken decrypts tok with the key cached for the login session or,
+// failing that, with a passphrase read from the terminal. A key derived
+// from a passphrase is cached for the rest of the session.
+func unlockToken(cc *cmdContext, tok *encryptedToken) ([]byte, *tokenKey, error) {
+	if key := readCachedTokenKey(cc.xdgDirs); key != nil && bytes.Equal(key.salt, tok.salt) {
+		if plaintext, err := key.open(tok.sealed); err == nil {
+			return plaintext, key, nil
+		}
+	}
+	passphrase, err := readPassphrase(cc, "Passphrase for GitHub tokens: ")
+	if err != nil {
+		return nil, nil, err
+	}
+	key := deriveTokenKey(passphrase, tok.salt)
+	plaintext, err := key.open(tok.sealed)
+	if err != nil {
+		return nil, nil, err
+	}
+	cacheTokenKey(cc.xdgDirs, key)
+	return plaintext, key, nil
+}
+
+type cachedTokenKey struct {
+	Salt []byte `json:"salt"`
+	Key  []byte `json:"key"`
+}
+
+// readCachedTokenKey returns the key unlocked earlier in the login
+// session or nil if there isn't one.
+func readCachedTokenKey(x *xdgDirs) *tokenKey {
+	data, err := x.readRuntime(tokenKeyCacheFile)
+	if err != nil {
+		return nil
+	}
+	var cached cachedTokenKey
+	if err := json.Unmarshal(data, &cached); err != nil || len(cached.Key) == 0 {
+		return nil
+	}
+	return &tokenKey{salt: cached.Salt, key: cached.Key}
+}
+
+// cacheTokenKey saves the key in the runtime directory, if there is one,
+// so that gg doesn't ask for the passphrase again in the login session.
+func cacheTokenKey(x *xdgDirs, key *tokenKey) {
+	data, err := json.Marshal(&cachedTokenKey{Salt: key.salt, Key: key.key})
+	if err != nil {
+		return
+	}
+	x.writeRuntimeSecret(tokenKeyCacheFile, data)
+}
+
+// readPassphrase prints the prompt to stderr and reads a line from the
+// terminal without echoing it.
+func readPassphrase(cc *cmdContext, prompt string) (_ []byte, err error) {
+	f, ok := cc.stdin.(*os.File)
+	if !ok || !terminal.IsTerminal(f) {
+		return nil, errors.New("GitHub tokens are encrypted; run gg in a terminal to enter the passphrase")
+	}
+	fmt.Fprint(cc.stderr, prompt)
+	restore, err := terminal.MakeRaw(f)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if restoreErr := restore(); err == nil && restoreErr != nil {
+			err = restoreErr
+		}
+		fmt.Fprintln(cc.stderr)
+	}()
+	var passphrase []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := f.Read(buf)
+		if n == 0 {
+			if err == io.EOF {
+				return nil, errors.New("no passphrase entered")
+			}
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		switch c := buf[0]; c {
+		case '\r', '\n':
+			return passphrase, nil
+		case 0x03, 0x04:
+			return nil, errors.New("no passphrase entered")
+		case 0x7f, '\b':
+			if len(passphrase) > 0 {
+				passphrase = passphrase[:len(passphrase)-1]
+			}
+		default:
+			passphrase = append(passphrase, c)
+		}
+	}
+}
+
+// readNewPassphrase asks for a new passphrase twice.
+func readNewPassphrase(cc *cmdContext) ([]byte, error) {
+	passphrase, err := readPassphrase(cc, "New passphrase for GitHub tokens: ")
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+	again, err := readPassphrase(cc, "Enter the passphrase again: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(passphrase, again) {
+		return nil, errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// Test vectors from RFC 7914 Section 11.
+	tests := []struct {
+		password string
+		salt     string
+		iter     int
+		want     string
+	}{
+		{
+			password: "passwd",
+			salt:     "salt",
+			iter:     1,
+			want:     "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		{
+			password: "Password",
+			salt:     "NaCl",
+			iter:     80000,
+			want:     "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	}
+	for _, test := range tests {
+		got := hex.EncodeToString(pbkdf2SHA256([]byte(test.password), []byte(test.salt), test.iter, 64))
+		if got != test.want {
+			t.Errorf("pbkdf2SHA256(%q, %q, %d, 64) = %s; want %s", test.password, test.salt, test.iter, got, test.want)
+		}
+	}
+}
+
+func TestEncryptedToken(t *testing.T) {
+	t.Parallel()
+	key, err := newTokenKey([]byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := key.seal([]byte("xyzzy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := (&encryptedToken{salt: key.salt, login: "octocat", sealed: sealed}).marshal()
+	if !isEncryptedToken(data) {
+		t.Fatalf("isEncryptedToken(%q) = false", data)
+	}
+	if bytes.Contains(data, []byte("xyzzy")) {
+		t.Errorf("encrypted token file %q contains token", data)
+	}
+	tok, err := parseEncryptedToken(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.login != "octocat" {
+		t.Errorf("login = %q; want \"octocat\"", tok.login)
+	}
+	got, err := deriveTokenKey([]byte("correct horse"), tok.salt).open(tok.sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "xyzzy" {
+		t.Errorf("decrypted token = %q; want \"xyzzy\"", got)
+	}
+	if _, err := deriveTokenKey([]byte("battery staple"), tok.salt).open(tok.sealed); err == nil {
+		t.Error("decrypting with the wrong passphrase succeeded")
+	}
+	if isEncryptedToken([]byte("xyzzy\noctocat\n")) {
+		t.Error("isEncryptedToken(plain text token) = true")
+	}
+}
+
+func TestAuthEncrypted(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.environ = append(env.environ, "XDG_RUNTIME_DIR="+env.topDir.FromSlash("run"))
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.writeGitHubAuth([]byte("xyzzy\noctocat\n")); err != nil {
+		t.Fatal(err)
+	}
+	key, err := newTokenKey([]byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := key.seal([]byte("plugh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok := &encryptedToken{salt: key.salt, login: "octo-work", sealed: sealed}
+	err = env.topDir.Apply(filesystem.Write("xdgconfig/gg/github_accounts/work", string(tok.marshal())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.gg(ctx, env.root.String(), "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "* default  octocat\n  work     octo-work  (encrypted)\n"; got != want {
+		t.Errorf("gg auth =\n%s\nwant:\n%s", got, want)
+	}
+
+	// Without a terminal or an unlocked key, gg can't get the passphrase.
+	if _, err := env.gg(ctx, env.root.String(), "auth", "decrypt"); err == nil {
+		t.Error("gg auth decrypt succeeded while locked without a terminal")
+	}
+	if _, err := env.gg(ctx, env.root.String(), "auth", "encrypt"); err == nil {
+		t.Error("gg auth encrypt succeeded while locked without a terminal")
+	}
+	plain, err := env.topDir.ReadFile("xdgconfig/gg/github_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain != "xyzzy\noctocat\n" {
+		t.Errorf("github_token = %q after failed encrypt; want unchanged", plain)
+	}
+
+	// Once unlocked for the session, encrypt uses the same key.
+	x := newXDGDirs([]string{"XDG_RUNTIME_DIR=" + env.topDir.FromSlash("run")})
+	cacheTokenKey(x, key)
+	if _, err := env.gg(ctx, env.root.String(), "auth", "encrypt"); err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := env.topDir.ReadFile("xdgconfig/gg/github_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncryptedToken([]byte(encrypted)) || strings.Contains(encrypted, "xyzzy") {
+		t.Errorf("github_token = %q after encrypt; want encrypted", encrypted)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "auth", "decrypt"); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"xdgconfig/gg/github_token":         "xyzzy\noctocat\n",
+		"xdgconfig/gg/github_accounts/work": "plugh\nocto-work\n",
+	} {
+		got, err := env.topDir.ReadFile(name)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s = %q after decrypt; want %q", name, got, want)
+		}
+	}
+	if _, err := x.readRuntime(tokenKeyCacheFile); !os.IsNotExist(err) {
+		t.Errorf("unlocked key still cached after decrypt (err = %v)", err)
+	}
+}
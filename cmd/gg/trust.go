@@ -0,0 +1,139 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const trustSynopsis = "allow Git to use a repository owned by another user"
+
+func trust(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg trust [-y] [DIR]", trustSynopsis+`
+
+	Git refuses to work in a repository that is owned by a different user
+	than the one running it, since the repository's configuration could
+	run commands as you. This is common on shared machines and in
+	containers where the repository is mounted from the host.
+
+	`+"`trust`"+` adds the repository containing DIR (or the current
+	directory) to the `+"`safe.directory`"+` setting in your global Git
+	configuration after asking for confirmation. Only trust repositories
+	whose owner you trust.`)
+	yes := f.Bool("y", false, "don't ask for confirmation")
+	f.Alias("y", "yes")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if f.NArg() > 1 {
+		return usagef("can only trust one repository at a time")
+	}
+	g := cc.git
+	if f.NArg() == 1 {
+		g = cc.git.WithDir(cc.abs(f.Arg(0)))
+	}
+	dir, err := untrustedRepositoryDir(ctx, g)
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		_, err := fmt.Fprintln(cc.stdout, "repository is already trusted")
+		return err
+	}
+	if !*yes {
+		ok, err := confirm(cc, fmt.Sprintf("Trust the repository at %s?", dir))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("repository not trusted")
+		}
+	}
+	if err := g.Run(ctx, "config", "--global", "--add", "safe.directory", dir); err != nil {
+		return fmt.Errorf("trust %s: %w", dir, err)
+	}
+	_, err = fmt.Fprintf(cc.stdout, "added %s to safe.directory in your global Git configuration\n", dir)
+	return err
+}
+
+// untrustedRepositoryDir returns the directory that Git's ownership
+// check refuses to use, or the empty string if Git accepts the
+// repository.
+func untrustedRepositoryDir(ctx context.Context, g *git.Git) (string, error) {
+	_, err := g.GitDir(ctx)
+	if err == nil {
+		return "", nil
+	}
+	if dir := dubiousOwnershipDir(err); dir != "" {
+		return dir, nil
+	}
+	return "", err
+}
+
+var dubiousOwnershipPattern = regexp.MustCompile(`detected dubious ownership in repository at '([^']+)'`)
+
+// dubiousOwnershipDir returns the repository named in Git's error for a
+// repository that fails its ownership check. It returns the empty string
+// if err is not such an error.
+func dubiousOwnershipDir(err error) string {
+	if err == nil {
+		return ""
+	}
+	m := dubiousOwnershipPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// explainGitError replaces Git's error for a repository that fails its
+// ownership check with an explanation that mentions gg trust.
+// Other errors are returned unchanged.
+func explainGitError(err error) error {
+	dir := dubiousOwnershipDir(err)
+	if dir == "" {
+		return err
+	}
+	return fmt.Errorf("git refuses to use the repository at %s because it is owned by another user.\n"+
+		"If you trust the owner, run `gg trust` to allow it", dir)
+}
+
+// confirm asks the user a yes or no question on stderr and reads the
+// answer from stdin. Anything other than "y" or "yes" is a no.
+func confirm(cc *cmdContext, question string) (bool, error) {
+	if _, err := fmt.Fprintf(cc.stderr, "%s [y/N] ", question); err != nil {
+		return false, err
+	}
+	answer, err := bufio.NewReader(cc.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDubiousOwnershipDir(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: nil, want: ""},
+		{err: errors.New("git rev-parse: exit status 128"), want: ""},
+		{
+			err: errors.New("git rev-parse: fatal: detected dubious ownership in repository at '/srv/my repo'\n" +
+				"To add an exception for this directory, call:\n\n" +
+				"\tgit config --global --add safe.directory '/srv/my repo'"),
+			want: "/srv/my repo",
+		},
+	}
+	for _, test := range tests {
+		if got := dubiousOwnershipDir(test.err); got != test.want {
+			t.Errorf("dubiousOwnershipDir(%v) = %q; want %q", test.err, got, test.want)
+		}
+	}
+}
+
+func TestTrust(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repo"); err != nil {
+		t.Fatal(err)
+	}
+	// Have Git act as if the repository belonged to another user.
+	env.environ = append(env.environ, "GIT_TEST_ASSUME_DIFFERENT_OWNER=1")
+	repoDir := env.root.FromSlash("repo")
+
+	_, err = env.gg(ctx, repoDir, "status")
+	if err == nil {
+		t.Skip("git does not support GIT_TEST_ASSUME_DIFFERENT_OWNER")
+	}
+	if !strings.Contains(err.Error(), "gg trust") {
+		t.Errorf("gg status error = %v; want to mention gg trust", err)
+	}
+
+	if _, err := env.gg(ctx, repoDir, "trust", "-y"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, repoDir, "status"); err != nil {
+		t.Errorf("gg status after gg trust: %v", err)
+	}
+	out, err := env.gg(ctx, repoDir, "trust", "-y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "already trusted") {
+		t.Errorf("second gg trust output = %q; want to say already trusted", out)
+	}
+}
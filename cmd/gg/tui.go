@@ -0,0 +1,232 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gg-scm.io/tool/internal/terminal"
+)
+
+// tuiScreen is a full-screen terminal user interface, as used by gg view
+// and gg commit --tui.
+type tuiScreen struct {
+	stdin   io.Reader
+	stdout  io.Writer
+	restore func() error // restores the terminal after start
+}
+
+// newTUIScreen returns a screen that draws to cc.stdout. It returns an
+// error if cc.stdout is not a terminal.
+func newTUIScreen(cc *cmdContext) (*tuiScreen, error) {
+	if !terminal.IsTerminal(cc.stdout) {
+		return nil, errors.New("requires a terminal")
+	}
+	return &tuiScreen{stdin: cc.stdin, stdout: cc.stdout}, nil
+}
+
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	exitAltScreen  = "\x1b[?25h\x1b[?1049l"
+)
+
+// start switches the terminal to raw mode and the alternate screen.
+func (s *tuiScreen) start() error {
+	restore, err := terminal.MakeRaw(s.stdin)
+	if err != nil {
+		return err
+	}
+	s.restore = restore
+	_, err = fmt.Fprint(s.stdout, enterAltScreen)
+	return err
+}
+
+// stop undoes start.
+func (s *tuiScreen) stop() error {
+	_, err := fmt.Fprint(s.stdout, exitAltScreen)
+	if restoreErr := s.restore(); err == nil {
+		err = restoreErr
+	}
+	return err
+}
+
+// size returns the number of columns and rows on the screen.
+func (s *tuiScreen) size() (width, height int, err error) {
+	return terminal.Size(s.stdout)
+}
+
+// draw replaces the screen's contents with the given lines.
+func (s *tuiScreen) draw(lines []string) error {
+	buf := new(bytes.Buffer)
+	buf.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			buf.WriteString("\r\n")
+		}
+		buf.WriteString(line)
+		buf.WriteString("\x1b[K")
+	}
+	buf.WriteString("\x1b[J")
+	_, err := s.stdout.Write(buf.Bytes())
+	return err
+}
+
+// readKey waits for a key press. key is the result of parseKey and text
+// is the printable text that was typed or pasted, if any.
+func (s *tuiScreen) readKey() (key, text string, err error) {
+	buf := make([]byte, 256)
+	n, err := s.stdin.Read(buf)
+	if n == 0 && err != nil {
+		return "", "", err
+	}
+	return parseKey(buf[:n]), inputText(buf[:n]), nil
+}
+
+// Key names returned by parseKey for keys that are not printable.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdn"
+	keyHome      = "home"
+	keyEnd       = "end"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyBackspace = "backspace"
+	keyInterrupt = "ctrl-c"
+	keyEOF       = "ctrl-d"
+)
+
+// parseKey returns the name of the key that produced the given terminal
+// input: either one of the key constants or the typed character.
+// It returns the empty string for unrecognized input.
+func parseKey(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	switch string(b) {
+	case "\x1b[A", "\x1bOA":
+		return keyUp
+	case "\x1b[B", "\x1bOB":
+		return keyDown
+	case "\x1b[5~":
+		return keyPageUp
+	case "\x1b[6~":
+		return keyPageDown
+	case "\x1b[H", "\x1bOH", "\x1b[1~":
+		return keyHome
+	case "\x1b[F", "\x1bOF", "\x1b[4~":
+		return keyEnd
+	case "\r", "\n":
+		return keyEnter
+	case "\x1b":
+		return keyEscape
+	case "\x7f", "\b":
+		return keyBackspace
+	case "\x03":
+		return keyInterrupt
+	case "\x04":
+		return keyEOF
+	}
+	if b[0] == '\x1b' {
+		return ""
+	}
+	c, size := utf8.DecodeRune(b)
+	if size != len(b) || c == utf8.RuneError || unicode.IsControl(c) {
+		return ""
+	}
+	return string(c)
+}
+
+// inputText returns the text in the given terminal input, with line
+// breaks as "\n". It returns the empty string if the input contains
+// escape sequences or other control characters.
+func inputText(b []byte) string {
+	if !utf8.Valid(b) {
+		return ""
+	}
+	s := strings.ReplaceAll(string(b), "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	for _, c := range s {
+		if c != '\n' && c != '\t' && unicode.IsControl(c) {
+			return ""
+		}
+	}
+	return s
+}
+
+// fitWidth expands tabs, removes control characters, and truncates s to
+// at most width characters.
+func fitWidth(s string, width int) string {
+	sb := new(strings.Builder)
+	n := 0
+	for _, c := range s {
+		if n >= width {
+			break
+		}
+		switch {
+		case c == '\t':
+			for {
+				sb.WriteByte(' ')
+				n++
+				if n%8 == 0 || n >= width {
+					break
+				}
+			}
+			continue
+		case unicode.IsControl(c):
+			continue
+		}
+		sb.WriteRune(c)
+		n++
+	}
+	return sb.String()
+}
+
+// highlightLine returns s fitted to width and drawn in reverse video
+// across the whole width of the screen.
+func highlightLine(s string, width int) string {
+	s = fitWidth(s, width)
+	if pad := width - utf8.RuneCountInString(s); pad > 0 {
+		s += strings.Repeat(" ", pad)
+	}
+	return "\x1b[7m" + s + "\x1b[m"
+}
+
+// colorDiffLine returns a line of git diff output with terminal colors.
+func colorDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return "\x1b[1m" + line + "\x1b[m"
+	case strings.HasPrefix(line, "+"):
+		return "\x1b[32m" + line + "\x1b[m"
+	case strings.HasPrefix(line, "-"):
+		return "\x1b[31m" + line + "\x1b[m"
+	case strings.HasPrefix(line, "@@"):
+		return "\x1b[36m" + line + "\x1b[m"
+	case strings.HasPrefix(line, "commit "):
+		return "\x1b[33m" + line + "\x1b[m"
+	default:
+		return line
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "testing"
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "j", want: "j"},
+		{in: "G", want: "G"},
+		{in: "é", want: "é"},
+		{in: "\x1b[A", want: keyUp},
+		{in: "\x1bOB", want: keyDown},
+		{in: "\x1b[6~", want: keyPageDown},
+		{in: "\r", want: keyEnter},
+		{in: "\x1b", want: keyEscape},
+		{in: "\x7f", want: keyBackspace},
+		{in: "\x03", want: keyInterrupt},
+		{in: "\x04", want: keyEOF},
+		{in: "\x1b[99~", want: ""},
+		{in: "jk", want: ""},
+		{in: "\x01", want: ""},
+	}
+	for _, test := range tests {
+		if got := parseKey([]byte(test.in)); got != test.want {
+			t.Errorf("parseKey(%q) = %q; want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestInputText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "a", want: "a"},
+		{in: "hello world", want: "hello world"},
+		{in: "line 1\r\nline 2\r", want: "line 1\nline 2\n"},
+		{in: "tab\tstop", want: "tab\tstop"},
+		{in: "\x1b[A", want: ""},
+		{in: "\x7f", want: ""},
+		{in: "\xff", want: ""},
+	}
+	for _, test := range tests {
+		if got := inputText([]byte(test.in)); got != test.want {
+			t.Errorf("inputText(%q) = %q; want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestFitWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{s: "hello", width: 10, want: "hello"},
+		{s: "hello", width: 3, want: "hel"},
+		{s: "a\tb", width: 10, want: "a       b"},
+		{s: "a\tb", width: 4, want: "a   "},
+		{s: "\x1b[31mred", width: 10, want: "[31mred"},
+		{s: "héllo", width: 2, want: "hé"},
+	}
+	for _, test := range tests {
+		if got := fitWidth(test.s, test.width); got != test.want {
+			t.Errorf("fitWidth(%q, %d) = %q; want %q", test.s, test.width, got, test.want)
+		}
+	}
+}
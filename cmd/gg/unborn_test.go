@@ -0,0 +1,89 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+)
+
+func TestEmptyRepository(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := env.gg(ctx, env.root.String(), "status", "-b"); err != nil {
+		t.Error("gg status -b:", err)
+	} else if got, want := string(out), "on branch main (no commits yet)"; !strings.Contains(got, want) {
+		t.Errorf("gg status -b output = %q; want to contain %q", got, want)
+	}
+	if out, err := env.gg(ctx, env.root.String(), "log"); err != nil {
+		t.Error("gg log:", err)
+	} else if got, want := string(out), "no commits yet\n"; got != want {
+		t.Errorf("gg log output = %q; want %q", got, want)
+	}
+	if out, err := env.gg(ctx, env.root.String(), "branch"); err != nil {
+		t.Error("gg branch:", err)
+	} else if got := string(out); !strings.HasPrefix(got, "* main") || !strings.Contains(got, "(no commits yet)") {
+		t.Errorf("gg branch output = %q; want unborn main branch", got)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "identify"); err == nil {
+		t.Error("gg identify did not return an error")
+	} else if got, want := err.Error(), "no commits yet"; !strings.Contains(got, want) {
+		t.Errorf("gg identify error = %q; want to contain %q", got, want)
+	}
+
+	// Create the root commit.
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.trackFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "first"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := env.git.CommitInfo(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(head.Parents) != 0 {
+		t.Errorf("HEAD parents = %v; want none", head.Parents)
+	}
+	if got, want := head.Message, "first\n"; got != want {
+		t.Errorf("HEAD message = %q; want %q", got, want)
+	}
+	if out, err := env.gg(ctx, env.root.String(), "log"); err != nil {
+		t.Error("gg log after commit:", err)
+	} else if strings.Contains(string(out), "no commits yet") {
+		t.Errorf("gg log output after commit = %q; want history", out)
+	}
+	if out, err := env.gg(ctx, env.root.String(), "branch"); err != nil {
+		t.Error("gg branch after commit:", err)
+	} else if strings.Contains(string(out), "(no commits yet)") {
+		t.Errorf("gg branch output after commit = %q; want main with a commit", out)
+	}
+}
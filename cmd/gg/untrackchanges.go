@@ -0,0 +1,199 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gg-scm.io/pkg/git"
+	"gg-scm.io/tool/internal/flag"
+)
+
+const untrackChangesSynopsis = "ignore local changes to tracked files"
+
+func untrackChanges(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg untrack-changes [--clear | --list [--relative | --root-relative]] [FILE [...]]", untrackChangesSynopsis+`
+
+	Mark tracked files so that local changes to them are hidden from
+	`+"`gg status`"+` and are not committed. This is useful for files like
+	local configuration that must be edited but should not be shared.
+	Marked files are listed in `+"`gg status`"+` with an S.
+
+	Marks are not cleared automatically. If a pull or update brings in
+	changes to a marked file, Git may refuse to update or may overwrite
+	your local changes. Clear the mark with `+"`--clear`"+` before updating
+	to be safe.
+
+	This uses Git's skip-worktree bit. See git-update-index(1) for details.`+pathStyleHelp)
+	clearMarks := f.Bool("clear", false, "stop ignoring local changes to the given files (or all files if none given)")
+	list := f.Bool("l", false, "list files with ignored local changes")
+	f.Alias("l", "list")
+	pathStyle := new(pathStyleFlags)
+	pathStyle.addFlags(f)
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if *clearMarks && *list {
+		return usagef("cannot pass both --clear and --list")
+	}
+	if !*list && (pathStyle.relative || pathStyle.rootRelative) {
+		return usagef("--relative and --root-relative can only be used with --list")
+	}
+	pathspecs := make([]git.Pathspec, 0, f.NArg())
+	for _, arg := range f.Args() {
+		pathspecs = append(pathspecs, git.LiteralPath(arg))
+	}
+	switch {
+	case *list:
+		cfg, err := cc.git.ReadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		pf, err := pathStyle.formatter(ctx, cc, cfg)
+		if err != nil {
+			return err
+		}
+		marked, err := listUntrackedChanges(ctx, cc.git, pathspecs)
+		if err != nil {
+			return err
+		}
+		for _, name := range marked {
+			if _, err := fmt.Fprintln(cc.stdout, pf.format(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *clearMarks:
+		if len(pathspecs) == 0 {
+			marked, err := listUntrackedChanges(ctx, cc.git, nil)
+			if err != nil {
+				return err
+			}
+			if len(marked) == 0 {
+				return nil
+			}
+			for _, name := range marked {
+				pathspecs = append(pathspecs, name.Pathspec())
+			}
+		}
+		return updateSkipWorktree(ctx, cc.git, "--no-skip-worktree", pathspecs)
+	default:
+		if len(pathspecs) == 0 {
+			return usagef("must pass one or more files")
+		}
+		if err := updateSkipWorktree(ctx, cc.git, "--skip-worktree", pathspecs); err != nil {
+			return err
+		}
+		fmt.Fprintln(cc.stderr, "gg: warning: pulls and updates that change these files may fail or overwrite local changes.\n"+
+			"gg: Run `gg untrack-changes --clear` before updating to be safe.")
+		return nil
+	}
+}
+
+func updateSkipWorktree(ctx context.Context, g *git.Git, mode string, pathspecs []git.Pathspec) error {
+	// git update-index takes file names, not pathspecs, so expand them.
+	lsArgs := []string{"ls-files", "-z", "--full-name", "--error-unmatch", "--"}
+	for _, p := range pathspecs {
+		lsArgs = append(lsArgs, p.String())
+	}
+	out, err := g.Output(ctx, lsArgs...)
+	if err != nil {
+		return err
+	}
+	workTree, err := g.WorkTree(ctx)
+	if err != nil {
+		return err
+	}
+	updateArgs := []string{"update-index", mode, "--"}
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			updateArgs = append(updateArgs, name)
+		}
+	}
+	return g.WithDir(workTree).Run(ctx, updateArgs...)
+}
+
+// listUntrackedChanges returns the tracked files matching the given
+// pathspecs that have been marked with untrack-changes. Files marked
+// skip-worktree that are not present in the working copy are omitted,
+// since those come from sparse checkouts.
+func listUntrackedChanges(ctx context.Context, g *git.Git, pathspecs []git.Pathspec) ([]git.TopPath, error) {
+	args := []string{"ls-files", "-v", "-z", "--full-name", "--"}
+	if len(pathspecs) == 0 {
+		args = append(args, git.JoinPathspecMagic(git.PathspecMagic{Top: true, Literal: true}, "").String())
+	}
+	for _, p := range pathspecs {
+		args = append(args, p.String())
+	}
+	out, err := g.Output(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list skip-worktree files: %w", err)
+	}
+	var workTree string
+	var marked []git.TopPath
+	for _, ent := range strings.Split(out, "\x00") {
+		// Each entry is a one-character tag, a space, and the file name.
+		// S is skip-worktree; lowercase also denotes assume-unchanged.
+		if len(ent) < 3 || (ent[0] != 'S' && ent[0] != 's') {
+			continue
+		}
+		if workTree == "" {
+			workTree, err = g.WorkTree(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("list skip-worktree files: %w", err)
+			}
+		}
+		name := ent[2:]
+		if _, err := os.Lstat(filepath.Join(workTree, filepath.FromSlash(name))); err != nil {
+			continue
+		}
+		marked = append(marked, git.TopPath(name))
+	}
+	return marked, nil
+}
+
+// warnUntrackedChanges prints a warning for each file marked with
+// untrack-changes that differs between HEAD and rev. Errors are ignored,
+// since this is only advisory.
+func warnUntrackedChanges(ctx context.Context, cc *cmdContext, rev string) {
+	marked, err := listUntrackedChanges(ctx, cc.git, nil)
+	if err != nil || len(marked) == 0 {
+		return
+	}
+	pathspecs := make([]git.Pathspec, 0, len(marked))
+	for _, name := range marked {
+		pathspecs = append(pathspecs, name.Pathspec())
+	}
+	diff, err := cc.git.DiffStatus(ctx, git.DiffStatusOptions{
+		Commit1:   git.Head.String(),
+		Commit2:   rev,
+		Pathspecs: pathspecs,
+	})
+	if err != nil {
+		return
+	}
+	for _, ent := range diff {
+		fmt.Fprintf(cc.stderr, "gg: warning: %s changes in %s but local changes to it are ignored (see gg untrack-changes)\n", ent.Name, rev)
+	}
+}
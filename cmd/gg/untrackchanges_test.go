@@ -0,0 +1,141 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gg-scm.io/tool/internal/filesystem"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestUntrackChanges(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initEmptyRepo(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("config.txt", "debug = false\n"),
+		filesystem.Write("main.txt", dummyContent),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "config.txt", "main.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.newCommit(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "untrack-changes", "config.txt"); err != nil {
+		t.Fatal(err)
+	}
+	err = env.root.Apply(
+		filesystem.Write("config.txt", "debug = true\n"),
+		filesystem.Write("main.txt", "Changed!\n"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Marked files are listed separately from modified files.
+	out, err := env.gg(ctx, env.root.String(), "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ggStatusLine{
+		{letter: 'M', name: "main.txt"},
+		{letter: 'S', name: "config.txt"},
+	}
+	diff := cmp.Diff(want, parseGGStatus(out, t),
+		cmp.AllowUnexported(ggStatusLine{}),
+		cmp.Transformer("Map", ggStatusMap),
+		cmpopts.EquateEmpty())
+	if diff != "" {
+		t.Errorf("status output differs (-want +got):\n%s", diff)
+	}
+	out, err = env.gg(ctx, env.root.String(), "untrack-changes", "--list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "config.txt\n"; got != want {
+		t.Errorf("untrack-changes --list = %q; want %q", got, want)
+	}
+
+	// Committing everything leaves the marked file alone.
+	if _, err := env.gg(ctx, env.root.String(), "commit", "-m", "changed main"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := catBlob(ctx, env.git, "HEAD", "config.txt"); err != nil {
+		t.Fatal(err)
+	} else if want := "debug = false\n"; string(got) != want {
+		t.Errorf("config.txt @ HEAD = %q; want %q", got, want)
+	}
+
+	// Clearing the mark reveals the local changes again.
+	if _, err := env.gg(ctx, env.root.String(), "untrack-changes", "--clear"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.gg(ctx, env.root.String(), "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []ggStatusLine{
+		{letter: 'M', name: "config.txt"},
+	}
+	diff = cmp.Diff(want, parseGGStatus(out, t),
+		cmp.AllowUnexported(ggStatusLine{}),
+		cmp.Transformer("Map", ggStatusMap),
+		cmpopts.EquateEmpty())
+	if diff != "" {
+		t.Errorf("status output after --clear differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestUntrackChanges_UntrackedFile(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("new.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, env.root.String(), "untrack-changes", "new.txt"); err == nil {
+		t.Error("untrack-changes on untracked file did not return an error")
+	}
+	out, err := env.gg(ctx, env.root.String(), "untrack-changes", "--list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(out)) != "" {
+		t.Errorf("untrack-changes --list = %q; want empty", out)
+	}
+}
@@ -21,12 +21,13 @@ import (
 
 	"gg-scm.io/pkg/git"
 	"gg-scm.io/tool/internal/flag"
+	"gg-scm.io/tool/internal/terminal"
 )
 
 const updateSynopsis = "update working directory (or switch revisions)"
 
 func update(ctx context.Context, cc *cmdContext, args []string) error {
-	f := flag.NewFlagSet(true, "gg update [--clean] [[-r] REV]", updateSynopsis+`
+	f := flag.NewFlagSet(true, "gg update [--clean | --conflict-style STYLE] [--default | --pick | [-r] REV]", updateSynopsis+`
 
 aliases: up, checkout, co
 
@@ -35,24 +36,89 @@ aliases: up, checkout, co
 	it has the same name as the current branch or the tip of the push
 	branch otherwise.
 
+	With `+"`--default`"+`, update to the default branch of the current branch's
+	remote (or origin), creating a local branch that tracks it if needed.
+	See `+"`gg remote`"+`.
+
 	If the commit is not a descendant or ancestor of the HEAD commit,
-	the update is aborted.`)
+	the update is aborted.
+
+	If HEAD is detached, gg lists any commits that are left behind on no
+	branch. They can still be found in the HEAD reflog.
+
+	With `+"`--clean`"+`, files with uncommitted changes are saved in a backup
+	under `+"`.git/gg/backups`"+` before the changes are discarded.
+	`+"`gg backups restore`"+` copies them back.
+
+	If `+"`core.ignoreCase`"+` is set, as Git does for repositories on
+	case-insensitive file systems, a revision that doesn't exist matches
+	a branch whose name differs only in case.`+autoFetchHelp+branchPickerHelp)
 	rev := f.String("r", "", "`rev`ision")
-	clean := f.Bool("clean", false, "discard uncommitted changes (no backup)")
+	toDefault := f.Bool("default", false, "update to the default branch of the remote")
+	pick := f.Bool("pick", false, "choose a branch from a list of recently used branches")
+	clean := f.Bool("clean", false, "discard uncommitted changes (saving a backup)")
 	f.Alias("clean", "C")
+	conflictStyle := addConflictStyleFlag(f)
 	if err := f.Parse(args); flag.IsHelp(err) {
 		f.Help(cc.stdout)
 		return nil
 	} else if err != nil {
 		return usagef("%v", err)
 	}
+	if *clean && *conflictStyle != "" {
+		return usagef("cannot specify --conflict-style with --clean")
+	}
+	cc, err := withConflictStyle(cc, *conflictStyle)
+	if err != nil {
+		return err
+	}
 	behavior := git.MergeLocal
 	if *clean {
 		behavior = git.DiscardLocal
 	}
+	if *toDefault && *pick {
+		return usagef("can't pass both --default and --pick")
+	}
+	if !*pick && !*toDefault && f.NArg() == 0 && *rev == "" && terminal.IsTerminal(cc.stdout) {
+		cfg, err := cc.git.ReadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if cfg.Value("gg.updatePick") != "" {
+			*pick, err = cfg.Bool("gg.updatePick")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if *pick {
+		if f.NArg() > 0 || *rev != "" {
+			return usagef("can't pass a revision with --pick")
+		}
+		branch, err := pickBranch(ctx, cc)
+		if err != nil {
+			return err
+		}
+		*rev = git.BranchRef(branch).String()
+	}
+	if *toDefault {
+		if f.NArg() > 0 || *rev != "" {
+			return usagef("can't pass a revision with --default")
+		}
+		branch, err := checkoutDefaultBranch(ctx, cc)
+		if err != nil || branch == "" {
+			return err
+		}
+		*rev = git.BranchRef(branch).String()
+	}
+	if f.NArg() == 1 && *rev == "" {
+		*rev = f.Arg(0)
+	} else if f.NArg() > 0 {
+		return usagef("can pass only one revision")
+	}
 	var r *git.Rev
 	switch {
-	case f.NArg() == 0 && *rev == "":
+	case *rev == "":
 		cfg, err := cc.git.ReadConfig(ctx)
 		if err != nil {
 			return err
@@ -66,34 +132,110 @@ aliases: up, checkout, co
 			return errors.New("can't update with no branch checked out; run 'gg update BRANCH'")
 		}
 		target := targetForUpdate(cfg, branch)
+		if target != "" {
+			warnUntrackedChanges(ctx, cc, target.String())
+		}
+		if *clean {
+			if err := backupBeforeDiscard(ctx, cc, "update --clean", nil); err != nil {
+				return err
+			}
+		}
 		return updateToBranch(ctx, cc.git, branch, target, behavior)
-	case f.NArg() == 0 && *rev != "":
-		var err error
-		r, err = cc.git.ParseRev(ctx, *rev)
+	default:
+		cfg, err := cc.git.ReadConfig(ctx)
 		if err != nil {
 			return err
 		}
-	case f.NArg() == 1 && *rev == "":
-		var err error
-		r, err = cc.git.ParseRev(ctx, f.Arg(0))
+		*rev, err = resolveBranchCase(ctx, cc.git, cfg, *rev)
 		if err != nil {
 			return err
 		}
-	default:
-		return usagef("can pass only one revision")
+		if err := fetchMissingRevs(ctx, cc, *rev); err != nil {
+			return err
+		}
+		r, err = cc.git.ParseRev(ctx, *rev)
+		if err != nil {
+			return err
+		}
+	}
+	abandoned, err := leftBehind(ctx, cc.git, r.Commit.String())
+	if err != nil {
+		return err
+	}
+	if *clean {
+		if err := backupBeforeDiscard(ctx, cc, "update --clean", nil); err != nil {
+			return err
+		}
 	}
 	b := r.Ref.Branch()
 	if b == "" {
-		return cc.git.CheckoutRev(ctx, r.Commit.String(), git.CheckoutOptions{
+		warnUntrackedChanges(ctx, cc, r.Commit.String())
+		err := cc.git.CheckoutRev(ctx, r.Commit.String(), git.CheckoutOptions{
 			ConflictBehavior: behavior,
 		})
+		if err != nil {
+			return err
+		}
+		warnLeftBehind(cc, abandoned)
+		return nil
 	}
 	cfg, err := cc.git.ReadConfig(ctx)
 	if err != nil {
 		return err
 	}
 	target := targetForUpdate(cfg, b)
-	return updateToBranch(ctx, cc.git, b, target, behavior)
+	if target != "" {
+		warnUntrackedChanges(ctx, cc, target.String())
+	} else {
+		warnUntrackedChanges(ctx, cc, r.Commit.String())
+	}
+	if err := updateToBranch(ctx, cc.git, b, target, behavior); err != nil {
+		return err
+	}
+	warnLeftBehind(cc, abandoned)
+	return nil
+}
+
+// checkoutDefaultBranch finds the default branch of the current
+// branch's remote. If there is a local branch with the same name, it
+// returns the name so that the caller can update to it. Otherwise, it
+// creates a local branch that tracks the remote's default branch, checks
+// it out, and returns the empty string.
+func checkoutDefaultBranch(ctx context.Context, cc *cmdContext) (string, error) {
+	cfg, err := cc.git.ReadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	headRef, err := cc.git.HeadRef(ctx)
+	if err != nil {
+		return "", err
+	}
+	remote, branch, err := defaultBranchRef(ctx, cc.git, cfg, headRef.Branch())
+	if err != nil {
+		return "", err
+	}
+	if branch == "" {
+		return "", errors.New("default branch of remote is unknown; run 'gg remote set-default'")
+	}
+	if _, err := cc.git.ParseRev(ctx, git.BranchRef(branch).String()); err == nil {
+		return branch, nil
+	}
+	startPoint := "refs/remotes/" + remote + "/" + branch
+	abandoned, err := leftBehind(ctx, cc.git, startPoint)
+	if err != nil {
+		return "", err
+	}
+	warnUntrackedChanges(ctx, cc, startPoint)
+	err = cc.git.NewBranch(ctx, branch, git.BranchOptions{
+		StartPoint: startPoint,
+		Track:      true,
+		Checkout:   true,
+	})
+	if err != nil {
+		return "", err
+	}
+	warnLeftBehind(cc, abandoned)
+	return "", nil
 }
 
 // updateToBranch switches to another branch and fast-forwards it.
@@ -130,7 +272,11 @@ func updateToBranch(ctx context.Context, g *git.Git, branch string, target git.R
 	if isAncestor, err := g.IsAncestor(ctx, git.BranchRef(branch).String(), target.String()); err != nil {
 		return err
 	} else if !isAncestor {
-		return errors.New("upstream has diverged; run 'gg merge' or 'gg rebase'")
+		d, err := updateDivergence(ctx, g, branch, target)
+		if err != nil {
+			return fmt.Errorf("upstream has diverged: %w", err)
+		}
+		return d
 	}
 	// Here's the trickiness: move the working copy to the given revision
 	// while merging the local changes, then move the branch ref to match the
@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"gg-scm.io/pkg/git"
@@ -797,3 +798,200 @@ func TestUpdate_Clean(t *testing.T) {
 		t.Errorf("foo.txt = %q; want %q", got, want)
 	}
 }
+
+func TestUpdate_Diverged(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("Diverged", func(t *testing.T) {
+		env, err := newTestEnv(ctx, t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.git.Run(ctx, "clone", "repoA", "repoB"); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.root.Apply(filesystem.Write("repoA/foo.txt", "Apple\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := env.newCommit(ctx, "repoA"); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.root.Apply(filesystem.Write("repoB/bar.txt", "Banana\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, "repoB/bar.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := env.newCommit(ctx, "repoB"); err != nil {
+			t.Fatal(err)
+		}
+		repoBPath := env.root.FromSlash("repoB")
+		if err := env.git.WithDir(repoBPath).Run(ctx, "fetch", "origin"); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = env.gg(ctx, repoBPath, "update")
+		if err == nil {
+			t.Fatal("update did not return error")
+		}
+		const want = "main has diverged from origin/main (1 commit ahead, 1 commit behind); run 'gg rebase'"
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v; want to contain %q", err, want)
+		}
+	})
+	t.Run("Rewritten", func(t *testing.T) {
+		env, err := newTestEnv(ctx, t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := env.initRepoWithHistory(ctx, "repoA"); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.root.Apply(filesystem.Write("repoA/foo.txt", "Apple\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := env.newCommit(ctx, "repoA"); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.git.Run(ctx, "clone", "repoA", "repoB"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Replace the last commit in repository A.
+		gitA := env.git.WithDir(env.root.FromSlash("repoA"))
+		if err := gitA.Run(ctx, "reset", "--hard", "HEAD~1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.root.Apply(filesystem.Write("repoA/foo.txt", "Apricot\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, "repoA/foo.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := env.newCommit(ctx, "repoA"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Make a local commit in repository B on top of the old history.
+		if err := env.root.Apply(filesystem.Write("repoB/bar.txt", "Banana\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.addFiles(ctx, "repoB/bar.txt"); err != nil {
+			t.Fatal(err)
+		}
+		local, err := env.newCommit(ctx, "repoB")
+		if err != nil {
+			t.Fatal(err)
+		}
+		repoBPath := env.root.FromSlash("repoB")
+		if err := env.git.WithDir(repoBPath).Run(ctx, "fetch", "origin"); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = env.gg(ctx, repoBPath, "update")
+		if err == nil {
+			t.Fatal("update did not return error")
+		}
+		want := "origin/main was rewritten; run 'gg rebase --source " + local.String()[:7] + " --dest origin/main' to move 1 commit of main"
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v; want to contain %q", err, want)
+		}
+	})
+}
+
+func TestUpdate_LeftBehind(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "checkout", "--quiet", "--detach", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.root.Apply(filesystem.Write("foo.txt", dummyContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.addFiles(ctx, "foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+	orphan, err := env.newCommit(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, env.root.String(), "update", "main"); err != nil {
+		t.Fatal(err)
+	}
+	got := env.stderr.String()
+	for _, want := range []string{"left behind 1 commit not on any branch", orphan.Short(), "gg branch -r " + orphan.Short()} {
+		if !strings.Contains(got, want) {
+			t.Errorf("stderr = %q; want to contain %q", got, want)
+		}
+	}
+}
+
+func TestUpdate_Default(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "origin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.git.Run(ctx, "clone", "--quiet", "origin", "local"); err != nil {
+		t.Fatal(err)
+	}
+	localDir := env.root.FromSlash("local")
+	localGit := env.git.WithDir(localDir)
+	if err := localGit.NewBranch(ctx, "topic", git.BranchOptions{Checkout: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.gg(ctx, localDir, "update", "--default"); err != nil {
+		t.Fatal(err)
+	}
+	if ref, err := localGit.HeadRef(ctx); err != nil {
+		t.Fatal(err)
+	} else if want := git.BranchRef("main"); ref != want {
+		t.Errorf("after update --default, HEAD = %s; want %s", ref, want)
+	}
+
+	// Without a local main branch, update --default creates one.
+	if err := localGit.CheckoutBranch(ctx, "topic", git.CheckoutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := localGit.DeleteBranches(ctx, []string{"main"}, git.DeleteBranchOptions{Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.gg(ctx, localDir, "update", "--default"); err != nil {
+		t.Fatal(err)
+	}
+	if ref, err := localGit.HeadRef(ctx); err != nil {
+		t.Fatal(err)
+	} else if want := git.BranchRef("main"); ref != want {
+		t.Errorf("after update --default without local branch, HEAD = %s; want %s", ref, want)
+	}
+	cfg, err := localGit.ReadConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Value("branch.main.remote"); got != "origin" {
+		t.Errorf("branch.main.remote = %q; want \"origin\"", got)
+	}
+}
@@ -0,0 +1,579 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gg-scm.io/tool/internal/flag"
+)
+
+const viewSynopsis = "browse history interactively"
+
+const viewKeysHelp = `keys:
+	  j, down       select the next commit
+	  k, up         select the previous commit
+	  pgdn, pgup    move a page down or up
+	  g, G          select the first or last commit
+	  enter         show or hide the selected commit's diff
+	  J, K          scroll the diff a line down or up
+	  space, -      scroll the diff a page down or up
+	  b             show only a branch (empty for the original revisions)
+	  u             update to the selected commit (like gg update)
+	  r             rebase the current branch onto the selected commit
+	                (like gg rebase -d)
+	  p             apply the selected commit on top of the current branch
+	                (git cherry-pick)
+	  R             reload history
+	  ?             show this list of keys
+	  q             quit`
+
+func view(ctx context.Context, cc *cmdContext, args []string) error {
+	f := flag.NewFlagSet(true, "gg view [--all] [-n NUM] [REV [...]]", viewSynopsis+`
+
+	`+"`gg view`"+` shows the commit graph in a full-screen browser. Select a
+	commit to see its diff or to update, rebase, or cherry-pick onto it.
+	By default, the history of the working copy's commit is shown.
+
+	`+viewKeysHelp)
+	all := f.Bool("all", false, "show all local branches and tags")
+	maxCount := f.Int("n", 1000, "maximum number of commits to load")
+	if err := f.Parse(args); flag.IsHelp(err) {
+		f.Help(cc.stdout)
+		return nil
+	} else if err != nil {
+		return usagef("%v", err)
+	}
+	if *maxCount <= 0 {
+		return usagef("-n must be positive")
+	}
+	screen, err := newTUIScreen(cc)
+	if err != nil {
+		return fmt.Errorf("view: %w; use gg log instead", err)
+	}
+	revs := f.Args()
+	if *all {
+		revs = append(revs, "--branches", "--tags")
+	}
+	if len(revs) == 0 {
+		revs = []string{"HEAD"}
+	}
+	v := &viewer{
+		cc:       cc,
+		screen:   screen,
+		revs:     revs,
+		maxCount: *maxCount,
+		model:    new(viewModel),
+	}
+	if err := v.reload(ctx); err != nil {
+		return err
+	}
+	if err := screen.start(); err != nil {
+		return err
+	}
+	err = v.loop(ctx)
+	if stopErr := screen.stop(); err == nil {
+		err = stopErr
+	}
+	return err
+}
+
+// A viewCommit is a commit shown by gg view.
+type viewCommit struct {
+	hash    string
+	short   string
+	author  string
+	date    string
+	refs    string
+	summary string
+}
+
+// A viewRow is a line of the graph: either a commit or graph lines that
+// connect commits.
+type viewRow struct {
+	graph  string
+	commit *viewCommit // nil for rows without a commit
+}
+
+// viewLogFormat is the git log format parsed by parseViewLog. Graph
+// drawing appears before the first NUL byte.
+const viewLogFormat = "%x00%H%x00%h%x00%an%x00%ad%x00%D%x00%s"
+
+// parseViewLog parses the output of git log --graph with viewLogFormat.
+func parseViewLog(out string) []viewRow {
+	var rows []viewRow
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, "\x00")
+		if len(parts) != 7 {
+			rows = append(rows, viewRow{graph: line})
+			continue
+		}
+		rows = append(rows, viewRow{
+			graph: parts[0],
+			commit: &viewCommit{
+				hash:    parts[1],
+				short:   parts[2],
+				author:  parts[3],
+				date:    parts[4],
+				refs:    parts[5],
+				summary: parts[6],
+			},
+		})
+	}
+	return rows
+}
+
+// viewModel is the state of gg view's screen, independent of the
+// terminal.
+type viewModel struct {
+	rows   []viewRow
+	cursor int // index of the selected row, which has a commit if any row does
+	top    int // index of the first row on screen
+
+	diff    []string // lines of the selected commit's diff, or nil if hidden
+	diffTop int      // index of the first diff line on screen
+
+	branch string // branch filter, if any
+	status string // message for the status line
+}
+
+// selected returns the selected commit or nil if there are no commits.
+func (m *viewModel) selected() *viewCommit {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return m.rows[m.cursor].commit
+}
+
+// setRows replaces the rows and selects the commit with the given hash,
+// or the first commit if it is no longer present.
+func (m *viewModel) setRows(rows []viewRow, hash string) {
+	m.rows = rows
+	m.cursor = 0
+	m.top = 0
+	for i, row := range rows {
+		if row.commit != nil && row.commit.hash == hash {
+			m.cursor = i
+			return
+		}
+	}
+	m.moveTo(false)
+}
+
+// move selects the commit delta commits after (or before, if negative)
+// the current one, stopping at the first or last commit.
+func (m *viewModel) move(delta int) {
+	step := 1
+	if delta < 0 {
+		step, delta = -1, -delta
+	}
+	for i := m.cursor + step; delta > 0 && i >= 0 && i < len(m.rows); i += step {
+		if m.rows[i].commit != nil {
+			m.cursor = i
+			delta--
+		}
+	}
+}
+
+// moveTo selects the first commit, or the last commit if last is true.
+func (m *viewModel) moveTo(last bool) {
+	if last {
+		for i := len(m.rows) - 1; i >= 0; i-- {
+			if m.rows[i].commit != nil {
+				m.cursor = i
+				return
+			}
+		}
+		return
+	}
+	for i, row := range m.rows {
+		if row.commit != nil {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// scrollDiff moves the diff pane by delta lines.
+func (m *viewModel) scrollDiff(delta int) {
+	m.diffTop += delta
+	if m.diffTop > len(m.diff)-1 {
+		m.diffTop = len(m.diff) - 1
+	}
+	if m.diffTop < 0 {
+		m.diffTop = 0
+	}
+}
+
+// layout returns the number of rows used for the commit list and for the
+// diff pane on a screen of the given height. The last row is always the
+// status line.
+func (m *viewModel) layout(height int) (listHeight, diffHeight int) {
+	avail := height - 1
+	if avail < 1 {
+		return 0, 0
+	}
+	if m.diff == nil {
+		return avail, 0
+	}
+	listHeight = avail / 3
+	if listHeight < 1 {
+		listHeight = 1
+	}
+	return listHeight, avail - listHeight
+}
+
+// render returns the lines to display on a screen of the given size,
+// including terminal escape sequences for styles.
+func (m *viewModel) render(width, height int) []string {
+	listHeight, diffHeight := m.layout(height)
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if listHeight > 0 && m.cursor >= m.top+listHeight {
+		m.top = m.cursor - listHeight + 1
+	}
+	lines := make([]string, 0, height)
+	for i := m.top; i < m.top+listHeight; i++ {
+		if i >= len(m.rows) {
+			lines = append(lines, "")
+			continue
+		}
+		if i == m.cursor {
+			lines = append(lines, highlightLine(formatViewRow(m.rows[i]), width))
+		} else {
+			lines = append(lines, fitWidth(formatViewRow(m.rows[i]), width))
+		}
+	}
+	for i := m.diffTop; i < m.diffTop+diffHeight; i++ {
+		if i >= len(m.diff) {
+			lines = append(lines, "")
+			continue
+		}
+		lines = append(lines, colorDiffLine(fitWidth(m.diff[i], width)))
+	}
+	status := m.status
+	if status == "" {
+		status = "q quit  enter diff  b branch  u update  r rebase  p cherry-pick  ? keys"
+		if m.branch != "" {
+			status = "[" + m.branch + "]  " + status
+		}
+	}
+	lines = append(lines, highlightLine(status, width))
+	return lines
+}
+
+func formatViewRow(row viewRow) string {
+	if row.commit == nil {
+		return row.graph
+	}
+	c := row.commit
+	sb := new(strings.Builder)
+	sb.WriteString(row.graph)
+	sb.WriteString(c.short)
+	sb.WriteString(" ")
+	sb.WriteString(c.date)
+	sb.WriteString(" ")
+	sb.WriteString(c.author)
+	sb.WriteString(" ")
+	if c.refs != "" {
+		sb.WriteString("(")
+		sb.WriteString(c.refs)
+		sb.WriteString(") ")
+	}
+	sb.WriteString(c.summary)
+	return sb.String()
+}
+
+// viewer connects a viewModel to the terminal and to gg's commands.
+type viewer struct {
+	cc       *cmdContext
+	revs     []string
+	maxCount int
+	screen   *tuiScreen
+	model    *viewModel
+	branches map[string][]string // commit hash to local branch names
+}
+
+// reload reads the history from Git, keeping the selection if possible.
+func (v *viewer) reload(ctx context.Context) error {
+	logArgs := []string{"log", "--graph", "--date=short", "--no-color",
+		"--max-count=" + strconv.Itoa(v.maxCount), "--format=" + viewLogFormat}
+	if v.model.branch != "" {
+		logArgs = append(logArgs, v.model.branch)
+	} else {
+		logArgs = append(logArgs, v.revs...)
+	}
+	logArgs = append(logArgs, "--")
+	out, err := v.cc.git.Output(ctx, logArgs...)
+	if err != nil {
+		return err
+	}
+	var hash string
+	if c := v.model.selected(); c != nil {
+		hash = c.hash
+	}
+	v.model.setRows(parseViewLog(out), hash)
+
+	refs, err := v.cc.git.Output(ctx, "for-each-ref", "--format=%(objectname) %(refname:short)", "refs/heads/")
+	if err != nil {
+		return err
+	}
+	v.branches = make(map[string][]string)
+	for _, line := range strings.Split(strings.TrimSuffix(refs, "\n"), "\n") {
+		if hash, name, ok := strings.Cut(line, " "); ok {
+			v.branches[hash] = append(v.branches[hash], name)
+		}
+	}
+	if v.model.diff != nil {
+		return v.loadDiff(ctx)
+	}
+	return nil
+}
+
+// loadDiff reads the diff of the selected commit into the diff pane.
+func (v *viewer) loadDiff(ctx context.Context) error {
+	c := v.model.selected()
+	if c == nil {
+		v.model.diff = []string{}
+		return nil
+	}
+	out, err := v.cc.git.Output(ctx, "show", "--no-color", "--stat", "--patch", "--format=fuller", c.hash, "--")
+	if err != nil {
+		return err
+	}
+	v.model.diff = strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	v.model.diffTop = 0
+	return nil
+}
+
+func (v *viewer) draw() error {
+	width, height, err := v.screen.size()
+	if err != nil {
+		return err
+	}
+	return v.screen.draw(v.model.render(width, height))
+}
+
+// prompt reads a line of input on the status line. ok is false if the
+// user pressed escape.
+func (v *viewer) prompt(label string) (line string, ok bool, err error) {
+	defer func() { v.model.status = "" }()
+	var input []rune
+	for {
+		v.model.status = label + string(input)
+		if err := v.draw(); err != nil {
+			return "", false, err
+		}
+		key, _, err := v.screen.readKey()
+		if err != nil {
+			return "", false, err
+		}
+		switch key {
+		case keyEnter:
+			return string(input), true, nil
+		case keyEscape, keyInterrupt:
+			return "", false, nil
+		case keyBackspace:
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		default:
+			if utf8.RuneCountInString(key) == 1 {
+				input = append(input, []rune(key)...)
+			}
+		}
+	}
+}
+
+// confirm asks a yes or no question on the status line.
+func (v *viewer) confirm(question string) (bool, error) {
+	defer func() { v.model.status = "" }()
+	v.model.status = question + " [y/N]"
+	if err := v.draw(); err != nil {
+		return false, err
+	}
+	key, _, err := v.screen.readKey()
+	if err != nil {
+		return false, err
+	}
+	return key == "y" || key == "Y", nil
+}
+
+// runCommand leaves the full-screen display to run a command, waits for
+// a key press, and then reloads the history.
+func (v *viewer) runCommand(ctx context.Context, description string, cmd func() error) error {
+	if err := v.screen.stop(); err != nil {
+		return err
+	}
+	fmt.Fprintf(v.cc.stderr, "gg view: %s\n", description)
+	cmdErr := cmd()
+	if cmdErr != nil {
+		fmt.Fprintln(v.cc.stderr, "gg:", cmdErr)
+	}
+	fmt.Fprint(v.cc.stderr, "Press any key to return to gg view.")
+	if err := v.screen.start(); err != nil {
+		return err
+	}
+	if _, _, err := v.screen.readKey(); err != nil {
+		return err
+	}
+	if err := v.reload(ctx); err != nil {
+		return err
+	}
+	if cmdErr != nil {
+		v.model.status = "failed: " + description
+	} else {
+		v.model.status = "done: " + description
+	}
+	return nil
+}
+
+// updateTarget returns the revision to pass to gg update to check out
+// c: a branch that points to it, if any.
+func (v *viewer) updateTarget(c *viewCommit) string {
+	if names := v.branches[c.hash]; len(names) > 0 {
+		return names[0]
+	}
+	return c.hash
+}
+
+func (v *viewer) loop(ctx context.Context) error {
+	for {
+		if err := v.draw(); err != nil {
+			return err
+		}
+		key, _, err := v.screen.readKey()
+		if err != nil {
+			return err
+		}
+		m := v.model
+		m.status = ""
+		_, height, _ := v.screen.size()
+		listHeight, diffHeight := m.layout(height)
+		moved := false
+		switch key {
+		case "q", keyInterrupt:
+			return nil
+		case "j", keyDown:
+			m.move(1)
+			moved = true
+		case "k", keyUp:
+			m.move(-1)
+			moved = true
+		case keyPageDown:
+			m.move(listHeight)
+			moved = true
+		case keyPageUp:
+			m.move(-listHeight)
+			moved = true
+		case "g", keyHome:
+			m.moveTo(false)
+			moved = true
+		case "G", keyEnd:
+			m.moveTo(true)
+			moved = true
+		case keyEnter:
+			if m.diff != nil {
+				m.diff = nil
+			} else if err := v.loadDiff(ctx); err != nil {
+				m.status = err.Error()
+			}
+		case "J":
+			m.scrollDiff(1)
+		case "K":
+			m.scrollDiff(-1)
+		case " ":
+			m.scrollDiff(diffHeight)
+		case "-":
+			m.scrollDiff(-diffHeight)
+		case "R":
+			if err := v.reload(ctx); err != nil {
+				m.status = err.Error()
+			}
+		case "?":
+			m.diff = strings.Split(strings.ReplaceAll(viewKeysHelp, "\n\t", "\n"), "\n")
+			m.diffTop = 0
+		case "b":
+			branch, ok, err := v.prompt("branch: ")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			m.branch = strings.TrimSpace(branch)
+			if err := v.reload(ctx); err != nil {
+				m.branch = ""
+				m.status = err.Error()
+			}
+		case "u":
+			c := m.selected()
+			if c == nil {
+				break
+			}
+			target := v.updateTarget(c)
+			if err := v.runCommand(ctx, "gg update "+target, func() error {
+				return update(ctx, v.cc, []string{target})
+			}); err != nil {
+				return err
+			}
+		case "r":
+			c := m.selected()
+			if c == nil {
+				break
+			}
+			if ok, err := v.confirm("Rebase the current branch onto " + c.short + "?"); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+			if err := v.runCommand(ctx, "gg rebase -d "+c.short, func() error {
+				return rebase(ctx, v.cc, []string{"-d", c.hash})
+			}); err != nil {
+				return err
+			}
+		case "p":
+			c := m.selected()
+			if c == nil {
+				break
+			}
+			if ok, err := v.confirm("Cherry-pick " + c.short + " onto the current branch?"); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+			if err := v.runCommand(ctx, "git cherry-pick "+c.short, func() error {
+				return v.cc.interactiveGit(ctx, "cherry-pick", c.hash)
+			}); err != nil {
+				return err
+			}
+		}
+		if moved && m.diff != nil {
+			if err := v.loadDiff(ctx); err != nil {
+				m.status = err.Error()
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const testViewLog = "*\x00aaaa1111\x00aaaa\x00Alice\x002026-01-03\x00HEAD -> main\x00Merge feature\n" +
+	"|\\\n" +
+	"| *\x00bbbb2222\x00bbbb\x00Bob\x002026-01-02\x00feature\x00Add feature\n" +
+	"|/\n" +
+	"*\x00cccc3333\x00cccc\x00Alice\x002026-01-01\x00\x00Initial commit\n"
+
+func TestParseViewLog(t *testing.T) {
+	rows := parseViewLog(testViewLog)
+	if len(rows) != 5 {
+		t.Fatalf("len(rows) = %d; want 5", len(rows))
+	}
+	wantHashes := []string{"aaaa1111", "", "bbbb2222", "", "cccc3333"}
+	for i, want := range wantHashes {
+		var got string
+		if rows[i].commit != nil {
+			got = rows[i].commit.hash
+		}
+		if got != want {
+			t.Errorf("rows[%d] hash = %q; want %q", i, got, want)
+		}
+	}
+	if got, want := rows[2].graph, "| *"; got != want {
+		t.Errorf("rows[2].graph = %q; want %q", got, want)
+	}
+	if got, want := rows[2].commit.refs, "feature"; got != want {
+		t.Errorf("rows[2].commit.refs = %q; want %q", got, want)
+	}
+	if got, want := rows[4].commit.summary, "Initial commit"; got != want {
+		t.Errorf("rows[4].commit.summary = %q; want %q", got, want)
+	}
+}
+
+func TestViewModel_Move(t *testing.T) {
+	m := new(viewModel)
+	m.setRows(parseViewLog(testViewLog), "")
+	if got := m.selected().hash; got != "aaaa1111" {
+		t.Fatalf("initial selection = %q; want aaaa1111", got)
+	}
+	m.move(1)
+	if got := m.selected().hash; got != "bbbb2222" {
+		t.Errorf("after move(1), selection = %q; want bbbb2222", got)
+	}
+	m.move(5)
+	if got := m.selected().hash; got != "cccc3333" {
+		t.Errorf("after move(5), selection = %q; want cccc3333", got)
+	}
+	m.move(-1)
+	if got := m.selected().hash; got != "bbbb2222" {
+		t.Errorf("after move(-1), selection = %q; want bbbb2222", got)
+	}
+	m.moveTo(true)
+	if got := m.selected().hash; got != "cccc3333" {
+		t.Errorf("after moveTo(true), selection = %q; want cccc3333", got)
+	}
+	m.moveTo(false)
+	if got := m.selected().hash; got != "aaaa1111" {
+		t.Errorf("after moveTo(false), selection = %q; want aaaa1111", got)
+	}
+
+	m.setRows(parseViewLog(testViewLog), "cccc3333")
+	if got := m.selected().hash; got != "cccc3333" {
+		t.Errorf("after setRows with cccc3333, selection = %q; want cccc3333", got)
+	}
+}
+
+func TestViewModel_Render(t *testing.T) {
+	m := new(viewModel)
+	m.setRows(parseViewLog(testViewLog), "")
+	m.moveTo(true)
+	lines := m.render(20, 3)
+	if len(lines) != 3 {
+		t.Fatalf("len(render(20, 3)) = %d; want 3", len(lines))
+	}
+	// The list must scroll so that the selected commit is visible.
+	if want := "\x1b[7m*cccc 2026-01-01 Ali\x1b[m"; lines[1] != want {
+		t.Errorf("selected line = %q; want %q", lines[1], want)
+	}
+	if got := lines[0]; got != "|/" {
+		t.Errorf("line above selection = %q; want \"|/\"", got)
+	}
+
+	m.diff = []string{"+added\tline", "-removed"}
+	lines = m.render(20, 7)
+	if len(lines) != 7 {
+		t.Fatalf("len(render(20, 7)) = %d; want 7", len(lines))
+	}
+	if want := "\x1b[32m+added  line\x1b[m"; lines[2] != want {
+		t.Errorf("first diff line = %q; want %q", lines[2], want)
+	}
+}
+
+func TestView_NotTerminal(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	env, err := newTestEnv(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.initRepoWithHistory(ctx, "repo"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.gg(ctx, env.root.FromSlash("repo"), "view")
+	if err == nil {
+		t.Fatal("gg view succeeded without a terminal")
+	}
+	if isUsage(err) {
+		t.Errorf("gg view returned usage error: %v", err)
+	}
+	if !strings.Contains(err.Error(), "terminal") {
+		t.Errorf("error = %v; want to mention terminal", err)
+	}
+}
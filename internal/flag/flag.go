@@ -26,6 +26,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"gg-scm.io/tool/internal/suggest"
 )
 
 // A FlagSet represents a set of defined flags. The zero value of a
@@ -45,6 +47,7 @@ type flag struct {
 	usage    string
 	value    Value
 	defValue string
+	set      bool // whether the flag was passed to Parse
 }
 
 // NewFlagSet returns a new, empty flag set with the specified
@@ -215,6 +218,9 @@ flags:
 			if name == "h" || name == "help" {
 				return errHelp
 			}
+			if hint := suggest.Phrase("-", f.similarFlags(name)); hint != "" {
+				return fmt.Errorf("flag provided but not defined: -%s (%s)", name, hint)
+			}
 			return fmt.Errorf("flag provided but not defined: -%s", name)
 		}
 		if !hasval {
@@ -230,11 +236,22 @@ flags:
 		if err := ff.value.Set(val); err != nil {
 			return fmt.Errorf("invalid value %q for flag -%s: %w", val, name, err)
 		}
+		ff.set = true
 	}
 	f.args = append(f.args, arguments[i:]...)
 	return nil
 }
 
+// similarFlags returns the names of defined flags that name may be a
+// misspelling of.
+func (f *FlagSet) similarFlags(name string) []string {
+	names := make([]string, 0, len(f.flags))
+	for n := range f.flags {
+		names = append(names, n)
+	}
+	return suggest.Similar(name, names)
+}
+
 func split(f string) (name, value string, hasValue bool) {
 	i := strings.IndexByte(f, '=')
 	if i == -1 {
@@ -243,6 +260,16 @@ func split(f string) (name, value string, hasValue bool) {
 	return f[:i], f[i+1:], true
 }
 
+// IsSet reports whether the named flag or one of its aliases was given
+// on the command line passed to Parse.
+func (f *FlagSet) IsSet(name string) bool {
+	ff := f.flags[name]
+	if ff == nil {
+		panic("IsSet for undefined flag: " + name)
+	}
+	return ff.set
+}
+
 // Args returns the non-flag arguments.
 func (f *FlagSet) Args() []string {
 	return f.args[:len(f.args):len(f.args)]
@@ -262,8 +289,13 @@ func (f *FlagSet) Arg(i int) string {
 	return f.args[i]
 }
 
-// Help prints the help.
+// Help prints the help. If w is a MetadataCollector, then Help passes
+// the flag set's Metadata to it instead of printing.
 func (f *FlagSet) Help(w io.Writer) {
+	if c, ok := w.(MetadataCollector); ok {
+		c.CollectMetadata(f.Metadata())
+		return
+	}
 	var buf bytes.Buffer
 	if f.usage != "" {
 		buf.WriteString("usage: ")
@@ -345,6 +377,97 @@ func (f *FlagSet) printDefaults(w io.Writer) {
 	}
 }
 
+// Metadata is a machine-readable description of a FlagSet.
+type Metadata struct {
+	Usage       string         `json:"usage,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Flags       []FlagMetadata `json:"flags"`
+}
+
+// FlagMetadata is a machine-readable description of a flag.
+type FlagMetadata struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Type is "bool", "int", "string", "regexp", or "value" for
+	// other kinds of flags.
+	Type string `json:"type"`
+
+	// Repeatable is true if the flag can be given more than once
+	// to add more values.
+	Repeatable bool `json:"repeatable,omitempty"`
+
+	// Argument is the name of the flag's argument as shown in the help,
+	// or empty for boolean flags.
+	Argument string `json:"argument,omitempty"`
+
+	Usage   string `json:"usage"`
+	Default string `json:"default,omitempty"`
+}
+
+// A MetadataCollector receives the Metadata of a FlagSet whose Help
+// method is called with it as the writer. This permits programs to
+// gather flag sets' metadata without parsing help text.
+type MetadataCollector interface {
+	io.Writer
+	CollectMetadata(md *Metadata)
+}
+
+// Metadata returns a description of the flag set.
+// Flags are sorted by name.
+func (f *FlagSet) Metadata() *Metadata {
+	md := &Metadata{
+		Usage:       f.usage,
+		Description: strings.TrimSpace(strings.ReplaceAll(f.description, "\n\t", "\n")),
+		Flags:       []FlagMetadata{},
+	}
+	names := make([]string, 0, len(f.flags))
+	for name, ff := range f.flags {
+		if ff.name == name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		ff := f.flags[k]
+		arg, usage := unquoteUsage(ff.value, ff.usage)
+		fmd := FlagMetadata{
+			Name:     ff.name,
+			Argument: arg,
+			Usage:    usage,
+		}
+		if len(ff.aliases) > 0 {
+			fmd.Aliases = append([]string(nil), ff.aliases...)
+			sort.Strings(fmd.Aliases)
+		}
+		switch ff.value.(type) {
+		case *boolValue:
+			fmd.Type = "bool"
+		case *intValue:
+			fmd.Type = "int"
+		case *stringValue:
+			fmd.Type = "string"
+		case *multiStringValue:
+			fmd.Type = "string"
+			fmd.Repeatable = true
+		case regexpValue:
+			fmd.Type = "regexp"
+			fmd.Repeatable = true
+		default:
+			if ff.value.IsBoolFlag() {
+				fmd.Type = "bool"
+			} else {
+				fmd.Type = "value"
+			}
+		}
+		if ff.defValue != "" && !(fmd.Type == "bool" && ff.defValue == "false") {
+			fmd.Default = ff.defValue
+		}
+		md.Flags = append(md.Flags, fmd)
+	}
+	return md
+}
+
 func unquoteUsage(val Value, usage string) (name, usage_ string) {
 	if i := strings.IndexByte(usage, '`'); i != -1 {
 		if j := strings.IndexByte(usage[i+1:], '`'); j != -1 {
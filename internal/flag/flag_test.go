@@ -15,6 +15,7 @@
 package flag
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -178,3 +179,88 @@ func stringsEqual(a, b []string) bool {
 	}
 	return true
 }
+
+func TestIsSet(t *testing.T) {
+	fset := NewFlagSet(true, "", "")
+	fset.Bool("x", false, "")
+	fset.String("o", "", "")
+	fset.Alias("o", "out")
+	fset.Bool("u", true, "")
+	if err := fset.Parse([]string{"--out=foo", "-u=true", "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	if fset.IsSet("x") {
+		t.Error("IsSet(\"x\") = true; want false")
+	}
+	if !fset.IsSet("o") {
+		t.Error("IsSet(\"o\") = false; want true")
+	}
+	if !fset.IsSet("out") {
+		t.Error("IsSet(\"out\") = false; want true")
+	}
+	if !fset.IsSet("u") {
+		t.Error("IsSet(\"u\") = false; want true")
+	}
+}
+
+func TestParseSuggestions(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"--forse", "flag provided but not defined: -forse (did you mean '-force'?)"},
+		{"--outptu=x", "flag provided but not defined: -outptu (did you mean '-output'?)"},
+		{"--xyzzy", "flag provided but not defined: -xyzzy"},
+	}
+	for _, test := range tests {
+		fset := NewFlagSet(true, "", "")
+		fset.Bool("force", false, "")
+		fset.String("o", "", "")
+		fset.Alias("o", "output")
+		err := fset.Parse([]string{test.arg})
+		if err == nil {
+			t.Errorf("Parse(%q) = <nil>; want error", test.arg)
+			continue
+		}
+		if got := err.Error(); got != test.want {
+			t.Errorf("Parse(%q) = %q; want %q", test.arg, got, test.want)
+		}
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	fset := NewFlagSet(true, "foo [-x] [-o FILE] ARG", "Do foo.\n\n\tMore about foo.")
+	fset.Bool("x", false, "enable x")
+	fset.String("o", "out.txt", "write to `file`")
+	fset.Alias("o", "output", "out")
+	fset.MultiString("r", "`rev`isions")
+	fset.Int("n", 3, "count")
+	md := fset.Metadata()
+	if md.Usage != "foo [-x] [-o FILE] ARG" {
+		t.Errorf("Usage = %q; want %q", md.Usage, "foo [-x] [-o FILE] ARG")
+	}
+	if want := "Do foo.\n\nMore about foo."; md.Description != want {
+		t.Errorf("Description = %q; want %q", md.Description, want)
+	}
+	want := []FlagMetadata{
+		{Name: "n", Type: "int", Argument: "value", Usage: "count", Default: "3"},
+		{Name: "o", Aliases: []string{"out", "output"}, Type: "string", Argument: "file", Usage: "write to file", Default: "out.txt"},
+		{Name: "r", Type: "string", Repeatable: true, Argument: "rev", Usage: "revisions"},
+		{Name: "x", Type: "bool", Usage: "enable x"},
+	}
+	if len(md.Flags) != len(want) {
+		t.Fatalf("Flags = %+v; want %+v", md.Flags, want)
+	}
+	for i := range want {
+		got := md.Flags[i]
+		if got.Name != want[i].Name ||
+			strings.Join(got.Aliases, ",") != strings.Join(want[i].Aliases, ",") ||
+			got.Type != want[i].Type ||
+			got.Repeatable != want[i].Repeatable ||
+			got.Argument != want[i].Argument ||
+			got.Usage != want[i].Usage ||
+			got.Default != want[i].Default {
+			t.Errorf("Flags[%d] = %+v; want %+v", i, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graph draws a commit graph as text, one line per commit with
+// the edges between commits drawn in columns to the left, in the style
+// of git log --graph and hg log -G.
+package graph
+
+import (
+	"io"
+	"strings"
+)
+
+// A Node is a commit in the graph.
+type Node struct {
+	ID      string
+	Parents []string
+
+	// Glyph is the character drawn for the node. If it is zero, the node
+	// is drawn as 'o'.
+	Glyph rune
+
+	// Text is printed to the right of the node. Lines after the first
+	// are printed with the edges continuing to the left of them.
+	Text string
+}
+
+// A Graph lays out a sequence of nodes. Each node must be added before
+// any of its parents, as git log prints them. The zero value is an
+// empty graph.
+type Graph struct {
+	cols []string // ID of the node that each column leads to
+}
+
+// Write draws the given nodes to w.
+func Write(w io.Writer, nodes []*Node) error {
+	g := new(Graph)
+	sb := new(strings.Builder)
+	for _, n := range nodes {
+		for _, line := range g.Add(n) {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Add returns the lines to draw for n, without trailing newlines.
+// The lines include the edges that connect the node to the nodes before
+// it and the edges that lead to its parents.
+func (g *Graph) Add(n *Node) []string {
+	i := g.index(n.ID)
+	if i == -1 {
+		// Nothing added so far leads to n: start a new column.
+		g.cols = append(g.cols, n.ID)
+		i = len(g.cols) - 1
+	}
+
+	// Join other columns that lead to n into n's column.
+	// Since i is the first such column, the others are to its right.
+	target := make([]int, len(g.cols))
+	var newCols []string
+	for k, id := range g.cols {
+		if id == n.ID && k != i {
+			target[k] = i
+			continue
+		}
+		target[k] = len(newCols)
+		newCols = append(newCols, id)
+	}
+	lines := g.shift(target)
+	g.cols = newCols
+
+	// Draw the node.
+	glyph := n.Glyph
+	if glyph == 0 {
+		glyph = 'o'
+	}
+	row := g.edges()
+	row[2*i] = glyph
+	text := strings.Split(n.Text, "\n")
+	lines = append(lines, withText(row, i, text[0]))
+	if len(text) > 1 {
+		row = g.edges()
+		if len(n.Parents) == 0 {
+			row[2*i] = ' '
+		}
+		for _, t := range text[1:] {
+			lines = append(lines, withText(row, i, t))
+		}
+	}
+
+	// Lead the node's column to its parents.
+	if len(n.Parents) == 0 {
+		target = make([]int, len(g.cols))
+		for k := range target {
+			switch {
+			case k < i:
+				target[k] = k
+			case k == i:
+				target[k] = -1
+			default:
+				target[k] = k - 1
+			}
+		}
+		lines = append(lines, g.shift(target)...)
+		g.cols = append(g.cols[:i], g.cols[i+1:]...)
+		return lines
+	}
+	g.cols[i] = n.Parents[0]
+	for j := len(n.Parents) - 1; j >= 1; j-- {
+		lines = append(lines, g.split(i))
+		g.cols = append(g.cols, "")
+		copy(g.cols[i+2:], g.cols[i+1:])
+		g.cols[i+1] = n.Parents[j]
+	}
+	return lines
+}
+
+// index returns the first column that leads to the node with the given
+// ID or -1 if there is none.
+func (g *Graph) index(id string) int {
+	for k, c := range g.cols {
+		if c == id {
+			return k
+		}
+	}
+	return -1
+}
+
+// edges returns a row with a vertical edge in every column.
+func (g *Graph) edges() []rune {
+	row := blankRow(len(g.cols))
+	for k := range g.cols {
+		row[2*k] = '|'
+	}
+	return row
+}
+
+// shift returns the lines that move each column k to column target[k],
+// at most one column per line. A target of -1 ends the column. Columns
+// must not cross each other.
+func (g *Graph) shift(target []int) []string {
+	pos := make([]int, len(target))
+	for k := range pos {
+		pos[k] = k
+	}
+	var lines []string
+	for {
+		row := blankRow(len(target))
+		moved := false
+		for k, t := range target {
+			switch {
+			case t == -1:
+			case pos[k] < t:
+				row[2*pos[k]+1] = '\\'
+				pos[k]++
+				moved = true
+			case pos[k] > t:
+				row[2*pos[k]-1] = '/'
+				pos[k]--
+				moved = true
+			default:
+				row[2*pos[k]] = '|'
+			}
+		}
+		if !moved {
+			return lines
+		}
+		lines = append(lines, strings.TrimRight(string(row), " "))
+	}
+}
+
+// split returns the line that branches a new column off of column i,
+// moving the columns to its right over by one.
+func (g *Graph) split(i int) string {
+	row := blankRow(len(g.cols) + 1)
+	for k := range g.cols {
+		switch {
+		case k < i:
+			row[2*k] = '|'
+		case k == i:
+			row[2*k] = '|'
+			row[2*k+1] = '\\'
+		default:
+			row[2*k+1] = '\\'
+		}
+	}
+	return strings.TrimRight(string(row), " ")
+}
+
+func blankRow(ncols int) []rune {
+	row := make([]rune, 2*ncols)
+	for k := range row {
+		row[k] = ' '
+	}
+	return row
+}
+
+// withText returns a node's row followed by text. The row is trimmed,
+// but not to the left of the node's column i, so that lines of text
+// stay aligned.
+func withText(row []rune, i int, text string) string {
+	end := len(row)
+	for end > 2*i+1 && row[end-1] == ' ' {
+		end--
+	}
+	prefix := string(row[:end])
+	if text == "" {
+		return prefix
+	}
+	return prefix + "  " + text
+}
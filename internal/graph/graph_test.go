@@ -0,0 +1,132 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []*Node
+		want  string
+	}{
+		{
+			name:  "Empty",
+			nodes: nil,
+			want:  "",
+		},
+		{
+			name: "Linear",
+			nodes: []*Node{
+				{ID: "c", Parents: []string{"b"}, Glyph: '@', Text: "c"},
+				{ID: "b", Parents: []string{"a"}, Text: "b"},
+				{ID: "a", Text: "a"},
+			},
+			want: "@  c\n" +
+				"o  b\n" +
+				"o  a\n",
+		},
+		{
+			name: "Merge",
+			nodes: []*Node{
+				{ID: "m", Parents: []string{"a", "b"}, Text: "m"},
+				{ID: "a", Parents: []string{"r"}, Text: "a"},
+				{ID: "b", Parents: []string{"r"}, Text: "b"},
+				{ID: "r", Text: "r"},
+			},
+			want: "o  m\n" +
+				"|\\\n" +
+				"o |  a\n" +
+				"| o  b\n" +
+				"|/\n" +
+				"o  r\n",
+		},
+		{
+			name: "TwoHeads",
+			nodes: []*Node{
+				{ID: "x", Parents: []string{"r"}, Text: "x"},
+				{ID: "y", Parents: []string{"r"}, Text: "y"},
+				{ID: "r", Text: "r"},
+			},
+			want: "o  x\n" +
+				"| o  y\n" +
+				"|/\n" +
+				"o  r\n",
+		},
+		{
+			name: "TwoRoots",
+			nodes: []*Node{
+				{ID: "m", Parents: []string{"a", "b"}, Text: "m"},
+				{ID: "a", Text: "a"},
+				{ID: "b", Text: "b"},
+			},
+			want: "o  m\n" +
+				"|\\\n" +
+				"o |  a\n" +
+				" /\n" +
+				"o  b\n",
+		},
+		{
+			name: "Octopus",
+			nodes: []*Node{
+				{ID: "m", Parents: []string{"a", "b", "c"}, Text: "m"},
+				{ID: "a", Text: "a"},
+				{ID: "b", Text: "b"},
+				{ID: "c", Text: "c"},
+			},
+			want: "o  m\n" +
+				"|\\\n" +
+				"|\\ \\\n" +
+				"o | |  a\n" +
+				" / /\n" +
+				"o |  b\n" +
+				" /\n" +
+				"o  c\n",
+		},
+		{
+			name: "MultilineText",
+			nodes: []*Node{
+				{ID: "b", Parents: []string{"a"}, Text: "b\nsecond"},
+				{ID: "a", Text: "a\nsecond"},
+			},
+			want: "o  b\n" +
+				"|  second\n" +
+				"o  a\n" +
+				"   second\n",
+		},
+		{
+			name: "MissingParents",
+			nodes: []*Node{
+				{ID: "b", Parents: []string{"a"}, Text: "b"},
+				{ID: "d", Parents: []string{"c"}, Text: "d"},
+			},
+			want: "o  b\n" +
+				"| o  d\n",
+		},
+	}
+	for _, test := range tests {
+		sb := new(strings.Builder)
+		if err := Write(sb, test.nodes); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := sb.String(); got != test.want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1 @@
+select count(*) as "count" from "commits";
@@ -0,0 +1,12 @@
+select
+  "type" as "type",
+  count(*) as "count",
+  sum("size") as "uncompressed_size",
+  sum(length("content")) as "compressed_size"
+from "objects"
+where
+  "type" is not null and
+  "size" >= 0 and
+  "content" is not null
+group by "type"
+order by "type";
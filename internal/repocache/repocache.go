@@ -154,6 +154,53 @@ func stat(conn *sqlite.Conn, id githash.SHA1) (oid int64, prefix object.Prefix,
 	return oid, prefix, nil
 }
 
+// Stats summarizes the contents of a cache.
+type Stats struct {
+	// Objects has an entry for each object type present in the cache.
+	Objects map[object.Type]TypeStats
+	// IndexedCommits is the number of commits whose metadata has been indexed.
+	IndexedCommits int64
+}
+
+// TypeStats summarizes the cached objects of a single type.
+type TypeStats struct {
+	Count          int64
+	Size           int64 // total uncompressed size in bytes
+	CompressedSize int64 // total size stored in the cache in bytes
+}
+
+// Stats returns counts and sizes of the objects in the cache.
+func (c *Cache) Stats(ctx context.Context) (_ *Stats, err error) {
+	c.conn.SetInterrupt(ctx.Done())
+	defer c.conn.SetInterrupt(nil)
+	defer sqlitex.Transaction(c.conn)(&err)
+
+	stats := &Stats{Objects: make(map[object.Type]TypeStats)}
+	err = sqlitex.ExecuteTransientFS(c.conn, sqlFiles, "objects/stats.sql", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			stats.Objects[object.Type(stmt.GetText("type"))] = TypeStats{
+				Count:          stmt.GetInt64("count"),
+				Size:           stmt.GetInt64("uncompressed_size"),
+				CompressedSize: stmt.GetInt64("compressed_size"),
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("git repo cache stats: %v", err)
+	}
+	err = sqlitex.ExecuteTransientFS(c.conn, sqlFiles, "commits/count.sql", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			stats.IndexedCommits = stmt.GetInt64("count")
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("git repo cache stats: %v", err)
+	}
+	return stats, nil
+}
+
 // objectReader is an open handle to a Git object.
 // It verifies the read content on EOF.
 type objectReader struct {
@@ -194,3 +194,71 @@ func TestCopyFrom(t *testing.T) {
 		t.Errorf("content (-want +got):\n%s", diff)
 	}
 }
+
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+	gitDir := filesystem.Dir(t.TempDir())
+	g, err := git.New(git.Options{Dir: gitDir.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Init(ctx, "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitDir.Apply(filesystem.Write("foo.txt", "Hello, World!\n")); err != nil {
+		t.Fatal(err)
+	}
+	err = g.Add(ctx, []git.Pathspec{git.LiteralPath("foo.txt")}, git.AddOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const author object.User = "Ross Light <ross@zombiezen.com>"
+	err = g.Commit(ctx, "Initial import", git.CommitOptions{
+		Author:    author,
+		Committer: author,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err := Open(ctx, filepath.Join(t.TempDir(), "foo.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := cache.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	stats, err := cache.Stats(ctx)
+	if err != nil {
+		t.Fatal("Stats:", err)
+	}
+	if len(stats.Objects) != 0 || stats.IndexedCommits != 0 {
+		t.Errorf("empty cache Stats = %+v; want no objects", stats)
+	}
+
+	gitClient, err := client.NewRemote(client.URLFromPath(gitDir.String()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.CopyFrom(ctx, gitClient); err != nil {
+		t.Fatal("CopyFrom:", err)
+	}
+	stats, err = cache.Stats(ctx)
+	if err != nil {
+		t.Fatal("Stats:", err)
+	}
+	if got := stats.Objects[object.TypeCommit].Count; got != 1 {
+		t.Errorf("commit count = %d; want 1", got)
+	}
+	if got := stats.Objects[object.TypeTree].Count; got != 1 {
+		t.Errorf("tree count = %d; want 1", got)
+	}
+	if got := stats.Objects[object.TypeCommit]; got.Size <= 0 || got.CompressedSize <= 0 {
+		t.Errorf("commit sizes = %+v; want positive", got)
+	}
+	if stats.IndexedCommits != 1 {
+		t.Errorf("IndexedCommits = %d; want 1", stats.IndexedCommits)
+	}
+}
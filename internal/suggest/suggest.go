@@ -0,0 +1,121 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package suggest finds likely intended names for misspelled ones.
+package suggest
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the most names that Similar returns.
+const maxSuggestions = 3
+
+// Similar returns the candidates that name is most likely a misspelling
+// of, closest first. Candidates are similar if they are a few edits away
+// from name or if name is a prefix of them. Similar returns nil if no
+// candidate is close enough.
+func Similar(name string, candidates []string) []string {
+	if name == "" {
+		return nil
+	}
+	// Allow one edit for every three characters, so that short names
+	// don't match everything.
+	maxDist := max(1, len(name)/3)
+	type match struct {
+		name string
+		dist int
+	}
+	var matches []match
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		if c == name || seen[c] {
+			continue
+		}
+		seen[c] = true
+		d := Distance(name, c)
+		if d > maxDist {
+			if len(name) < 3 || !strings.HasPrefix(c, name) {
+				continue
+			}
+			// Prefixes sort after typos.
+			d = maxDist + 1
+		}
+		matches = append(matches, match{c, d})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].dist != matches[j].dist {
+			return matches[i].dist < matches[j].dist
+		}
+		return matches[i].name < matches[j].name
+	})
+	if len(matches) > maxSuggestions {
+		matches = matches[:maxSuggestions]
+	}
+	var names []string
+	for _, m := range matches {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// Distance returns the number of single-byte insertions, deletions,
+// substitutions, and transpositions of adjacent bytes needed to turn a
+// into b.
+func Distance(a, b string) int {
+	// d[i][j] is the distance between a[:i] and b[:j].
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+// Phrase formats suggestions as a question like "did you mean 'a' or 'b'?",
+// adding prefix to each name. It returns the empty string if there are no
+// suggestions.
+func Phrase(prefix string, names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + prefix + name + "'"
+	}
+	switch len(quoted) {
+	case 1:
+		return "did you mean " + quoted[0] + "?"
+	case 2:
+		return "did you mean " + quoted[0] + " or " + quoted[1] + "?"
+	default:
+		return "did you mean " + strings.Join(quoted[:len(quoted)-1], ", ") + ", or " + quoted[len(quoted)-1] + "?"
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suggest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"commit", "commit", 0},
+		{"comit", "commit", 1},
+		{"cmomit", "commit", 1},
+		{"comitt", "commit", 2},
+		{"push", "pull", 2},
+		{"", "add", 3},
+	}
+	for _, test := range tests {
+		if got := Distance(test.a, test.b); got != test.want {
+			t.Errorf("Distance(%q, %q) = %d; want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	commands := []string{"add", "addremove", "branch", "cat", "commit", "ci", "diff", "log", "pull", "push", "rebase", "status", "st"}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"comit", []string{"commit"}},
+		{"stauts", []string{"status"}},
+		{"pul", []string{"pull"}},
+		{"addr", []string{"add", "addremove"}},
+		{"xyzzy", nil},
+		{"", nil},
+	}
+	for _, test := range tests {
+		got := Similar(test.name, commands)
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("Similar(%q, ...) = %q; want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPhrase(t *testing.T) {
+	tests := []struct {
+		prefix string
+		names  []string
+		want   string
+	}{
+		{"", nil, ""},
+		{"", []string{"commit"}, "did you mean 'commit'?"},
+		{"-", []string{"a", "b"}, "did you mean '-a' or '-b'?"},
+		{"", []string{"a", "b", "c"}, "did you mean 'a', 'b', or 'c'?"},
+	}
+	for _, test := range tests {
+		if got := Phrase(test.prefix, test.names); got != test.want {
+			t.Errorf("Phrase(%q, %q) = %q; want %q", test.prefix, test.names, got, test.want)
+		}
+	}
+}
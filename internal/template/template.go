@@ -0,0 +1,267 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package template formats command output from Mercurial-style templates
+// like "{node|short} {desc|firstline}\n".
+//
+// A template is literal text with expressions in braces. An expression
+// names a keyword, followed by any number of filters separated by "|".
+// Backslash escapes "\n", "\t", "\\", "\{", and "\}" are recognized in
+// the literal text, so templates can be written on the command line.
+package template
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Keywords maps keyword names to their values for one execution of a
+// template. Values may be strings, []string, or time.Time.
+type Keywords map[string]interface{}
+
+// A Template is a parsed template.
+type Template struct {
+	parts []part
+}
+
+// A part is a piece of a template: either literal text or an expression.
+type part struct {
+	text    string // literal text if keyword is empty
+	keyword string
+	filters []string
+}
+
+// Parse parses a template.
+func Parse(s string) (*Template, error) {
+	t := new(Template)
+	lit := new(strings.Builder)
+	flush := func() {
+		if lit.Len() > 0 {
+			t.parts = append(t.parts, part{text: lit.String()})
+			lit.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 >= len(s) {
+				lit.WriteByte(c)
+				continue
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				lit.WriteByte('\n')
+			case 't':
+				lit.WriteByte('\t')
+			case '\\', '{', '}':
+				lit.WriteByte(s[i])
+			default:
+				lit.WriteByte('\\')
+				lit.WriteByte(s[i])
+			}
+		case '{':
+			end := strings.IndexByte(s[i+1:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("parse template: unterminated expression at offset %d", i)
+			}
+			p, err := parseExpr(s[i+1 : i+1+end])
+			if err != nil {
+				return nil, fmt.Errorf("parse template: %w", err)
+			}
+			flush()
+			t.parts = append(t.parts, p)
+			i += end + 1
+		default:
+			lit.WriteByte(c)
+		}
+	}
+	flush()
+	return t, nil
+}
+
+// parseExpr parses the text between the braces of an expression.
+func parseExpr(expr string) (part, error) {
+	fields := strings.Split(expr, "|")
+	p := part{keyword: strings.TrimSpace(fields[0])}
+	if !isName(p.keyword) {
+		return part{}, fmt.Errorf("invalid keyword %q", p.keyword)
+	}
+	for _, f := range fields[1:] {
+		f = strings.TrimSpace(f)
+		if filters[f] == nil {
+			return part{}, fmt.Errorf("unknown filter %q", f)
+		}
+		p.filters = append(p.filters, f)
+	}
+	return p, nil
+}
+
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !('a' <= c && c <= 'z' || c == '_' || i > 0 && '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Keywords returns the names of the keywords that the template uses,
+// in order of first use.
+func (t *Template) Keywords() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, p := range t.parts {
+		if p.keyword != "" && !seen[p.keyword] {
+			seen[p.keyword] = true
+			names = append(names, p.keyword)
+		}
+	}
+	return names
+}
+
+// Check returns an error if the template uses a keyword that is not
+// in known.
+func (t *Template) Check(known []string) error {
+	for _, name := range t.Keywords() {
+		found := false
+		for _, k := range known {
+			if name == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown keyword %q (must be one of %s)", name, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// Execute writes the template to w with the given keyword values.
+func (t *Template) Execute(w io.Writer, kw Keywords) error {
+	sb := new(strings.Builder)
+	for _, p := range t.parts {
+		if p.keyword == "" {
+			sb.WriteString(p.text)
+			continue
+		}
+		v, ok := kw[p.keyword]
+		if !ok {
+			return fmt.Errorf("execute template: unknown keyword %q", p.keyword)
+		}
+		for _, name := range p.filters {
+			var err error
+			v, err = filters[name](v)
+			if err != nil {
+				return fmt.Errorf("execute template: %s|%s: %w", p.keyword, name, err)
+			}
+		}
+		sb.WriteString(format(v))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// format returns the default text for a keyword or filter value.
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, " ")
+	case time.Time:
+		return v.Format(dateLayout)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// dateLayout is the format of the date filter, the same as Mercurial's.
+const dateLayout = "Mon Jan 02 15:04:05 2006 -0700"
+
+// shortHashLen is the number of hex digits that the short filter keeps.
+const shortHashLen = 12
+
+// filters maps filter names to their functions.
+var filters = map[string]func(interface{}) (interface{}, error){
+	"short": stringFilter(func(s string) string {
+		if len(s) > shortHashLen {
+			return s[:shortHashLen]
+		}
+		return s
+	}),
+	"firstline": stringFilter(func(s string) string {
+		line, _, _ := strings.Cut(s, "\n")
+		return strings.TrimRight(line, "\r")
+	}),
+	"strip": stringFilter(strings.TrimSpace),
+	"lower": stringFilter(strings.ToLower),
+	"upper": stringFilter(strings.ToUpper),
+	"person": stringFilter(func(s string) string {
+		if name, _, ok := strings.Cut(s, "<"); ok {
+			if name = strings.TrimSpace(name); name != "" {
+				return name
+			}
+		}
+		return s
+	}),
+	"email": stringFilter(func(s string) string {
+		if _, rest, ok := strings.Cut(s, "<"); ok {
+			if email, _, ok := strings.Cut(rest, ">"); ok {
+				return email
+			}
+		}
+		return s
+	}),
+	"date":        timeFilter(dateLayout),
+	"isodate":     timeFilter("2006-01-02 15:04 -0700"),
+	"shortdate":   timeFilter("2006-01-02"),
+	"rfc3339date": timeFilter(time.RFC3339),
+}
+
+// stringFilter returns a filter that applies f to a string, or to each
+// string in a list.
+func stringFilter(f func(string) string) func(interface{}) (interface{}, error) {
+	return func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case string:
+			return f(v), nil
+		case []string:
+			out := make([]string, len(v))
+			for i, s := range v {
+				out[i] = f(s)
+			}
+			return out, nil
+		default:
+			return nil, fmt.Errorf("not a string")
+		}
+	}
+}
+
+// timeFilter returns a filter that formats a date with the given layout.
+func timeFilter(layout string) func(interface{}) (interface{}, error) {
+	return func(v interface{}) (interface{}, error) {
+		t, ok := v.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("not a date")
+		}
+		return t.Format(layout), nil
+	}
+}
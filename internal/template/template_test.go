@@ -0,0 +1,120 @@
+// Copyright 2026 The gg Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecute(t *testing.T) {
+	date := time.Date(2026, time.March, 1, 9, 30, 15, 0, time.FixedZone("", -7*60*60))
+	kw := Keywords{
+		"node":    "0123456789abcdef0123456789abcdef01234567",
+		"desc":    "Add a feature\n\nWith a longer description.",
+		"author":  "Octocat <octocat@example.com>",
+		"date":    date,
+		"parents": []string{"aaaaaaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbbbbbb"},
+	}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: "", want: ""},
+		{template: "hello", want: "hello"},
+		{template: `{node|short} {desc|firstline}\n`, want: "0123456789ab Add a feature\n"},
+		{template: "{node}", want: "0123456789abcdef0123456789abcdef01234567"},
+		{template: "{ node | short }", want: "0123456789ab"},
+		{template: "{author|person}", want: "Octocat"},
+		{template: "{author|email}", want: "octocat@example.com"},
+		{template: "{date}", want: "Sun Mar 01 09:30:15 2026 -0700"},
+		{template: "{date|isodate}", want: "2026-03-01 09:30 -0700"},
+		{template: "{date|shortdate}", want: "2026-03-01"},
+		{template: "{date|rfc3339date}", want: "2026-03-01T09:30:15-07:00"},
+		{template: "{parents|short}", want: "aaaaaaaaaaaa bbbbbbbbbbbb"},
+		{template: "{desc|firstline|upper}", want: "ADD A FEATURE"},
+		{template: `\{node\}\t\\`, want: "{node}\t\\"},
+		{template: `a\qb`, want: `a\qb`},
+	}
+	for _, test := range tests {
+		tmpl, err := Parse(test.template)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.template, err)
+			continue
+		}
+		sb := new(strings.Builder)
+		if err := tmpl.Execute(sb, kw); err != nil {
+			t.Errorf("Parse(%q).Execute(...): %v", test.template, err)
+			continue
+		}
+		if got := sb.String(); got != test.want {
+			t.Errorf("Parse(%q).Execute(...) = %q; want %q", test.template, got, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"{node",
+		"{}",
+		"{node|bogus}",
+		"{Node}",
+		"{node|}",
+	}
+	for _, test := range tests {
+		if _, err := Parse(test); err == nil {
+			t.Errorf("Parse(%q) did not return an error", test)
+		}
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	kw := Keywords{
+		"node": "0123456789abcdef",
+		"date": time.Date(2026, time.March, 1, 9, 30, 15, 0, time.UTC),
+	}
+	tests := []string{
+		"{desc}",
+		"{node|date}",
+		"{date|short}",
+	}
+	for _, test := range tests {
+		tmpl, err := Parse(test)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test, err)
+			continue
+		}
+		if err := tmpl.Execute(new(strings.Builder), kw); err == nil {
+			t.Errorf("Parse(%q).Execute(...) did not return an error", test)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tmpl, err := Parse("{node} {desc} {node|short}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(tmpl.Keywords(), ","), "node,desc"; got != want {
+		t.Errorf("Keywords() = %q; want %q", got, want)
+	}
+	if err := tmpl.Check([]string{"desc", "node"}); err != nil {
+		t.Errorf("Check([desc node]) = %v; want <nil>", err)
+	}
+	if err := tmpl.Check([]string{"node"}); err == nil {
+		t.Error("Check([node]) did not return an error")
+	}
+}
@@ -17,8 +17,12 @@
 package terminal
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 )
 
 // IsTerminal reports whether w writes directly to a terminal.
@@ -30,9 +34,82 @@ func IsTerminal(w io.Writer) bool {
 	return isTerminal(f.Fd())
 }
 
+// MakeRaw puts the terminal that r reads from into raw mode, where
+// input is available a byte at a time, without echo, and without
+// generating signals. The returned function restores the terminal to its
+// previous state.
+func MakeRaw(r io.Reader) (restore func() error, err error) {
+	f, ok := r.(*os.File)
+	if !ok {
+		return nil, errors.New("make raw: not a terminal")
+	}
+	restore, err = makeRaw(f.Fd())
+	if err != nil {
+		return nil, fmt.Errorf("make raw: %w", err)
+	}
+	return restore, nil
+}
+
+// Size returns the number of columns and rows of the terminal that w
+// writes to.
+func Size(w io.Writer) (width, height int, err error) {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0, 0, errors.New("terminal size: not a terminal")
+	}
+	width, height, err = size(f.Fd())
+	if err != nil {
+		return 0, 0, fmt.Errorf("terminal size: %w", err)
+	}
+	return width, height, nil
+}
+
 // ResetTextStyle clears any text styles on the writer. The behavior of
 // calling this function on a non-terminal is undefined.
 func ResetTextStyle(w io.Writer) error {
 	_, err := w.Write([]byte("\x1b[m"))
 	return err
 }
+
+// SupportsHyperlinks reports whether the terminal described by the given
+// environment variables is known to render OSC 8 hyperlinks. Setting
+// FORCE_HYPERLINK to 1 or 0 overrides the detection.
+func SupportsHyperlinks(env []string) bool {
+	vars := make(map[string]string)
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			vars[k] = v
+		}
+	}
+	if force, ok := vars["FORCE_HYPERLINK"]; ok {
+		return force != "0"
+	}
+	if vars["TERM"] == "dumb" {
+		return false
+	}
+	for _, k := range []string{"DOMTERM", "KITTY_WINDOW_ID", "KONSOLE_VERSION", "WT_SESSION", "WEZTERM_EXECUTABLE"} {
+		if vars[k] != "" {
+			return true
+		}
+	}
+	switch vars["TERM_PROGRAM"] {
+	case "ghostty", "iTerm.app", "vscode", "WezTerm":
+		return true
+	}
+	switch vars["TERM"] {
+	case "alacritty", "foot", "xterm-ghostty", "xterm-kitty":
+		return true
+	}
+	// VTE-based terminals (like GNOME Terminal) support hyperlinks since 0.50.
+	if vte, err := strconv.Atoi(vars["VTE_VERSION"]); err == nil && vte >= 5000 {
+		return true
+	}
+	return false
+}
+
+// Hyperlink returns text marked up as a hyperlink to url using an OSC 8
+// escape sequence. Terminals that do not support hyperlinks display the
+// text as-is.
+func Hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
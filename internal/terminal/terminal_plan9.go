@@ -14,6 +14,16 @@
 
 package terminal
 
+import "errors"
+
 func isTerminal(fd uintptr) bool {
 	return false
 }
+
+func makeRaw(fd uintptr) (restore func() error, err error) {
+	return nil, errors.New("raw mode not supported on this platform")
+}
+
+func size(fd uintptr) (width, height int, err error) {
+	return 0, 0, errors.New("terminal size not supported on this platform")
+}
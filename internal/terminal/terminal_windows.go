@@ -23,3 +23,26 @@ func isTerminal(fd uintptr) bool {
 	err := windows.GetConsoleMode(windows.Handle(fd), &st)
 	return err == nil
 }
+
+func makeRaw(fd uintptr) (restore func() error, err error) {
+	var old uint32
+	if err := windows.GetConsoleMode(windows.Handle(fd), &old); err != nil {
+		return nil, err
+	}
+	raw := old &^ (windows.ENABLE_ECHO_INPUT | windows.ENABLE_PROCESSED_INPUT | windows.ENABLE_LINE_INPUT)
+	raw |= windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(windows.Handle(fd), raw); err != nil {
+		return nil, err
+	}
+	return func() error {
+		return windows.SetConsoleMode(windows.Handle(fd), old)
+	}, nil
+}
+
+func size(fd uintptr) (width, height int, err error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0, 0, err
+	}
+	return int(info.Window.Right-info.Window.Left) + 1, int(info.Window.Bottom-info.Window.Top) + 1, nil
+}
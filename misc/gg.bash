@@ -33,41 +33,83 @@ _gg_complete() {
 
   if [[ $COMP_CWORD -eq $subcmd_idx && "$curr_word" != -* ]]; then
     local commands=( \
+      absorb \
       add \
       addremove \
+      amend \
+      apply \
+      attrs \
+      auth \
       backout \
+      backups \
       branch \
+      cat \
+      changelog \
       check \
       checkout \
       ci \
       clone \
       co \
       commit \
+      config \
+      debug \
       diff \
       evolve \
+      filelog \
+      fixup \
+      fold \
       gerrithook \
+      git \
       github-login \
+      graft \
       histedit \
       history \
       id \
       identify \
+      identity \
+      import \
+      incoming \
       init \
+      journal \
+      lint-history \
       log \
       mail \
       merge \
+      note \
+      old \
+      outgoing \
+      paths \
       pr \
       pull \
       push \
       rebase \
+      recent \
+      release \
+      remote \
       remove \
+      repos \
       rm \
       requestpull \
+      resolve \
+      resolve-rev \
+      restore-from \
       revert \
+      shelve \
       st \
+      snapshot \
+      squash \
+      stage \
+      state \
+      stats-repo \
       status \
+      trust \
+      unshelve \
+      unstage \
+      untrack-changes \
       up \
       update \
       upstream \
+      view \
     )
     COMPREPLY=( $(compgen -W "${commands[*]}" -- "$curr_word") )
     return 0
@@ -81,15 +123,90 @@ _gg_complete() {
     printf '%s\n' "${refs[@]}" | grep '^refs/remotes/' | sed -e 's:^refs/remotes/::'
   }
 
+  # compgen_revs prints the names from named_revs that start with
+  # curr_word. If core.ignoreCase is set, as Git does on macOS and
+  # Windows, the match ignores case but names are printed as stored.
+  compgen_revs() {
+    if [[ "$(git config --bool core.ignoreCase 2>/dev/null)" != true ]]; then
+      compgen -W "$(named_revs)" -- "$curr_word"
+      return
+    fi
+    (
+      shopt -s nocasematch
+      named_revs | while IFS= read -r rev; do
+        if [[ "$rev" == "$curr_word"* ]]; then
+          printf '%s\n' "$rev"
+        fi
+      done
+    )
+  }
+
+  shelf_names() {
+    git stash list --format=%gs 2>/dev/null | sed -n -e 's/^[^:]*: gg shelf \([^:]*\).*$/\1/p'
+  }
+
+  repo_bookmarks() {
+    git config --name-only --get-regexp '^ggrepo\..*\.path$' 2>/dev/null | sed -e 's:^ggrepo\.\(.*\)\.path$:\1:'
+  }
+
+  named_repos() {
+    git remote
+    git config --name-only --get-regexp '^gg\.paths\.' | sed -e 's:^gg\.paths\.::'
+  }
+
   if [[ "$curr_word" == -* ]]; then
     # An option.
     case "$subcmd" in
+      add)
+        COMPREPLY=( $(compgen -W '-force-large --force-large -I -include --include -X -exclude --exclude' -- "$curr_word") )
+        return 0
+        ;;
+      addremove)
+        COMPREPLY=( $(compgen -W '-I -include --include -X -exclude --exclude -relative --relative -root-relative --root-relative' -- "$curr_word") )
+        return 0
+        ;;
+      check|st|status)
+        COMPREPLY=( $(compgen -W '-b -branch --branch -expand-renames --expand-renames -json --json -T -template --template -staged --staged -unstaged --unstaged -I -include --include -X -exclude --exclude -relative --relative -root-relative --root-relative' -- "$curr_word") )
+        return 0
+        ;;
+      absorb)
+        COMPREPLY=( $(compgen -W '-n -dry-run --dry-run -rewrite --rewrite -base --base -allow-rewrite-published --allow-rewrite-published -force-protected --force-protected -no-verify --no-verify -I -include --include -X -exclude --exclude' -- "$curr_word") )
+        return 0
+        ;;
+      amend)
+        COMPREPLY=( $(compgen -W '-e -edit --edit -m -date --date -hooks --hooks -allow-rewrite-published --allow-rewrite-published -force-protected --force-protected -no-verify --no-verify -I -include --include -X -exclude --exclude' -- "$curr_word") )
+        return 0
+        ;;
+      apply)
+        COMPREPLY=( $(compgen -W '-3way --3way -check --check -p -R -reverse --reverse' -- "$curr_word") )
+        return 0
+        ;;
+      attrs)
+        COMPREPLY=( $(compgen -W '-json --json' -- "$curr_word") )
+        return 0
+        ;;
+      github-login)
+        COMPREPLY=( $(compgen -W '-account --account -check --check' -- "$curr_word") )
+        return 0
+        ;;
       backout)
-        COMPREPLY=( $(compgen -W '-e -edit --edit -n -no-commit --no-commit -r' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-e -edit --edit -no-edit --no-edit -merge-into --merge-into -n -no-commit --no-commit -r' -- "$curr_word") )
+        return 0
+        ;;
+      backups)
+        COMPREPLY=( $(compgen -W '-f -force --force' -- "$curr_word") )
         return 0
         ;;
       branch)
-        COMPREPLY=( $(compgen -W '-d -delete --delete -f -force --force -p -pattern --pattern -r -sort --sort' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-d -delete --delete -f -force --force -force-protected --force-protected -from-template --from-template -json --json -p -pattern --pattern -r -sort --sort' -- "$curr_word") )
+        return 0
+        ;;
+      cat)
+        COMPREPLY=( $(compgen -W '-r -textconv --textconv -no-textconv --no-textconv' -- "$curr_word") )
+        return 0
+        ;;
+      changelog)
+        COMPREPLY=( $(compgen -W '-r -format --format -title --title' -- "$curr_word") )
         return 0
         ;;
       clone)
@@ -97,73 +214,169 @@ _gg_complete() {
         return 0
         ;;
       ci|commit)
-        COMPREPLY=( $(compgen -W '-amend --amend -hooks --hooks -m' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-amend --amend -hooks --hooks -m -n -dry-run --dry-run -split-by-dir --split-by-dir -I -include --include -X -exclude --exclude -allow-rewrite-published --allow-rewrite-published -force-protected --force-protected -tui --tui -branch --branch -no-verify --no-verify' -- "$curr_word") )
+        return 0
+        ;;
+      config)
+        COMPREPLY=( $(compgen -W '-global --global -unset --unset' -- "$curr_word") )
+        return 0
+        ;;
+      debug)
+        COMPREPLY=( $(compgen -W '-raw --raw -sync --sync obj refs cache' -- "$curr_word") )
         return 0
         ;;
       diff)
-        COMPREPLY=( $(compgen -W '-b -ignore-space-change --ignore-space-change -B -ignore-blank-lines --ignore-blank-lines -c -U -r -stat --stat -w -ignore-all-space --ignore-all-space -Z -ignore-space-at-eol --ignore-space-at-eol -M -C -copies-unmodified --copies-unmodified' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-b -ignore-space-change --ignore-space-change -B -ignore-blank-lines --ignore-blank-lines -c -U -r -stat --stat -expand-renames --expand-renames -staged --staged -no-textconv --no-textconv -w -ignore-all-space --ignore-all-space -Z -ignore-space-at-eol --ignore-space-at-eol -M -C -copies-unmodified --copies-unmodified -relative --relative -root-relative --root-relative -I -include --include -X -exclude --exclude' -- "$curr_word") )
         return 0
         ;;
       evolve)
-        COMPREPLY=( $(compgen -W '-d -dst --dst -l -list --list' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-autosquash --autosquash -d -dst --dst -force-protected --force-protected -l -list --list -stop --stop' -- "$curr_word") )
+        return 0
+        ;;
+      filelog)
+        COMPREPLY=( $(compgen -W '-json --json -no-textconv --no-textconv -p -r -relative --relative -root-relative --root-relative' -- "$curr_word") )
+        return 0
+        ;;
+      fold|squash)
+        COMPREPLY=( $(compgen -W '-r -m -f -force --force -force-protected --force-protected' -- "$curr_word") )
+        return 0
+        ;;
+      fixup)
+        COMPREPLY=( $(compgen -W '-to --to -squash --squash -m -hooks --hooks -no-verify --no-verify' -- "$curr_word") )
         return 0
         ;;
       gerrithook)
         COMPREPLY=( $(compgen -W '-url --url -cached --cached' -- "$curr_word") )
         return 0
         ;;
+      graft)
+        COMPREPLY=( $(compgen -W '-e -edit --edit -log --log -r -abort --abort -continue --continue -conflict-style --conflict-style' -- "$curr_word") )
+        return 0
+        ;;
       histedit)
-        COMPREPLY=( $(compgen -W '-abort --abort -continue --continue -edit-plan --edit-plan -exec --exec' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-abort --abort -continue --continue -edit-plan --edit-plan -exec --exec -allow-rewrite-published --allow-rewrite-published -force-protected --force-protected -edit-message-only --edit-message-only -r' -- "$curr_word") )
         return 0
         ;;
       id|identify)
-        COMPREPLY=( $(compgen -W '-r' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-json --json -r' -- "$curr_word") )
+        return 0
+        ;;
+      import)
+        COMPREPLY=( $(compgen -W '-b -branch --branch -patch --patch' -- "$curr_word") )
+        return 0
+        ;;
+      identity)
+        COMPREPLY=( $(compgen -W '-email --email -forge --forge -name --name -signing-key --signing-key' -- "$curr_word") )
+        return 0
+        ;;
+      incoming|outgoing)
+        COMPREPLY=( $(compgen -W '-fetch --fetch -r -upstream --upstream' -- "$curr_word") )
         return 0
         ;;
       log|history)
-        COMPREPLY=( $(compgen -W '-follow --follow -follow-first --follow-first -G -graph --graph -r -reverse --reverse -stat --stat' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-follow --follow -follow-first --follow-first -G -graph --graph -r -reverse --reverse -stat --stat -grep --grep -author --author -S -grep-diff --grep-diff -json --json -T -template --template' -- "$curr_word") )
         return 0
         ;;
       mail)
-        COMPREPLY=( $(compgen -W '-allow-dirty --allow-dirty -d -dest --dest -for --for -r -R -reviewer --reviewer -CC --CC -cc --cc -notify --notify -notify-to --notify-to -notify-cc --notify-cc -notify-bcc --notify-bcc -m -p -publish-comments --publish-comments' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-allow-dirty --allow-dirty -d -dest --dest -for --for -r -R -reviewer --reviewer -CC --CC -cc --cc -notify --notify -notify-to --notify-to -notify-cc --notify-cc -notify-bcc --notify-bcc -m -p -publish-comments --publish-comments -override-limits --override-limits' -- "$curr_word") )
         return 0
         ;;
       merge)
-        COMPREPLY=( $(compgen -W '-r -abort --abort' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-r -abort --abort -conflict-style --conflict-style -into --into -log --log' -- "$curr_word") )
+        return 0
+        ;;
+      note)
+        COMPREPLY=( $(compgen -W '-f -force --force -m -r' -- "$curr_word") )
         return 0
         ;;
       pull)
-        COMPREPLY=( $(compgen -W '-force-tags --force-tags -p -pattern --pattern -r -u' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-all --all -force-tags --force-tags -json --json -p -pattern --pattern -r -u' -- "$curr_word") )
         return 0
         ;;
       push)
-        COMPREPLY=( $(compgen -W '-f -force --force -hooks --hooks -new-branch --new-branch -r' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-exclude --exclude -f -force --force -hooks --hooks -json --json -new-branch --new-branch -override-limits --override-limits -r -set-upstream --set-upstream -signed --signed -signing-key --signing-key -skip-mirrors --skip-mirrors' -- "$curr_word") )
         return 0
         ;;
       rebase)
-        COMPREPLY=( $(compgen -W '-base --base -dst --dst -src --src -abort --abort -continue --continue' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-b -base --base -d -dest --dest -dst --dst -s -source --source -src --src -preview --preview -abort --abort -continue --continue -reset-dates --reset-dates -allow-rewrite-published --allow-rewrite-published -force-protected --force-protected -autosquash --autosquash -conflict-style --conflict-style' -- "$curr_word") )
+        return 0
+        ;;
+      journal)
+        COMPREPLY=( $(compgen -W 'export -since --since -json --json' -- "$curr_word") )
+        return 0
+        ;;
+      lint-history)
+        COMPREPLY=( $(compgen -W '-base --base -exec --exec -r' -- "$curr_word") )
+        return 0
+        ;;
+      recent)
+        COMPREPLY=( $(compgen -W '-json --json -n' -- "$curr_word") )
+        return 0
+        ;;
+      release)
+        COMPREPLY=( $(compgen -W '-n -dry-run --dry-run -s -sign --sign -notes-from-log --notes-from-log -version-file --version-file -push --push -github --github -draft --draft' -- "$curr_word") )
         return 0
         ;;
       remove|rm)
-        COMPREPLY=( $(compgen -W '-after --after -f -force --force -r' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-after --after -f -force --force -r -I -include --include -X -exclude --exclude -relative --relative -root-relative --root-relative' -- "$curr_word") )
         return 0
         ;;
       requestpull|pr)
-        COMPREPLY=( $(compgen -W '-body --body -draft --draft -e -edit --edit -n -dry-run --dry-run -maintainer-edits --maintainer-edits -R -reviewer --reviewer -title --title' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-body --body -draft --draft -e -edit --edit -n -dry-run --dry-run -maintainer-edits --maintainer-edits -paths --paths -R -reviewer --reviewer -title --title -json --json' -- "$curr_word") )
+        return 0
+        ;;
+      resolve)
+        COMPREPLY=( $(compgen -W '-forget --forget -take --take -re-merge --re-merge -conflict-style --conflict-style -manifest --manifest' -- "$curr_word") )
+        return 0
+        ;;
+      resolve-rev)
+        COMPREPLY=( $(compgen -W '-json --json' -- "$curr_word") )
+        return 0
+        ;;
+      restore-from)
+        COMPREPLY=( $(compgen -W '-n -dry-run --dry-run -relative --relative -root-relative --root-relative' -- "$curr_word") )
         return 0
         ;;
       revert)
-        COMPREPLY=( $(compgen -W '-all --all -C -no-backup --no-backup -r' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-all --all -C -no-backup --no-backup -r -I -include --include -X -exclude --exclude' -- "$curr_word") )
+        return 0
+        ;;
+      shelve)
+        COMPREPLY=( $(compgen -W '-n -name --name -m -k -keep --keep -u -unknown --unknown -l -list --list -d -delete --delete -I -include --include -X -exclude --exclude' -- "$curr_word") )
+        return 0
+        ;;
+      stage)
+        COMPREPLY=( $(compgen -W '-p -patch --patch' -- "$curr_word") )
+        return 0
+        ;;
+      stats-repo)
+        COMPREPLY=( $(compgen -W '-json --json -n' -- "$curr_word") )
+        return 0
+        ;;
+      trust)
+        COMPREPLY=( $(compgen -W '-y -yes --yes' -- "$curr_word") )
+        return 0
+        ;;
+      unshelve)
+        COMPREPLY=( $(compgen -W '-k -keep --keep' -- "$curr_word") )
+        return 0
+        ;;
+      untrack-changes)
+        COMPREPLY=( $(compgen -W '-clear --clear -l -list --list -relative --relative -root-relative --root-relative' -- "$curr_word") )
         return 0
         ;;
       update|checkout|co|up)
-        COMPREPLY=( $(compgen -W '-r -clean --clean -C' -- "$curr_word") )
+        COMPREPLY=( $(compgen -W '-r -clean --clean -C -conflict-style --conflict-style -default --default -pick --pick' -- "$curr_word") )
         return 0
         ;;
       upstream)
         COMPREPLY=( $(compgen -W '-b' -- "$curr_word") )
         return 0
         ;;
+      view)
+        COMPREPLY=( $(compgen -W '-all --all -n' -- "$curr_word") )
+        return 0
+        ;;
       *)
         COMPREPLY=()
         return 0
@@ -172,20 +385,56 @@ _gg_complete() {
   else
     # A positional argument.
     case "$subcmd" in
-      add|addremove|check|clone|evolve|init|remove|rm|st|status)
+      absorb|add|addremove|apply|attrs|check|clone|evolve|fixup|init|remove|resolve|rm|st|stage|status|unstage|untrack-changes)
         # Commands that only deal with files.
         compopt -o nospace -o filenames
         COMPREPLY=( $(compgen -f -- "$curr_word") )
         return 0
         ;;
-      backout|branch|checkout|co|histedit|id|identify|merge|rebase|up|update|upstream)
+      backout|branch|checkout|co|graft|histedit|id|identify|merge|rebase|resolve-rev|up|update|upstream|view)
         # Commands that only deal with revisions.
-        COMPREPLY=( $(compgen -W "$(named_revs)" -- "$curr_word") )
+        COMPREPLY=( $(compgen_revs) )
         return 0
         ;;
-      ci|commit)
+      remote)
+        COMPREPLY=( $(compgen -W 'set-default' -- "$curr_word") )
+        return 0
+        ;;
+      repos)
+        if [[ $COMP_CWORD -eq $(( subcmd_idx + 1 )) ]]; then
+          COMPREPLY=( $(compgen -W 'list add remove path status shell' -- "$curr_word") )
+        elif [[ "$prev_word" == remove || "$prev_word" == path ]]; then
+          COMPREPLY=( $(compgen -W "$(repo_bookmarks)" -- "$curr_word") )
+        else
+          compopt -o nospace -o filenames
+          COMPREPLY=( $(compgen -d -- "$curr_word") )
+        fi
+        return 0
+        ;;
+      auth)
+        COMPREPLY=( $(compgen -W 'list use remove encrypt decrypt lock' -- "$curr_word") )
+        return 0
+        ;;
+      shelve)
+        if [[ " ${COMP_WORDS[*]} " == *' -d '* || " ${COMP_WORDS[*]} " == *' --delete '* || " ${COMP_WORDS[*]} " == *' -delete '* ]]; then
+          COMPREPLY=( $(compgen -W "$(shelf_names)" -- "$curr_word") )
+        else
+          compopt -o nospace -o filenames
+          COMPREPLY=( $(compgen -f -- "$curr_word") )
+        fi
+        return 0
+        ;;
+      unshelve)
+        COMPREPLY=( $(compgen -W "$(shelf_names)" -- "$curr_word") )
+        return 0
+        ;;
+      snapshot)
+        COMPREPLY=( $(compgen -W '-m list restore diff' -- "$curr_word") )
+        return 0
+        ;;
+      amend|ci|commit)
         case "$prev_word" in
-          -m)
+          -m|-date|--date)
             # Don't complete for message.
             COMPREPLY=()
             return 0
@@ -197,10 +446,10 @@ _gg_complete() {
             ;;
         esac
         ;;
-      diff)
+      cat|diff)
         case "$prev_word" in
           -c|-r)
-            COMPREPLY=( $(compgen -W "$(named_revs)" -- "$curr_word") )
+            COMPREPLY=( $(compgen_revs) )
             return 0
             ;;
           *)
@@ -218,10 +467,10 @@ _gg_complete() {
         COMPREPLY=()
         return 0
         ;;
-      log|history)
+      filelog|log|history)
         case "$prev_word" in
           -r)
-            COMPREPLY=( $(compgen -W "$(named_revs)" -- "$curr_word") )
+            COMPREPLY=( $(compgen_revs) )
             return 0
             ;;
           *)
@@ -231,10 +480,22 @@ _gg_complete() {
             ;;
         esac
         ;;
+      incoming|outgoing)
+        case "$prev_word" in
+          -r)
+            COMPREPLY=( $(compgen_revs) )
+            return 0
+            ;;
+          *)
+            COMPREPLY=( $(compgen -W "$(named_repos)" -- "$curr_word") )
+            return 0
+            ;;
+        esac
+        ;;
       mail)
         case "$prev_word" in
           -r|-d|-dest|--dest|-for|--for)
-            COMPREPLY=( $(compgen -W "$(named_revs)" -- "$curr_word") )
+            COMPREPLY=( $(compgen_revs) )
             return 0
             ;;
           -m|-R|-reviewer|--reviewer|-CC|--CC|-cc|--cc|-notify-to|--notify-to|-notify-cc|--notify-cc|-notify-bcc|--notify-bcc)
@@ -247,35 +508,58 @@ _gg_complete() {
             return 0
             ;;
           *)
-            COMPREPLY=( $(compgen -W "$(git remote)" -- "$curr_word") )
+            COMPREPLY=( $(compgen -W "$(named_repos)" -- "$curr_word") )
             return 0
             ;;
         esac
         ;;
-      pull)
-        COMPREPLY=( $(compgen -W "$(git remote)" -- "$curr_word") )
+      backups)
+        if [[ "$prev_word" == backups ]]; then
+          COMPREPLY=( $(compgen -W 'list restore' -- "$curr_word") )
+        elif [[ "$prev_word" == restore ]]; then
+          COMPREPLY=( $(compgen -W "$(ls "$(git rev-parse --git-dir 2>/dev/null)/gg/backups" 2>/dev/null)" -- "$curr_word") )
+        else
+          compopt -o nospace -o filenames
+          COMPREPLY=( $(compgen -f -- "$curr_word") )
+        fi
+        return 0
+        ;;
+      note)
+        COMPREPLY=( $(compgen -W 'show add remove' -- "$curr_word") )
+        return 0
+        ;;
+      old)
+        if [[ "$prev_word" == old ]]; then
+          COMPREPLY=( $(compgen -W 'list restore prune' -- "$curr_word") )
+        else
+          COMPREPLY=( $(compgen -W "$(git for-each-ref --format='%(refname)' refs/gg-old/ | sed -e 's:^refs/gg-old/::')" -- "$curr_word") )
+        fi
+        return 0
+        ;;
+      paths|pull)
+        COMPREPLY=( $(compgen -W "$(named_repos)" -- "$curr_word") )
         return 0
         ;;
       push)
         case "$prev_word" in
-          -r)
-            COMPREPLY=( $(compgen -W "$(named_revs)" -- "$curr_word") )
+          -r|-exclude|--exclude)
+            COMPREPLY=( $(compgen_revs) )
             return 0
             ;;
           *)
-            COMPREPLY=( $(compgen -W "$(git remote)" -- "$curr_word") )
+            COMPREPLY=( $(compgen -W "$(named_repos)" -- "$curr_word") )
             return 0
             ;;
         esac
         ;;
       requestpull|pr)
-        COMPREPLY=( $(compgen -W "$(named_revs)" -- "$curr_word") )
+        COMPREPLY=( $(compgen -W "comments $(named_revs)" -- "$curr_word") )
         return 0
         ;;
       revert)
         case "$prev_word" in
           -r)
-            COMPREPLY=( $(compgen -W "$(named_revs)" -- "$curr_word") )
+            COMPREPLY=( $(compgen_revs) )
             return 0
             ;;
           *)